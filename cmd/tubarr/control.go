@@ -2,12 +2,46 @@ package main
 
 import (
 	"context"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"tubarr/internal/cfg"
 	"tubarr/internal/data/repo"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/process"
 	"tubarr/internal/utils/logging"
 )
 
+// handleSignals cancels the program context on SIGINT or SIGTERM.
+//
+// With --shutdown-grace set, work in progress is first given that long to finish while no new work starts.
+// A second signal cancels at once.
+func handleSignals(cancel context.CancelFunc) {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGSEGV)
+	<-sigs
+
+	grace := cfg.GetDuration(keys.ShutdownGrace)
+	if grace <= 0 {
+		cancel()
+		return
+	}
+
+	process.BeginDrain()
+	logging.I("Shutting down, letting work in progress finish for up to %s. Signal again to stop now", grace)
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-sigs:
+		logging.I("Stopping work in progress, interrupted downloads resume on the next run")
+	case <-timer.C:
+		logging.I("Shutdown grace period over, stopping work in progress, interrupted downloads resume on the next run")
+	}
+	cancel()
+}
+
 // startHeartbeat starts the program heartbeat.
 //
 // Mainly useful for preventing DB lockouts.
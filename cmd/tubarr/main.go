@@ -4,12 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"tubarr/internal/cfg"
 	"tubarr/internal/domain/keys"
+	"tubarr/internal/events"
 	"tubarr/internal/process"
 	"tubarr/internal/utils/logging"
 )
@@ -24,10 +23,15 @@ func main() {
 	}
 	logging.I("Tubarr (PID: %d) started at: %v", progControl.ProcessID, startTime.Format("2006-01-02 15:04:05.00 MST"))
 
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGSEGV)
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	go handleSignals(cancel)
 	defer cleanup(progControl)
 
+	// Event hooks, finished before exiting
+	process.InitHooks(store, ctx)
+	defer events.Wait()
+
 	// Start heatbeat
 	go startHeartbeat(progControl, ctx)
 
@@ -43,6 +47,13 @@ func main() {
 		return
 	}
 
+	// Resume downloads interrupted by a previous run
+	if cfg.GetBool(keys.CheckChannels) || cfg.GetBool(keys.RunScheduler) {
+		if err := process.ResumeDownloads(store, ctx); err != nil {
+			logging.E(0, "Encountered errors while resuming interrupted downloads: %v\n", err)
+		}
+	}
+
 	// Check channels
 	if cfg.GetBool(keys.CheckChannels) {
 		if err := process.CheckChannels(store, ctx); err != nil {
@@ -51,9 +62,41 @@ func main() {
 		}
 	}
 
+	// Run scheduler
+	if cfg.GetBool(keys.RunScheduler) {
+		if err := progControl.SetScheduler(process.HTTPAddr()); err != nil {
+			logging.E(0, "Failed to record scheduler state, other instances can't send it commands: %v", err)
+		}
+		if err := process.RunScheduler(store, ctx); err != nil {
+			logging.E(0, "Scheduler exited with error: %v\n", err)
+			return
+		}
+	}
+
+	// Serve the HTTP API alone
+	if cfg.GetBool(keys.RunServer) {
+		if err := progControl.SetScheduler(process.HTTPAddr()); err != nil {
+			logging.E(0, "Failed to record HTTP address, other instances can't send it commands: %v", err)
+		}
+		if err := process.Serve(store, ctx); err != nil {
+			logging.E(0, "HTTP server exited with error: %v\n", err)
+			return
+		}
+	}
+
+	// Run self-test
+	if cfg.GetBool(keys.RunSelfTest) {
+		if err := process.SelfTest(store, ctx); err != nil {
+			logging.E(0, "Self-test failed: %v\n", err)
+			return
+		}
+	}
+
 	endTime := time.Now()
 	logging.I("Tubarr finished at: %v\n\nTime elapsed: %.2f seconds",
 		endTime.Format("2006-01-02 15:04:05.00 MST"),
 		endTime.Sub(startTime).Seconds())
-	fmt.Println()
+	if !machineOutputArg(os.Args[1:]) {
+		fmt.Println()
+	}
 }
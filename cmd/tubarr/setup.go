@@ -4,14 +4,20 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
+	"tubarr/internal/cfg"
+	cfgflags "tubarr/internal/cfg/flags"
 	"tubarr/internal/data/database"
 	"tubarr/internal/data/repo"
+	"tubarr/internal/domain/keys"
 	"tubarr/internal/domain/setup"
 	"tubarr/internal/utils/benchmark"
 	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
 )
 
 // initializeApplication sets up the application for the current run.
@@ -25,17 +31,26 @@ func initializeApplication(startTime time.Time) (store *repo.Store, progControl
 	}
 	benchmark.InjectMainWorkDir(mainGoPath)
 
+	// Keep stdout for the output of scripts and shell completions
+	quiet := machineOutputArg(os.Args[1:])
+	if quiet {
+		logging.SetConsole(os.Stderr)
+	}
+
 	// Setup files/dirs
 	if err := setup.InitCfgFilesDirs(startTime.Format("2006-01-02 15:04:05.00 MST")); err != nil {
 		fmt.Printf("Tubarr exiting: %v\n", err)
 		os.Exit(0)
 	}
 
-	fmt.Printf("\nMain Tubarr file/dir locations:\n\nDatabase: %s\nLog file: %s\n\n",
-		setup.DBFilePath, setup.LogFilePath)
+	if !quiet {
+		fmt.Printf("\nMain Tubarr file/dir locations:\n\nDatabase: %s\nLog file: %s\n\n",
+			setup.DBFilePath, setup.LogFilePath)
+	}
 
-	// Database & stores
-	db, err := database.InitDB()
+	// Database & stores, completions shouldn't wait on or block a running instance
+	readOnly := readOnlyArg(os.Args[1:]) || completionArg(os.Args[1:])
+	db, err := database.InitDB(readOnly, !dbCommandArg(os.Args[1:]))
 	if err != nil {
 		fmt.Printf("Tubarr exiting: %v\n", err)
 		os.Exit(0)
@@ -44,13 +59,39 @@ func initializeApplication(startTime time.Time) (store *repo.Store, progControl
 
 	// Start controller
 	progControl = repo.NewProgController(db.DB)
+	progControl.ReadOnly = readOnly
 	if progControl.ProcessID, err = progControl.StartTubarr(); err != nil {
 		if strings.HasPrefix(err.Error(), "failure:") {
 			logging.E(0, "DB %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("Tubarr exiting: %v\n", err)
-		os.Exit(0)
+		pid, addr, scheduler, ok := progControl.RunningInstance()
+		if !ok || (addr == "" && !scheduler) {
+			fmt.Printf("Tubarr exiting: %v\n", err)
+			os.Exit(0)
+		}
+
+		// Run alongside the running instance, leaving commands which modify the database to it
+		progControl.ReadOnly, progControl.ProcessID = true, os.Getpid()
+		cfg.Set(keys.ReadOnly, true)
+		if addr != "" {
+			if err := db.DB.Close(); err != nil {
+				logging.E(0, "Failed to close database: %v", err)
+			}
+			if db, err = database.InitDB(true, false); err != nil {
+				fmt.Printf("Tubarr exiting: %v\n", err)
+				os.Exit(0)
+			}
+			store = repo.InitStores(db.DB)
+			progControl.DB = db.DB
+			cfg.Set(keys.RemoteAddr, addr)
+			logging.I("Tubarr is already running (PID: %d), sending commands to its HTTP API at %s", pid, addr)
+		} else {
+			// The database stays writable to queue commands, the rest are refused by read-only mode
+			cfg.Set(keys.QueueCommands, true)
+			logging.I("Tubarr is already running (PID: %d), queueing commands for its scheduler", pid)
+		}
+		err = nil
 	}
 
 	// Setup logging
@@ -60,3 +101,70 @@ func initializeApplication(startTime time.Time) (store *repo.Store, progControl
 
 	return store, progControl, err
 }
+
+// readOnlyArg reports whether read-only mode was requested, by flag or environment variable.
+//
+// The database is opened before Cobra parses flags, so the flag is checked here directly. The flag
+// takes precedence over the environment variable, as it does once Viper reads them.
+func readOnlyArg(args []string) bool {
+	for _, a := range args {
+		if a == "--" {
+			break
+		}
+		if a == "--"+keys.ReadOnly {
+			return true
+		}
+		if v, ok := strings.CutPrefix(a, "--"+keys.ReadOnly+"="); ok {
+			readOnly, err := strconv.ParseBool(v)
+			return err == nil && readOnly
+		}
+	}
+	readOnly, err := strconv.ParseBool(os.Getenv(cfgflags.ReadOnlyEnv))
+	return err == nil && readOnly
+}
+
+// dbCommandArg reports whether a database command was requested, which manages migrations itself.
+func dbCommandArg(args []string) bool {
+	for _, a := range args {
+		if a == "--" {
+			return false
+		}
+		if !strings.HasPrefix(a, "-") {
+			return a == "db"
+		}
+	}
+	return false
+}
+
+// completionArg reports whether a shell completion script or completion candidates were requested.
+func completionArg(args []string) bool {
+	for _, a := range args {
+		if a == "--" {
+			return false
+		}
+		if !strings.HasPrefix(a, "-") {
+			return a == "completion" || a == cobra.ShellCompRequestCmd || a == cobra.ShellCompNoDescRequestCmd
+		}
+	}
+	return false
+}
+
+// machineOutputArg reports whether stdout is read by a program, for shell completions or JSON and YAML output.
+func machineOutputArg(args []string) bool {
+	if completionArg(args) {
+		return true
+	}
+	for i, a := range args {
+		if a == "--" {
+			return false
+		}
+		format, ok := strings.CutPrefix(a, "--output=")
+		if !ok && a == "--output" && i+1 < len(args) {
+			format, ok = args[i+1], true
+		}
+		if ok && (strings.EqualFold(format, "json") || strings.EqualFold(format, "yaml")) {
+			return true
+		}
+	}
+	return false
+}
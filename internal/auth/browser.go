@@ -0,0 +1,295 @@
+// Package auth handles browser logins, saved login sessions, and looking up credentials kept outside the database.
+package auth
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	devtoolsOrigin = "http://127.0.0.1"
+	loginTimeout   = 90 * time.Second
+	startTimeout   = 20 * time.Second
+	pollInterval   = 500 * time.Millisecond
+	landingGrace   = 2 * time.Second
+)
+
+// chromeNames are the executables searched for in PATH when no browser is set.
+var chromeNames = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "chrome", "headless_shell"}
+
+var devtoolsRx = regexp.MustCompile(`DevTools listening on (ws://\S+)`)
+
+// Session is the result of a browser login.
+type Session struct {
+	Cookies   []*http.Cookie
+	URL       string // Page the login ended on
+	HTML      string
+	FormShown bool // Whether the login form was still on the page
+}
+
+// fillLoginScript fills the page's password field and the username field in its form, then submits it.
+//
+// Returns an error message, or an empty string once submitted.
+const fillLoginScript = `((user, pass) => {
+	const pw = document.querySelector('input[type="password"]');
+	if (!pw) return "no password field on login page";
+	const form = pw.form || document;
+	const name = form.querySelector('input[type="email"], input[autocomplete="username"], input[name*="user" i], input[name*="email" i], input[name*="login" i], input[type="text"]');
+	const set = (el, v) => {
+		Object.getOwnPropertyDescriptor(HTMLInputElement.prototype, "value").set.call(el, v);
+		el.dispatchEvent(new Event("input", {bubbles: true}));
+		el.dispatchEvent(new Event("change", {bubbles: true}));
+	};
+	if (name) set(name, user);
+	set(pw, pass);
+	const btn = form.querySelector('button[type="submit"], input[type="submit"], button:not([type])');
+	if (btn) btn.click();
+	else if (pw.form) pw.form.requestSubmit();
+	else return "no login form on login page";
+	return "";
+})(%s, %s)`
+
+const (
+	pageLoadedScript = `location.href !== "about:blank" && document.readyState === "complete"`
+	loginDoneScript  = `document.readyState === "complete" && (location.href !== %s || !document.querySelector('input[type="password"]'))`
+	formShownScript  = `!!document.querySelector('input[type="password"]')`
+	pageURLScript    = `location.href`
+	pageHTMLScript   = `document.documentElement.outerHTML`
+)
+
+// BrowserLogin logs in to the channel's login page in a headless Chrome, returning the session it ends with.
+//
+// The page's password field and the username field beside it are filled and the form submitted, for
+// sites where posting the login form directly fails. chromeBin is searched for in PATH if empty.
+// Requests go through proxyURL if it is set.
+func BrowserLogin(c *models.Channel, chromeBin, proxyURL string, ctx context.Context) (*Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+	defer cancel()
+
+	bin, err := chromePath(chromeBin)
+	if err != nil {
+		return nil, err
+	}
+
+	dataDir, err := os.MkdirTemp("", "tubarr-chrome-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dataDir)
+
+	args := []string{
+		"--headless=new",
+		"--disable-gpu",
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--remote-debugging-port=0",
+		"--remote-allow-origins=" + devtoolsOrigin,
+		"--user-data-dir=" + dataDir,
+	}
+	if proxyURL != "" {
+		args = append(args, "--proxy-server="+proxyURL)
+	}
+	args = append(args, "about:blank")
+
+	cmd := exec.CommandContext(ctx, bin, args...)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start browser %q: %w", bin, err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	browserWS, err := devtoolsURL(stderr)
+	if err != nil {
+		return nil, err
+	}
+	pageWS, err := pageTarget(browserWS, ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialCDP(pageWS)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	logging.I("Logging in to %q with username %q in a headless browser", c.LoginURL, c.Username)
+	if err := conn.call(ctx, "Page.navigate", map[string]any{"url": c.LoginURL}, nil); err != nil {
+		return nil, err
+	}
+	if _, err := conn.waitFor(ctx, pageLoadedScript, loginTimeout); err != nil {
+		return nil, err
+	}
+
+	user, _ := json.Marshal(c.Username)
+	pass, _ := json.Marshal(c.Password)
+	var msg string
+	if err := conn.evaluate(ctx, fmt.Sprintf(fillLoginScript, user, pass), &msg); err != nil {
+		return nil, err
+	}
+	if msg != "" {
+		return nil, errors.New(msg)
+	}
+
+	loginURL, _ := json.Marshal(c.LoginURL)
+	done, err := conn.waitFor(ctx, fmt.Sprintf(loginDoneScript, loginURL), loginTimeout/2)
+	if err != nil {
+		return nil, err
+	}
+	if !done {
+		logging.D(1, "Login page %q did not move on after submitting, checking the session anyway", c.LoginURL)
+	}
+
+	// Give scripts on the landing page a moment to set their cookies
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(landingGrace):
+	}
+
+	sess := &Session{}
+	if err := conn.evaluate(ctx, pageURLScript, &sess.URL); err != nil {
+		return nil, err
+	}
+	if err := conn.evaluate(ctx, pageHTMLScript, &sess.HTML); err != nil {
+		return nil, err
+	}
+	if err := conn.evaluate(ctx, formShownScript, &sess.FormShown); err != nil {
+		return nil, err
+	}
+	if sess.Cookies, err = pageCookies(conn, ctx); err != nil {
+		return nil, err
+	}
+
+	logging.D(1, "Browser login to %q ended on %q with %d cookies", c.LoginURL, sess.URL, len(sess.Cookies))
+	return sess, nil
+}
+
+// chromePath returns the Chrome or Chromium executable to log in with.
+func chromePath(bin string) (string, error) {
+	if bin != "" {
+		return bin, nil
+	}
+	for _, name := range chromeNames {
+		if p, err := exec.LookPath(name); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("no Chrome or Chromium found for browser login, install one or set --%s", keys.ChromePath)
+}
+
+// devtoolsURL reads the browser's DevTools websocket URL from its startup output.
+func devtoolsURL(stderr io.Reader) (string, error) {
+	found := make(chan string, 1)
+	go func() {
+		// Keeps reading after the URL so the browser never blocks writing to stderr
+		var sent bool
+		sc := bufio.NewScanner(stderr)
+		for sc.Scan() {
+			if m := devtoolsRx.FindStringSubmatch(sc.Text()); m != nil && !sent {
+				found <- m[1]
+				sent = true
+			}
+		}
+		if !sent {
+			close(found)
+		}
+	}()
+
+	select {
+	case u, ok := <-found:
+		if !ok {
+			return "", errors.New("browser exited before starting its DevTools endpoint")
+		}
+		return u, nil
+	case <-time.After(startTimeout):
+		return "", errors.New("browser did not start its DevTools endpoint in time")
+	}
+}
+
+// pageTarget returns the DevTools websocket URL of the browser's open page.
+func pageTarget(browserWS string, ctx context.Context) (string, error) {
+	u, err := url.Parse(browserWS)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+u.Host+"/json/list", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to list browser pages: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var targets []struct {
+		Type  string `json:"type"`
+		WSURL string `json:"webSocketDebuggerUrl"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&targets); err != nil {
+		return "", fmt.Errorf("failed to decode browser pages: %w", err)
+	}
+	for _, t := range targets {
+		if t.Type == "page" && strings.HasPrefix(t.WSURL, "ws://") {
+			return t.WSURL, nil
+		}
+	}
+	return "", errors.New("browser has no open page")
+}
+
+// pageCookies returns every cookie the browser holds.
+func pageCookies(conn *cdpConn, ctx context.Context) ([]*http.Cookie, error) {
+	var res struct {
+		Cookies []struct {
+			Name     string  `json:"name"`
+			Value    string  `json:"value"`
+			Domain   string  `json:"domain"`
+			Path     string  `json:"path"`
+			Expires  float64 `json:"expires"`
+			Secure   bool    `json:"secure"`
+			HTTPOnly bool    `json:"httpOnly"`
+		} `json:"cookies"`
+	}
+	if err := conn.call(ctx, "Network.getAllCookies", nil, &res); err != nil {
+		return nil, err
+	}
+
+	cookies := make([]*http.Cookie, 0, len(res.Cookies))
+	for _, ck := range res.Cookies {
+		hc := &http.Cookie{
+			Name:     ck.Name,
+			Value:    ck.Value,
+			Domain:   ck.Domain,
+			Path:     ck.Path,
+			Secure:   ck.Secure,
+			HttpOnly: ck.HTTPOnly,
+		}
+		if ck.Expires > 0 { // Session cookies have -1
+			hc.Expires = time.Unix(int64(ck.Expires), 0)
+		}
+		cookies = append(cookies, hc)
+	}
+	return cookies, nil
+}
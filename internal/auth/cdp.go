@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// cdpConn is a connection to a Chrome DevTools Protocol target.
+//
+// Commands are sent one at a time, and events received while waiting for a reply are dropped.
+type cdpConn struct {
+	ws     *websocket.Conn
+	nextID int64
+}
+
+type cdpMessage struct {
+	ID     int64           `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params any             `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// dialCDP connects to a DevTools websocket URL.
+func dialCDP(wsURL string) (*cdpConn, error) {
+	ws, err := websocket.Dial(wsURL, "", devtoolsOrigin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	return &cdpConn{ws: ws}, nil
+}
+
+// call sends a command and decodes its result into result, if result is not nil.
+func (c *cdpConn) call(ctx context.Context, method string, params, result any) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := c.ws.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	c.nextID++
+	id := c.nextID
+	if err := websocket.JSON.Send(c.ws, cdpMessage{ID: id, Method: method, Params: params}); err != nil {
+		return fmt.Errorf("failed to send %s: %w", method, err)
+	}
+
+	for {
+		var msg cdpMessage
+		if err := websocket.JSON.Receive(c.ws, &msg); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to read reply to %s: %w", method, err)
+		}
+		if msg.ID != id {
+			continue
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("%s failed: %s (code %d)", method, msg.Error.Message, msg.Error.Code)
+		}
+		if result == nil || len(msg.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(msg.Result, result)
+	}
+}
+
+// evaluate runs a script in the page and decodes its return value into out, if out is not nil.
+func (c *cdpConn) evaluate(ctx context.Context, script string, out any) error {
+	var res struct {
+		Result struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+	params := map[string]any{"expression": script, "returnByValue": true, "awaitPromise": true}
+	if err := c.call(ctx, "Runtime.evaluate", params, &res); err != nil {
+		return err
+	}
+	if res.ExceptionDetails != nil {
+		return fmt.Errorf("script failed in page: %s", res.ExceptionDetails.Text)
+	}
+	if out == nil || len(res.Result.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Result.Value, out)
+}
+
+// waitFor evaluates a boolean script until it returns true, the timeout passes, or the context is cancelled.
+//
+// Returns false if the timeout passes first.
+func (c *cdpConn) waitFor(ctx context.Context, script string, timeout time.Duration) (bool, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		// Scripts fail while the page navigates, so errors are retried
+		var done bool
+		if err := c.evaluate(ctx, script, &done); err != nil && ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if done {
+			return true, nil
+		}
+		if time.Now().After(deadline) {
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (c *cdpConn) close() {
+	_ = c.ws.Close()
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"tubarr/internal/utils/shellwords"
+)
+
+const secretCmdTimeout = 30 * time.Second
+
+// SecretProvider looks up the secrets referred to with its scheme, such as "env:VARNAME".
+type SecretProvider interface {
+	// Resolve returns the secret for the reference, with the scheme and colon removed.
+	Resolve(ref string) (string, error)
+}
+
+var (
+	secretsMu       sync.RWMutex
+	secretProviders = map[string]SecretProvider{
+		"env":  envSecrets{},
+		"file": fileSecrets{},
+		"cmd":  cmdSecrets{},
+	}
+
+	// secretCommands are the commands "cmd:name" references may run, by name.
+	secretCommands map[string]string
+)
+
+// SetSecretCommands sets the commands "cmd:name" references may run, by lowercase name.
+//
+// These only come from flags or the config file. Stored credentials name a command rather than hold one,
+// so a value written to the database by an import or the HTTP API can't run anything not set up locally.
+func SetSecretCommands(commands map[string]string) {
+	secretsMu.Lock()
+	secretCommands = commands
+	secretsMu.Unlock()
+}
+
+// IsCommandRef reports whether a value is a "cmd:name" secret reference.
+func IsCommandRef(v string) bool {
+	return strings.HasPrefix(v, "cmd:")
+}
+
+// RegisterSecretProvider lets credentials refer to secrets with "scheme:ref", such as a secret manager's.
+func RegisterSecretProvider(scheme string, p SecretProvider) {
+	secretsMu.Lock()
+	secretProviders[scheme] = p
+	secretsMu.Unlock()
+}
+
+// ResolveSecret returns the secret a stored credential refers to, or the credential itself if it isn't a reference.
+//
+// References are "scheme:ref" with a registered scheme, for example "env:SITE_PASSWORD", "file:/run/secrets/site"
+// or "cmd:site" to run the command named "site" in the secret commands.
+func ResolveSecret(stored string) (string, error) {
+	scheme, ref, ok := strings.Cut(stored, ":")
+	if !ok {
+		return stored, nil
+	}
+
+	secretsMu.RLock()
+	p, ok := secretProviders[scheme]
+	secretsMu.RUnlock()
+	if !ok {
+		return stored, nil
+	}
+
+	secret, err := p.Resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s secret: %w", scheme, err)
+	}
+	if secret == "" {
+		return "", fmt.Errorf("%s secret %q is empty", scheme, ref)
+	}
+	return secret, nil
+}
+
+// envSecrets reads secrets from environment variables.
+type envSecrets struct{}
+
+func (envSecrets) Resolve(name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+	return v, nil
+}
+
+// fileSecrets reads secrets from files, such as Docker or Kubernetes secrets.
+type fileSecrets struct{}
+
+func (fileSecrets) Resolve(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// cmdSecrets runs a named secret command, such as a password manager's CLI, and reads the secret from its output.
+//
+// The command is split into arguments like a shell would, honoring quotes, and run without one.
+type cmdSecrets struct{}
+
+func (cmdSecrets) Resolve(name string) (string, error) {
+	secretsMu.RLock()
+	command, ok := secretCommands[strings.ToLower(name)]
+	secretsMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("no secret command named %q, add it with '--secret-command %s=<command>' or under 'secret-commands' in the config file", name, name)
+	}
+
+	args, err := shellwords.Split(command)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret command %q: %w", name, err)
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("secret command %q is empty", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretCmdTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%q failed: %w: %s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("%q failed: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
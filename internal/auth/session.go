@@ -0,0 +1,335 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"tubarr/internal/domain/setup"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	sessionDir     = "sessions"
+	sessionExt     = ".enc"
+	sessionKeyFile = "session.key"
+	sessionKeySize = 32
+	rotateExt      = ".new"
+
+	// sessionMaxAge is how long a session holding only browser-session cookies is reused.
+	sessionMaxAge = 12 * time.Hour
+)
+
+// storedCookie is a session cookie as saved to disk.
+type storedCookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Expires  time.Time `json:"expires,omitempty"`
+	Secure   bool      `json:"secure,omitempty"`
+	HTTPOnly bool      `json:"http_only,omitempty"`
+}
+
+// SaveSession encrypts a channel's login cookies and saves them for later crawls.
+//
+// The key is generated on first use and kept beside the database, readable only by the user.
+func SaveSession(c *models.Channel, cookies []*http.Cookie) error {
+	gcm, err := sessionCipher()
+	if err != nil {
+		return err
+	}
+
+	stored := make([]storedCookie, 0, len(cookies))
+	for _, ck := range cookies {
+		stored = append(stored, storedCookie{
+			Name:     ck.Name,
+			Value:    ck.Value,
+			Domain:   ck.Domain,
+			Path:     ck.Path,
+			Expires:  ck.Expires,
+			Secure:   ck.Secure,
+			HTTPOnly: ck.HttpOnly,
+		})
+	}
+	plain, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	sealed := gcm.Seal(nonce, nonce, plain, nil)
+
+	path := sessionPath(c)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, sealed, 0o600); err != nil {
+		return fmt.Errorf("failed to save session for channel %q: %w", c.Name, err)
+	}
+	logging.D(1, "Saved %d session cookies for channel %q", len(stored), c.Name)
+	return nil
+}
+
+// LoadSession returns a channel's saved login cookies, leaving out expired ones.
+//
+// Returns nil if there is no saved session or it has expired. Sessions without any cookies carrying an
+// expiry are treated as expired after sessionMaxAge.
+func LoadSession(c *models.Channel) ([]*http.Cookie, error) {
+	path := sessionPath(c)
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := sessionCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("saved session for channel %q is corrupt", c.Name)
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt saved session for channel %q: %w", c.Name, err)
+	}
+
+	var stored []storedCookie
+	if err := json.Unmarshal(plain, &stored); err != nil {
+		return nil, err
+	}
+
+	var (
+		now      = time.Now()
+		cookies  []*http.Cookie
+		expiring bool
+	)
+	for _, sc := range stored {
+		if !sc.Expires.IsZero() {
+			if sc.Expires.Before(now) {
+				continue
+			}
+			expiring = true
+		}
+		cookies = append(cookies, &http.Cookie{
+			Name:     sc.Name,
+			Value:    sc.Value,
+			Domain:   sc.Domain,
+			Path:     sc.Path,
+			Expires:  sc.Expires,
+			Secure:   sc.Secure,
+			HttpOnly: sc.HTTPOnly,
+		})
+	}
+	if len(cookies) == 0 || (!expiring && now.Sub(info.ModTime()) > sessionMaxAge) {
+		logging.D(1, "Saved session for channel %q has expired", c.Name)
+		return nil, nil
+	}
+	return cookies, nil
+}
+
+// sessionPath returns where a channel's session is saved.
+func sessionPath(c *models.Channel) string {
+	return filepath.Join(setup.CfgDir, sessionDir, strconv.FormatInt(c.ID, 10)+sessionExt)
+}
+
+// sessionCipher returns the cipher sessions are encrypted with, creating its key if there is none.
+//
+// A key of the wrong size is reported rather than replaced, since replacing it would lose every saved session.
+func sessionCipher() (cipher.AEAD, error) {
+	path := sessionKeyPath()
+
+	key, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if key, err = createSessionKey(path); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to read session key: %w", err)
+	}
+	if len(key) != sessionKeySize {
+		return nil, fmt.Errorf("session key %q is corrupt (%d bytes, expected %d), restore it from a backup or delete it and log in again",
+			path, len(key), sessionKeySize)
+	}
+	return keyCipher(key)
+}
+
+// createSessionKey saves a new session key, or returns the existing one if another process created it first.
+//
+// The key is written in full to a temporary file and then linked into place, which fails if the key exists,
+// so a concurrent reader never sees a partial key and two processes never end up with different keys.
+func createSessionKey(path string) ([]byte, error) {
+	key, err := newSessionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to save session key: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to save session key: %w", err)
+	}
+	if _, err := tmp.Write(key); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to save session key: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to save session key: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to save session key: %w", err)
+	}
+
+	if err := os.Link(tmp.Name(), path); err != nil {
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to save session key: %w", err)
+		}
+		// Another process created the key first
+		if key, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read session key: %w", err)
+		}
+	}
+	return key, nil
+}
+
+// RotateSessionKey replaces the session key with a new one and re-encrypts every saved session with it.
+//
+// All sessions are re-encrypted before anything is replaced, so a failure leaves the old key and sessions
+// in place. The old key is backed up beside the new one, and its path returned.
+func RotateSessionKey() (rotated int, backup string, err error) {
+	path := sessionKeyPath()
+	oldKey, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, "", errors.New("no session key to rotate, one is created on the first browser login")
+		}
+		return 0, "", fmt.Errorf("failed to read session key: %w", err)
+	}
+	oldGCM, err := keyCipher(oldKey)
+	if err != nil {
+		return 0, "", err
+	}
+
+	newKey, err := newSessionKey()
+	if err != nil {
+		return 0, "", err
+	}
+	newGCM, err := keyCipher(newKey)
+	if err != nil {
+		return 0, "", err
+	}
+
+	dir := filepath.Join(setup.CfgDir, sessionDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, "", err
+	}
+
+	// Stage the re-encrypted sessions, so nothing changes unless all of them succeed
+	var staged []string
+	defer func() {
+		if err != nil {
+			for _, p := range staged {
+				_ = os.Remove(p + rotateExt)
+			}
+		}
+	}()
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != sessionExt {
+			continue
+		}
+		p := filepath.Join(dir, e.Name())
+		sealed, err := os.ReadFile(p)
+		if err != nil {
+			return 0, "", err
+		}
+		if len(sealed) < oldGCM.NonceSize() {
+			return 0, "", fmt.Errorf("saved session %q is corrupt", p)
+		}
+		plain, err := oldGCM.Open(nil, sealed[:oldGCM.NonceSize()], sealed[oldGCM.NonceSize():], nil)
+		if err != nil {
+			return 0, "", fmt.Errorf("failed to decrypt saved session %q: %w", p, err)
+		}
+
+		nonce := make([]byte, newGCM.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return 0, "", err
+		}
+		if err := os.WriteFile(p+rotateExt, newGCM.Seal(nonce, nonce, plain, nil), 0o600); err != nil {
+			return 0, "", err
+		}
+		staged = append(staged, p)
+	}
+
+	backup = fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
+	if err := os.WriteFile(backup, oldKey, 0o600); err != nil {
+		return 0, "", fmt.Errorf("failed to back up session key: %w", err)
+	}
+	if err := os.WriteFile(path+rotateExt, newKey, 0o600); err != nil {
+		return 0, "", fmt.Errorf("failed to write new session key: %w", err)
+	}
+
+	for _, p := range staged {
+		if err := os.Rename(p+rotateExt, p); err != nil {
+			return 0, "", fmt.Errorf("failed to replace saved session %q, restore the old key from %q: %w", p, backup, err)
+		}
+	}
+	if err := os.Rename(path+rotateExt, path); err != nil {
+		return 0, "", fmt.Errorf("failed to replace session key, sessions are encrypted with %q: %w", path+rotateExt, err)
+	}
+
+	return len(staged), backup, nil
+}
+
+// sessionKeyPath returns where the session key is kept.
+func sessionKeyPath() string {
+	return filepath.Join(setup.CfgDir, sessionKeyFile)
+}
+
+// newSessionKey returns a random session key.
+func newSessionKey() ([]byte, error) {
+	key := make([]byte, sessionKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// keyCipher returns the AES-GCM cipher for a session key.
+func keyCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != sessionKeySize {
+		return nil, fmt.Errorf("session key is %d bytes, expected %d", len(key), sessionKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,46 @@
+// Package cfgauth sets up Cobra login and credential commands.
+package cfgauth
+
+import (
+	"errors"
+
+	"tubarr/internal/auth"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitAuthCmds is the entrypoint for initializing login and credential commands.
+func InitAuthCmds() *cobra.Command {
+	authCmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Login and credential commands.",
+		Long:  "Manage the keys protecting saved login sessions.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	authCmd.AddCommand(rotateKeyCmd())
+
+	return authCmd
+}
+
+// rotateKeyCmd replaces the session encryption key and re-encrypts the saved sessions.
+func rotateKeyCmd() *cobra.Command {
+	rotateCmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Rotate the session encryption key.",
+		Long: "Generates a new key for saved browser login sessions and re-encrypts every session with it. " +
+			"The old key is backed up first, and kept if any session fails to re-encrypt.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rotated, backup, err := auth.RotateSessionKey()
+			if err != nil {
+				return err
+			}
+			logging.S(0, "Rotated session key, re-encrypted %d saved sessions (old key backed up to %q)", rotated, backup)
+			return nil
+		},
+	}
+	return rotateCmd
+}
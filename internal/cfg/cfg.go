@@ -7,10 +7,23 @@ import (
 	"os"
 	"time"
 
+	"tubarr/internal/auth"
+	cfgauth "tubarr/internal/cfg/auth"
 	cfgchannel "tubarr/internal/cfg/channel"
+	cfgdb "tubarr/internal/cfg/db"
+	cfgdoctor "tubarr/internal/cfg/doctor"
 	cfgflags "tubarr/internal/cfg/flags"
+	cfghost "tubarr/internal/cfg/host"
+	cfgignore "tubarr/internal/cfg/ignore"
+	cfgnotify "tubarr/internal/cfg/notify"
+	cfgops "tubarr/internal/cfg/ops"
+	cfgpaths "tubarr/internal/cfg/paths"
+	cfgstats "tubarr/internal/cfg/stats"
+	cfgstorage "tubarr/internal/cfg/storage"
+	cfguser "tubarr/internal/cfg/user"
 	cfgvalidate "tubarr/internal/cfg/validation"
 	cfgvideo "tubarr/internal/cfg/video"
+	cfgytdlp "tubarr/internal/cfg/ytdlp"
 	"tubarr/internal/domain/keys"
 	"tubarr/internal/interfaces"
 	"tubarr/internal/utils/benchmark"
@@ -29,17 +42,26 @@ var (
 var rootCmd = &cobra.Command{
 	Use:   "tubarr",
 	Short: "Tubarr is a video downloading and metatagging tool.",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := cfgflags.CheckReadOnly(cmd); err != nil {
+			return err
+		}
 		if err := cfgvalidate.ValidateViperFlags(); err != nil {
-			return
+			return nil
+		}
+		secretCommands, err := cfgflags.SecretCommands(cmd)
+		if err != nil {
+			return err
 		}
+		auth.SetSecretCommands(secretCommands)
 		if viper.IsSet(keys.Benchmarking) {
 			if benchFiles, err = benchmark.SetupBenchmarking(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				return
+				return nil
 			}
 			benchmarking = true
 		}
+		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if cmd.Flags().Lookup("help").Changed {
@@ -78,8 +100,30 @@ func InitCommands(s interfaces.Store, ctx context.Context) error {
 		return err
 	}
 
-	rootCmd.AddCommand(cfgchannel.InitChannelCmds(s, ctx))
-	rootCmd.AddCommand(cfgvideo.InitVideoCmds(s))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(cfgchannel.InitChannelCmds(s, ctx)))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(cfgchannel.InitGroupCmds(s, ctx)))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(cfgchannel.InitDefaultsCmds(s.ChannelStore())))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(cfgchannel.InitPresetCmds(s.ChannelStore())))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(cfgvideo.InitVideoCmds(s)))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(cfghost.InitHostCmds(s)))
+	rootCmd.AddCommand(cfghost.InitPauseCmds(s)...)
+	rootCmd.AddCommand(cfgdoctor.InitDoctorCmds(s))
+	rootCmd.AddCommand(cfgauth.InitAuthCmds())
+	rootCmd.AddCommand(cfguser.InitUserCmds(s))
+	rootCmd.AddCommand(cfgstorage.InitStorageCmds(s))
+	rootCmd.AddCommand(cfgstats.InitStatsCmds(s))
+	rootCmd.AddCommand(cfgnotify.InitNotifyCmds(s))
+	rootCmd.AddCommand(cfgignore.InitIgnoreCmds(s))
+	rootCmd.AddCommand(cfgpaths.InitPathsCmds(s))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(cfgdb.InitDBCmds(s)))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(cfgops.InitOpsCmds()))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(cfgytdlp.InitYTDLPCmds(ctx)))
+	rootCmd.AddCommand(cfgflags.MarkReadOnlySafe(shellCmd()))
+	rootCmd.AddCommand(schedulerCmd())
+	rootCmd.AddCommand(serveCmd())
+	rootCmd.AddCommand(selfTestCmd())
+
+	cfgchannel.RegisterChannelCompletions(rootCmd, s.ChannelStore())
 	return nil
 }
 
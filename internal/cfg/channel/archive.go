@@ -0,0 +1,100 @@
+package cfgchannel
+
+import (
+	"context"
+	"errors"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/interfaces"
+
+	"github.com/spf13/cobra"
+)
+
+// archiveCmd returns the commands importing and exporting yt-dlp download archive files.
+func archiveCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
+	archiveCmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Import or export yt-dlp download archives.",
+		Long: "Share a channel's downloaded videos with yt-dlp's --download-archive files, " +
+			"which list one 'extractor id' line per video, such as 'youtube dQw4w9WgXcQ'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	archiveCmd.AddCommand(importArchiveCmd(cs, s, ctx))
+	archiveCmd.AddCommand(cfgflags.MarkReadOnlySafe(exportArchiveCmd(cs, s)))
+	return archiveCmd
+}
+
+// importArchiveCmd marks the videos in a download archive as finished.
+func importArchiveCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
+	var (
+		url, name, file string
+		id              int
+		offline         bool
+	)
+
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Mark videos in a download archive as finished.",
+		Long: "Reads a yt-dlp download archive and marks the channel's videos it lists as finished, so they aren't downloaded again. " +
+			"Entries are matched to the channel's videos by ID, YouTube entries not yet seen are added by their watch URL.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return errors.New("please enter the download archive file with --file")
+			}
+
+			key, val, err := getChanKeyVal(id, name, url)
+			if err != nil {
+				return err
+			}
+
+			return cs.ImportDownloadArchive(key, val, file, offline, s, ctx)
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(importCmd, &name, &url, &id)
+
+	importCmd.Flags().StringVar(&file, "file", "", "yt-dlp download archive file to import")
+	importCmd.Flags().BoolVar(&offline, "offline", false, "Match against videos already recorded for the channel only, without listing its remote videos")
+
+	return importCmd
+}
+
+// exportArchiveCmd writes the channel's finished videos to a download archive.
+func exportArchiveCmd(cs interfaces.ChannelStore, s interfaces.Store) *cobra.Command {
+	var (
+		url, name, file string
+		id              int
+		appendTo        bool
+	)
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write finished videos to a download archive.",
+		Long: "Writes the channel's downloaded and ignored videos to a yt-dlp download archive, for use with 'yt-dlp --download-archive'. " +
+			"Non-YouTube videos are written using the extractor in their metadata, and left out if it's unknown.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return errors.New("please enter the download archive file with --file")
+			}
+
+			key, val, err := getChanKeyVal(id, name, url)
+			if err != nil {
+				return err
+			}
+
+			return cs.ExportDownloadArchive(key, val, file, appendTo, s)
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(exportCmd, &name, &url, &id)
+
+	exportCmd.Flags().StringVar(&file, "file", "", "yt-dlp download archive file to write")
+	exportCmd.Flags().BoolVar(&appendTo, "append", false, "Add to an existing archive file, skipping entries already in it, instead of overwriting it")
+
+	return exportCmd
+}
@@ -5,14 +5,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 	cfgflags "tubarr/internal/cfg/flags"
+	cfgoutput "tubarr/internal/cfg/output"
 	cfgvalidate "tubarr/internal/cfg/validation"
 	"tubarr/internal/domain/consts"
 	"tubarr/internal/domain/keys"
 	"tubarr/internal/interfaces"
 	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/apprise"
+	"tubarr/internal/utils/chat"
+	"tubarr/internal/utils/email"
+	"tubarr/internal/utils/gotify"
+	"tubarr/internal/utils/jellyfin"
 	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/plex"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -32,30 +44,219 @@ func InitChannelCmds(s interfaces.Store, ctx context.Context) *cobra.Command {
 	cs := s.ChannelStore()
 
 	// Add subcommands with dependencies
+	channelCmd.AddCommand(cfgflags.MarkReadOnlySafe(activityCmd(cs)))
 	channelCmd.AddCommand(addAuth(cs))
 	channelCmd.AddCommand(addChannelCmd(cs))
 	channelCmd.AddCommand(dlURLs(cs, s, ctx))
-	channelCmd.AddCommand(crawlChannelCmd(cs, s, ctx))
+	channelCmd.AddCommand(redownloadCmd(cs, s, ctx))
+	channelCmd.AddCommand(cfgflags.MarkRemoteSafe(reprocessCmd(cs, s, ctx)))
+	channelCmd.AddCommand(cfgflags.MarkRemoteSafe(crawlChannelCmd(cs, s, ctx)))
+	channelCmd.AddCommand(setCookiesCmd(cs, s, ctx))
+	channelCmd.AddCommand(setOwnerCmd(cs, s.UserStore()))
 	channelCmd.AddCommand(addCrawlToIgnore(cs, s, ctx))
 	channelCmd.AddCommand(addURLToIgnore(cs))
-	channelCmd.AddCommand(deleteChannelCmd(cs))
+	channelCmd.AddCommand(deleteChannelCmd(cs, s.ConfirmStore()))
 	channelCmd.AddCommand(deleteURLs(cs))
 	channelCmd.AddCommand(deleteNotifyURLs(cs))
-	channelCmd.AddCommand(listChannelCmd(cs))
-	channelCmd.AddCommand(listAllChannelsCmd(cs))
+	channelCmd.AddCommand(discoverCmd(cs))
+	channelCmd.AddCommand(cfgflags.MarkReadOnlySafe(exportChannelsCmd(cs)))
+	channelCmd.AddCommand(importChannelsCmd(cs))
+	channelCmd.AddCommand(importExistingCmd(cs, s, ctx))
+	channelCmd.AddCommand(archiveCmd(cs, s, ctx))
+	channelCmd.AddCommand(cfgflags.MarkReadOnlySafe(listChannelCmd(cs)))
+	channelCmd.AddCommand(cfgflags.MarkReadOnlySafe(listAllChannelsCmd(cs)))
+	channelCmd.AddCommand(cfgflags.MarkReadOnlySafe(listFailedCmd(cs, s.RetryStore())))
+	channelCmd.AddCommand(cfgflags.MarkReadOnlySafe(listPostsCmd(cs, s.PostStore())))
+	channelCmd.AddCommand(urlCmd(cs, s, ctx))
 	channelCmd.AddCommand(updateChannelRow(cs))
 	channelCmd.AddCommand(updateChannelSettingsCmd(cs))
 	channelCmd.AddCommand(addNotifyURL(cs))
+	channelCmd.AddCommand(addPlexNotify(cs))
+	channelCmd.AddCommand(addMediaServerNotify(cs))
+	channelCmd.AddCommand(cfgflags.MarkReadOnlySafe(verifyCompleteCmd(cs, s, ctx)))
+	channelCmd.AddCommand(addWebhookCmd(cs))
+	channelCmd.AddCommand(deleteWebhooksCmd(cs))
+	channelCmd.AddCommand(tagCmd(cs))
+	channelCmd.AddCommand(cfgflags.MarkReadOnlySafe(listWebhooksCmd(cs)))
 
 	return channelCmd
 }
 
+// activityCmd prints a chronological history of a channel's downloads, failures, and changes.
+func activityCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		url, name string
+		id, limit int
+	)
+
+	activityCmd := &cobra.Command{
+		Use:   "activity",
+		Short: "Show channel activity history.",
+		Long:  "Prints a merged, newest first feed of downloads, failures, cancellations, skipped videos, bot blocks, settings edits, crawl runs, and archived posts for a channel.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			key, val, err := getChanKeyVal(id, name, url)
+			if err != nil {
+				return err
+			}
+
+			chanID, err := cs.GetID(key, val)
+			if err != nil {
+				return err
+			}
+
+			feed, err := cs.FetchChannelActivity(chanID, limit)
+			if err != nil {
+				return err
+			}
+			if len(feed) == 0 {
+				logging.I("No activity recorded for channel with ID %d", chanID)
+				return nil
+			}
+
+			for _, e := range feed {
+				fmt.Printf("%s  %s%-10s%s", e.Time.Local().Format(time.DateTime), consts.ColorGreen, e.Kind, consts.ColorReset)
+				if e.URL != "" {
+					fmt.Printf("  %s", e.URL)
+				}
+				if e.Detail != "" {
+					fmt.Printf("  %s", e.Detail)
+				}
+				fmt.Println()
+			}
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(activityCmd, &name, &url, &id)
+
+	activityCmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of entries to show (0 for all)")
+
+	return activityCmd
+}
+
+// listPostsCmd prints the posts archived from a channel's posts feed.
+func listPostsCmd(cs interfaces.ChannelStore, ps interfaces.PostStore) *cobra.Command {
+	var (
+		url, name string
+		id, limit int
+	)
+
+	postsCmd := &cobra.Command{
+		Use:   "posts",
+		Short: "List archived channel posts.",
+		Long:  "Prints the community, text, and image posts archived from a channel's posts feed (set with --posts-url), newest first.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			key, val, err := getChanKeyVal(id, name, url)
+			if err != nil {
+				return err
+			}
+
+			chanID, err := cs.GetID(key, val)
+			if err != nil {
+				return err
+			}
+
+			posts, err := ps.FetchPosts(chanID, limit)
+			if err != nil {
+				return err
+			}
+			if len(posts) == 0 {
+				logging.I("No posts archived for channel with ID %d", chanID)
+				return nil
+			}
+
+			for _, p := range posts {
+				at := p.PostedAt
+				if at.IsZero() {
+					at = p.CreatedAt
+				}
+				fmt.Printf("%s%s%s  %s  %s\n", consts.ColorGreen, p.PostID, consts.ColorReset, at.Local().Format(time.DateTime), p.URL)
+				if p.Text != "" {
+					fmt.Printf("  %s\n", strings.ReplaceAll(p.Text, "\n", "\n  "))
+				}
+				for _, f := range p.Files {
+					fmt.Printf("  File: %s\n", f)
+				}
+				fmt.Println()
+			}
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(postsCmd, &name, &url, &id)
+
+	postsCmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of posts to show (0 for all)")
+
+	return postsCmd
+}
+
+// listFailedCmd prints failed downloads in the retry queue, with when each is next retried.
+func listFailedCmd(cs interfaces.ChannelStore, rs interfaces.RetryStore) *cobra.Command {
+	var (
+		url, name string
+		id        int
+	)
+
+	listFailedCmd := &cobra.Command{
+		Use:   "list-failed",
+		Short: "List failed downloads awaiting retry.",
+		Long:  "Prints failed downloads in the retry queue, newest failure first, with attempts made, the next retry time, the failure reason if recognized, and the last error. Lists all channels if none is given.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			var chanID int64
+			if id != 0 || name != "" || url != "" {
+				key, val, err := getChanKeyVal(id, name, url)
+				if err != nil {
+					return err
+				}
+				if chanID, err = cs.GetID(key, val); err != nil {
+					return err
+				}
+			}
+
+			entries, err := rs.FetchChannelRetries(chanID)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				logging.I("No failed downloads in the retry queue")
+				return nil
+			}
+
+			for _, e := range entries {
+				next := consts.ColorRed + "gave up" + consts.ColorReset
+				if !e.Exhausted() {
+					next = "retry at " + e.NextAttempt.Local().Format(time.DateTime)
+				}
+				if e.FailReason != "" {
+					next += ", reason: " + string(e.FailReason)
+				}
+				fmt.Printf("%s  [channel %d]  %s  attempts: %d, %s\n", e.UpdatedAt.Local().Format(time.DateTime), e.ChannelID, e.URL, e.Attempts, next)
+				if e.LastError != "" {
+					fmt.Printf("    %s\n", e.LastError)
+				}
+			}
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(listFailedCmd, &name, &url, &id)
+
+	return listFailedCmd
+}
+
 // addAuth adds authentication details to a channel.
 func addAuth(cs interfaces.ChannelStore) *cobra.Command {
 	var (
 		channelName, channelURL      string
 		channelID                    int
 		username, password, loginURL string
+		authMethod                   string
 	)
 
 	addAuthCmd := &cobra.Command{
@@ -66,6 +267,9 @@ func addAuth(cs interfaces.ChannelStore) *cobra.Command {
 			if username == "" || password == "" || loginURL == "" {
 				return errors.New("must enter a username, password, and login URL")
 			}
+			if err := cfgvalidate.ValidateAuthMethod(authMethod); err != nil {
+				return err
+			}
 
 			chanID := int64(channelID)
 
@@ -83,11 +287,20 @@ func addAuth(cs interfaces.ChannelStore) *cobra.Command {
 			if err := cs.AddAuth(chanID, username, password, loginURL); err != nil {
 				return err
 			}
+
+			if authMethod != "" {
+				if _, err := cs.UpdateChannelSettingsJSON(consts.QChanID, strconv.FormatInt(chanID, 10), func(s *models.ChannelSettings) error {
+					s.AuthMethod = authMethod
+					return nil
+				}); err != nil {
+					return fmt.Errorf("failed to set login method: %w", err)
+				}
+			}
 			return nil
 		},
 	}
 	SetPrimaryChannelFlags(addAuthCmd, &channelName, &channelURL, &channelID)
-	cfgflags.SetAuthFlags(addAuthCmd, &username, &password, &loginURL)
+	cfgflags.SetAuthFlags(addAuthCmd, &username, &password, &loginURL, &authMethod)
 	return addAuthCmd
 }
 
@@ -176,6 +389,87 @@ func dlURLs(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context)
 	return dlURLFileCmd
 }
 
+// redownloadCmd downloads videos again, running them through the full download and Metarr pipeline.
+func redownloadCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
+	var (
+		channelURL, channelName, token string
+		channelID                      int
+		urls                           []string
+		deleteFiles                    bool
+	)
+
+	redownloadCmd := &cobra.Command{
+		Use:   "redownload",
+		Short: "Download videos again.",
+		Long: "Marks downloaded videos as not finished and runs them through the download and Metarr pipeline again, " +
+			"for example after changing transcode settings. Deleting the existing files must be confirmed with the token printed on the first run.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(urls) == 0 {
+				return errors.New("must enter at least one video URL")
+			}
+
+			key, val, err := getChanKeyVal(channelID, channelName, channelURL)
+			if err != nil {
+				return err
+			}
+
+			if deleteFiles {
+				chanID, err := cs.GetID(key, val)
+				if err != nil {
+					return err
+				}
+				sorted := slices.Sorted(slices.Values(urls))
+				operation := fmt.Sprintf("redownload:%d:%s", chanID, strings.Join(sorted, ","))
+				summary := fmt.Sprintf("delete the downloaded files of %d videos in channel with ID %d and download them again", len(urls), chanID)
+				confirmed, err := ConfirmDestructive(s.ConfirmStore(), operation, summary, token)
+				if err != nil || !confirmed {
+					return err
+				}
+			}
+
+			return cs.RedownloadVideos(key, val, urls, deleteFiles, s, ctx)
+		},
+	}
+
+	SetPrimaryChannelFlags(redownloadCmd, &channelName, &channelURL, &channelID)
+	redownloadCmd.Flags().StringSliceVar(&urls, keys.URLs, nil, "Video URLs to download again")
+	redownloadCmd.Flags().BoolVar(&deleteFiles, keys.DeleteFiles, false, "Delete the existing video and JSON files first")
+	SetConfirmFlag(redownloadCmd, &token)
+
+	return redownloadCmd
+}
+
+// reprocessCmd runs downloaded videos through Metarr again without downloading them.
+func reprocessCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
+	var (
+		channelURL, channelName string
+		channelID               int
+		urls                    []string
+	)
+
+	reprocessCmd := &cobra.Command{
+		Use:   "reprocess",
+		Short: "Run downloaded videos through Metarr again.",
+		Long: "Re-applies Metarr processing (meta ops, renaming, transcoding) to a channel's downloaded videos with its current Metarr settings, " +
+			"without downloading them again. Processes every downloaded video unless URLs are entered.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, val, err := getChanKeyVal(channelID, channelName, channelURL)
+			if err != nil {
+				return err
+			}
+			if cfgflags.RemoteAddr() != "" || cfgflags.QueueCommands() {
+				return delegateChannelAction(cs, key, val, consts.CommandReprocess, urls)
+			}
+			return cs.ReprocessChannelMetarr(key, val, urls, s, ctx)
+		},
+	}
+
+	SetPrimaryChannelFlags(reprocessCmd, &channelName, &channelURL, &channelID)
+	reprocessCmd.Flags().StringSliceVar(&urls, keys.URLs, nil, "Only reprocess these video URLs")
+
+	return reprocessCmd
+}
+
 // deleteNotifyURLs deletes notification URLs from a channel.
 func deleteNotifyURLs(cs interfaces.ChannelStore) *cobra.Command {
 	var (
@@ -236,12 +530,18 @@ func addNotifyURL(cs interfaces.ChannelStore) *cobra.Command {
 	addNotifyCmd := &cobra.Command{
 		Use:   "notify",
 		Short: "Adds notify function to a channel.",
-		Long:  "Enter a fully qualified notification URL here to send update requests to platforms like Plex etc.",
+		Long: "Enter a fully qualified notification URL here to send update requests to platforms like Plex etc. " +
+			"Use 'mailto:address' to email downloaded videos with the SMTP settings from 'tubarr notify smtp set'.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			if notifyURL == "" {
 				return errors.New("notification URL cannot be blank")
 			}
+			if addr, ok := strings.CutPrefix(notifyURL, email.MailtoScheme); ok {
+				if err := email.ValidateAddresses([]string{addr}); err != nil {
+					return err
+				}
+			}
 
 			var (
 				id = int64(channelID)
@@ -278,6 +578,214 @@ func addNotifyURL(cs interfaces.ChannelStore) *cobra.Command {
 	return addNotifyCmd
 }
 
+// addPlexNotify looks up a Plex library section and adds its refresh URL as a notification.
+func addPlexNotify(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		channelName, channelURL string
+		channelID               int
+		server, token, library  string
+		notifyName              string
+		insecure                bool
+	)
+
+	addPlexCmd := &cobra.Command{
+		Use:   "notify-add-plex",
+		Short: "Adds a Plex library refresh notification to a channel.",
+		Long:  "Queries the Plex server for its library sections, then stores the refresh URL for the chosen section. Prompts for a section if --library is not given or doesn't match.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if server == "" || token == "" {
+				return errors.New("plex server and token are required")
+			}
+
+			key, val, err := getChanKeyVal(channelID, channelName, channelURL)
+			if err != nil {
+				return err
+			}
+
+			id, err := cs.GetID(key, val)
+			if err != nil {
+				return err
+			}
+
+			sections, err := plex.FetchSections(server, token, insecure)
+			if err != nil {
+				return err
+			}
+			if len(sections) == 0 {
+				return fmt.Errorf("no library sections found on Plex server %q", server)
+			}
+
+			section, err := pickPlexSection(sections, library)
+			if err != nil {
+				return err
+			}
+
+			if notifyName == "" {
+				notifyName = "Plex: " + section.Title
+			}
+
+			if err := cs.AddNotifyURL(id, notifyName, plex.RefreshURL(server, token, section.Key)); err != nil {
+				return err
+			}
+			logging.S(0, "Added Plex refresh for library %q (section %s) to channel with ID %d", section.Title, section.Key, id)
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(addPlexCmd, &channelName, &channelURL, &channelID)
+	addPlexCmd.Flags().StringVar(&server, "server", "", "Plex server address (e.g. http://host:32400)")
+	addPlexCmd.Flags().StringVar(&token, "token", "", "Plex authentication token")
+	addPlexCmd.Flags().StringVar(&library, "library", "", "Name of the Plex library to refresh")
+	addPlexCmd.Flags().StringVar(&notifyName, "notify-name", "", "Provide a custom name for this notification")
+	addPlexCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip verifying the server's TLS certificate, for self-signed certificates")
+
+	return addPlexCmd
+}
+
+// Notification types for notify-add besides media servers and chat providers.
+const (
+	typeGotify  = "gotify"
+	typeApprise = "apprise"
+)
+
+// addMediaServerNotify adds a Jellyfin or Emby library refresh, or a Discord, Telegram, ntfy, Gotify, or Apprise message, as a notification.
+func addMediaServerNotify(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		channelName, channelURL    string
+		channelID                  int
+		serverType, server, apiKey string
+		webhookURL, botToken       string
+		chatID, topic, appriseURL  string
+		appToken                   string
+		events                     []string
+		priority, errorPriority    int
+		notifyName                 string
+		insecure                   bool
+	)
+
+	addMediaServerCmd := &cobra.Command{
+		Use:   "notify-add",
+		Short: "Adds a media server refresh or chat notification to a channel.",
+		Long: "Checks the server accepts the API key, then stores its library refresh URL. Use 'notify-add-plex' for Plex servers. " +
+			"Discord, Telegram, and ntfy types post a message with the title, channel, and thumbnail of each downloaded video. " +
+			"The gotify type pushes new videos, crawl failures, and blocked channels to a Gotify application, as chosen with --events. " +
+			"The apprise type takes any Apprise service URL, sending services other than Discord, Telegram, and ntfy through the apprise tool.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			serverType = strings.ToLower(serverType)
+			if serverType == "plex" {
+				return errors.New("use 'channel notify-add-plex' for Plex servers")
+			}
+
+			var (
+				notifyURL, target string
+				err               error
+			)
+			switch serverType {
+			case chat.Discord:
+				if webhookURL == "" {
+					return errors.New("webhook URL is required for Discord")
+				}
+				notifyURL, err = chat.DiscordURL(webhookURL)
+				target = "Discord webhook"
+			case chat.Telegram:
+				notifyURL, err = chat.TelegramURL(botToken, chatID)
+				target = "Telegram chat " + chatID
+			case chat.Ntfy:
+				notifyURL, err = chat.NtfyURL(server, topic)
+				target = "ntfy topic " + topic
+			case typeGotify:
+				t := &gotify.Target{Server: server, Token: appToken, Events: events, Priority: priority, ErrorPriority: errorPriority}
+				err = gotify.Validate(t)
+				notifyURL = t.URL()
+				target = "Gotify server " + server
+			case typeApprise:
+				if appriseURL == "" {
+					return errors.New("an Apprise URL is required, set with --url")
+				}
+				err = apprise.Validate(viper.GetString(keys.AppriseBin), appriseURL)
+				notifyURL = apprise.Scheme + appriseURL
+				target, _, _ = strings.Cut(appriseURL, "://")
+				target = "Apprise " + target
+			case jellyfin.TypeJellyfin, jellyfin.TypeEmby:
+				if server == "" || apiKey == "" {
+					return errors.New("server and API key are required")
+				}
+			default:
+				return fmt.Errorf("unsupported notification type %q, expected jellyfin, emby, %s, %s, or %s", serverType, strings.Join(chat.Providers(), ", "), typeGotify, typeApprise)
+			}
+			if err != nil {
+				return err
+			}
+
+			key, val, err := getChanKeyVal(channelID, channelName, channelURL)
+			if err != nil {
+				return err
+			}
+
+			id, err := cs.GetID(key, val)
+			if err != nil {
+				return err
+			}
+
+			if notifyURL != "" {
+				if notifyName == "" {
+					notifyName = target
+				}
+				if err := cs.AddNotifyURL(id, notifyName, notifyURL); err != nil {
+					return err
+				}
+				logging.S(0, "Added %s notification to channel with ID %d", target, id)
+				return nil
+			}
+
+			serverName, err := jellyfin.CheckServer(serverType, server, apiKey, insecure)
+			if err != nil {
+				return err
+			}
+
+			refreshURL, err := jellyfin.RefreshURL(serverType, server, apiKey)
+			if err != nil {
+				return err
+			}
+
+			if serverName == "" {
+				serverName = server
+			}
+			if notifyName == "" {
+				notifyName = serverType + ": " + serverName
+			}
+
+			if err := cs.AddNotifyURL(id, notifyName, refreshURL); err != nil {
+				return err
+			}
+			logging.S(0, "Added %s library refresh for server %q to channel with ID %d", serverType, serverName, id)
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(addMediaServerCmd, &channelName, &channelURL, &channelID)
+	addMediaServerCmd.Flags().StringVar(&serverType, "type", jellyfin.TypeJellyfin, "Notification type (jellyfin, emby, discord, telegram, ntfy, gotify, or apprise)")
+	addMediaServerCmd.Flags().StringVar(&server, "server", "", "Server address (e.g. http://host:8096), or the ntfy server (default "+chat.DefaultNtfy+")")
+	addMediaServerCmd.Flags().StringVar(&appToken, "app-token", "", "Gotify application token")
+	addMediaServerCmd.Flags().StringSliceVar(&events, "events", gotify.Events(), "Events to push to Gotify ("+strings.Join(gotify.Events(), ", ")+")")
+	addMediaServerCmd.Flags().IntVar(&priority, "priority", gotify.DefaultPriority, "Gotify priority for new videos (0-10)")
+	addMediaServerCmd.Flags().IntVar(&errorPriority, "error-priority", gotify.DefaultErrorPriority, "Gotify priority for crawl failures and blocked channels (0-10)")
+	addMediaServerCmd.Flags().StringVar(&apiKey, "api-key", "", "Server API key")
+	addMediaServerCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip verifying the Jellyfin or Emby server's TLS certificate, for self-signed certificates")
+	addMediaServerCmd.Flags().StringVar(&webhookURL, "webhook-url", "", "Discord webhook URL")
+	addMediaServerCmd.Flags().StringVar(&botToken, "bot-token", "", "Telegram bot token")
+	addMediaServerCmd.Flags().StringVar(&chatID, "chat-id", "", "Telegram chat ID to message")
+	addMediaServerCmd.Flags().StringVar(&topic, "topic", "", "ntfy topic to publish to")
+	addMediaServerCmd.Flags().StringVar(&appriseURL, "url", "", "Apprise service URL (e.g. 'tgram://bottoken/chatid' or 'slack://tokenA/tokenB/tokenC')")
+	addMediaServerCmd.Flags().StringVar(&notifyName, "notify-name", "", "Provide a custom name for this notification")
+
+	return addMediaServerCmd
+}
+
 // addURLToIgnore adds a user inputted URL to ignore from crawls.
 func addURLToIgnore(cs interfaces.ChannelStore) *cobra.Command {
 	var (
@@ -305,9 +813,15 @@ func addURLToIgnore(cs interfaces.ChannelStore) *cobra.Command {
 				return err
 			}
 
-			if err := cs.AddURLToIgnore(id, ignoreURL); err != nil {
+			caught, err := cs.AddURLToIgnore(id, ignoreURL)
+			if err != nil {
 				return err
 			}
+			if caught {
+				logging.S(0, "URL %q was caught before its download started, any running crawl will skip it", ignoreURL)
+			} else {
+				logging.I("Download for URL %q had already started or finished, it will only be skipped in future crawls", ignoreURL)
+			}
 			return nil
 		},
 	}
@@ -355,10 +869,27 @@ func addChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 	var (
 		url, name, vDir, jDir, outDir, cookieSource,
 		externalDownloader, externalDownloaderArgs, maxFilesize, filenameDateTag, renameStyle, minFreeMem, metarrExt,
-		username, password, loginURL string
+		username, password, loginURL, ytdlpExtraArgs, playlistMatch string
+		maxRate, fetcher                                   string
+		sponsorBlockRemove, sponsorBlockMark, postsURL     string
+		crawlCron, quietHours, maxTotalSize, liveURL       string
+		preset, stagingDir                                 string
+		fallbackProxy, fallbackCookieSource, authMethod    string
+		minDuration, maxDuration                           string
+		maxResolution, preferredCodec                      string
+		nfoEpisodeTemplate, nfoShowTemplate, nfoMapFile    string
+		nfoFieldMap                                        []string
+		outputTemplate, transcribeModel, transcribeLang    string
 		dlFilters, metaOps, fileSfxReplace                 []string
+		urlAllow, urlBlock, blackoutDates, externalIDs     []string
+		proxies, fetcherRules, sidecars, postProcessors    []string
 		crawlFreq, concurrency, metarrConcurrency, retries int
+		fragments, connections, jitter, retryMaxAttempts   int
+		maxPerCrawl, keepLast, keepDays, liveCheckFreq     int
+		minViews                                           int
 		maxCPU                                             float64
+		skipMetarr, diagnostics, quotaPrune                bool
+		retentionNotify, audioOnly, writeNFO, transcribe   bool
 	)
 
 	now := time.Now()
@@ -382,7 +913,7 @@ func addChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 			}
 
 			// Verify filters
-			dlFilters, err := verifyChannelOps(dlFilters)
+			dlFilters, err := parsing.ParseFilters(dlFilters)
 			if err != nil {
 				return err
 			}
@@ -417,11 +948,139 @@ func addChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 				}
 			}
 
-			c := &models.Channel{
-				URL:      url,
-				Name:     name,
-				VideoDir: vDir,
-				JSONDir:  jDir,
+			if urlAllow, err = cfgvalidate.ValidateURLPatterns(urlAllow); err != nil {
+				return err
+			}
+
+			if urlBlock, err = cfgvalidate.ValidateURLPatterns(urlBlock); err != nil {
+				return err
+			}
+
+			if blackoutDates, err = cfgvalidate.ValidateBlackoutDates(blackoutDates); err != nil {
+				return err
+			}
+
+			externalIDMap, err := cfgvalidate.ValidateExternalIDs(externalIDs)
+			if err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateSchedule(crawlCron, quietHours, jitter); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateRetryMaxAttempts(retryMaxAttempts); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateMaxDownloadsPerCrawl(maxPerCrawl); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateMaxRate(maxRate); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateMetadataLimits(minDuration, maxDuration, minViews); err != nil {
+				return err
+			}
+
+			maxHeight, err := cfgvalidate.ValidateFormat(maxResolution, preferredCodec)
+			if err != nil {
+				return err
+			}
+			if preferredCodec == parsing.CodecAny {
+				preferredCodec = ""
+			}
+
+			if sidecars, err = cfgvalidate.ValidateSidecars(sidecars); err != nil {
+				return err
+			}
+
+			if postProcessors, err = cfgvalidate.ValidatePostProcessors(postProcessors); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateNFOTemplates(nfoEpisodeTemplate, nfoShowTemplate); err != nil {
+				return err
+			}
+			nfoFields, err := cfgvalidate.ValidateNFOFieldMap(nfoFieldMap, nfoMapFile)
+			if err != nil {
+				return err
+			}
+
+			if transcribe && transcribeModel == "" {
+				return fmt.Errorf("--%s needs a whisper.cpp model, set it with --%s", keys.Transcribe, keys.TranscribeModel)
+			}
+			if err := cfgvalidate.ValidateTranscribe(transcribeModel, transcribeLang); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateOutputTemplate(outputTemplate); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateFetcher(fetcher); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateSponsorBlock(sponsorBlockRemove); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateSponsorBlock(sponsorBlockMark); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidatePostsURL(postsURL); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateMaxTotalSize(maxTotalSize); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateRetention(keepLast, keepDays); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateProxies(proxies); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateFallbackProxy(fallbackProxy); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateAuthMethod(authMethod); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateFetcherRules(fetcherRules); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateLive(liveURL, liveCheckFreq); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateConcurrentFragments(fragments); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidatePlaylistMatch(playlistMatch); err != nil {
+				return err
+			}
+
+			if err := cfgvalidate.ValidateExternalDLConnections(connections, externalDownloader); err != nil {
+				return err
+			}
+
+			c := &models.Channel{
+				URL:      url,
+				Name:     name,
+				VideoDir: vDir,
+				JSONDir:  jDir,
 
 				Settings: models.ChannelSettings{
 					CrawlFreq:              crawlFreq,
@@ -432,6 +1091,55 @@ func addChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 					ExternalDownloaderArgs: externalDownloaderArgs,
 					Concurrency:            concurrency,
 					MaxFilesize:            maxFilesize,
+					URLAllow:               urlAllow,
+					URLBlock:               urlBlock,
+					SkipMetarr:             skipMetarr,
+					ExtractorDiagnostics:   diagnostics,
+					YTDLPExtraArgs:         ytdlpExtraArgs,
+					ConcurrentFragments:    fragments,
+					ExternalDLConnections:  connections,
+					PlaylistMatch:          playlistMatch,
+					BlackoutDates:          blackoutDates,
+					CrawlCron:              crawlCron,
+					QuietHours:             quietHours,
+					CrawlJitter:            jitter,
+					ExternalIDs:            externalIDMap,
+					RetryMaxAttempts:       retryMaxAttempts,
+					MaxDownloadsPerCrawl:   maxPerCrawl,
+					MaxRate:                maxRate,
+					Fetcher:                fetcher,
+					FetcherRules:           fetcherRules,
+					SponsorBlockRemove:     sponsorBlockRemove,
+					SponsorBlockMark:       sponsorBlockMark,
+					PostsURL:               postsURL,
+					MaxTotalSize:           maxTotalSize,
+					QuotaPrune:             quotaPrune,
+					KeepLast:               keepLast,
+					KeepDays:               keepDays,
+					RetentionNotify:        retentionNotify,
+					Proxies:                proxies,
+					LiveURL:                liveURL,
+					LiveCheckFreq:          liveCheckFreq,
+					StagingDir:             stagingDir,
+					FallbackProxy:          fallbackProxy,
+					FallbackCookieSource:   fallbackCookieSource,
+					AuthMethod:             authMethod,
+					MinDuration:            minDuration,
+					MaxDuration:            maxDuration,
+					MinViews:               minViews,
+					MaxResolution:          maxHeight,
+					PreferredCodec:         preferredCodec,
+					AudioOnly:              audioOnly,
+					Sidecars:               sidecars,
+					NFO:                    writeNFO,
+					NFOEpisodeTemplate:     nfoEpisodeTemplate,
+					NFOShowTemplate:        nfoShowTemplate,
+					NFOFieldMap:            nfoFields,
+					OutputTemplate:         outputTemplate,
+					PostProcessors:         postProcessors,
+					Transcribe:             transcribe,
+					TranscribeModel:        transcribeModel,
+					TranscribeLanguage:     transcribeLang,
 				},
 
 				MetarrArgs: models.MetarrArgs{
@@ -454,6 +1162,19 @@ func addChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 				UpdatedAt: now,
 			}
 
+			// Preset fills settings not entered as flags
+			if preset != "" {
+				p, err := cs.GetPreset(preset)
+				if err != nil {
+					return err
+				}
+				if !cmd.Flags().Changed(keys.CrawlFreq) && p.Settings.CrawlFreq > 0 {
+					c.Settings.CrawlFreq = p.Settings.CrawlFreq
+				}
+				p.Apply(c)
+				logging.I("Applied preset %q to channel %q", p.Name, c.Name)
+			}
+
 			if _, err := cs.AddChannel(c); err != nil {
 				return err
 			}
@@ -463,36 +1184,65 @@ func addChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 
 	// Primary channel elements
 	SetPrimaryChannelFlags(addCmd, &name, &url, nil)
+	addCmd.Flags().StringVar(&preset, keys.Preset, "", "Start from the settings of this preset, see 'preset list'")
 
 	// Files/dirs
 	cfgflags.SetFileDirFlags(addCmd, &jDir, &vDir)
+	cfgflags.SetStagingDirFlag(addCmd, &stagingDir)
 
 	// Program related
 	cfgflags.SetProgramRelatedFlags(addCmd, &concurrency, &crawlFreq, &externalDownloaderArgs, &externalDownloader)
+	cfgflags.SetScheduleFlags(addCmd, &crawlCron, &quietHours, &jitter)
 
 	// Download
 	cfgflags.SetDownloadFlags(addCmd, &retries, &cookieSource, &maxFilesize, &dlFilters)
+	cfgflags.SetRetryMaxAttemptsFlag(addCmd, &retryMaxAttempts)
+	cfgflags.SetMaxDownloadsPerCrawlFlag(addCmd, &maxPerCrawl)
+	cfgflags.SetMetadataLimitFlags(addCmd, &minDuration, &maxDuration, &minViews)
+	cfgflags.SetFormatFlags(addCmd, &maxResolution, &preferredCodec, &audioOnly)
+	cfgflags.SetSidecarsFlag(addCmd, &sidecars)
+	cfgflags.SetNFOFlags(addCmd, &writeNFO, &nfoEpisodeTemplate, &nfoShowTemplate, &nfoFieldMap, &nfoMapFile)
+	cfgflags.SetOutputTemplateFlag(addCmd, &outputTemplate)
+	cfgflags.SetPostProcessorsFlag(addCmd, &postProcessors)
+	cfgflags.SetTranscribeFlags(addCmd, &transcribe, &transcribeModel, &transcribeLang)
+	cfgflags.SetMaxRateFlag(addCmd, &maxRate)
+	cfgflags.SetFetcherFlags(addCmd, &fetcher, &fetcherRules)
+	cfgflags.SetSponsorBlockFlags(addCmd, &sponsorBlockRemove, &sponsorBlockMark)
+	cfgflags.SetPostsURLFlag(addCmd, &postsURL)
+	cfgflags.SetQuotaFlags(addCmd, &maxTotalSize, &quotaPrune)
+	cfgflags.SetRetentionFlags(addCmd, &keepLast, &keepDays, &retentionNotify)
+	cfgflags.SetProxyFlag(addCmd, &proxies)
+	cfgflags.SetFallbackFlags(addCmd, &fallbackProxy, &fallbackCookieSource)
+	cfgflags.SetLiveFlags(addCmd, &liveURL, &liveCheckFreq)
+	cfgflags.SetURLPatternFlags(addCmd, &urlAllow, &urlBlock)
+	cfgflags.SetYTDLPExtraArgsFlag(addCmd, &ytdlpExtraArgs)
+	cfgflags.SetConnectionFlags(addCmd, &fragments, &connections)
+	cfgflags.SetPlaylistMatchFlag(addCmd, &playlistMatch)
+	cfgflags.SetBlackoutDatesFlag(addCmd, &blackoutDates)
 
 	// Metarr
 	cfgflags.SetMetarrFlags(addCmd, &maxCPU, &metarrConcurrency, &metarrExt, &filenameDateTag, &minFreeMem, &outDir, &renameStyle, &fileSfxReplace, &metaOps)
+	cfgflags.SetSkipMetarrFlag(addCmd, &skipMetarr)
+	cfgflags.SetExtractorDiagnosticsFlag(addCmd, &diagnostics)
+	cfgflags.SetExternalIDsFlag(addCmd, &externalIDs)
 
 	// Login credentials
-	cfgflags.SetAuthFlags(addCmd, &username, &password, &loginURL)
+	cfgflags.SetAuthFlags(addCmd, &username, &password, &loginURL, &authMethod)
 
 	return addCmd
 }
 
 // deleteChannelCmd deletes a channel from the database.
-func deleteChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
+func deleteChannelCmd(cs interfaces.ChannelStore, fs interfaces.ConfirmStore) *cobra.Command {
 	var (
-		url, name string
-		id        int
+		url, name, token string
+		id               int
 	)
 
 	delCmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete channels.",
-		Long:  "Delete a channel by ID, name, or URL.",
+		Long:  "Delete a channel by ID, name, or URL. The first run prints a confirmation token which must be passed with --confirm to delete.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if url == "" && name == "" {
 				return errors.New("must enter both a video directory and url")
@@ -503,6 +1253,21 @@ func deleteChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 				return err
 			}
 
+			cid, err := cs.GetID(key, val)
+			if err != nil {
+				return err
+			}
+			urls, err := cs.LoadAllVideoURLs(&models.Channel{ID: cid})
+			if err != nil {
+				return err
+			}
+
+			summary := fmt.Sprintf("delete channel with key %q and value %q (ID %d) and its %d video records", key, val, cid, len(urls))
+			confirmed, err := ConfirmDestructive(fs, fmt.Sprintf("channel-delete:%d", cid), summary, token)
+			if err != nil || !confirmed {
+				return err
+			}
+
 			if err := cs.DeleteChannel(key, val); err != nil {
 				return err
 			}
@@ -513,6 +1278,7 @@ func deleteChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 
 	// Primary channel elements
 	SetPrimaryChannelFlags(delCmd, &name, &url, &id)
+	SetConfirmFlag(delCmd, &token)
 
 	return delCmd
 }
@@ -521,6 +1287,7 @@ func deleteChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 func listChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 	var (
 		url, name, key, val string
+		format              string
 		err                 error
 		channelID           int
 	)
@@ -529,6 +1296,9 @@ func listChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 		Short: "List a channel's details.",
 		Long:  "Lists details of a channel in the database.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfgoutput.Validate(&format); err != nil {
+				return err
+			}
 
 			id := int64(channelID)
 			if id == 0 {
@@ -551,46 +1321,89 @@ func listChannelCmd(cs interfaces.ChannelStore) *cobra.Command {
 				return err
 			}
 
-			fmt.Printf("\n%sChannel ID: %d%s\nName: %s\nURL: %s\nVideo Directory: %s\nJSON Directory: %s\n", consts.ColorGreen, ch.ID, consts.ColorReset, ch.Name, ch.URL, ch.VideoDir, ch.JSONDir)
-			fmt.Printf("Crawl Frequency: %d minutes\nFilters: %v\nConcurrency: %d\nCookie Source: %s\nRetries: %d\n", ch.Settings.CrawlFreq, ch.Settings.Filters, ch.Settings.Concurrency, ch.Settings.CookieSource, ch.Settings.Retries)
-			fmt.Printf("External Downloader: %s\nExternal Downloader Args: %s\nMax Filesize: %s\n", ch.Settings.ExternalDownloader, ch.Settings.ExternalDownloaderArgs, ch.Settings.MaxFilesize)
-			fmt.Printf("Max CPU: %.2f\nMetarr Concurrency: %d\nMin Free Mem: %s\nOutput Dir: %s\nOutput Filetype: %s\n", ch.MetarrArgs.MaxCPU, ch.MetarrArgs.Concurrency, ch.MetarrArgs.MinFreeMem, ch.MetarrArgs.OutputDir, ch.MetarrArgs.Ext)
-			fmt.Printf("Rename Style: %s\nFilename Suffix Replace: %v\nMeta Ops: %v\nFilename Date Format: %s\n", ch.MetarrArgs.RenameStyle, ch.MetarrArgs.FilenameReplaceSfx, ch.MetarrArgs.MetaOps, ch.MetarrArgs.FileDatePfx)
+			tags, err := cs.GetChannelTags(ch.ID)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case cfgoutput.JSON, cfgoutput.YAML:
+				return cfgoutput.Print(format, toChannelOutput(ch, tags))
+			case cfgoutput.Table:
+				return printChannels(format, []channelOutput{toChannelOutput(ch, tags)})
+			}
+
+			printChannelInfo(ch, tags)
 
 			return nil
 		},
 	}
 	// Primary channel elements
 	SetPrimaryChannelFlags(listCmd, &name, &url, &channelID)
+	cfgoutput.SetFlag(listCmd, &format)
 	return listCmd
 }
 
 // listAllChannelsCmd returns a list of channels in the database.
 func listAllChannelsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var tag, format string
+
 	listAllCmd := &cobra.Command{
 		Use:   "list-all",
 		Short: "List all channels.",
-		Long:  "Lists all channels currently saved in the database.",
+		Long:  "Lists all channels currently saved in the database, or only those with a tag.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			chans, err, hasRows := cs.FetchAllChannels()
-			if !hasRows {
-				logging.I("No entries in the database")
-				return nil
-			}
-			if err != nil {
+			if err := cfgoutput.Validate(&format); err != nil {
 				return err
 			}
 
+			var chans []*models.Channel
+			if tag != "" {
+				tagged, err := groupChannels(cs, tag)
+				if err != nil {
+					return err
+				}
+				chans = tagged
+			} else {
+				all, err, hasRows := cs.FetchAllChannels()
+				if !hasRows {
+					if cfgoutput.Structured(format) {
+						return cfgoutput.Print(format, []channelOutput{})
+					}
+					logging.I("No entries in the database")
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				chans = all
+			}
+
+			if format != cfgoutput.Text {
+				out := make([]channelOutput, 0, len(chans))
+				for _, ch := range chans {
+					tags, err := cs.GetChannelTags(ch.ID)
+					if err != nil {
+						return err
+					}
+					out = append(out, toChannelOutput(ch, tags))
+				}
+				return printChannels(format, out)
+			}
+
 			for _, ch := range chans {
-				fmt.Printf("\n%sChannel ID: %d%s\nName: %s\nURL: %s\nVideo Directory: %s\nJSON Directory: %s\n", consts.ColorGreen, ch.ID, consts.ColorReset, ch.Name, ch.URL, ch.VideoDir, ch.JSONDir)
-				fmt.Printf("Crawl Frequency: %d minutes\nFilters: %v\nConcurrency: %d\nCookie Source: %s\nRetries: %d\n", ch.Settings.CrawlFreq, ch.Settings.Filters, ch.Settings.Concurrency, ch.Settings.CookieSource, ch.Settings.Retries)
-				fmt.Printf("External Downloader: %s\nExternal Downloader Args: %s\nMax Filesize: %s\n", ch.Settings.ExternalDownloader, ch.Settings.ExternalDownloaderArgs, ch.Settings.MaxFilesize)
-				fmt.Printf("Max CPU: %.2f\nMetarr Concurrency: %d\nMin Free Mem: %s\nOutput Dir: %s\nOutput Filetype: %s\n", ch.MetarrArgs.MaxCPU, ch.MetarrArgs.Concurrency, ch.MetarrArgs.MinFreeMem, ch.MetarrArgs.OutputDir, ch.MetarrArgs.Ext)
-				fmt.Printf("Rename Style: %s\nFilename Suffix Replace: %v\nMeta Ops: %v\nFilename Date Format: %s\n", ch.MetarrArgs.RenameStyle, ch.MetarrArgs.FilenameReplaceSfx, ch.MetarrArgs.MetaOps, ch.MetarrArgs.FileDatePfx)
+				tags, err := cs.GetChannelTags(ch.ID)
+				if err != nil {
+					return err
+				}
+
+				printChannelInfo(ch, tags)
 			}
 			return nil
 		},
 	}
+	listAllCmd.Flags().StringVar(&tag, keys.ChannelTag, "", "Only list channels with this tag")
+	cfgoutput.SetFlag(listAllCmd, &format)
 	return listAllCmd
 }
 
@@ -612,6 +1425,9 @@ func crawlChannelCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context
 				return err
 			}
 
+			if cfgflags.RemoteAddr() != "" || cfgflags.QueueCommands() {
+				return delegateChannelAction(cs, key, val, consts.CommandCrawl, nil)
+			}
 			if err := cs.CrawlChannel(key, val, s, ctx); err != nil {
 				return err
 			}
@@ -625,133 +1441,442 @@ func crawlChannelCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context
 	return crawlCmd
 }
 
-// updateChannelSettingsCmd updates channel settings.
-func updateChannelSettingsCmd(cs interfaces.ChannelStore) *cobra.Command {
+// setCookiesCmd crawls a channel with cookies pasted by the user, for sites showing a captcha or login wall.
+func setCookiesCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
 	var (
-		id, concurrency, crawlFreq, metarrConcurrency, retries  int
-		maxCPU                                                  float64
-		vDir, jDir, outDir                                      string
-		name, url, cookieSource                                 string
-		minFreeMem, renameStyle, filenameDateTag, metarrExt     string
-		maxFilesize, externalDownloader, externalDownloaderArgs string
-		username, password, loginURL                            string
-		dlFilters, metaOps                                      []string
-		fileSfxReplace                                          []string
+		url, name, cookies, cookiesFile string
+		id                              int
 	)
 
-	updateSettingsCmd := &cobra.Command{
-		Use:   "update-settings",
-		Short: "Update channel settings.",
-		Long:  "Update channel settings with various parameters, both for Tubarr itself and for external software like Metarr.",
+	setCookiesCmd := &cobra.Command{
+		Use:   "set-cookies",
+		Short: "Crawl a channel with cookies from a browser login.",
+		Long: "Use when a channel's login hits a captcha or other challenge. Log in to the site in a browser, " +
+			"then enter its cookies as a Netscape cookies file or a Cookie header to resume crawling.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if (cookies == "") == (cookiesFile == "") {
+				return errors.New("must enter either cookies or a cookies file")
+			}
 
 			key, val, err := getChanKeyVal(id, name, url)
 			if err != nil {
 				return err
 			}
 
-			// Files/dirs:
-			if vDir != "" { // Do not stat, due to templating
-				if err := cs.UpdateChannelEntry(key, val, consts.QChanVideoDir, vDir); err != nil {
-					return fmt.Errorf("failed to update video directory: %w", err)
+			if cookiesFile != "" {
+				b, err := os.ReadFile(cookiesFile)
+				if err != nil {
+					return err
 				}
-				logging.S(0, "Updated video directory to %q", vDir)
+				cookies = string(b)
 			}
+			viper.Set(keys.AuthCookies, cookies)
 
-			if jDir != "" { // Do not stat, due to templating
-				if err := cs.UpdateChannelEntry(key, val, consts.QChanJSONDir, jDir); err != nil {
-					return fmt.Errorf("failed to update JSON directory: %w", err)
-				}
-				logging.S(0, "Updated JSON directory to %q", jDir)
+			if err := cs.CrawlChannel(key, val, s, ctx); err != nil {
+				return err
+			}
+			return nil
+		},
+	}
+
+	SetPrimaryChannelFlags(setCookiesCmd, &name, &url, &id)
+	setCookiesCmd.Flags().StringVar(&cookies, keys.AuthCookies, "", "Cookie header to crawl with (\"name=value; name2=value2\")")
+	setCookiesCmd.Flags().StringVar(&cookiesFile, keys.AuthCookiesFile, "", "Netscape cookies file or Cookie header to crawl with")
+
+	return setCookiesCmd
+}
+
+// setOwnerCmd gives a channel to a user of the HTTP endpoints.
+func setOwnerCmd(cs interfaces.ChannelStore, us interfaces.UserStore) *cobra.Command {
+	var (
+		url, name, owner string
+		id               int
+	)
+
+	setOwnerCmd := &cobra.Command{
+		Use:   "set-owner",
+		Short: "Set the user who owns a channel.",
+		Long: "Sets the user who can see and manage a channel over the HTTP endpoints. " +
+			"Channels without an owner are only visible to admins. Enter an empty owner to clear it.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed(keys.Owner) {
+				return fmt.Errorf("must enter --%s", keys.Owner)
 			}
 
-			if username != "" {
-				if err := cs.UpdateChannelEntry(key, val, consts.QChanUsername, username); err != nil {
-					return fmt.Errorf("failed to update username: %w", err)
+			chanID := int64(id)
+			if id == 0 {
+				key, val, err := getChanKeyVal(id, name, url)
+				if err != nil {
+					return err
+				}
+				if chanID, err = cs.GetID(key, val); err != nil {
+					return err
 				}
-				logging.S(0, "Updated username to %q", username)
 			}
 
-			if password != "" {
-				if err := cs.UpdateChannelEntry(key, val, consts.QChanPassword, password); err != nil {
-					return fmt.Errorf("failed to update password: %w", err)
-				}
-				logging.S(0, "Updated password for channel with key:value %q:%q", key, val)
+			if err := us.SetChannelOwner(chanID, owner); err != nil {
+				return err
+			}
+			if owner == "" {
+				logging.S(0, "Cleared owner of channel with ID %d", chanID)
+			} else {
+				logging.S(0, "Set owner of channel with ID %d to %q", chanID, owner)
 			}
+			return nil
+		},
+	}
 
-			if loginURL != "" {
-				if err := cs.UpdateChannelEntry(key, val, consts.QChanLoginURL, loginURL); err != nil {
-					return fmt.Errorf("failed to update login URL: %w", err)
-				}
-				logging.S(0, "Updated login URL to %q", loginURL)
+	SetPrimaryChannelFlags(setOwnerCmd, &name, &url, &id)
+	setOwnerCmd.Flags().StringVar(&owner, keys.Owner, "", "Username of the channel's owner, empty to clear it")
+
+	return setOwnerCmd
+}
+
+// importExistingCmd marks videos already in a directory as downloaded.
+func importExistingCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
+	var (
+		url, name, dir  string
+		id              int
+		offline, dryRun bool
+	)
+
+	importCmd := &cobra.Command{
+		Use:   "import-existing",
+		Short: "Mark videos already on disk as downloaded.",
+		Long: "Scans a directory of previously downloaded files and marks the channel's videos they match as finished, with their paths, so they aren't downloaded again. " +
+			"Files are matched by yt-dlp metadata JSON next to them (.info.json), by a video ID in the filename such as 'Title [id].mp4', or by title.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir == "" {
+				return errors.New("please enter the directory of existing files with --dir")
 			}
 
-			// Settings
-			fnSettingsArgs, err := getSettingsArgFns(chanSettings{
-				cookieSource:           cookieSource,
-				crawlFreq:              crawlFreq,
-				retries:                retries,
-				filters:                dlFilters,
-				externalDownloader:     externalDownloader,
-				externalDownloaderArgs: externalDownloaderArgs,
-				concurrency:            concurrency,
-				maxFilesize:            maxFilesize,
-			})
+			key, val, err := getChanKeyVal(id, name, url)
 			if err != nil {
 				return err
 			}
 
-			if len(fnSettingsArgs) > 0 {
-				for _, fn := range fnSettingsArgs {
-					if _, err := cs.UpdateChannelSettingsJSON(key, val, fn); err != nil {
-						return err
-					}
-				}
+			return cs.ImportExistingVideos(key, val, dir, offline, dryRun, s, ctx)
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(importCmd, &name, &url, &id)
+
+	importCmd.Flags().StringVar(&dir, "dir", "", "Directory of existing video files, searched recursively")
+	importCmd.Flags().BoolVar(&offline, "offline", false, "Match against videos already recorded for the channel only, without listing its remote videos")
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the matches without marking any videos as downloaded")
+
+	return importCmd
+}
+
+// verifyCompleteCmd checks the channel's remote videos against those recorded by Tubarr.
+func verifyCompleteCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
+	var (
+		url, name string
+		id        int
+		enqueue   bool
+	)
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify-complete",
+		Short: "List remote videos never seen by Tubarr.",
+		Long:  "Enumerates all remote videos for the channel and lists those with no downloaded, ignored, or other recorded entry. Use --enqueue to download them.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfgflags.CheckReadOnlyFlag(cmd, "enqueue"); err != nil {
+				return err
 			}
 
-			fnMetarrArray, err := getMetarrArgFns(cobraMetarrArgs{
-				filenameReplaceSfx: fileSfxReplace,
-				renameStyle:        renameStyle,
-				fileDatePfx:        filenameDateTag,
-				metarrExt:          metarrExt,
-				metaOps:            metaOps,
-				outputDir:          outDir,
-				concurrency:        metarrConcurrency,
-				maxCPU:             maxCPU,
-				minFreeMem:         minFreeMem,
-			})
+			key, val, err := getChanKeyVal(id, name, url)
 			if err != nil {
 				return err
 			}
 
-			if len(fnMetarrArray) > 0 {
-				for _, fn := range fnMetarrArray {
-					if _, err := cs.UpdateChannelMetarrArgsJSON(key, val, fn); err != nil {
-						return err
+			return cs.VerifyChannelComplete(key, val, enqueue, s, ctx)
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(verifyCmd, &name, &url, &id)
+
+	verifyCmd.Flags().BoolVar(&enqueue, "enqueue", false, "Download the missing videos")
+
+	return verifyCmd
+}
+
+// updateChannelSettingsCmd updates channel settings.
+func updateChannelSettingsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		id, concurrency, crawlFreq, metarrConcurrency, retries  int
+		fragments, connections, jitter, retryMaxAttempts        int
+		maxPerCrawl, keepLast, keepDays, liveCheckFreq          int
+		minViews                                                int
+		maxCPU                                                  float64
+		vDir, jDir, outDir                                      string
+		name, url, cookieSource                                 string
+		minFreeMem, renameStyle, filenameDateTag, metarrExt     string
+		maxFilesize, externalDownloader, externalDownloaderArgs string
+		username, password, loginURL, ytdlpExtraArgs            string
+		playlistMatch, crawlCron, quietHours, maxRate           string
+		fetcher, sponsorBlockRemove, sponsorBlockMark           string
+		postsURL, maxTotalSize, liveURL, tag                    string
+		stagingDir, fallbackProxy, fallbackCookieSource         string
+		authMethod, minDuration, maxDuration                    string
+		maxResolution, preferredCodec                           string
+		nfoEpisodeTemplate, nfoShowTemplate, nfoMapFile         string
+		outputTemplate                                          string
+		dlFilters, metaOps, nfoFieldMap                         []string
+		fileSfxReplace, urlAllow, urlBlock, blackoutDates       []string
+		externalIDs, proxies, fetcherRules, sidecars            []string
+		postProcessors                                          []string
+		transcribeModel, transcribeLang                         string
+		skipMetarr, diagnostics, quotaPrune, retentionNotify    bool
+		audioOnly, writeNFO, transcribe                         bool
+	)
+
+	// updateSettings applies the flags set to the channel matching the key and value.
+	updateSettings := func(cmd *cobra.Command, key, val string) error {
+		// Files/dirs:
+		if vDir != "" { // Do not stat, due to templating
+			if err := cs.UpdateChannelEntry(key, val, consts.QChanVideoDir, vDir); err != nil {
+				return fmt.Errorf("failed to update video directory: %w", err)
+			}
+			logging.S(0, "Updated video directory to %q", vDir)
+		}
+
+		if jDir != "" { // Do not stat, due to templating
+			if err := cs.UpdateChannelEntry(key, val, consts.QChanJSONDir, jDir); err != nil {
+				return fmt.Errorf("failed to update JSON directory: %w", err)
+			}
+			logging.S(0, "Updated JSON directory to %q", jDir)
+		}
+
+		if username != "" {
+			if err := cs.UpdateChannelEntry(key, val, consts.QChanUsername, username); err != nil {
+				return fmt.Errorf("failed to update username: %w", err)
+			}
+			logging.S(0, "Updated username to %q", username)
+		}
+
+		if password != "" {
+			if err := cs.UpdateChannelEntry(key, val, consts.QChanPassword, password); err != nil {
+				return fmt.Errorf("failed to update password: %w", err)
+			}
+			logging.S(0, "Updated password for channel with key:value %q:%q", key, val)
+		}
+
+		if loginURL != "" {
+			if err := cs.UpdateChannelEntry(key, val, consts.QChanLoginURL, loginURL); err != nil {
+				return fmt.Errorf("failed to update login URL: %w", err)
+			}
+			logging.S(0, "Updated login URL to %q", loginURL)
+		}
+
+		// Settings
+		settings := chanSettings{
+			cookieSource:           cookieSource,
+			crawlFreq:              crawlFreq,
+			retries:                retries,
+			filters:                dlFilters,
+			externalDownloader:     externalDownloader,
+			externalDownloaderArgs: externalDownloaderArgs,
+			concurrency:            concurrency,
+			maxFilesize:            maxFilesize,
+			urlAllow:               urlAllow,
+			urlBlock:               urlBlock,
+			ytdlpExtraArgs:         ytdlpExtraArgs,
+			playlistMatch:          playlistMatch,
+			blackoutDates:          blackoutDates,
+			crawlCron:              crawlCron,
+			quietHours:             quietHours,
+			externalIDs:            externalIDs,
+			maxRate:                maxRate,
+			fetcher:                fetcher,
+			fetcherRules:           fetcherRules,
+			sponsorBlockRemove:     sponsorBlockRemove,
+			sponsorBlockMark:       sponsorBlockMark,
+			postsURL:               postsURL,
+			maxTotalSize:           maxTotalSize,
+			proxies:                proxies,
+			liveURL:                liveURL,
+			stagingDir:             stagingDir,
+			fallbackProxy:          fallbackProxy,
+			fallbackCookieSource:   fallbackCookieSource,
+			authMethod:             authMethod,
+			minDuration:            minDuration,
+			maxDuration:            maxDuration,
+			maxResolution:          maxResolution,
+			preferredCodec:         preferredCodec,
+			sidecars:               sidecars,
+			nfoEpisodeTemplate:     nfoEpisodeTemplate,
+			nfoShowTemplate:        nfoShowTemplate,
+			nfoFieldMap:            nfoFieldMap,
+			nfoFieldMapFile:        nfoMapFile,
+			outputTemplate:         outputTemplate,
+			postProcessors:         postProcessors,
+			transcribeModel:        transcribeModel,
+			transcribeLanguage:     transcribeLang,
+		}
+		if cmd.Flags().Changed(keys.CrawlJitter) {
+			settings.jitter = &jitter
+		}
+		if cmd.Flags().Changed(keys.ConcurrentFragments) {
+			settings.fragments = &fragments
+		}
+		if cmd.Flags().Changed(keys.ExternalDLConnections) {
+			settings.connections = &connections
+		}
+		if cmd.Flags().Changed(keys.RetryMaxAttempts) {
+			settings.retryMaxAttempts = &retryMaxAttempts
+		}
+		if cmd.Flags().Changed(keys.MaxDownloadsPerCrawl) {
+			settings.maxPerCrawl = &maxPerCrawl
+		}
+		if cmd.Flags().Changed(keys.MinViews) {
+			settings.minViews = &minViews
+		}
+		if cmd.Flags().Changed(keys.AudioOnly) {
+			settings.audioOnly = &audioOnly
+		}
+		if cmd.Flags().Changed(keys.NFO) {
+			settings.nfo = &writeNFO
+		}
+		if cmd.Flags().Changed(keys.Transcribe) {
+			settings.transcribe = &transcribe
+		}
+		if cmd.Flags().Changed(keys.SkipMetarr) {
+			settings.skipMetarr = &skipMetarr
+		}
+		if cmd.Flags().Changed(keys.ExtractorDiagnostics) {
+			settings.diagnostics = &diagnostics
+		}
+		if cmd.Flags().Changed(keys.QuotaPrune) {
+			settings.quotaPrune = &quotaPrune
+		}
+		if cmd.Flags().Changed(keys.KeepLast) {
+			settings.keepLast = &keepLast
+		}
+		if cmd.Flags().Changed(keys.KeepDays) {
+			settings.keepDays = &keepDays
+		}
+		if cmd.Flags().Changed(keys.RetentionNotify) {
+			settings.retentionNotify = &retentionNotify
+		}
+		if cmd.Flags().Changed(keys.LiveCheckFreq) {
+			settings.liveCheckFreq = &liveCheckFreq
+		}
+
+		fnSettingsArgs, err := getSettingsArgFns(settings)
+		if err != nil {
+			return err
+		}
+
+		if len(fnSettingsArgs) > 0 {
+			for _, fn := range fnSettingsArgs {
+				if _, err := cs.UpdateChannelSettingsJSON(key, val, fn); err != nil {
+					return err
+				}
+			}
+		}
+
+		fnMetarrArray, err := getMetarrArgFns(cobraMetarrArgs{
+			filenameReplaceSfx: fileSfxReplace,
+			renameStyle:        renameStyle,
+			fileDatePfx:        filenameDateTag,
+			metarrExt:          metarrExt,
+			metaOps:            metaOps,
+			outputDir:          outDir,
+			concurrency:        metarrConcurrency,
+			maxCPU:             maxCPU,
+			minFreeMem:         minFreeMem,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(fnMetarrArray) > 0 {
+			for _, fn := range fnMetarrArray {
+				if _, err := cs.UpdateChannelMetarrArgsJSON(key, val, fn); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	updateSettingsCmd := &cobra.Command{
+		Use:   "update-settings",
+		Short: "Update channel settings.",
+		Long: "Update channel settings with various parameters, both for Tubarr itself and for external software like Metarr. " +
+			"Use --tag instead of a channel to update every channel in a group.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if tag != "" {
+				chans, err := groupChannels(cs, tag)
+				if err != nil {
+					return err
+				}
+				for _, c := range chans {
+					logging.I("Updating settings for channel %q", c.Name)
+					if err := updateSettings(cmd, consts.QChanID, strconv.FormatInt(c.ID, 10)); err != nil {
+						return fmt.Errorf("failed to update channel %q: %w", c.Name, err)
 					}
 				}
+				return nil
 			}
-			return nil
+
+			key, val, err := getChanKeyVal(id, name, url)
+			if err != nil {
+				return err
+			}
+			return updateSettings(cmd, key, val)
 		},
 	}
 
 	// Primary channel elements
 	SetPrimaryChannelFlags(updateSettingsCmd, &name, &url, &id)
+	updateSettingsCmd.Flags().StringVar(&tag, keys.ChannelTag, "", "Update every channel with this tag instead of a single channel")
 
 	// Files/dirs
 	cfgflags.SetFileDirFlags(updateSettingsCmd, &jDir, &vDir)
+	cfgflags.SetStagingDirFlag(updateSettingsCmd, &stagingDir)
 
 	// Program related
 	cfgflags.SetProgramRelatedFlags(updateSettingsCmd, &concurrency, &crawlFreq, &externalDownloaderArgs, &externalDownloader)
+	cfgflags.SetScheduleFlags(updateSettingsCmd, &crawlCron, &quietHours, &jitter)
 
 	// Download
 	cfgflags.SetDownloadFlags(updateSettingsCmd, &retries, &cookieSource, &maxFilesize, &dlFilters)
+	cfgflags.SetRetryMaxAttemptsFlag(updateSettingsCmd, &retryMaxAttempts)
+	cfgflags.SetMaxDownloadsPerCrawlFlag(updateSettingsCmd, &maxPerCrawl)
+	cfgflags.SetMetadataLimitFlags(updateSettingsCmd, &minDuration, &maxDuration, &minViews)
+	cfgflags.SetFormatFlags(updateSettingsCmd, &maxResolution, &preferredCodec, &audioOnly)
+	cfgflags.SetSidecarsFlag(updateSettingsCmd, &sidecars)
+	cfgflags.SetNFOFlags(updateSettingsCmd, &writeNFO, &nfoEpisodeTemplate, &nfoShowTemplate, &nfoFieldMap, &nfoMapFile)
+	cfgflags.SetOutputTemplateFlag(updateSettingsCmd, &outputTemplate)
+	cfgflags.SetPostProcessorsFlag(updateSettingsCmd, &postProcessors)
+	cfgflags.SetTranscribeFlags(updateSettingsCmd, &transcribe, &transcribeModel, &transcribeLang)
+	cfgflags.SetMaxRateFlag(updateSettingsCmd, &maxRate)
+	cfgflags.SetFetcherFlags(updateSettingsCmd, &fetcher, &fetcherRules)
+	cfgflags.SetSponsorBlockFlags(updateSettingsCmd, &sponsorBlockRemove, &sponsorBlockMark)
+	cfgflags.SetPostsURLFlag(updateSettingsCmd, &postsURL)
+	cfgflags.SetQuotaFlags(updateSettingsCmd, &maxTotalSize, &quotaPrune)
+	cfgflags.SetRetentionFlags(updateSettingsCmd, &keepLast, &keepDays, &retentionNotify)
+	cfgflags.SetProxyFlag(updateSettingsCmd, &proxies)
+	cfgflags.SetFallbackFlags(updateSettingsCmd, &fallbackProxy, &fallbackCookieSource)
+	cfgflags.SetLiveFlags(updateSettingsCmd, &liveURL, &liveCheckFreq)
+	cfgflags.SetURLPatternFlags(updateSettingsCmd, &urlAllow, &urlBlock)
+	cfgflags.SetYTDLPExtraArgsFlag(updateSettingsCmd, &ytdlpExtraArgs)
+	cfgflags.SetConnectionFlags(updateSettingsCmd, &fragments, &connections)
+	cfgflags.SetPlaylistMatchFlag(updateSettingsCmd, &playlistMatch)
+	cfgflags.SetBlackoutDatesFlag(updateSettingsCmd, &blackoutDates)
 
 	// Metarr
 	cfgflags.SetMetarrFlags(updateSettingsCmd, &maxCPU, &metarrConcurrency, &metarrExt, &filenameDateTag, &minFreeMem, &outDir, &renameStyle, &fileSfxReplace, &metaOps)
+	cfgflags.SetSkipMetarrFlag(updateSettingsCmd, &skipMetarr)
+	cfgflags.SetExtractorDiagnosticsFlag(updateSettingsCmd, &diagnostics)
+	cfgflags.SetExternalIDsFlag(updateSettingsCmd, &externalIDs)
 
 	// Auth
-	cfgflags.SetAuthFlags(updateSettingsCmd, &username, &password, &loginURL)
+	cfgflags.SetAuthFlags(updateSettingsCmd, &username, &password, &loginURL, &authMethod)
 
 	return updateSettingsCmd
 }
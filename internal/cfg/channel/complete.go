@@ -0,0 +1,51 @@
+package cfgchannel
+
+import (
+	"strings"
+
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// RegisterChannelCompletions completes the channel name and URL flags of every command under root from the database.
+func RegisterChannelCompletions(root *cobra.Command, cs interfaces.ChannelStore) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			kind, ok := f.Annotations[completeChannelAnnotation]
+			if !ok || len(kind) == 0 {
+				return
+			}
+			_ = cmd.RegisterFlagCompletionFunc(f.Name, channelCompletion(cs, kind[0] == keys.URL))
+		})
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+// channelCompletion returns a completion function listing channel names, or URLs, starting with the typed prefix.
+func channelCompletion(cs interfaces.ChannelStore, urls bool) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		chans, err, hasRows := cs.FetchAllChannels()
+		if err != nil || !hasRows {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions := make([]string, 0, len(chans))
+		for _, c := range chans {
+			val, desc := c.Name, c.URL
+			if urls {
+				val, desc = c.URL, c.Name
+			}
+			if strings.HasPrefix(strings.ToLower(val), strings.ToLower(toComplete)) {
+				completions = append(completions, val+"\t"+desc)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
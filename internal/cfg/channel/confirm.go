@@ -0,0 +1,44 @@
+package cfgchannel
+
+import (
+	"fmt"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	confirmFlag = "confirm"
+	confirmTTL  = 5 * time.Minute
+)
+
+// SetConfirmFlag sets the confirmation token flag for destructive commands.
+func SetConfirmFlag(cmd *cobra.Command, token *string) {
+	cmd.Flags().StringVar(token, confirmFlag, "", "Confirmation token printed by a previous run of this command")
+}
+
+// ConfirmDestructive requires a second run with a confirmation token before a destructive operation.
+//
+// Without a token, it prints what will be destroyed along with a new token and returns false.
+// The token is only valid for the same operation, once, within a few minutes.
+func ConfirmDestructive(fs interfaces.ConfirmStore, operation, summary, token string) (confirmed bool, err error) {
+	if token != "" {
+		if _, err := fs.ConsumeConfirmation(token, operation); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	token, err = fs.IssueConfirmation(operation, summary, confirmTTL)
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Printf("\n%sThis will %s.%s\n\n", consts.ColorYellow, summary, consts.ColorReset)
+	logging.I("Run the command again with '--%s %s' within %v to proceed", confirmFlag, token, confirmTTL)
+	return false, nil
+}
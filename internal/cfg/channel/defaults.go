@@ -0,0 +1,263 @@
+package cfgchannel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitDefaultsCmds is the entrypoint for initializing commands managing the defaults profile.
+func InitDefaultsCmds(cs interfaces.ChannelStore) *cobra.Command {
+	defaultsCmd := &cobra.Command{
+		Use:   "defaults",
+		Short: "Default channel settings.",
+		Long: "Manage the defaults profile, whose settings and Metarr arguments are inherited by every channel which doesn't set them itself. " +
+			"A channel setting left empty, 0, or false inherits the default.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	defaultsCmd.AddCommand(setDefaultsCmd(cs))
+	defaultsCmd.AddCommand(cfgflags.MarkReadOnlySafe(showDefaultsCmd(cs)))
+	defaultsCmd.AddCommand(resetDefaultsCmd(cs))
+	return defaultsCmd
+}
+
+// setDefaultsCmd updates the defaults profile.
+func setDefaultsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		concurrency, crawlFreq, metarrConcurrency, retries      int
+		fragments, connections, jitter, retryMaxAttempts        int
+		maxPerCrawl, keepLast, keepDays, minViews               int
+		maxCPU                                                  float64
+		outDir, cookieSource, stagingDir                        string
+		fallbackProxy, fallbackCookieSource                     string
+		minFreeMem, renameStyle, filenameDateTag, metarrExt     string
+		maxFilesize, externalDownloader, externalDownloaderArgs string
+		ytdlpExtraArgs, playlistMatch, crawlCron, quietHours    string
+		maxRate, fetcher, sponsorBlockRemove, sponsorBlockMark  string
+		maxTotalSize, minDuration, maxDuration                  string
+		maxResolution, preferredCodec                           string
+		nfoEpisodeTemplate, nfoShowTemplate, nfoMapFile         string
+		outputTemplate                                          string
+		dlFilters, metaOps, fileSfxReplace, urlAllow, urlBlock  []string
+		nfoFieldMap                                             []string
+		blackoutDates, proxies, fetcherRules, sidecars          []string
+		postProcessors                                          []string
+		transcribeModel, transcribeLang                         string
+		skipMetarr, diagnostics, quotaPrune, retentionNotify    bool
+		audioOnly, writeNFO, transcribe                         bool
+	)
+
+	setCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set default channel settings.",
+		Long:  "Sets settings in the defaults profile. Takes the same settings flags as 'channel update-settings', except those specific to one channel.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings := chanSettings{
+				cookieSource:           cookieSource,
+				retries:                retries,
+				filters:                dlFilters,
+				externalDownloader:     externalDownloader,
+				externalDownloaderArgs: externalDownloaderArgs,
+				concurrency:            concurrency,
+				maxFilesize:            maxFilesize,
+				urlAllow:               urlAllow,
+				urlBlock:               urlBlock,
+				ytdlpExtraArgs:         ytdlpExtraArgs,
+				playlistMatch:          playlistMatch,
+				blackoutDates:          blackoutDates,
+				crawlCron:              crawlCron,
+				quietHours:             quietHours,
+				maxRate:                maxRate,
+				fetcher:                fetcher,
+				fetcherRules:           fetcherRules,
+				sponsorBlockRemove:     sponsorBlockRemove,
+				sponsorBlockMark:       sponsorBlockMark,
+				maxTotalSize:           maxTotalSize,
+				proxies:                proxies,
+				stagingDir:             stagingDir,
+				fallbackProxy:          fallbackProxy,
+				fallbackCookieSource:   fallbackCookieSource,
+				minDuration:            minDuration,
+				maxDuration:            maxDuration,
+				maxResolution:          maxResolution,
+				preferredCodec:         preferredCodec,
+				sidecars:               sidecars,
+				nfoEpisodeTemplate:     nfoEpisodeTemplate,
+				nfoShowTemplate:        nfoShowTemplate,
+				nfoFieldMap:            nfoFieldMap,
+				nfoFieldMapFile:        nfoMapFile,
+				outputTemplate:         outputTemplate,
+				postProcessors:         postProcessors,
+				transcribeModel:        transcribeModel,
+				transcribeLanguage:     transcribeLang,
+			}
+			if cmd.Flags().Changed(keys.CrawlFreq) { // Flag defaults to 30, only a default if entered
+				settings.crawlFreq = crawlFreq
+			}
+			if cmd.Flags().Changed(keys.CrawlJitter) {
+				settings.jitter = &jitter
+			}
+			if cmd.Flags().Changed(keys.ConcurrentFragments) {
+				settings.fragments = &fragments
+			}
+			if cmd.Flags().Changed(keys.ExternalDLConnections) {
+				settings.connections = &connections
+			}
+			if cmd.Flags().Changed(keys.RetryMaxAttempts) {
+				settings.retryMaxAttempts = &retryMaxAttempts
+			}
+			if cmd.Flags().Changed(keys.MaxDownloadsPerCrawl) {
+				settings.maxPerCrawl = &maxPerCrawl
+			}
+			if cmd.Flags().Changed(keys.MinViews) {
+				settings.minViews = &minViews
+			}
+			if cmd.Flags().Changed(keys.AudioOnly) {
+				settings.audioOnly = &audioOnly
+			}
+			if cmd.Flags().Changed(keys.NFO) {
+				settings.nfo = &writeNFO
+			}
+			if cmd.Flags().Changed(keys.Transcribe) {
+				settings.transcribe = &transcribe
+			}
+			if cmd.Flags().Changed(keys.SkipMetarr) {
+				settings.skipMetarr = &skipMetarr
+			}
+			if cmd.Flags().Changed(keys.ExtractorDiagnostics) {
+				settings.diagnostics = &diagnostics
+			}
+			if cmd.Flags().Changed(keys.QuotaPrune) {
+				settings.quotaPrune = &quotaPrune
+			}
+			if cmd.Flags().Changed(keys.KeepLast) {
+				settings.keepLast = &keepLast
+			}
+			if cmd.Flags().Changed(keys.KeepDays) {
+				settings.keepDays = &keepDays
+			}
+			if cmd.Flags().Changed(keys.RetentionNotify) {
+				settings.retentionNotify = &retentionNotify
+			}
+
+			fnSettingsArgs, err := getSettingsArgFns(settings)
+			if err != nil {
+				return err
+			}
+			fnMetarrArray, err := getMetarrArgFns(cobraMetarrArgs{
+				filenameReplaceSfx: fileSfxReplace,
+				renameStyle:        renameStyle,
+				fileDatePfx:        filenameDateTag,
+				metarrExt:          metarrExt,
+				metaOps:            metaOps,
+				outputDir:          outDir,
+				concurrency:        metarrConcurrency,
+				maxCPU:             maxCPU,
+				minFreeMem:         minFreeMem,
+			})
+			if err != nil {
+				return err
+			}
+			if len(fnSettingsArgs) == 0 && len(fnMetarrArray) == 0 {
+				return errors.New("no settings were entered, use --help to see available settings")
+			}
+
+			for _, fn := range fnSettingsArgs {
+				if err := cs.UpdateDefaultSettingsJSON(fn); err != nil {
+					return err
+				}
+			}
+			for _, fn := range fnMetarrArray {
+				if err := cs.UpdateDefaultMetarrArgsJSON(fn); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	// Files/dirs
+	cfgflags.SetStagingDirFlag(setCmd, &stagingDir)
+
+	// Program related
+	cfgflags.SetProgramRelatedFlags(setCmd, &concurrency, &crawlFreq, &externalDownloaderArgs, &externalDownloader)
+	cfgflags.SetScheduleFlags(setCmd, &crawlCron, &quietHours, &jitter)
+
+	// Download
+	cfgflags.SetDownloadFlags(setCmd, &retries, &cookieSource, &maxFilesize, &dlFilters)
+	cfgflags.SetRetryMaxAttemptsFlag(setCmd, &retryMaxAttempts)
+	cfgflags.SetMaxDownloadsPerCrawlFlag(setCmd, &maxPerCrawl)
+	cfgflags.SetMetadataLimitFlags(setCmd, &minDuration, &maxDuration, &minViews)
+	cfgflags.SetFormatFlags(setCmd, &maxResolution, &preferredCodec, &audioOnly)
+	cfgflags.SetSidecarsFlag(setCmd, &sidecars)
+	cfgflags.SetNFOFlags(setCmd, &writeNFO, &nfoEpisodeTemplate, &nfoShowTemplate, &nfoFieldMap, &nfoMapFile)
+	cfgflags.SetOutputTemplateFlag(setCmd, &outputTemplate)
+	cfgflags.SetPostProcessorsFlag(setCmd, &postProcessors)
+	cfgflags.SetTranscribeFlags(setCmd, &transcribe, &transcribeModel, &transcribeLang)
+	cfgflags.SetMaxRateFlag(setCmd, &maxRate)
+	cfgflags.SetFetcherFlags(setCmd, &fetcher, &fetcherRules)
+	cfgflags.SetSponsorBlockFlags(setCmd, &sponsorBlockRemove, &sponsorBlockMark)
+	cfgflags.SetQuotaFlags(setCmd, &maxTotalSize, &quotaPrune)
+	cfgflags.SetRetentionFlags(setCmd, &keepLast, &keepDays, &retentionNotify)
+	cfgflags.SetProxyFlag(setCmd, &proxies)
+	cfgflags.SetFallbackFlags(setCmd, &fallbackProxy, &fallbackCookieSource)
+	cfgflags.SetURLPatternFlags(setCmd, &urlAllow, &urlBlock)
+	cfgflags.SetYTDLPExtraArgsFlag(setCmd, &ytdlpExtraArgs)
+	cfgflags.SetConnectionFlags(setCmd, &fragments, &connections)
+	cfgflags.SetPlaylistMatchFlag(setCmd, &playlistMatch)
+	cfgflags.SetBlackoutDatesFlag(setCmd, &blackoutDates)
+
+	// Metarr
+	cfgflags.SetMetarrFlags(setCmd, &maxCPU, &metarrConcurrency, &metarrExt, &filenameDateTag, &minFreeMem, &outDir, &renameStyle, &fileSfxReplace, &metaOps)
+	cfgflags.SetSkipMetarrFlag(setCmd, &skipMetarr)
+	cfgflags.SetExtractorDiagnosticsFlag(setCmd, &diagnostics)
+
+	return setCmd
+}
+
+// showDefaultsCmd prints the defaults profile.
+func showDefaultsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show default channel settings.",
+		Long:  "Prints the defaults profile as JSON. Settings which are empty, 0, or false are unset and not inherited.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, err := cs.GetDefaults()
+			if err != nil {
+				return err
+			}
+			b, err := json.MarshalIndent(d, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal defaults: %w", err)
+			}
+			fmt.Println(string(b))
+			return nil
+		},
+	}
+}
+
+// resetDefaultsCmd clears the defaults profile.
+func resetDefaultsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset",
+		Short: "Clear default channel settings.",
+		Long:  "Clears every setting in the defaults profile, so channels only use their own settings.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cs.ResetDefaults(); err != nil {
+				return err
+			}
+			logging.S(0, "Cleared default channel settings")
+			return nil
+		},
+	}
+}
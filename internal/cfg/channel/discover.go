@@ -0,0 +1,292 @@
+package cfgchannel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	youTubeChannelURL = "https://www.youtube.com/channel/"
+)
+
+// subscription is a channel read from a subscriptions export.
+type subscription struct {
+	Name string
+	URL  string
+}
+
+// opmlOutline is an outline element of an OPML file, nested to any depth.
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr"`
+	XMLURL   string        `xml:"xmlUrl,attr"`
+	HTMLURL  string        `xml:"htmlUrl,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// discoverCmd adds channels from a subscriptions export.
+func discoverCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		file, vDir, jDir, preset string
+		crawlFreq                int
+		yes                      bool
+	)
+
+	discoverCmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Add channels from a subscriptions export.",
+		Long: "Reads a YouTube subscriptions export, either the subscriptions.csv from Google Takeout or an OPML file, and asks whether to add each channel. " +
+			"Use --yes to add every channel without asking. Channels whose name or URL already exist are skipped. " +
+			"Use a templated video directory such as '/videos/{{channel_name}}' to keep each channel's videos apart.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case file == "":
+				return errors.New("please enter the subscriptions export with --from-subscriptions")
+			case vDir == "":
+				return errors.New("please enter a video directory with --video-directory, such as '/videos/{{channel_name}}'")
+			}
+
+			subs, err := readSubscriptions(file)
+			if err != nil {
+				return err
+			}
+			if len(subs) == 0 {
+				logging.I("No channels found in %q", file)
+				return nil
+			}
+
+			var p *models.Preset
+			if preset != "" {
+				if p, err = cs.GetPreset(preset); err != nil {
+					return err
+				}
+			}
+
+			var (
+				added, skipped int
+				errs           []error
+				scanner        = bufio.NewScanner(os.Stdin)
+			)
+			for i, sub := range subs {
+				if err := rejectCommandRefs(sub.Name, sub.URL); err != nil {
+					errs = append(errs, fmt.Errorf("channel %q: %w", sub.Name, err))
+					continue
+				}
+				exists, err := channelNameOrURLExists(cs, sub.Name, sub.URL)
+				if err != nil {
+					return err
+				}
+				if exists {
+					logging.D(1, "Skipping channel %q, a channel with this name or URL already exists", sub.Name)
+					skipped++
+					continue
+				}
+
+				if !yes {
+					fmt.Printf("[%d/%d] Add channel %q (%s)? [y/N/a(ll)/q(uit)]: ", i+1, len(subs), sub.Name, sub.URL)
+					if !scanner.Scan() {
+						fmt.Println()
+						break
+					}
+					answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+					if answer == "q" || answer == "quit" {
+						break
+					}
+					if answer == "a" || answer == "all" {
+						yes = true
+					} else if answer != "y" && answer != "yes" {
+						continue
+					}
+				}
+
+				now := time.Now()
+				c := &models.Channel{
+					URL:       sub.URL,
+					Name:      sub.Name,
+					VideoDir:  vDir,
+					JSONDir:   jDir,
+					Settings:  models.ChannelSettings{CrawlFreq: crawlFreq},
+					LastScan:  now,
+					CreatedAt: now,
+					UpdatedAt: now,
+				}
+				if p != nil {
+					if !cmd.Flags().Changed(keys.CrawlFreq) && p.Settings.CrawlFreq > 0 {
+						c.Settings.CrawlFreq = p.Settings.CrawlFreq
+					}
+					p.Apply(c)
+				}
+				if _, err := cs.AddChannel(c); err != nil {
+					errs = append(errs, fmt.Errorf("channel %q: %w", sub.Name, err))
+					continue
+				}
+				added++
+			}
+
+			logging.I("Added %d of %d channel(s) from %q, skipped %d existing", added, len(subs), file, skipped)
+			if len(errs) > 0 {
+				return fmt.Errorf("failed to add %d channel(s): %w", len(errs), errors.Join(errs...))
+			}
+			return nil
+		},
+	}
+
+	discoverCmd.Flags().StringVar(&file, "from-subscriptions", "", "YouTube subscriptions export, as Takeout CSV or OPML")
+	discoverCmd.Flags().StringVar(&preset, keys.Preset, "", "Settings preset for the added channels, see 'preset list'")
+	discoverCmd.Flags().BoolVar(&yes, "yes", false, "Add every channel in the export without asking")
+	discoverCmd.Flags().IntVar(&crawlFreq, keys.CrawlFreq, 30, "Crawl frequency in minutes of the added channels")
+	cfgflags.SetFileDirFlags(discoverCmd, &jDir, &vDir)
+
+	return discoverCmd
+}
+
+// readSubscriptions reads the channels in a subscriptions export, as OPML if it starts with a tag or else as CSV.
+func readSubscriptions(path string) ([]subscription, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subscriptions file: %w", err)
+	}
+	b = bytes.TrimPrefix(b, []byte("\ufeff"))
+
+	var subs []subscription
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("<")) {
+		subs, err = parseOPMLSubscriptions(b)
+	} else {
+		subs, err = parseCSVSubscriptions(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse subscriptions file %q: %w", path, err)
+	}
+
+	// Drop repeats
+	seen := make(map[string]struct{}, len(subs))
+	unique := subs[:0]
+	for _, s := range subs {
+		if _, ok := seen[s.URL]; ok {
+			continue
+		}
+		seen[s.URL] = struct{}{}
+		unique = append(unique, s)
+	}
+	return unique, nil
+}
+
+// parseCSVSubscriptions reads a Takeout subscriptions.csv, with "Channel Id,Channel Url,Channel Title" columns.
+//
+// Headers are translated in some locales, so columns are found by position when the header isn't recognized.
+func parseCSVSubscriptions(b []byte) ([]subscription, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	idCol, urlCol, titleCol := 0, 1, 2
+	var subs []subscription
+	for n := 0; ; n++ {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if n == 0 && !strings.HasPrefix(strings.TrimSpace(rec[0]), "UC") {
+			for i, h := range rec {
+				switch h = strings.ToLower(h); {
+				case strings.Contains(h, "id"):
+					idCol = i
+				case strings.Contains(h, "url"):
+					urlCol = i
+				case strings.Contains(h, "title"), strings.Contains(h, "name"):
+					titleCol = i
+				}
+			}
+			continue
+		}
+
+		field := func(i int) string {
+			if i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		sub := subscription{Name: field(titleCol), URL: channelURL(field(idCol), field(urlCol))}
+		if sub.URL == "" {
+			logging.W("Skipping subscriptions line %d with no channel ID or URL", n+1)
+			continue
+		}
+		if sub.Name == "" {
+			sub.Name = sub.URL
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// parseOPMLSubscriptions reads the feed outlines of an OPML file, such as YouTube's former subscriptions export.
+func parseOPMLSubscriptions(b []byte) ([]subscription, error) {
+	var doc struct {
+		Outlines []opmlOutline `xml:"body>outline"`
+	}
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	var (
+		subs []subscription
+		walk func([]opmlOutline)
+	)
+	walk = func(outlines []opmlOutline) {
+		for _, o := range outlines {
+			walk(o.Outlines)
+
+			var id string
+			if u, err := url.Parse(o.XMLURL); err == nil {
+				id = u.Query().Get("channel_id")
+			}
+			u := channelURL(id, o.HTMLURL)
+			if u == "" {
+				continue
+			}
+
+			name := o.Title
+			if name == "" {
+				name = o.Text
+			}
+			if name == "" {
+				name = u
+			}
+			subs = append(subs, subscription{Name: strings.TrimSpace(name), URL: u})
+		}
+	}
+	walk(doc.Outlines)
+	return subs, nil
+}
+
+// channelURL returns the YouTube channel URL for a channel ID, or else the listed URL over HTTPS.
+func channelURL(id, listed string) string {
+	if strings.HasPrefix(id, "UC") {
+		return youTubeChannelURL + id
+	}
+	if listed == "" {
+		return ""
+	}
+	return strings.Replace(listed, "http://", "https://", 1)
+}
@@ -0,0 +1,414 @@
+package cfgchannel
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tubarr/internal/auth"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/webhook"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	exportVersion = 1
+)
+
+// channelExport is the file format written by 'channel export'.
+//
+// Fields use JSON tags, YAML files are converted through JSON so settings keep their usual names.
+//
+// Channels hold their own settings, the settings they inherit are in the defaults profile.
+type channelExport struct {
+	Version  int             `json:"version"`
+	Defaults *exportDefaults `json:"defaults,omitempty"`
+	Channels []exportChannel `json:"channels"`
+}
+
+// exportDefaults is the defaults profile channels inherit unset settings from.
+type exportDefaults struct {
+	Settings   models.ChannelSettings `json:"settings"`
+	MetarrArgs models.MetarrArgs      `json:"metarr"`
+}
+
+// exportChannel is a channel with its notifications, passwords are never exported.
+type exportChannel struct {
+	Name          string                 `json:"name"`
+	URL           string                 `json:"url"`
+	VideoDir      string                 `json:"video_directory"`
+	JSONDir       string                 `json:"json_directory,omitempty"`
+	Settings      models.ChannelSettings `json:"settings"`
+	MetarrArgs    models.MetarrArgs      `json:"metarr"`
+	Username      string                 `json:"username,omitempty"`
+	LoginURL      string                 `json:"login_url,omitempty"`
+	Notifications []*models.Notification `json:"notifications,omitempty"`
+	Webhooks      []exportWebhook        `json:"webhooks,omitempty"`
+}
+
+// exportWebhook is a webhook without its database IDs.
+type exportWebhook struct {
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Payload string            `json:"payload,omitempty"`
+}
+
+// exportChannelsCmd writes channels and their notifications to a YAML or JSON file.
+func exportChannelsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		url, name, output string
+		id                int
+		all               bool
+	)
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export channels to a file.",
+		Long: "Writes channels with their settings, Metarr arguments, notification URLs, and webhooks to a YAML file (or JSON for a .json output), " +
+			"for backups or moving to another machine. Channels are exported with their own settings, and the defaults profile they inherit from is exported beside them. " +
+			"Passwords are not exported, webhook headers are exported as is.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var chans []*models.Channel
+			if all {
+				var (
+					err     error
+					hasRows bool
+				)
+				if chans, err, hasRows = cs.FetchAllChannels(); err != nil {
+					return err
+				} else if !hasRows {
+					return errors.New("no channels to export")
+				}
+			} else {
+				key, val, err := getChanKeyVal(id, name, url)
+				if err != nil {
+					return fmt.Errorf("%w, or use --all", err)
+				}
+				chanID, err := cs.GetID(key, val)
+				if err != nil {
+					return err
+				}
+				c, err, hasRows := cs.FetchChannel(chanID)
+				if err != nil {
+					return err
+				} else if !hasRows {
+					return fmt.Errorf("channel with ID %d not found", chanID)
+				}
+				chans = append(chans, c)
+			}
+
+			export := channelExport{Version: exportVersion}
+			d, err := cs.GetDefaults()
+			if err != nil {
+				return err
+			}
+			if !d.UpdatedAt.IsZero() {
+				export.Defaults = &exportDefaults{Settings: d.Settings, MetarrArgs: d.MetarrArgs}
+			}
+			for _, c := range chans {
+				ec, err := toExportChannel(cs, c)
+				if err != nil {
+					return err
+				}
+				export.Channels = append(export.Channels, ec)
+			}
+
+			b, err := encodeExport(&export, output)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				_, err = os.Stdout.Write(b)
+				return err
+			}
+			if err := os.WriteFile(output, b, 0o600); err != nil {
+				return fmt.Errorf("failed to write export file: %w", err)
+			}
+			logging.S(0, "Exported %d channel(s) to %q", len(export.Channels), output)
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(exportCmd, &name, &url, &id)
+
+	exportCmd.Flags().BoolVar(&all, "all", false, "Export every channel")
+	exportCmd.Flags().StringVar(&output, "output", "", "File to write, YAML unless it ends in .json (default stdout as YAML)")
+
+	return exportCmd
+}
+
+// importChannelsCmd adds the channels from an export file.
+func importChannelsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import channels from an export file.",
+		Long: "Adds the channels in a file written by 'channel export', with their notification URLs and webhooks. Channels whose name or URL already exist are skipped. " +
+			"The file's defaults profile is applied if no defaults are set here.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			export, err := decodeExport(args[0])
+			if err != nil {
+				return err
+			}
+			if export.Version > exportVersion {
+				return fmt.Errorf("export file version %d is newer than this build supports (%d)", export.Version, exportVersion)
+			}
+
+			if export.Defaults != nil {
+				if err := importDefaults(cs, export.Defaults); err != nil {
+					return err
+				}
+			}
+
+			var (
+				imported, skipped int
+				errs              []error
+			)
+			for i := range export.Channels {
+				ec := &export.Channels[i]
+				exists, err := channelNameOrURLExists(cs, ec.Name, ec.URL)
+				if err != nil {
+					return err
+				}
+				if exists {
+					logging.W("Skipping channel %q, a channel with this name or URL already exists", ec.Name)
+					skipped++
+					continue
+				}
+				if err := importChannel(cs, ec); err != nil {
+					errs = append(errs, fmt.Errorf("channel %q: %w", ec.Name, err))
+					continue
+				}
+				imported++
+			}
+
+			logging.I("Imported %d channel(s), skipped %d existing", imported, skipped)
+			if len(errs) > 0 {
+				return fmt.Errorf("failed to import %d channel(s): %w", len(errs), errors.Join(errs...))
+			}
+			return nil
+		},
+	}
+
+	return importCmd
+}
+
+// importDefaults applies an exported defaults profile, unless defaults are already set.
+func importDefaults(cs interfaces.ChannelStore, ed *exportDefaults) error {
+	d, err := cs.GetDefaults()
+	if err != nil {
+		return err
+	}
+	if !d.UpdatedAt.IsZero() {
+		logging.W("Skipping the file's defaults profile, defaults are already set here (see 'defaults show')")
+		return nil
+	}
+
+	if err := cs.UpdateDefaultSettingsJSON(func(s *models.ChannelSettings) error {
+		*s = ed.Settings
+		return nil
+	}); err != nil {
+		return err
+	}
+	return cs.UpdateDefaultMetarrArgsJSON(func(m *models.MetarrArgs) error {
+		*m = ed.MetarrArgs
+		return nil
+	})
+}
+
+// toExportChannel gathers a channel's own settings, notification URLs, and webhooks for export.
+//
+// Fetched channels have inherited the defaults, so the stored settings are exported instead. Otherwise
+// importing would pin every inherited setting to the channel.
+func toExportChannel(cs interfaces.ChannelStore, c *models.Channel) (exportChannel, error) {
+	settings, metarr, err := cs.GetStoredSettings(c.ID)
+	if err != nil {
+		return exportChannel{}, err
+	}
+	notifications, err := cs.GetNotifications(c.ID)
+	if err != nil {
+		return exportChannel{}, err
+	}
+	hooks, err := cs.GetWebhooks(c.ID)
+	if err != nil {
+		return exportChannel{}, err
+	}
+
+	ec := exportChannel{
+		Name:          c.Name,
+		URL:           c.URL,
+		VideoDir:      c.VideoDir,
+		JSONDir:       c.JSONDir,
+		Settings:      *settings,
+		MetarrArgs:    *metarr,
+		Username:      c.Username,
+		LoginURL:      c.LoginURL,
+		Notifications: notifications,
+	}
+	for _, h := range hooks {
+		ec.Webhooks = append(ec.Webhooks, exportWebhook{
+			Name:    h.Name,
+			URL:     h.URL,
+			Method:  h.Method,
+			Headers: h.Headers,
+			Payload: h.Payload,
+		})
+	}
+	return ec, nil
+}
+
+// importChannel adds an exported channel, then its authentication details, notification URLs, and webhooks.
+func importChannel(cs interfaces.ChannelStore, ec *exportChannel) error {
+	if ec.Name == "" {
+		return errors.New("channel name is blank")
+	}
+	if err := rejectCommandRefs(ec.Name, ec.URL, ec.Username, ec.LoginURL); err != nil {
+		return err
+	}
+	for _, h := range ec.Webhooks {
+		if err := webhook.Validate(h.Method, h.Payload); err != nil {
+			return fmt.Errorf("webhook %q: %w", h.Name, err)
+		}
+		for _, v := range h.Headers {
+			if err := rejectCommandRefs(v); err != nil {
+				return fmt.Errorf("webhook %q: %w", h.Name, err)
+			}
+		}
+	}
+
+	id, err := cs.AddChannel(&models.Channel{
+		Name:       ec.Name,
+		URL:        ec.URL,
+		VideoDir:   ec.VideoDir,
+		JSONDir:    ec.JSONDir,
+		Settings:   ec.Settings,
+		MetarrArgs: ec.MetarrArgs,
+	})
+	if err != nil {
+		return err
+	}
+
+	if ec.Username != "" {
+		if err := cs.AddAuth(id, ec.Username, "", ec.LoginURL); err != nil {
+			return err
+		}
+		logging.W("Channel %q was exported without its password, set it with 'channel auth'", ec.Name)
+	}
+
+	for _, n := range ec.Notifications {
+		if err := cs.AddNotifyURL(id, n.Name, n.URL); err != nil {
+			return err
+		}
+	}
+
+	for _, h := range ec.Webhooks {
+		if err := cs.AddWebhook(&models.Webhook{
+			ChannelID: id,
+			Name:      h.Name,
+			URL:       h.URL,
+			Method:    h.Method,
+			Headers:   h.Headers,
+			Payload:   h.Payload,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rejectCommandRefs refuses values from files which are "cmd:" secret references.
+//
+// Secret commands only run from local flags or the config file, an imported file must not pick one.
+func rejectCommandRefs(values ...string) error {
+	for _, v := range values {
+		if auth.IsCommandRef(v) {
+			return fmt.Errorf("value %q is a 'cmd:' secret reference, which can't be imported", v)
+		}
+	}
+	return nil
+}
+
+// channelNameOrURLExists reports whether a channel already uses the name or URL.
+func channelNameOrURLExists(cs interfaces.ChannelStore, name, url string) (bool, error) {
+	for key, val := range map[string]string{consts.QChanName: name, consts.QChanURL: url} {
+		if val == "" {
+			continue
+		}
+		if _, err := cs.GetID(key, val); err == nil {
+			return true, nil
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// encodeExport encodes the export as JSON for .json files, or YAML otherwise.
+func encodeExport(export *channelExport, path string) ([]byte, error) {
+	b, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode channels: %w", err)
+	}
+	if isJSONFile(path) {
+		return append(b, '\n'), nil
+	}
+
+	var doc any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode channels as YAML: %w", err)
+	}
+	return out, nil
+}
+
+// decodeExport reads an export file, as JSON for .json files or YAML otherwise.
+func decodeExport(path string) (*channelExport, error) {
+	var export channelExport
+	if err := decodeFile(path, "export", &export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// decodeFile reads a JSON or YAML file into v, converting YAML through JSON so fields keep their JSON names.
+func decodeFile(path, kind string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s file: %w", kind, err)
+	}
+
+	if !isJSONFile(path) {
+		var doc any
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return fmt.Errorf("failed to parse %s file %q: %w", kind, path, err)
+		}
+		if b, err = json.Marshal(doc); err != nil {
+			return fmt.Errorf("failed to convert %s file %q: %w", kind, path, err)
+		}
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to parse %s file %q: %w", kind, path, err)
+	}
+	return nil
+}
+
+// isJSONFile reports whether the path has a .json extension.
+func isJSONFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
@@ -6,15 +6,26 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// completeChannelAnnotation marks flags completed with the names or URLs of channels in the database.
+const completeChannelAnnotation = "tubarr_complete_channel"
+
 // SetPrimaryChannelFlags sets the main flags for channels in, or intended for, the database.
+//
+// Flags of channels already in the database, chosen by ID, complete with their names and URLs.
 func SetPrimaryChannelFlags(cmd *cobra.Command, name, url *string, id *int) {
 	if id != nil {
 		cmd.Flags().IntVarP(id, keys.ID, "i", 0, "Channel ID in the DB")
 	}
 	if name != nil {
 		cmd.Flags().StringVarP(name, keys.Name, "n", "", "Channel name")
+		if id != nil {
+			_ = cmd.Flags().SetAnnotation(keys.Name, completeChannelAnnotation, []string{keys.Name})
+		}
 	}
 	if url != nil {
 		cmd.Flags().StringVarP(url, keys.URL, "u", "", "Channel URL")
+		if id != nil {
+			_ = cmd.Flags().SetAnnotation(keys.URL, completeChannelAnnotation, []string{keys.URL})
+		}
 	}
 }
@@ -1,13 +1,20 @@
 package cfgchannel
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	cfgvalidate "tubarr/internal/cfg/validation"
 	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
 	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/plex"
 )
 
 type cobraMetarrArgs struct {
@@ -104,6 +111,56 @@ type chanSettings struct {
 	externalDownloaderArgs string
 	concurrency            int
 	maxFilesize            string
+	urlAllow               []string
+	urlBlock               []string
+	skipMetarr             *bool
+	ytdlpExtraArgs         string
+	fragments              *int
+	connections            *int
+	playlistMatch          string
+	diagnostics            *bool
+	blackoutDates          []string
+	crawlCron              string
+	quietHours             string
+	jitter                 *int
+	externalIDs            []string
+	retryMaxAttempts       *int
+	maxPerCrawl            *int
+	maxRate                string
+	fetcher                string
+	fetcherRules           []string
+	sponsorBlockRemove     string
+	sponsorBlockMark       string
+	postsURL               string
+	maxTotalSize           string
+	quotaPrune             *bool
+	keepLast               *int
+	keepDays               *int
+	retentionNotify        *bool
+	proxies                []string
+	liveURL                string
+	liveCheckFreq          *int
+	stagingDir             string
+	fallbackProxy          string
+	fallbackCookieSource   string
+	authMethod             string
+	minDuration            string
+	maxDuration            string
+	minViews               *int
+	maxResolution          string
+	preferredCodec         string
+	audioOnly              *bool
+	sidecars               []string
+	nfo                    *bool
+	nfoEpisodeTemplate     string
+	nfoShowTemplate        string
+	nfoFieldMap            []string
+	nfoFieldMapFile        string
+	outputTemplate         string
+	postProcessors         []string
+	transcribe             *bool
+	transcribeModel        string
+	transcribeLanguage     string
 }
 
 func getSettingsArgFns(c chanSettings) (fns []func(m *models.ChannelSettings) error, err error) {
@@ -129,8 +186,45 @@ func getSettingsArgFns(c chanSettings) (fns []func(m *models.ChannelSettings) er
 		})
 	}
 
+	if c.crawlCron != "" {
+		if err := cfgvalidate.ValidateSchedule(c.crawlCron, "", 0); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.CrawlCron = c.crawlCron
+			return nil
+		})
+	}
+
+	if c.quietHours != "" {
+		if err := cfgvalidate.ValidateSchedule("", c.quietHours, 0); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.QuietHours = c.quietHours
+			return nil
+		})
+	}
+
+	if c.jitter != nil {
+		jitter := *c.jitter
+		if err := cfgvalidate.ValidateSchedule("", "", jitter); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.CrawlJitter = jitter
+			return nil
+		})
+	}
+
 	if c.externalDownloader != "" {
 		fns = append(fns, func(s *models.ChannelSettings) error {
+			// Connections kept from before must still suit the new downloader
+			if c.connections == nil {
+				if err := cfgvalidate.ValidateExternalDLConnections(s.ExternalDLConnections, c.externalDownloader); err != nil {
+					return fmt.Errorf("%w (change or reset --%s along with the downloader)", err, keys.ExternalDLConnections)
+				}
+			}
 			s.ExternalDownloader = c.externalDownloader
 			return nil
 		})
@@ -162,7 +256,7 @@ func getSettingsArgFns(c chanSettings) (fns []func(m *models.ChannelSettings) er
 	}
 
 	if len(c.filters) > 0 {
-		dlFilters, err := verifyChannelOps(c.filters)
+		dlFilters, err := parsing.ParseFilters(c.filters)
 		if err != nil {
 			return nil, err
 		}
@@ -172,6 +266,441 @@ func getSettingsArgFns(c chanSettings) (fns []func(m *models.ChannelSettings) er
 		})
 	}
 
+	if len(c.urlAllow) > 0 {
+		urlAllow, err := cfgvalidate.ValidateURLPatterns(c.urlAllow)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.URLAllow = urlAllow
+			return nil
+		})
+	}
+
+	if len(c.urlBlock) > 0 {
+		urlBlock, err := cfgvalidate.ValidateURLPatterns(c.urlBlock)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.URLBlock = urlBlock
+			return nil
+		})
+	}
+
+	if len(c.blackoutDates) > 0 {
+		blackoutDates, err := cfgvalidate.ValidateBlackoutDates(c.blackoutDates)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.BlackoutDates = blackoutDates
+			return nil
+		})
+	}
+
+	if c.retryMaxAttempts != nil {
+		retryMaxAttempts := *c.retryMaxAttempts
+		if err := cfgvalidate.ValidateRetryMaxAttempts(retryMaxAttempts); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.RetryMaxAttempts = retryMaxAttempts
+			return nil
+		})
+	}
+
+	if c.maxPerCrawl != nil {
+		maxPerCrawl := *c.maxPerCrawl
+		if err := cfgvalidate.ValidateMaxDownloadsPerCrawl(maxPerCrawl); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.MaxDownloadsPerCrawl = maxPerCrawl
+			return nil
+		})
+	}
+
+	if c.maxRate != "" {
+		if err := cfgvalidate.ValidateMaxRate(c.maxRate); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.MaxRate = c.maxRate
+			return nil
+		})
+	}
+
+	if c.fetcher != "" {
+		if err := cfgvalidate.ValidateFetcher(c.fetcher); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.Fetcher = c.fetcher
+			return nil
+		})
+	}
+
+	if c.sponsorBlockRemove != "" {
+		if err := cfgvalidate.ValidateSponsorBlock(c.sponsorBlockRemove); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.SponsorBlockRemove = c.sponsorBlockRemove
+			return nil
+		})
+	}
+
+	if c.sponsorBlockMark != "" {
+		if err := cfgvalidate.ValidateSponsorBlock(c.sponsorBlockMark); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.SponsorBlockMark = c.sponsorBlockMark
+			return nil
+		})
+	}
+
+	if c.postsURL != "" {
+		if err := cfgvalidate.ValidatePostsURL(c.postsURL); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.PostsURL = c.postsURL
+			return nil
+		})
+	}
+
+	if c.maxTotalSize != "" {
+		if err := cfgvalidate.ValidateMaxTotalSize(c.maxTotalSize); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.MaxTotalSize = c.maxTotalSize
+			return nil
+		})
+	}
+
+	if len(c.fetcherRules) > 0 {
+		if err := cfgvalidate.ValidateFetcherRules(c.fetcherRules); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.FetcherRules = c.fetcherRules
+			return nil
+		})
+	}
+
+	if c.liveURL != "" {
+		if err := cfgvalidate.ValidateLive(c.liveURL, 0); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.LiveURL = c.liveURL
+			return nil
+		})
+	}
+
+	if c.liveCheckFreq != nil {
+		liveCheckFreq := *c.liveCheckFreq
+		if err := cfgvalidate.ValidateLive("", liveCheckFreq); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.LiveCheckFreq = liveCheckFreq
+			return nil
+		})
+	}
+
+	if c.minDuration != "" || c.maxDuration != "" || c.minViews != nil {
+		minViews := 0
+		if c.minViews != nil {
+			minViews = *c.minViews
+		}
+		if err := cfgvalidate.ValidateMetadataLimits(c.minDuration, c.maxDuration, minViews); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			if c.minDuration != "" {
+				s.MinDuration = c.minDuration
+			}
+			if c.maxDuration != "" {
+				s.MaxDuration = c.maxDuration
+			}
+			if c.minViews != nil {
+				s.MinViews = *c.minViews
+			}
+			// Checked again against the limits already set
+			return cfgvalidate.ValidateMetadataLimits(s.MinDuration, s.MaxDuration, s.MinViews)
+		})
+	}
+
+	if c.maxResolution != "" || c.preferredCodec != "" {
+		height, err := cfgvalidate.ValidateFormat(c.maxResolution, c.preferredCodec)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			if c.maxResolution != "" {
+				s.MaxResolution = height
+			}
+			switch c.preferredCodec {
+			case "":
+			case parsing.CodecAny:
+				s.PreferredCodec = ""
+			default:
+				s.PreferredCodec = c.preferredCodec
+			}
+			return nil
+		})
+	}
+
+	if c.audioOnly != nil {
+		audioOnly := *c.audioOnly
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.AudioOnly = audioOnly
+			return nil
+		})
+	}
+
+	if len(c.sidecars) > 0 {
+		sidecars, err := cfgvalidate.ValidateSidecars(c.sidecars)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.Sidecars = sidecars
+			return nil
+		})
+	}
+
+	if c.nfo != nil {
+		nfo := *c.nfo
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.NFO = nfo
+			return nil
+		})
+	}
+
+	if c.nfoEpisodeTemplate != "" || c.nfoShowTemplate != "" {
+		if err := cfgvalidate.ValidateNFOTemplates(c.nfoEpisodeTemplate, c.nfoShowTemplate); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			if c.nfoEpisodeTemplate != "" {
+				s.NFOEpisodeTemplate = c.nfoEpisodeTemplate
+			}
+			if c.nfoShowTemplate != "" {
+				s.NFOShowTemplate = c.nfoShowTemplate
+			}
+			return nil
+		})
+	}
+
+	if len(c.nfoFieldMap) > 0 || c.nfoFieldMapFile != "" {
+		nfoFields, err := cfgvalidate.ValidateNFOFieldMap(c.nfoFieldMap, c.nfoFieldMapFile)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.NFOFieldMap = nfoFields
+			return nil
+		})
+	}
+
+	if len(c.postProcessors) > 0 {
+		postProcessors, err := cfgvalidate.ValidatePostProcessors(c.postProcessors)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.PostProcessors = postProcessors
+			return nil
+		})
+	}
+
+	if c.transcribe != nil {
+		transcribe := *c.transcribe
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.Transcribe = transcribe
+			return nil
+		})
+	}
+
+	if c.transcribeModel != "" || c.transcribeLanguage != "" {
+		if err := cfgvalidate.ValidateTranscribe(c.transcribeModel, c.transcribeLanguage); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			if c.transcribeModel != "" {
+				s.TranscribeModel = c.transcribeModel
+			}
+			if c.transcribeLanguage != "" {
+				s.TranscribeLanguage = c.transcribeLanguage
+			}
+			return nil
+		})
+	}
+
+	if c.outputTemplate != "" {
+		if err := cfgvalidate.ValidateOutputTemplate(c.outputTemplate); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.OutputTemplate = c.outputTemplate
+			return nil
+		})
+	}
+
+	if c.stagingDir != "" {
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.StagingDir = c.stagingDir
+			return nil
+		})
+	}
+
+	if c.fallbackProxy != "" {
+		if err := cfgvalidate.ValidateFallbackProxy(c.fallbackProxy); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.FallbackProxy = c.fallbackProxy
+			return nil
+		})
+	}
+
+	if c.fallbackCookieSource != "" {
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.FallbackCookieSource = c.fallbackCookieSource
+			return nil
+		})
+	}
+
+	if c.authMethod != "" {
+		if err := cfgvalidate.ValidateAuthMethod(c.authMethod); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.AuthMethod = c.authMethod
+			return nil
+		})
+	}
+
+	if len(c.proxies) > 0 {
+		if err := cfgvalidate.ValidateProxies(c.proxies); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.Proxies = c.proxies
+			return nil
+		})
+	}
+
+	if len(c.externalIDs) > 0 {
+		externalIDs, err := cfgvalidate.ValidateExternalIDs(c.externalIDs)
+		if err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.ExternalIDs = externalIDs
+			return nil
+		})
+	}
+
+	if c.ytdlpExtraArgs != "" {
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.YTDLPExtraArgs = c.ytdlpExtraArgs
+			return nil
+		})
+	}
+
+	if c.fragments != nil {
+		fragments := *c.fragments
+		if err := cfgvalidate.ValidateConcurrentFragments(fragments); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.ConcurrentFragments = fragments
+			return nil
+		})
+	}
+
+	if c.connections != nil {
+		connections := *c.connections
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			// Validated against the resulting downloader, which may be set in this same update
+			if err := cfgvalidate.ValidateExternalDLConnections(connections, s.ExternalDownloader); err != nil {
+				return err
+			}
+			s.ExternalDLConnections = connections
+			return nil
+		})
+	}
+
+	if c.playlistMatch != "" {
+		if err := cfgvalidate.ValidatePlaylistMatch(c.playlistMatch); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.PlaylistMatch = c.playlistMatch
+			return nil
+		})
+	}
+
+	if c.skipMetarr != nil {
+		skip := *c.skipMetarr
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.SkipMetarr = skip
+			return nil
+		})
+	}
+
+	if c.diagnostics != nil {
+		diagnostics := *c.diagnostics
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.ExtractorDiagnostics = diagnostics
+			return nil
+		})
+	}
+
+	if c.quotaPrune != nil {
+		prune := *c.quotaPrune
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.QuotaPrune = prune
+			return nil
+		})
+	}
+
+	if c.keepLast != nil {
+		keepLast := *c.keepLast
+		if err := cfgvalidate.ValidateRetention(keepLast, 0); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.KeepLast = keepLast
+			return nil
+		})
+	}
+
+	if c.keepDays != nil {
+		keepDays := *c.keepDays
+		if err := cfgvalidate.ValidateRetention(0, keepDays); err != nil {
+			return nil, err
+		}
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.KeepDays = keepDays
+			return nil
+		})
+	}
+
+	if c.retentionNotify != nil {
+		notify := *c.retentionNotify
+		fns = append(fns, func(s *models.ChannelSettings) error {
+			s.RetentionNotify = notify
+			return nil
+		})
+	}
+
 	return fns, nil
 }
 
@@ -206,6 +735,15 @@ func getChanKeyVal(id int, name, url string) (key, val string, err error) {
 	return key, val, nil
 }
 
+// ChannelID returns the ID of the channel selected by the primary channel flags.
+func ChannelID(cs interfaces.ChannelStore, id int, name, url string) (int64, error) {
+	key, val, err := getChanKeyVal(id, name, url)
+	if err != nil {
+		return 0, err
+	}
+	return cs.GetID(key, val)
+}
+
 // verifyChanRowUpdateValid verifies that your update operation is valid
 func verifyChanRowUpdateValid(col, val string) error {
 	switch col {
@@ -219,41 +757,30 @@ func verifyChanRowUpdateValid(col, val string) error {
 	return nil
 }
 
-// verifyChannelOps verifies that the user inputted filters are valid
-func verifyChannelOps(ops []string) ([]models.DLFilters, error) {
-
-	var filters = make([]models.DLFilters, 0, len(ops))
-	for _, op := range ops {
-		split := strings.Split(op, ":")
-		if len(split) < 3 {
-			return nil, errors.New("please enter filters in the format 'field:filter_type:value' (e.g. 'title:omit:frogs' ignores videos with frogs in the metatitle)")
-		}
-		switch len(split) {
-		case 3:
-			switch split[1] {
-			case "contains", "omit":
-				filters = append(filters, models.DLFilters{
-					Field: split[0],
-					Type:  split[1],
-					Value: split[2],
-				})
-			default:
-				return nil, errors.New("please enter a filter type of either 'contains' or 'omit'")
+// pickPlexSection returns the section matching the library name, or asks the user to choose one.
+func pickPlexSection(sections []plex.Section, library string) (plex.Section, error) {
+	if library != "" {
+		for _, s := range sections {
+			if strings.EqualFold(s.Title, library) {
+				return s, nil
 			}
-		case 2:
-			switch split[1] {
-			case "contains", "omit":
-				filters = append(filters, models.DLFilters{
-					Field: split[0],
-					Type:  split[1],
-				})
-			default:
-				return nil, errors.New("please enter a filter type of either 'contains' or 'omit'")
-			}
-		default:
-			return nil, errors.New("invalid filter. Valid examples: 'title:contains:frogs','date:omit' (contains only metatitles with frogs, and omits downloads including a date metafield)")
-
 		}
+		logging.I("No Plex library named %q, please choose one:", library)
+	}
+
+	for i, s := range sections {
+		fmt.Printf("%d) %s (%s)\n", i+1, s.Title, s.Type)
+	}
+	fmt.Printf("Select a library [1-%d]: ", len(sections))
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return plex.Section{}, errors.New("no library selected")
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil || n < 1 || n > len(sections) {
+		return plex.Section{}, fmt.Errorf("invalid selection %q", scanner.Text())
 	}
-	return filters, nil
+	return sections[n-1], nil
 }
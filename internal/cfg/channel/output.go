@@ -0,0 +1,169 @@
+package cfgchannel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	cfgoutput "tubarr/internal/cfg/output"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+)
+
+// channelOutput is a channel as written by list commands for scripts, without its authentication details.
+type channelOutput struct {
+	ID         int64                  `json:"id"`
+	Name       string                 `json:"name"`
+	URL        string                 `json:"url"`
+	VideoDir   string                 `json:"video_directory"`
+	JSONDir    string                 `json:"json_directory"`
+	Tags       []string               `json:"tags"`
+	Settings   models.ChannelSettings `json:"settings"`
+	MetarrArgs models.MetarrArgs      `json:"metarr"`
+	LastScan   time.Time              `json:"last_scan"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// channelTableHeader is the header of the channel table output.
+var channelTableHeader = []string{"ID", "NAME", "URL", "CRAWL FREQ", "TAGS", "LAST SCAN"}
+
+// toChannelOutput converts a channel and its tags for output.
+func toChannelOutput(c *models.Channel, tags []string) channelOutput {
+	if tags == nil {
+		tags = []string{}
+	}
+	return channelOutput{
+		ID:         c.ID,
+		Name:       c.Name,
+		URL:        c.URL,
+		VideoDir:   c.VideoDir,
+		JSONDir:    c.JSONDir,
+		Tags:       tags,
+		Settings:   c.Settings,
+		MetarrArgs: c.MetarrArgs,
+		LastScan:   c.LastScan,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+	}
+}
+
+// printChannels writes channels in a table, JSON, or YAML output format.
+func printChannels(format string, chans []channelOutput) error {
+	if cfgoutput.Structured(format) {
+		return cfgoutput.Print(format, chans)
+	}
+
+	rows := make([][]string, 0, len(chans))
+	for _, c := range chans {
+		lastScan := "never"
+		if !c.LastScan.IsZero() {
+			lastScan = c.LastScan.Local().Format("2006-01-02 15:04")
+		}
+		rows = append(rows, []string{
+			strconv.FormatInt(c.ID, 10),
+			c.Name,
+			c.URL,
+			strconv.Itoa(c.Settings.CrawlFreq) + "m",
+			strings.Join(c.Tags, ","),
+			lastScan,
+		})
+	}
+	return cfgoutput.PrintTable(channelTableHeader, rows)
+}
+
+// printChannelInfo prints every setting of a channel, one labelled line each.
+func printChannelInfo(ch *models.Channel, tags []string) {
+	st, ma := ch.Settings, ch.MetarrArgs
+
+	fmt.Printf("\n%sChannel ID: %d%s\n", consts.ColorGreen, ch.ID, consts.ColorReset)
+	for _, f := range []struct {
+		label string
+		value any
+	}{
+		{"Name", ch.Name},
+		{"URL", ch.URL},
+		{"Video Directory", ch.VideoDir},
+		{"JSON Directory", ch.JSONDir},
+		{"Tags", tags},
+
+		// Crawling
+		{"Crawl Frequency", fmt.Sprintf("%d minutes", st.CrawlFreq)},
+		{"Crawl Schedule", st.CrawlCron},
+		{"Crawl Jitter", fmt.Sprintf("%d minutes", st.CrawlJitter)},
+		{"Quiet Hours", st.QuietHours},
+		{"Blackout Dates", st.BlackoutDates},
+		{"Filters", st.Filters},
+		{"URL Allow Patterns", st.URLAllow},
+		{"URL Block Patterns", st.URLBlock},
+		{"Playlist Match", st.PlaylistMatch},
+		{"Min Duration", st.MinDuration},
+		{"Max Duration", st.MaxDuration},
+		{"Min Views", st.MinViews},
+		{"Max Downloads Per Crawl", st.MaxDownloadsPerCrawl},
+		{"Posts URL", st.PostsURL},
+		{"Live URL", st.LiveURL},
+		{"Live Check Frequency", fmt.Sprintf("%d minutes", st.LiveCheckFreq)},
+		{"External IDs", st.ExternalIDs},
+		{"Extractor Diagnostics", st.ExtractorDiagnostics},
+
+		// Downloading
+		{"Concurrency", st.Concurrency},
+		{"Retries", st.Retries},
+		{"Retry Max Attempts", st.RetryMaxAttempts},
+		{"Cookie Source", st.CookieSource},
+		{"Fallback Cookie Source", st.FallbackCookieSource},
+		{"Auth Method", st.AuthMethod},
+		{"Proxies", st.Proxies},
+		{"Fallback Proxy", st.FallbackProxy},
+		{"Fetcher", st.Fetcher},
+		{"Fetcher Rules", st.FetcherRules},
+		{"External Downloader", st.ExternalDownloader},
+		{"External Downloader Args", st.ExternalDownloaderArgs},
+		{"External Downloader Connections", st.ExternalDLConnections},
+		{"Concurrent Fragments", st.ConcurrentFragments},
+		{"Extra yt-dlp Args", st.YTDLPExtraArgs},
+		{"Max Filesize", st.MaxFilesize},
+		{"Max Rate", st.MaxRate},
+		{"Max Resolution", st.MaxResolution},
+		{"Preferred Codec", st.PreferredCodec},
+		{"Audio Only", st.AudioOnly},
+		{"SponsorBlock Remove", st.SponsorBlockRemove},
+		{"SponsorBlock Mark", st.SponsorBlockMark},
+		{"Output Template", st.OutputTemplate},
+		{"Staging Directory", st.StagingDir},
+
+		// Storage and retention
+		{"Max Total Size", st.MaxTotalSize},
+		{"Quota Prune", st.QuotaPrune},
+		{"Keep Last", st.KeepLast},
+		{"Keep Days", st.KeepDays},
+		{"Retention Notify", st.RetentionNotify},
+
+		// Post-processing
+		{"Post-Processors", st.PostProcessors},
+		{"Sidecars", st.Sidecars},
+		{"NFO", st.NFO},
+		{"NFO Episode Template", st.NFOEpisodeTemplate},
+		{"NFO Show Template", st.NFOShowTemplate},
+		{"NFO Field Map", st.NFOFieldMap},
+		{"Transcribe", st.Transcribe},
+		{"Transcribe Model", st.TranscribeModel},
+		{"Transcribe Language", st.TranscribeLanguage},
+
+		// Metarr
+		{"Skip Metarr", st.SkipMetarr},
+		{"Max CPU", fmt.Sprintf("%.2f", ma.MaxCPU)},
+		{"Metarr Concurrency", ma.Concurrency},
+		{"Min Free Mem", ma.MinFreeMem},
+		{"Output Dir", ma.OutputDir},
+		{"Output Filetype", ma.Ext},
+		{"Rename Style", ma.RenameStyle},
+		{"Filename Suffix Replace", ma.FilenameReplaceSfx},
+		{"Meta Ops", ma.MetaOps},
+		{"Filename Date Format", ma.FileDatePfx},
+	} {
+		fmt.Printf("%s: %v\n", f.label, f.value)
+	}
+}
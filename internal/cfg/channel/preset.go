@@ -0,0 +1,262 @@
+package cfgchannel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	cfgvalidate "tubarr/internal/cfg/validation"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitPresetCmds is the entrypoint for initializing commands managing settings presets.
+func InitPresetCmds(cs interfaces.ChannelStore) *cobra.Command {
+	presetCmd := &cobra.Command{
+		Use:   "preset",
+		Short: "Channel settings presets.",
+		Long: "Manage named presets of settings and Metarr arguments. Add a channel with 'channel add --preset <name>' to start from a preset, " +
+			"settings entered as flags override the preset's.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	presetCmd.AddCommand(createPresetCmd(cs))
+	presetCmd.AddCommand(cfgflags.MarkReadOnlySafe(listPresetsCmd(cs)))
+	presetCmd.AddCommand(cfgflags.MarkReadOnlySafe(showPresetCmd(cs)))
+	presetCmd.AddCommand(deletePresetCmd(cs))
+	return presetCmd
+}
+
+// createPresetCmd stores a preset read from a YAML or JSON file.
+func createPresetCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		configFile string
+		replace    bool
+	)
+
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a preset from a config file.",
+		Long: "Creates a preset from a YAML or JSON file with 'settings' and 'metarr' sections, using the same setting names as 'channel export'. " +
+			"Use --replace to overwrite an existing preset.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				return errors.New("preset name cannot be blank")
+			}
+			if configFile == "" {
+				return errors.New("must enter a preset config file with --config")
+			}
+
+			var p models.Preset
+			if err := decodeFile(configFile, "preset", &p); err != nil {
+				return err
+			}
+			if err := validatePreset(&p); err != nil {
+				return fmt.Errorf("invalid preset %q: %w", name, err)
+			}
+			p.Name = name
+
+			if err := cs.AddPreset(&p, replace); err != nil {
+				return err
+			}
+			logging.S(0, "Saved preset %q", name)
+			return nil
+		},
+	}
+
+	createCmd.Flags().StringVar(&configFile, "config", "", "YAML or JSON file holding the preset's settings")
+	createCmd.Flags().BoolVar(&replace, "replace", false, "Replace the preset if it already exists")
+	return createCmd
+}
+
+// listPresetsCmd lists the stored presets.
+func listPresetsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List presets.",
+		Long:  "Lists the names of every stored preset and when they were last updated.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			presets, err := cs.FetchPresets()
+			if err != nil {
+				return err
+			}
+			if len(presets) == 0 {
+				logging.I("No presets are stored")
+				return nil
+			}
+			for _, p := range presets {
+				fmt.Printf("%s%s%s (updated %s)\n", consts.ColorGreen, p.Name, consts.ColorReset, p.UpdatedAt.Format("2006-01-02 15:04"))
+			}
+			return nil
+		},
+	}
+}
+
+// showPresetCmd prints a preset.
+func showPresetCmd(cs interfaces.ChannelStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a preset.",
+		Long:  "Prints a preset's settings and Metarr arguments as JSON.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := cs.GetPreset(args[0])
+			if err != nil {
+				return err
+			}
+			b, err := json.MarshalIndent(p, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal preset: %w", err)
+			}
+			fmt.Println(string(b))
+			return nil
+		},
+	}
+}
+
+// deletePresetCmd deletes a preset.
+func deletePresetCmd(cs interfaces.ChannelStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a preset.",
+		Long:  "Deletes a preset. Channels added with it keep the settings it gave them.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deleted, err := cs.DeletePreset(args[0])
+			if err != nil {
+				return err
+			}
+			if !deleted {
+				return fmt.Errorf("no preset named %q", args[0])
+			}
+			logging.S(0, "Deleted preset %q", args[0])
+			return nil
+		},
+	}
+}
+
+// validatePreset checks a preset's settings as 'channel add' checks the same settings entered as flags.
+func validatePreset(p *models.Preset) error {
+	s, m := &p.Settings, &p.MetarrArgs
+	var err error
+
+	if s.URLAllow, err = cfgvalidate.ValidateURLPatterns(s.URLAllow); err != nil {
+		return err
+	}
+	if s.URLBlock, err = cfgvalidate.ValidateURLPatterns(s.URLBlock); err != nil {
+		return err
+	}
+	if s.BlackoutDates, err = cfgvalidate.ValidateBlackoutDates(s.BlackoutDates); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateSchedule(s.CrawlCron, s.QuietHours, s.CrawlJitter); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateRetryMaxAttempts(s.RetryMaxAttempts); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateMaxDownloadsPerCrawl(s.MaxDownloadsPerCrawl); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateMaxRate(s.MaxRate); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateMetadataLimits(s.MinDuration, s.MaxDuration, s.MinViews); err != nil {
+		return err
+	}
+	if _, err := cfgvalidate.ValidateFormat("", s.PreferredCodec); err != nil {
+		return err
+	}
+	if s.Sidecars, err = cfgvalidate.ValidateSidecars(s.Sidecars); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateNFOTemplates(s.NFOEpisodeTemplate, s.NFOShowTemplate); err != nil {
+		return err
+	}
+	if len(s.NFOFieldMap) > 0 {
+		entries := make([]string, 0, len(s.NFOFieldMap))
+		for field, metaField := range s.NFOFieldMap {
+			entries = append(entries, field+":"+metaField)
+		}
+		if s.NFOFieldMap, err = cfgvalidate.ValidateNFOFieldMap(entries, ""); err != nil {
+			return err
+		}
+	}
+	if err := cfgvalidate.ValidateOutputTemplate(s.OutputTemplate); err != nil {
+		return err
+	}
+	if s.PostProcessors, err = cfgvalidate.ValidatePostProcessors(s.PostProcessors); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateTranscribe(s.TranscribeModel, s.TranscribeLanguage); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateFetcher(s.Fetcher); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateFetcherRules(s.FetcherRules); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateSponsorBlock(s.SponsorBlockRemove); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateSponsorBlock(s.SponsorBlockMark); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateMaxTotalSize(s.MaxTotalSize); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateRetention(s.KeepLast, s.KeepDays); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateProxies(s.Proxies); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateFallbackProxy(s.FallbackProxy); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateConcurrentFragments(s.ConcurrentFragments); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidatePlaylistMatch(s.PlaylistMatch); err != nil {
+		return err
+	}
+	if err := cfgvalidate.ValidateExternalDLConnections(s.ExternalDLConnections, s.ExternalDownloader); err != nil {
+		return err
+	}
+
+	if m.FileDatePfx != "" && !cfgvalidate.DateFormat(m.FileDatePfx) {
+		return errors.New("invalid Metarr filename date tag format")
+	}
+	if len(m.MetaOps) > 0 {
+		if m.MetaOps, err = cfgvalidate.ValidateMetaOps(m.MetaOps); err != nil {
+			return err
+		}
+	}
+	if len(m.FilenameReplaceSfx) > 0 {
+		if m.FilenameReplaceSfx, err = cfgvalidate.ValidateFilenameSuffixReplace(m.FilenameReplaceSfx); err != nil {
+			return err
+		}
+	}
+	if m.RenameStyle != "" {
+		if err := cfgvalidate.ValidateRenameFlag(m.RenameStyle); err != nil {
+			return err
+		}
+	}
+	if m.MinFreeMem != "" {
+		if err := cfgvalidate.ValidateMinFreeMem(m.MinFreeMem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package cfgchannel
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/remote"
+)
+
+// delegateChannelAction hands a crawl or reprocess of a channel to the running instance, optionally only some
+// video URLs.
+//
+// The action is sent to its HTTP API if served, or else queued for its scheduler.
+func delegateChannelAction(cs interfaces.ChannelStore, key, val string, action consts.CommandAction, videoURLs []string) error {
+	addr := cfgflags.RemoteAddr()
+	if addr == "" {
+		return cs.QueueCommand(key, val, action, videoURLs)
+	}
+
+	id, err := cs.GetID(key, val)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/api/channels/%d/%s", id, action)
+	if len(videoURLs) > 0 {
+		path += "?" + url.Values{"url": videoURLs}.Encode()
+	}
+	if err := remote.Call(addr, cfgflags.APIToken(), http.MethodPost, path, nil); err != nil {
+		return err
+	}
+	logging.S(0, "Started %s of channel with ID %d in the running Tubarr instance, follow its progress in its log", action, id)
+	return nil
+}
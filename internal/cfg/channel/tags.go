@@ -0,0 +1,302 @@
+package cfgchannel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	cfgvalidate "tubarr/internal/cfg/validation"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// tagCmd returns the commands adding, removing, and listing a channel's tags.
+func tagCmd(cs interfaces.ChannelStore) *cobra.Command {
+	tagCmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Manage channel tags.",
+		Long:  "Tag channels to group them, so a whole group can be crawled, paused, or updated at once with 'group' commands.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	tagCmd.AddCommand(addTagsCmd(cs))
+	tagCmd.AddCommand(removeTagsCmd(cs))
+	tagCmd.AddCommand(cfgflags.MarkReadOnlySafe(listTagsCmd(cs)))
+	return tagCmd
+}
+
+// addTagsCmd adds tags to a channel.
+func addTagsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		url, name string
+		id        int
+	)
+
+	addCmd := &cobra.Command{
+		Use:   "add <tag>...",
+		Short: "Add tags to a channel.",
+		Long:  "Adds one or more tags to a channel. Tags are lowercase single words, such as 'news' or 'kids-shows'.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := cfgvalidate.ValidateTags(args)
+			if err != nil {
+				return err
+			}
+			chanID, err := ChannelID(cs, id, name, url)
+			if err != nil {
+				return err
+			}
+			if err := cs.AddChannelTags(chanID, tags); err != nil {
+				return err
+			}
+			logging.S(0, "Tagged channel with ID %d: %v", chanID, tags)
+			return nil
+		},
+	}
+
+	SetPrimaryChannelFlags(addCmd, &name, &url, &id)
+	return addCmd
+}
+
+// removeTagsCmd removes tags from a channel.
+func removeTagsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		url, name string
+		id        int
+	)
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <tag>...",
+		Short: "Remove tags from a channel.",
+		Long:  "Removes one or more tags from a channel.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := cfgvalidate.ValidateTags(args)
+			if err != nil {
+				return err
+			}
+			chanID, err := ChannelID(cs, id, name, url)
+			if err != nil {
+				return err
+			}
+			n, err := cs.RemoveChannelTags(chanID, tags)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				logging.I("Channel with ID %d had none of the tags %v", chanID, tags)
+				return nil
+			}
+			logging.S(0, "Removed %d tags from channel with ID %d", n, chanID)
+			return nil
+		},
+	}
+
+	SetPrimaryChannelFlags(removeCmd, &name, &url, &id)
+	return removeCmd
+}
+
+// listTagsCmd lists a channel's tags.
+func listTagsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		url, name string
+		id        int
+	)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List a channel's tags.",
+		Long:  "Lists the tags of a channel. Use 'group list' for every tag in use.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chanID, err := ChannelID(cs, id, name, url)
+			if err != nil {
+				return err
+			}
+			tags, err := cs.GetChannelTags(chanID)
+			if err != nil {
+				return err
+			}
+			if len(tags) == 0 {
+				logging.I("Channel with ID %d has no tags", chanID)
+				return nil
+			}
+			for _, t := range tags {
+				fmt.Println(t)
+			}
+			return nil
+		},
+	}
+
+	SetPrimaryChannelFlags(listCmd, &name, &url, &id)
+	return listCmd
+}
+
+// InitGroupCmds is the entrypoint for initializing commands operating on every channel with a tag.
+func InitGroupCmds(s interfaces.Store, ctx context.Context) *cobra.Command {
+	groupCmd := &cobra.Command{
+		Use:   "group",
+		Short: "Channel group commands.",
+		Long:  "Crawl, pause, or resume every channel with a tag. Tag channels with 'channel tag add', and update a group's settings with 'channel update-settings --tag'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	cs := s.ChannelStore()
+	groupCmd.AddCommand(cfgflags.MarkReadOnlySafe(listGroupsCmd(cs)))
+	groupCmd.AddCommand(crawlGroupCmd(cs, s, ctx))
+	groupCmd.AddCommand(pauseGroupCmd(s.HostStore()))
+	groupCmd.AddCommand(resumeGroupCmd(s.HostStore()))
+	return groupCmd
+}
+
+// listGroupsCmd lists every tag in use and its channels.
+func listGroupsCmd(cs interfaces.ChannelStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List channel groups.",
+		Long:  "Lists every tag in use and how many channels have it.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			counts, err := cs.FetchTagCounts()
+			if err != nil {
+				return err
+			}
+			if len(counts) == 0 {
+				logging.I("No channels are tagged")
+				return nil
+			}
+
+			tags := make([]string, 0, len(counts))
+			for t := range counts {
+				tags = append(tags, t)
+			}
+			slices.Sort(tags)
+			for _, t := range tags {
+				fmt.Printf("%s%s%s: %d channels\n", consts.ColorGreen, t, consts.ColorReset, counts[t])
+			}
+			return nil
+		},
+	}
+}
+
+// crawlGroupCmd crawls every channel with a tag.
+func crawlGroupCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "crawl <tag>",
+		Short: "Crawl every channel in a group.",
+		Long:  "Crawls each channel with the tag for new URLs in turn, continuing past channels which fail.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chans, err := groupChannels(cs, args[0])
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, c := range chans {
+				if err := cs.CrawlChannel(consts.QChanID, strconv.FormatInt(c.ID, 10), s, ctx); err != nil {
+					logging.E(0, "Failed to crawl channel %q: %v", c.Name, err)
+					errs = append(errs, err)
+				}
+			}
+			if len(errs) > 0 {
+				return fmt.Errorf("%d of %d channels in group %q failed to crawl", len(errs), len(chans), args[0])
+			}
+			return nil
+		},
+	}
+}
+
+// pauseGroupCmd stops new crawls and downloads from starting for every channel with a tag.
+func pauseGroupCmd(hs interfaces.HostStore) *cobra.Command {
+	var (
+		duration time.Duration
+		reason   string
+	)
+
+	pauseCmd := &cobra.Command{
+		Use:   "pause <tag>",
+		Short: "Pause downloads for a group.",
+		Long: "Stops new crawls, downloads, and retries from starting for every channel with the tag, in this and any running Tubarr process. " +
+			"Downloads already in progress are allowed to finish.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := cfgvalidate.ValidateTags(args)
+			if err != nil {
+				return err
+			}
+			if duration < 0 {
+				return fmt.Errorf("pause duration cannot be negative, got %v", duration)
+			}
+
+			var until time.Time
+			if duration > 0 {
+				until = time.Now().Add(duration)
+			}
+			scope := consts.PauseGroupPrefix + tags[0]
+			if err := hs.SetPause(scope, until, reason); err != nil {
+				return err
+			}
+
+			p := models.Pause{Scope: scope, Until: until, Reason: reason}
+			logging.S(0, "Paused downloads for %s", p.Describe())
+			return nil
+		},
+	}
+
+	pauseCmd.Flags().DurationVar(&duration, "for", 0, "How long to pause for (e.g. '24h', '90m'), pauses until resumed if not set")
+	pauseCmd.Flags().StringVar(&reason, "reason", "", "Note on why downloads are paused, shown in logs")
+	return pauseCmd
+}
+
+// resumeGroupCmd lifts a group's pause.
+func resumeGroupCmd(hs interfaces.HostStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume <tag>",
+		Short: "Resume downloads for a group.",
+		Long:  "Lifts the pause set with 'group pause' for a tag. Global and host pauses stay in effect.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := cfgvalidate.ValidateTags(args)
+			if err != nil {
+				return err
+			}
+			cleared, err := hs.ClearPause(consts.PauseGroupPrefix + tags[0])
+			if err != nil {
+				return err
+			}
+			if !cleared {
+				logging.I("Downloads were not paused for group %s", tags[0])
+				return nil
+			}
+			logging.S(0, "Resumed downloads for group %s", tags[0])
+			return nil
+		},
+	}
+}
+
+// groupChannels returns the channels with a tag, erroring if there are none.
+func groupChannels(cs interfaces.ChannelStore, tag string) ([]*models.Channel, error) {
+	tags, err := cfgvalidate.ValidateTags([]string{tag})
+	if err != nil {
+		return nil, err
+	}
+	chans, err := cs.FetchTaggedChannels(tags[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(chans) == 0 {
+		return nil, fmt.Errorf("no channels are tagged %q", tags[0])
+	}
+	return chans, nil
+}
@@ -0,0 +1,246 @@
+package cfgchannel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/remote"
+
+	"github.com/spf13/cobra"
+)
+
+// urlCmd returns the commands crawling, pausing, resuming, and setting the check frequency of a single channel URL.
+func urlCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
+	hs := s.HostStore()
+	urlCmd := &cobra.Command{
+		Use:   "url",
+		Short: "Manage individual channel URLs.",
+		Long: "Crawl, pause, or set the check frequency of one of a channel's URLs, its crawl URL or its live URL, " +
+			"without affecting the rest of the channel.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	urlCmd.AddCommand(cfgflags.MarkRemoteSafe(crawlURLCmd(cs, s, ctx)))
+	urlCmd.AddCommand(pauseURLCmd(cs, hs))
+	urlCmd.AddCommand(resumeURLCmd(cs, hs))
+	urlCmd.AddCommand(urlFreqCmd(cs))
+	return urlCmd
+}
+
+// crawlURLCmd crawls a single channel URL now.
+func crawlURLCmd(cs interfaces.ChannelStore, s interfaces.Store, ctx context.Context) *cobra.Command {
+	var rawURL string
+
+	crawlCmd := &cobra.Command{
+		Use:   "crawl",
+		Short: "Crawl a channel URL now.",
+		Long: "Crawls the channel when given its crawl URL, or checks its live URL for a stream to capture when given its live URL, " +
+			"without waiting for the URL's check frequency. Paused URLs are not crawled.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, chanURL, err := channelForURL(cs, rawURL)
+			if err != nil {
+				return err
+			}
+			if p, paused, err := urlPause(s.HostStore(), chanURL); err != nil {
+				return err
+			} else if paused {
+				return fmt.Errorf("URL %q of channel %q is paused for %s", chanURL, c.Name, p.Describe())
+			}
+
+			id := strconv.FormatInt(c.ID, 10)
+			live := chanURL != c.URL
+			if !live {
+				if cfgflags.RemoteAddr() != "" || cfgflags.QueueCommands() {
+					return delegateChannelAction(cs, consts.QChanID, id, consts.CommandCrawl, nil)
+				}
+				return cs.CrawlChannel(consts.QChanID, id, s, ctx)
+			}
+
+			if addr := cfgflags.RemoteAddr(); addr != "" {
+				path := fmt.Sprintf("/api/channels/%d/urls/%s/crawl", c.ID, consts.ChannelURLLive)
+				if err := remote.Call(addr, cfgflags.APIToken(), http.MethodPost, path, nil); err != nil {
+					return err
+				}
+				logging.S(0, "Started live check of channel %q in the running Tubarr instance, follow its progress in its log", c.Name)
+				return nil
+			}
+			if cfgflags.QueueCommands() {
+				return errors.New("live checks can't be queued for the running scheduler, serve its HTTP API with --http-addr to check live URLs on request")
+			}
+			return cs.CheckLiveURL(consts.QChanID, id, s, ctx)
+		},
+	}
+
+	crawlCmd.Flags().StringVar(&rawURL, "url", "", "Crawl or live URL of a channel")
+	return crawlCmd
+}
+
+// urlPause returns the pause set on the channel URL, if any.
+func urlPause(hs interfaces.HostStore, chanURL string) (*models.Pause, bool, error) {
+	pauses, err := hs.FetchPauses()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, p := range pauses {
+		if p.Scope == consts.PauseURLPrefix+chanURL {
+			return p, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// pauseURLCmd stops a channel URL from being crawled or checked.
+func pauseURLCmd(cs interfaces.ChannelStore, hs interfaces.HostStore) *cobra.Command {
+	var (
+		rawURL, reason string
+		duration       time.Duration
+	)
+
+	pauseCmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause a channel URL.",
+		Long: "Stops a channel's crawl URL from being crawled, or its live URL from being checked, in this and any running Tubarr process. " +
+			"Pausing the crawl URL also holds back its downloads and retries. Downloads already in progress are allowed to finish.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, chanURL, err := channelForURL(cs, rawURL)
+			if err != nil {
+				return err
+			}
+			if duration < 0 {
+				return fmt.Errorf("pause duration cannot be negative, got %v", duration)
+			}
+
+			var until time.Time
+			if duration > 0 {
+				until = time.Now().Add(duration)
+			}
+			scope := consts.PauseURLPrefix + chanURL
+			if err := hs.SetPause(scope, until, reason); err != nil {
+				return err
+			}
+
+			p := models.Pause{Scope: scope, Until: until, Reason: reason}
+			logging.S(0, "Paused channel %q %s", c.Name, p.Describe())
+			return nil
+		},
+	}
+
+	pauseCmd.Flags().StringVar(&rawURL, "url", "", "Crawl or live URL of a channel")
+	pauseCmd.Flags().DurationVar(&duration, "for", 0, "How long to pause for (e.g. '24h', '90m'), pauses until resumed if not set")
+	pauseCmd.Flags().StringVar(&reason, "reason", "", "Note on why the URL is paused, shown in logs")
+	return pauseCmd
+}
+
+// resumeURLCmd lifts a channel URL's pause.
+func resumeURLCmd(cs interfaces.ChannelStore, hs interfaces.HostStore) *cobra.Command {
+	var rawURL string
+
+	resumeCmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume a channel URL.",
+		Long:  "Lifts the pause set with 'channel url pause'. Global, host, and group pauses stay in effect.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, chanURL, err := channelForURL(cs, rawURL)
+			if err != nil {
+				return err
+			}
+			cleared, err := hs.ClearPause(consts.PauseURLPrefix + chanURL)
+			if err != nil {
+				return err
+			}
+			if !cleared {
+				logging.I("URL %q of channel %q was not paused", chanURL, c.Name)
+				return nil
+			}
+			logging.S(0, "Resumed URL %q of channel %q", chanURL, c.Name)
+			return nil
+		},
+	}
+
+	resumeCmd.Flags().StringVar(&rawURL, "url", "", "Crawl or live URL of a channel")
+	return resumeCmd
+}
+
+// urlFreqCmd sets how often a channel URL is crawled or checked.
+func urlFreqCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		rawURL string
+		freq   int
+	)
+
+	freqCmd := &cobra.Command{
+		Use:   "crawl-freq",
+		Short: "Set how often a channel URL is checked.",
+		Long: "Sets the crawl frequency when given a channel's crawl URL, or the live check frequency when given its live URL. " +
+			"A crawl schedule set with --crawl-cron still takes precedence for the crawl URL.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if freq < 1 {
+				return fmt.Errorf("frequency must be at least 1 minute, got %d", freq)
+			}
+			c, chanURL, err := channelForURL(cs, rawURL)
+			if err != nil {
+				return err
+			}
+
+			live := chanURL != c.URL
+			if _, err := cs.UpdateChannelSettingsJSON(consts.QChanID, strconv.FormatInt(c.ID, 10), func(s *models.ChannelSettings) error {
+				if live {
+					s.LiveCheckFreq = freq
+				} else {
+					s.CrawlFreq = freq
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			if live {
+				logging.S(0, "Checking live URL %q of channel %q every %d minutes", chanURL, c.Name, freq)
+			} else {
+				logging.S(0, "Crawling URL %q of channel %q every %d minutes", chanURL, c.Name, freq)
+			}
+			return nil
+		},
+	}
+
+	freqCmd.Flags().StringVar(&rawURL, "url", "", "Crawl or live URL of a channel")
+	freqCmd.Flags().IntVar(&freq, "freq", 0, "Minutes between checks of the URL")
+	return freqCmd
+}
+
+// channelForURL returns the channel with the crawl or live URL, and that URL as stored.
+//
+// Trailing slashes are ignored when matching.
+func channelForURL(cs interfaces.ChannelStore, rawURL string) (*models.Channel, string, error) {
+	want := strings.TrimRight(strings.TrimSpace(rawURL), "/")
+	if want == "" {
+		return nil, "", errors.New("must enter a channel URL with --url")
+	}
+
+	chans, err, hasRows := cs.FetchAllChannels()
+	if !hasRows {
+		return nil, "", errors.New("no channels in database")
+	} else if err != nil {
+		return nil, "", err
+	}
+	for _, c := range chans {
+		for _, u := range []string{c.URL, c.Settings.LiveURL} {
+			if u != "" && strings.TrimRight(u, "/") == want {
+				return c, u, nil
+			}
+		}
+	}
+	return nil, "", fmt.Errorf("no channel has the crawl or live URL %q", rawURL)
+}
@@ -0,0 +1,195 @@
+package cfgchannel
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/webhook"
+
+	"github.com/spf13/cobra"
+)
+
+// addWebhookCmd adds a generic webhook which is sent for each newly downloaded video.
+func addWebhookCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		channelName, channelURL string
+		channelID               int
+		name, hookURL, method   string
+		payload, payloadFile    string
+		headers                 []string
+	)
+
+	addWebhookCmd := &cobra.Command{
+		Use:   "webhook-add",
+		Short: "Adds a webhook notification to a channel.",
+		Long: "Sends an HTTP request to the URL for each newly downloaded video. The payload is a Go template rendering JSON, with fields " +
+			".Title, .Channel, .ChannelURL, .URL, .Path, and .UploadDate, and a 'json' function for quoting values, e.g. '{\"text\": {{json .Title}}}'. " +
+			"Channel warnings, such as reaching the max total size, are also sent with the message in .Title and .Warning.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if hookURL == "" {
+				return errors.New("webhook URL cannot be blank")
+			}
+
+			if payloadFile != "" {
+				if payload != "" {
+					return errors.New("use only one of --payload and --payload-file")
+				}
+				b, err := os.ReadFile(payloadFile)
+				if err != nil {
+					return fmt.Errorf("failed to read payload file: %w", err)
+				}
+				payload = string(b)
+			}
+
+			if err := webhook.Validate(method, payload); err != nil {
+				return err
+			}
+
+			headerMap, err := parseHeaders(headers)
+			if err != nil {
+				return err
+			}
+
+			key, val, err := getChanKeyVal(channelID, channelName, channelURL)
+			if err != nil {
+				return err
+			}
+			id, err := cs.GetID(key, val)
+			if err != nil {
+				return err
+			}
+
+			return cs.AddWebhook(&models.Webhook{
+				ChannelID: id,
+				Name:      name,
+				URL:       hookURL,
+				Method:    method,
+				Headers:   headerMap,
+				Payload:   payload,
+			})
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(addWebhookCmd, &channelName, &channelURL, &channelID)
+	addWebhookCmd.Flags().StringVar(&hookURL, "webhook-url", "", "URL to send the webhook to")
+	addWebhookCmd.Flags().StringVar(&name, "webhook-name", "", "Name for this webhook (defaults to the URL)")
+	addWebhookCmd.Flags().StringVar(&method, "method", http.MethodPost, "HTTP method (GET, POST, PUT, PATCH, DELETE)")
+	addWebhookCmd.Flags().StringArrayVar(&headers, "header", nil, "Header to send, as 'Name: Value' (repeatable)")
+	addWebhookCmd.Flags().StringVar(&payload, "payload", "", "Go template for the JSON payload (defaults to title, channel, url, and path)")
+	addWebhookCmd.Flags().StringVar(&payloadFile, "payload-file", "", "File containing the payload template")
+
+	return addWebhookCmd
+}
+
+// deleteWebhooksCmd deletes webhooks from a channel by name.
+func deleteWebhooksCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		channelName, channelURL string
+		channelID               int
+		names                   []string
+	)
+
+	deleteWebhooksCmd := &cobra.Command{
+		Use:   "webhook-delete",
+		Short: "Deletes webhooks from a channel.",
+		Long:  "Deletes webhook notifications from a channel by name.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(names) == 0 {
+				return errors.New("must enter at least one webhook name to delete")
+			}
+
+			key, val, err := getChanKeyVal(channelID, channelName, channelURL)
+			if err != nil {
+				return err
+			}
+			id, err := cs.GetID(key, val)
+			if err != nil {
+				return err
+			}
+			return cs.DeleteWebhooks(id, names)
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(deleteWebhooksCmd, &channelName, &channelURL, &channelID)
+	deleteWebhooksCmd.Flags().StringSliceVar(&names, "names", nil, "Webhook names to delete")
+
+	return deleteWebhooksCmd
+}
+
+// listWebhooksCmd lists a channel's webhooks.
+func listWebhooksCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		channelName, channelURL string
+		channelID               int
+	)
+
+	listWebhooksCmd := &cobra.Command{
+		Use:   "webhook-list",
+		Short: "Lists a channel's webhooks.",
+		Long:  "Prints each webhook's name, method, URL, header names, and payload template.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, val, err := getChanKeyVal(channelID, channelName, channelURL)
+			if err != nil {
+				return err
+			}
+			id, err := cs.GetID(key, val)
+			if err != nil {
+				return err
+			}
+
+			hooks, err := cs.GetWebhooks(id)
+			if err != nil {
+				return err
+			}
+			if len(hooks) == 0 {
+				fmt.Printf("No webhooks for channel with ID %d\n", id)
+				return nil
+			}
+
+			for _, h := range hooks {
+				// Header values often hold secrets, so only names are shown
+				headerNames := make([]string, 0, len(h.Headers))
+				for k := range h.Headers {
+					headerNames = append(headerNames, k)
+				}
+
+				payload := h.Payload
+				if payload == "" {
+					payload = webhook.DefaultPayload + " (default)"
+				}
+				fmt.Printf("\n%s\n  %s %s\n  Headers: %v\n  Payload: %s\n", h.Name, h.Method, h.URL, headerNames, payload)
+			}
+			fmt.Println()
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	SetPrimaryChannelFlags(listWebhooksCmd, &channelName, &channelURL, &channelID)
+
+	return listWebhooksCmd
+}
+
+// parseHeaders parses 'Name: Value' header entries.
+func parseHeaders(headers []string) (map[string]string, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	headerMap := make(map[string]string, len(headers))
+	for _, h := range headers {
+		k, v, ok := strings.Cut(h, ":")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid header %q, expected 'Name: Value'", h)
+		}
+		headerMap[http.CanonicalHeaderKey(k)] = strings.TrimSpace(v)
+	}
+	return headerMap, nil
+}
@@ -0,0 +1,128 @@
+// Package cfgdb sets up Cobra database commands.
+package cfgdb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/data/database"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitDBCmds is the entrypoint for initializing database commands.
+//
+// Pending migrations are not applied automatically when a database command runs.
+func InitDBCmds(s interfaces.Store) *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Database commands.",
+		Long:  "Manage database schema migrations. Other commands apply pending migrations on startup.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	dbCmd.AddCommand(migrateCmd(s))
+	dbCmd.AddCommand(rollbackCmd(s))
+	dbCmd.AddCommand(cfgflags.MarkReadOnlySafe(statusCmd(s)))
+	return dbCmd
+}
+
+// migrateCmd applies pending schema migrations.
+func migrateCmd(s interfaces.Store) *cobra.Command {
+	var to int
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending migrations.",
+		Long:  "Applies pending schema migrations in order, up to --to if set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			applied, err := database.Migrate(s.GetDB(), to)
+			if err != nil {
+				return err
+			}
+			if len(applied) == 0 {
+				logging.I("No migrations to apply")
+				return nil
+			}
+			logging.S(0, "Applied %d migration(s), schema is at version %d", len(applied), applied[len(applied)-1])
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&to, "to", 0, "Schema version to migrate up to (0 for the latest)")
+	return cmd
+}
+
+// rollbackCmd reverts applied schema migrations.
+func rollbackCmd(s interfaces.Store) *cobra.Command {
+	var (
+		to    int
+		steps int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Revert applied migrations.",
+		Long:  "Reverts the newest applied migrations, by --steps (default 1) or down to version --to. Run it with the build which applied them, before downgrading Tubarr.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db := s.GetDB()
+			if to == 0 {
+				if steps < 1 {
+					return errors.New("steps must be at least 1")
+				}
+				current, err := database.SchemaVersion(db)
+				if err != nil {
+					return err
+				}
+				to = max(current-steps, 1) // The baseline can't be rolled back
+			}
+
+			reverted, err := database.Rollback(db, to)
+			if err != nil {
+				return err
+			}
+			if len(reverted) == 0 {
+				logging.I("No migrations to roll back")
+				return nil
+			}
+			logging.S(0, "Rolled back %d migration(s), schema is at version %d", len(reverted), to)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&to, "to", 0, "Schema version to roll back to")
+	cmd.Flags().IntVar(&steps, "steps", 1, "Number of migrations to roll back, if --to is not set")
+	return cmd
+}
+
+// statusCmd lists migrations and whether they are applied.
+func statusCmd(s interfaces.Store) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "List migrations.",
+		Long:  "Lists known schema migrations and when each was applied.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statuses, err := database.Migrations(s.GetDB())
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\n%sSchema Migrations%s (latest: %d)\n", consts.ColorGreen, consts.ColorReset, database.LatestVersion())
+			for _, m := range statuses {
+				applied := "pending"
+				if !m.AppliedAt.IsZero() {
+					applied = "applied " + m.AppliedAt.Format(time.RFC1123Z)
+				}
+				fmt.Printf("%4d  %-24s %s\n", m.Version, m.Name, applied)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,161 @@
+// Package cfgdoctor sets up Cobra doctor commands.
+package cfgdoctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitDoctorCmds is the entrypoint for initializing doctor commands.
+func InitDoctorCmds(s interfaces.Store) *cobra.Command {
+	var repair bool
+
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check stored file paths against the filesystem.",
+		Long:  "Checks stored video and JSON paths, reports files which no longer exist, and optionally repairs paths for files which were renamed or moved.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfgflags.CheckReadOnlyFlag(cmd, "repair"); err != nil {
+				return err
+			}
+			vs := s.VideoStore()
+
+			videos, err := vs.FetchVideosWithPaths()
+			if err != nil {
+				return err
+			}
+
+			var mismatches, repaired int
+			for _, v := range videos {
+				videoPath, vOK := checkPath(v.VideoPath, v.VideoDir, false)
+				jsonPath, jOK := checkPath(v.JSONPath, v.JSONDir, true)
+				if vOK && jOK {
+					continue
+				}
+				mismatches++
+
+				reportMismatch(v, v.VideoPath, videoPath, vOK)
+				reportMismatch(v, v.JSONPath, jsonPath, jOK)
+
+				if !repair || (videoPath == v.VideoPath && jsonPath == v.JSONPath) {
+					continue
+				}
+
+				if err := vs.UpdateVideoPaths(v.ID, videoPath, jsonPath); err != nil {
+					logging.E(0, "Failed to repair paths for video %q: %v", v.URL, err)
+					continue
+				}
+				repaired++
+			}
+
+			switch {
+			case mismatches == 0:
+				logging.S(0, "All %d stored video paths are valid", len(videos))
+			case repair:
+				logging.I("Found %d video(s) with missing files, repaired %d", mismatches, repaired)
+			default:
+				logging.I("Found %d video(s) with missing files, run with --repair to fix paths with a unique match", mismatches)
+			}
+			return nil
+		},
+	}
+
+	doctorCmd.Flags().BoolVar(&repair, "repair", false, "Update stored paths when a unique renamed match is found")
+	return cfgflags.MarkReadOnlySafe(doctorCmd)
+}
+
+// reportMismatch prints the result of a path check.
+func reportMismatch(v *models.Video, stored, found string, ok bool) {
+	switch {
+	case ok:
+		return
+	case found != stored:
+		fmt.Printf("%sMoved:%s %q (video ID %d)\n  stored: %s\n  found:  %s\n", consts.ColorYellow, consts.ColorReset, v.URL, v.ID, stored, found)
+	default:
+		fmt.Printf("%sMissing:%s %q (video ID %d)\n  stored: %s\n", consts.ColorRed, consts.ColorReset, v.URL, v.ID, stored)
+	}
+}
+
+// checkPath returns true if the stored path exists, or a unique candidate if the file was renamed.
+func checkPath(stored, dir string, isJSON bool) (string, bool) {
+	if stored == "" {
+		return stored, true
+	}
+	if _, err := os.Stat(stored); err == nil {
+		return stored, true
+	}
+
+	dirs := []string{filepath.Dir(stored)}
+	if dir != "" && dir != dirs[0] {
+		dirs = append(dirs, dir)
+	}
+
+	stem := normalizeStem(stored)
+	if stem == "" {
+		return stored, false
+	}
+
+	var match string
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() || !validExtension(e.Name(), isJSON) {
+				continue
+			}
+			if !strings.Contains(normalizeStem(e.Name()), stem) {
+				continue
+			}
+			if match != "" {
+				return stored, false // Ambiguous
+			}
+			match = filepath.Join(d, e.Name())
+		}
+	}
+
+	if match == "" {
+		return stored, false
+	}
+	return match, false
+}
+
+// normalizeStem lowercases a filename without its extension and strips non-alphanumeric characters.
+func normalizeStem(path string) string {
+	base := filepath.Base(path)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// validExtension checks the file has a video or JSON extension as appropriate.
+func validExtension(name string, isJSON bool) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	if isJSON {
+		return ext == ".json"
+	}
+	for _, v := range consts.AllVidExtensions {
+		if ext == v {
+			return true
+		}
+	}
+	return false
+}
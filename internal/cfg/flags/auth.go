@@ -1,22 +1,51 @@
 package cfgflags
 
 import (
+	"fmt"
+	"strings"
+
 	"tubarr/internal/domain/keys"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // SetAuthFlags sets flags related to channel authorization.
-func SetAuthFlags(cmd *cobra.Command, username, password, loginURL *string) {
+func SetAuthFlags(cmd *cobra.Command, username, password, loginURL, authMethod *string) {
 	if username != nil {
 		cmd.Flags().StringVar(username, keys.AuthUsername, "", "Username for authentication.")
 	}
 
 	if password != nil {
-		cmd.Flags().StringVar(password, keys.AuthPassword, "", "Password for authentication.")
+		cmd.Flags().StringVar(password, keys.AuthPassword, "", "Password for authentication, or where to read it from: 'env:VARNAME', 'file:/run/secrets/name', or 'cmd:name' to use the output of a command named with --"+keys.SecretCommand+".")
 	}
 
 	if loginURL != nil {
 		cmd.Flags().StringVar(loginURL, keys.AuthURL, "", "Login URL for authentication.")
 	}
+
+	if authMethod != nil {
+		cmd.Flags().StringVar(authMethod, keys.AuthMethod, "", "How to log in: 'form' posts the login form, 'browser' logs in through a headless Chrome for sites where that fails.")
+	}
+}
+
+// SecretCommands returns the named commands "cmd:name" secret references may run.
+//
+// Commands come from the config file's secret commands, then --secret-command flags, which replace any of the same name.
+// Names are lowercased, as Viper does for config file keys.
+func SecretCommands(cmd *cobra.Command) (map[string]string, error) {
+	commands := viper.GetStringMapString(keys.SecretCommands)
+
+	entries, err := cmd.Flags().GetStringArray(keys.SecretCommand)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		name, command, ok := strings.Cut(e, "=")
+		if !ok || strings.TrimSpace(name) == "" || strings.TrimSpace(command) == "" {
+			return nil, fmt.Errorf("invalid --%s %q, expected 'name=command'", keys.SecretCommand, e)
+		}
+		commands[strings.ToLower(strings.TrimSpace(name))] = command
+	}
+	return commands, nil
 }
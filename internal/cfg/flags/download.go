@@ -20,6 +20,225 @@ func SetDownloadFlags(cmd *cobra.Command, retries *int, cookieSource, maxFilesiz
 	}
 
 	if dlFilters != nil {
-		cmd.Flags().StringSliceVar(dlFilters, keys.FilterOpsInput, nil, "Filter in or out videos with certain metafields")
+		cmd.Flags().StringSliceVar(dlFilters, keys.FilterOpsInput, nil, "Filter in or out videos with certain metafields (field:contains|omit|regex|omit-regex[:value])")
+	}
+}
+
+// SetYTDLPExtraArgsFlag sets the flag for extra yt-dlp arguments on a channel.
+func SetYTDLPExtraArgsFlag(cmd *cobra.Command, extraArgs *string) {
+	if extraArgs != nil {
+		cmd.Flags().StringVar(extraArgs, keys.YTDLPExtraArgs, "", "Extra yt-dlp arguments for this channel, taking precedence over the global default (e.g. '--socket-timeout 30')")
+	}
+}
+
+// SetConnectionFlags sets flags for yt-dlp fragment and external downloader connection counts.
+func SetConnectionFlags(cmd *cobra.Command, fragments, connections *int) {
+	if fragments != nil {
+		cmd.Flags().IntVar(fragments, keys.ConcurrentFragments, 0, "Number of fragments yt-dlp downloads concurrently per video")
+	}
+	if connections != nil {
+		cmd.Flags().IntVar(connections, keys.ExternalDLConnections, 0, "Connections per download for the external downloader (aria2c or axel)")
+	}
+}
+
+// SetExtractorDiagnosticsFlag sets the flag for diagnosing mass download failures.
+func SetExtractorDiagnosticsFlag(cmd *cobra.Command, diagnostics *bool) {
+	if diagnostics != nil {
+		cmd.Flags().BoolVar(diagnostics, keys.ExtractorDiagnostics, false, "Detect failure spikes and record the yt-dlp version and extractor error to help diagnose yt-dlp breakage")
+	}
+}
+
+// SetPlaylistMatchFlag sets the flag for only expanding playlists with matching titles.
+func SetPlaylistMatchFlag(cmd *cobra.Command, playlistMatch *string) {
+	if playlistMatch != nil {
+		cmd.Flags().StringVar(playlistMatch, keys.PlaylistMatch, "", "For playlist page URLs, only grab videos from playlists whose titles match this case-insensitive glob, where '*' also matches '/' (e.g. 'Lecture*')")
+	}
+}
+
+// SetBlackoutDatesFlag sets the flag for date ranges during which scheduled crawls are skipped.
+func SetBlackoutDatesFlag(cmd *cobra.Command, blackoutDates *[]string) {
+	if blackoutDates != nil {
+		cmd.Flags().StringSliceVar(blackoutDates, keys.BlackoutDates, nil, "Dates or inclusive date ranges to skip scheduled crawls (e.g. '2025-12-20..2026-01-02')")
+	}
+}
+
+// SetURLPatternFlags sets flags for allowing or blocking discovered video URLs by regex.
+func SetURLPatternFlags(cmd *cobra.Command, urlAllow, urlBlock *[]string) {
+	if urlAllow != nil {
+		cmd.Flags().StringSliceVar(urlAllow, keys.URLAllow, nil, "Only grab discovered video URLs matching one of these regex patterns (e.g. '/videos/')")
+	}
+
+	if urlBlock != nil {
+		cmd.Flags().StringSliceVar(urlBlock, keys.URLBlock, nil, "Skip discovered video URLs matching any of these regex patterns (e.g. '/shorts/')")
+	}
+}
+
+// SetRetryMaxAttemptsFlag sets the flag for how many times a failed download is attempted before giving up.
+func SetRetryMaxAttemptsFlag(cmd *cobra.Command, maxAttempts *int) {
+	if maxAttempts != nil {
+		cmd.Flags().IntVar(maxAttempts, keys.RetryMaxAttempts, 3, "Total attempts for a failed download, retried between crawls with exponential backoff (0 disables the retry queue)")
+	}
+}
+
+// SetMaxDownloadsPerCrawlFlag sets the flag capping how many new videos a single crawl downloads.
+func SetMaxDownloadsPerCrawlFlag(cmd *cobra.Command, maxPerCrawl *int) {
+	if maxPerCrawl != nil {
+		cmd.Flags().IntVar(maxPerCrawl, keys.MaxDownloadsPerCrawl, 0, "Most new videos to download in one crawl, spreading large backlogs over several crawls (0 for no limit)")
+	}
+}
+
+// SetMetadataLimitFlags sets the flags skipping videos by their duration and view count before download.
+func SetMetadataLimitFlags(cmd *cobra.Command, minDuration, maxDuration *string, minViews *int) {
+	if minDuration != nil {
+		cmd.Flags().StringVar(minDuration, keys.MinDuration, "", "Skip videos shorter than this (e.g. '90s', '2m'), such as shorts ('0' for no minimum)")
+	}
+	if maxDuration != nil {
+		cmd.Flags().StringVar(maxDuration, keys.MaxDuration, "", "Skip videos longer than this (e.g. '3h', '0' for no maximum)")
+	}
+	if minViews != nil {
+		cmd.Flags().IntVar(minViews, keys.MinViews, 0, "Skip videos with fewer views than this when their metadata is fetched (0 for no minimum)")
+	}
+}
+
+// SetFormatFlags sets the flags choosing which of a video's formats yt-dlp downloads.
+func SetFormatFlags(cmd *cobra.Command, maxResolution, codec *string, audioOnly *bool) {
+	if maxResolution != nil {
+		cmd.Flags().StringVar(maxResolution, keys.MaxResolution, "", "Highest video resolution to download (e.g. '1080', '720p', '4k', '0' for no limit)")
+	}
+	if codec != nil {
+		cmd.Flags().StringVar(codec, keys.PreferredCodec, "", "Video codec to prefer when available (h264, h265, vp9, av1, or 'any')")
+	}
+	if audioOnly != nil {
+		cmd.Flags().BoolVar(audioOnly, keys.AudioOnly, false, "Download only the best audio format of each video")
+	}
+}
+
+// SetSidecarsFlag sets the flag choosing the sidecar files written next to each downloaded video.
+func SetSidecarsFlag(cmd *cobra.Command, sidecars *[]string) {
+	if sidecars != nil {
+		cmd.Flags().StringSliceVar(sidecars, keys.Sidecars, nil, "Sidecar files to write next to each video from its metadata, for Kodi and Jellyfin (nfo, chapters, description)")
+	}
+}
+
+// SetPostProcessorsFlag sets the flag choosing the custom steps run on each video between the download and Metarr.
+func SetPostProcessorsFlag(cmd *cobra.Command, postProcessors *[]string) {
+	if postProcessors != nil {
+		cmd.Flags().StringSliceVar(postProcessors, keys.PostProcessors, nil, "Post-processor plugins to run in order on each downloaded video before Metarr, by name in --"+keys.PostProcessorDir+" or by absolute path")
+	}
+}
+
+// SetTranscribeFlags sets the flags for generating subtitles for a channel's videos with whisper.cpp.
+func SetTranscribeFlags(cmd *cobra.Command, enabled *bool, model, lang *string) {
+	if enabled != nil {
+		cmd.Flags().BoolVar(enabled, keys.Transcribe, false, "Generate .srt subtitles for each downloaded video with whisper.cpp (see --"+keys.WhisperPath+")")
+	}
+	if model != nil {
+		cmd.Flags().StringVar(model, keys.TranscribeModel, "", "whisper.cpp ggml model file used for transcription, such as ggml-base.en.bin")
+	}
+	if lang != nil {
+		cmd.Flags().StringVar(lang, keys.TranscribeLanguage, "", "Spoken language code for transcription, such as 'en', or 'auto' to detect it (default auto)")
+	}
+}
+
+// SetNFOFlags sets the flags for writing Kodi NFO files for a channel and its videos.
+func SetNFOFlags(cmd *cobra.Command, enabled *bool, episodeTemplate, showTemplate *string, fieldMap *[]string, fieldMapFile *string) {
+	if enabled != nil {
+		cmd.Flags().BoolVar(enabled, keys.NFO, false, "Write a tvshow.nfo for the channel and an episode NFO next to each video, for Kodi and Jellyfin")
+	}
+	if episodeTemplate != nil {
+		cmd.Flags().StringVar(episodeTemplate, keys.NFOEpisodeTemplate, "", "Go template file replacing the default episode NFO (fields such as {{xml .Title}}, {{.Aired}})")
+	}
+	if showTemplate != nil {
+		cmd.Flags().StringVar(showTemplate, keys.NFOShowTemplate, "", "Go template file replacing the default tvshow.nfo (fields such as {{xml .Title}}, {{xml .URL}})")
+	}
+	if fieldMap != nil {
+		cmd.Flags().StringSliceVar(fieldMap, keys.NFOFieldMap, nil, "Episode NFO fields filled from metadata fields as nfo-field:metadata-field, read after Metarr's meta ops (e.g. 'plot:clean_description')")
+	}
+	if fieldMapFile != nil {
+		cmd.Flags().StringVar(fieldMapFile, keys.NFOFieldMapFile, "", "File of NFO field mappings, one nfo-field:metadata-field per line (an empty file clears the mappings)")
+	}
+}
+
+// SetMaxRateFlag sets the flag capping the download rate of each of a channel's videos.
+func SetMaxRateFlag(cmd *cobra.Command, maxRate *string) {
+	if maxRate != nil {
+		cmd.Flags().StringVar(maxRate, keys.MaxRate, "", "Download rate cap for each of this channel's videos, within the global max rate (e.g. '2M', '500K')")
+	}
+}
+
+// SetFetcherFlags sets the flags choosing the programs which download a channel's videos.
+func SetFetcherFlags(cmd *cobra.Command, fetcher *string, rules *[]string) {
+	if fetcher != nil {
+		cmd.Flags().StringVar(fetcher, keys.Fetcher, "", "Program downloading this channel's media: 'yt-dlp' (default), 'gallery-dl' for image and gallery sites, or 'streamlink' for livestreams")
+	}
+	if rules != nil {
+		cmd.Flags().StringSliceVar(rules, keys.FetcherRules, nil, "Fetcher for video URLs matching a pattern, checked in order before the channel fetcher (e.g. 'twitch\\.tv/videos:streamlink')")
+	}
+}
+
+// SetSponsorBlockFlags sets the flags choosing SponsorBlock segment categories to cut from or mark in videos.
+func SetSponsorBlockFlags(cmd *cobra.Command, remove, mark *string) {
+	if remove != nil {
+		cmd.Flags().StringVar(remove, keys.SponsorBlockRemove, "", "SponsorBlock categories to cut from videos, comma separated (e.g. 'sponsor,selfpromo', 'all,-filler')")
+	}
+	if mark != nil {
+		cmd.Flags().StringVar(mark, keys.SponsorBlockMark, "", "SponsorBlock categories to mark as chapters in videos, comma separated")
+	}
+}
+
+// SetQuotaFlags sets the flags capping a channel's total disk usage, and whether the oldest videos are pruned to stay under it.
+func SetQuotaFlags(cmd *cobra.Command, maxTotal *string, prune *bool) {
+	if maxTotal != nil {
+		cmd.Flags().StringVar(maxTotal, keys.MaxTotalSize, "", "Disk usage cap for this channel's downloaded files (e.g. '50G'), new downloads are skipped once reached")
+	}
+	if prune != nil {
+		cmd.Flags().BoolVar(prune, keys.QuotaPrune, false, "Delete the channel's oldest downloaded videos to make room instead of skipping new downloads at the max total size")
+	}
+}
+
+// SetRetentionFlags sets the flags for deleting a channel's older downloads, and notifying media servers afterwards.
+func SetRetentionFlags(cmd *cobra.Command, keepLast, keepDays *int, notify *bool) {
+	if keepLast != nil {
+		cmd.Flags().IntVar(keepLast, keys.KeepLast, 0, "Keep only this channel's newest N downloaded videos, deleting older ones after each crawl (0 keeps all)")
+	}
+	if keepDays != nil {
+		cmd.Flags().IntVar(keepDays, keys.KeepDays, 0, "Delete this channel's videos downloaded more than this many days ago after each crawl (0 keeps all)")
+	}
+	if notify != nil {
+		cmd.Flags().BoolVar(notify, keys.RetentionNotify, false, "Call the channel's notification URLs (e.g. Plex or Jellyfin library refreshes) after deleting old videos")
+	}
+}
+
+// SetProxyFlag sets the flag routing a channel's downloads and HTTP requests through proxies.
+func SetProxyFlag(cmd *cobra.Command, proxies *[]string) {
+	if proxies != nil {
+		cmd.Flags().StringSliceVar(proxies, keys.Proxy, nil, "HTTP or SOCKS5 proxies for this channel, rotated between requests (e.g. 'socks5://127.0.0.1:1080'), overrides the global proxies")
+	}
+}
+
+// SetFallbackFlags sets the proxy and cookie source a channel switches to while its host is bot blocking it.
+func SetFallbackFlags(cmd *cobra.Command, fallbackProxy, fallbackCookieSource *string) {
+	if fallbackProxy != nil {
+		cmd.Flags().StringVar(fallbackProxy, keys.FallbackProxy, "", "Proxy to switch to while this channel's host is bot blocking it, until a download succeeds")
+	}
+	if fallbackCookieSource != nil {
+		cmd.Flags().StringVar(fallbackCookieSource, keys.FallbackCookieSource, "", "Cookie source to switch to while this channel's host is bot blocking it (e.g. 'firefox:other-profile')")
+	}
+}
+
+// SetLiveFlags sets the flags for watching a channel's live page and capturing its streams as they start.
+func SetLiveFlags(cmd *cobra.Command, liveURL *string, checkFreq *int) {
+	if liveURL != nil {
+		cmd.Flags().StringVar(liveURL, keys.LiveURL, "", "Page showing this channel's current livestream (e.g. 'https://www.youtube.com/@name/live'), watched by the scheduler to capture streams from the start")
+	}
+	if checkFreq != nil {
+		cmd.Flags().IntVar(checkFreq, keys.LiveCheckFreq, 0, "Minutes between checks of the live URL (default 2)")
+	}
+}
+
+// SetPostsURLFlag sets the flag for a gallery-dl supported feed of a channel's posts to archive.
+func SetPostsURLFlag(cmd *cobra.Command, postsURL *string) {
+	if postsURL != nil {
+		cmd.Flags().StringVar(postsURL, keys.PostsURL, "", "Feed of this channel's community or text/image posts to archive after each crawl, must be supported by gallery-dl")
 	}
 }
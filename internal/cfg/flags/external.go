@@ -7,6 +7,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// SetSkipMetarrFlag sets the flag for bypassing Metarr entirely.
+func SetSkipMetarrFlag(cmd *cobra.Command, skipMetarr *bool) {
+	if skipMetarr != nil {
+		cmd.Flags().BoolVar(skipMetarr, keys.SkipMetarr, false, "Skip Metarr and move downloaded files directly to the output directory")
+	}
+}
+
 // SetMetarrFlags sets flags for interaction with the Metarr software.
 func SetMetarrFlags(cmd *cobra.Command, maxCPUPtr *float64, metarrConcurrencyPtr *int, extPtr, filenameDateTagPtr, minFreeMemPtr, outDirPtr, renameStylePtr *string, fileSfxReplacePtr, metaOpsPtr *[]string) models.MetarrArgs {
 	var (
@@ -43,10 +50,10 @@ func SetMetarrFlags(cmd *cobra.Command, maxCPUPtr *float64, metarrConcurrencyPtr
 
 	// Arrays
 	if fileSfxReplacePtr != nil {
-		cmd.Flags().StringSliceVar(fileSfxReplacePtr, keys.FilenameReplaceSuffix, nil, "Replace a filename suffix element in Metarr")
+		cmd.Flags().StringSliceVar(fileSfxReplacePtr, keys.FilenameReplaceSuffix, nil, "Replace a filename suffix element in Metarr (suffix:replacement), or rename by regex (regex:pattern:replacement)")
 	}
 	if metaOpsPtr != nil {
-		cmd.Flags().StringSliceVar(metaOpsPtr, keys.MetaOps, nil, "Meta operations to perform in Metarr")
+		cmd.Flags().StringSliceVar(metaOpsPtr, keys.MetaOps, nil, "Meta operations to perform in Metarr, including field:regex:pattern:replacement")
 	}
 
 	return models.MetarrArgs{
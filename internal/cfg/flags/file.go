@@ -29,7 +29,7 @@ func InitAllFileTransformers(rootCmd *cobra.Command) error {
 	}
 
 	// Replace filename suffix
-	rootCmd.PersistentFlags().StringSlice(keys.FilenameReplaceSuffix, nil, "Replaces a specified suffix on filenames. (suffix:replacement)")
+	rootCmd.PersistentFlags().StringSlice(keys.FilenameReplaceSuffix, nil, "Replaces a specified suffix on filenames (suffix:replacement), or renames by regex (regex:pattern:replacement)")
 	if err := viper.BindPFlag(keys.FilenameReplaceSuffix, rootCmd.PersistentFlags().Lookup(keys.FilenameReplaceSuffix)); err != nil {
 		return err
 	}
@@ -53,6 +53,18 @@ func SetFileDirFlags(cmd *cobra.Command, jsonDir, videoDir *string) {
 	}
 }
 
+// SetStagingDirFlag sets the directory videos are downloaded and processed in before moving to the video directory.
+func SetStagingDirFlag(cmd *cobra.Command, stagingDir *string) {
+	cmd.Flags().StringVar(stagingDir, keys.StagingDir, "", "Download and process videos here, then move them into the video directory once complete (some {{}} templating commands available)")
+}
+
+// SetOutputTemplateFlag sets the template organizing downloaded videos inside the video directory.
+func SetOutputTemplateFlag(cmd *cobra.Command, outputTemplate *string) {
+	cmd.Flags().StringVar(outputTemplate, keys.OutputTemplate, "", "Path of each video inside the video directory, without extension "+
+		"(e.g. '{{channel}}/Season {{season}}/{{channel}} - S{{season}}E{{episode}} - {{video_title}}'). "+
+		"Season is the upload year, episode the upload month and day then an index for same-day uploads")
+}
+
 // InitVideoTransformers initializes user flag settings for transformation of video files.
 func InitVideoTransformers(rootCmd *cobra.Command) error {
 
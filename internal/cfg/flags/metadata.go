@@ -11,7 +11,7 @@ import (
 func InitMetaTransformers(rootCmd *cobra.Command) error {
 
 	// Metadata transformations
-	rootCmd.PersistentFlags().StringSlice(keys.MetaOps, nil, "Metadata operations (field:operation:value) - e.g. title:set:New Title, description:prefix:Draft-, tags:append:newtag")
+	rootCmd.PersistentFlags().StringSlice(keys.MetaOps, nil, "Metadata operations (field:operation:value) - e.g. title:set:New Title, description:prefix:Draft-, tags:append:newtag, title:regex:Episode (\\d+):Ep. $1")
 	if err := viper.BindPFlag(keys.MetaOps, rootCmd.PersistentFlags().Lookup(keys.MetaOps)); err != nil {
 		return err
 	}
@@ -33,3 +33,10 @@ func InitMetaTransformers(rootCmd *cobra.Command) error {
 	}
 	return nil
 }
+
+// SetExternalIDsFlag sets the flag for media server IDs (TVDB, TMDB, IMDb) identifying the channel.
+func SetExternalIDsFlag(cmd *cobra.Command, externalIDs *[]string) {
+	if externalIDs != nil {
+		cmd.Flags().StringSliceVar(externalIDs, keys.ExternalIDs, nil, "Media server IDs as provider:id, usable in directory templates as {{tvdb_id}} etc. (e.g. 'tvdb:81189,imdb:tt0903747')")
+	}
+}
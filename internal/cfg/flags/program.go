@@ -2,7 +2,12 @@
 package cfgflags
 
 import (
+	"time"
+
 	"tubarr/internal/domain/keys"
+	"tubarr/internal/postprocess"
+	"tubarr/internal/utils/remote"
+	"tubarr/internal/utils/whisper"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -11,6 +16,15 @@ import (
 // InitProgramFlags initializes user flag settings related to the core program. E.g. logging level.
 func InitProgramFlags(rootCmd *cobra.Command) error {
 
+	// Reject commands which modify the database
+	rootCmd.PersistentFlags().Bool(keys.ReadOnly, false, "Open the database read-only and reject commands which modify it (e.g. for dashboards against a shared database), also set by "+ReadOnlyEnv)
+	if err := viper.BindPFlag(keys.ReadOnly, rootCmd.PersistentFlags().Lookup(keys.ReadOnly)); err != nil {
+		return err
+	}
+	if err := viper.BindEnv(keys.ReadOnly, ReadOnlyEnv); err != nil {
+		return err
+	}
+
 	// Output benchmarking files
 	rootCmd.PersistentFlags().Bool(keys.Benchmarking, false, "Benchmarks the program")
 	if err := viper.BindPFlag(keys.Benchmarking, rootCmd.PersistentFlags().Lookup(keys.Benchmarking)); err != nil {
@@ -22,6 +36,153 @@ func InitProgramFlags(rootCmd *cobra.Command) error {
 	if err := viper.BindPFlag(keys.DebugLevel, rootCmd.PersistentFlags().Lookup(keys.DebugLevel)); err != nil {
 		return err
 	}
+
+	// Auto-tune per-host concurrency
+	rootCmd.PersistentFlags().Bool(keys.AutoTuneHosts, false, "Automatically lower concurrency and add delays for hosts with high failure rates")
+	if err := viper.BindPFlag(keys.AutoTuneHosts, rootCmd.PersistentFlags().Lookup(keys.AutoTuneHosts)); err != nil {
+		return err
+	}
+
+	// Bot block backoff
+	rootCmd.PersistentFlags().Duration(keys.BotBlockBackoff, 15*time.Minute, "How long to pause a host's downloads after repeated bot blocks, doubling with each further block up to a day (0 to disable)")
+	if err := viper.BindPFlag(keys.BotBlockBackoff, rootCmd.PersistentFlags().Lookup(keys.BotBlockBackoff)); err != nil {
+		return err
+	}
+
+	// Global extra yt-dlp arguments
+	rootCmd.PersistentFlags().String(keys.YTDLPExtraArgs, "", "Default extra yt-dlp arguments for all channels, overridden per flag by channel extra args (e.g. '--socket-timeout 30')")
+	if err := viper.BindPFlag(keys.YTDLPExtraArgs, rootCmd.PersistentFlags().Lookup(keys.YTDLPExtraArgs)); err != nil {
+		return err
+	}
+
+	// yt-dlp plugins
+	rootCmd.PersistentFlags().String(keys.YTDLPPluginDir, "", "Directory containing a yt_dlp_plugins package with custom extractors, passed to every yt-dlp run")
+	if err := viper.BindPFlag(keys.YTDLPPluginDir, rootCmd.PersistentFlags().Lookup(keys.YTDLPPluginDir)); err != nil {
+		return err
+	}
+
+	// Proxies
+	rootCmd.PersistentFlags().StringSlice(keys.Proxy, nil, "HTTP or SOCKS5 proxies for channels without their own, rotated between requests (e.g. 'http://127.0.0.1:8080')")
+	if err := viper.BindPFlag(keys.Proxy, rootCmd.PersistentFlags().Lookup(keys.Proxy)); err != nil {
+		return err
+	}
+
+	// Bandwidth
+	rootCmd.PersistentFlags().String(keys.MaxRate, "", "Total download rate shared by all concurrent video downloads (e.g. '4M')")
+	if err := viper.BindPFlag(keys.MaxRate, rootCmd.PersistentFlags().Lookup(keys.MaxRate)); err != nil {
+		return err
+	}
+
+	// Per-host request spacing
+	rootCmd.PersistentFlags().StringSlice(keys.HostRateLimit, nil, "Requests per minute for metadata fetches and downloads from a host and its subdomains, shared by all channels (e.g. 'youtube.com=6'), a bare number applies to every other host")
+	if err := viper.BindPFlag(keys.HostRateLimit, rootCmd.PersistentFlags().Lookup(keys.HostRateLimit)); err != nil {
+		return err
+	}
+
+	// Skipped video recording
+	rootCmd.PersistentFlags().Bool(keys.RecordSkips, false, "Record videos skipped by filters, URL patterns, duration and view limits, or yt-dlp date windows, with the reason")
+	if err := viper.BindPFlag(keys.RecordSkips, rootCmd.PersistentFlags().Lookup(keys.RecordSkips)); err != nil {
+		return err
+	}
+
+	rootCmd.PersistentFlags().Int(keys.SkipRetentionDays, 30, "Days to keep skipped video records")
+	if err := viper.BindPFlag(keys.SkipRetentionDays, rootCmd.PersistentFlags().Lookup(keys.SkipRetentionDays)); err != nil {
+		return err
+	}
+
+	// Channel event history
+	rootCmd.PersistentFlags().Int(keys.EventRetentionDays, 90, "Days to keep channel events such as crawls and bot blocks in the activity history (0 to keep forever)")
+	if err := viper.BindPFlag(keys.EventRetentionDays, rootCmd.PersistentFlags().Lookup(keys.EventRetentionDays)); err != nil {
+		return err
+	}
+
+	// Truncated download detection
+	rootCmd.PersistentFlags().Float64(keys.DurationTolerance, 5, "Seconds a downloaded video may fall short of its metadata duration before it is treated as truncated and retried (-1 to disable)")
+	if err := viper.BindPFlag(keys.DurationTolerance, rootCmd.PersistentFlags().Lookup(keys.DurationTolerance)); err != nil {
+		return err
+	}
+
+	// HTTP endpoints
+	rootCmd.PersistentFlags().String(keys.HTTPAddr, "", "Address for the scheduler to serve health checks and downloaded videos on (e.g. ':8081')")
+	if err := viper.BindPFlag(keys.HTTPAddr, rootCmd.PersistentFlags().Lookup(keys.HTTPAddr)); err != nil {
+		return err
+	}
+
+	rootCmd.PersistentFlags().String(keys.APIToken, "", "Session token for commands sent to a running instance's HTTP API, once users are added (default $"+remote.TokenEnv+")")
+	if err := viper.BindPFlag(keys.APIToken, rootCmd.PersistentFlags().Lookup(keys.APIToken)); err != nil {
+		return err
+	}
+
+	// Shutdown
+	rootCmd.PersistentFlags().Duration(keys.ShutdownGrace, 0, "On SIGINT or SIGTERM, time to let downloads in progress finish before they are stopped and checkpointed for resume, while no new work starts (0 to stop at once)")
+	if err := viper.BindPFlag(keys.ShutdownGrace, rootCmd.PersistentFlags().Lookup(keys.ShutdownGrace)); err != nil {
+		return err
+	}
+
+	// Crawl digest
+	rootCmd.PersistentFlags().String(keys.DigestWebhook, "", "URL to POST a JSON summary to after each crawl pass which downloaded, failed, or filtered videos, or had channels fail")
+	if err := viper.BindPFlag(keys.DigestWebhook, rootCmd.PersistentFlags().Lookup(keys.DigestWebhook)); err != nil {
+		return err
+	}
+
+	rootCmd.PersistentFlags().StringSlice(keys.DigestEmail, nil, "Addresses to email the crawl pass summary to, using the SMTP settings from 'tubarr notify smtp set'")
+	if err := viper.BindPFlag(keys.DigestEmail, rootCmd.PersistentFlags().Lookup(keys.DigestEmail)); err != nil {
+		return err
+	}
+
+	// Post-processor plugins
+	rootCmd.PersistentFlags().String(keys.PostProcessorDir, "", "Directory of post-processor plugins, defaulting to 'plugins' in the Tubarr config directory")
+	if err := viper.BindPFlag(keys.PostProcessorDir, rootCmd.PersistentFlags().Lookup(keys.PostProcessorDir)); err != nil {
+		return err
+	}
+
+	rootCmd.PersistentFlags().Duration(keys.PostProcessorTimeout, postprocess.DefaultTimeout, "Time after which a post-processor plugin run is killed and the video fails")
+	if err := viper.BindPFlag(keys.PostProcessorTimeout, rootCmd.PersistentFlags().Lookup(keys.PostProcessorTimeout)); err != nil {
+		return err
+	}
+
+	// Transcription
+	rootCmd.PersistentFlags().String(keys.WhisperPath, whisper.DefaultBin, "whisper.cpp command line tool used to transcribe videos, searched for in PATH if not a path")
+	if err := viper.BindPFlag(keys.WhisperPath, rootCmd.PersistentFlags().Lookup(keys.WhisperPath)); err != nil {
+		return err
+	}
+
+	// Apprise notifications
+	rootCmd.PersistentFlags().String(keys.AppriseBin, "", "Apprise command line tool for notification services not sent directly, searched for in PATH if unset")
+	if err := viper.BindPFlag(keys.AppriseBin, rootCmd.PersistentFlags().Lookup(keys.AppriseBin)); err != nil {
+		return err
+	}
+
+	rootCmd.PersistentFlags().StringSlice(keys.AppriseURLs, nil, "Apprise URLs to notify of every channel's downloaded videos (e.g. 'tgram://bottoken/chatid')")
+	if err := viper.BindPFlag(keys.AppriseURLs, rootCmd.PersistentFlags().Lookup(keys.AppriseURLs)); err != nil {
+		return err
+	}
+
+	// Browser login
+	rootCmd.PersistentFlags().String(keys.ChromePath, "", "Chrome or Chromium executable for channels using browser login, searched for in PATH if unset")
+	if err := viper.BindPFlag(keys.ChromePath, rootCmd.PersistentFlags().Lookup(keys.ChromePath)); err != nil {
+		return err
+	}
+
+	// Secret commands, never read from the database so stored or imported values can't run commands
+	rootCmd.PersistentFlags().StringArray(keys.SecretCommand, nil, "Named command whose output 'cmd:name' passwords use, such as a password manager's CLI, added to '"+keys.SecretCommands+"' in the config file (e.g. 'site=pass show site')")
+
+	// Crawl worker pool
+	rootCmd.PersistentFlags().Int(keys.CrawlConcurrency, 0, "Maximum channels crawled at once, defaults to the concurrency limit")
+	if err := viper.BindPFlag(keys.CrawlConcurrency, rootCmd.PersistentFlags().Lookup(keys.CrawlConcurrency)); err != nil {
+		return err
+	}
+
+	rootCmd.PersistentFlags().Int(keys.CrawlHostConcurrency, 0, "Maximum channels on the same host crawled at once (0 for no limit), lowered further by host auto-tuning")
+	if err := viper.BindPFlag(keys.CrawlHostConcurrency, rootCmd.PersistentFlags().Lookup(keys.CrawlHostConcurrency)); err != nil {
+		return err
+	}
+
+	// Mount availability marker
+	rootCmd.PersistentFlags().String(keys.MountMarker, "", "Filename which must exist at or above output directories before crawling (e.g. at the root of a NAS mount)")
+	if err := viper.BindPFlag(keys.MountMarker, rootCmd.PersistentFlags().Lookup(keys.MountMarker)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -40,3 +201,16 @@ func SetProgramRelatedFlags(cmd *cobra.Command, concurrency, crawlFreq *int, dow
 		cmd.Flags().StringVar(downloadArgs, "downloader-args", "", "External downloader arguments")
 	}
 }
+
+// SetScheduleFlags sets flags for cron crawl schedules, quiet hours, and jitter.
+func SetScheduleFlags(cmd *cobra.Command, crawlCron, quietHours *string, jitter *int) {
+	if crawlCron != nil {
+		cmd.Flags().StringVar(crawlCron, keys.CrawlCron, "", "Cron expression for when to crawl, replacing the crawl frequency (e.g. '0 */6 * * *' or '@daily')")
+	}
+	if quietHours != nil {
+		cmd.Flags().StringVar(quietHours, keys.QuietHours, "", "Daily window in which crawls don't start (e.g. '22:00-07:00')")
+	}
+	if jitter != nil {
+		cmd.Flags().IntVar(jitter, keys.CrawlJitter, 0, "Delay scheduled crawls by up to this many minutes, to spread load")
+	}
+}
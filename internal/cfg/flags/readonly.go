@@ -0,0 +1,90 @@
+package cfgflags
+
+import (
+	"fmt"
+	"os"
+
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/utils/remote"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	readOnlySafe = "read-only-safe"
+	remoteSafe   = "remote-safe"
+)
+
+// ReadOnlyEnv is the environment variable enabling read-only mode, like --read-only.
+const ReadOnlyEnv = "TUBARR_READ_ONLY"
+
+// MarkReadOnlySafe marks a command as safe to run in read-only mode.
+func MarkReadOnlySafe(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[readOnlySafe] = "true"
+	return cmd
+}
+
+// MarkRemoteSafe marks a command as able to run through a running instance, checking RemoteAddr and QueueCommands.
+func MarkRemoteSafe(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[remoteSafe] = "true"
+	return cmd
+}
+
+// RemoteAddr returns the HTTP address of the running instance commands are sent to, or blank if the
+// database is used directly.
+func RemoteAddr() string {
+	return viper.GetString(keys.RemoteAddr)
+}
+
+// QueueCommands reports whether commands are queued for a running scheduler which doesn't serve HTTP.
+func QueueCommands() bool {
+	return viper.GetBool(keys.QueueCommands)
+}
+
+// APIToken returns the session token for commands sent to a running instance.
+func APIToken() string {
+	if token := viper.GetString(keys.APIToken); token != "" {
+		return token
+	}
+	return os.Getenv(remote.TokenEnv)
+}
+
+// CheckReadOnly returns an error if the command modifies the database and read-only mode is enabled.
+//
+// While another instance is running, commands it can run through its HTTP API or command queue are allowed.
+func CheckReadOnly(cmd *cobra.Command) error {
+	if !viper.GetBool(keys.ReadOnly) || !cmd.Runnable() {
+		return nil
+	}
+	if cmd.Annotations[readOnlySafe] == "true" {
+		return nil
+	}
+	if addr := RemoteAddr(); addr != "" {
+		if cmd.Annotations[remoteSafe] == "true" {
+			return nil
+		}
+		return fmt.Errorf("%q modifies the database, which is in use by the Tubarr instance serving %s. Stop it to run this command", cmd.CommandPath(), addr)
+	}
+	if QueueCommands() {
+		if cmd.Annotations[remoteSafe] == "true" {
+			return nil
+		}
+		return fmt.Errorf("%q modifies the database, which is in use by the running Tubarr scheduler. Stop it to run this command", cmd.CommandPath())
+	}
+	return fmt.Errorf("%q modifies the database and is disabled in read-only mode", cmd.CommandPath())
+}
+
+// CheckReadOnlyFlag returns an error if a flag which modifies the database is set in read-only mode.
+func CheckReadOnlyFlag(cmd *cobra.Command, flag string) error {
+	if viper.GetBool(keys.ReadOnly) && cmd.Flags().Changed(flag) {
+		return fmt.Errorf("%q with --%s modifies the database and is disabled in read-only mode", cmd.CommandPath(), flag)
+	}
+	return nil
+}
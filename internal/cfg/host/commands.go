@@ -0,0 +1,73 @@
+// Package cfghost sets up Cobra host commands.
+package cfghost
+
+import (
+	"errors"
+	"fmt"
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitHostCmds is the entrypoint for initializing host commands.
+func InitHostCmds(s interfaces.Store) *cobra.Command {
+	hostCmd := &cobra.Command{
+		Use:   "host",
+		Short: "Host commands.",
+		Long:  "View and manage per-hostname download statistics, tuning, and pauses.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	hs := s.HostStore()
+
+	// Add subcommands with dependencies
+	hostCmd.AddCommand(cfgflags.MarkReadOnlySafe(hostStatsCmd(hs)))
+	hostCmd.AddCommand(hostPauseCmd(hs))
+	hostCmd.AddCommand(hostResumeCmd(hs))
+	hostCmd.AddCommand(cfgflags.MarkReadOnlySafe(hostPausesCmd(hs)))
+
+	return hostCmd
+}
+
+// hostStatsCmd lists recorded results and current effective values per hostname.
+func hostStatsCmd(hs interfaces.HostStore) *cobra.Command {
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "List host statistics.",
+		Long:  "Lists recent download results and the current auto-tuned concurrency and delay for each hostname.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stats, err := hs.FetchAllHostStats()
+			if err != nil {
+				return err
+			}
+
+			if len(stats) == 0 {
+				logging.I("No host statistics recorded yet")
+				return nil
+			}
+
+			for _, h := range stats {
+				total := h.Successes + h.Failures
+				var failRate float64
+				if total > 0 {
+					failRate = float64(h.Failures) / float64(total) * 100
+				}
+
+				conc := "configured"
+				if h.Concurrency > 0 {
+					conc = fmt.Sprintf("%d", h.Concurrency)
+				}
+
+				fmt.Printf("\n%sHost: %s%s\nSuccesses: %d\nFailures: %d (%.0f%%)\nBot Blocks: %d (%d in a row, %d since last tuned)\n", consts.ColorGreen, h.Hostname, consts.ColorReset, h.Successes, h.Failures, failRate, h.BotBlocks, h.BlockStreak, h.NewBlocks)
+				fmt.Printf("Effective Concurrency: %s\nEffective Delay: %v\nLast Updated: %s\n", conc, h.Delay, h.UpdatedAt.Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		},
+	}
+	return statsCmd
+}
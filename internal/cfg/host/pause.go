@@ -0,0 +1,186 @@
+package cfghost
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitPauseCmds returns the root-level commands which pause and resume all downloads.
+func InitPauseCmds(s interfaces.Store) []*cobra.Command {
+	hs := s.HostStore()
+	return []*cobra.Command{
+		pauseAllCmd(hs),
+		resumeAllCmd(hs),
+	}
+}
+
+// pauseAllCmd stops new downloads from starting for every host.
+func pauseAllCmd(hs interfaces.HostStore) *cobra.Command {
+	var (
+		duration time.Duration
+		reason   string
+	)
+
+	pauseCmd := &cobra.Command{
+		Use:   "pause-all",
+		Short: "Pause all downloads.",
+		Long: "Stops new crawls, downloads, and retries from starting for every host, in this and any running Tubarr process. " +
+			"Downloads already in progress are allowed to finish.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setPause(hs, consts.PauseAllScope, duration, reason)
+		},
+	}
+
+	setPauseFlags(pauseCmd, &duration, &reason)
+	return pauseCmd
+}
+
+// resumeAllCmd lifts the global pause, host pauses stay in effect.
+func resumeAllCmd(hs interfaces.HostStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume-all",
+		Short: "Resume all downloads.",
+		Long:  "Lifts the pause set with 'pause-all'. Pauses for individual hosts stay in effect.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return clearPause(hs, consts.PauseAllScope)
+		},
+	}
+}
+
+// hostPauseCmd stops new downloads from starting for a hostname and its subdomains.
+func hostPauseCmd(hs interfaces.HostStore) *cobra.Command {
+	var (
+		duration time.Duration
+		reason   string
+	)
+
+	pauseCmd := &cobra.Command{
+		Use:   "pause <hostname>",
+		Short: "Pause downloads from a host.",
+		Long: "Stops new crawls, downloads, and retries from starting for a hostname and its subdomains (e.g. 'youtube.com' covers 'www.youtube.com'). " +
+			"Downloads already in progress are allowed to finish.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			host, err := pauseHostname(args[0])
+			if err != nil {
+				return err
+			}
+			return setPause(hs, host, duration, reason)
+		},
+	}
+
+	setPauseFlags(pauseCmd, &duration, &reason)
+	return pauseCmd
+}
+
+// hostResumeCmd lifts a host's pause.
+func hostResumeCmd(hs interfaces.HostStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume <hostname>",
+		Short: "Resume downloads from a host.",
+		Long:  "Lifts the pause set with 'host pause' for a hostname.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			host, err := pauseHostname(args[0])
+			if err != nil {
+				return err
+			}
+			return clearPause(hs, host)
+		},
+	}
+}
+
+// hostPausesCmd lists the pauses currently in effect.
+func hostPausesCmd(hs interfaces.HostStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pauses",
+		Short: "List download pauses.",
+		Long:  "Lists the global and per-host download pauses currently in effect.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pauses, err := hs.FetchPauses()
+			if err != nil {
+				return err
+			}
+			if len(pauses) == 0 {
+				logging.I("No downloads are paused")
+				return nil
+			}
+			for _, p := range pauses {
+				fmt.Printf("%sPaused:%s %s, since %s\n", consts.ColorYellow, consts.ColorReset, p.Describe(), p.CreatedAt.Local().Format(time.DateTime))
+			}
+			return nil
+		},
+	}
+}
+
+// setPauseFlags sets the pause duration and reason flags.
+func setPauseFlags(cmd *cobra.Command, duration *time.Duration, reason *string) {
+	cmd.Flags().DurationVar(duration, "for", 0, "How long to pause for (e.g. '24h', '90m'), pauses until resumed if not set")
+	cmd.Flags().StringVar(reason, "reason", "", "Note on why downloads are paused, shown in logs")
+}
+
+// setPause saves a pause for the scope.
+func setPause(hs interfaces.HostStore, scope string, duration time.Duration, reason string) error {
+	if duration < 0 {
+		return fmt.Errorf("pause duration cannot be negative, got %v", duration)
+	}
+
+	var until time.Time
+	if duration > 0 {
+		until = time.Now().Add(duration)
+	}
+	if err := hs.SetPause(scope, until, reason); err != nil {
+		return err
+	}
+
+	p := models.Pause{Scope: scope, Until: until, Reason: reason}
+	logging.S(0, "Paused downloads for %s", p.Describe())
+	return nil
+}
+
+// clearPause removes the pause for the scope.
+func clearPause(hs interfaces.HostStore, scope string) error {
+	cleared, err := hs.ClearPause(scope)
+	if err != nil {
+		return err
+	}
+
+	name := scope
+	if scope == consts.PauseAllScope {
+		name = "all hosts"
+	}
+	if !cleared {
+		logging.I("Downloads were not paused for %s", name)
+		return nil
+	}
+	logging.S(0, "Resumed downloads for %s", name)
+	return nil
+}
+
+// pauseHostname normalizes a hostname or URL to a lowercase hostname.
+func pauseHostname(arg string) (string, error) {
+	host := strings.TrimSpace(arg)
+	if strings.Contains(host, "://") {
+		u, err := url.Parse(host)
+		if err != nil {
+			return "", fmt.Errorf("invalid URL %q: %w", arg, err)
+		}
+		host = u.Hostname()
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	if host == "" || host == consts.PauseAllScope || strings.ContainsAny(host, "/ ") {
+		return "", errors.New("please enter a hostname such as 'youtube.com', or use 'pause-all' for every host")
+	}
+	return host, nil
+}
@@ -0,0 +1,131 @@
+// Package cfgignore sets up Cobra commands for the global ignore list.
+package cfgignore
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitIgnoreCmds is the entrypoint for initializing global ignore commands.
+func InitIgnoreCmds(s interfaces.Store) *cobra.Command {
+	ignoreCmd := &cobra.Command{
+		Use:   "ignore",
+		Short: "Global ignore list commands.",
+		Long: "Manage videos skipped by every channel's crawls: exact URLs, URL patterns, and title patterns. " +
+			"These apply in addition to each channel's own ignore list ('tubarr channel ignore-url').",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	cs := s.ChannelStore()
+	ignoreCmd.AddCommand(addCmd(cs))
+	ignoreCmd.AddCommand(cfgflags.MarkReadOnlySafe(listCmd(cs)))
+	ignoreCmd.AddCommand(deleteCmd(cs))
+	return ignoreCmd
+}
+
+// addCmd adds global ignore rules.
+func addCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		urls, patterns, titles []string
+		note                   string
+	)
+
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add to the global ignore list.",
+		Long: "Ignores videos in every channel by exact URL, by regex matched against the URL, or by regex matched against the title. " +
+			"Title patterns are checked once a video's metadata is downloaded, use '(?i)' for case insensitive matches.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(urls)+len(patterns)+len(titles) == 0 {
+				return errors.New("please enter at least one --url, --pattern, or --title to ignore")
+			}
+
+			var rules []*models.GlobalIgnore
+			for _, u := range urls {
+				rules = append(rules, &models.GlobalIgnore{Kind: consts.IgnoreURL, Value: u, Note: note})
+			}
+			for _, p := range patterns {
+				rules = append(rules, &models.GlobalIgnore{Kind: consts.IgnorePattern, Value: p, Note: note})
+			}
+			for _, t := range titles {
+				rules = append(rules, &models.GlobalIgnore{Kind: consts.IgnoreTitle, Value: t, Note: note})
+			}
+
+			for _, r := range rules {
+				added, err := cs.AddGlobalIgnore(r)
+				if err != nil {
+					return err
+				}
+				if !added {
+					logging.I("%s %q is already in the global ignore list", r.Kind, r.Value)
+				}
+			}
+			return nil
+		},
+	}
+
+	addCmd.Flags().StringArrayVar(&urls, "url", nil, "Video URL to ignore in every channel (repeatable)")
+	addCmd.Flags().StringArrayVar(&patterns, "pattern", nil, "Regex pattern of video URLs to ignore, e.g. '/shorts/' (repeatable)")
+	addCmd.Flags().StringArrayVar(&titles, "title", nil, "Regex pattern of video titles to ignore, e.g. '(?i)\\blive\\b' (repeatable)")
+	addCmd.Flags().StringVar(&note, "note", "", "Note on why the entries are ignored, shown when listed")
+	return addCmd
+}
+
+// listCmd prints the global ignore list.
+func listCmd(cs interfaces.ChannelStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the global ignore list.",
+		Long:  "Prints every global ignore rule with its ID, for use with 'tubarr ignore delete'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ignores, err := cs.GetGlobalIgnores()
+			if err != nil {
+				return err
+			}
+			if len(ignores) == 0 {
+				logging.I("Global ignore list is empty, add to it with 'tubarr ignore add'")
+				return nil
+			}
+
+			for _, g := range ignores {
+				fmt.Printf("%d: %s %q [added %s]", g.ID, g.Kind, g.Value, g.CreatedAt.Format(time.DateOnly))
+				if g.Note != "" {
+					fmt.Printf(" %s", g.Note)
+				}
+				fmt.Println()
+			}
+			return nil
+		},
+	}
+}
+
+// deleteCmd deletes global ignore rules by ID.
+func deleteCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var ids []int64
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete from the global ignore list.",
+		Long:  "Deletes global ignore rules by the IDs shown by 'tubarr ignore list'. Videos they skipped are found again by the next crawl.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(ids) == 0 {
+				return errors.New("please enter at least one ignore rule ID with --id")
+			}
+			return cs.DeleteGlobalIgnores(ids)
+		},
+	}
+
+	deleteCmd.Flags().Int64SliceVar(&ids, "id", nil, "IDs of the ignore rules to delete")
+	return deleteCmd
+}
@@ -0,0 +1,308 @@
+// Package cfgnotify sets up Cobra global notification commands.
+package cfgnotify
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/events"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/email"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitNotifyCmds is the entrypoint for initializing global notification commands.
+func InitNotifyCmds(s interfaces.Store) *cobra.Command {
+	notifyCmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Global notification commands.",
+		Long:  "Manage notification settings shared by every channel, such as the mail server used for email notifications and digests, and event hook scripts.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	smtpCmd := &cobra.Command{
+		Use:   "smtp",
+		Short: "SMTP mail server commands.",
+		Long: "Manage the mail server for email notifications. Channels email downloaded videos to 'mailto:address' notification URLs, " +
+			"and --digest-email sends crawl summaries.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	cs := s.ChannelStore()
+	smtpCmd.AddCommand(smtpSetCmd(cs))
+	smtpCmd.AddCommand(cfgflags.MarkReadOnlySafe(smtpShowCmd(cs)))
+	smtpCmd.AddCommand(cfgflags.MarkReadOnlySafe(smtpTestCmd(cs)))
+	notifyCmd.AddCommand(smtpCmd)
+
+	hookCmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Event hook script commands.",
+		Long: fmt.Sprintf("Manage scripts run in the background when events happen. Events: %s. "+
+			"Hook output is logged at debug level, and scripts are killed after their timeout.", strings.Join(events.Names(), ", ")),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+	hookCmd.AddCommand(hookAddCmd(cs))
+	hookCmd.AddCommand(cfgflags.MarkReadOnlySafe(hookListCmd(cs)))
+	hookCmd.AddCommand(hookDeleteCmd(cs))
+	notifyCmd.AddCommand(hookCmd)
+
+	return notifyCmd
+}
+
+// hookAddCmd adds a script to run on an event.
+func hookAddCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var h models.Hook
+
+	addCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add an event hook script.",
+		Long: "Adds a script to run when the event happens, replacing any hook with the same name. " +
+			"Each --arg is a Go template such as '{{.Path}}' (" + strings.Join(events.Fields(), ", ") + "). " +
+			"The same fields are set as TUBARR_ environment variables, such as TUBARR_PATH.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !events.Valid(h.Event) {
+				return fmt.Errorf("invalid event %q, expected one of: %s", h.Event, strings.Join(events.Names(), ", "))
+			}
+			if h.Path == "" {
+				return errors.New("please enter the script to run with --path")
+			}
+			path, err := exec.LookPath(h.Path)
+			if err != nil {
+				return fmt.Errorf("hook script %q is not executable: %w", h.Path, err)
+			}
+			if h.Path, err = filepath.Abs(path); err != nil {
+				return err
+			}
+			for _, a := range h.Args {
+				if err := events.CheckTemplate(a); err != nil {
+					return err
+				}
+			}
+			if h.Timeout < time.Second {
+				return fmt.Errorf("hook timeout %v is too short, expected at least 1s", h.Timeout)
+			}
+			return cs.AddHook(&h)
+		},
+	}
+
+	addCmd.Flags().StringVar(&h.Name, "name", "", "Name of the hook, defaulting to the event and path")
+	addCmd.Flags().StringVar(&h.Event, "event", "", "Event to run on ("+strings.Join(events.Names(), ", ")+")")
+	addCmd.Flags().StringVar(&h.Path, "path", "", "Script or program to run")
+	addCmd.Flags().StringArrayVar(&h.Args, "arg", nil, "Argument to pass, as a template (repeatable, e.g. --arg '{{.Path}}')")
+	addCmd.Flags().DurationVar(&h.Timeout, "timeout", time.Minute, "Time after which the script is killed")
+
+	return addCmd
+}
+
+// hookListCmd prints the saved event hooks.
+func hookListCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var event string
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List event hook scripts.",
+		Long:  "Prints the saved event hooks, with their arguments and timeouts.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hooks, err := cs.GetHooks(event)
+			if err != nil {
+				return err
+			}
+			if len(hooks) == 0 {
+				logging.I("No event hooks saved, add one with 'tubarr notify hook add'")
+				return nil
+			}
+			for _, h := range hooks {
+				fmt.Printf("%s (%s): %s", h.Name, h.Event, h.Path)
+				for _, a := range h.Args {
+					fmt.Printf(" %q", a)
+				}
+				fmt.Printf(" [timeout %v]\n", h.Timeout)
+			}
+			return nil
+		},
+	}
+
+	listCmd.Flags().StringVar(&event, "event", "", "Only list hooks for this event")
+	return listCmd
+}
+
+// hookDeleteCmd deletes event hooks by name.
+func hookDeleteCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var names []string
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete event hook scripts.",
+		Long:  "Deletes the named event hooks.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(names) == 0 {
+				return errors.New("please enter at least one hook name with --name")
+			}
+			return cs.DeleteHooks(names)
+		},
+	}
+
+	deleteCmd.Flags().StringSliceVar(&names, "name", nil, "Names of the hooks to delete")
+	return deleteCmd
+}
+
+// smtpSetCmd updates the mail server settings, leaving unset flags unchanged.
+func smtpSetCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		c    models.SMTPConfig
+		port int
+	)
+
+	setCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the SMTP mail server.",
+		Long: "Sets the mail server used for email notifications. Only the flags given are changed. " +
+			"The password may be a secret reference such as 'env:SMTP_PASSWORD' or 'file:/run/secrets/smtp'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			return cs.UpdateSMTPConfig(func(s *models.SMTPConfig) error {
+				if flags.Changed("host") {
+					s.Host = c.Host
+				}
+				if flags.Changed("port") {
+					s.Port = port
+				}
+				if flags.Changed("username") {
+					s.Username = c.Username
+				}
+				if flags.Changed("password") {
+					s.Password = c.Password
+				}
+				if flags.Changed("from") {
+					s.From = c.From
+				}
+				if flags.Changed("tls") {
+					s.TLS = strings.ToLower(c.TLS)
+				}
+				if flags.Changed("subject") {
+					s.Subject = c.Subject
+				}
+				if flags.Changed("body") {
+					s.Body = c.Body
+				}
+				if s.Port == 0 {
+					s.Port = defaultPort(s.TLS)
+				}
+				if err := email.Validate(s); err != nil {
+					return err
+				}
+				logging.S(0, "Saved SMTP settings for %s:%d", s.Host, s.Port)
+				return nil
+			})
+		},
+	}
+
+	setCmd.Flags().StringVar(&c.Host, "host", "", "SMTP server hostname")
+	setCmd.Flags().IntVar(&port, "port", 0, "SMTP server port, defaulting to 587 for starttls, 465 for tls, and 25 for none")
+	setCmd.Flags().StringVar(&c.Username, "username", "", "SMTP login username, blank to send without logging in")
+	setCmd.Flags().StringVar(&c.Password, "password", "", "SMTP login password, or a secret reference such as 'env:SMTP_PASSWORD'")
+	setCmd.Flags().StringVar(&c.From, "from", "", "Sender address (e.g. 'Tubarr <tubarr@example.com>')")
+	setCmd.Flags().StringVar(&c.TLS, "tls", email.TLSStartTLS, fmt.Sprintf("TLS mode: %s, %s, or %s", email.TLSStartTLS, email.TLSImplicit, email.TLSNone))
+	setCmd.Flags().StringVar(&c.Subject, "subject", "", "Go template for the subject, blank for the default "+email.DefaultSubject+" (fields .Title, .Text, .Channel, .Videos, .Report)")
+	setCmd.Flags().StringVar(&c.Body, "body", "", "Go template for the body, blank for the default (fields .Title, .Text, .Channel, .Videos with .Title .URL .Path, .Report)")
+
+	return setCmd
+}
+
+// smtpShowCmd prints the mail server settings, hiding the password.
+func smtpShowCmd(cs interfaces.ChannelStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the SMTP mail server settings.",
+		Long:  "Prints the mail server settings, showing only whether a password is set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cs.GetSMTPConfig()
+			if err != nil {
+				return err
+			}
+			if c.Host == "" {
+				logging.I("No SMTP server set, use 'tubarr notify smtp set'")
+				return nil
+			}
+
+			password := "(none)"
+			if c.Password != "" {
+				password = "(set)"
+			}
+			fmt.Printf("Host: %s\nPort: %d\nUsername: %s\nPassword: %s\nFrom: %s\nTLS: %s\nSubject: %s\nBody: %s\n",
+				c.Host, c.Port, c.Username, password, c.From, orDefault(c.TLS, email.TLSStartTLS), orDefault(c.Subject, email.DefaultSubject), orDefault(c.Body, email.DefaultBody))
+			return nil
+		},
+	}
+}
+
+// smtpTestCmd sends a test email with the saved settings.
+func smtpTestCmd(cs interfaces.ChannelStore) *cobra.Command {
+	var to []string
+
+	testCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Send a test email.",
+		Long:  "Sends a test email through the saved mail server settings, rendering the subject and body templates with example videos.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(to) == 0 {
+				return errors.New("please enter at least one address with --to")
+			}
+			if err := email.ValidateAddresses(to); err != nil {
+				return err
+			}
+			c, err := cs.GetSMTPConfig()
+			if err != nil {
+				return err
+			}
+
+			example := &models.Channel{Name: "Example channel"}
+			data := email.FromVideos(example, []*models.Video{
+				{Title: "Example video", URL: "https://example.com/video", VideoPath: "/videos/example.mp4"},
+			})
+			data.Title = "Tubarr test email: " + data.Title
+			if err := email.Send(c, to, data); err != nil {
+				return err
+			}
+			logging.S(0, "Sent test email to %v", to)
+			return nil
+		},
+	}
+
+	testCmd.Flags().StringSliceVar(&to, "to", nil, "Addresses to send the test email to")
+	return testCmd
+}
+
+// defaultPort returns the usual SMTP port for the TLS mode.
+func defaultPort(tls string) int {
+	switch tls {
+	case email.TLSImplicit:
+		return 465
+	case email.TLSNone:
+		return 25
+	default:
+		return 587
+	}
+}
+
+// orDefault returns s, or def if s is blank.
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
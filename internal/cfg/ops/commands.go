@@ -0,0 +1,147 @@
+// Package cfgops sets up Cobra filter and meta operation commands.
+package cfgops
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitOpsCmds is the entrypoint for initializing filter and meta operation commands.
+func InitOpsCmds() *cobra.Command {
+	opsCmd := &cobra.Command{
+		Use:   "ops",
+		Short: "Filter and meta operation commands.",
+		Long:  "Check download filters and Metarr meta operations.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	opsCmd.AddCommand(cfgflags.MarkReadOnlySafe(lintCmd()))
+	return opsCmd
+}
+
+// lintCmd validates filters and meta operations without touching the database.
+func lintCmd() *cobra.Command {
+	var (
+		filters, metaOps        []string
+		filterFile, metaOpsFile string
+	)
+
+	lint := &cobra.Command{
+		Use:   "lint",
+		Short: "Validate filters and meta operations.",
+		Long:  "Checks download filters and meta operations passed inline or in files (one per line, '#' starts a comment), reporting the position of each error. Nothing is saved.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var checks []opCheck
+			for i, f := range filters {
+				checks = append(checks, opCheck{src: fmt.Sprintf("--filter %d", i+1), op: f, filter: true})
+			}
+			for i, m := range metaOps {
+				checks = append(checks, opCheck{src: fmt.Sprintf("--meta-op %d", i+1), op: m})
+			}
+			if filterFile != "" {
+				fileChecks, err := readOpFile(filterFile, true)
+				if err != nil {
+					return err
+				}
+				checks = append(checks, fileChecks...)
+			}
+			if metaOpsFile != "" {
+				fileChecks, err := readOpFile(metaOpsFile, false)
+				if err != nil {
+					return err
+				}
+				checks = append(checks, fileChecks...)
+			}
+			if len(checks) == 0 {
+				return errors.New("nothing to lint, pass --filter, --meta-op, --filter-file, or --meta-ops-file")
+			}
+
+			var failed int
+			for _, c := range checks {
+				if err := c.run(); err != nil {
+					failed++
+					fmt.Printf("%s: %v\n", c.src, err)
+				}
+			}
+			if failed > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d of %d operation(s) invalid", failed, len(checks))
+			}
+			logging.S(0, "All %d operation(s) valid", len(checks))
+			return nil
+		},
+	}
+
+	lint.Flags().StringSliceVar(&filters, "filter", nil, "Download filter to check (e.g. 'title:omit:frogs')")
+	lint.Flags().StringSliceVar(&metaOps, "meta-op", nil, "Meta operation to check (e.g. 'title:prefix:Draft-')")
+	lint.Flags().StringVar(&filterFile, "filter-file", "", "File of download filters to check, one per line")
+	lint.Flags().StringVar(&metaOpsFile, "meta-ops-file", "", "File of meta operations to check, one per line")
+
+	return lint
+}
+
+// opCheck is a single filter or meta operation to lint, with where it came from.
+type opCheck struct {
+	src    string
+	op     string
+	filter bool
+}
+
+// run checks the operation, warning about field names which look misspelled.
+func (c opCheck) run() error {
+	var (
+		field string
+		err   error
+	)
+	if c.filter {
+		var f models.DLFilters
+		f, err = parsing.ParseFilter(c.op)
+		field = f.Field
+	} else {
+		_, err = parsing.ParseMetaOp(c.op)
+		field, _, _ = strings.Cut(c.op, ":")
+	}
+	if err != nil {
+		return err
+	}
+
+	if s := parsing.SuggestField(field); s != "" {
+		logging.I("%s: unknown field %q, did you mean %q?", c.src, field, s)
+	}
+	return nil
+}
+
+// readOpFile reads one operation per line, skipping blank lines and '#' comments.
+func readOpFile(path string, filter bool) ([]opCheck, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	var checks []opCheck
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		checks = append(checks, opCheck{src: fmt.Sprintf("%s:%d", path, n), op: line, filter: filter})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+	return checks, nil
+}
@@ -0,0 +1,77 @@
+// Package cfgoutput formats the output of list commands as text, tables, JSON, or YAML.
+package cfgoutput
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Text  = ""
+	Table = "table"
+	JSON  = "json"
+	YAML  = "yaml"
+
+	flagName = "output"
+)
+
+// SetFlag sets the output format flag of a list command.
+func SetFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVar(format, flagName, Text, "Output format: table, json, or yaml (default detailed text)")
+	_ = cmd.RegisterFlagCompletionFunc(flagName, cobra.FixedCompletions([]string{Table, JSON, YAML}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// Validate checks the output format is supported, normalizing its case.
+func Validate(format *string) error {
+	*format = strings.ToLower(strings.TrimSpace(*format))
+	switch *format {
+	case Text, Table, JSON, YAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q, must be %s, %s, or %s", *format, Table, JSON, YAML)
+	}
+}
+
+// Structured reports whether the format is machine readable, JSON or YAML.
+func Structured(format string) bool {
+	return format == JSON || format == YAML
+}
+
+// Print writes v to stdout as JSON or YAML.
+//
+// YAML is converted through JSON, so fields keep their JSON names.
+func Print(format string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	if format == YAML {
+		var doc any
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+		if b, err = yaml.Marshal(doc); err != nil {
+			return fmt.Errorf("failed to encode output as YAML: %w", err)
+		}
+	} else {
+		b = append(b, '\n')
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
+
+// PrintTable writes rows to stdout as aligned columns under a header.
+func PrintTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, r := range rows {
+		fmt.Fprintln(w, strings.Join(r, "\t"))
+	}
+	return w.Flush()
+}
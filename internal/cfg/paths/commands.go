@@ -0,0 +1,86 @@
+// Package cfgpaths sets up Cobra path maintenance commands.
+package cfgpaths
+
+import (
+	"errors"
+	"fmt"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	maxMissingShown = 20
+)
+
+// InitPathsCmds is the entrypoint for initializing path commands.
+func InitPathsCmds(s interfaces.Store) *cobra.Command {
+	pathsCmd := &cobra.Command{
+		Use:   "paths",
+		Short: "Stored path commands.",
+		Long:  "Maintain the file and directory paths stored in the database.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	pathsCmd.AddCommand(rewritePathsCmd(s.StorageStore()))
+	return pathsCmd
+}
+
+// rewritePathsCmd moves stored paths from an old library root to a new one.
+func rewritePathsCmd(ss interfaces.StorageStore) *cobra.Command {
+	var (
+		from, to             string
+		dryRun, allowMissing bool
+	)
+
+	rewriteCmd := &cobra.Command{
+		Use:   "rewrite",
+		Short: "Rewrite stored paths after moving the library.",
+		Long:  "Rewrites every stored channel directory, video and transcript path, and post directory under --from to sit under --to, in a single transaction. Rewritten files must exist unless --allow-missing is set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" || to == "" {
+				return errors.New("must enter both --from and --to")
+			}
+
+			res, err := ss.RewritePaths(from, to, dryRun, allowMissing)
+			if res != nil {
+				verb := "Rewrote"
+				if dryRun || err != nil {
+					verb = "Would rewrite"
+				}
+				fmt.Printf("\n%s%s paths for %d channel(s), %d video(s), and %d post(s)%s\n", consts.ColorGreen, verb, res.Channels, res.Videos, res.Posts, consts.ColorReset)
+
+				if len(res.Missing) > 0 {
+					fmt.Printf("%d rewritten file(s) not found:\n", len(res.Missing))
+					for i, p := range res.Missing {
+						if i == maxMissingShown {
+							fmt.Printf("...and %d more\n", len(res.Missing)-maxMissingShown)
+							break
+						}
+						fmt.Println("  " + p)
+					}
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			if !dryRun {
+				logging.S(0, "Rewrote stored paths from %q to %q", from, to)
+			}
+			return nil
+		},
+	}
+
+	rewriteCmd.Flags().StringVar(&from, "from", "", "Old library root (e.g. '/mnt/old')")
+	rewriteCmd.Flags().StringVar(&to, "to", "", "New library root (e.g. '/mnt/new')")
+	rewriteCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be rewritten without changing the database")
+	rewriteCmd.Flags().BoolVar(&allowMissing, "allow-missing", false, "Rewrite even if some files are not found under the new root")
+
+	return rewriteCmd
+}
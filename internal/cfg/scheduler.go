@@ -0,0 +1,65 @@
+package cfg
+
+import (
+	"errors"
+	"time"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/utils/socket"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// schedulerCmd runs continuously, crawling each channel when its schedule is due.
+//
+// Crawls modify the database, so in read-only mode only the HTTP API is served.
+func schedulerCmd() *cobra.Command {
+	return cfgflags.MarkReadOnlySafe(&cobra.Command{
+		Use:   "scheduler",
+		Short: "Crawl channels on their schedules.",
+		Long:  "Runs until interrupted, crawling each channel when it is due by its cron expression or crawl frequency, respecting quiet hours, jitter, and blackout dates. With --read-only, channels are not crawled and only the HTTP API is served, as with 'serve'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if viper.GetBool(keys.ReadOnly) {
+				return setServer()
+			}
+			viper.Set(keys.RunScheduler, true)
+			return nil
+		},
+	})
+}
+
+// serveCmd serves the HTTP API without crawling channels.
+func serveCmd() *cobra.Command {
+	var idleExit time.Duration
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the HTTP API without crawling channels.",
+		Long: "Runs until interrupted, serving the HTTP API on --http-addr without crawling channels. With --read-only, requests which would modify the database are refused, e.g. for a public dashboard against a shared or backed-up database. " +
+			"When started by systemd socket activation, the socket systemd passes is served instead, and with --idle-exit Tubarr exits once idle so systemd can start it again on the next request.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if idleExit < 0 {
+				return errors.New("--idle-exit cannot be negative")
+			}
+			viper.Set(keys.IdleExit, idleExit)
+			return setServer()
+		},
+	}
+
+	serveCmd.Flags().DurationVar(&idleExit, keys.IdleExit, 0, "Exit after this long without HTTP requests or background work (e.g. '15m', 0 to keep running)")
+	return cfgflags.MarkReadOnlySafe(serveCmd)
+}
+
+// setServer requests the HTTP API is served once commands have run.
+func setServer() error {
+	if cfgflags.RemoteAddr() != "" || cfgflags.QueueCommands() {
+		return errors.New("another Tubarr instance is already running the scheduler")
+	}
+	if viper.GetString(keys.HTTPAddr) == "" && !socket.Activated() {
+		return errors.New("no address to serve on, set --http-addr or start Tubarr by systemd socket activation")
+	}
+	viper.Set(keys.RunServer, true)
+	return nil
+}
@@ -0,0 +1,36 @@
+package cfg
+
+import (
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/keys"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// selfTestCmd runs the download pipeline once against a known-good video.
+func selfTestCmd() *cobra.Command {
+	var (
+		url, notifyChannel string
+		keep               bool
+	)
+
+	selfTestCmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "Test the download pipeline end to end.",
+		Long: "Fetches metadata for and downloads a short test video through a temporary channel, hands it to Metarr (or moves it if Metarr isn't installed), " +
+			"and renders notifications without sending them, reporting pass or fail per stage. Files go to a temporary directory and the channel is removed afterwards.",
+		Run: func(cmd *cobra.Command, args []string) {
+			viper.Set(keys.SelfTestURL, url)
+			viper.Set(keys.SelfTestKeep, keep)
+			viper.Set(keys.SelfTestNotify, notifyChannel)
+			viper.Set(keys.RunSelfTest, true)
+		},
+	}
+
+	selfTestCmd.Flags().StringVar(&url, "url", consts.SelfTestURL, "Video to test with")
+	selfTestCmd.Flags().BoolVar(&keep, "keep", false, "Keep the downloaded files instead of deleting the temporary directory")
+	selfTestCmd.Flags().StringVar(&notifyChannel, "notify-channel", "", "Channel whose notification URLs and webhooks are rendered for the test video, without sending")
+
+	return selfTestCmd
+}
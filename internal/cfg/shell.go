@@ -0,0 +1,232 @@
+package cfg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/shellwords"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+const (
+	shellPrompt = "tubarr> "
+)
+
+// shellReadOnly keeps read-only mode in effect for every command run from a read-only shell.
+var shellReadOnly bool
+
+// shellCmd starts an interactive shell which runs subcommands in this process.
+func shellCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Start an interactive shell.",
+		Long:  "Starts a persistent shell which accepts the usual subcommands, keeping the database open and skipping startup for each command. Type 'exit' to quit.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCmd.SilenceUsage, rootCmd.SilenceErrors = true, true
+			defer func() { rootCmd.SilenceUsage, rootCmd.SilenceErrors = false, false }()
+			shellReadOnly = viper.GetBool(keys.ReadOnly)
+
+			fd := int(os.Stdin.Fd())
+			if !term.IsTerminal(fd) {
+				return runShellLines(bufio.NewScanner(os.Stdin))
+			}
+			return runShellTerminal(fd)
+		},
+	}
+}
+
+// runShellTerminal runs the shell with line editing, history, and tab completion.
+func runShellTerminal(fd int) error {
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, shellPrompt)
+	t.AutoCompleteCallback = completeLine
+
+	for {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to set terminal raw mode: %w", err)
+		}
+		line, err := t.ReadLine()
+		if restoreErr := term.Restore(fd, state); restoreErr != nil {
+			logging.E(0, "Failed to restore terminal: %v", restoreErr)
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				fmt.Println()
+				return nil
+			}
+			return err
+		}
+
+		if !runShellLine(line) {
+			return nil
+		}
+	}
+}
+
+// runShellLines runs the shell over non-interactive input, one command per line.
+func runShellLines(scanner *bufio.Scanner) error {
+	for scanner.Scan() {
+		if !runShellLine(scanner.Text()) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// runShellLine executes a single shell line, returning false if the shell should exit.
+func runShellLine(line string) bool {
+	args, err := shellwords.Split(line)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return true
+	}
+	if len(args) == 0 {
+		return true
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return false
+	case "shell":
+		fmt.Fprintln(os.Stderr, "Error: already in a shell")
+		return true
+	}
+
+	resetFlags(rootCmd)
+	if shellReadOnly {
+		if err := rootCmd.PersistentFlags().Set(keys.ReadOnly, "true"); err != nil {
+			logging.E(0, "Failed to keep read-only mode: %v", err)
+		}
+	}
+	rootCmd.SetArgs(args)
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+
+	// Root-level channel checks are only run on program exit, not in the shell
+	if viper.GetBool(keys.CheckChannels) {
+		viper.Set(keys.CheckChannels, false)
+		logging.I("Use 'channel crawl' to crawl channels from the shell")
+	}
+	if viper.GetBool(keys.RunScheduler) {
+		viper.Set(keys.RunScheduler, false)
+		logging.I("The scheduler cannot be run from the shell")
+	}
+	if viper.GetBool(keys.RunServer) {
+		viper.Set(keys.RunServer, false)
+		logging.I("The HTTP API cannot be served from the shell")
+	}
+	if viper.GetBool(keys.RunSelfTest) {
+		viper.Set(keys.RunSelfTest, false)
+		logging.I("The self-test cannot be run from the shell")
+	}
+	return true
+}
+
+// resetFlags restores every flag in the command tree to its default, so values don't carry between commands.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			if err := sv.Replace(nil); err != nil {
+				logging.E(0, "Failed to reset flag %q: %v", f.Name, err)
+			}
+		} else if err := f.Value.Set(f.DefValue); err != nil {
+			logging.E(0, "Failed to reset flag %q: %v", f.Name, err)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+// completeLine completes subcommand and flag names when tab is pressed.
+func completeLine(line string, pos int, key rune) (newLine string, newPos int, ok bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	head := line[:pos]
+	fields := strings.Fields(head)
+
+	partial := ""
+	if len(fields) > 0 && !strings.HasSuffix(head, " ") {
+		partial = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	// Walk to the deepest subcommand typed so far
+	cmd := rootCmd
+	for _, f := range fields {
+		if strings.HasPrefix(f, "-") {
+			continue
+		}
+		for _, c := range cmd.Commands() {
+			if c.Name() == f {
+				cmd = c
+				break
+			}
+		}
+	}
+
+	var candidates []string
+	if strings.HasPrefix(partial, "-") {
+		visit := func(f *pflag.Flag) {
+			if name := "--" + f.Name; strings.HasPrefix(name, partial) {
+				candidates = append(candidates, name)
+			}
+		}
+		cmd.Flags().VisitAll(visit)
+		cmd.InheritedFlags().VisitAll(visit)
+	} else {
+		for _, c := range cmd.Commands() {
+			if !c.Hidden && strings.HasPrefix(c.Name(), partial) {
+				candidates = append(candidates, c.Name())
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+	sort.Strings(candidates)
+
+	completion := candidates[0]
+	if len(candidates) == 1 {
+		completion += " "
+	} else {
+		completion = commonPrefix(candidates)
+	}
+
+	head = head[:len(head)-len(partial)] + completion
+	return head + line[pos:], len(head), true
+}
+
+// commonPrefix returns the longest prefix shared by all strings.
+func commonPrefix(strs []string) string {
+	prefix := strs[0]
+	for _, s := range strs[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
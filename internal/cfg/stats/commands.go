@@ -0,0 +1,264 @@
+// Package cfgstats sets up Cobra download statistics commands.
+package cfgstats
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cfgchannel "tubarr/internal/cfg/channel"
+	cfgflags "tubarr/internal/cfg/flags"
+	cfgoutput "tubarr/internal/cfg/output"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/disk"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitStatsCmds is the entrypoint for initializing download statistics commands.
+func InitStatsCmds(s interfaces.Store) *cobra.Command {
+	var (
+		chanName, chanURL, format string
+		chanID, days              int
+		daily                     bool
+	)
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show download statistics.",
+		Long: "Summarizes downloads, failures, bytes downloaded, and average download time per channel over recent days, " +
+			"with downloads still failed counted by failure reason. " +
+			"Use --daily for a day by day breakdown.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if days < 1 {
+				return errors.New("--days must be at least 1")
+			}
+			if err := cfgoutput.Validate(&format); err != nil {
+				return err
+			}
+
+			var cid int64
+			if chanID != 0 || chanName != "" || chanURL != "" {
+				var err error
+				if cid, err = cfgchannel.ChannelID(s.ChannelStore(), chanID, chanName, chanURL); err != nil {
+					return err
+				}
+			}
+
+			ts := s.StatsStore()
+			since := time.Now().AddDate(0, 0, -(days - 1))
+
+			var (
+				stats []*models.DownloadStats
+				err   error
+			)
+			if daily {
+				stats, err = ts.FetchDailyStats(cid, since)
+			} else {
+				stats, err = ts.FetchStatsTotals(cid, since)
+			}
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case cfgoutput.JSON, cfgoutput.YAML:
+				out := make([]statsOutput, 0, len(stats))
+				for _, st := range stats {
+					out = append(out, toStatsOutput(st))
+				}
+				return cfgoutput.Print(format, out)
+			case cfgoutput.Table:
+				rows := make([][]string, 0, len(stats))
+				for _, st := range stats {
+					rows = append(rows, []string{st.Day, st.ChannelName, strconv.Itoa(st.Downloads), strconv.Itoa(st.Failures),
+						disk.FormatBytes(st.Bytes), st.AvgDuration().Round(time.Second).String()})
+				}
+				return cfgoutput.PrintTable([]string{"DAY", "CHANNEL", "DOWNLOADS", "FAILURES", "DOWNLOADED", "AVERAGE TIME"}, rows)
+			}
+
+			if len(stats) == 0 {
+				logging.I("No downloads recorded in the last %d days", days)
+				return nil
+			}
+
+			var total models.DownloadStats
+			day := ""
+			for _, st := range stats {
+				if daily && st.Day != day {
+					day = st.Day
+					fmt.Printf("\n%s%s%s\n", consts.ColorGreen, day, consts.ColorReset)
+				}
+				if !daily {
+					fmt.Println()
+				}
+				printStats(fmt.Sprintf("%s (ID %d)", st.ChannelName, st.ChannelID), st)
+
+				total.Downloads += st.Downloads
+				total.Failures += st.Failures
+				total.Bytes += st.Bytes
+				total.Duration += st.Duration
+				for reason, n := range st.FailReasons {
+					if total.FailReasons == nil {
+						total.FailReasons = make(map[consts.FailReason]int)
+					}
+					total.FailReasons[reason] += n
+				}
+			}
+
+			fmt.Printf("\n%sLast %d days%s\n", consts.ColorGreen, days, consts.ColorReset)
+			printStats("All channels", &total)
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(statsCmd, &chanName, &chanURL, &chanID)
+	statsCmd.Flags().IntVar(&days, "days", 30, "Number of days to include, counting today")
+	statsCmd.Flags().BoolVar(&daily, "daily", false, "Show each day separately")
+	cfgoutput.SetFlag(statsCmd, &format)
+
+	statsCmd.AddCommand(crawlsCmd(s))
+	return cfgflags.MarkReadOnlySafe(statsCmd)
+}
+
+// crawlsCmd shows the summaries of recent crawl passes.
+func crawlsCmd(s interfaces.Store) *cobra.Command {
+	var (
+		limit  int
+		format string
+	)
+
+	crawlsCmd := &cobra.Command{
+		Use:   "crawls",
+		Short: "Show recent crawl summaries.",
+		Long:  "Shows the new videos, failures, and filtered videos of each channel in recent crawl passes, newest first.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit < 1 {
+				return errors.New("--limit must be at least 1")
+			}
+			if err := cfgoutput.Validate(&format); err != nil {
+				return err
+			}
+			reports, err := s.StatsStore().FetchCrawlReports(limit)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case cfgoutput.JSON, cfgoutput.YAML:
+				out := make([]crawlOutput, 0, len(reports))
+				for _, r := range reports {
+					out = append(out, toCrawlOutput(r))
+				}
+				return cfgoutput.Print(format, out)
+			case cfgoutput.Table:
+				var rows [][]string
+				for _, r := range reports {
+					started := r.StartedAt.Local().Format("2006-01-02 15:04:05")
+					for _, c := range r.Channels {
+						rows = append(rows, []string{started, c.ChannelName, strconv.Itoa(c.NewVideos), strconv.Itoa(c.Failures), strconv.Itoa(c.Filtered), c.Error})
+					}
+				}
+				return cfgoutput.PrintTable([]string{"STARTED", "CHANNEL", "NEW", "FAILED", "FILTERED", "ERROR"}, rows)
+			}
+			if len(reports) == 0 {
+				logging.I("No crawl passes recorded")
+				return nil
+			}
+
+			for _, r := range reports {
+				t := r.Totals()
+				fmt.Printf("\n%s%s%s (%v, digest sent: %v)\n", consts.ColorGreen, r.StartedAt.Local().Format("2006-01-02 15:04:05"), consts.ColorReset,
+					r.FinishedAt.Sub(r.StartedAt).Round(time.Second), r.DigestSent)
+				fmt.Printf("  Total: %d new, %d failed, %d filtered, %d of %d channels failed\n", t.NewVideos, t.Failures, t.Filtered, r.FailedChannels(), len(r.Channels))
+				for _, c := range r.Channels {
+					fmt.Printf("  %s (ID %d): %d new, %d failed, %d filtered\n", c.ChannelName, c.ChannelID, c.NewVideos, c.Failures, c.Filtered)
+					if c.Error != "" {
+						fmt.Printf("    Error: %s\n", c.Error)
+					}
+				}
+			}
+			return nil
+		},
+	}
+
+	crawlsCmd.Flags().IntVar(&limit, "limit", 10, "Number of crawl passes to show")
+	cfgoutput.SetFlag(crawlsCmd, &format)
+	return cfgflags.MarkReadOnlySafe(crawlsCmd)
+}
+
+// printStats prints a set of download totals.
+func printStats(label string, st *models.DownloadStats) {
+	failRate := 0.0
+	if n := st.Downloads + st.Failures; n > 0 {
+		failRate = float64(st.Failures) / float64(n) * 100
+	}
+	fmt.Printf("%s\n  Downloads: %d\n  Failures: %d (%.0f%%)\n  Downloaded: %s\n  Average Time: %v\n",
+		label, st.Downloads, st.Failures, failRate, disk.FormatBytes(st.Bytes), st.AvgDuration().Round(time.Second))
+
+	if len(st.FailReasons) > 0 {
+		reasons := make([]string, 0, len(st.FailReasons))
+		for reason, n := range st.FailReasons {
+			reasons = append(reasons, fmt.Sprintf("%s %d", reason, n))
+		}
+		sort.Strings(reasons)
+		fmt.Printf("  Still Failed: %s\n", strings.Join(reasons, ", "))
+	}
+}
+
+// statsOutput is a set of download totals as written for scripts.
+type statsOutput struct {
+	ChannelID   int64                     `json:"channel_id"`
+	ChannelName string                    `json:"channel_name"`
+	Day         string                    `json:"day,omitempty"`
+	Downloads   int                       `json:"downloads"`
+	Failures    int                       `json:"failures"`
+	Bytes       int64                     `json:"bytes"`
+	AvgSeconds  float64                   `json:"average_seconds"`
+	FailReasons map[consts.FailReason]int `json:"fail_reasons,omitempty"`
+}
+
+// toStatsOutput converts download totals for output.
+func toStatsOutput(st *models.DownloadStats) statsOutput {
+	return statsOutput{
+		ChannelID:   st.ChannelID,
+		ChannelName: st.ChannelName,
+		Day:         st.Day,
+		Downloads:   st.Downloads,
+		Failures:    st.Failures,
+		Bytes:       st.Bytes,
+		AvgSeconds:  st.AvgDuration().Seconds(),
+		FailReasons: st.FailReasons,
+	}
+}
+
+// crawlOutput is a crawl pass summary as written for scripts.
+type crawlOutput struct {
+	ID         int64                  `json:"id"`
+	StartedAt  time.Time              `json:"started_at"`
+	FinishedAt time.Time              `json:"finished_at"`
+	DigestSent bool                   `json:"digest_sent"`
+	Channels   []models.ChannelReport `json:"channels"`
+}
+
+// toCrawlOutput converts a crawl report for output.
+func toCrawlOutput(r *models.CrawlReport) crawlOutput {
+	channels := r.Channels
+	if channels == nil {
+		channels = []models.ChannelReport{}
+	}
+	return crawlOutput{
+		ID:         r.ID,
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+		DigestSent: r.DigestSent,
+		Channels:   channels,
+	}
+}
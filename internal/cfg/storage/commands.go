@@ -0,0 +1,103 @@
+// Package cfgstorage sets up Cobra storage commands.
+package cfgstorage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/disk"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// InitStorageCmds is the entrypoint for initializing storage commands.
+func InitStorageCmds(s interfaces.Store) *cobra.Command {
+	var refresh bool
+
+	storageCmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Show disk usage.",
+		Long:  "Summarizes cached disk usage per root directory and per channel, including filesystem free space.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfgflags.CheckReadOnlyFlag(cmd, "refresh"); err != nil {
+				return err
+			}
+			cs := s.ChannelStore()
+			ss := s.StorageStore()
+
+			chans, err, hasRows := cs.FetchAllChannels()
+			if !hasRows {
+				logging.I("No channels in database")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if refresh {
+				for _, c := range chans {
+					if _, err := ss.RefreshChannelStorage(c.ID); err != nil {
+						return err
+					}
+				}
+			}
+
+			usages, err := ss.FetchAllChannelStorage()
+			if err != nil {
+				return err
+			}
+			usageMap := make(map[int64]*models.ChannelStorage, len(usages))
+			for _, u := range usages {
+				usageMap[u.ChannelID] = u
+			}
+
+			// Group channels by the static root of their video directory
+			roots := make(map[string]int64)
+			for _, c := range chans {
+				root := filepath.Clean(parsing.StaticPrefix(c.VideoDir))
+				used := roots[root]
+				if u, ok := usageMap[c.ID]; ok {
+					used += u.VideoBytes + u.JSONBytes
+				}
+				roots[root] = used
+			}
+
+			rootNames := make([]string, 0, len(roots))
+			for r := range roots {
+				rootNames = append(rootNames, r)
+			}
+			sort.Strings(rootNames)
+
+			fmt.Printf("\n%sRoot Directories%s\n", consts.ColorGreen, consts.ColorReset)
+			for _, r := range rootNames {
+				space, err := disk.FreeSpace(r)
+				if err != nil {
+					fmt.Printf("%s\n  Used by Tubarr: %s\n  Free: unavailable (%v)\n", r, disk.FormatBytes(roots[r]), err)
+					continue
+				}
+				fmt.Printf("%s\n  Used by Tubarr: %s\n  Free: %s of %s\n", r, disk.FormatBytes(roots[r]), disk.FormatBytes(int64(space.Free)), disk.FormatBytes(int64(space.Total)))
+			}
+
+			fmt.Printf("\n%sChannels%s\n", consts.ColorGreen, consts.ColorReset)
+			for _, c := range chans {
+				u, ok := usageMap[c.ID]
+				if !ok {
+					fmt.Printf("%s (ID %d)\n  Not yet calculated, run with --refresh\n", c.Name, c.ID)
+					continue
+				}
+				fmt.Printf("%s (ID %d)\n  Videos: %s\n  JSON: %s\n  Files: %d\n  Updated: %s\n", c.Name, c.ID, disk.FormatBytes(u.VideoBytes), disk.FormatBytes(u.JSONBytes), u.Files, u.UpdatedAt.Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		},
+	}
+
+	storageCmd.Flags().BoolVar(&refresh, "refresh", false, "Recalculate usage for all channels before printing")
+	return cfgflags.MarkReadOnlySafe(storageCmd)
+}
@@ -0,0 +1,127 @@
+// Package cfguser sets up Cobra commands for the users of the HTTP endpoints.
+package cfguser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// InitUserCmds is the entrypoint for initializing user commands.
+func InitUserCmds(s interfaces.Store) *cobra.Command {
+	userCmd := &cobra.Command{
+		Use:   "user",
+		Short: "User commands.",
+		Long: "Manage the users who can log in to the HTTP endpoints. Until the first user is added the endpoints are open, " +
+			"afterwards each user only sees the channels they own, and only admins can delete channels and videos.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	us := s.UserStore()
+
+	// Add subcommands with dependencies
+	userCmd.AddCommand(addUserCmd(us))
+	userCmd.AddCommand(deleteUserCmd(us))
+	userCmd.AddCommand(cfgflags.MarkReadOnlySafe(listUsersCmd(us)))
+
+	return userCmd
+}
+
+// addUserCmd adds a user, prompting for their password if it isn't entered.
+func addUserCmd(us interfaces.UserStore) *cobra.Command {
+	var password, role string
+
+	addCmd := &cobra.Command{
+		Use:   "add <username>",
+		Short: "Add a user.",
+		Long:  "Adds a user who can log in to the HTTP endpoints. The password is prompted for if not entered.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if password == "" {
+				var err error
+				if password, err = promptPassword(); err != nil {
+					return err
+				}
+			}
+
+			if _, err := us.AddUser(args[0], password, role); err != nil {
+				return err
+			}
+			logging.S(0, "Added %s %q", role, args[0])
+			return nil
+		},
+	}
+
+	addCmd.Flags().StringVar(&password, "password", "", "Password for the user, prompted for if not set")
+	addCmd.Flags().StringVar(&role, "role", consts.RoleUser, fmt.Sprintf("Role of the user (%s or %s)", consts.RoleUser, consts.RoleAdmin))
+	return addCmd
+}
+
+// deleteUserCmd deletes a user, their channels are left without an owner.
+func deleteUserCmd(us interfaces.UserStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <username>",
+		Short: "Delete a user.",
+		Long:  "Deletes a user and logs them out. Their channels are kept, visible only to admins until given a new owner.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := us.DeleteUser(args[0]); err != nil {
+				return err
+			}
+			logging.S(0, "Deleted user %q", args[0])
+			return nil
+		},
+	}
+}
+
+// listUsersCmd lists the users.
+func listUsersCmd(us interfaces.UserStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List users.",
+		Long:  "Lists the users who can log in to the HTTP endpoints.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			users, err := us.FetchUsers()
+			if err != nil {
+				return err
+			}
+			if len(users) == 0 {
+				logging.I("No users added, the HTTP endpoints are open to everyone")
+				return nil
+			}
+			for _, u := range users {
+				fmt.Printf("%s%s%s (%s), added %s\n", consts.ColorGreen, u.Username, consts.ColorReset, u.Role, u.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		},
+	}
+}
+
+// promptPassword reads a password from the terminal without echoing it.
+func promptPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("no password entered, use --password when not running in a terminal")
+	}
+
+	fmt.Print("Password: ")
+	pass, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+	if len(pass) == 0 {
+		return "", errors.New("password is blank")
+	}
+	return string(pass), nil
+}
@@ -2,11 +2,22 @@
 package cfgvalidate
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 	"tubarr/internal/domain/consts"
 	"tubarr/internal/domain/keys"
+	"tubarr/internal/nfo"
+	"tubarr/internal/parsing"
+	"tubarr/internal/postprocess"
+	"tubarr/internal/schedule"
 	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/whisper"
 
 	"github.com/spf13/viper"
 )
@@ -27,6 +38,24 @@ func ValidateViperFlags() error {
 		}
 	}
 
+	if viper.IsSet(keys.MaxRate) {
+		if err := ValidateMaxRate(viper.GetString(keys.MaxRate)); err != nil {
+			return err
+		}
+	}
+
+	if viper.IsSet(keys.Proxy) {
+		if err := ValidateProxies(viper.GetStringSlice(keys.Proxy)); err != nil {
+			return err
+		}
+	}
+
+	if viper.IsSet(keys.HostRateLimit) {
+		if _, err := parsing.ParseHostRates(viper.GetStringSlice(keys.HostRateLimit)); err != nil {
+			return err
+		}
+	}
+
 	ValidateLoggingLevel()
 	ValidateConcurrencyLimit()
 	return nil
@@ -99,5 +128,445 @@ func ValidateLoggingLevel() {
 	}
 
 	logging.Level = l
-	fmt.Printf("Logging level: %d\n", logging.Level)
+	fmt.Fprintf(os.Stderr, "Logging level: %d\n", logging.Level)
+}
+
+// ValidateConcurrentFragments checks the yt-dlp fragment count is within range.
+func ValidateConcurrentFragments(n int) error {
+	if n < 0 || n > consts.MaxConcurrentFragments {
+		return fmt.Errorf("concurrent fragments must be between 0 (unset) and %d, got %d", consts.MaxConcurrentFragments, n)
+	}
+	return nil
+}
+
+// ValidateRetryMaxAttempts checks the retry queue attempt limit.
+func ValidateRetryMaxAttempts(n int) error {
+	if n < 0 {
+		return fmt.Errorf("retry max attempts cannot be negative, got %d", n)
+	}
+	return nil
+}
+
+// ValidateMaxDownloadsPerCrawl checks the per-crawl download cap.
+func ValidateMaxDownloadsPerCrawl(n int) error {
+	if n < 0 {
+		return fmt.Errorf("max downloads per crawl cannot be negative, got %d", n)
+	}
+	return nil
+}
+
+// ValidateMetadataLimits checks the duration range and minimum view count filters.
+//
+// Either duration may be blank or '0' for no limit.
+func ValidateMetadataLimits(minDuration, maxDuration string, minViews int) error {
+	lo, err := parseLimitDuration("min duration", minDuration)
+	if err != nil {
+		return err
+	}
+	hi, err := parseLimitDuration("max duration", maxDuration)
+	if err != nil {
+		return err
+	}
+	if lo > 0 && hi > 0 && lo > hi {
+		return fmt.Errorf("min duration %v is longer than max duration %v", lo, hi)
+	}
+	if minViews < 0 {
+		return fmt.Errorf("min views cannot be negative, got %d", minViews)
+	}
+	return nil
+}
+
+// parseLimitDuration parses a duration limit, returning 0 if blank.
+func parseLimitDuration(name, val string) (time.Duration, error) {
+	if val == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q, expected a duration such as '90s' or '2m': %w", name, val, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s cannot be negative, got %q", name, val)
+	}
+	return d, nil
+}
+
+// ValidateFormat checks the maximum resolution and preferred codec, returning the resolution as a height.
+//
+// Blank values are left unset.
+func ValidateFormat(maxResolution, codec string) (int, error) {
+	if codec != "" && !slices.Contains(parsing.VideoCodecs(), codec) {
+		return 0, fmt.Errorf("invalid preferred codec %q, expected one of %s", codec, strings.Join(parsing.VideoCodecs(), ", "))
+	}
+	if maxResolution == "" {
+		return 0, nil
+	}
+	return parsing.ParseResolution(maxResolution)
+}
+
+// ValidateSidecars checks the sidecar file types, removing duplicates.
+func ValidateSidecars(sidecars []string) ([]string, error) {
+	valid := make([]string, 0, len(sidecars))
+	for _, s := range sidecars {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if !slices.Contains(consts.SidecarKinds, s) {
+			return nil, fmt.Errorf("invalid sidecar file type %q, expected %s", s, strings.Join(consts.SidecarKinds, ", "))
+		}
+		if !slices.Contains(valid, s) {
+			valid = append(valid, s)
+		}
+	}
+	return valid, nil
+}
+
+// ValidatePostProcessors checks the post-processor names, removing duplicates.
+//
+// Plugins not yet installed only log a warning, so they can be added later.
+func ValidatePostProcessors(names []string) ([]string, error) {
+	valid := make([]string, 0, len(names))
+	dir := postprocess.Dir(viper.GetString(keys.PostProcessorDir))
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if slices.Contains(valid, n) {
+			continue
+		}
+		if err := postprocess.ValidName(n); err != nil {
+			return nil, err
+		}
+		if _, err := postprocess.Load(dir, n, 0); err != nil {
+			logging.W("%v", err)
+		}
+		valid = append(valid, n)
+	}
+	return valid, nil
+}
+
+// ValidateOutputTemplate checks an output path template, if set.
+func ValidateOutputTemplate(tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+	return parsing.ValidateOutputTemplate(tmpl)
+}
+
+// ValidateNFOTemplates checks the NFO template override files exist and parse.
+func ValidateNFOTemplates(episodeTemplate, showTemplate string) error {
+	if err := nfo.CheckTemplate(episodeTemplate); err != nil {
+		return err
+	}
+	return nfo.CheckTemplate(showTemplate)
+}
+
+// ValidateNFOFieldMap parses nfo-field:metadata-field pairs passed inline or in a file (one per line, '#' starts a comment).
+//
+// Entries in the file come after inline entries, so they win for the same NFO field.
+func ValidateNFOFieldMap(entries []string, file string) (map[string]string, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read NFO field map: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				entries = append(entries, line)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	fieldMap := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		field, metaField, ok := strings.Cut(strings.ReplaceAll(entry, " ", ""), ":")
+		field = strings.ToLower(field)
+		if !ok || field == "" || metaField == "" {
+			return nil, fmt.Errorf("invalid NFO field mapping %q, expected nfo-field:metadata-field (e.g. 'plot:clean_description')", entry)
+		}
+		if !slices.Contains(nfo.MappableFields, field) {
+			return nil, fmt.Errorf("unsupported NFO field %q, expected one of %s", field, strings.Join(nfo.MappableFields, ", "))
+		}
+		if sug := parsing.SuggestField(metaField); sug != "" {
+			logging.I("NFO field %q maps unknown metadata field %q, did you mean %q? (fields set by meta ops are fine)", field, metaField, sug)
+		}
+		fieldMap[field] = metaField
+	}
+	return fieldMap, nil
+}
+
+// ValidateTranscribe checks the whisper.cpp model file exists, if set, and the transcription language.
+func ValidateTranscribe(model, lang string) error {
+	if model != "" {
+		if err := whisper.ValidateModel(model); err != nil {
+			return err
+		}
+	}
+	return whisper.ValidateLanguage(lang)
+}
+
+// ValidateRetention checks the keep last and keep days retention policies.
+func ValidateRetention(keepLast, keepDays int) error {
+	if keepLast < 0 {
+		return fmt.Errorf("keep last cannot be negative, got %d", keepLast)
+	}
+	if keepDays < 0 {
+		return fmt.Errorf("keep days cannot be negative, got %d", keepDays)
+	}
+	return nil
+}
+
+// ValidateTags normalizes channel tags to lowercase, checking they are single words such as "news" or "kids-shows".
+func ValidateTags(tags []string) ([]string, error) {
+	valid := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if strings.ContainsAny(t, " \t,:") {
+			return nil, fmt.Errorf("invalid tag %q, tags cannot contain spaces, commas, or colons", t)
+		}
+		if !slices.Contains(valid, t) {
+			valid = append(valid, t)
+		}
+	}
+	if len(valid) == 0 {
+		return nil, errors.New("please enter at least one tag")
+	}
+	return valid, nil
+}
+
+// ValidateLive checks the live URL is an http or https URL and the check frequency isn't negative.
+func ValidateLive(liveURL string, checkFreq int) error {
+	if checkFreq < 0 {
+		return fmt.Errorf("live check frequency cannot be negative, got %d", checkFreq)
+	}
+	if liveURL == "" {
+		return nil
+	}
+	u, err := url.Parse(liveURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid live URL %q, expected an http or https URL", liveURL)
+	}
+	return nil
+}
+
+// ValidateMaxRate checks a download rate cap such as "4M".
+func ValidateMaxRate(rate string) error {
+	_, err := parsing.ParseRate(rate)
+	return err
+}
+
+// ValidateMaxTotalSize checks a channel disk usage cap such as "50G".
+func ValidateMaxTotalSize(size string) error {
+	_, err := parsing.ParseSize(size)
+	return err
+}
+
+// ValidateFetcher checks the download backend is supported.
+func ValidateFetcher(fetcher string) error {
+	switch fetcher {
+	case "", consts.FetcherYTDLP, consts.FetcherGalleryDL, consts.FetcherStreamlink:
+		return nil
+	}
+	return fmt.Errorf("unsupported fetcher %q, expected %s, %s, or %s",
+		fetcher, consts.FetcherYTDLP, consts.FetcherGalleryDL, consts.FetcherStreamlink)
+}
+
+// ValidateAuthMethod checks the channel login method is supported.
+func ValidateAuthMethod(method string) error {
+	switch method {
+	case "", consts.AuthMethodForm, consts.AuthMethodBrowser:
+		return nil
+	}
+	return fmt.Errorf("unsupported login method %q, expected %s or %s", method, consts.AuthMethodForm, consts.AuthMethodBrowser)
+}
+
+// ValidateFetcherRules checks "pattern:fetcher" rules choosing a download backend by video URL.
+func ValidateFetcherRules(rules []string) error {
+	for _, rule := range rules {
+		i := strings.LastIndex(rule, ":")
+		if i < 1 {
+			return fmt.Errorf("invalid fetcher rule %q, expected 'pattern:fetcher' (e.g. 'twitch\\.tv:streamlink')", rule)
+		}
+		if _, err := regexp.Compile(rule[:i]); err != nil {
+			return fmt.Errorf("invalid pattern in fetcher rule %q: %w", rule, err)
+		}
+		if rule[i+1:] == "" {
+			return fmt.Errorf("fetcher rule %q is missing a fetcher", rule)
+		}
+		if err := ValidateFetcher(rule[i+1:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sponsorBlockCategories are the SponsorBlock categories accepted by yt-dlp.
+var sponsorBlockCategories = map[string]bool{
+	"all": true, "default": true, "sponsor": true, "intro": true, "outro": true, "selfpromo": true, "preview": true,
+	"filler": true, "interaction": true, "music_offtopic": true, "poi_highlight": true, "chapter": true,
+}
+
+// ValidateSponsorBlock checks a comma separated SponsorBlock category list, entries may be prefixed with '-' to exclude them.
+func ValidateSponsorBlock(categories string) error {
+	if categories == "" {
+		return nil
+	}
+	for _, c := range strings.Split(categories, ",") {
+		if !sponsorBlockCategories[strings.TrimPrefix(strings.TrimSpace(c), "-")] {
+			return fmt.Errorf("invalid SponsorBlock category %q in %q", c, categories)
+		}
+	}
+	return nil
+}
+
+// proxySchemes are the proxy schemes supported by both yt-dlp and Go's HTTP client.
+var proxySchemes = map[string]bool{
+	"http":    true,
+	"https":   true,
+	"socks5":  true,
+	"socks5h": true,
+}
+
+// ValidateProxies checks each proxy is an absolute URL with a supported scheme, e.g. "socks5://127.0.0.1:1080".
+func ValidateProxies(proxies []string) error {
+	for _, p := range proxies {
+		u, err := url.Parse(p)
+		if err != nil || !proxySchemes[strings.ToLower(u.Scheme)] || u.Host == "" {
+			return fmt.Errorf("invalid proxy %q, expected a URL such as 'http://host:port' or 'socks5://host:port'", p)
+		}
+	}
+	return nil
+}
+
+// ValidateFallbackProxy checks the fallback proxy is empty or a valid proxy URL.
+func ValidateFallbackProxy(p string) error {
+	if p == "" {
+		return nil
+	}
+	return ValidateProxies([]string{p})
+}
+
+// ValidatePostsURL checks the posts feed is an absolute HTTP(S) URL.
+func ValidatePostsURL(postsURL string) error {
+	if postsURL == "" {
+		return nil
+	}
+	u, err := url.Parse(postsURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid posts URL %q, expected an http or https URL", postsURL)
+	}
+	return nil
+}
+
+// ValidateExternalDLConnections checks the external downloader connection count is valid for the downloader.
+func ValidateExternalDLConnections(n int, downloader string) error {
+	if n < 0 {
+		return fmt.Errorf("external downloader connections cannot be negative, got %d", n)
+	}
+	if n == 0 {
+		return nil
+	}
+	switch downloader {
+	case consts.DownloaderAria:
+		if n > consts.MaxAriaConnections {
+			return fmt.Errorf("%s allows at most %d connections per server, got %d", consts.DownloaderAria, consts.MaxAriaConnections, n)
+		}
+	case consts.DownloaderAxel:
+	case "":
+		return errors.New("external downloader connections require an external downloader to be set")
+	default:
+		return fmt.Errorf("connection count is only supported for %s and %s, not %q", consts.DownloaderAria, consts.DownloaderAxel, downloader)
+	}
+	return nil
+}
+
+// ValidatePlaylistMatch checks the playlist title pattern is a valid glob.
+func ValidatePlaylistMatch(pattern string) error {
+	if _, err := parsing.CompileGlob(pattern); err != nil {
+		return fmt.Errorf("invalid playlist match pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// ValidateBlackoutDates checks and normalizes blackout dates and date ranges.
+func ValidateBlackoutDates(blackouts []string) ([]string, error) {
+	valid := make([]string, 0, len(blackouts))
+	for _, b := range blackouts {
+		b = strings.ReplaceAll(b, " ", "")
+		if b == "" {
+			continue
+		}
+		if _, _, err := parsing.ParseBlackout(b); err != nil {
+			return nil, err
+		}
+		valid = append(valid, b)
+	}
+	return valid, nil
+}
+
+// externalIDFormats are the accepted ID formats for each media server ID provider.
+var externalIDFormats = map[string]*regexp.Regexp{
+	"tvdb": regexp.MustCompile(`^\d+$`),
+	"tmdb": regexp.MustCompile(`^\d+$`),
+	"imdb": regexp.MustCompile(`^tt\d{7,}$`),
+}
+
+// ValidateExternalIDs parses provider:id pairs, checking each ID matches its provider's format.
+func ValidateExternalIDs(externalIDs []string) (map[string]string, error) {
+	if len(externalIDs) == 0 {
+		return nil, nil
+	}
+	ids := make(map[string]string, len(externalIDs))
+	for _, entry := range externalIDs {
+		provider, id, ok := strings.Cut(strings.ReplaceAll(entry, " ", ""), ":")
+		provider = strings.ToLower(provider)
+		if !ok || provider == "" || id == "" {
+			return nil, fmt.Errorf("invalid external ID %q, expected provider:id (e.g. 'tvdb:81189')", entry)
+		}
+		format, exists := externalIDFormats[provider]
+		if !exists {
+			return nil, fmt.Errorf("unsupported external ID provider %q, expected tvdb, tmdb, or imdb", provider)
+		}
+		if !format.MatchString(id) {
+			return nil, fmt.Errorf("invalid %s ID %q", provider, id)
+		}
+		ids[provider] = id
+	}
+	return ids, nil
+}
+
+// ValidateSchedule checks the crawl cron expression, quiet hours, and jitter.
+func ValidateSchedule(crawlCron, quietHours string, jitter int) error {
+	if crawlCron != "" {
+		if _, err := schedule.ParseCron(crawlCron); err != nil {
+			return err
+		}
+	}
+	if quietHours != "" {
+		if _, err := schedule.ParseQuietHours(quietHours); err != nil {
+			return err
+		}
+	}
+	if jitter < 0 {
+		return fmt.Errorf("crawl jitter must not be negative, got %d", jitter)
+	}
+	return nil
+}
+
+// ValidateURLPatterns checks that the URL allow/block patterns compile as regular expressions.
+func ValidateURLPatterns(patterns []string) ([]string, error) {
+	valid := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("invalid URL pattern %q: %w", p, err)
+		}
+		valid = append(valid, p)
+	}
+	return valid, nil
 }
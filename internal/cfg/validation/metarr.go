@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"tubarr/internal/parsing"
 	"tubarr/internal/utils/logging"
 )
 
@@ -19,66 +20,21 @@ func ValidateMetaOps(metaOps []string) ([]string, error) {
 		return metaOps, nil
 	}
 
-	var b strings.Builder
-
 	logging.D(1, "Validating meta operations...")
 	valid := make([]string, 0, len(metaOps))
 	exists := make(map[string]bool, len(metaOps))
 
 	for _, m := range metaOps {
-		split := strings.Split(m, ":")
-		switch len(split) {
-		case 3:
-			switch split[1] {
-			case "append", "copy-to", "paste-from", "prefix", "trim-prefix", "trim-suffix", "replace", "set":
-
-				b.Grow(len(m))
-				b.WriteString(split[0])
-				b.WriteRune(':')
-				b.WriteString(split[1])
-				b.WriteRune(':')
-				b.WriteString(split[2])
-				key := b.String()
-				b.Reset()
-
-				if exists[key] {
-					logging.I(dupMsg, m)
-					continue
-				}
-				exists[key] = true
-				valid = append(valid, m)
-			default:
-				return nil, fmt.Errorf("invalid meta operation %q", split[1])
-			}
-		case 4:
-			if split[1] == "date-tag" {
-				switch split[2] {
-				case "prefix", "suffix":
-					if DateFormat(split[3]) {
-
-						b.Grow(len(m))
-						b.WriteString(split[0])
-						b.WriteRune(':')
-						b.WriteString(split[1])
-						b.WriteRune(':')
-						b.WriteString(split[2]) // Leave out split[3], probably don't want multiple date tags with diff formats
-						key := b.String()
-						b.Reset()
-
-						if exists[key] {
-							logging.I(dupMsg, m)
-							continue
-						}
-						exists[key] = true
-						valid = append(valid, m)
-					}
-				default:
-					return nil, fmt.Errorf("invalid date tag location %q, use prefix or suffix", split[2])
-				}
-			}
-		default:
-			return nil, fmt.Errorf("invalid meta op %q", m)
+		key, err := parsing.ParseMetaOp(m)
+		if err != nil {
+			return nil, err
+		}
+		if exists[key] {
+			logging.I(dupMsg, m)
+			continue
 		}
+		exists[key] = true
+		valid = append(valid, m)
 	}
 
 	if len(valid) != 0 {
@@ -89,11 +45,20 @@ func ValidateMetaOps(metaOps []string) ([]string, error) {
 }
 
 // ValidateFilenameSuffixReplace checks if the input format for filename suffix replacement is valid.
+//
+// Entries in the form 'regex:pattern:replacement' are regex renames, their patterns are compiled here.
 func ValidateFilenameSuffixReplace(fileSfxReplace []string) ([]string, error) {
 	valid := make([]string, 0, len(fileSfxReplace))
 
 	lengthStrings := 0
 	for _, pair := range fileSfxReplace {
+		if _, isRegex, err := parsing.ParseFilenameOp(pair); err != nil {
+			return nil, err
+		} else if isRegex {
+			valid = append(valid, pair)
+			continue
+		}
+
 		parts := strings.Split(pair, ":")
 		if len(parts) < 2 {
 			return nil, errors.New("invalid use of filename-replace-suffix, values must be written as (suffix:replacement)")
@@ -3,11 +3,17 @@ package cfgvideo
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
+	"time"
 	cfgchannel "tubarr/internal/cfg/channel"
+	cfgflags "tubarr/internal/cfg/flags"
 	"tubarr/internal/domain/consts"
 	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
 	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/remote"
 
 	"github.com/spf13/cobra"
 )
@@ -25,24 +31,37 @@ func InitVideoCmds(s interfaces.Store) *cobra.Command {
 
 	vs := s.VideoStore()
 	cs := s.ChannelStore()
+	ds := s.DownloadStore()
+	ss := s.StorageStore()
 
 	// Add subcommands with dependencies
-	vidCmd.AddCommand(deletecmdvideo(vs, cs))
+	vidCmd.AddCommand(deletecmdvideo(vs, cs, ss, s.ConfirmStore()))
+	vidCmd.AddCommand(cfgflags.MarkRemoteSafe(cancelVideoCmd(vs, cs, ds)))
+	vidCmd.AddCommand(requeueVideoCmd(vs, cs, ds))
+	vidCmd.AddCommand(cfgflags.MarkReadOnlySafe(videoStatusCmd(vs, cs, ds)))
+	vidCmd.AddCommand(bulkVideoCmd(vs, cs, ss, s.ConfirmStore()))
+	vidCmd.AddCommand(editMetadataCmd(vs, cs))
+	vidCmd.AddCommand(cfgflags.MarkReadOnlySafe(skippedVideosCmd(cs, s.SkipStore())))
+	vidCmd.AddCommand(cfgflags.MarkReadOnlySafe(exportVideosCmd(vs, cs)))
+	vidCmd.AddCommand(cfgflags.MarkReadOnlySafe(feedVideosCmd(vs, cs)))
+	vidCmd.AddCommand(cfgflags.MarkReadOnlySafe(videoTimingsCmd(vs, cs)))
+	vidCmd.AddCommand(cfgflags.MarkReadOnlySafe(searchVideosCmd(vs, cs)))
+	vidCmd.AddCommand(cfgflags.MarkReadOnlySafe(listVideosCmd(vs, cs)))
 
 	return vidCmd
 }
 
 // deletecmdvideo deletes a channel from the database.
-func deletecmdvideo(vs interfaces.VideoStore, cs interfaces.ChannelStore) *cobra.Command {
+func deletecmdvideo(vs interfaces.VideoStore, cs interfaces.ChannelStore, ss interfaces.StorageStore, fs interfaces.ConfirmStore) *cobra.Command {
 	var (
-		chanName, chanURL, url, chanKey, chanVal string
-		chanID                                   int
+		chanName, chanURL, url, chanKey, chanVal, token string
+		chanID                                          int
 	)
 
 	delCmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete video entry",
-		Long:  "Delete a video entry from a channel by URL",
+		Long:  "Delete a video entry from a channel by URL. The first run prints a confirmation token which must be passed with --confirm to delete.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			switch {
@@ -64,9 +83,18 @@ func deletecmdvideo(vs interfaces.VideoStore, cs interfaces.ChannelStore) *cobra
 				return err
 			}
 
+			summary := fmt.Sprintf("delete the record of video %q from channel with ID %d", url, cid)
+			confirmed, err := cfgchannel.ConfirmDestructive(fs, fmt.Sprintf("video-delete:%d:%s", cid, url), summary, token)
+			if err != nil || !confirmed {
+				return err
+			}
+
 			if err := vs.DeleteVideo(consts.QVidURL, url, cid); err != nil {
 				return err
 			}
+			if _, err := ss.RefreshChannelStorage(cid); err != nil {
+				logging.E(0, "Failed to refresh storage usage for channel with ID %d: %v", cid, err)
+			}
 			logging.S(0, "Successfully deleted video with URL %q", url)
 			return nil
 		},
@@ -75,6 +103,271 @@ func deletecmdvideo(vs interfaces.VideoStore, cs interfaces.ChannelStore) *cobra
 	// Primary channel elements
 	cfgchannel.SetPrimaryChannelFlags(delCmd, &chanName, &chanURL, &chanID)
 	delCmd.Flags().StringVar(&url, "delete-url", "", "Video URL")
+	cfgchannel.SetConfirmFlag(delCmd, &token)
 
 	return delCmd
 }
+
+// cancelVideoCmd marks a video as cancelled by the user, so it is not retried in crawls, stopping it if it is
+// being downloaded by a running instance.
+func cancelVideoCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore, ds interfaces.DownloadStore) *cobra.Command {
+	var (
+		chanName, chanURL, url string
+		chanID                 int
+	)
+
+	cancelCmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a video download",
+		Long:  "Marks a video as cancelled by the user. It will not be downloaded in crawls until requeued. A download in progress in a running Tubarr instance is stopped.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vid, err := getVideoID(vs, cs, chanID, chanName, chanURL, url)
+			if err != nil {
+				return err
+			}
+
+			// Running instances serving HTTP stop the download at once, others notice the cancellation in the database
+			if addr := cfgflags.RemoteAddr(); addr != "" {
+				if err := remote.Call(addr, cfgflags.APIToken(), http.MethodPost, fmt.Sprintf("/api/videos/%d/cancel", vid), nil); err != nil {
+					return err
+				}
+			} else if err := ds.CancelDownload(vid, consts.CancelUser); err != nil {
+				return err
+			}
+			logging.S(0, "Cancelled video with URL %q", url)
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(cancelCmd, &chanName, &chanURL, &chanID)
+	cancelCmd.Flags().StringVar(&url, "video-url", "", "Video URL")
+
+	return cancelCmd
+}
+
+// requeueVideoCmd resets a video to pending, clearing any cancellation.
+func requeueVideoCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore, ds interfaces.DownloadStore) *cobra.Command {
+	var (
+		chanName, chanURL, url string
+		chanID                 int
+	)
+
+	requeueCmd := &cobra.Command{
+		Use:   "requeue",
+		Short: "Requeue a video download",
+		Long:  "Resets a video to pending, clearing any cancellation so the next crawl downloads it again.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vid, err := getVideoID(vs, cs, chanID, chanName, chanURL, url)
+			if err != nil {
+				return err
+			}
+
+			if err := ds.RequeueDownload(vid); err != nil {
+				return err
+			}
+			logging.S(0, "Requeued video with URL %q", url)
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(requeueCmd, &chanName, &chanURL, &chanID)
+	requeueCmd.Flags().StringVar(&url, "video-url", "", "Video URL")
+
+	return requeueCmd
+}
+
+// videoStatusCmd prints the download status details of a video.
+func videoStatusCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore, ds interfaces.DownloadStore) *cobra.Command {
+	var (
+		chanName, chanURL, url string
+		chanID                 int
+	)
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show video download status",
+		Long:  "Displays the download status of a video, including cancellation details and how long each pipeline stage took.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vid, err := getVideoID(vs, cs, chanID, chanName, chanURL, url)
+			if err != nil {
+				return err
+			}
+
+			v := &models.Video{ID: vid, URL: url}
+			if err := ds.GetDownloadStatus(v); err != nil {
+				return err
+			}
+
+			fmt.Printf("\n%sVideo ID: %d%s\nURL: %s\nStatus: %s\nPercentage: %.1f\n", consts.ColorGreen, v.ID, consts.ColorReset, v.URL, v.DownloadStatus.Status, v.DownloadStatus.Pct)
+			if v.DownloadStatus.CancelReason != "" {
+				fmt.Printf("Cancel Reason: %s\nCancelled At: %s\n", v.DownloadStatus.CancelReason, v.DownloadStatus.CancelledAt.Format(time.RFC1123Z))
+			}
+			if v.DownloadStatus.FailReason != "" {
+				fmt.Printf("Fail Reason: %s\n", v.DownloadStatus.FailReason)
+			}
+			if version, err := vs.GetYTDLPVersion(v.ID); err != nil {
+				return err
+			} else if version != "" {
+				fmt.Printf("yt-dlp Version: %s\n", version)
+			}
+
+			timing, err := vs.FetchVideoTiming(v.ID)
+			if err != nil {
+				return err
+			}
+			printVideoTiming(timing)
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(statusCmd, &chanName, &chanURL, &chanID)
+	statusCmd.Flags().StringVar(&url, "video-url", "", "Video URL")
+
+	return statusCmd
+}
+
+// bulkVideoCmd applies a status transition to several videos of a channel at once.
+func bulkVideoCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore, ss interfaces.StorageStore, fs interfaces.ConfirmStore) *cobra.Command {
+	var (
+		chanName, chanURL, action, token string
+		chanID                           int
+		urls                             []string
+		ids                              []int64
+	)
+
+	bulkCmd := &cobra.Command{
+		Use:   "bulk",
+		Short: "Apply an action to several videos",
+		Long:  "Applies ignore, unignore, requeue, delete-files-keep-record, or redownload (delete files and requeue) to several videos in a channel. Changes are only made if every video succeeds. Actions deleting files must be confirmed with the token printed on the first run.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(urls) == 0 && len(ids) == 0 {
+				return errors.New("must enter at least one video URL or ID")
+			}
+
+			chanKey, chanVal, err := chanKeyVal(chanID, chanName, chanURL)
+			if err != nil {
+				return err
+			}
+
+			cid, err := cs.GetID(chanKey, chanVal)
+			if err != nil {
+				return err
+			}
+
+			if a := consts.BulkAction(action); a == consts.BulkDeleteFilesKeep || a == consts.BulkRedownload {
+				summary := fmt.Sprintf("delete the downloaded files of %d videos in channel with ID %d, keeping their records", len(ids)+len(urls), cid)
+				confirmed, err := cfgchannel.ConfirmDestructive(fs, models.BulkOperation(cid, action, ids, urls), summary, token)
+				if err != nil || !confirmed {
+					return err
+				}
+			}
+
+			results, err := vs.BulkVideoAction(cid, consts.BulkAction(action), ids, urls)
+			for _, r := range results {
+				if r.Err != nil {
+					fmt.Printf("%sFailed:%s %d %s: %v\n", consts.ColorRed, consts.ColorReset, r.VideoID, r.URL, r.Err)
+					continue
+				}
+				fmt.Printf("%sOK:%s %d %s\n", consts.ColorGreen, consts.ColorReset, r.VideoID, r.URL)
+			}
+			if err != nil {
+				return err
+			}
+
+			if a := consts.BulkAction(action); a == consts.BulkDeleteFilesKeep || a == consts.BulkRedownload {
+				if _, err := ss.RefreshChannelStorage(cid); err != nil {
+					logging.E(0, "Failed to refresh storage usage for channel with ID %d: %v", cid, err)
+				}
+			}
+			logging.S(0, "Applied %s to %d videos", action, len(results))
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(bulkCmd, &chanName, &chanURL, &chanID)
+	bulkCmd.Flags().StringVar(&action, "action", "", "Action to apply (ignore, unignore, requeue, delete-files-keep-record, redownload)")
+	bulkCmd.Flags().StringSliceVar(&urls, "video-url", nil, "Video URLs")
+	bulkCmd.Flags().Int64SliceVar(&ids, "video-id", nil, "Video IDs")
+	cfgchannel.SetConfirmFlag(bulkCmd, &token)
+
+	return bulkCmd
+}
+
+// chanKeyVal returns the channel lookup key and value.
+func chanKeyVal(chanID int, chanName, chanURL string) (chanKey, chanVal string, err error) {
+	switch {
+	case chanID != 0:
+		return consts.QChanID, strconv.Itoa(chanID), nil
+	case chanURL != "":
+		return consts.QChanURL, chanURL, nil
+	case chanName != "":
+		return consts.QChanName, chanName, nil
+	}
+	return "", "", errors.New("must enter a channel ID, name, or URL")
+}
+
+// skippedVideosCmd lists recorded skipped video candidates for a channel.
+func skippedVideosCmd(cs interfaces.ChannelStore, ss interfaces.SkipStore) *cobra.Command {
+	var (
+		chanName, chanURL string
+		chanID            int
+	)
+
+	skippedCmd := &cobra.Command{
+		Use:   "skipped",
+		Short: "List skipped videos",
+		Long:  "Lists videos which were skipped by filters or URL patterns, with the reason. Requires --record-skips during crawls.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chanKey, chanVal, err := chanKeyVal(chanID, chanName, chanURL)
+			if err != nil {
+				return err
+			}
+
+			cid, err := cs.GetID(chanKey, chanVal)
+			if err != nil {
+				return err
+			}
+
+			skipped, err := ss.FetchSkipped(cid)
+			if err != nil {
+				return err
+			}
+			if len(skipped) == 0 {
+				logging.I("No skipped videos recorded for channel with ID %d", cid)
+				return nil
+			}
+
+			for _, sv := range skipped {
+				fmt.Printf("\n%sURL: %s%s\nReason: %s\nDetail: %s\nSkipped At: %s\n", consts.ColorGreen, sv.URL, consts.ColorReset, sv.Reason, sv.Detail, sv.CreatedAt.Format(time.RFC1123Z))
+			}
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(skippedCmd, &chanName, &chanURL, &chanID)
+
+	return skippedCmd
+}
+
+// getVideoID resolves a video ID from the channel identifiers and video URL.
+func getVideoID(vs interfaces.VideoStore, cs interfaces.ChannelStore, chanID int, chanName, chanURL, url string) (int64, error) {
+	if url == "" {
+		return 0, errors.New("must enter a video URL")
+	}
+
+	chanKey, chanVal, err := chanKeyVal(chanID, chanName, chanURL)
+	if err != nil {
+		return 0, err
+	}
+
+	cid, err := cs.GetID(chanKey, chanVal)
+	if err != nil {
+		return 0, err
+	}
+	return vs.GetVideoID(cid, url)
+}
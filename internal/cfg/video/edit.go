@@ -0,0 +1,89 @@
+package cfgvideo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	cfgchannel "tubarr/internal/cfg/channel"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/jsonutils"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// editMetadataCmd manually corrects the title, description, or upload date of a video.
+func editMetadataCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		chanName, chanURL, url       string
+		title, description, uploadAt string
+		chanID                       int
+		rewriteJSON                  bool
+	)
+
+	editCmd := &cobra.Command{
+		Use:   "edit-metadata",
+		Short: "Correct video metadata",
+		Long:  "Corrects the title, description, or upload date stored for a video. Use --rewrite-json to also update the video's JSON file. The edit is recorded in the channel activity feed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vid, err := getVideoID(vs, cs, chanID, chanName, chanURL, url)
+			if err != nil {
+				return err
+			}
+
+			var e models.VideoMetadataEdit
+			if cmd.Flags().Changed("title") {
+				e.Title = &title
+			}
+			if cmd.Flags().Changed("description") {
+				e.Description = &description
+			}
+			if cmd.Flags().Changed("upload-date") {
+				t, err := time.Parse("2006-01-02", uploadAt)
+				if err != nil {
+					return fmt.Errorf("invalid upload date %q, must be in the format YYYY-MM-DD", uploadAt)
+				}
+				e.UploadDate = &t
+			}
+			fields := e.Fields()
+			if len(fields) == 0 {
+				return errors.New("must enter at least one of --title, --description, or --upload-date")
+			}
+
+			v, err := vs.EditVideoMetadata(vid, &e)
+			if err != nil {
+				return err
+			}
+
+			if rewriteJSON {
+				if v.JSONPath == "" {
+					return fmt.Errorf("metadata updated in database, but video with URL %q has no JSON file to rewrite", v.URL)
+				}
+				if err := jsonutils.RewriteSidecar(v.JSONPath, e.MetadataFields()); err != nil {
+					return fmt.Errorf("metadata updated in database, but JSON rewrite failed: %w", err)
+				}
+				logging.S(0, "Rewrote JSON file %q", v.JSONPath)
+			}
+
+			if err := cs.RecordChannelEvent(v.ChannelID, consts.ActivityMetadataEdit, v.URL+": "+strings.Join(fields, ", ")); err != nil {
+				logging.E(0, "Failed to record metadata edit for video with URL %q: %v", v.URL, err)
+			}
+			logging.S(0, "Updated %s for video with URL %q", strings.Join(fields, ", "), v.URL)
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(editCmd, &chanName, &chanURL, &chanID)
+	editCmd.Flags().StringVar(&url, "video-url", "", "Video URL")
+
+	editCmd.Flags().StringVar(&title, "title", "", "Corrected video title")
+	editCmd.Flags().StringVar(&description, "description", "", "Corrected video description")
+	editCmd.Flags().StringVar(&uploadAt, "upload-date", "", "Corrected upload date (YYYY-MM-DD)")
+	editCmd.Flags().BoolVar(&rewriteJSON, "rewrite-json", false, "Also write the corrections to the video's JSON file")
+
+	return editCmd
+}
@@ -0,0 +1,188 @@
+package cfgvideo
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	cfgchannel "tubarr/internal/cfg/channel"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	exportCSV   = "csv"
+	exportJSONL = "jsonl"
+)
+
+// exportFields maps export field names to their value in a video.
+var exportFields = map[string]func(v *models.Video) any{
+	"id":          func(v *models.Video) any { return v.ID },
+	"url":         func(v *models.Video) any { return v.URL },
+	"title":       func(v *models.Video) any { return v.Title },
+	"description": func(v *models.Video) any { return v.Description },
+	"upload_date": func(v *models.Video) any { return formatExportTime(v.UploadDate) },
+	"created_at":  func(v *models.Video) any { return formatExportTime(v.CreatedAt) },
+	"status":      func(v *models.Video) any { return string(v.DownloadStatus.Status) },
+	"path":        func(v *models.Video) any { return v.VideoPath },
+	"json_path":   func(v *models.Video) any { return v.JSONPath },
+	"filesize":    func(v *models.Video) any { return fileSize(v.VideoPath) },
+}
+
+// exportVideosCmd writes a channel's video metadata as CSV or JSON lines.
+func exportVideosCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		chanName, chanURL string
+		chanID            int
+		format, output    string
+		fields            []string
+	)
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export video metadata",
+		Long:  "Writes the channel's videos as CSV or JSON lines for analysis in other tools. Available fields: " + strings.Join(exportFieldNames(), ", ") + ".",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format = strings.ToLower(format)
+			if format != exportCSV && format != exportJSONL {
+				return fmt.Errorf("invalid export format %q, must be %s or %s", format, exportCSV, exportJSONL)
+			}
+
+			for i, f := range fields {
+				fields[i] = strings.ToLower(strings.TrimSpace(f))
+				if _, ok := exportFields[fields[i]]; !ok {
+					return fmt.Errorf("unknown export field %q, available fields: %s", f, strings.Join(exportFieldNames(), ", "))
+				}
+			}
+			if len(fields) == 0 {
+				return errors.New("must enter at least one field to export")
+			}
+
+			chanKey, chanVal, err := chanKeyVal(chanID, chanName, chanURL)
+			if err != nil {
+				return err
+			}
+
+			cid, err := cs.GetID(chanKey, chanVal)
+			if err != nil {
+				return err
+			}
+
+			var out io.Writer = os.Stdout
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create export file: %w", err)
+				}
+				defer func() {
+					if err := f.Close(); err != nil {
+						logging.E(0, "Failed to close export file %q: %v", output, err)
+					}
+				}()
+				out = f
+			}
+
+			w := bufio.NewWriter(out)
+			n, err := writeExport(w, vs, cid, format, fields)
+			if err != nil {
+				return err
+			}
+			if err := w.Flush(); err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+
+			if output != "" {
+				logging.S(0, "Exported %d videos to %q", n, output)
+			}
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(exportCmd, &chanName, &chanURL, &chanID)
+	exportCmd.Flags().StringVar(&format, "format", exportCSV, "Export format (csv or jsonl)")
+	exportCmd.Flags().StringSliceVar(&fields, "fields", []string{"title", "url", "upload_date", "filesize", "path"}, "Fields to export, in order")
+	exportCmd.Flags().StringVar(&output, "output", "", "File to write to (defaults to stdout)")
+
+	return exportCmd
+}
+
+// writeExport streams the channel's videos to the writer, returning the number written.
+func writeExport(w io.Writer, vs interfaces.VideoStore, chanID int64, format string, fields []string) (n int, err error) {
+	switch format {
+	case exportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(fields); err != nil {
+			return 0, err
+		}
+
+		record := make([]string, len(fields))
+		err = vs.StreamChannelVideos(chanID, func(v *models.Video) error {
+			for i, f := range fields {
+				if val := exportFields[f](v); val != nil {
+					record[i] = fmt.Sprint(val)
+				} else {
+					record[i] = ""
+				}
+			}
+			n++
+			return cw.Write(record)
+		})
+		cw.Flush()
+		if err == nil {
+			err = cw.Error()
+		}
+
+	case exportJSONL:
+		enc := json.NewEncoder(w)
+		err = vs.StreamChannelVideos(chanID, func(v *models.Video) error {
+			row := make(map[string]any, len(fields))
+			for _, f := range fields {
+				row[f] = exportFields[f](v)
+			}
+			n++
+			return enc.Encode(row)
+		})
+	}
+	return n, err
+}
+
+// exportFieldNames returns the sorted export field names.
+func exportFieldNames() []string {
+	names := make([]string, 0, len(exportFields))
+	for name := range exportFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatExportTime formats a time as RFC3339, or blank if unset.
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// fileSize returns the size of a file in bytes, or nil if it can't be read.
+func fileSize(path string) any {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	return info.Size()
+}
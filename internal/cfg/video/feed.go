@@ -0,0 +1,119 @@
+package cfgvideo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cfgchannel "tubarr/internal/cfg/channel"
+	"tubarr/internal/feed"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// feedVideosCmd writes an RSS feed of a channel's downloaded videos.
+func feedVideosCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		chanName, chanURL string
+		chanID, limit     int
+		baseURL, output   string
+	)
+
+	feedCmd := &cobra.Command{
+		Use:   "feed",
+		Short: "Export an RSS feed",
+		Long:  "Writes an RSS feed of the channel's downloaded videos, with enclosures linking to the local files, for media players and podcast apps. Use --base-url to link to files served over HTTP from the channel's video directory.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chanKey, chanVal, err := chanKeyVal(chanID, chanName, chanURL)
+			if err != nil {
+				return err
+			}
+
+			cid, err := cs.GetID(chanKey, chanVal)
+			if err != nil {
+				return err
+			}
+
+			c, err, hasRows := cs.FetchChannel(cid)
+			if !hasRows {
+				return fmt.Errorf("channel with ID %d does not exist", cid)
+			}
+			if err != nil {
+				return err
+			}
+
+			if baseURL != "" {
+				if _, err := url.ParseRequestURI(baseURL); err != nil {
+					return fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+				}
+			}
+
+			var out io.Writer = os.Stdout
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create feed file: %w", err)
+				}
+				defer func() {
+					if err := f.Close(); err != nil {
+						logging.E(0, "Failed to close feed file %q: %v", output, err)
+					}
+				}()
+				out = f
+			}
+
+			w := bufio.NewWriter(out)
+			n, err := writeFeed(w, vs, c, baseURL, limit)
+			if err != nil {
+				return err
+			}
+			if err := w.Flush(); err != nil {
+				return fmt.Errorf("failed to write feed: %w", err)
+			}
+
+			if output != "" {
+				logging.S(0, "Wrote feed with %d videos to %q", n, output)
+			}
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(feedCmd, &chanName, &chanURL, &chanID)
+	feedCmd.Flags().StringVar(&baseURL, "base-url", "", "URL the channel's video directory is served at (defaults to file:// links)")
+	feedCmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of videos, newest first (0 for all)")
+	feedCmd.Flags().StringVar(&output, "output", "", "File to write to (defaults to stdout)")
+
+	return feedCmd
+}
+
+// writeFeed writes the RSS feed for the channel's downloaded videos, returning the number of items.
+func writeFeed(w io.Writer, vs interfaces.VideoStore, c *models.Channel, baseURL string, limit int) (int, error) {
+	root := filepath.Clean(parsing.StaticPrefix(c.VideoDir))
+	return feed.Write(w, vs, c, limit, func(v *models.Video) string {
+		return fileLink(v.VideoPath, root, baseURL)
+	})
+}
+
+// fileLink returns a link to the file, under the base URL if the file is inside the root directory.
+func fileLink(path, root, baseURL string) string {
+	if baseURL != "" {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			parts := strings.Split(filepath.ToSlash(rel), "/")
+			for i, p := range parts {
+				parts[i] = url.PathEscape(p)
+			}
+			return strings.TrimSuffix(baseURL, "/") + "/" + strings.Join(parts, "/")
+		}
+		logging.E(0, "File %q is outside the channel video directory %q, linking to the local file", path, root)
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
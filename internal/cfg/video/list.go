@@ -0,0 +1,126 @@
+package cfgvideo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	cfgchannel "tubarr/internal/cfg/channel"
+	cfgoutput "tubarr/internal/cfg/output"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// videoOutput is a video as written by 'video list' for scripts.
+type videoOutput struct {
+	ID         int64     `json:"id"`
+	ChannelID  int64     `json:"channel_id"`
+	URL        string    `json:"url"`
+	Title      string    `json:"title"`
+	Status     string    `json:"status"`
+	Percentage float64   `json:"percentage"`
+	UploadDate string    `json:"upload_date,omitempty"`
+	VideoPath  string    `json:"video_path,omitempty"`
+	JSONPath   string    `json:"json_path,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// listVideosCmd lists a channel's videos and their download status.
+func listVideosCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		chanName, chanURL string
+		chanID, limit     int
+		status, format    string
+	)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List videos",
+		Long:  "Lists a channel's recorded videos with their download status and paths, optionally only those with a status such as 'Finished' or 'Failed'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfgoutput.Validate(&format); err != nil {
+				return err
+			}
+
+			chanKey, chanVal, err := chanKeyVal(chanID, chanName, chanURL)
+			if err != nil {
+				return err
+			}
+			cid, err := cs.GetID(chanKey, chanVal)
+			if err != nil {
+				return err
+			}
+
+			videos := []videoOutput{}
+			if err := vs.StreamChannelVideos(cid, func(v *models.Video) error {
+				if status != "" && !strings.EqualFold(string(v.DownloadStatus.Status), status) {
+					return nil
+				}
+				if limit > 0 && len(videos) == limit {
+					return nil
+				}
+				videos = append(videos, toVideoOutput(v))
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			switch format {
+			case cfgoutput.JSON, cfgoutput.YAML:
+				return cfgoutput.Print(format, videos)
+			case cfgoutput.Table:
+				rows := make([][]string, 0, len(videos))
+				for _, v := range videos {
+					rows = append(rows, []string{strconv.FormatInt(v.ID, 10), v.Status, v.UploadDate, v.Title, v.URL})
+				}
+				return cfgoutput.PrintTable([]string{"ID", "STATUS", "UPLOADED", "TITLE", "URL"}, rows)
+			}
+
+			if len(videos) == 0 {
+				logging.I("No videos found for channel with %s %q", chanKey, chanVal)
+				return nil
+			}
+			for _, v := range videos {
+				path := v.VideoPath
+				if path == "" {
+					path = "(not downloaded)"
+				}
+				fmt.Printf("\n%s%s%s\nVideo ID: %d\nURL: %s\nUploaded: %s\nPath: %s\nStatus: %s\n",
+					consts.ColorGreen, v.Title, consts.ColorReset, v.ID, v.URL, v.UploadDate, path, v.Status)
+			}
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(listCmd, &chanName, &chanURL, &chanID)
+	listCmd.Flags().StringVar(&status, "status", "", "Only list videos with this download status (Pending, Downloading, Finished, Failed, Cancelled)")
+	listCmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of videos to list (0 for all)")
+	cfgoutput.SetFlag(listCmd, &format)
+
+	return listCmd
+}
+
+// toVideoOutput converts a video for output.
+func toVideoOutput(v *models.Video) videoOutput {
+	out := videoOutput{
+		ID:         v.ID,
+		ChannelID:  v.ChannelID,
+		URL:        v.URL,
+		Title:      v.Title,
+		Status:     string(v.DownloadStatus.Status),
+		Percentage: v.DownloadStatus.Pct,
+		VideoPath:  v.VideoPath,
+		JSONPath:   v.JSONPath,
+		CreatedAt:  v.CreatedAt,
+	}
+	if !v.UploadDate.IsZero() {
+		out.UploadDate = v.UploadDate.Format(time.DateOnly)
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package cfgvideo
+
+import (
+	"fmt"
+	"strings"
+
+	cfgchannel "tubarr/internal/cfg/channel"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// searchVideosCmd searches videos across channels by title, description, and metadata.
+func searchVideosCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		chanName, chanURL string
+		chanID, limit     int
+	)
+
+	searchCmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search videos",
+		Long: "Searches the titles, descriptions, and metadata of every channel's videos, or one channel's if a channel is entered. " +
+			"Videos must contain every word of the query, words match as prefixes.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cid int64
+			if chanID != 0 || chanName != "" || chanURL != "" {
+				chanKey, chanVal, err := chanKeyVal(chanID, chanName, chanURL)
+				if err != nil {
+					return err
+				}
+				if cid, err = cs.GetID(chanKey, chanVal); err != nil {
+					return err
+				}
+			}
+
+			q := strings.Join(args, " ")
+			videos, err := vs.SearchVideos(q, cid, limit)
+			if err != nil {
+				return err
+			}
+			if len(videos) == 0 {
+				logging.I("No videos match %q", q)
+				return nil
+			}
+
+			for _, v := range videos {
+				path := v.VideoPath
+				if path == "" {
+					path = "(not downloaded)"
+				}
+				fmt.Printf("\n%s%s%s\nChannel: %s (ID %d)\nVideo ID: %d\nURL: %s\nPath: %s\nStatus: %s\n",
+					consts.ColorGreen, v.Title, consts.ColorReset, v.Channel.Name, v.ChannelID, v.ID, v.URL, path, v.DownloadStatus.Status)
+			}
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(searchCmd, &chanName, &chanURL, &chanID)
+	searchCmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of videos, newest first (0 for all)")
+
+	return searchCmd
+}
@@ -0,0 +1,157 @@
+package cfgvideo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	cfgchannel "tubarr/internal/cfg/channel"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// pipelinePhase is the time between two pipeline stages.
+//
+// The first reached stage in from is used, e.g. moving starts after Metarr if it ran, else after the download.
+type pipelinePhase struct {
+	name string
+	from []consts.PipelineStage
+	to   consts.PipelineStage
+}
+
+var pipelinePhases = []pipelinePhase{
+	{name: "Queue wait", from: []consts.PipelineStage{consts.StageQueued}, to: consts.StageDownloadStart},
+	{name: "Download", from: []consts.PipelineStage{consts.StageDownloadStart}, to: consts.StageDownloadEnd},
+	{name: "Metarr wait", from: []consts.PipelineStage{consts.StageDownloadEnd}, to: consts.StageMetarrStart},
+	{name: "Metarr", from: []consts.PipelineStage{consts.StageMetarrStart}, to: consts.StageMetarrEnd},
+	{name: "Move", from: []consts.PipelineStage{consts.StageMetarrEnd, consts.StageDownloadEnd}, to: consts.StageMoved},
+	{name: "Notify wait", from: []consts.PipelineStage{consts.StageMoved}, to: consts.StageNotified},
+}
+
+// duration returns the phase duration for a video, if it completed the phase.
+func (p pipelinePhase) duration(t *models.VideoTiming) (time.Duration, bool) {
+	for _, from := range p.from {
+		if _, ok := t.Stages[from]; ok {
+			return t.Between(from, p.to)
+		}
+	}
+	return 0, false
+}
+
+// totalDuration returns the time from queueing to the last stage reached.
+func totalDuration(t *models.VideoTiming) (time.Duration, bool) {
+	for _, last := range []consts.PipelineStage{consts.StageNotified, consts.StageMoved, consts.StageMetarrEnd, consts.StageDownloadEnd} {
+		if _, ok := t.Stages[last]; ok {
+			return t.Between(consts.StageQueued, last)
+		}
+	}
+	return 0, false
+}
+
+// printVideoTiming prints a single video's pipeline timing breakdown.
+func printVideoTiming(t *models.VideoTiming) {
+	if len(t.Stages) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%sPipeline Timing%s\n", consts.ColorGreen, consts.ColorReset)
+	if queued, ok := t.Stages[consts.StageQueued]; ok {
+		fmt.Printf("Queued At: %s\n", queued.Format(time.RFC1123Z))
+	}
+	for _, p := range pipelinePhases {
+		if d, ok := p.duration(t); ok {
+			fmt.Printf("%s: %s\n", p.name, d.Round(time.Millisecond))
+		}
+	}
+	if d, ok := totalDuration(t); ok {
+		fmt.Printf("Total: %s\n", d.Round(time.Millisecond))
+	}
+}
+
+// videoTimingsCmd prints aggregate pipeline stage timings, showing where processing time goes.
+func videoTimingsCmd(vs interfaces.VideoStore, cs interfaces.ChannelStore) *cobra.Command {
+	var (
+		chanName, chanURL string
+		chanID            int
+	)
+
+	timingsCmd := &cobra.Command{
+		Use:   "timings",
+		Short: "Show where processing time goes",
+		Long:  "Summarizes how long videos spend in each pipeline stage (queueing, downloading, Metarr, moving, notifying) for a channel, or all channels if none is given.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cid int64
+			if chanID != 0 || chanName != "" || chanURL != "" {
+				chanKey, chanVal, err := chanKeyVal(chanID, chanName, chanURL)
+				if err != nil {
+					return err
+				}
+				if cid, err = cs.GetID(chanKey, chanVal); err != nil {
+					return err
+				}
+			}
+
+			timings, err := vs.FetchChannelTimings(cid)
+			if err != nil {
+				return err
+			}
+			if len(timings) == 0 {
+				logging.I("No pipeline timings recorded yet")
+				return nil
+			}
+
+			var grandTotal time.Duration
+			totals := make([]time.Duration, len(pipelinePhases))
+			samples := make([][]time.Duration, len(pipelinePhases))
+			for _, t := range timings {
+				for i, p := range pipelinePhases {
+					if d, ok := p.duration(t); ok {
+						totals[i] += d
+						grandTotal += d
+						samples[i] = append(samples[i], d)
+					}
+				}
+			}
+
+			fmt.Printf("\n%sPipeline Timings (%d videos)%s\n", consts.ColorGreen, len(timings), consts.ColorReset)
+			fmt.Printf("%-12s %6s %12s %12s %12s %7s\n", "Stage", "Videos", "Mean", "Median", "P90", "Share")
+			for i, p := range pipelinePhases {
+				n := len(samples[i])
+				if n == 0 {
+					fmt.Printf("%-12s %6d %12s %12s %12s %7s\n", p.name, 0, "-", "-", "-", "-")
+					continue
+				}
+				sort.Slice(samples[i], func(a, b int) bool { return samples[i][a] < samples[i][b] })
+
+				var share float64
+				if grandTotal > 0 {
+					share = float64(totals[i]) / float64(grandTotal) * 100
+				}
+				fmt.Printf("%-12s %6d %12s %12s %12s %6.1f%%\n", p.name, n,
+					(totals[i] / time.Duration(n)).Round(time.Millisecond),
+					percentile(samples[i], 50).Round(time.Millisecond),
+					percentile(samples[i], 90).Round(time.Millisecond),
+					share)
+			}
+			return nil
+		},
+	}
+
+	// Primary channel elements
+	cfgchannel.SetPrimaryChannelFlags(timingsCmd, &chanName, &chanURL, &chanID)
+
+	return timingsCmd
+}
+
+// percentile returns the pth percentile of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	i := (len(sorted)*p + 99) / 100
+	if i > 0 {
+		i--
+	}
+	return sorted[i]
+}
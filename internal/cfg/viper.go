@@ -1,6 +1,8 @@
 package cfg
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -35,6 +37,11 @@ func GetFloat64(key string) float64 {
 	return viper.GetFloat64(key)
 }
 
+// GetDuration returns the value associated with the key as a duration.
+func GetDuration(key string) time.Duration {
+	return viper.GetDuration(key)
+}
+
 // GetString returns the value associated with the key as a string.
 func GetString(key string) string {
 	return viper.GetString(key)
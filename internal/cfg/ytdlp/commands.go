@@ -0,0 +1,121 @@
+// Package cfgytdlp sets up Cobra yt-dlp management commands.
+package cfgytdlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cfgflags "tubarr/internal/cfg/flags"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/ytdlpbin"
+
+	"github.com/spf13/cobra"
+)
+
+// InitYTDLPCmds is the entrypoint for initializing yt-dlp management commands.
+func InitYTDLPCmds(ctx context.Context) *cobra.Command {
+	ytdlpCmd := &cobra.Command{
+		Use:   "ytdlp",
+		Short: "Manage the yt-dlp binary.",
+		Long:  "Check the yt-dlp version, update it, or pin a release downloaded into the Tubarr config directory. A pinned or downloaded release is used instead of yt-dlp in $PATH.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("please specify a subcommand. Use --help to see available subcommands")
+		},
+	}
+
+	ytdlpCmd.AddCommand(cfgflags.MarkReadOnlySafe(versionCmd(ctx)))
+	ytdlpCmd.AddCommand(updateCmd(ctx))
+	ytdlpCmd.AddCommand(pinCmd(ctx))
+	ytdlpCmd.AddCommand(unpinCmd(ctx))
+	return ytdlpCmd
+}
+
+// versionCmd prints the yt-dlp binary in use and its version.
+func versionCmd(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Show the yt-dlp version in use.",
+		Long:  "Prints which yt-dlp binary Tubarr runs, its version, and the pinned release if any.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := ytdlpbin.Version(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Binary: %s\nVersion: %s\n", ytdlpbin.Path(), version)
+			if pinned := ytdlpbin.Pinned(); pinned != "" {
+				fmt.Printf("Pinned: %s\n", pinned)
+			}
+			return nil
+		},
+	}
+}
+
+// updateCmd updates yt-dlp to the latest release.
+func updateCmd(ctx context.Context) *cobra.Command {
+	var managed bool
+
+	update := &cobra.Command{
+		Use:   "update",
+		Short: "Update yt-dlp to the latest release.",
+		Long: "Replaces the release in the Tubarr config directory with the latest one, or runs 'yt-dlp -U' on the yt-dlp in $PATH. " +
+			"Pinned releases are not updated.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if managed && !ytdlpbin.Managed() {
+				err = ytdlpbin.Install(ctx, ytdlpbin.Latest, false)
+			} else {
+				err = ytdlpbin.Update(ctx)
+			}
+			if err != nil {
+				return err
+			}
+			return printInstalled(ctx)
+		},
+	}
+
+	update.Flags().BoolVar(&managed, "managed", false, "Download the latest release into the Tubarr config directory instead of updating yt-dlp in $PATH")
+	return update
+}
+
+// pinCmd downloads a yt-dlp release into the config directory and keeps it from being updated.
+func pinCmd(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pin <version>",
+		Short: "Pin a yt-dlp release.",
+		Long:  "Downloads a yt-dlp release (e.g. '2025.01.15') into the Tubarr config directory and uses it until unpinned, for when a newer release breaks an extractor.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ytdlpbin.Install(ctx, args[0], true); err != nil {
+				return err
+			}
+			return printInstalled(ctx)
+		},
+	}
+}
+
+// unpinCmd removes the managed yt-dlp release.
+func unpinCmd(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unpin",
+		Short: "Remove the pinned or downloaded yt-dlp release.",
+		Long:  "Deletes the yt-dlp release from the Tubarr config directory, returning to yt-dlp in $PATH.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ytdlpbin.Remove(); err != nil {
+				return err
+			}
+			logging.S(0, "Removed the managed yt-dlp release, using %q", ytdlpbin.Path())
+			return nil
+		},
+	}
+}
+
+// printInstalled reports the yt-dlp version now in use.
+func printInstalled(ctx context.Context) error {
+	version, err := ytdlpbin.Version(ctx)
+	if err != nil {
+		return err
+	}
+	logging.S(0, "Using yt-dlp %s at %q", version, ytdlpbin.Path())
+	return nil
+}
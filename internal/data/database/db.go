@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"fmt"
 	"tubarr/internal/domain/setup"
-	"tubarr/internal/utils/logging"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -21,57 +20,32 @@ type Database struct {
 // InitDB returns a new DB control instance.
 //
 // Can initiate or return database, and perform main program operations.
-func InitDB() (d *Database, err error) {
+// In read-only mode the database must already exist, and SQLite rejects any write.
+// Pending migrations are applied unless migrate is false, the baseline schema is always applied.
+func InitDB(readOnly, migrate bool) (d *Database, err error) {
 	d = new(Database)
-	d.DB, err = sql.Open(dbDriver, setup.DBFilePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database at path %q: %w", setup.DBFilePath, err)
-	}
-
-	if err := d.initTables(); err != nil {
-		return nil, fmt.Errorf("failed to initialize tables: %w", err)
-	}
-	return d, nil
-}
-
-// initTables initializes the SQL tables.
-func (d *Database) initTables() error {
-	tx, err := d.DB.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-
-	defer func() {
+	if readOnly {
+		d.DB, err = sql.Open(dbDriver, "file:"+setup.DBFilePath+"?mode=ro")
 		if err != nil {
-			if rollbackErr := tx.Rollback(); rollbackErr != nil {
-				logging.E(0, "transaction rollback failed: %v", rollbackErr)
-			}
+			return nil, fmt.Errorf("failed to open database read-only at path %q: %w", setup.DBFilePath, err)
 		}
-	}()
-
-	if err := initProgramTable(tx); err != nil {
-		return err
-	}
-
-	if err := initChannelsTable(tx); err != nil {
-		return err
-	}
-
-	if err := initVideosTable(tx); err != nil {
-		return err
+		if err := d.DB.Ping(); err != nil {
+			return nil, fmt.Errorf("failed to open database read-only at path %q: %w", setup.DBFilePath, err)
+		}
+		return d, nil
 	}
 
-	if err := initDownloadsTable(tx); err != nil {
-		return err
+	d.DB, err = sql.Open(dbDriver, setup.DBFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database at path %q: %w", setup.DBFilePath, err)
 	}
 
-	if err := initNotifyTable(tx); err != nil {
-		return err
+	target := 0
+	if !migrate {
+		target = baselineVersion
 	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if _, err := Migrate(d.DB, target); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
-
-	return nil
+	return d, nil
 }
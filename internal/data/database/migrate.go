@@ -0,0 +1,384 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	migrationsSQL   = "sql/migrations.sql"
+	baselineVersion = 1
+)
+
+// migration is a numbered schema change.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+	down    func(tx *sql.Tx) error // Nil if the migration can't be rolled back
+}
+
+// migrations are applied in order, new schema changes are appended with the next version.
+var migrations = []migration{
+	{version: baselineVersion, name: "baseline", up: baselineUp},
+	{version: 2, name: "posts", up: initPostsTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS posts")
+		return err
+	}},
+	{version: 3, name: "pauses", up: initPausesTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS pauses")
+		return err
+	}},
+	{version: 4, name: "video yt-dlp version", up: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE videos ADD COLUMN ytdlp_version TEXT NOT NULL DEFAULT ''")
+		return err
+	}, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE videos DROP COLUMN ytdlp_version")
+		return err
+	}},
+	{version: 5, name: "channel tags", up: initTagsTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS channel_tags")
+		return err
+	}},
+	{version: 6, name: "defaults profile", up: initDefaultsTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS defaults")
+		return err
+	}},
+	{version: 7, name: "presets", up: initPresetsTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS presets")
+		return err
+	}},
+	{version: 8, name: "video search", up: initSearchIndex, down: func(tx *sql.Tx) error {
+		for _, t := range []string{"videos_fts_ai", "videos_fts_bd", "videos_fts_bu", "videos_fts_au"} {
+			if _, err := tx.Exec("DROP TRIGGER IF EXISTS " + t); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec("DROP TABLE IF EXISTS videos_fts")
+		return err
+	}},
+	{version: 9, name: "download stats", up: initStatsTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS download_stats")
+		return err
+	}},
+	{version: 10, name: "download resume state", up: func(tx *sql.Tx) error {
+		return execAll(tx,
+			"ALTER TABLE downloads ADD COLUMN partial_path TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE downloads ADD COLUMN fragments_done INTEGER NOT NULL DEFAULT 0",
+			"ALTER TABLE downloads ADD COLUMN fragments_total INTEGER NOT NULL DEFAULT 0")
+	}, down: func(tx *sql.Tx) error {
+		return execAll(tx,
+			"ALTER TABLE downloads DROP COLUMN partial_path",
+			"ALTER TABLE downloads DROP COLUMN fragments_done",
+			"ALTER TABLE downloads DROP COLUMN fragments_total")
+	}},
+	{version: 11, name: "host block streak", up: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE host_stats ADD COLUMN block_streak INTEGER NOT NULL DEFAULT 0")
+		return err
+	}, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE host_stats DROP COLUMN block_streak")
+		return err
+	}},
+	{version: 12, name: "users and channel owners", up: func(tx *sql.Tx) error {
+		if err := initUsersTable(tx); err != nil {
+			return err
+		}
+		_, err := tx.Exec("ALTER TABLE channels ADD COLUMN owner_id INTEGER REFERENCES users(id) ON DELETE SET NULL")
+		return err
+	}, down: func(tx *sql.Tx) error {
+		return execAll(tx,
+			"ALTER TABLE channels DROP COLUMN owner_id",
+			"DROP TABLE IF EXISTS user_sessions",
+			"DROP TABLE IF EXISTS users")
+	}},
+	{version: 13, name: "crawl reports", up: initReportsTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS crawl_reports")
+		return err
+	}},
+	{version: 14, name: "smtp settings", up: initSMTPTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS smtp_settings")
+		return err
+	}},
+	{version: 15, name: "event hooks", up: initHooksTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS event_hooks")
+		return err
+	}},
+	{version: 16, name: "video transcripts", up: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE videos ADD COLUMN transcript_path TEXT NOT NULL DEFAULT ''")
+		return err
+	}, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE videos DROP COLUMN transcript_path")
+		return err
+	}},
+	{version: 17, name: "global ignores", up: initIgnoresTable, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("DROP TABLE IF EXISTS global_ignores")
+		return err
+	}},
+	{version: 18, name: "program http address", up: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE program ADD COLUMN http_addr TEXT NOT NULL DEFAULT ''")
+		return err
+	}, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE program DROP COLUMN http_addr")
+		return err
+	}},
+	{version: 19, name: "pending commands", up: func(tx *sql.Tx) error {
+		if err := initCommandsTable(tx); err != nil {
+			return err
+		}
+		_, err := tx.Exec("ALTER TABLE program ADD COLUMN scheduler INTEGER NOT NULL DEFAULT 0")
+		return err
+	}, down: func(tx *sql.Tx) error {
+		if _, err := tx.Exec("ALTER TABLE program DROP COLUMN scheduler"); err != nil {
+			return err
+		}
+		_, err := tx.Exec("DROP TABLE IF EXISTS pending_commands")
+		return err
+	}},
+	{version: 20, name: "download cancel reasons", up: func(tx *sql.Tx) error {
+		if err := addColumn(tx, "downloads", "cancel_reason", "TEXT"); err != nil {
+			return err
+		}
+		return addColumn(tx, "downloads", "cancelled_at", "TIMESTAMP")
+	}, down: func(tx *sql.Tx) error {
+		return execAll(tx,
+			"ALTER TABLE downloads DROP COLUMN cancel_reason",
+			"ALTER TABLE downloads DROP COLUMN cancelled_at")
+	}},
+	{version: 21, name: "download fail reasons", up: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE downloads ADD COLUMN fail_reason TEXT NOT NULL DEFAULT ''")
+		return err
+	}, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE downloads DROP COLUMN fail_reason")
+		return err
+	}},
+	{version: 22, name: "host bot blocks since tuning", up: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE host_stats ADD COLUMN new_bot_blocks INTEGER NOT NULL DEFAULT 0")
+		return err
+	}, down: func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE host_stats DROP COLUMN new_bot_blocks")
+		return err
+	}},
+}
+
+// MigrationStatus is the applied state of a schema migration.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time // Zero if pending
+}
+
+// LatestVersion returns the newest schema version known to this build.
+func LatestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// SchemaVersion returns the newest migration applied to the database, or 0 if none.
+func SchemaVersion(db *sql.DB) (int, error) {
+	if err := initMigrationsTable(db); err != nil {
+		return 0, err
+	}
+	var v sql.NullInt64
+	if err := db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&v); err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return int(v.Int64), nil
+}
+
+// Migrate applies pending migrations up to and including target, or all pending migrations if target is 0.
+//
+// Each migration runs in its own transaction, returning the versions applied.
+func Migrate(db *sql.DB, target int) (applied []int, err error) {
+	if target == 0 {
+		target = LatestVersion()
+	}
+	if target > LatestVersion() {
+		return nil, fmt.Errorf("unknown schema version %d, the latest is %d", target, LatestVersion())
+	}
+
+	current, err := SchemaVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	if current > LatestVersion() {
+		return nil, fmt.Errorf("database schema version %d is newer than this build supports (%d), update Tubarr or roll back with the newer build", current, LatestVersion())
+	}
+
+	for _, m := range migrations {
+		if m.version <= current || m.version > target {
+			continue
+		}
+		if err := runMigration(db, m, true); err != nil {
+			return applied, err
+		}
+		logging.I("Applied database migration %d (%s)", m.version, m.name)
+		applied = append(applied, m.version)
+	}
+	return applied, nil
+}
+
+// Rollback reverts applied migrations newer than target, newest first, returning the versions reverted.
+func Rollback(db *sql.DB, target int) (reverted []int, err error) {
+	if target < baselineVersion {
+		return nil, errors.New("cannot roll back the baseline schema, target version must be at least 1")
+	}
+
+	current, err := SchemaVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	if current > LatestVersion() {
+		return nil, fmt.Errorf("database schema version %d is newer than this build supports (%d), roll back with the newer build", current, LatestVersion())
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.version > current || m.version <= target {
+			continue
+		}
+		if m.down == nil {
+			return reverted, fmt.Errorf("migration %d (%s) cannot be rolled back", m.version, m.name)
+		}
+		if err := runMigration(db, m, false); err != nil {
+			return reverted, err
+		}
+		logging.I("Rolled back database migration %d (%s)", m.version, m.name)
+		reverted = append(reverted, m.version)
+	}
+	return reverted, nil
+}
+
+// Migrations returns every known migration with its applied time.
+func Migrations(db *sql.DB) ([]MigrationStatus, error) {
+	if err := initMigrationsTable(db); err != nil {
+		return nil, err
+	}
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			v  int
+			at sql.NullTime
+		)
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		appliedAt[v] = at.Time
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		statuses = append(statuses, MigrationStatus{Version: m.version, Name: m.name, AppliedAt: appliedAt[m.version]})
+	}
+	return statuses, nil
+}
+
+// runMigration applies or reverts a migration and records the change in one transaction.
+func runMigration(db *sql.DB, m migration, up bool) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				logging.E(0, "transaction rollback failed: %v", rollbackErr)
+			}
+		}
+	}()
+
+	if up {
+		if err = m.up(tx); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		if _, err = tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", m.version, m.name, time.Now()); err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+		}
+	} else {
+		if err = m.down(tx); err != nil {
+			return fmt.Errorf("rollback of migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		if _, err = tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.version); err != nil {
+			return fmt.Errorf("failed to remove migration record %d: %w", m.version, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
+	}
+	return nil
+}
+
+// execAll executes each statement in order, stopping at the first error.
+func execAll(tx *sql.Tx, stmts ...string) error {
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumn adds a column to a table, skipping it if the table already has one by that name.
+//
+// Databases created before the download cancel columns had a migration already have them from the baseline.
+func addColumn(tx *sql.Tx, table, column, def string) error {
+	var n int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&n); err != nil {
+		return fmt.Errorf("failed to check for column %s.%s: %w", table, column, err)
+	}
+	if n > 0 {
+		return nil
+	}
+	_, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, def))
+	return err
+}
+
+// initMigrationsTable creates the applied migrations table if it doesn't exist.
+func initMigrationsTable(db *sql.DB) error {
+	query, err := readSQLFile(migrationsSQL)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to execute SQL for migrations table: %w", err)
+	}
+	return nil
+}
+
+// baselineUp creates the tables which existed before versioned migrations.
+//
+// Tables are created only if missing, so databases from older builds adopt the baseline as is.
+func baselineUp(tx *sql.Tx) error {
+	for _, init := range []func(tx *sql.Tx) error{
+		initProgramTable,
+		initChannelsTable,
+		initVideosTable,
+		initDownloadsTable,
+		initNotifyTable,
+		initHostsTable,
+		initStorageTable,
+		initSkippedTable,
+		initEventsTable,
+		initConfirmTable,
+		initStagesTable,
+		initWebhooksTable,
+		initRetryTable,
+	} {
+		if err := init(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
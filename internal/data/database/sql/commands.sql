@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS pending_commands (
+    id INTEGER PRIMARY KEY,
+    channel_id INTEGER NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    action TEXT NOT NULL,
+    urls TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS confirmations (
+    token TEXT PRIMARY KEY,
+    operation TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
\ No newline at end of file
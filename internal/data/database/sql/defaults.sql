@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS defaults (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    settings TEXT NOT NULL DEFAULT '{}',
+    metarr TEXT NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT OR IGNORE INTO defaults (id) VALUES (1);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS event_hooks (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    event TEXT NOT NULL,
+    path TEXT NOT NULL,
+    args TEXT NOT NULL DEFAULT '[]',
+    timeout_secs INTEGER NOT NULL DEFAULT 60,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_event_hooks_event ON event_hooks(event);
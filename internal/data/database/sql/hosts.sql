@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS host_stats (
+    hostname TEXT PRIMARY KEY,
+    successes INTEGER DEFAULT 0 NOT NULL,
+    failures INTEGER DEFAULT 0 NOT NULL,
+    bot_blocks INTEGER DEFAULT 0 NOT NULL,
+    concurrency INTEGER DEFAULT 0 NOT NULL,
+    delay_seconds INTEGER DEFAULT 0 NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
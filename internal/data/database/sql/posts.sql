@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS posts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    channel_id INTEGER NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    post_id TEXT NOT NULL,
+    url TEXT,
+    text TEXT,
+    files TEXT,
+    directory TEXT NOT NULL,
+    posted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(channel_id, post_id)
+);
+CREATE INDEX IF NOT EXISTS idx_posts_channel ON posts(channel_id, created_at);
\ No newline at end of file
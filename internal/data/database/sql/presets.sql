@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS presets (
+    name TEXT PRIMARY KEY,
+    settings TEXT NOT NULL DEFAULT '{}',
+    metarr TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS crawl_reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    new_videos INTEGER DEFAULT 0 NOT NULL,
+    failures INTEGER DEFAULT 0 NOT NULL,
+    filtered INTEGER DEFAULT 0 NOT NULL,
+    channels TEXT DEFAULT '[]' NOT NULL,
+    digest_sent INTEGER DEFAULT 0 NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_crawl_reports_started ON crawl_reports(started_at);
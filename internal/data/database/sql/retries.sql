@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS retry_queue (
+    channel_id INTEGER NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (channel_id, url)
+);
+CREATE INDEX IF NOT EXISTS idx_retry_queue_next ON retry_queue(next_attempt_at);
\ No newline at end of file
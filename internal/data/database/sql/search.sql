@@ -0,0 +1,14 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS videos_fts USING fts4(content="videos", title, description, metadata);
+CREATE TRIGGER IF NOT EXISTS videos_fts_ai AFTER INSERT ON videos BEGIN
+    INSERT INTO videos_fts(docid, title, description, metadata) VALUES (new.id, new.title, new.description, new.metadata);
+END;
+CREATE TRIGGER IF NOT EXISTS videos_fts_bd BEFORE DELETE ON videos BEGIN
+    DELETE FROM videos_fts WHERE docid = old.id;
+END;
+CREATE TRIGGER IF NOT EXISTS videos_fts_bu BEFORE UPDATE OF title, description, metadata ON videos BEGIN
+    DELETE FROM videos_fts WHERE docid = old.id;
+END;
+CREATE TRIGGER IF NOT EXISTS videos_fts_au AFTER UPDATE OF title, description, metadata ON videos BEGIN
+    INSERT INTO videos_fts(docid, title, description, metadata) VALUES (new.id, new.title, new.description, new.metadata);
+END;
+INSERT INTO videos_fts(videos_fts) VALUES ('rebuild');
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS skipped_videos (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    channel_id INTEGER NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    detail TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(channel_id, url)
+);
+CREATE INDEX IF NOT EXISTS idx_skipped_created ON skipped_videos(created_at);
\ No newline at end of file
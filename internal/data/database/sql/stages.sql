@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS video_stages (
+    video_id INTEGER NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    stage TEXT NOT NULL,
+    at TIMESTAMP NOT NULL,
+    PRIMARY KEY(video_id, stage)
+);
\ No newline at end of file
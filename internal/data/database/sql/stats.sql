@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS download_stats (
+    channel_id INTEGER NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    day TEXT NOT NULL,
+    downloads INTEGER DEFAULT 0 NOT NULL,
+    failures INTEGER DEFAULT 0 NOT NULL,
+    bytes INTEGER DEFAULT 0 NOT NULL,
+    duration_ms INTEGER DEFAULT 0 NOT NULL,
+    PRIMARY KEY (channel_id, day)
+);
+CREATE INDEX IF NOT EXISTS idx_download_stats_day ON download_stats(day);
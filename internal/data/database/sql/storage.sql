@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS storage_usage (
+    channel_id INTEGER PRIMARY KEY,
+    video_bytes INTEGER DEFAULT 0 NOT NULL,
+    json_bytes INTEGER DEFAULT 0 NOT NULL,
+    file_count INTEGER DEFAULT 0 NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(channel_id) REFERENCES channels(id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS channel_tags (
+    channel_id INTEGER NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (channel_id, tag)
+);
+CREATE INDEX IF NOT EXISTS idx_channel_tags_tag ON channel_tags(tag);
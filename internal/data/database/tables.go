@@ -11,10 +11,31 @@ var sqlFiles embed.FS
 
 const (
 	channelSQL      = "sql/channels.sql"
+	confirmSQL      = "sql/confirmations.sql"
+	defaultsSQL     = "sql/defaults.sql"
+	presetSQL       = "sql/presets.sql"
+	searchSQL       = "sql/search.sql"
+	smtpSQL         = "sql/smtp.sql"
+	hooksSQL        = "sql/hooks.sql"
+	ignoresSQL      = "sql/ignores.sql"
+	commandsSQL     = "sql/commands.sql"
+	statsSQL        = "sql/stats.sql"
 	downloadSQL     = "sql/downloads.sql"
+	eventSQL        = "sql/events.sql"
+	hostSQL         = "sql/hosts.sql"
 	notificationSQL = "sql/notifications.sql"
+	pauseSQL        = "sql/pauses.sql"
+	postSQL         = "sql/posts.sql"
 	programSQL      = "sql/program.sql"
+	reportSQL       = "sql/reports.sql"
+	retrySQL        = "sql/retries.sql"
+	skippedSQL      = "sql/skipped.sql"
+	tagSQL          = "sql/tags.sql"
+	userSQL         = "sql/users.sql"
+	stageSQL        = "sql/stages.sql"
+	storageSQL      = "sql/storage.sql"
 	videoSQL        = "sql/videos.sql"
+	webhookSQL      = "sql/webhooks.sql"
 )
 
 // initProgramTable initializes the primary program database table.
@@ -42,6 +63,46 @@ func initDownloadsTable(tx *sql.Tx) error {
 	return executeSQLFile(tx, downloadSQL, "downloads table")
 }
 
+// initHostsTable initializes the per-hostname statistics table.
+func initHostsTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, hostSQL, "host stats table")
+}
+
+// initStorageTable initializes the cached per-channel disk usage table.
+func initStorageTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, storageSQL, "storage usage table")
+}
+
+// initSkippedTable initializes the table of skipped video candidates.
+func initSkippedTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, skippedSQL, "skipped videos table")
+}
+
+// initEventsTable initializes the channel event history table.
+func initEventsTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, eventSQL, "channel events table")
+}
+
+// initConfirmTable initializes the table of pending destructive operation confirmations.
+func initConfirmTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, confirmSQL, "confirmations table")
+}
+
+// initWebhooksTable initializes the per-channel webhook notification table.
+func initWebhooksTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, webhookSQL, "webhooks table")
+}
+
+// initRetryTable initializes the queue of failed downloads awaiting retry.
+func initRetryTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, retrySQL, "retry queue table")
+}
+
+// initStagesTable initializes the per-video pipeline stage timing table.
+func initStagesTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, stageSQL, "video stages table")
+}
+
 // readSQLFile reads the SQL file stored in memory from go:embed.
 func readSQLFile(filename string) (string, error) {
 	data, err := sqlFiles.ReadFile(filename)
@@ -62,3 +123,68 @@ func executeSQLFile(tx *sql.Tx, filename, tableName string) error {
 	}
 	return nil
 }
+
+// initPostsTable initializes the table of archived channel posts.
+func initPostsTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, postSQL, "posts table")
+}
+
+// initPausesTable initializes the table of global and per-host download pauses.
+func initPausesTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, pauseSQL, "pauses table")
+}
+
+// initTagsTable initializes the table of channel tags, grouping channels for group-level operations.
+func initTagsTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, tagSQL, "channel tags table")
+}
+
+// initDefaultsTable initializes the single row defaults profile inherited by channels.
+func initDefaultsTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, defaultsSQL, "defaults table")
+}
+
+// initPresetsTable initializes the table of named settings presets applied when adding channels.
+func initPresetsTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, presetSQL, "presets table")
+}
+
+// initSearchIndex initializes the full-text index of video titles, descriptions, and metadata, indexing existing videos.
+func initSearchIndex(tx *sql.Tx) error {
+	return executeSQLFile(tx, searchSQL, "video search index")
+}
+
+// initUsersTable initializes the tables of web users and their login sessions.
+func initUsersTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, userSQL, "users tables")
+}
+
+// initReportsTable initializes the table of crawl pass summaries.
+func initReportsTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, reportSQL, "crawl reports table")
+}
+
+// initSMTPTable initializes the single row table of mail server settings.
+func initSMTPTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, smtpSQL, "SMTP settings table")
+}
+
+// initHooksTable initializes the table of external scripts run on events.
+func initHooksTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, hooksSQL, "event hooks table")
+}
+
+// initIgnoresTable initializes the table of ignore rules applied to every channel.
+func initIgnoresTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, ignoresSQL, "global ignores table")
+}
+
+// initCommandsTable initializes the table of commands queued for the running instance.
+func initCommandsTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, commandsSQL, "pending commands table")
+}
+
+// initStatsTable initializes the daily per-channel download statistics rollup.
+func initStatsTable(tx *sql.Tx) error {
+	return executeSQLFile(tx, statsSQL, "download stats table")
+}
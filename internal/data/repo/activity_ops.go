@@ -0,0 +1,318 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// RecordChannelEvent adds an entry to the channel's event history.
+func (cs *ChannelStore) RecordChannelEvent(channelID int64, kind consts.ActivityKind, detail string) error {
+	query := squirrel.
+		Insert(consts.DBEvents).
+		Columns(consts.QEventChanID, consts.QEventKind, consts.QEventDetail).
+		Values(channelID, kind, detail).
+		RunWith(cs.DB)
+
+	if _, err := query.Exec(); err != nil {
+		return fmt.Errorf("failed to record %s event for channel %d: %w", kind, channelID, err)
+	}
+	return nil
+}
+
+// RepeatChannelEvent adds an entry to the channel's event history, or if the newest entry is the same event,
+// counts the repeat on it instead, e.g. "no new videos (12 times since 2026-01-02 15:04)".
+//
+// The collapsed entry moves to the time of the latest repeat.
+func (cs *ChannelStore) RepeatChannelEvent(channelID int64, kind consts.ActivityKind, detail string) error {
+	var (
+		id         int64
+		lastKind   consts.ActivityKind
+		lastDetail sql.NullString
+		lastAt     time.Time
+	)
+	err := squirrel.
+		Select(consts.QEventID, consts.QEventKind, consts.QEventDetail, consts.QEventCreatedAt).
+		From(consts.DBEvents).
+		Where(squirrel.Eq{consts.QEventChanID: channelID}).
+		OrderBy(consts.QEventCreatedAt+" DESC", consts.QEventID+" DESC").
+		Limit(1).
+		RunWith(cs.DB).
+		QueryRow().
+		Scan(&id, &lastKind, &lastDetail, &lastAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return cs.RecordChannelEvent(channelID, kind, detail)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up newest event for channel %d: %w", channelID, err)
+	}
+
+	// Repeats so far, and when the first happened
+	times, since := 1, lastAt.Local().Format("2006-01-02 15:04")
+	switch rest, ok := strings.CutPrefix(lastDetail.String, detail); {
+	case lastKind != kind || !ok:
+		return cs.RecordChannelEvent(channelID, kind, detail)
+	case rest != "":
+		var n int
+		if _, err := fmt.Sscanf(rest, " (%d times since ", &n); err != nil {
+			return cs.RecordChannelEvent(channelID, kind, detail)
+		}
+		times = n
+		_, since, _ = strings.Cut(rest, " since ")
+		since = strings.TrimSuffix(since, ")")
+	}
+
+	if _, err := squirrel.
+		Update(consts.DBEvents).
+		Set(consts.QEventDetail, fmt.Sprintf("%s (%d times since %s)", detail, times+1, since)).
+		Set(consts.QEventCreatedAt, squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{consts.QEventID: id}).
+		RunWith(cs.DB).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to record repeated %s event for channel %d: %w", kind, channelID, err)
+	}
+	return nil
+}
+
+// PruneChannelEvents removes event history entries older than the given time for every channel.
+func (cs *ChannelStore) PruneChannelEvents(before time.Time) (int64, error) {
+	result, err := squirrel.
+		Delete(consts.DBEvents).
+		Where(squirrel.Lt{consts.QEventCreatedAt: before}).
+		RunWith(cs.DB).
+		Exec()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune channel events: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// FetchChannelActivity returns a merged, newest first feed of downloads, failures, skips, channel events, and archived posts.
+//
+// At most limit entries are returned, or all entries if limit is 0.
+func (cs *ChannelStore) FetchChannelActivity(channelID int64, limit int) ([]*models.ActivityEvent, error) {
+	var feed []*models.ActivityEvent
+
+	sources := []func(int64, int) ([]*models.ActivityEvent, error){
+		cs.downloadActivity,
+		cs.skipActivity,
+		cs.eventActivity,
+		cs.postActivity,
+	}
+	for _, src := range sources {
+		events, err := src(channelID, limit)
+		if err != nil {
+			return nil, err
+		}
+		feed = append(feed, events...)
+	}
+
+	sort.SliceStable(feed, func(i, j int) bool {
+		return feed[i].Time.After(feed[j].Time)
+	})
+
+	if limit > 0 && len(feed) > limit {
+		feed = feed[:limit]
+	}
+	return feed, nil
+}
+
+// downloadActivity returns finished, failed, and cancelled downloads for a channel.
+func (cs *ChannelStore) downloadActivity(channelID int64, limit int) ([]*models.ActivityEvent, error) {
+	query := squirrel.
+		Select(
+			consts.DBDownloads+"."+consts.QDLStatus,
+			consts.DBDownloads+"."+consts.QDLCancelReason,
+			consts.DBDownloads+"."+consts.QDLUpdatedAt,
+			consts.DBVideos+"."+consts.QVidID,
+			consts.DBVideos+"."+consts.QVidURL,
+			consts.DBVideos+"."+consts.QVidTitle,
+		).
+		From(consts.DBDownloads).
+		Join(consts.DBVideos + " ON " + consts.DBVideos + "." + consts.QVidID + " = " + consts.DBDownloads + "." + consts.QDLVidID).
+		Where(squirrel.Eq{
+			consts.DBVideos + "." + consts.QVidChanID:   channelID,
+			consts.DBDownloads + "." + consts.QDLStatus: []consts.DownloadStatus{consts.DLStatusCompleted, consts.DLStatusFailed, consts.DLStatusCancelled},
+		}).
+		OrderBy(consts.DBDownloads + "." + consts.QDLUpdatedAt + " DESC")
+
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	rows, err := query.RunWith(cs.DB).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query download activity: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.ActivityEvent
+	for rows.Next() {
+		var (
+			e            models.ActivityEvent
+			status       consts.DownloadStatus
+			cancelReason sql.NullString
+			updatedAt    sql.NullTime
+			title        sql.NullString
+		)
+		if err := rows.Scan(&status, &cancelReason, &updatedAt, &e.VideoID, &e.URL, &title); err != nil {
+			return nil, fmt.Errorf("failed to scan download activity: %w", err)
+		}
+		e.Time = updatedAt.Time
+		e.Detail = title.String
+
+		switch status {
+		case consts.DLStatusCompleted:
+			e.Kind = consts.ActivityDownload
+		case consts.DLStatusFailed:
+			e.Kind = consts.ActivityFailure
+		case consts.DLStatusCancelled:
+			e.Kind = consts.ActivityCancel
+			if cancelReason.String != "" {
+				e.Detail = strings.TrimSpace(e.Detail + " (reason: " + cancelReason.String + ")")
+			}
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// skipActivity returns recorded skipped video candidates for a channel.
+func (cs *ChannelStore) skipActivity(channelID int64, limit int) ([]*models.ActivityEvent, error) {
+	query := squirrel.
+		Select(consts.QSkipURL, consts.QSkipReason, consts.QSkipDetail, consts.QSkipCreatedAt).
+		From(consts.DBSkipped).
+		Where(squirrel.Eq{consts.QSkipChanID: channelID}).
+		OrderBy(consts.QSkipCreatedAt + " DESC")
+
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	rows, err := query.RunWith(cs.DB).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query skip activity: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.ActivityEvent
+	for rows.Next() {
+		var (
+			e      = models.ActivityEvent{Kind: consts.ActivitySkip}
+			reason string
+			detail sql.NullString
+		)
+		if err := rows.Scan(&e.URL, &reason, &detail, &e.Time); err != nil {
+			return nil, fmt.Errorf("failed to scan skip activity: %w", err)
+		}
+		e.Detail = reason
+		if detail.String != "" {
+			e.Detail += ": " + detail.String
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// eventActivity returns crawl runs, bot blocks, and settings edits for a channel.
+func (cs *ChannelStore) eventActivity(channelID int64, limit int) ([]*models.ActivityEvent, error) {
+	query := squirrel.
+		Select(consts.QEventKind, consts.QEventDetail, consts.QEventCreatedAt).
+		From(consts.DBEvents).
+		Where(squirrel.Eq{consts.QEventChanID: channelID}).
+		OrderBy(consts.QEventCreatedAt + " DESC")
+
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	rows, err := query.RunWith(cs.DB).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query channel events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.ActivityEvent
+	for rows.Next() {
+		var (
+			e      models.ActivityEvent
+			detail sql.NullString
+		)
+		if err := rows.Scan(&e.Kind, &detail, &e.Time); err != nil {
+			return nil, fmt.Errorf("failed to scan channel event: %w", err)
+		}
+		e.Detail = detail.String
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// postActivity returns archived posts for a channel, with the start of each post's text.
+func (cs *ChannelStore) postActivity(channelID int64, limit int) ([]*models.ActivityEvent, error) {
+	const (
+		excerptLen = 80
+	)
+
+	query := squirrel.
+		Select(consts.QPostURL, consts.QPostText, consts.QPostCreatedAt).
+		From(consts.DBPosts).
+		Where(squirrel.Eq{consts.QPostChanID: channelID}).
+		OrderBy(consts.QPostCreatedAt + " DESC")
+
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	rows, err := query.RunWith(cs.DB).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query post activity: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.ActivityEvent
+	for rows.Next() {
+		var (
+			e         = models.ActivityEvent{Kind: consts.ActivityPost}
+			url, text sql.NullString
+		)
+		if err := rows.Scan(&url, &text, &e.Time); err != nil {
+			return nil, fmt.Errorf("failed to scan post activity: %w", err)
+		}
+		e.URL = url.String
+		e.Detail = strings.Join(strings.Fields(text.String), " ")
+		if r := []rune(e.Detail); len(r) > excerptLen {
+			e.Detail = string(r[:excerptLen]) + "..."
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// changedJSONKeys returns the top-level keys which differ between two JSON objects.
+func changedJSONKeys(before, after []byte) []string {
+	var b, a map[string]json.RawMessage
+	if err := json.Unmarshal(before, &b); err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(after, &a); err != nil {
+		return nil
+	}
+
+	var changed []string
+	for k, v := range a {
+		if string(b[k]) != string(v) {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -10,7 +10,15 @@ type Store struct {
 	db            *sql.DB
 	videoStore    *VideoStore
 	channelStore  *ChannelStore
+	confirmStore  *ConfirmStore
 	downloadStore *DownloadStore
+	hostStore     *HostStore
+	postStore     *PostStore
+	retryStore    *RetryStore
+	skipStore     *SkipStore
+	statsStore    *StatsStore
+	storageStore  *StorageStore
+	userStore     *UserStore
 }
 
 // InitStores injects databases into the store methods.
@@ -19,10 +27,23 @@ func InitStores(db *sql.DB) *Store {
 		db:            db,
 		videoStore:    GetVideoStore(db),
 		channelStore:  GetChannelStore(db),
+		confirmStore:  GetConfirmStore(db),
 		downloadStore: GetDownloadStore(db),
+		hostStore:     GetHostStore(db),
+		postStore:     GetPostStore(db),
+		retryStore:    GetRetryStore(db),
+		skipStore:     GetSkipStore(db),
+		statsStore:    GetStatsStore(db),
+		storageStore:  GetStorageStore(db),
+		userStore:     GetUserStore(db),
 	}
 }
 
+// GetDB returns the database.
+func (s *Store) GetDB() *sql.DB {
+	return s.db
+}
+
 // ChannelStore with pointer receiver.
 func (s *Store) ChannelStore() interfaces.ChannelStore {
 	return s.channelStore
@@ -37,3 +58,43 @@ func (s *Store) VideoStore() interfaces.VideoStore {
 func (s *Store) DownloadStore() interfaces.DownloadStore {
 	return s.downloadStore
 }
+
+// HostStore with pointer receiver.
+func (s *Store) HostStore() interfaces.HostStore {
+	return s.hostStore
+}
+
+// PostStore with pointer receiver.
+func (s *Store) PostStore() interfaces.PostStore {
+	return s.postStore
+}
+
+// StorageStore with pointer receiver.
+func (s *Store) StorageStore() interfaces.StorageStore {
+	return s.storageStore
+}
+
+// SkipStore with pointer receiver.
+func (s *Store) SkipStore() interfaces.SkipStore {
+	return s.skipStore
+}
+
+// ConfirmStore with pointer receiver.
+func (s *Store) ConfirmStore() interfaces.ConfirmStore {
+	return s.confirmStore
+}
+
+// RetryStore with pointer receiver.
+func (s *Store) RetryStore() interfaces.RetryStore {
+	return s.retryStore
+}
+
+// StatsStore with pointer receiver.
+func (s *Store) StatsStore() interfaces.StatsStore {
+	return s.statsStore
+}
+
+// UserStore with pointer receiver.
+func (s *Store) UserStore() interfaces.UserStore {
+	return s.userStore
+}
@@ -0,0 +1,208 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/process"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// BulkVideoAction performs a status transition on several videos of a channel in a single transaction.
+//
+// Each video is identified by ID or URL. If any item fails, no changes are committed.
+// Files are only deleted once the transaction has been committed.
+func (vs VideoStore) BulkVideoAction(chanID int64, action consts.BulkAction, ids []int64, urls []string) ([]models.BulkResult, error) {
+	switch action {
+	case consts.BulkIgnore, consts.BulkUnignore, consts.BulkRequeue, consts.BulkDeleteFilesKeep, consts.BulkRedownload:
+	default:
+		return nil, fmt.Errorf("invalid bulk action %q", action)
+	}
+
+	tx, err := vs.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	var committed bool
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logging.E(0, "Error rolling back bulk %s for channel with ID %d: %v", action, chanID, err)
+			}
+		}
+	}()
+
+	results := make([]models.BulkResult, 0, len(ids)+len(urls))
+	var (
+		failed    bool
+		toDelete  []string
+		itemFiles []string
+	)
+
+	apply := func(r models.BulkResult, byID bool) models.BulkResult {
+		var (
+			videoPath, jsonPath sql.NullString
+			transcript          string
+			id                  int64
+		)
+
+		where := squirrel.And{squirrel.Eq{consts.QVidChanID: chanID}}
+		if byID {
+			where = append(where, squirrel.Eq{consts.QVidID: r.VideoID})
+		} else {
+			where = append(where, squirrel.Eq{consts.QVidURL: r.URL})
+		}
+
+		err := squirrel.
+			Select(consts.QVidID, consts.QVidURL, consts.QVidVideoPath, consts.QVidJSONPath, consts.QVidTranscript).
+			From(consts.DBVideos).
+			Where(where).
+			RunWith(tx).
+			QueryRow().
+			Scan(&id, &r.URL, &videoPath, &jsonPath, &transcript)
+
+		switch {
+		case errors.Is(err, sql.ErrNoRows) && action == consts.BulkIgnore && !byID:
+			// Unknown URLs may be ignored ahead of being crawled
+			res, err := squirrel.
+				Insert(consts.DBVideos).
+				Columns(consts.QVidChanID, consts.QVidURL, consts.QVidDownloaded).
+				Values(chanID, r.URL, true).
+				RunWith(tx).
+				Exec()
+			if err != nil {
+				r.Err = fmt.Errorf("failed to add video: %w", err)
+				return r
+			}
+			if id, err = res.LastInsertId(); err != nil {
+				r.Err = err
+				return r
+			}
+		case errors.Is(err, sql.ErrNoRows):
+			r.Err = errors.New("no such video in channel")
+			return r
+		case err != nil:
+			r.Err = err
+			return r
+		}
+		r.VideoID = id
+
+		itemFiles = itemFiles[:0]
+		switch action {
+		case consts.BulkIgnore:
+			r.Err = upsertDownloadStatus(tx, id, consts.DLStatusCompleted, 100.0)
+
+		case consts.BulkUnignore:
+			if videoPath.String != "" {
+				r.Err = errors.New("video has been downloaded, use requeue instead")
+				return r
+			}
+			r.Err = upsertDownloadStatus(tx, id, consts.DLStatusPending, 0.0)
+
+		case consts.BulkRequeue:
+			r.Err = upsertDownloadStatus(tx, id, consts.DLStatusPending, 0.0)
+
+		case consts.BulkDeleteFilesKeep, consts.BulkRedownload:
+			if _, err := squirrel.
+				Update(consts.DBVideos).
+				Set(consts.QVidVideoPath, "").
+				Set(consts.QVidJSONPath, "").
+				Set(consts.QVidTranscript, "").
+				Set(consts.QVidUpdatedAt, time.Now()).
+				Where(squirrel.Eq{consts.QVidID: id}).
+				RunWith(tx).
+				Exec(); err != nil {
+				r.Err = fmt.Errorf("failed to clear file paths: %w", err)
+				return r
+			}
+			for _, p := range []string{videoPath.String, jsonPath.String, transcript} {
+				if p != "" {
+					itemFiles = append(itemFiles, p)
+				}
+			}
+			itemFiles = append(itemFiles, process.SidecarPaths(videoPath.String)...)
+			if action == consts.BulkRedownload {
+				r.Err = upsertDownloadStatus(tx, id, consts.DLStatusPending, 0.0)
+			}
+		}
+		if r.Err == nil && action != consts.BulkIgnore && action != consts.BulkDeleteFilesKeep {
+			// Requeued videos start over rather than waiting on an old retry entry
+			r.Err = clearRetry(tx, chanID, r.URL)
+		}
+		if r.Err == nil {
+			toDelete = append(toDelete, itemFiles...)
+		}
+		return r
+	}
+
+	for _, id := range ids {
+		r := apply(models.BulkResult{VideoID: id}, true)
+		failed = failed || r.Err != nil
+		results = append(results, r)
+	}
+	for _, u := range urls {
+		r := apply(models.BulkResult{URL: u}, false)
+		failed = failed || r.Err != nil
+		results = append(results, r)
+	}
+
+	if failed {
+		return results, fmt.Errorf("bulk %s failed for one or more videos, no changes were made", action)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return results, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	if action == consts.BulkUnignore || action == consts.BulkRequeue || action == consts.BulkRedownload {
+		for _, r := range results {
+			process.UnmarkIgnored(chanID, r.URL)
+		}
+	}
+
+	for _, f := range toDelete {
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logging.E(0, "Failed to delete file %q: %v", f, err)
+		}
+	}
+	return results, nil
+}
+
+// upsertDownloadStatus sets a video's download status, clearing any cancellation details.
+func upsertDownloadStatus(tx *sql.Tx, videoID int64, status consts.DownloadStatus, pct float64) error {
+	const (
+		querySuffix = "ON CONFLICT (video_id) DO UPDATE SET status = EXCLUDED.status, percentage = EXCLUDED.percentage, " +
+			"cancel_reason = NULL, cancelled_at = NULL, updated_at = EXCLUDED.updated_at"
+	)
+
+	if _, err := squirrel.
+		Insert(consts.DBDownloads).
+		Columns(consts.QDLVidID, consts.QDLStatus, consts.QDLPct, consts.QDLUpdatedAt).
+		Values(videoID, status, pct, time.Now()).
+		Suffix(querySuffix).
+		RunWith(tx).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to set download status: %w", err)
+	}
+	return nil
+}
+
+// clearRetry removes any retry queue entry for a channel's video.
+func clearRetry(tx *sql.Tx, chanID int64, url string) error {
+	if _, err := squirrel.
+		Delete(consts.DBRetries).
+		Where(squirrel.Eq{consts.QRetryChanID: chanID, consts.QRetryURL: url}).
+		RunWith(tx).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to clear retry entry: %w", err)
+	}
+	return nil
+}
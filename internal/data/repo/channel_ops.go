@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"tubarr/internal/auth"
 	"tubarr/internal/domain/consts"
 	"tubarr/internal/interfaces"
 	"tubarr/internal/models"
@@ -56,6 +58,8 @@ func (cs *ChannelStore) GetID(key, val string) (int64, error) {
 }
 
 // GetAuth gets authentication details for a channel.
+//
+// Passwords stored as secret references, such as "env:VARNAME" or "file:/run/secrets/site", are resolved.
 func (cs *ChannelStore) GetAuth(channelID int64) (username, password, loginURL string, err error) {
 	query := squirrel.
 		Select(consts.QChanUsername, consts.QChanPassword, consts.QChanLoginURL).
@@ -67,6 +71,10 @@ func (cs *ChannelStore) GetAuth(channelID int64) (username, password, loginURL s
 		logging.I("No auth details in the database for channel with ID: %d", channelID)
 		return "", "", "", err
 	}
+
+	if password, err = auth.ResolveSecret(password); err != nil {
+		return "", "", "", fmt.Errorf("channel with ID %d: %w", channelID, err)
+	}
 	return username, password, loginURL, nil
 }
 
@@ -93,23 +101,128 @@ func (cs *ChannelStore) DeleteVideoURLs(channelID int64, urls []string) error {
 }
 
 // AddURLToIgnore adds a URL into the database to ignore in subsequent crawls.
-func (cs *ChannelStore) AddURLToIgnore(channelID int64, ignoreURL string) error {
+//
+// Returns false for caught if the video's download had already started or finished.
+func (cs *ChannelStore) AddURLToIgnore(channelID int64, ignoreURL string) (caught bool, err error) {
 
 	if !cs.channelExistsID(channelID) {
-		return fmt.Errorf("channel with ID %d does not exist", channelID)
+		return false, fmt.Errorf("channel with ID %d does not exist", channelID)
 	}
 
-	query := squirrel.
-		Insert(consts.DBVideos).
-		Columns(consts.QVidChanID, consts.QVidURL, consts.QVidDownloaded).
-		Values(channelID, ignoreURL, true).
-		RunWith(cs.DB)
+	tx, err := cs.DB.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	var committed bool
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logging.E(0, "Error rolling back ignore for URL %q: %v", ignoreURL, err)
+			}
+		}
+	}()
 
-	if _, err := query.Exec(); err != nil {
-		return err
+	var (
+		videoID   int64
+		videoPath sql.NullString
+		status    sql.NullString
+	)
+	err = squirrel.
+		Select(consts.DBVideos+"."+consts.QVidID, consts.DBVideos+"."+consts.QVidVideoPath, consts.DBDownloads+"."+consts.QDLStatus).
+		From(consts.DBVideos).
+		LeftJoin(consts.DBDownloads+" ON "+consts.DBDownloads+"."+consts.QDLVidID+" = "+consts.DBVideos+"."+consts.QVidID).
+		Where(squirrel.Eq{consts.DBVideos + "." + consts.QVidChanID: channelID, consts.DBVideos + "." + consts.QVidURL: ignoreURL}).
+		RunWith(tx).
+		QueryRow().
+		Scan(&videoID, &videoPath, &status)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		res, err := squirrel.
+			Insert(consts.DBVideos).
+			Columns(consts.QVidChanID, consts.QVidURL, consts.QVidDownloaded).
+			Values(channelID, ignoreURL, true).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return false, err
+		}
+		if videoID, err = res.LastInsertId(); err != nil {
+			return false, err
+		}
+		caught = true
+	case err != nil:
+		return false, fmt.Errorf("failed to look up video %q: %w", ignoreURL, err)
+	default:
+		switch consts.DownloadStatus(status.String) {
+		case consts.DLStatusDownloading:
+			caught = false
+		case consts.DLStatusCompleted:
+			caught = videoPath.String == ""
+		default:
+			caught = true
+		}
 	}
+
+	// Videos whose download already started are left as they are
+	if caught {
+		const (
+			querySuffix = "ON CONFLICT (video_id) DO UPDATE SET status = EXCLUDED.status, " +
+				"cancel_reason = EXCLUDED.cancel_reason, cancelled_at = EXCLUDED.cancelled_at, updated_at = EXCLUDED.updated_at"
+		)
+
+		now := time.Now()
+		if _, err := squirrel.
+			Insert(consts.DBDownloads).
+			Columns(consts.QDLVidID, consts.QDLStatus, consts.QDLCancelReason, consts.QDLCancelledAt, consts.QDLUpdatedAt).
+			Values(videoID, consts.DLStatusCancelled, consts.CancelIgnored, now, now).
+			Suffix(querySuffix).
+			RunWith(tx).
+			Exec(); err != nil {
+			return false, fmt.Errorf("failed to mark video %q as ignored: %w", ignoreURL, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	process.MarkIgnored(channelID, ignoreURL)
 	logging.S(0, "Added URL %q to ignore list for channel with ID '%d'", ignoreURL, channelID)
-	return nil
+	return caught, nil
+}
+
+// LoadIgnoredURLs loads the URLs the user has ignored for a channel.
+func (cs *ChannelStore) LoadIgnoredURLs(channelID int64) (urls []string, err error) {
+	rows, err := squirrel.
+		Select(consts.DBVideos + "." + consts.QVidURL).
+		From(consts.DBVideos).
+		Join(consts.DBDownloads + " ON " + consts.DBDownloads + "." + consts.QDLVidID + " = " + consts.DBVideos + "." + consts.QVidID).
+		Where(squirrel.Eq{
+			consts.DBVideos + "." + consts.QVidChanID:         channelID,
+			consts.DBDownloads + "." + consts.QDLStatus:       consts.DLStatusCancelled,
+			consts.DBDownloads + "." + consts.QDLCancelReason: consts.CancelIgnored,
+		}).
+		RunWith(cs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ignored URLs: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logging.E(0, "Failed to close rows for ignored URLs in channel with ID %d", channelID)
+		}
+	}()
+
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		urls = append(urls, u)
+	}
+	return urls, rows.Err()
 }
 
 // GetNotifyURLs returns all notification URLs for a given channel.
@@ -149,6 +262,31 @@ func (cs *ChannelStore) GetNotifyURLs(id int64) ([]string, error) {
 	return urls, nil
 }
 
+// GetNotifications returns the named notification URLs for a given channel.
+func (cs *ChannelStore) GetNotifications(id int64) ([]*models.Notification, error) {
+	rows, err := squirrel.
+		Select(consts.QNotifyName, consts.QNotifyURL).
+		From(consts.DBNotifications).
+		Where(squirrel.Eq{consts.QNotifyChanID: id}).
+		OrderBy(consts.QNotifyName).
+		RunWith(cs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.Name, &n.URL); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, &n)
+	}
+	return notifications, rows.Err()
+}
+
 // DeleteNotifyURLs deletes notify URLs from the channel.
 func (cs *ChannelStore) DeleteNotifyURLs(channelID int64, urls, names []string) error {
 
@@ -379,6 +517,7 @@ func (cs *ChannelStore) CrawlChannelIgnore(key, val string, s interfaces.Store,
 		}
 	}
 
+	cs.loadDefaults().Inherit(&c)
 	logging.D(1, "Retrieved channel with Metarr args: %+v", c.MetarrArgs)
 
 	if err := process.CrawlIgnoreNew(s, &c, ctx); err != nil {
@@ -446,10 +585,165 @@ func (cs *ChannelStore) CrawlChannel(key, val string, s interfaces.Store, ctx co
 		}
 	}
 
+	cs.loadDefaults().Inherit(&c)
 	logging.D(1, "Retrieved channel with Metarr args: %+v", c.MetarrArgs)
 	return process.ChannelCrawl(s, &c, ctx)
 }
 
+// CheckLiveURL checks the channel's live URL now, capturing its stream if it is live.
+func (cs *ChannelStore) CheckLiveURL(key, val string, s interfaces.Store, ctx context.Context) error {
+	id, err := cs.GetID(key, val)
+	if err != nil {
+		return err
+	}
+
+	c, err, hasRows := cs.FetchChannel(id)
+	if !hasRows {
+		return fmt.Errorf("no channel found with %s %q", key, val)
+	}
+	if err != nil {
+		return err
+	}
+	return process.CheckLive(s, c, ctx)
+}
+
+// VerifyChannelComplete compares the channel's remote videos against all recorded videos.
+//
+// Remote videos never seen by Tubarr are listed, and downloaded if enqueue is set.
+func (cs *ChannelStore) VerifyChannelComplete(key, val string, enqueue bool, s interfaces.Store, ctx context.Context) error {
+	id, err := cs.GetID(key, val)
+	if err != nil {
+		return err
+	}
+
+	c, err, hasRows := cs.FetchChannel(id)
+	if !hasRows {
+		return fmt.Errorf("no channel found with %s %q", key, val)
+	}
+	if err != nil {
+		return err
+	}
+	return process.VerifyComplete(s, c, enqueue, ctx)
+}
+
+// ImportExistingVideos marks videos of the channel already in a directory as downloaded.
+func (cs *ChannelStore) ImportExistingVideos(key, val, dir string, offline, dryRun bool, s interfaces.Store, ctx context.Context) error {
+	id, err := cs.GetID(key, val)
+	if err != nil {
+		return err
+	}
+
+	c, err, hasRows := cs.FetchChannel(id)
+	if !hasRows {
+		return fmt.Errorf("no channel found with %s %q", key, val)
+	}
+	if err != nil {
+		return err
+	}
+	return process.ImportExisting(s, c, dir, offline, dryRun, ctx)
+}
+
+// ImportDownloadArchive marks the videos in a yt-dlp download archive file as finished in the channel.
+func (cs *ChannelStore) ImportDownloadArchive(key, val, path string, offline bool, s interfaces.Store, ctx context.Context) error {
+	id, err := cs.GetID(key, val)
+	if err != nil {
+		return err
+	}
+
+	c, err, hasRows := cs.FetchChannel(id)
+	if !hasRows {
+		return fmt.Errorf("no channel found with %s %q", key, val)
+	}
+	if err != nil {
+		return err
+	}
+	return process.ImportArchive(s, c, path, offline, ctx)
+}
+
+// ExportDownloadArchive writes the channel's finished and ignored videos to a yt-dlp download archive file.
+func (cs *ChannelStore) ExportDownloadArchive(key, val, path string, appendTo bool, s interfaces.Store) error {
+	id, err := cs.GetID(key, val)
+	if err != nil {
+		return err
+	}
+
+	c, err, hasRows := cs.FetchChannel(id)
+	if !hasRows {
+		return fmt.Errorf("no channel found with %s %q", key, val)
+	}
+	if err != nil {
+		return err
+	}
+	return process.ExportArchive(s, c, path, appendTo)
+}
+
+// RedownloadVideos requeues videos of the channel and downloads them again, deleting their files first if deleteFiles is set.
+func (cs *ChannelStore) RedownloadVideos(key, val string, urls []string, deleteFiles bool, s interfaces.Store, ctx context.Context) error {
+	id, err := cs.GetID(key, val)
+	if err != nil {
+		return err
+	}
+
+	c, err, hasRows := cs.FetchChannel(id)
+	if !hasRows {
+		return fmt.Errorf("no channel found with %s %q", key, val)
+	}
+	if err != nil {
+		return err
+	}
+	return process.Redownload(s, c, urls, deleteFiles, ctx)
+}
+
+// ReprocessChannelMetarr runs the channel's downloaded videos through Metarr again, all of them unless urls is set.
+func (cs *ChannelStore) ReprocessChannelMetarr(key, val string, urls []string, s interfaces.Store, ctx context.Context) error {
+	id, err := cs.GetID(key, val)
+	if err != nil {
+		return err
+	}
+
+	c, err, hasRows := cs.FetchChannel(id)
+	if !hasRows {
+		return fmt.Errorf("no channel found with %s %q", key, val)
+	}
+	if err != nil {
+		return err
+	}
+	return process.ReprocessMetarr(s, c, urls, ctx)
+}
+
+// GetStoredSettings returns a channel's own settings and Metarr arguments, without inheriting the defaults.
+func (cs *ChannelStore) GetStoredSettings(channelID int64) (*models.ChannelSettings, *models.MetarrArgs, error) {
+	var settingsJSON, metarrJSON []byte
+	err := squirrel.
+		Select(consts.QChanSettings, consts.QChanMetarr).
+		From(consts.DBChannels).
+		Where(squirrel.Eq{consts.QChanID: channelID}).
+		RunWith(cs.DB).
+		QueryRow().
+		Scan(&settingsJSON, &metarrJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("channel with ID %d not found", channelID)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to get channel settings: %w", err)
+	}
+
+	var (
+		settings models.ChannelSettings
+		metarr   models.MetarrArgs
+	)
+	if len(settingsJSON) > 0 {
+		if err := json.Unmarshal(settingsJSON, &settings); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal settings: %w", err)
+		}
+	}
+	if len(metarrJSON) > 0 {
+		if err := json.Unmarshal(metarrJSON, &metarr); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal metarr settings: %w", err)
+		}
+	}
+	return &settings, &metarr, nil
+}
+
 // FetchChannel returns a single channel from the database.
 func (cs *ChannelStore) FetchChannel(id int64) (channel *models.Channel, err error, hasRows bool) {
 	var (
@@ -510,6 +804,7 @@ func (cs *ChannelStore) FetchChannel(id int64) (channel *models.Channel, err err
 			return nil, fmt.Errorf("failed to unmarshal metarr settings: %w", err), true
 		}
 	}
+	cs.loadDefaults().Inherit(c)
 	return c, nil, true
 }
 
@@ -547,6 +842,7 @@ func (cs *ChannelStore) FetchAllChannels() (channels []*models.Channel, err erro
 		}
 	}()
 
+	defaults := cs.loadDefaults()
 	for rows.Next() {
 		var c models.Channel
 		var settingsJSON, metarrJSON []byte
@@ -582,6 +878,7 @@ func (cs *ChannelStore) FetchAllChannels() (channels []*models.Channel, err erro
 				return nil, fmt.Errorf("failed to unmarshal metarr settings: %w", err), true
 			}
 		}
+		defaults.Inherit(&c)
 		channels = append(channels, &c)
 	}
 
@@ -595,13 +892,14 @@ func (cs *ChannelStore) FetchAllChannels() (channels []*models.Channel, err erro
 // UpdateChannelMetarrArgsJSON updates args for Metarr output.
 func (cs ChannelStore) UpdateChannelMetarrArgsJSON(key, val string, updateFn func(*models.MetarrArgs) error) (int64, error) {
 	var metarrArgs json.RawMessage
+	var chanID int64
 	query := squirrel.
-		Select(consts.QChanMetarr).
+		Select(consts.QChanID, consts.QChanMetarr).
 		From(consts.DBChannels).
 		Where(squirrel.Eq{key: val}).
 		RunWith(cs.DB)
 
-	err := query.QueryRow().Scan(&metarrArgs)
+	err := query.QueryRow().Scan(&chanID, &metarrArgs)
 	if errors.Is(err, sql.ErrNoRows) {
 		return 0, fmt.Errorf("no channel found with key %q and value '%v'", key, val)
 	} else if err != nil {
@@ -640,19 +938,26 @@ func (cs ChannelStore) UpdateChannelMetarrArgsJSON(key, val string, updateFn fun
 		return 0, fmt.Errorf("failed to update channel settings in database: %w", err)
 	}
 
+	if changed := changedJSONKeys(metarrArgs, updatedArgs); len(changed) > 0 {
+		if err := cs.RecordChannelEvent(chanID, consts.ActivitySettings, "Metarr args: "+strings.Join(changed, ", ")); err != nil {
+			logging.E(0, "Failed to record settings change: %v", err)
+		}
+	}
+
 	return rtn.RowsAffected()
 }
 
 // UpdateChannelSettingsJSON updates specific settings in the channel's settings JSON.
 func (cs ChannelStore) UpdateChannelSettingsJSON(key, val string, updateFn func(*models.ChannelSettings) error) (int64, error) {
 	var settingsJSON json.RawMessage
+	var chanID int64
 	query := squirrel.
-		Select(consts.QChanSettings).
+		Select(consts.QChanID, consts.QChanSettings).
 		From(consts.DBChannels).
 		Where(squirrel.Eq{key: val}).
 		RunWith(cs.DB)
 
-	err := query.QueryRow().Scan(&settingsJSON)
+	err := query.QueryRow().Scan(&chanID, &settingsJSON)
 	if errors.Is(err, sql.ErrNoRows) {
 		return 0, fmt.Errorf("no channel found with key %q and value '%v'", key, val)
 	} else if err != nil {
@@ -691,6 +996,12 @@ func (cs ChannelStore) UpdateChannelSettingsJSON(key, val string, updateFn func(
 		return 0, fmt.Errorf("failed to update channel settings in database: %w", err)
 	}
 
+	if changed := changedJSONKeys(settingsJSON, updatedSettings); len(changed) > 0 {
+		if err := cs.RecordChannelEvent(chanID, consts.ActivitySettings, "Settings: "+strings.Join(changed, ", ")); err != nil {
+			logging.E(0, "Failed to record settings change: %v", err)
+		}
+	}
+
 	return rtn.RowsAffected()
 }
 
@@ -764,19 +1075,27 @@ func (cs ChannelStore) LoadGrabbedURLs(c *models.Channel) (urls []string, err er
 	}
 
 	const (
-		join     = "downloads ON downloads.video_id = videos.id"
-		vidURL   = "videos.url"
-		vidCID   = "videos.channel_id"
-		dlStatus = "downloads.status"
+		join         = "downloads ON downloads.video_id = videos.id"
+		vidURL       = "videos.url"
+		vidCID       = "videos.channel_id"
+		dlStatus     = "downloads.status"
+		cancelReason = "downloads.cancel_reason"
 	)
 
+	// User-cancelled and ignored videos are treated as grabbed, so they aren't retried until requeued
 	query := squirrel.
 		Select(vidURL).
 		From(consts.DBVideos).
 		Join(join).
 		Where(squirrel.And{
 			squirrel.Eq{vidCID: c.ID},
-			squirrel.Eq{dlStatus: consts.DLStatusCompleted},
+			squirrel.Or{
+				squirrel.Eq{dlStatus: consts.DLStatusCompleted},
+				squirrel.And{
+					squirrel.Eq{dlStatus: consts.DLStatusCancelled},
+					squirrel.Eq{cancelReason: []consts.CancelReason{consts.CancelUser, consts.CancelIgnored}},
+				},
+			},
 		}).
 		RunWith(cs.DB)
 
@@ -805,10 +1124,78 @@ func (cs ChannelStore) LoadGrabbedURLs(c *models.Channel) (urls []string, err er
 		return nil, fmt.Errorf("row iteration error: %w", err)
 	}
 
+	// Videos waiting on (or out of) retries are left to the retry loop rather than re-queued by the crawl
+	retryURLs, err := cs.loadRetryURLs(c.ID)
+	if err != nil {
+		return nil, err
+	}
+	urls = append(urls, retryURLs...)
+
 	logging.I("Found %d previously downloaded videos for channel ID %d", len(urls), c.ID)
 	return urls, nil
 }
 
+// loadRetryURLs returns the URLs with a queued or exhausted retry entry for the channel.
+func (cs ChannelStore) loadRetryURLs(chanID int64) (urls []string, err error) {
+	rows, err := squirrel.
+		Select(consts.QRetryURL).
+		From(consts.DBRetries).
+		Where(squirrel.Eq{consts.QRetryChanID: chanID}).
+		RunWith(cs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query retry queue: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan retry URL: %w", err)
+		}
+		urls = append(urls, url)
+	}
+	return urls, rows.Err()
+}
+
+// LoadAllVideoURLs loads the URLs of all videos recorded for a channel, regardless of status.
+func (cs ChannelStore) LoadAllVideoURLs(c *models.Channel) (urls []string, err error) {
+	if c.ID == 0 {
+		return nil, errors.New("model entered has no ID")
+	}
+
+	query := squirrel.
+		Select(consts.QVidURL).
+		From(consts.DBVideos).
+		Where(squirrel.Eq{consts.QVidChanID: c.ID}).
+		RunWith(cs.DB)
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logging.E(0, "Failed to close rows for channel %v: %v", c.Name, err)
+		}
+	}()
+
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		urls = append(urls, url)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	logging.D(1, "Found %d recorded videos for channel ID %d", len(urls), c.ID)
+	return urls, nil
+}
+
 // Private /////////////////////////////////////////////////////////////////////
 
 // channelExists returns true if the channel exists in the database.
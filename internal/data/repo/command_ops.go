@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// QueueCommand queues a channel command for the running scheduler to run, optionally limited to some video URLs.
+func (cs *ChannelStore) QueueCommand(key, val string, action consts.CommandAction, urls []string) error {
+	id, err := cs.GetID(key, val)
+	if err != nil {
+		return err
+	}
+	if urls == nil {
+		urls = []string{}
+	}
+	urlsJSON, err := json.Marshal(urls)
+	if err != nil {
+		return err
+	}
+
+	if _, err := squirrel.
+		Insert(consts.DBPendingCmds).
+		Columns(consts.QPCmdChanID, consts.QPCmdAction, consts.QPCmdURLs, consts.QPCmdCreatedAt).
+		Values(id, action, string(urlsJSON), time.Now()).
+		RunWith(cs.DB).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to queue %s of channel with ID %d: %w", action, id, err)
+	}
+	logging.S(0, "Queued %s of channel with ID %d for the running Tubarr scheduler", action, id)
+	return nil
+}
+
+// TakePendingCommands returns and removes the queued commands, oldest first.
+func (cs *ChannelStore) TakePendingCommands() ([]*models.PendingCommand, error) {
+	tx, err := cs.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := squirrel.
+		Select(consts.QPCmdID, consts.QPCmdChanID, consts.QPCmdAction, consts.QPCmdURLs, consts.QPCmdCreatedAt).
+		From(consts.DBPendingCmds).
+		OrderBy(consts.QPCmdID).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending commands: %w", err)
+	}
+
+	var (
+		cmds []*models.PendingCommand
+		ids  []int64
+	)
+	for rows.Next() {
+		var (
+			c    models.PendingCommand
+			urls string
+		)
+		if err := rows.Scan(&c.ID, &c.ChannelID, &c.Action, &urls, &c.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan pending command: %w", err)
+		}
+		ids = append(ids, c.ID)
+		if err := json.Unmarshal([]byte(urls), &c.URLs); err != nil {
+			logging.E(0, "Dropping pending command with ID %d, invalid URLs %q: %v", c.ID, urls, err)
+			continue
+		}
+		cmds = append(cmds, &c)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if _, err := squirrel.
+		Delete(consts.DBPendingCmds).
+		Where(squirrel.Eq{consts.QPCmdID: ids}).
+		RunWith(tx).
+		Exec(); err != nil {
+		return nil, fmt.Errorf("failed to remove pending commands: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit pending commands: %w", err)
+	}
+	return cmds, nil
+}
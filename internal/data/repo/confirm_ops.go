@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/errconsts"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type ConfirmStore struct {
+	DB *sql.DB
+}
+
+// GetConfirmStore returns a confirmation store instance with injected database.
+func GetConfirmStore(db *sql.DB) *ConfirmStore {
+	return &ConfirmStore{
+		DB: db,
+	}
+}
+
+// GetDB returns the database.
+func (fs *ConfirmStore) GetDB() *sql.DB {
+	return fs.DB
+}
+
+// IssueConfirmation stores a short-lived token which must be passed back to run the operation.
+func (fs *ConfirmStore) IssueConfirmation(operation, summary string, ttl time.Duration) (token string, err error) {
+	fs.pruneExpired()
+
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	token = hex.EncodeToString(b)
+
+	if _, err := squirrel.
+		Insert(consts.DBConfirm).
+		Columns(consts.QConfirmToken, consts.QConfirmOperation, consts.QConfirmSummary, consts.QConfirmExpiresAt).
+		Values(token, operation, summary, time.Now().Add(ttl)).
+		RunWith(fs.DB).
+		Exec(); err != nil {
+		return "", fmt.Errorf("failed to store confirmation token: %w", err)
+	}
+	return token, nil
+}
+
+// ConsumeConfirmation checks the token was issued for this exact operation and has not expired.
+//
+// Tokens are single use, and are removed whether or not they match.
+func (fs *ConfirmStore) ConsumeConfirmation(token, operation string) (summary string, err error) {
+	var (
+		storedOp  string
+		expiresAt time.Time
+	)
+
+	err = squirrel.
+		Select(consts.QConfirmOperation, consts.QConfirmSummary, consts.QConfirmExpiresAt).
+		From(consts.DBConfirm).
+		Where(squirrel.Eq{consts.QConfirmToken: token}).
+		RunWith(fs.DB).
+		QueryRow().
+		Scan(&storedOp, &summary, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("unknown confirmation token %q, "+errconsts.ConfirmRenewCLI, token)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up confirmation token: %w", err)
+	}
+
+	if _, err := squirrel.
+		Delete(consts.DBConfirm).
+		Where(squirrel.Eq{consts.QConfirmToken: token}).
+		RunWith(fs.DB).
+		Exec(); err != nil {
+		return "", fmt.Errorf("failed to consume confirmation token: %w", err)
+	}
+
+	switch {
+	case time.Now().After(expiresAt):
+		return "", fmt.Errorf("confirmation token %q expired, "+errconsts.ConfirmRenewCLI, token)
+	case storedOp != operation:
+		return "", fmt.Errorf("confirmation token %q was issued for a different operation (%s)", token, summary)
+	}
+	return summary, nil
+}
+
+// pruneExpired removes expired confirmation tokens.
+func (fs *ConfirmStore) pruneExpired() {
+	if _, err := squirrel.
+		Delete(consts.DBConfirm).
+		Where(squirrel.Lt{consts.QConfirmExpiresAt: time.Now()}).
+		RunWith(fs.DB).
+		Exec(); err != nil {
+		logging.E(0, "Failed to prune expired confirmation tokens: %v", err)
+	}
+}
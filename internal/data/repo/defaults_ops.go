@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// GetDefaults returns the defaults profile inherited by channels.
+func (cs *ChannelStore) GetDefaults() (*models.Defaults, error) {
+	var (
+		d                        models.Defaults
+		settingsJSON, metarrJSON []byte
+		updatedAt                sql.NullTime
+	)
+	err := squirrel.
+		Select(consts.QDefSettings, consts.QDefMetarr, consts.QDefUpdatedAt).
+		From(consts.DBDefaults).
+		Where(squirrel.Eq{consts.QDefID: 1}).
+		RunWith(cs.DB).
+		QueryRow().
+		Scan(&settingsJSON, &metarrJSON, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &d, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get defaults: %w", err)
+	}
+
+	if len(settingsJSON) > 0 {
+		if err := json.Unmarshal(settingsJSON, &d.Settings); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal default settings: %w", err)
+		}
+	}
+	if len(metarrJSON) > 0 {
+		if err := json.Unmarshal(metarrJSON, &d.MetarrArgs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal default metarr settings: %w", err)
+		}
+	}
+	d.UpdatedAt = updatedAt.Time
+	return &d, nil
+}
+
+// UpdateDefaultSettingsJSON applies the update function to the default settings.
+func (cs *ChannelStore) UpdateDefaultSettingsJSON(updateFn func(*models.ChannelSettings) error) error {
+	d, err := cs.GetDefaults()
+	if err != nil {
+		return err
+	}
+	if err := updateFn(&d.Settings); err != nil {
+		return fmt.Errorf("failed to update default settings: %w", err)
+	}
+	return cs.saveDefaults(consts.QDefSettings, d.Settings)
+}
+
+// UpdateDefaultMetarrArgsJSON applies the update function to the default Metarr arguments.
+func (cs *ChannelStore) UpdateDefaultMetarrArgsJSON(updateFn func(*models.MetarrArgs) error) error {
+	d, err := cs.GetDefaults()
+	if err != nil {
+		return err
+	}
+	if err := updateFn(&d.MetarrArgs); err != nil {
+		return fmt.Errorf("failed to update default metarr settings: %w", err)
+	}
+	return cs.saveDefaults(consts.QDefMetarr, d.MetarrArgs)
+}
+
+// ResetDefaults clears the defaults profile.
+func (cs *ChannelStore) ResetDefaults() error {
+	_, err := squirrel.
+		Update(consts.DBDefaults).
+		Set(consts.QDefSettings, "{}").
+		Set(consts.QDefMetarr, "{}").
+		Set(consts.QDefUpdatedAt, time.Now()).
+		Where(squirrel.Eq{consts.QDefID: 1}).
+		RunWith(cs.DB).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to reset defaults: %w", err)
+	}
+	return nil
+}
+
+// saveDefaults writes one column of the defaults profile as JSON.
+func (cs *ChannelStore) saveDefaults(col string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal defaults: %w", err)
+	}
+	logging.S(0, "Updated defaults: %s", string(b))
+
+	_, err = squirrel.
+		Insert(consts.DBDefaults).
+		Columns(consts.QDefID, col, consts.QDefUpdatedAt).
+		Values(1, b, time.Now()).
+		Suffix("ON CONFLICT (id) DO UPDATE SET " + col + " = excluded." + col + ", updated_at = excluded.updated_at").
+		RunWith(cs.DB).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to save defaults: %w", err)
+	}
+	return nil
+}
+
+// loadDefaults returns the defaults profile for channels to inherit.
+//
+// Failures are logged rather than returned, so channels still load (with only their own settings)
+// from databases not yet migrated to have a defaults profile.
+func (cs *ChannelStore) loadDefaults() *models.Defaults {
+	d, err := cs.GetDefaults()
+	if err != nil {
+		logging.E(0, "Channels will not inherit default settings: %v", err)
+		return &models.Defaults{}
+	}
+	return d
+}
@@ -97,6 +97,13 @@ func (ds *DownloadStore) SetDownloadStatus(v *models.Video) error {
 		Where(squirrel.Eq{consts.QVidID: v.ID}).
 		RunWith(tx)
 
+	switch v.DownloadStatus.Status {
+	case consts.DLStatusFailed:
+		query = query.Set(consts.QDLFailReason, v.DownloadStatus.FailReason)
+	case consts.DLStatusCompleted:
+		query = query.Set(consts.QDLFailReason, "")
+	}
+
 	if _, err := query.Exec(); err != nil {
 		return fmt.Errorf("failed to update status for video %d: %w", v.ID, err)
 	}
@@ -133,8 +140,37 @@ func (ds *DownloadStore) UpdateDownloadStatuses(ctx context.Context, updates []m
 		query = query.
 			Set(consts.QDLStatus, update.Status).
 			Set(consts.QDLPct, update.Percent).
+			Set(consts.QDLUpdatedAt, time.Now()).
 			Where(squirrel.Eq{consts.QDLVidID: update.VideoID}).
 			RunWith(tx)
+
+		if update.Status == consts.DLStatusCancelled {
+			query = query.
+				Set(consts.QDLCancelReason, update.CancelReason).
+				Set(consts.QDLCancelledAt, time.Now())
+		}
+
+		// Kept until the video downloads or is requeued, so failures can be told apart later
+		switch update.Status {
+		case consts.DLStatusFailed:
+			query = query.Set(consts.QDLFailReason, update.FailReason)
+		case consts.DLStatusCompleted:
+			query = query.Set(consts.QDLFailReason, "")
+		}
+
+		// Partial files are gone once a download completes
+		switch {
+		case update.Status == consts.DLStatusCompleted:
+			query = query.
+				Set(consts.QDLPartialPath, "").
+				Set(consts.QDLFragsDone, 0).
+				Set(consts.QDLFragsTotal, 0)
+		case update.Partial.Path != "":
+			query = query.
+				Set(consts.QDLPartialPath, update.Partial.Path).
+				Set(consts.QDLFragsDone, update.Partial.FragsDone).
+				Set(consts.QDLFragsTotal, update.Partial.FragsTotal)
+		}
 	}
 
 	if _, err := query.ExecContext(ctx); err != nil {
@@ -151,31 +187,151 @@ func (ds *DownloadStore) UpdateDownloadStatuses(ctx context.Context, updates []m
 
 // GetDownloadStatus retrieves the download status of a video.
 func (ds *DownloadStore) GetDownloadStatus(v *models.Video) error {
+	var (
+		cancelReason sql.NullString
+		cancelledAt  sql.NullTime
+	)
 
-	query := squirrel.Select(consts.QDLVidID, consts.QDLStatus, consts.QDLPct).
+	query := squirrel.Select(consts.QDLStatus, consts.QDLPct, consts.QDLCancelReason, consts.QDLCancelledAt, consts.QDLFailReason).
 		From(consts.DBDownloads).
 		Where(squirrel.Eq{consts.QDLVidID: v.ID}).
 		RunWith(ds.DB)
 
-	normalizeDownloadStatus(&v.DownloadStatus.Pct, &v.DownloadStatus.Status, v.ID)
-	if err := query.QueryRow().Scan(&v); err != nil {
-		return fmt.Errorf("failed to query download statuses: %w", err)
+	if err := query.QueryRow().Scan(&v.DownloadStatus.Status, &v.DownloadStatus.Pct, &cancelReason, &cancelledAt, &v.DownloadStatus.FailReason); err != nil {
+		return fmt.Errorf("failed to query download status for video with ID %d: %w", v.ID, err)
 	}
 
+	v.DownloadStatus.CancelReason = consts.CancelReason(cancelReason.String)
+	v.DownloadStatus.CancelledAt = cancelledAt.Time
+	return nil
+}
+
+// CancelDownload marks a video's download as cancelled with the given reason.
+//
+// Videos cancelled by the user are not grabbed again in crawls until requeued.
+func (ds *DownloadStore) CancelDownload(videoID int64, reason consts.CancelReason) error {
+	const (
+		querySuffix = "ON CONFLICT (video_id) DO UPDATE SET status = EXCLUDED.status, " +
+			"cancel_reason = EXCLUDED.cancel_reason, cancelled_at = EXCLUDED.cancelled_at, updated_at = EXCLUDED.updated_at"
+	)
+
+	now := time.Now()
+	query := squirrel.
+		Insert(consts.DBDownloads).
+		Columns(consts.QDLVidID, consts.QDLStatus, consts.QDLCancelReason, consts.QDLCancelledAt, consts.QDLUpdatedAt).
+		Values(videoID, consts.DLStatusCancelled, reason, now, now).
+		Suffix(querySuffix).
+		RunWith(ds.DB)
+
+	if _, err := query.Exec(); err != nil {
+		return fmt.Errorf("failed to cancel download for video %d: %w", videoID, err)
+	}
+	return nil
+}
+
+// RequeueDownload resets a video's download to pending and clears any cancellation or failure reason, or retry entry.
+func (ds *DownloadStore) RequeueDownload(videoID int64) error {
+	const (
+		querySuffix = "ON CONFLICT (video_id) DO UPDATE SET status = EXCLUDED.status, percentage = 0, " +
+			"cancel_reason = NULL, cancelled_at = NULL, fail_reason = '', updated_at = EXCLUDED.updated_at"
+	)
+
+	query := squirrel.
+		Insert(consts.DBDownloads).
+		Columns(consts.QDLVidID, consts.QDLStatus, consts.QDLUpdatedAt).
+		Values(videoID, consts.DLStatusPending, time.Now()).
+		Suffix(querySuffix).
+		RunWith(ds.DB)
+
+	if _, err := query.Exec(); err != nil {
+		return fmt.Errorf("failed to requeue download for video %d: %w", videoID, err)
+	}
+
+	// Requeued videos start over rather than waiting on an old retry entry
+	if _, err := squirrel.
+		Delete(consts.DBRetries).
+		Where(squirrel.Expr(
+			"("+consts.QRetryChanID+", "+consts.QRetryURL+") IN (SELECT "+consts.QVidChanID+", "+consts.QVidURL+" FROM "+consts.DBVideos+" WHERE "+consts.QVidID+" = ?)",
+			videoID,
+		)).
+		RunWith(ds.DB).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to clear retry entry for video %d: %w", videoID, err)
+	}
+	return nil
+}
+
+// FetchInterruptedDownloads returns unfinished downloads which have written partial files.
+func (ds *DownloadStore) FetchInterruptedDownloads() ([]*models.InterruptedDownload, error) {
+	rows, err := squirrel.
+		Select(
+			consts.DBDownloads+"."+consts.QDLVidID,
+			consts.DBVideos+"."+consts.QVidChanID,
+			consts.DBVideos+"."+consts.QVidURL,
+			consts.DBDownloads+"."+consts.QDLStatus,
+			consts.DBDownloads+"."+consts.QDLCancelReason,
+			consts.DBDownloads+"."+consts.QDLPartialPath,
+			consts.DBDownloads+"."+consts.QDLFragsDone,
+			consts.DBDownloads+"."+consts.QDLFragsTotal,
+		).
+		From(consts.DBDownloads).
+		Join(consts.DBVideos + " ON " + consts.DBVideos + "." + consts.QVidID + " = " + consts.DBDownloads + "." + consts.QDLVidID).
+		Where(squirrel.And{
+			squirrel.NotEq{consts.DBDownloads + "." + consts.QDLPartialPath: ""},
+			squirrel.NotEq{consts.DBDownloads + "." + consts.QDLStatus: consts.DLStatusCompleted},
+		}).
+		OrderBy(consts.DBDownloads + "." + consts.QDLUpdatedAt).
+		RunWith(ds.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query interrupted downloads: %w", err)
+	}
+	defer rows.Close()
+
+	var dls []*models.InterruptedDownload
+	for rows.Next() {
+		var (
+			d            models.InterruptedDownload
+			cancelReason sql.NullString
+		)
+		if err := rows.Scan(&d.VideoID, &d.ChannelID, &d.URL, &d.Status, &cancelReason,
+			&d.Partial.Path, &d.Partial.FragsDone, &d.Partial.FragsTotal); err != nil {
+			return nil, fmt.Errorf("failed to scan interrupted download: %w", err)
+		}
+		d.CancelReason = consts.CancelReason(cancelReason.String)
+		dls = append(dls, &d)
+	}
+	return dls, rows.Err()
+}
+
+// ClearPartialDownload forgets the partial files of a video's download.
+func (ds *DownloadStore) ClearPartialDownload(videoID int64) error {
+	if _, err := squirrel.
+		Update(consts.DBDownloads).
+		Set(consts.QDLPartialPath, "").
+		Set(consts.QDLFragsDone, 0).
+		Set(consts.QDLFragsTotal, 0).
+		Set(consts.QDLUpdatedAt, time.Now()).
+		Where(squirrel.Eq{consts.QDLVidID: videoID}).
+		RunWith(ds.DB).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to clear partial download for video %d: %w", videoID, err)
+	}
 	return nil
 }
 
 // normalizeDownloadStatus normalizes percentage and statuses if required.
 func normalizeDownloadStatus(pctPtr *float64, statusPtr *consts.DownloadStatus, videoID int64) {
-	var (
-		pct    float64
-		status consts.DownloadStatus
-	)
 	if pctPtr == nil || statusPtr == nil {
 		logging.E(0, "Status or percentage passed into function null for video with ID %d", videoID)
 		return
 	}
 
+	var (
+		pct    = *pctPtr
+		status = *statusPtr
+	)
+
 	if *pctPtr >= 100.0 {
 		status = consts.DLStatusCompleted
 		pct = 100.0
@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// AddHook adds an event hook, replacing any existing hook with the same name.
+func (cs *ChannelStore) AddHook(h *models.Hook) error {
+	if h.Path == "" {
+		return errors.New("please enter a hook script path")
+	}
+	if h.Name == "" {
+		h.Name = h.Event + ": " + h.Path
+	}
+
+	args, err := json.Marshal(h.Args)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook arguments: %w", err)
+	}
+
+	const (
+		querySuffix = "ON CONFLICT (name) DO UPDATE SET event = EXCLUDED.event, path = EXCLUDED.path, args = EXCLUDED.args, timeout_secs = EXCLUDED.timeout_secs, updated_at = EXCLUDED.updated_at"
+	)
+
+	now := time.Now()
+	query := squirrel.
+		Insert(consts.DBEventHooks).
+		Columns(consts.QEvHookName, consts.QEvHookEvent, consts.QEvHookPath, consts.QEvHookArgs, consts.QEvHookTimeout, consts.QEvHookCreatedAt, consts.QEvHookUpdatedAt).
+		Values(h.Name, h.Event, h.Path, string(args), int64(h.Timeout/time.Second), now, now).
+		Suffix(querySuffix).
+		RunWith(cs.DB)
+
+	if _, err := query.Exec(); err != nil {
+		return fmt.Errorf("failed to add hook %q: %w", h.Name, err)
+	}
+
+	logging.S(0, "Added hook %q running %q on %s", h.Name, h.Path, h.Event)
+	return nil
+}
+
+// GetHooks returns the hooks for an event, or every hook if event is empty.
+func (cs *ChannelStore) GetHooks(event string) ([]*models.Hook, error) {
+	query := squirrel.
+		Select(consts.QEvHookID, consts.QEvHookName, consts.QEvHookEvent, consts.QEvHookPath, consts.QEvHookArgs, consts.QEvHookTimeout).
+		From(consts.DBEventHooks).
+		OrderBy(consts.QEvHookEvent, consts.QEvHookName).
+		RunWith(cs.DB)
+	if event != "" {
+		query = query.Where(squirrel.Eq{consts.QEvHookEvent: event})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hooks: %w", err)
+	}
+	defer rows.Close()
+
+	var hooks []*models.Hook
+	for rows.Next() {
+		var (
+			h       models.Hook
+			args    string
+			timeout int64
+		)
+		if err := rows.Scan(&h.ID, &h.Name, &h.Event, &h.Path, &args, &timeout); err != nil {
+			return nil, fmt.Errorf("failed to scan hook: %w", err)
+		}
+		if err := json.Unmarshal([]byte(args), &h.Args); err != nil {
+			return nil, fmt.Errorf("failed to decode arguments for hook %q: %w", h.Name, err)
+		}
+		h.Timeout = time.Duration(timeout) * time.Second
+		hooks = append(hooks, &h)
+	}
+	return hooks, rows.Err()
+}
+
+// DeleteHooks deletes the named hooks.
+func (cs *ChannelStore) DeleteHooks(names []string) error {
+	res, err := squirrel.
+		Delete(consts.DBEventHooks).
+		Where(squirrel.Eq{consts.QEvHookName: names}).
+		RunWith(cs.DB).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete hooks: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("no hooks named %q found", names)
+	}
+	logging.S(0, "Deleted hooks %q", names)
+	return nil
+}
@@ -0,0 +1,266 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const (
+	// hostStatsWindow is the number of results kept at full weight before counters are halved.
+	hostStatsWindow = 20
+)
+
+type HostStore struct {
+	DB *sql.DB
+}
+
+// GetHostStore returns a host store instance with injected database.
+func GetHostStore(db *sql.DB) *HostStore {
+	return &HostStore{
+		DB: db,
+	}
+}
+
+// GetDB returns the database.
+func (hs *HostStore) GetDB() *sql.DB {
+	return hs.DB
+}
+
+// RecordResult records a download result for a hostname.
+//
+// Counters are halved once they exceed the window size, so recent results weigh more heavily.
+// The block streak counts bot blocks since the last success.
+func (hs *HostStore) RecordResult(hostname string, success, botBlock bool) error {
+	if hostname == "" {
+		return errors.New("hostname cannot be blank")
+	}
+
+	var succ, fail, blocks int
+	if success {
+		succ = 1
+	} else {
+		fail = 1
+	}
+	if botBlock {
+		blocks = 1
+	}
+
+	const (
+		querySuffix = "ON CONFLICT (hostname) DO UPDATE SET " +
+			"successes = successes + EXCLUDED.successes, " +
+			"failures = failures + EXCLUDED.failures, " +
+			"bot_blocks = bot_blocks + EXCLUDED.bot_blocks, " +
+			"block_streak = CASE WHEN EXCLUDED.successes > 0 THEN 0 ELSE block_streak + EXCLUDED.block_streak END, " +
+			"new_bot_blocks = new_bot_blocks + EXCLUDED.new_bot_blocks, " +
+			"updated_at = EXCLUDED.updated_at"
+	)
+
+	now := time.Now()
+	query := squirrel.
+		Insert(consts.DBHostStats).
+		Columns(consts.QHostName, consts.QHostSuccesses, consts.QHostFailures, consts.QHostBotBlocks, consts.QHostBlockStreak, consts.QHostNewBlocks, consts.QHostCreatedAt, consts.QHostUpdatedAt).
+		Values(hostname, succ, fail, blocks, blocks, blocks, now, now).
+		Suffix(querySuffix).
+		RunWith(hs.DB)
+
+	if _, err := query.Exec(); err != nil {
+		return fmt.Errorf("failed to record result for host %q: %w", hostname, err)
+	}
+
+	decay := squirrel.
+		Update(consts.DBHostStats).
+		Set(consts.QHostSuccesses, squirrel.Expr(consts.QHostSuccesses+" / 2")).
+		Set(consts.QHostFailures, squirrel.Expr(consts.QHostFailures+" / 2")).
+		Set(consts.QHostBotBlocks, squirrel.Expr(consts.QHostBotBlocks+" / 2")).
+		Where(squirrel.Eq{consts.QHostName: hostname}).
+		Where(squirrel.Expr(consts.QHostSuccesses+" + "+consts.QHostFailures+" > ?", hostStatsWindow)).
+		RunWith(hs.DB)
+
+	if _, err := decay.Exec(); err != nil {
+		return fmt.Errorf("failed to decay stats for host %q: %w", hostname, err)
+	}
+	return nil
+}
+
+// GetHostStats returns the stats for a hostname, or a zeroed entry if none are recorded.
+func (hs *HostStore) GetHostStats(hostname string) (*models.HostStats, error) {
+	var delaySecs int
+	h := &models.HostStats{Hostname: hostname}
+
+	query := squirrel.
+		Select(consts.QHostSuccesses, consts.QHostFailures, consts.QHostBotBlocks, consts.QHostBlockStreak, consts.QHostNewBlocks, consts.QHostConcurrency, consts.QHostDelay, consts.QHostCreatedAt, consts.QHostUpdatedAt).
+		From(consts.DBHostStats).
+		Where(squirrel.Eq{consts.QHostName: hostname}).
+		RunWith(hs.DB)
+
+	if err := query.QueryRow().Scan(&h.Successes, &h.Failures, &h.BotBlocks, &h.BlockStreak, &h.NewBlocks, &h.Concurrency, &delaySecs, &h.CreatedAt, &h.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return h, nil
+		}
+		return nil, fmt.Errorf("failed to scan stats for host %q: %w", hostname, err)
+	}
+	h.Delay = time.Duration(delaySecs) * time.Second
+	return h, nil
+}
+
+// FetchAllHostStats returns stats for all recorded hostnames.
+func (hs *HostStore) FetchAllHostStats() ([]*models.HostStats, error) {
+	query := squirrel.
+		Select(consts.QHostName, consts.QHostSuccesses, consts.QHostFailures, consts.QHostBotBlocks, consts.QHostBlockStreak, consts.QHostNewBlocks, consts.QHostConcurrency, consts.QHostDelay, consts.QHostCreatedAt, consts.QHostUpdatedAt).
+		From(consts.DBHostStats).
+		OrderBy(consts.QHostName).
+		RunWith(hs.DB)
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query host stats: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logging.E(0, "Failed to close host stats rows: %v", err)
+		}
+	}()
+
+	var stats []*models.HostStats
+	for rows.Next() {
+		var delaySecs int
+		h := new(models.HostStats)
+		if err := rows.Scan(&h.Hostname, &h.Successes, &h.Failures, &h.BotBlocks, &h.BlockStreak, &h.NewBlocks, &h.Concurrency, &delaySecs, &h.CreatedAt, &h.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan host stats: %w", err)
+		}
+		h.Delay = time.Duration(delaySecs) * time.Second
+		stats = append(stats, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating host stats: %w", err)
+	}
+	return stats, nil
+}
+
+// SetHostTuning saves the effective concurrency and delay for a hostname.
+//
+// The bot blocks the tuning reacted to are taken off those since the last tuning, keeping any recorded meanwhile.
+func (hs *HostStore) SetHostTuning(hostname string, concurrency int, delay time.Duration, blocksSeen int) error {
+	const (
+		querySuffix = "ON CONFLICT (hostname) DO UPDATE SET concurrency = EXCLUDED.concurrency, " +
+			"delay_seconds = EXCLUDED.delay_seconds, new_bot_blocks = MAX(new_bot_blocks - ?, 0), updated_at = EXCLUDED.updated_at"
+	)
+
+	now := time.Now()
+	query := squirrel.
+		Insert(consts.DBHostStats).
+		Columns(consts.QHostName, consts.QHostConcurrency, consts.QHostDelay, consts.QHostCreatedAt, consts.QHostUpdatedAt).
+		Values(hostname, concurrency, int(delay.Seconds()), now, now).
+		Suffix(querySuffix, blocksSeen).
+		RunWith(hs.DB)
+
+	if _, err := query.Exec(); err != nil {
+		return fmt.Errorf("failed to set tuning for host %q: %w", hostname, err)
+	}
+	return nil
+}
+
+// SetPause pauses new downloads for a scope, replacing any existing pause for it.
+//
+// The scope is a hostname, consts.PauseAllScope, a group prefixed with consts.PauseGroupPrefix, or a
+// channel URL prefixed with consts.PauseURLPrefix. A zero until pauses until resumed.
+func (hs *HostStore) SetPause(scope string, until time.Time, reason string) error {
+	const (
+		querySuffix = "ON CONFLICT (scope) DO UPDATE SET until = EXCLUDED.until, reason = EXCLUDED.reason, created_at = EXCLUDED.created_at"
+	)
+
+	var untilVal sql.NullTime
+	if !until.IsZero() {
+		untilVal = sql.NullTime{Time: until, Valid: true}
+	}
+
+	query := squirrel.
+		Insert(consts.DBPauses).
+		Columns(consts.QPauseScope, consts.QPauseUntil, consts.QPauseReason, consts.QPauseCreatedAt).
+		Values(scope, untilVal, reason, time.Now()).
+		Suffix(querySuffix).
+		RunWith(hs.DB)
+
+	if _, err := query.Exec(); err != nil {
+		return fmt.Errorf("failed to pause %q: %w", scope, err)
+	}
+	return nil
+}
+
+// ClearPause removes the pause for a scope, reporting whether one was active.
+func (hs *HostStore) ClearPause(scope string) (bool, error) {
+	result, err := squirrel.
+		Delete(consts.DBPauses).
+		Where(squirrel.Eq{consts.QPauseScope: scope}).
+		Where(squirrel.Or{squirrel.Eq{consts.QPauseUntil: nil}, squirrel.Gt{consts.QPauseUntil: time.Now()}}).
+		RunWith(hs.DB).
+		Exec()
+	if err != nil {
+		return false, fmt.Errorf("failed to resume %q: %w", scope, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// FetchPauses returns the pauses currently in effect.
+func (hs *HostStore) FetchPauses() ([]*models.Pause, error) {
+	rows, err := squirrel.
+		Select(consts.QPauseScope, consts.QPauseUntil, consts.QPauseReason, consts.QPauseCreatedAt).
+		From(consts.DBPauses).
+		Where(squirrel.Or{squirrel.Eq{consts.QPauseUntil: nil}, squirrel.Gt{consts.QPauseUntil: time.Now()}}).
+		OrderBy(consts.QPauseScope).
+		RunWith(hs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pauses: %w", err)
+	}
+	defer rows.Close()
+
+	var pauses []*models.Pause
+	for rows.Next() {
+		var (
+			p      models.Pause
+			until  sql.NullTime
+			reason sql.NullString
+		)
+		if err := rows.Scan(&p.Scope, &until, &reason, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pause: %w", err)
+		}
+		p.Until, p.Reason = until.Time, reason.String
+		pauses = append(pauses, &p)
+	}
+	return pauses, rows.Err()
+}
+
+// ActivePause returns the pause holding back downloads from the hostname, or nil if there is none.
+//
+// A global pause takes precedence over a host pause.
+func (hs *HostStore) ActivePause(hostname string) (*models.Pause, error) {
+	pauses, err := hs.FetchPauses()
+	if err != nil {
+		return nil, err
+	}
+
+	var match *models.Pause
+	for _, p := range pauses {
+		if !p.Covers(hostname) {
+			continue
+		}
+		if p.Scope == consts.PauseAllScope {
+			return p, nil
+		}
+		match = p
+	}
+	return match, nil
+}
@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/process"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// AddGlobalIgnore adds an ignore rule applied to every channel, returning false if it already exists.
+func (cs *ChannelStore) AddGlobalIgnore(g *models.GlobalIgnore) (added bool, err error) {
+	if g.Value == "" {
+		return false, errors.New("please enter a URL or pattern to ignore")
+	}
+	switch g.Kind {
+	case consts.IgnoreURL:
+	case consts.IgnorePattern, consts.IgnoreTitle:
+		if _, err := regexp.Compile(g.Value); err != nil {
+			return false, fmt.Errorf("invalid global ignore %s %q: %w", g.Kind, g.Value, err)
+		}
+	default:
+		return false, fmt.Errorf("invalid ignore kind %q", g.Kind)
+	}
+
+	res, err := squirrel.
+		Insert(consts.DBGlobalIgnores).
+		Columns(consts.QGIgnKind, consts.QGIgnValue, consts.QGIgnNote, consts.QGIgnCreatedAt).
+		Values(g.Kind, g.Value, g.Note, time.Now()).
+		Suffix("ON CONFLICT (kind, value) DO NOTHING").
+		RunWith(cs.DB).
+		Exec()
+	if err != nil {
+		return false, fmt.Errorf("failed to add global ignore %q: %w", g.Value, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return false, nil
+	}
+
+	process.ResetGlobalIgnores()
+	logging.S(0, "Added %s %q to the global ignore list", g.Kind, g.Value)
+	return true, nil
+}
+
+// GetGlobalIgnores returns every global ignore rule, oldest first.
+func (cs *ChannelStore) GetGlobalIgnores() ([]*models.GlobalIgnore, error) {
+	rows, err := squirrel.
+		Select(consts.QGIgnID, consts.QGIgnKind, consts.QGIgnValue, consts.QGIgnNote, consts.QGIgnCreatedAt).
+		From(consts.DBGlobalIgnores).
+		OrderBy(consts.QGIgnID).
+		RunWith(cs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query global ignores: %w", err)
+	}
+	defer rows.Close()
+
+	var ignores []*models.GlobalIgnore
+	for rows.Next() {
+		var g models.GlobalIgnore
+		if err := rows.Scan(&g.ID, &g.Kind, &g.Value, &g.Note, &g.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan global ignore: %w", err)
+		}
+		ignores = append(ignores, &g)
+	}
+	return ignores, rows.Err()
+}
+
+// DeleteGlobalIgnores deletes global ignore rules by ID.
+func (cs *ChannelStore) DeleteGlobalIgnores(ids []int64) error {
+	res, err := squirrel.
+		Delete(consts.DBGlobalIgnores).
+		Where(squirrel.Eq{consts.QGIgnID: ids}).
+		RunWith(cs.DB).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete global ignores: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("no global ignores with IDs %v found", ids)
+	}
+
+	process.ResetGlobalIgnores()
+	logging.S(0, "Deleted global ignores with IDs %v", ids)
+	return nil
+}
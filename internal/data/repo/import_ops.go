@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ImportVideos records existing files as finished downloads of the channel's videos, in a single transaction.
+//
+// Videos already downloaded to a file which still exists are left alone. Returns the videos imported.
+func (vs VideoStore) ImportVideos(chanID int64, videos []*models.Video) ([]*models.Video, error) {
+	tx, err := vs.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	var committed bool
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logging.E(0, "Error rolling back import: %v", err)
+			}
+		}
+	}()
+
+	const (
+		join = consts.DBDownloads + " ON " + consts.DBDownloads + "." + consts.QDLVidID + " = " + consts.DBVideos + "." + consts.QVidID
+	)
+
+	col := func(c string) string { return consts.DBVideos + "." + c }
+	now := time.Now()
+	imported := make([]*models.Video, 0, len(videos))
+	for _, v := range videos {
+		var (
+			id        int64
+			videoPath sql.NullString
+			status    sql.NullString
+		)
+		err := squirrel.
+			Select(col(consts.QVidID), col(consts.QVidVideoPath), consts.DBDownloads+"."+consts.QDLStatus).
+			From(consts.DBVideos).
+			LeftJoin(join).
+			Where(squirrel.Eq{col(consts.QVidChanID): chanID, col(consts.QVidURL): v.URL}).
+			RunWith(tx).
+			QueryRow().
+			Scan(&id, &videoPath, &status)
+
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			res, err := squirrel.
+				Insert(consts.DBVideos).
+				Columns(consts.QVidChanID, consts.QVidURL, consts.QVidTitle, consts.QVidVideoDir, consts.QVidJSONDir,
+					consts.QVidVideoPath, consts.QVidJSONPath, consts.QVidDownloaded, consts.QVidCreatedAt, consts.QVidUpdatedAt).
+				Values(chanID, v.URL, v.Title, v.VideoDir, v.JSONDir, v.VideoPath, v.JSONPath, true, now, now).
+				RunWith(tx).
+				Exec()
+			if err != nil {
+				return nil, fmt.Errorf("failed to add video %q: %w", v.URL, err)
+			}
+			if id, err = res.LastInsertId(); err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, fmt.Errorf("failed to look up video %q: %w", v.URL, err)
+		default:
+			if status.String == string(consts.DLStatusCompleted) && videoPath.String != "" {
+				if _, err := os.Stat(videoPath.String); err == nil {
+					logging.D(1, "Video %q is already downloaded to %q, skipping", v.URL, videoPath.String)
+					continue
+				}
+			}
+			query := squirrel.
+				Update(consts.DBVideos).
+				Set(consts.QVidVideoDir, v.VideoDir).
+				Set(consts.QVidJSONDir, v.JSONDir).
+				Set(consts.QVidVideoPath, v.VideoPath).
+				Set(consts.QVidJSONPath, v.JSONPath).
+				Set(consts.QVidDownloaded, true).
+				Set(consts.QVidUpdatedAt, now).
+				Where(squirrel.Eq{consts.QVidID: id})
+			if v.Title != "" {
+				query = query.Set(consts.QVidTitle, v.Title)
+			}
+			if _, err := query.RunWith(tx).Exec(); err != nil {
+				return nil, fmt.Errorf("failed to update video %q: %w", v.URL, err)
+			}
+		}
+
+		if err := upsertDownloadStatus(tx, id, consts.DLStatusCompleted, 100.0); err != nil {
+			return nil, fmt.Errorf("failed to mark video %q finished: %w", v.URL, err)
+		}
+		v.ID, v.ChannelID = id, chanID
+		imported = append(imported, v)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	committed = true
+	return imported, nil
+}
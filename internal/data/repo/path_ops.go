@@ -0,0 +1,296 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// RewritePaths moves every stored channel directory, video path, and post directory under the from root to the to root in one transaction.
+//
+// Rewritten file paths are checked on disk. Unless allowMissing is set, any missing file rolls back the rewrite.
+// Dry runs report the same results without committing.
+func (ss *StorageStore) RewritePaths(from, to string, dryRun, allowMissing bool) (*models.PathRewrite, error) {
+	from, to = filepath.Clean(from), filepath.Clean(to)
+	if from == to {
+		return nil, fmt.Errorf("old and new roots are the same: %q", from)
+	}
+
+	tx, err := ss.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	var committed bool
+	defer func() {
+		if !committed {
+			if err := tx.Rollback(); err != nil {
+				logging.E(0, "Error rolling back path rewrite from %q: %v", from, err)
+			}
+		}
+	}()
+
+	res := &models.PathRewrite{}
+	if res.Channels, err = rewriteChannelPaths(tx, from, to); err != nil {
+		return nil, err
+	}
+	if res.Videos, res.Missing, err = rewriteVideoPaths(tx, from, to); err != nil {
+		return nil, err
+	}
+	var missingPosts []string
+	if res.Posts, missingPosts, err = rewritePostPaths(tx, from, to); err != nil {
+		return nil, err
+	}
+	res.Missing = append(res.Missing, missingPosts...)
+
+	if dryRun {
+		return res, nil
+	}
+	if len(res.Missing) > 0 && !allowMissing {
+		return res, fmt.Errorf("%d rewritten file(s) not found under %q, move the files first or allow missing files", len(res.Missing), to)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit path rewrite: %w", err)
+	}
+	committed = true
+	return res, nil
+}
+
+// rewriteChannelPaths rewrites channel directories and Metarr output directories, returning the channels changed.
+func rewriteChannelPaths(tx *sql.Tx, from, to string) (int, error) {
+	rows, err := squirrel.
+		Select(consts.QChanID, consts.QChanVideoDir, consts.QChanJSONDir, consts.QChanMetarr).
+		From(consts.DBChannels).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query channels: %w", err)
+	}
+
+	type channelDirs struct {
+		id           int64
+		vDir, jDir   string
+		metarr       []byte
+		metarrOutDir bool
+	}
+	var changed []channelDirs
+	for rows.Next() {
+		var (
+			c          channelDirs
+			vDir, jDir sql.NullString
+		)
+		if err := rows.Scan(&c.id, &vDir, &jDir, &c.metarr); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan channel: %w", err)
+		}
+
+		var vOK, jOK bool
+		c.vDir, vOK = rebase(vDir.String, from, to)
+		c.jDir, jOK = rebase(jDir.String, from, to)
+		if c.metarr, c.metarrOutDir, err = rebaseMetarrOutDir(c.metarr, from, to); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("channel with ID %d: %w", c.id, err)
+		}
+		if vOK || jOK || c.metarrOutDir {
+			changed = append(changed, c)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+
+	for _, c := range changed {
+		if _, err := squirrel.
+			Update(consts.DBChannels).
+			Set(consts.QChanVideoDir, c.vDir).
+			Set(consts.QChanJSONDir, c.jDir).
+			Set(consts.QChanMetarr, c.metarr).
+			Set(consts.QChanUpdatedAt, time.Now()).
+			Where(squirrel.Eq{consts.QChanID: c.id}).
+			RunWith(tx).
+			Exec(); err != nil {
+			return 0, fmt.Errorf("failed to rewrite paths for channel with ID %d: %w", c.id, err)
+		}
+	}
+	return len(changed), nil
+}
+
+// rewriteVideoPaths rewrites video directories and file paths, returning the videos changed and any missing files.
+func rewriteVideoPaths(tx *sql.Tx, from, to string) (int, []string, error) {
+	rows, err := squirrel.
+		Select(consts.QVidID, consts.QVidVideoDir, consts.QVidJSONDir, consts.QVidVideoPath, consts.QVidJSONPath, consts.QVidTranscript, consts.QVidMetarr).
+		From(consts.DBVideos).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to query videos: %w", err)
+	}
+
+	type videoPaths struct {
+		id                              int64
+		vDir, jDir, vPath, jPath, tPath string
+		metarr                          []byte
+	}
+	var (
+		changed []videoPaths
+		missing []string
+	)
+	for rows.Next() {
+		var (
+			v                               videoPaths
+			vDir, jDir, vPath, jPath, tPath sql.NullString
+			vDirOK, jDirOK, vOK, jOK, tOK   bool
+			metarrOK                        bool
+		)
+		if err := rows.Scan(&v.id, &vDir, &jDir, &vPath, &jPath, &tPath, &v.metarr); err != nil {
+			rows.Close()
+			return 0, nil, fmt.Errorf("failed to scan video: %w", err)
+		}
+
+		v.vDir, vDirOK = rebase(vDir.String, from, to)
+		v.jDir, jDirOK = rebase(jDir.String, from, to)
+		v.vPath, vOK = rebase(vPath.String, from, to)
+		v.jPath, jOK = rebase(jPath.String, from, to)
+		v.tPath, tOK = rebase(tPath.String, from, to)
+		if v.metarr, metarrOK, err = rebaseMetarrOutDir(v.metarr, from, to); err != nil {
+			rows.Close()
+			return 0, nil, fmt.Errorf("video with ID %d: %w", v.id, err)
+		}
+		if !vDirOK && !jDirOK && !vOK && !jOK && !tOK && !metarrOK {
+			continue
+		}
+
+		for _, p := range []string{v.vPath, v.jPath, v.tPath} {
+			if p == "" {
+				continue
+			}
+			if _, err := os.Stat(p); err != nil {
+				missing = append(missing, p)
+			}
+		}
+		changed = append(changed, v)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, nil, err
+	}
+	rows.Close()
+
+	for _, v := range changed {
+		if _, err := squirrel.
+			Update(consts.DBVideos).
+			Set(consts.QVidVideoDir, v.vDir).
+			Set(consts.QVidJSONDir, v.jDir).
+			Set(consts.QVidVideoPath, v.vPath).
+			Set(consts.QVidJSONPath, v.jPath).
+			Set(consts.QVidTranscript, v.tPath).
+			Set(consts.QVidMetarr, v.metarr).
+			Set(consts.QVidUpdatedAt, time.Now()).
+			Where(squirrel.Eq{consts.QVidID: v.id}).
+			RunWith(tx).
+			Exec(); err != nil {
+			return 0, nil, fmt.Errorf("failed to rewrite paths for video with ID %d: %w", v.id, err)
+		}
+	}
+	return len(changed), missing, nil
+}
+
+// rewritePostPaths rewrites archived post directories, returning the posts changed and any missing directories.
+func rewritePostPaths(tx *sql.Tx, from, to string) (int, []string, error) {
+	rows, err := squirrel.
+		Select(consts.QPostID, consts.QPostDir).
+		From(consts.DBPosts).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to query posts: %w", err)
+	}
+
+	type postDir struct {
+		id  int64
+		dir string
+	}
+	var (
+		changed []postDir
+		missing []string
+	)
+	for rows.Next() {
+		var (
+			p   postDir
+			dir sql.NullString
+			ok  bool
+		)
+		if err := rows.Scan(&p.id, &dir); err != nil {
+			rows.Close()
+			return 0, nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		if p.dir, ok = rebase(dir.String, from, to); !ok {
+			continue
+		}
+		if _, err := os.Stat(p.dir); err != nil {
+			missing = append(missing, p.dir)
+		}
+		changed = append(changed, p)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, nil, err
+	}
+	rows.Close()
+
+	for _, p := range changed {
+		if _, err := squirrel.
+			Update(consts.DBPosts).
+			Set(consts.QPostDir, p.dir).
+			Where(squirrel.Eq{consts.QPostID: p.id}).
+			RunWith(tx).
+			Exec(); err != nil {
+			return 0, nil, fmt.Errorf("failed to rewrite directory for post with ID %d: %w", p.id, err)
+		}
+	}
+	return len(changed), missing, nil
+}
+
+// rebase swaps the from root at the start of a path for the to root, reporting whether the path was under from.
+func rebase(path, from, to string) (string, bool) {
+	switch {
+	case path == from:
+		return to, true
+	case strings.HasPrefix(path, from+string(filepath.Separator)):
+		return to + path[len(from):], true
+	}
+	return path, false
+}
+
+// rebaseMetarrOutDir rebases the output directory in stored Metarr arguments.
+func rebaseMetarrOutDir(metarr []byte, from, to string) ([]byte, bool, error) {
+	if len(metarr) == 0 {
+		return metarr, false, nil
+	}
+	var args models.MetarrArgs
+	if err := json.Unmarshal(metarr, &args); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal Metarr args: %w", err)
+	}
+
+	var ok bool
+	if args.OutputDir, ok = rebase(args.OutputDir, from, to); !ok {
+		return metarr, false, nil
+	}
+	out, err := json.Marshal(args)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal Metarr args: %w", err)
+	}
+	return out, true, nil
+}
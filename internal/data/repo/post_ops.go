@@ -0,0 +1,115 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type PostStore struct {
+	DB *sql.DB
+}
+
+// GetPostStore returns a post store instance with injected database.
+func GetPostStore(db *sql.DB) *PostStore {
+	return &PostStore{
+		DB: db,
+	}
+}
+
+// GetDB returns the database.
+func (ps *PostStore) GetDB() *sql.DB {
+	return ps.DB
+}
+
+// AddPost records an archived post, returning false if the channel already has the post.
+func (ps *PostStore) AddPost(p *models.Post) (added bool, err error) {
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now()
+	}
+
+	files, err := json.Marshal(p.Files)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal files of post %q: %w", p.PostID, err)
+	}
+
+	var postedAt sql.NullTime
+	if !p.PostedAt.IsZero() {
+		postedAt = sql.NullTime{Time: p.PostedAt, Valid: true}
+	}
+
+	result, err := squirrel.
+		Insert(consts.DBPosts).
+		Columns(consts.QPostChanID, consts.QPostPostID, consts.QPostURL, consts.QPostText, consts.QPostFiles,
+			consts.QPostDir, consts.QPostPostedAt, consts.QPostCreatedAt).
+		Values(p.ChannelID, p.PostID, p.URL, p.Text, string(files), p.Dir, postedAt, p.CreatedAt).
+		Suffix("ON CONFLICT (" + consts.QPostChanID + ", " + consts.QPostPostID + ") DO NOTHING").
+		RunWith(ps.DB).
+		Exec()
+	if err != nil {
+		return false, fmt.Errorf("failed to add post %q: %w", p.PostID, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// HasPost reports whether the channel already has the post archived.
+func (ps *PostStore) HasPost(channelID int64, postID string) (bool, error) {
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM " + consts.DBPosts + " WHERE " + consts.QPostChanID + " = ? AND " + consts.QPostPostID + " = ?)"
+	if err := ps.DB.QueryRow(query, channelID, postID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check for post %q: %w", postID, err)
+	}
+	return exists, nil
+}
+
+// FetchPosts returns the archived posts for a channel, newest first.
+//
+// At most limit posts are returned, or all posts if limit is 0.
+func (ps *PostStore) FetchPosts(channelID int64, limit int) ([]*models.Post, error) {
+	query := squirrel.
+		Select(consts.QPostID, consts.QPostChanID, consts.QPostPostID, consts.QPostURL, consts.QPostText, consts.QPostFiles,
+			consts.QPostDir, consts.QPostPostedAt, consts.QPostCreatedAt).
+		From(consts.DBPosts).
+		Where(squirrel.Eq{consts.QPostChanID: channelID}).
+		OrderBy("COALESCE("+consts.QPostPostedAt+", "+consts.QPostCreatedAt+") DESC", consts.QPostID+" DESC")
+
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	rows, err := query.RunWith(ps.DB).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*models.Post
+	for rows.Next() {
+		var (
+			p                models.Post
+			url, text, files sql.NullString
+			postedAt         sql.NullTime
+		)
+		if err := rows.Scan(&p.ID, &p.ChannelID, &p.PostID, &url, &text, &files, &p.Dir, &postedAt, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan post: %w", err)
+		}
+		p.URL, p.Text, p.PostedAt = url.String, text.String, postedAt.Time
+		if files.String != "" {
+			if err := json.Unmarshal([]byte(files.String), &p.Files); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal files of post %q: %w", p.PostID, err)
+			}
+		}
+		posts = append(posts, &p)
+	}
+	return posts, rows.Err()
+}
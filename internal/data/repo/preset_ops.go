@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// AddPreset stores a named preset, replacing any existing preset with the name if replace is set.
+func (cs *ChannelStore) AddPreset(p *models.Preset, replace bool) error {
+	settingsJSON, err := json.Marshal(p.Settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preset settings: %w", err)
+	}
+	metarrJSON, err := json.Marshal(p.MetarrArgs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preset metarr settings: %w", err)
+	}
+
+	now := time.Now()
+	query := squirrel.
+		Insert(consts.DBPresets).
+		Columns(consts.QPresetName, consts.QPresetSettings, consts.QPresetMetarr, consts.QPresetCreatedAt, consts.QPresetUpdatedAt).
+		Values(p.Name, settingsJSON, metarrJSON, now, now)
+	if replace {
+		query = query.Suffix("ON CONFLICT (name) DO UPDATE SET settings = excluded.settings, metarr = excluded.metarr, updated_at = excluded.updated_at")
+	}
+
+	if _, err := query.RunWith(cs.DB).Exec(); err != nil {
+		if !replace && cs.presetExists(p.Name) {
+			return fmt.Errorf("preset %q already exists", p.Name)
+		}
+		return fmt.Errorf("failed to store preset %q: %w", p.Name, err)
+	}
+	return nil
+}
+
+// GetPreset returns a preset by name.
+func (cs *ChannelStore) GetPreset(name string) (*models.Preset, error) {
+	row := squirrel.
+		Select(consts.QPresetName, consts.QPresetSettings, consts.QPresetMetarr, consts.QPresetCreatedAt, consts.QPresetUpdatedAt).
+		From(consts.DBPresets).
+		Where(squirrel.Eq{consts.QPresetName: name}).
+		RunWith(cs.DB).
+		QueryRow()
+
+	p, err := scanPreset(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no preset named %q, see 'preset list'", name)
+	}
+	return p, err
+}
+
+// FetchPresets returns every preset, ordered by name.
+func (cs *ChannelStore) FetchPresets() ([]*models.Preset, error) {
+	rows, err := squirrel.
+		Select(consts.QPresetName, consts.QPresetSettings, consts.QPresetMetarr, consts.QPresetCreatedAt, consts.QPresetUpdatedAt).
+		From(consts.DBPresets).
+		OrderBy(consts.QPresetName).
+		RunWith(cs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query presets: %w", err)
+	}
+	defer rows.Close()
+
+	var presets []*models.Preset
+	for rows.Next() {
+		p, err := scanPreset(rows)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, p)
+	}
+	return presets, rows.Err()
+}
+
+// DeletePreset deletes a preset, reporting whether it existed.
+//
+// Channels added with the preset keep the settings it gave them.
+func (cs *ChannelStore) DeletePreset(name string) (bool, error) {
+	result, err := squirrel.
+		Delete(consts.DBPresets).
+		Where(squirrel.Eq{consts.QPresetName: name}).
+		RunWith(cs.DB).
+		Exec()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete preset %q: %w", name, err)
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// presetExists reports whether a preset with the name is stored.
+func (cs *ChannelStore) presetExists(name string) bool {
+	var n int
+	err := squirrel.
+		Select("COUNT(*)").
+		From(consts.DBPresets).
+		Where(squirrel.Eq{consts.QPresetName: name}).
+		RunWith(cs.DB).
+		QueryRow().
+		Scan(&n)
+	return err == nil && n > 0
+}
+
+// scanPreset scans a preset row, unmarshalling its settings.
+func scanPreset(row squirrel.RowScanner) (*models.Preset, error) {
+	var (
+		p                        models.Preset
+		settingsJSON, metarrJSON []byte
+	)
+	if err := row.Scan(&p.Name, &settingsJSON, &metarrJSON, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan preset: %w", err)
+	}
+	if err := json.Unmarshal(settingsJSON, &p.Settings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal settings for preset %q: %w", p.Name, err)
+	}
+	if err := json.Unmarshal(metarrJSON, &p.MetarrArgs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metarr settings for preset %q: %w", p.Name, err)
+	}
+	return &p, nil
+}
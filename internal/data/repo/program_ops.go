@@ -11,9 +11,13 @@ import (
 	"github.com/Masterminds/squirrel"
 )
 
+// staleHeartbeat is how long without a heartbeat before a running instance is presumed dead.
+const staleHeartbeat = 2 * time.Minute
+
 type ProgControl struct {
 	DB        *sql.DB
 	ProcessID int
+	ReadOnly  bool
 }
 
 // NewProgController returns a program controller for updating primary program elements.
@@ -26,7 +30,12 @@ func NewProgController(database *sql.DB) *ProgControl {
 }
 
 // StartTubarr sets Tubarr fields in the database.
+//
+// Read-only instances don't claim the program row, so they may run alongside a primary instance.
 func (pc ProgControl) StartTubarr() (pid int, err error) {
+	if pc.ReadOnly {
+		return os.Getpid(), nil
+	}
 
 	// Check running or stale state
 	if id, running := pc.checkProgRunning(); running {
@@ -56,11 +65,15 @@ func (pc ProgControl) StartTubarr() (pid int, err error) {
 	if _, err := query.Exec(); err != nil {
 		return pid, fmt.Errorf("failure: %w", err)
 	}
+	pc.clearScheduler()
 	return pid, nil
 }
 
 // QuitTubarr sets the program exit fields, ready for next run.
 func (pc ProgControl) QuitTubarr() error {
+	if pc.ReadOnly {
+		return nil
+	}
 	if id, running := pc.checkProgRunning(); !running {
 		return fmt.Errorf("tubarr is not marked as running. Process %d still active?", id)
 	}
@@ -80,6 +93,7 @@ func (pc ProgControl) QuitTubarr() error {
 	if _, err := query.Exec(); err != nil {
 		return err
 	}
+	pc.clearScheduler()
 	logging.I("Quitting Tubarr...\n")
 	return nil
 }
@@ -89,6 +103,9 @@ func (pc ProgControl) QuitTubarr() error {
 // This function is crucial for ensuring things like powercuts don't
 // permanently lock the user out of the database.
 func (pc ProgControl) UpdateHeartbeat() error {
+	if pc.ReadOnly {
+		return nil
+	}
 	query := squirrel.
 		Update(consts.DBProgram).
 		Set(consts.QProgHeartbeat, time.Now()).
@@ -101,8 +118,67 @@ func (pc ProgControl) UpdateHeartbeat() error {
 	return nil
 }
 
+// SetScheduler records that this instance runs the scheduler, and the address it serves its HTTP API on if any.
+//
+// Other instances send commands to the HTTP API, or else queue them for the scheduler.
+func (pc ProgControl) SetScheduler(addr string) error {
+	if pc.ReadOnly {
+		return nil
+	}
+	_, err := squirrel.
+		Update(consts.DBProgram).
+		Set(consts.QProgScheduler, true).
+		Set(consts.QProgHTTPAddr, addr).
+		Where(squirrel.Eq{consts.QProgID: 1}).
+		RunWith(pc.DB).
+		Exec()
+	return err
+}
+
+// RunningInstance returns the PID of the running instance, the address of its HTTP API if served, and whether
+// it runs the scheduler.
+//
+// Returns false if no instance is running or its heartbeat is stale.
+func (pc ProgControl) RunningInstance() (pid int, addr string, scheduler, ok bool) {
+	var (
+		running   bool
+		heartbeat time.Time
+	)
+	err := squirrel.
+		Select(consts.QProgRunning, consts.QProgPID, consts.QProgHTTPAddr, consts.QProgScheduler, consts.QProgHeartbeat).
+		From(consts.DBProgram).
+		Where(squirrel.Eq{consts.QProgID: 1}).
+		RunWith(pc.DB).
+		QueryRow().
+		Scan(&running, &pid, &addr, &scheduler, &heartbeat)
+	if err != nil {
+		logging.E(0, "Failed to query program running row: %v", err)
+		return 0, "", false, false
+	}
+	if !running || time.Since(heartbeat) > staleHeartbeat {
+		return 0, "", false, false
+	}
+	return pid, addr, scheduler, true
+}
+
 // Private ////////////////////////////////////////////////////////////////////////////////////////////
 
+// clearScheduler clears the scheduler state recorded by SetScheduler.
+//
+// Kept apart from the other program fields, so the program row can still be claimed and released
+// after 'db rollback' drops these columns.
+func (pc ProgControl) clearScheduler() {
+	if _, err := squirrel.
+		Update(consts.DBProgram).
+		Set(consts.QProgHTTPAddr, "").
+		Set(consts.QProgScheduler, false).
+		Where(squirrel.Eq{consts.QProgID: 1}).
+		RunWith(pc.DB).
+		Exec(); err != nil {
+		logging.D(1, "Could not clear scheduler state: %v", err)
+	}
+}
+
 // checkProgRunning checks if the program is already running.
 func (pc ProgControl) checkProgRunning() (int, bool) {
 	var (
@@ -138,7 +214,7 @@ func (pc ProgControl) resetStaleProcess() (reset bool, err error) {
 		return false, err
 	}
 
-	if time.Since(lastHeartbeat) > 2*time.Minute {
+	if time.Since(lastHeartbeat) > staleHeartbeat {
 
 		logging.I("Detected stale process, resetting state...")
 
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// RecordCrawlReport stores a crawl pass summary, returning its ID.
+func (ss *StatsStore) RecordCrawlReport(r *models.CrawlReport) (int64, error) {
+	channels, err := json.Marshal(r.Channels)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode crawl report channels: %w", err)
+	}
+
+	t := r.Totals()
+	res, err := squirrel.
+		Insert(consts.DBCrawlReports).
+		Columns(consts.QReportStartedAt, consts.QReportFinishedAt, consts.QReportNewVideos, consts.QReportFailures, consts.QReportFiltered, consts.QReportChannels).
+		Values(r.StartedAt, r.FinishedAt, t.NewVideos, t.Failures, t.Filtered, string(channels)).
+		RunWith(ss.DB).
+		Exec()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record crawl report: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// MarkDigestSent records that the crawl report was sent as a digest.
+func (ss *StatsStore) MarkDigestSent(reportID int64) error {
+	_, err := squirrel.
+		Update(consts.DBCrawlReports).
+		Set(consts.QReportDigestSent, true).
+		Where(squirrel.Eq{consts.QReportID: reportID}).
+		RunWith(ss.DB).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to mark digest sent for crawl report %d: %w", reportID, err)
+	}
+	return nil
+}
+
+// FetchCrawlReports returns the most recent crawl reports, newest first.
+func (ss *StatsStore) FetchCrawlReports(limit int) ([]*models.CrawlReport, error) {
+	rows, err := squirrel.
+		Select(consts.QReportID, consts.QReportStartedAt, consts.QReportFinishedAt, consts.QReportChannels, consts.QReportDigestSent).
+		From(consts.DBCrawlReports).
+		OrderBy(consts.QReportStartedAt + " DESC").
+		Limit(uint64(max(limit, 1))).
+		RunWith(ss.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query crawl reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*models.CrawlReport
+	for rows.Next() {
+		var (
+			r        models.CrawlReport
+			channels string
+		)
+		if err := rows.Scan(&r.ID, &r.StartedAt, &r.FinishedAt, &channels, &r.DigestSent); err != nil {
+			return nil, fmt.Errorf("failed to scan crawl report: %w", err)
+		}
+		if err := json.Unmarshal([]byte(channels), &r.Channels); err != nil {
+			return nil, fmt.Errorf("invalid channels in crawl report %d: %w", r.ID, err)
+		}
+		reports = append(reports, &r)
+	}
+	return reports, rows.Err()
+}
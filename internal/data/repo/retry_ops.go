@@ -0,0 +1,176 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// maxRetryBackoff caps the exponential delay between retries.
+const maxRetryBackoff = 24 * time.Hour
+
+type RetryStore struct {
+	DB *sql.DB
+}
+
+// GetRetryStore returns a retry queue store instance with injected database.
+func GetRetryStore(db *sql.DB) *RetryStore {
+	return &RetryStore{
+		DB: db,
+	}
+}
+
+// GetDB returns the database.
+func (rs *RetryStore) GetDB() *sql.DB {
+	return rs.DB
+}
+
+// QueueRetry records a failed download attempt, scheduling the next try with exponential backoff.
+//
+// Once maxAttempts failures are recorded the entry is kept, but no further retry is scheduled.
+func (rs *RetryStore) QueueRetry(channelID int64, url, lastErr string, maxAttempts int, base time.Duration) (*models.RetryEntry, error) {
+	var attempts int
+	err := squirrel.
+		Select(consts.QRetryAttempts).
+		From(consts.DBRetries).
+		Where(squirrel.Eq{consts.QRetryChanID: channelID, consts.QRetryURL: url}).
+		RunWith(rs.DB).
+		QueryRow().
+		Scan(&attempts)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to look up retry entry for %q: %w", url, err)
+	}
+
+	now := time.Now()
+	entry := &models.RetryEntry{
+		ChannelID: channelID,
+		URL:       url,
+		Attempts:  attempts + 1,
+		LastError: lastErr,
+		UpdatedAt: now,
+	}
+
+	var next any // NULL once attempts are exhausted
+	if entry.Attempts < maxAttempts {
+		entry.NextAttempt = now.Add(retryBackoff(base, entry.Attempts))
+		next = entry.NextAttempt
+	}
+
+	const (
+		querySuffix = "ON CONFLICT (channel_id, url) DO UPDATE SET attempts = EXCLUDED.attempts, next_attempt_at = EXCLUDED.next_attempt_at, last_error = EXCLUDED.last_error, updated_at = EXCLUDED.updated_at"
+	)
+
+	if _, err := squirrel.
+		Insert(consts.DBRetries).
+		Columns(consts.QRetryChanID, consts.QRetryURL, consts.QRetryAttempts, consts.QRetryNextAttempt, consts.QRetryLastError, consts.QRetryUpdatedAt).
+		Values(channelID, url, entry.Attempts, next, lastErr, now).
+		Suffix(querySuffix).
+		RunWith(rs.DB).
+		Exec(); err != nil {
+		return nil, fmt.Errorf("failed to queue retry for %q: %w", url, err)
+	}
+	return entry, nil
+}
+
+// ClearRetry removes a video from the retry queue, e.g. after it downloads successfully.
+func (rs *RetryStore) ClearRetry(channelID int64, url string) error {
+	if _, err := squirrel.
+		Delete(consts.DBRetries).
+		Where(squirrel.Eq{consts.QRetryChanID: channelID, consts.QRetryURL: url}).
+		RunWith(rs.DB).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to clear retry entry for %q: %w", url, err)
+	}
+	return nil
+}
+
+// FetchDueRetries returns entries whose next attempt is at or before the given time.
+func (rs *RetryStore) FetchDueRetries(at time.Time) ([]*models.RetryEntry, error) {
+	return rs.fetchRetries(squirrel.LtOrEq{consts.QRetryNextAttempt: at}, consts.QRetryNextAttempt)
+}
+
+// FetchChannelRetries returns all queued and exhausted entries for a channel, newest failure first.
+//
+// A channel ID of 0 returns entries for all channels.
+func (rs *RetryStore) FetchChannelRetries(channelID int64) ([]*models.RetryEntry, error) {
+	var where squirrel.Sqlizer = squirrel.Expr("1 = 1")
+	if channelID != 0 {
+		where = squirrel.Eq{consts.QRetryChanID: channelID}
+	}
+	return rs.fetchRetries(where, consts.QRetryUpdatedAt+" DESC")
+}
+
+// NextRetryAt returns when the earliest retry due after the given time is, or zero if there are none.
+func (rs *RetryStore) NextRetryAt(after time.Time) (time.Time, error) {
+	var next sql.NullTime
+	err := squirrel.
+		Select(consts.QRetryNextAttempt).
+		From(consts.DBRetries).
+		Where(squirrel.Gt{consts.QRetryNextAttempt: after}).
+		OrderBy(consts.QRetryNextAttempt).
+		Limit(1).
+		RunWith(rs.DB).
+		QueryRow().
+		Scan(&next)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query next retry: %w", err)
+	}
+	return next.Time, nil
+}
+
+// retryFailReason selects the fail reason stored with the download of a retry entry's video.
+const retryFailReason = "(SELECT d." + consts.QDLFailReason + " FROM " + consts.DBDownloads + " d" +
+	" JOIN " + consts.DBVideos + " v ON v." + consts.QVidID + " = d." + consts.QDLVidID +
+	" WHERE v." + consts.QVidChanID + " = " + consts.DBRetries + "." + consts.QRetryChanID +
+	" AND v." + consts.QVidURL + " = " + consts.DBRetries + "." + consts.QRetryURL + ")"
+
+// fetchRetries returns retry entries matching the condition, with the fail reason of their last attempt.
+func (rs *RetryStore) fetchRetries(where squirrel.Sqlizer, orderBy string) ([]*models.RetryEntry, error) {
+	rows, err := squirrel.
+		Select(consts.QRetryChanID, consts.QRetryURL, consts.QRetryAttempts, consts.QRetryNextAttempt, consts.QRetryLastError, retryFailReason, consts.QRetryUpdatedAt).
+		From(consts.DBRetries).
+		Where(where).
+		OrderBy(orderBy).
+		RunWith(rs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query retry queue: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.RetryEntry
+	for rows.Next() {
+		var (
+			e       models.RetryEntry
+			next    sql.NullTime
+			lastErr sql.NullString
+			reason  sql.NullString
+		)
+		if err := rows.Scan(&e.ChannelID, &e.URL, &e.Attempts, &next, &lastErr, &reason, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan retry entry: %w", err)
+		}
+		e.NextAttempt = next.Time
+		e.LastError = lastErr.String
+		e.FailReason = consts.FailReason(reason.String)
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// retryBackoff doubles the base delay for each failed attempt, up to maxRetryBackoff.
+func retryBackoff(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
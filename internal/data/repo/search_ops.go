@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// SearchVideos returns videos whose title, description, or metadata contain every word of the query, newest first.
+//
+// Words match as prefixes, so "linux inst" finds "Installing Linux". A channel ID of 0 searches every channel.
+func (vs VideoStore) SearchVideos(q string, chanID int64, limit int) ([]*models.Video, error) {
+	match := searchMatch(q)
+	if match == "" {
+		return nil, fmt.Errorf("search query %q has no words to search for", q)
+	}
+
+	query := squirrel.
+		Select(
+			"v."+consts.QVidID,
+			"v."+consts.QVidChanID,
+			"c."+consts.QChanName,
+			"v."+consts.QVidURL,
+			"v."+consts.QVidTitle,
+			"v."+consts.QVidVideoPath,
+			"COALESCE(d."+consts.QDLStatus+", v."+consts.QVidDLStatus+")",
+			"v."+consts.QVidUploadDate,
+		).
+		From(consts.DBVideoSearch).
+		Join(consts.DBVideos+" v ON v."+consts.QVidID+" = "+consts.DBVideoSearch+".docid").
+		Join(consts.DBChannels+" c ON c."+consts.QChanID+" = v."+consts.QVidChanID).
+		LeftJoin(consts.DBDownloads+" d ON d."+consts.QDLVidID+" = v."+consts.QVidID).
+		Where(consts.DBVideoSearch+" MATCH ?", match).
+		OrderBy("v."+consts.QVidUploadDate+" DESC", "v."+consts.QVidID+" DESC")
+	if chanID != 0 {
+		query = query.Where(squirrel.Eq{"v." + consts.QVidChanID: chanID})
+	}
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	rows, err := query.RunWith(vs.DB).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search videos: %w", err)
+	}
+	defer rows.Close()
+
+	var videos []*models.Video
+	for rows.Next() {
+		var (
+			v                    models.Video
+			chanName             string
+			title, vPath, status sql.NullString
+			uploadDate           sql.NullTime
+		)
+		if err := rows.Scan(&v.ID, &v.ChannelID, &chanName, &v.URL, &title, &vPath, &status, &uploadDate); err != nil {
+			return nil, fmt.Errorf("failed to scan video: %w", err)
+		}
+		v.Channel = &models.Channel{ID: v.ChannelID, Name: chanName}
+		v.Title = title.String
+		v.VideoPath = vPath.String
+		v.DownloadStatus.Status = consts.DownloadStatus(status.String)
+		v.UploadDate = uploadDate.Time
+		videos = append(videos, &v)
+	}
+	return videos, rows.Err()
+}
+
+// searchMatch builds a full-text query requiring every word of the search as a prefix.
+//
+// Words are quoted, so characters with meaning in the full-text query syntax are searched for literally.
+func searchMatch(q string) string {
+	var terms []string
+	for _, w := range strings.Fields(strings.ReplaceAll(q, `"`, " ")) {
+		terms = append(terms, `"`+w+`*"`)
+	}
+	return strings.Join(terms, " ")
+}
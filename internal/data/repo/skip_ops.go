@@ -0,0 +1,125 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type SkipStore struct {
+	DB *sql.DB
+}
+
+// GetSkipStore returns a skip store instance with injected database.
+func GetSkipStore(db *sql.DB) *SkipStore {
+	return &SkipStore{
+		DB: db,
+	}
+}
+
+// GetDB returns the database.
+func (ss *SkipStore) GetDB() *sql.DB {
+	return ss.DB
+}
+
+// RecordSkip records a skipped video candidate, replacing any previous reason for the URL.
+func (ss *SkipStore) RecordSkip(sv *models.SkippedVideo) error {
+	return recordSkip(ss.DB, sv)
+}
+
+// RecordSkips records several skipped video candidates in a single transaction.
+func (ss *SkipStore) RecordSkips(svs []*models.SkippedVideo) error {
+	if len(svs) == 0 {
+		return nil
+	}
+
+	tx, err := ss.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	for _, sv := range svs {
+		if err := recordSkip(tx, sv); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				logging.E(0, "Error rolling back skipped videos: %v", rollbackErr)
+			}
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit skipped videos: %w", err)
+	}
+	return nil
+}
+
+// recordSkip upserts a skipped video candidate.
+func recordSkip(runner squirrel.BaseRunner, sv *models.SkippedVideo) error {
+	const (
+		querySuffix = "ON CONFLICT (channel_id, url) DO UPDATE SET reason = EXCLUDED.reason, " +
+			"detail = EXCLUDED.detail, created_at = EXCLUDED.created_at"
+	)
+
+	if sv.CreatedAt.IsZero() {
+		sv.CreatedAt = time.Now()
+	}
+
+	query := squirrel.
+		Insert(consts.DBSkipped).
+		Columns(consts.QSkipChanID, consts.QSkipURL, consts.QSkipReason, consts.QSkipDetail, consts.QSkipCreatedAt).
+		Values(sv.ChannelID, sv.URL, sv.Reason, sv.Detail, sv.CreatedAt).
+		Suffix(querySuffix).
+		RunWith(runner)
+
+	if _, err := query.Exec(); err != nil {
+		return fmt.Errorf("failed to record skipped video %q: %w", sv.URL, err)
+	}
+	return nil
+}
+
+// FetchSkipped returns the skipped video candidates for a channel, newest first.
+func (ss *SkipStore) FetchSkipped(channelID int64) ([]*models.SkippedVideo, error) {
+	rows, err := squirrel.
+		Select(consts.QSkipID, consts.QSkipChanID, consts.QSkipURL, consts.QSkipReason, consts.QSkipDetail, consts.QSkipCreatedAt).
+		From(consts.DBSkipped).
+		Where(squirrel.Eq{consts.QSkipChanID: channelID}).
+		OrderBy(consts.QSkipCreatedAt + " DESC").
+		RunWith(ss.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query skipped videos: %w", err)
+	}
+	defer rows.Close()
+
+	var skipped []*models.SkippedVideo
+	for rows.Next() {
+		var (
+			sv     models.SkippedVideo
+			detail sql.NullString
+		)
+		if err := rows.Scan(&sv.ID, &sv.ChannelID, &sv.URL, &sv.Reason, &detail, &sv.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan skipped video: %w", err)
+		}
+		sv.Detail = detail.String
+		skipped = append(skipped, &sv)
+	}
+	return skipped, rows.Err()
+}
+
+// PruneSkipped deletes skipped video records older than the given time.
+func (ss *SkipStore) PruneSkipped(before time.Time) (int64, error) {
+	result, err := squirrel.
+		Delete(consts.DBSkipped).
+		Where(squirrel.Lt{consts.QSkipCreatedAt: before}).
+		RunWith(ss.DB).
+		Exec()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune skipped videos: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// GetSMTPConfig returns the mail server settings, empty if never set.
+func (cs *ChannelStore) GetSMTPConfig() (*models.SMTPConfig, error) {
+	var (
+		c          models.SMTPConfig
+		configJSON []byte
+	)
+	err := squirrel.
+		Select(consts.QSMTPConfig).
+		From(consts.DBSMTP).
+		Where(squirrel.Eq{consts.QSMTPID: 1}).
+		RunWith(cs.DB).
+		QueryRow().
+		Scan(&configJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &c, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get SMTP settings: %w", err)
+	}
+
+	if len(configJSON) > 0 {
+		if err := json.Unmarshal(configJSON, &c); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal SMTP settings: %w", err)
+		}
+	}
+	return &c, nil
+}
+
+// UpdateSMTPConfig applies the update function to the mail server settings.
+func (cs *ChannelStore) UpdateSMTPConfig(updateFn func(*models.SMTPConfig) error) error {
+	c, err := cs.GetSMTPConfig()
+	if err != nil {
+		return err
+	}
+	if err := updateFn(c); err != nil {
+		return fmt.Errorf("failed to update SMTP settings: %w", err)
+	}
+
+	configJSON, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SMTP settings: %w", err)
+	}
+	_, err = squirrel.
+		Insert(consts.DBSMTP).
+		Columns(consts.QSMTPID, consts.QSMTPConfig, consts.QSMTPUpdatedAt).
+		Values(1, string(configJSON), time.Now()).
+		Suffix("ON CONFLICT (id) DO UPDATE SET config = EXCLUDED.config, updated_at = EXCLUDED.updated_at").
+		RunWith(cs.DB).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to save SMTP settings: %w", err)
+	}
+	return nil
+}
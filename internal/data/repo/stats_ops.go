@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+const statsDayFormat = "2006-01-02"
+
+type StatsStore struct {
+	DB *sql.DB
+}
+
+// GetStatsStore returns a stats store instance with injected database.
+func GetStatsStore(db *sql.DB) *StatsStore {
+	return &StatsStore{
+		DB: db,
+	}
+}
+
+// GetDB returns the database.
+func (ss *StatsStore) GetDB() *sql.DB {
+	return ss.DB
+}
+
+// RecordDownload adds a download result to the channel's totals for the day it finished.
+//
+// Bytes and duration are only counted for successful downloads.
+func (ss *StatsStore) RecordDownload(channelID int64, at time.Time, success bool, bytes int64, dur time.Duration) error {
+	var downloads, failures int
+	if success {
+		downloads = 1
+	} else {
+		failures, bytes, dur = 1, 0, 0
+	}
+
+	const querySuffix = "ON CONFLICT (channel_id, day) DO UPDATE SET " +
+		"downloads = downloads + EXCLUDED.downloads, " +
+		"failures = failures + EXCLUDED.failures, " +
+		"bytes = bytes + EXCLUDED.bytes, " +
+		"duration_ms = duration_ms + EXCLUDED.duration_ms"
+
+	_, err := squirrel.
+		Insert(consts.DBStats).
+		Columns(consts.QStatChanID, consts.QStatDay, consts.QStatDownloads, consts.QStatFailures, consts.QStatBytes, consts.QStatDuration).
+		Values(channelID, at.Local().Format(statsDayFormat), downloads, failures, bytes, dur.Milliseconds()).
+		Suffix(querySuffix).
+		RunWith(ss.DB).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to record download stats for channel with ID %d: %w", channelID, err)
+	}
+	return nil
+}
+
+// FetchDailyStats returns each channel's totals per day since the given day, newest day first.
+//
+// A channel ID of 0 returns every channel.
+func (ss *StatsStore) FetchDailyStats(channelID int64, since time.Time) ([]*models.DownloadStats, error) {
+	query := ss.statsQuery(channelID, since,
+		"s."+consts.QStatDay,
+		"s."+consts.QStatDownloads,
+		"s."+consts.QStatFailures,
+		"s."+consts.QStatBytes,
+		"s."+consts.QStatDuration,
+	).OrderBy("s."+consts.QStatDay+" DESC", "c."+consts.QChanName)
+	stats, err := scanStats(query, true)
+	if err != nil {
+		return nil, err
+	}
+	return stats, ss.addFailReasons(stats, channelID, since, true)
+}
+
+// FetchStatsTotals returns each channel's totals summed since the given day, ordered by channel name.
+//
+// A channel ID of 0 returns every channel.
+func (ss *StatsStore) FetchStatsTotals(channelID int64, since time.Time) ([]*models.DownloadStats, error) {
+	query := ss.statsQuery(channelID, since,
+		"SUM(s."+consts.QStatDownloads+")",
+		"SUM(s."+consts.QStatFailures+")",
+		"SUM(s."+consts.QStatBytes+")",
+		"SUM(s."+consts.QStatDuration+")",
+	).GroupBy("s." + consts.QStatChanID).OrderBy("c." + consts.QChanName)
+	stats, err := scanStats(query, false)
+	if err != nil {
+		return nil, err
+	}
+	return stats, ss.addFailReasons(stats, channelID, since, false)
+}
+
+// addFailReasons counts the downloads still failed since the given day by fail reason, adding them to the matching stats.
+//
+// Downloads are counted by the day of their last failure if daily is set.
+func (ss *StatsStore) addFailReasons(stats []*models.DownloadStats, channelID int64, since time.Time, daily bool) error {
+	day := "SUBSTR(d." + consts.QDLUpdatedAt + ", 1, 10)"
+	query := squirrel.
+		Select("v."+consts.QVidChanID, day, "d."+consts.QDLFailReason, "COUNT(*)").
+		From(consts.DBDownloads+" d").
+		Join(consts.DBVideos+" v ON v."+consts.QVidID+" = d."+consts.QDLVidID).
+		Where(squirrel.Eq{"d." + consts.QDLStatus: consts.DLStatusFailed}).
+		Where(squirrel.GtOrEq{day: since.Local().Format(statsDayFormat)}).
+		GroupBy("v."+consts.QVidChanID, day, "d."+consts.QDLFailReason).
+		RunWith(ss.DB)
+	if channelID != 0 {
+		query = query.Where(squirrel.Eq{"v." + consts.QVidChanID: channelID})
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		return fmt.Errorf("failed to query download fail reasons: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			chanID int64
+			d      string
+			reason consts.FailReason
+			n      int
+		)
+		if err := rows.Scan(&chanID, &d, &reason, &n); err != nil {
+			return fmt.Errorf("failed to scan download fail reasons: %w", err)
+		}
+		if reason == "" {
+			reason = consts.FailUnknown
+		}
+		for _, s := range stats {
+			if s.ChannelID != chanID || (daily && s.Day != d) {
+				continue
+			}
+			if s.FailReasons == nil {
+				s.FailReasons = make(map[consts.FailReason]int)
+			}
+			s.FailReasons[reason] += n
+			break
+		}
+	}
+	return rows.Err()
+}
+
+// statsQuery selects the channel ID and name followed by the columns, joined to channels and filtered by channel and day.
+func (ss *StatsStore) statsQuery(channelID int64, since time.Time, cols ...string) squirrel.SelectBuilder {
+	query := squirrel.
+		Select(append([]string{"s." + consts.QStatChanID, "c." + consts.QChanName}, cols...)...).
+		From(consts.DBStats + " s").
+		Join(consts.DBChannels + " c ON c." + consts.QChanID + " = s." + consts.QStatChanID).
+		Where(squirrel.GtOrEq{"s." + consts.QStatDay: since.Local().Format(statsDayFormat)}).
+		RunWith(ss.DB)
+	if channelID != 0 {
+		query = query.Where(squirrel.Eq{"s." + consts.QStatChanID: channelID})
+	}
+	return query
+}
+
+// scanStats runs a stats query, scanning the day column if daily is set.
+func scanStats(query squirrel.SelectBuilder, daily bool) ([]*models.DownloadStats, error) {
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query download stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*models.DownloadStats
+	for rows.Next() {
+		var (
+			s     models.DownloadStats
+			durMS int64
+		)
+		dest := []any{&s.ChannelID, &s.ChannelName}
+		if daily {
+			dest = append(dest, &s.Day)
+		}
+		if err := rows.Scan(append(dest, &s.Downloads, &s.Failures, &s.Bytes, &durMS)...); err != nil {
+			return nil, fmt.Errorf("failed to scan download stats: %w", err)
+		}
+		s.Duration = time.Duration(durMS) * time.Millisecond
+		stats = append(stats, &s)
+	}
+	return stats, rows.Err()
+}
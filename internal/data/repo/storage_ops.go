@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+type StorageStore struct {
+	DB *sql.DB
+}
+
+// GetStorageStore returns a storage store instance with injected database.
+func GetStorageStore(db *sql.DB) *StorageStore {
+	return &StorageStore{
+		DB: db,
+	}
+}
+
+// GetDB returns the database.
+func (ss *StorageStore) GetDB() *sql.DB {
+	return ss.DB
+}
+
+// RefreshChannelStorage recalculates a channel's disk usage from its stored file paths and caches the result.
+//
+// Only the recorded files are checked, so no directory walk is needed.
+func (ss *StorageStore) RefreshChannelStorage(channelID int64) (*models.ChannelStorage, error) {
+	rows, err := squirrel.
+		Select(consts.QVidVideoPath, consts.QVidJSONPath).
+		From(consts.DBVideos).
+		Where(squirrel.Eq{consts.QVidChanID: channelID}).
+		RunWith(ss.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query video paths for channel with ID %d: %w", channelID, err)
+	}
+	defer rows.Close()
+
+	usage := &models.ChannelStorage{
+		ChannelID: channelID,
+		UpdatedAt: time.Now(),
+	}
+
+	for rows.Next() {
+		var vPath, jPath sql.NullString
+		if err := rows.Scan(&vPath, &jPath); err != nil {
+			return nil, fmt.Errorf("failed to scan video paths: %w", err)
+		}
+		if size, ok := fileSize(vPath.String); ok {
+			usage.VideoBytes += size
+			usage.Files++
+		}
+		if size, ok := fileSize(jPath.String); ok {
+			usage.JSONBytes += size
+			usage.Files++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	const (
+		querySuffix = "ON CONFLICT (channel_id) DO UPDATE SET video_bytes = EXCLUDED.video_bytes, " +
+			"json_bytes = EXCLUDED.json_bytes, file_count = EXCLUDED.file_count, updated_at = EXCLUDED.updated_at"
+	)
+
+	query := squirrel.
+		Insert(consts.DBStorage).
+		Columns(consts.QStorageChanID, consts.QStorageVideoBytes, consts.QStorageJSONBytes, consts.QStorageFileCount, consts.QStorageUpdatedAt).
+		Values(usage.ChannelID, usage.VideoBytes, usage.JSONBytes, usage.Files, usage.UpdatedAt).
+		Suffix(querySuffix).
+		RunWith(ss.DB)
+
+	if _, err := query.Exec(); err != nil {
+		return nil, fmt.Errorf("failed to cache storage usage for channel with ID %d: %w", channelID, err)
+	}
+	logging.D(1, "Refreshed storage usage for channel with ID %d: %d files", channelID, usage.Files)
+	return usage, nil
+}
+
+// FetchAllChannelStorage returns the cached disk usage for all channels.
+func (ss *StorageStore) FetchAllChannelStorage() ([]*models.ChannelStorage, error) {
+	rows, err := squirrel.
+		Select(consts.QStorageChanID, consts.QStorageVideoBytes, consts.QStorageJSONBytes, consts.QStorageFileCount, consts.QStorageUpdatedAt).
+		From(consts.DBStorage).
+		OrderBy(consts.QStorageChanID).
+		RunWith(ss.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query storage usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usages []*models.ChannelStorage
+	for rows.Next() {
+		var u models.ChannelStorage
+		if err := rows.Scan(&u.ChannelID, &u.VideoBytes, &u.JSONBytes, &u.Files, &u.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan storage usage: %w", err)
+		}
+		usages = append(usages, &u)
+	}
+	return usages, rows.Err()
+}
+
+// fileSize returns the size of a file, and false if it is blank or missing.
+func fileSize(path string) (int64, bool) {
+	if path == "" {
+		return 0, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return 0, false
+	}
+	return info.Size(), true
+}
@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// AddChannelTags adds tags to a channel, tags it already has are left alone.
+func (cs *ChannelStore) AddChannelTags(channelID int64, tags []string) error {
+	if !cs.channelExistsID(channelID) {
+		return fmt.Errorf("channel with ID %d does not exist", channelID)
+	}
+
+	now := time.Now()
+	for _, tag := range tags {
+		query := squirrel.
+			Insert(consts.DBChannelTags).
+			Columns(consts.QTagChanID, consts.QTagName, consts.QTagCreatedAt).
+			Values(channelID, tag, now).
+			Suffix("ON CONFLICT (channel_id, tag) DO NOTHING").
+			RunWith(cs.DB)
+
+		if _, err := query.Exec(); err != nil {
+			return fmt.Errorf("failed to add tag %q to channel with ID %d: %w", tag, channelID, err)
+		}
+	}
+	return nil
+}
+
+// RemoveChannelTags removes tags from a channel, returning how many it had.
+func (cs *ChannelStore) RemoveChannelTags(channelID int64, tags []string) (int64, error) {
+	result, err := squirrel.
+		Delete(consts.DBChannelTags).
+		Where(squirrel.Eq{consts.QTagChanID: channelID, consts.QTagName: tags}).
+		RunWith(cs.DB).
+		Exec()
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove tags from channel with ID %d: %w", channelID, err)
+	}
+	return result.RowsAffected()
+}
+
+// GetChannelTags returns a channel's tags in alphabetical order.
+func (cs *ChannelStore) GetChannelTags(channelID int64) ([]string, error) {
+	rows, err := squirrel.
+		Select(consts.QTagName).
+		From(consts.DBChannelTags).
+		Where(squirrel.Eq{consts.QTagChanID: channelID}).
+		OrderBy(consts.QTagName).
+		RunWith(cs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags for channel with ID %d: %w", channelID, err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// FetchTagCounts returns every tag in use and how many channels have it.
+func (cs *ChannelStore) FetchTagCounts() (map[string]int, error) {
+	rows, err := squirrel.
+		Select(consts.QTagName, "COUNT(*)").
+		From(consts.DBChannelTags).
+		GroupBy(consts.QTagName).
+		RunWith(cs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			tag string
+			n   int
+		)
+		if err := rows.Scan(&tag, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		counts[tag] = n
+	}
+	return counts, rows.Err()
+}
+
+// FetchTaggedChannels returns the channels with a tag, ordered by name.
+func (cs *ChannelStore) FetchTaggedChannels(tag string) ([]*models.Channel, error) {
+	rows, err := squirrel.
+		Select(consts.QTagChanID).
+		From(consts.DBChannelTags).
+		Where(squirrel.Eq{consts.QTagName: tag}).
+		RunWith(cs.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query channels tagged %q: %w", tag, err)
+	}
+	defer rows.Close()
+
+	ids := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan channel ID: %w", err)
+		}
+		ids[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	chans, err, hasRows := cs.FetchAllChannels()
+	if !hasRows || err != nil {
+		return nil, err
+	}
+
+	tagged := make([]*models.Channel, 0, len(ids))
+	for _, c := range chans {
+		if ids[c.ID] {
+			tagged = append(tagged, c)
+		}
+	}
+	return tagged, nil
+}
@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// RecordStage records when a video reached a pipeline stage.
+//
+// Reaching the queued stage starts a new run, clearing the stages of any previous run.
+func (vs VideoStore) RecordStage(videoID int64, stage consts.PipelineStage, at time.Time) error {
+	tx, err := vs.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if stage == consts.StageQueued {
+		if _, err := squirrel.
+			Delete(consts.DBStages).
+			Where(squirrel.Eq{consts.QStageVidID: videoID}).
+			RunWith(tx).
+			Exec(); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				logging.E(0, "Error rolling back stages for video with ID %d: %v", videoID, rollbackErr)
+			}
+			return fmt.Errorf("failed to clear previous stages for video with ID %d: %w", videoID, err)
+		}
+	}
+
+	if _, err := squirrel.
+		Insert(consts.DBStages).
+		Columns(consts.QStageVidID, consts.QStageName, consts.QStageAt).
+		Values(videoID, stage, at).
+		Suffix("ON CONFLICT(" + consts.QStageVidID + ", " + consts.QStageName + ") DO UPDATE SET " + consts.QStageAt + " = excluded." + consts.QStageAt).
+		RunWith(tx).
+		Exec(); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logging.E(0, "Error rolling back stages for video with ID %d: %v", videoID, rollbackErr)
+		}
+		return fmt.Errorf("failed to record stage %q for video with ID %d: %w", stage, videoID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit stage %q for video with ID %d: %w", stage, videoID, err)
+	}
+	return nil
+}
+
+// FetchVideoTiming returns the pipeline stages reached by a video in its latest run.
+func (vs VideoStore) FetchVideoTiming(videoID int64) (*models.VideoTiming, error) {
+	timings, err := vs.fetchTimings(squirrel.Eq{consts.QStageVidID: videoID})
+	if err != nil {
+		return nil, err
+	}
+	if len(timings) == 0 {
+		return &models.VideoTiming{VideoID: videoID, Stages: map[consts.PipelineStage]time.Time{}}, nil
+	}
+	return timings[0], nil
+}
+
+// FetchChannelTimings returns the pipeline stage timings of every video in a channel, or all channels if the ID is 0.
+func (vs VideoStore) FetchChannelTimings(chanID int64) ([]*models.VideoTiming, error) {
+	if chanID == 0 {
+		return vs.fetchTimings(nil)
+	}
+
+	sub := squirrel.
+		Select(consts.QVidID).
+		From(consts.DBVideos).
+		Where(squirrel.Eq{consts.QVidChanID: chanID})
+	subSQL, subArgs, err := sub.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return vs.fetchTimings(squirrel.Expr(consts.QStageVidID+" IN ("+subSQL+")", subArgs...))
+}
+
+// fetchTimings groups stage rows matching the condition by video.
+func (vs VideoStore) fetchTimings(where squirrel.Sqlizer) ([]*models.VideoTiming, error) {
+	query := squirrel.
+		Select(consts.QStageVidID, consts.QStageName, consts.QStageAt).
+		From(consts.DBStages).
+		OrderBy(consts.QStageVidID)
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	rows, err := query.RunWith(vs.DB).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query video stages: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		timings []*models.VideoTiming
+		current *models.VideoTiming
+	)
+	for rows.Next() {
+		var (
+			videoID int64
+			stage   string
+			at      time.Time
+		)
+		if err := rows.Scan(&videoID, &stage, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan video stage: %w", err)
+		}
+		if current == nil || current.VideoID != videoID {
+			current = &models.VideoTiming{VideoID: videoID, Stages: make(map[consts.PipelineStage]time.Time)}
+			timings = append(timings, current)
+		}
+		current.Stages[consts.PipelineStage(stage)] = at
+	}
+	return timings, rows.Err()
+}
@@ -0,0 +1,297 @@
+package repo
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+
+	"github.com/Masterminds/squirrel"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserStore struct {
+	DB *sql.DB
+}
+
+// GetUserStore returns a user store instance with injected database.
+func GetUserStore(db *sql.DB) *UserStore {
+	return &UserStore{
+		DB: db,
+	}
+}
+
+// GetDB returns the database.
+func (us *UserStore) GetDB() *sql.DB {
+	return us.DB
+}
+
+// AddUser creates a user with a bcrypt hash of the password.
+func (us *UserStore) AddUser(username, password, role string) (int64, error) {
+	switch {
+	case username == "":
+		return 0, errors.New("username is blank")
+	case password == "":
+		return 0, errors.New("password is blank")
+	case role != consts.RoleAdmin && role != consts.RoleUser:
+		return 0, fmt.Errorf("unsupported role %q, expected %s or %s", role, consts.RoleAdmin, consts.RoleUser)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := squirrel.
+		Insert(consts.DBUsers).
+		Columns(consts.QUserName, consts.QUserPassword, consts.QUserRole).
+		Values(username, string(hash), role).
+		RunWith(us.DB).
+		Exec()
+	if err != nil {
+		return 0, fmt.Errorf("failed to add user %q: %w", username, err)
+	}
+	return result.LastInsertId()
+}
+
+// DeleteUser removes a user along with their sessions, leaving their channels without an owner.
+func (us *UserStore) DeleteUser(username string) error {
+	result, err := squirrel.
+		Delete(consts.DBUsers).
+		Where(squirrel.Eq{consts.QUserName: username}).
+		RunWith(us.DB).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete user %q: %w", username, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("no user named %q", username)
+	}
+	return nil
+}
+
+// FetchUsers returns every user, oldest first.
+func (us *UserStore) FetchUsers() ([]*models.User, error) {
+	rows, err := squirrel.
+		Select(consts.QUserID, consts.QUserName, consts.QUserRole, consts.QUserCreatedAt).
+		From(consts.DBUsers).
+		OrderBy(consts.QUserID).
+		RunWith(us.DB).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		u := &models.User{}
+		if err := rows.Scan(&u.ID, &u.Username, &u.Role, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// HasUsers reports whether any users exist, and so whether the HTTP endpoints need a login.
+func (us *UserStore) HasUsers() (bool, error) {
+	var exists bool
+	if err := us.DB.QueryRow("SELECT EXISTS (SELECT 1 FROM " + consts.DBUsers + ")").Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check for users: %w", err)
+	}
+	return exists, nil
+}
+
+// Authenticate returns the user if the password matches, or nil for an unknown username or wrong password.
+func (us *UserStore) Authenticate(username, password string) (*models.User, error) {
+	var (
+		u    models.User
+		hash string
+	)
+	err := squirrel.
+		Select(consts.QUserID, consts.QUserName, consts.QUserRole, consts.QUserCreatedAt, consts.QUserPassword).
+		From(consts.DBUsers).
+		Where(squirrel.Eq{consts.QUserName: username}).
+		RunWith(us.DB).
+		QueryRow().
+		Scan(&u.ID, &u.Username, &u.Role, &u.CreatedAt, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to look up user %q: %w", username, err)
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return nil, nil
+	}
+	return &u, nil
+}
+
+// CreateSession starts a login session for the user, returning its token.
+//
+// Only a hash of the token is stored. Expired sessions are pruned.
+func (us *UserStore) CreateSession(userID int64, ttl time.Duration) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	if _, err := squirrel.
+		Delete(consts.DBUserSessions).
+		Where(squirrel.Lt{consts.QSessExpiresAt: time.Now()}).
+		RunWith(us.DB).
+		Exec(); err != nil {
+		return "", fmt.Errorf("failed to prune expired sessions: %w", err)
+	}
+
+	if _, err := squirrel.
+		Insert(consts.DBUserSessions).
+		Columns(consts.QSessToken, consts.QSessUserID, consts.QSessExpiresAt).
+		Values(hashToken(token), userID, time.Now().Add(ttl)).
+		RunWith(us.DB).
+		Exec(); err != nil {
+		return "", fmt.Errorf("failed to create session: %w", err)
+	}
+	return token, nil
+}
+
+// SessionUser returns the user logged in with the session token, or nil if the session is unknown or expired.
+func (us *UserStore) SessionUser(token string) (*models.User, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	var u models.User
+	err := squirrel.
+		Select("u."+consts.QUserID, "u."+consts.QUserName, "u."+consts.QUserRole, "u."+consts.QUserCreatedAt).
+		From(consts.DBUserSessions+" s").
+		Join(consts.DBUsers+" u ON u."+consts.QUserID+" = s."+consts.QSessUserID).
+		Where(squirrel.Eq{"s." + consts.QSessToken: hashToken(token)}).
+		Where(squirrel.Gt{"s." + consts.QSessExpiresAt: time.Now()}).
+		RunWith(us.DB).
+		QueryRow().
+		Scan(&u.ID, &u.Username, &u.Role, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to look up session: %w", err)
+	}
+	return &u, nil
+}
+
+// DeleteSession ends a login session.
+func (us *UserStore) DeleteSession(token string) error {
+	if _, err := squirrel.
+		Delete(consts.DBUserSessions).
+		Where(squirrel.Eq{consts.QSessToken: hashToken(token)}).
+		RunWith(us.DB).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// SetChannelOwner gives a channel to a user, or clears its owner if username is empty.
+func (us *UserStore) SetChannelOwner(channelID int64, username string) error {
+	var owner any
+	if username != "" {
+		var id int64
+		err := squirrel.
+			Select(consts.QUserID).
+			From(consts.DBUsers).
+			Where(squirrel.Eq{consts.QUserName: username}).
+			RunWith(us.DB).
+			QueryRow().
+			Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no user named %q", username)
+		} else if err != nil {
+			return err
+		}
+		owner = id
+	}
+
+	result, err := squirrel.
+		Update(consts.DBChannels).
+		Set(consts.QChanOwnerID, owner).
+		Where(squirrel.Eq{consts.QChanID: channelID}).
+		RunWith(us.DB).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to set owner of channel with ID %d: %w", channelID, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("channel with ID %d does not exist", channelID)
+	}
+	return nil
+}
+
+// FetchVisibleChannels returns the channels the user can see: all of them for admins and before any users exist,
+// otherwise those they own.
+func (us *UserStore) FetchVisibleChannels(u *models.User) ([]*models.OwnedChannel, error) {
+	query := squirrel.
+		Select("c."+consts.QChanID, "c."+consts.QChanName, "c."+consts.QChanURL, "COALESCE(u."+consts.QUserName+", '')").
+		From(consts.DBChannels + " c").
+		LeftJoin(consts.DBUsers + " u ON u." + consts.QUserID + " = c." + consts.QChanOwnerID).
+		OrderBy("c." + consts.QChanName)
+	if !u.SeesAll() {
+		query = query.Where(squirrel.Eq{"c." + consts.QChanOwnerID: u.ID})
+	}
+
+	rows, err := query.RunWith(us.DB).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch channels for user %q: %w", u.Username, err)
+	}
+	defer rows.Close()
+
+	var chans []*models.OwnedChannel
+	for rows.Next() {
+		c := &models.OwnedChannel{}
+		if err := rows.Scan(&c.ID, &c.Name, &c.URL, &c.Owner); err != nil {
+			return nil, err
+		}
+		chans = append(chans, c)
+	}
+	return chans, rows.Err()
+}
+
+// CanAccessChannel reports whether the user can see the channel.
+func (us *UserStore) CanAccessChannel(u *models.User, channelID int64) (bool, error) {
+	if u.SeesAll() {
+		return true, nil
+	}
+	var ok bool
+	query := "SELECT EXISTS (SELECT 1 FROM " + consts.DBChannels + " WHERE " + consts.QChanID + " = ? AND " + consts.QChanOwnerID + " = ?)"
+	if err := us.DB.QueryRow(query, channelID, u.ID).Scan(&ok); err != nil {
+		return false, fmt.Errorf("failed to check access to channel with ID %d: %w", channelID, err)
+	}
+	return ok, nil
+}
+
+// CanAccessVideo reports whether the user can see the channel the video belongs to.
+func (us *UserStore) CanAccessVideo(u *models.User, videoID int64) (bool, error) {
+	if u.SeesAll() {
+		return true, nil
+	}
+	var ok bool
+	query := "SELECT EXISTS (SELECT 1 FROM " + consts.DBVideos + " v JOIN " + consts.DBChannels + " c ON c." + consts.QChanID +
+		" = v." + consts.QVidChanID + " WHERE v." + consts.QVidID + " = ? AND c." + consts.QChanOwnerID + " = ?)"
+	if err := us.DB.QueryRow(query, videoID, u.ID).Scan(&ok); err != nil {
+		return false, fmt.Errorf("failed to check access to video with ID %d: %w", videoID, err)
+	}
+	return ok, nil
+}
+
+// hashToken returns the stored form of a session token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
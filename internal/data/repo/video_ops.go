@@ -2,6 +2,7 @@ package repo
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -133,6 +134,11 @@ func (vs VideoStore) AddVideo(v *models.Video) (int64, error) {
 		return 0, errors.New("must enter a video directory where downloads will be stored")
 	}
 
+	// Already looked up, such as by the crawl pipeline
+	if v.ID != 0 {
+		return v.ID, vs.UpdateVideo(v)
+	}
+
 	if id, exists := vs.videoExists(v); exists {
 		logging.D(1, "Video %q already exists in the database", v.URL)
 		if err := vs.UpdateVideo(v); err != nil { // Attempt an update if add is not appropriate
@@ -310,6 +316,386 @@ func (vs VideoStore) DeleteVideo(key, val string, chanID int64) error {
 	return nil
 }
 
+// GetVideoID returns the ID of a video in a channel by its URL.
+func (vs VideoStore) GetVideoID(chanID int64, url string) (int64, error) {
+	var id int64
+	query := squirrel.
+		Select(consts.QVidID).
+		From(consts.DBVideos).
+		Where(squirrel.And{
+			squirrel.Eq{consts.QVidURL: url},
+			squirrel.Eq{consts.QVidChanID: chanID},
+		}).
+		RunWith(vs.DB)
+
+	if err := query.QueryRow().Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("no video with URL %q in channel with ID %d", url, chanID)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetVideoIDs returns the IDs of the videos of a channel with any of the URLs, by URL.
+//
+// URLs are looked up in chunks, to stay within SQLite's query variable limit.
+func (vs VideoStore) GetVideoIDs(chanID int64, urls []string) (map[string]int64, error) {
+	const chunkSize = 500
+
+	ids := make(map[string]int64, len(urls))
+	for start := 0; start < len(urls); start += chunkSize {
+		rows, err := squirrel.
+			Select(consts.QVidID, consts.QVidURL).
+			From(consts.DBVideos).
+			Where(squirrel.And{
+				squirrel.Eq{consts.QVidChanID: chanID},
+				squirrel.Eq{consts.QVidURL: urls[start:min(start+chunkSize, len(urls))]},
+			}).
+			RunWith(vs.DB).
+			Query()
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var (
+				id  int64
+				url string
+			)
+			if err := rows.Scan(&id, &url); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			ids[url] = id
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// FetchVideosWithPaths returns all videos which have a stored video or JSON path.
+func (vs VideoStore) FetchVideosWithPaths() ([]*models.Video, error) {
+	query := squirrel.
+		Select(
+			consts.QVidID,
+			consts.QVidChanID,
+			consts.QVidURL,
+			consts.QVidVideoDir,
+			consts.QVidVideoPath,
+			consts.QVidJSONDir,
+			consts.QVidJSONPath,
+		).
+		From(consts.DBVideos).
+		Where(squirrel.Or{
+			squirrel.NotEq{consts.QVidVideoPath: ""},
+			squirrel.NotEq{consts.QVidJSONPath: ""},
+		}).
+		RunWith(vs.DB)
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query video paths: %w", err)
+	}
+	defer rows.Close()
+
+	var videos []*models.Video
+	for rows.Next() {
+		var (
+			v                        models.Video
+			vDir, vPath, jDir, jPath sql.NullString
+		)
+		if err := rows.Scan(&v.ID, &v.ChannelID, &v.URL, &vDir, &vPath, &jDir, &jPath); err != nil {
+			return nil, fmt.Errorf("failed to scan video paths: %w", err)
+		}
+		v.VideoDir = vDir.String
+		v.VideoPath = vPath.String
+		v.JSONDir = jDir.String
+		v.JSONPath = jPath.String
+		videos = append(videos, &v)
+	}
+	return videos, rows.Err()
+}
+
+// FetchOldestDownloads returns a channel's downloaded videos which still have a video file, oldest first.
+func (vs VideoStore) FetchOldestDownloads(chanID int64, limit int) ([]*models.Video, error) {
+	query := squirrel.
+		Select(
+			consts.QVidID,
+			consts.QVidURL,
+			consts.QVidTitle,
+			consts.QVidVideoPath,
+			consts.QVidJSONPath,
+		).
+		From(consts.DBVideos).
+		Where(squirrel.And{
+			squirrel.Eq{consts.QVidChanID: chanID},
+			squirrel.NotEq{consts.QVidVideoPath: ""},
+		}).
+		OrderBy(consts.QVidCreatedAt, consts.QVidID).
+		Limit(uint64(limit)).
+		RunWith(vs.DB)
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query downloads for channel with ID %d: %w", chanID, err)
+	}
+	defer rows.Close()
+
+	var videos []*models.Video
+	for rows.Next() {
+		var (
+			v                   models.Video
+			title, vPath, jPath sql.NullString
+		)
+		if err := rows.Scan(&v.ID, &v.URL, &title, &vPath, &jPath); err != nil {
+			return nil, fmt.Errorf("failed to scan download: %w", err)
+		}
+		v.ChannelID = chanID
+		v.Title = title.String
+		v.VideoPath = vPath.String
+		v.JSONPath = jPath.String
+		videos = append(videos, &v)
+	}
+	return videos, rows.Err()
+}
+
+// FetchExpiredDownloads returns a channel's downloaded videos past its retention policy, newest first.
+//
+// Videos beyond the newest keepLast, or downloaded before the cutoff, are expired. Zero values disable either rule.
+func (vs VideoStore) FetchExpiredDownloads(chanID int64, keepLast int, before time.Time) ([]*models.Video, error) {
+	query := squirrel.
+		Select(
+			consts.QVidID,
+			consts.QVidURL,
+			consts.QVidTitle,
+			consts.QVidVideoPath,
+			consts.QVidJSONPath,
+			consts.QVidCreatedAt,
+		).
+		From(consts.DBVideos).
+		Where(squirrel.And{
+			squirrel.Eq{consts.QVidChanID: chanID},
+			squirrel.NotEq{consts.QVidVideoPath: ""},
+		}).
+		OrderBy(consts.QVidCreatedAt+" DESC", consts.QVidID+" DESC").
+		RunWith(vs.DB)
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query downloads for channel with ID %d: %w", chanID, err)
+	}
+	defer rows.Close()
+
+	var (
+		videos []*models.Video
+		n      int
+	)
+	for rows.Next() {
+		var (
+			v                   models.Video
+			title, vPath, jPath sql.NullString
+		)
+		if err := rows.Scan(&v.ID, &v.URL, &title, &vPath, &jPath, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan download: %w", err)
+		}
+		n++
+		if (keepLast > 0 && n > keepLast) || (!before.IsZero() && v.CreatedAt.Before(before)) {
+			v.ChannelID = chanID
+			v.Title = title.String
+			v.VideoPath = vPath.String
+			v.JSONPath = jPath.String
+			videos = append(videos, &v)
+		}
+	}
+	return videos, rows.Err()
+}
+
+// SetYTDLPVersion records the yt-dlp version used to download a video.
+func (vs VideoStore) SetYTDLPVersion(id int64, version string) error {
+	if _, err := squirrel.
+		Update(consts.DBVideos).
+		Set(consts.QVidYTDLPVer, version).
+		Where(squirrel.Eq{consts.QVidID: id}).
+		RunWith(vs.DB).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to set yt-dlp version for video with ID %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetYTDLPVersion returns the yt-dlp version a video was last downloaded with, or an empty string if not recorded.
+func (vs VideoStore) GetYTDLPVersion(id int64) (string, error) {
+	var version string
+	if err := squirrel.
+		Select(consts.QVidYTDLPVer).
+		From(consts.DBVideos).
+		Where(squirrel.Eq{consts.QVidID: id}).
+		RunWith(vs.DB).
+		QueryRow().
+		Scan(&version); err != nil {
+		return "", fmt.Errorf("failed to get yt-dlp version for video with ID %d: %w", id, err)
+	}
+	return version, nil
+}
+
+// SetTranscriptPath records the subtitles generated for a video, so it isn't transcribed again.
+func (vs VideoStore) SetTranscriptPath(id int64, path string) error {
+	if _, err := squirrel.
+		Update(consts.DBVideos).
+		Set(consts.QVidTranscript, path).
+		Where(squirrel.Eq{consts.QVidID: id}).
+		RunWith(vs.DB).
+		Exec(); err != nil {
+		return fmt.Errorf("failed to set transcript path for video with ID %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetTranscriptPath returns the subtitles generated for a video, or an empty string if not transcribed.
+func (vs VideoStore) GetTranscriptPath(id int64) (string, error) {
+	var path string
+	if err := squirrel.
+		Select(consts.QVidTranscript).
+		From(consts.DBVideos).
+		Where(squirrel.Eq{consts.QVidID: id}).
+		RunWith(vs.DB).
+		QueryRow().
+		Scan(&path); err != nil {
+		return "", fmt.Errorf("failed to get transcript path for video with ID %d: %w", id, err)
+	}
+	return path, nil
+}
+
+// GetVideoPaths returns the stored video and JSON file paths of a video.
+func (vs VideoStore) GetVideoPaths(id int64) (videoPath, jsonPath string, err error) {
+	var vPath, jPath sql.NullString
+	err = squirrel.
+		Select(consts.QVidVideoPath, consts.QVidJSONPath).
+		From(consts.DBVideos).
+		Where(squirrel.Eq{consts.QVidID: id}).
+		RunWith(vs.DB).
+		QueryRow().
+		Scan(&vPath, &jPath)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", fmt.Errorf("no video with ID %d", id)
+	} else if err != nil {
+		return "", "", fmt.Errorf("failed to get paths for video with ID %d: %w", id, err)
+	}
+	return vPath.String, jPath.String, nil
+}
+
+// GetVideoURL returns the channel and URL of a video.
+func (vs VideoStore) GetVideoURL(id int64) (chanID int64, url string, err error) {
+	err = squirrel.
+		Select(consts.QVidChanID, consts.QVidURL).
+		From(consts.DBVideos).
+		Where(squirrel.Eq{consts.QVidID: id}).
+		RunWith(vs.DB).
+		QueryRow().
+		Scan(&chanID, &url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", fmt.Errorf("no video with ID %d", id)
+	} else if err != nil {
+		return 0, "", fmt.Errorf("failed to get URL for video with ID %d: %w", id, err)
+	}
+	return chanID, url, nil
+}
+
+// UpdateVideoPaths sets the stored video and JSON file paths for a video.
+func (vs VideoStore) UpdateVideoPaths(id int64, videoPath, jsonPath string) error {
+	query := squirrel.
+		Update(consts.DBVideos).
+		Set(consts.QVidVideoPath, videoPath).
+		Set(consts.QVidJSONPath, jsonPath).
+		Set(consts.QVidUpdatedAt, time.Now()).
+		Where(squirrel.Eq{consts.QVidID: id}).
+		RunWith(vs.DB)
+
+	result, err := query.Exec()
+	if err != nil {
+		return fmt.Errorf("failed to update paths for video with ID %d: %w", id, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("no video found with ID %d", id)
+	}
+	return nil
+}
+
+// EditVideoMetadata applies manual metadata corrections to a video, returning the updated video.
+//
+// The stored metadata map is patched to match, so later reads agree with the corrected columns.
+func (vs VideoStore) EditVideoMetadata(id int64, e *models.VideoMetadataEdit) (*models.Video, error) {
+	var (
+		v            = models.Video{ID: id}
+		title, desc  sql.NullString
+		jPath, vPath sql.NullString
+		metadata     []byte
+		uploadDate   sql.NullTime
+	)
+	err := squirrel.
+		Select(consts.QVidChanID, consts.QVidURL, consts.QVidTitle, consts.QVidDescription, consts.QVidUploadDate, consts.QVidVideoPath, consts.QVidJSONPath, consts.QVidMetadata).
+		From(consts.DBVideos).
+		Where(squirrel.Eq{consts.QVidID: id}).
+		RunWith(vs.DB).
+		QueryRow().
+		Scan(&v.ChannelID, &v.URL, &title, &desc, &uploadDate, &vPath, &jPath, &metadata)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no video found with ID %d", id)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch video with ID %d: %w", id, err)
+	}
+	v.Title, v.Description, v.UploadDate = title.String, desc.String, uploadDate.Time
+	v.VideoPath, v.JSONPath = vPath.String, jPath.String
+
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &v.MetadataMap); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata for video with ID %d: %w", id, err)
+		}
+	}
+	if v.MetadataMap == nil {
+		v.MetadataMap = make(map[string]any)
+	}
+	for k, val := range e.MetadataFields() {
+		v.MetadataMap[k] = val
+	}
+	if e.Title != nil {
+		v.Title = *e.Title
+	}
+	if e.Description != nil {
+		v.Description = *e.Description
+	}
+	if e.UploadDate != nil {
+		v.UploadDate = *e.UploadDate
+	}
+
+	if metadata, err = json.Marshal(v.MetadataMap); err != nil {
+		return nil, fmt.Errorf("metadata marshal failed for video with ID %d: %w", id, err)
+	}
+
+	if _, err := squirrel.
+		Update(consts.DBVideos).
+		Set(consts.QVidTitle, v.Title).
+		Set(consts.QVidDescription, v.Description).
+		Set(consts.QVidUploadDate, v.UploadDate).
+		Set(consts.QVidMetadata, metadata).
+		Set(consts.QVidUpdatedAt, time.Now()).
+		Where(squirrel.Eq{consts.QVidID: id}).
+		RunWith(vs.DB).
+		Exec(); err != nil {
+		return nil, fmt.Errorf("failed to update metadata for video with ID %d: %w", id, err)
+	}
+	return &v, nil
+}
+
 // Private /////////////////////////////////////////////////////////////////////
 
 // videoExists returns true if the video exists in the database.
@@ -334,3 +720,58 @@ func (vs VideoStore) videoExists(v *models.Video) (int64, bool) {
 	logging.D(1, "Video %q already exists", v.ID)
 	return id, true
 }
+
+// StreamChannelVideos calls fn for each video in a channel, ordered by ID, without loading them all into memory.
+func (vs VideoStore) StreamChannelVideos(chanID int64, fn func(v *models.Video) error) error {
+	const (
+		join = consts.DBDownloads + " ON " + consts.DBDownloads + "." + consts.QDLVidID + " = " + consts.DBVideos + "." + consts.QVidID
+	)
+
+	col := func(c string) string { return consts.DBVideos + "." + c }
+	rows, err := squirrel.
+		Select(
+			col(consts.QVidID),
+			col(consts.QVidURL),
+			col(consts.QVidTitle),
+			col(consts.QVidDescription),
+			col(consts.QVidUploadDate),
+			col(consts.QVidVideoPath),
+			col(consts.QVidJSONPath),
+			col(consts.QVidCreatedAt),
+			consts.DBDownloads+"."+consts.QDLStatus,
+		).
+		From(consts.DBVideos).
+		LeftJoin(join).
+		Where(squirrel.Eq{col(consts.QVidChanID): chanID}).
+		OrderBy(col(consts.QVidID)).
+		RunWith(vs.DB).
+		Query()
+	if err != nil {
+		return fmt.Errorf("failed to query videos for channel with ID %d: %w", chanID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			v                         = models.Video{ChannelID: chanID}
+			title, desc, vPath, jPath sql.NullString
+			status                    sql.NullString
+			uploadDate, createdAt     sql.NullTime
+		)
+		if err := rows.Scan(&v.ID, &v.URL, &title, &desc, &uploadDate, &vPath, &jPath, &createdAt, &status); err != nil {
+			return fmt.Errorf("failed to scan video: %w", err)
+		}
+		v.Title = title.String
+		v.Description = desc.String
+		v.UploadDate = uploadDate.Time
+		v.VideoPath = vPath.String
+		v.JSONPath = jPath.String
+		v.CreatedAt = createdAt.Time
+		v.DownloadStatus.Status = consts.DownloadStatus(status.String)
+
+		if err := fn(&v); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,113 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// AddWebhook adds a webhook to a channel, replacing any existing webhook with the same name.
+func (cs *ChannelStore) AddWebhook(h *models.Webhook) error {
+	if h.URL == "" {
+		return errors.New("please enter a webhook URL")
+	}
+	if !cs.channelExistsID(h.ChannelID) {
+		return fmt.Errorf("channel with ID %d does not exist", h.ChannelID)
+	}
+	if h.Name == "" {
+		h.Name = h.URL
+	}
+
+	headers, err := json.Marshal(h.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook headers: %w", err)
+	}
+
+	const (
+		querySuffix = "ON CONFLICT (channel_id, name) DO UPDATE SET url = EXCLUDED.url, method = EXCLUDED.method, headers = EXCLUDED.headers, payload = EXCLUDED.payload, updated_at = EXCLUDED.updated_at"
+	)
+
+	now := time.Now()
+	query := squirrel.
+		Insert(consts.DBWebhooks).
+		Columns(consts.QHookChanID, consts.QHookName, consts.QHookURL, consts.QHookMethod, consts.QHookHeaders, consts.QHookPayload, consts.QHookCreatedAt, consts.QHookUpdatedAt).
+		Values(h.ChannelID, h.Name, h.URL, strings.ToUpper(h.Method), string(headers), h.Payload, now, now).
+		Suffix(querySuffix).
+		RunWith(cs.DB)
+
+	if _, err := query.Exec(); err != nil {
+		return fmt.Errorf("failed to add webhook %q: %w", h.Name, err)
+	}
+
+	logging.S(0, "Added webhook %q (%s %s) to channel with ID: %d", h.Name, strings.ToUpper(h.Method), h.URL, h.ChannelID)
+	return nil
+}
+
+// GetWebhooks returns all webhooks for a given channel.
+func (cs *ChannelStore) GetWebhooks(channelID int64) ([]*models.Webhook, error) {
+	query := squirrel.
+		Select(consts.QHookName, consts.QHookURL, consts.QHookMethod, consts.QHookHeaders, consts.QHookPayload).
+		From(consts.DBWebhooks).
+		Where(squirrel.Eq{consts.QHookChanID: channelID}).
+		OrderBy(consts.QHookName).
+		RunWith(cs.DB)
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var hooks []*models.Webhook
+	for rows.Next() {
+		var (
+			h                = models.Webhook{ChannelID: channelID}
+			headers, payload sql.NullString
+		)
+		if err := rows.Scan(&h.Name, &h.URL, &h.Method, &headers, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		if headers.String != "" {
+			if err := json.Unmarshal([]byte(headers.String), &h.Headers); err != nil {
+				return nil, fmt.Errorf("failed to decode headers for webhook %q: %w", h.Name, err)
+			}
+		}
+		h.Payload = payload.String
+		hooks = append(hooks, &h)
+	}
+	return hooks, rows.Err()
+}
+
+// DeleteWebhooks deletes the named webhooks from the channel.
+func (cs *ChannelStore) DeleteWebhooks(channelID int64, names []string) error {
+	if !cs.channelExistsID(channelID) {
+		return fmt.Errorf("channel with ID %d does not exist", channelID)
+	}
+
+	query := squirrel.
+		Delete(consts.DBWebhooks).
+		Where(squirrel.Eq{
+			consts.QHookChanID: channelID,
+			consts.QHookName:   names,
+		}).
+		RunWith(cs.DB)
+
+	res, err := query.Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete webhooks: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("no webhooks named %q found for channel with ID %d", names, channelID)
+	}
+	logging.S(0, "Deleted webhooks %q for channel with ID '%d'", names, channelID)
+	return nil
+}
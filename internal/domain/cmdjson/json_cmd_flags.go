@@ -9,6 +9,7 @@ const (
 	FilenameSyntax    = "%(title)s.%(ext)s"
 	RestrictFilenames = "--restrict-filenames"
 	MaxFilesize       = "--max-filesize"
+	Proxy             = "--proxy"
 	Output            = "-o"
 	P                 = "-P"
 	Retries           = "--retries"
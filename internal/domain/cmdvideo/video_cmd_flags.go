@@ -2,22 +2,60 @@
 package cmdvideo
 
 const (
-	AfterMove         = "after_move:%(filepath)s"
-	CookieSource      = "--cookies-from-browser"
-	CookiePath        = "--cookies"
-	ExternalDLer      = "--external-downloader"
-	ExternalDLArgs    = "--external-downloader-args"
-	FilenameSyntax    = "%(title)s.%(ext)s"
-	RestrictFilenames = "--restrict-filenames"
-	Retries           = "--retries"
-	SleepRequests     = "--sleep-requests"
-	SleepRequestsNum  = "1"
-	MaxFilesize       = "--max-filesize"
-	Output            = "-o"
-	Print             = "--print"
-	YTDLP             = "yt-dlp"
+	AfterMove          = "after_move:%(filepath)s"
+	CookieSource       = "--cookies-from-browser"
+	CookiePath         = "--cookies"
+	ExternalDLer       = "--external-downloader"
+	ExternalDLArgs     = "--external-downloader-args"
+	ConcurrentFrags    = "--concurrent-fragments"
+	FilenameSyntax     = "%(title)s.%(ext)s"
+	Format             = "-f"
+	LimitRate          = "--limit-rate"
+	LiveFromStart      = "--live-from-start"
+	RestrictFilenames  = "--restrict-filenames"
+	Retries            = "--retries"
+	SleepRequests      = "--sleep-requests"
+	SleepRequestsNum   = "1"
+	MaxFilesize        = "--max-filesize"
+	Newline            = "--newline"
+	Output             = "-o"
+	PluginDirs         = "--plugin-dirs"
+	Print              = "--print"
+	Progress           = "--progress"
+	Proxy              = "--proxy"
+	SponsorBlockMark   = "--sponsorblock-mark"
+	SponsorBlockRemove = "--sponsorblock-remove"
+	YTDLP              = "yt-dlp"
 )
 
 const (
 	AriaLog = "--console-log-level=info"
 )
+
+// SponsorBlock
+const (
+	SponsorBlockPrefix = "tubarr-sponsorblock:"
+	SponsorBlockPrint  = "after_move:" + SponsorBlockPrefix + "%(sponsorblock_chapters)j"
+)
+
+// Gallery-dl
+const (
+	GalleryDL            = "gallery-dl"
+	GalleryDLDest        = "-D"
+	GalleryDLMaxFilesize = "--filesize-max"
+	GalleryDLProxy       = "--proxy"
+	GalleryDLSleep       = "--sleep-request"
+	GalleryDLDumpJSON    = "-j"
+	GalleryDLFilename    = "-f"
+)
+
+// Streamlink
+const (
+	Streamlink              = "streamlink"
+	StreamlinkOutput        = "-o"
+	StreamlinkForce         = "--force"
+	StreamlinkHTTPProxy     = "--http-proxy"
+	StreamlinkRetryOpen     = "--retry-open"
+	StreamlinkQualityBest   = "best"
+	StreamlinkWritingOutput = "Writing output to"
+)
@@ -25,6 +25,9 @@ var (
 
 // Op types
 const (
-	FilterContains = "contains"
-	FilterOmit     = "omit"
+	FilterContains  = "contains"
+	FilterOmit      = "omit"
+	FilterRegex     = "regex"
+	FilterOmitRegex = "omit-regex"
+	OpRegex         = "regex"
 )
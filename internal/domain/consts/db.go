@@ -7,16 +7,40 @@ const (
 	DBVideos        = "videos"
 	DBDownloads     = "downloads"
 	DBNotifications = "notifications"
+	DBHostStats     = "host_stats"
+	DBStorage       = "storage_usage"
+	DBSkipped       = "skipped_videos"
+	DBEvents        = "channel_events"
+	DBConfirm       = "confirmations"
+	DBStages        = "video_stages"
+	DBWebhooks      = "webhooks"
+	DBRetries       = "retry_queue"
+	DBPosts         = "posts"
+	DBPauses        = "pauses"
+	DBChannelTags   = "channel_tags"
+	DBDefaults      = "defaults"
+	DBPresets       = "presets"
+	DBVideoSearch   = "videos_fts"
+	DBStats         = "download_stats"
+	DBUsers         = "users"
+	DBUserSessions  = "user_sessions"
+	DBCrawlReports  = "crawl_reports"
+	DBSMTP          = "smtp_settings"
+	DBEventHooks    = "event_hooks"
+	DBGlobalIgnores = "global_ignores"
+	DBPendingCmds   = "pending_commands"
 )
 
 // Program
 const (
 	QProgHost      = "host"
+	QProgHTTPAddr  = "http_addr"
 	QProgID        = "id"
 	QProgHeartbeat = "last_heartbeat"
 	QProgPID       = "pid"
 	QProgStartedAt = "started_at"
 	QProgRunning   = "running"
+	QProgScheduler = "scheduler"
 )
 
 // Channel
@@ -37,6 +61,7 @@ const (
 	QChanLoginURL        = "login_url"
 	QChanCreatedAt       = "created_at"
 	QChanUpdatedAt       = "updated_at"
+	QChanOwnerID         = "owner_id"
 )
 
 // Videos
@@ -58,15 +83,23 @@ const (
 	QVidDLStatus    = "download_status"
 	QVidCreatedAt   = "created_at"
 	QVidUpdatedAt   = "updated_at"
+	QVidYTDLPVer    = "ytdlp_version"
+	QVidTranscript  = "transcript_path"
 )
 
 // Downloads
 const (
-	QDLVidID     = "video_id"
-	QDLStatus    = "status"
-	QDLPct       = "percentage"
-	QDLCreatedAt = "created_at"
-	QDLUpdatedAt = "updated_at"
+	QDLVidID        = "video_id"
+	QDLStatus       = "status"
+	QDLPct          = "percentage"
+	QDLCancelReason = "cancel_reason"
+	QDLCancelledAt  = "cancelled_at"
+	QDLFailReason   = "fail_reason"
+	QDLPartialPath  = "partial_path"
+	QDLFragsDone    = "fragments_done"
+	QDLFragsTotal   = "fragments_total"
+	QDLCreatedAt    = "created_at"
+	QDLUpdatedAt    = "updated_at"
 )
 
 // Notification
@@ -78,6 +111,225 @@ const (
 	QNotifyUpdatedAt = "updated_at"
 )
 
+// Webhooks
+const (
+	QHookChanID    = "channel_id"
+	QHookName      = "name"
+	QHookURL       = "url"
+	QHookMethod    = "method"
+	QHookHeaders   = "headers"
+	QHookPayload   = "payload"
+	QHookCreatedAt = "created_at"
+	QHookUpdatedAt = "updated_at"
+)
+
+// Retry queue
+const (
+	QRetryChanID      = "channel_id"
+	QRetryURL         = "url"
+	QRetryAttempts    = "attempts"
+	QRetryNextAttempt = "next_attempt_at"
+	QRetryLastError   = "last_error"
+	QRetryCreatedAt   = "created_at"
+	QRetryUpdatedAt   = "updated_at"
+)
+
+// Host stats
+const (
+	QHostName        = "hostname"
+	QHostSuccesses   = "successes"
+	QHostFailures    = "failures"
+	QHostBotBlocks   = "bot_blocks"
+	QHostBlockStreak = "block_streak"
+	QHostNewBlocks   = "new_bot_blocks"
+	QHostConcurrency = "concurrency"
+	QHostDelay       = "delay_seconds"
+	QHostCreatedAt   = "created_at"
+	QHostUpdatedAt   = "updated_at"
+)
+
+// Storage usage
+const (
+	QStorageChanID     = "channel_id"
+	QStorageVideoBytes = "video_bytes"
+	QStorageJSONBytes  = "json_bytes"
+	QStorageFileCount  = "file_count"
+	QStorageUpdatedAt  = "updated_at"
+)
+
+// Skipped videos
+const (
+	QSkipID        = "id"
+	QSkipChanID    = "channel_id"
+	QSkipURL       = "url"
+	QSkipReason    = "reason"
+	QSkipDetail    = "detail"
+	QSkipCreatedAt = "created_at"
+)
+
+// Posts
+const (
+	QPostID        = "id"
+	QPostChanID    = "channel_id"
+	QPostPostID    = "post_id"
+	QPostURL       = "url"
+	QPostText      = "text"
+	QPostFiles     = "files"
+	QPostDir       = "directory"
+	QPostPostedAt  = "posted_at"
+	QPostCreatedAt = "created_at"
+)
+
+// Pauses
+const (
+	QPauseScope     = "scope"
+	QPauseUntil     = "until"
+	QPauseReason    = "reason"
+	QPauseCreatedAt = "created_at"
+
+	// PauseAllScope is the pause scope covering every host.
+	PauseAllScope = "*"
+
+	// PauseGroupPrefix prefixes the tag in pause scopes covering a channel group.
+	PauseGroupPrefix = "group:"
+
+	// PauseURLPrefix prefixes the URL in pause scopes covering a single channel URL.
+	PauseURLPrefix = "url:"
+)
+
+// Channel URL IDs, naming a channel's URLs in the HTTP API.
+const (
+	ChannelURLCrawl = "crawl"
+	ChannelURLLive  = "live"
+)
+
+// Defaults profile
+const (
+	QDefID        = "id"
+	QDefSettings  = "settings"
+	QDefMetarr    = "metarr"
+	QDefUpdatedAt = "updated_at"
+)
+
+// SMTP settings
+const (
+	QSMTPID        = "id"
+	QSMTPConfig    = "config"
+	QSMTPUpdatedAt = "updated_at"
+)
+
+// Event hooks
+const (
+	QEvHookID        = "id"
+	QEvHookName      = "name"
+	QEvHookEvent     = "event"
+	QEvHookPath      = "path"
+	QEvHookArgs      = "args"
+	QEvHookTimeout   = "timeout_secs"
+	QEvHookCreatedAt = "created_at"
+	QEvHookUpdatedAt = "updated_at"
+)
+
+// Global ignores
+const (
+	QGIgnID        = "id"
+	QGIgnKind      = "kind"
+	QGIgnValue     = "value"
+	QGIgnNote      = "note"
+	QGIgnCreatedAt = "created_at"
+)
+
+// Pending commands
+const (
+	QPCmdID        = "id"
+	QPCmdChanID    = "channel_id"
+	QPCmdAction    = "action"
+	QPCmdURLs      = "urls"
+	QPCmdCreatedAt = "created_at"
+)
+
+// Users
+const (
+	QUserID        = "id"
+	QUserName      = "username"
+	QUserPassword  = "password_hash"
+	QUserRole      = "role"
+	QUserCreatedAt = "created_at"
+
+	QSessToken     = "token_hash"
+	QSessUserID    = "user_id"
+	QSessExpiresAt = "expires_at"
+
+	// RoleAdmin users see every channel and may delete channels and videos.
+	RoleAdmin = "admin"
+	// RoleUser users see only the channels they own.
+	RoleUser = "user"
+	// RoleOpen is how requests act before any users are added: they see every channel but can't delete anything.
+	RoleOpen = "open"
+)
+
+// Download stats
+const (
+	QStatChanID    = "channel_id"
+	QStatDay       = "day"
+	QStatDownloads = "downloads"
+	QStatFailures  = "failures"
+	QStatBytes     = "bytes"
+	QStatDuration  = "duration_ms"
+)
+
+// Crawl reports
+const (
+	QReportID         = "id"
+	QReportStartedAt  = "started_at"
+	QReportFinishedAt = "finished_at"
+	QReportNewVideos  = "new_videos"
+	QReportFailures   = "failures"
+	QReportFiltered   = "filtered"
+	QReportChannels   = "channels"
+	QReportDigestSent = "digest_sent"
+)
+
+// Presets
+const (
+	QPresetName      = "name"
+	QPresetSettings  = "settings"
+	QPresetMetarr    = "metarr"
+	QPresetCreatedAt = "created_at"
+	QPresetUpdatedAt = "updated_at"
+)
+
+// Channel tags
+const (
+	QTagChanID    = "channel_id"
+	QTagName      = "tag"
+	QTagCreatedAt = "created_at"
+)
+
+// Channel events
+const (
+	QEventID        = "id"
+	QEventChanID    = "channel_id"
+	QEventKind      = "kind"
+	QEventDetail    = "detail"
+	QEventCreatedAt = "created_at"
+)
+
+// Video pipeline stages
+const (
+	QStageVidID = "video_id"
+	QStageName  = "stage"
+	QStageAt    = "at"
+)
+
+// Confirmations
+const (
+	QConfirmToken     = "token"
+	QConfirmOperation = "operation"
+	QConfirmSummary   = "summary"
+	QConfirmExpiresAt = "expires_at"
+)
+
 // DownloadStatus holds constant download status strings.
 type DownloadStatus string
 
@@ -86,4 +338,100 @@ const (
 	DLStatusDownloading DownloadStatus = "Downloading"
 	DLStatusCompleted   DownloadStatus = "Finished"
 	DLStatusFailed      DownloadStatus = "Failed"
+	DLStatusCancelled   DownloadStatus = "Cancelled"
+)
+
+// CancelReason holds constant download cancellation source strings.
+type CancelReason string
+
+const (
+	CancelUser     CancelReason = "user"
+	CancelShutdown CancelReason = "shutdown"
+	CancelTimeout  CancelReason = "timeout"
+	CancelQuota    CancelReason = "quota"
+	CancelIgnored  CancelReason = "ignored"
+)
+
+// FailReason holds constant download failure classifications.
+type FailReason string
+
+const (
+	FailTruncated   FailReason = "truncated"
+	FailAuth        FailReason = "auth"
+	FailRateLimited FailReason = "rate-limited"
+	FailUnavailable FailReason = "unavailable"
+	FailUnknown     FailReason = "unknown"
+)
+
+// SkipReason holds constant reasons for a video candidate being skipped.
+type SkipReason string
+
+const (
+	SkipFilter     SkipReason = "filter"
+	SkipURLAllow   SkipReason = "url-allow"
+	SkipURLBlock   SkipReason = "url-block"
+	SkipIgnored    SkipReason = "global-ignore"
+	SkipDuration   SkipReason = "duration"
+	SkipViews      SkipReason = "min-views"
+	SkipDateWindow SkipReason = "date-window"
+)
+
+// IgnoreKind holds constant kinds of global ignore rule.
+type IgnoreKind string
+
+const (
+	IgnoreURL     IgnoreKind = "url"     // Exact video URL
+	IgnorePattern IgnoreKind = "pattern" // Regex matched against video URLs
+	IgnoreTitle   IgnoreKind = "title"   // Regex matched against video titles
+)
+
+// CommandAction holds constant actions which may be queued for the running instance.
+type CommandAction string
+
+const (
+	CommandCrawl     CommandAction = "crawl"
+	CommandReprocess CommandAction = "reprocess"
+)
+
+// ActivityKind holds constant channel activity feed entry types.
+type ActivityKind string
+
+const (
+	ActivityDownload         ActivityKind = "download"
+	ActivityFailure          ActivityKind = "failure"
+	ActivityCancel           ActivityKind = "cancelled"
+	ActivitySkip             ActivityKind = "skipped"
+	ActivityBotBlock         ActivityKind = "bot-block"
+	ActivitySettings         ActivityKind = "settings"
+	ActivityCrawl            ActivityKind = "crawl"
+	ActivityExtractorFailure ActivityKind = "extractor-failure"
+	ActivityMetadataEdit     ActivityKind = "metadata-edit"
+	ActivityPost             ActivityKind = "post"
+	ActivityQuota            ActivityKind = "quota"
+	ActivityLive             ActivityKind = "live"
+	ActivityChallenge        ActivityKind = "challenge"
+)
+
+// PipelineStage holds constant video processing stage names.
+type PipelineStage string
+
+const (
+	StageQueued        PipelineStage = "queued"
+	StageDownloadStart PipelineStage = "download-start"
+	StageDownloadEnd   PipelineStage = "download-end"
+	StageMetarrStart   PipelineStage = "metarr-start"
+	StageMetarrEnd     PipelineStage = "metarr-end"
+	StageMoved         PipelineStage = "moved"
+	StageNotified      PipelineStage = "notified"
+)
+
+// BulkAction holds constant bulk video status transition strings.
+type BulkAction string
+
+const (
+	BulkIgnore          BulkAction = "ignore"
+	BulkUnignore        BulkAction = "unignore"
+	BulkRequeue         BulkAction = "requeue"
+	BulkDeleteFilesKeep BulkAction = "delete-files-keep-record"
+	BulkRedownload      BulkAction = "redownload"
 )
@@ -4,9 +4,50 @@ package consts
 const (
 	YtDLPFlatPlaylist = "--flat-playlist"
 	YtDLPOutputJSON   = "-J"
+	YtDLPPrint        = "--print"
+	YtDLPLiveStatus   = "%(live_status)s %(webpage_url)s"
+	YtDLPPlaylistOne  = "--playlist-items=1"
+	YtDLPNoWarnings   = "--no-warnings"
 )
 
 // Downloaders
 const (
 	DownloaderAria = "aria2c"
+	DownloaderAxel = "axel"
+)
+
+// Fetchers
+const (
+	FetcherYTDLP      = "yt-dlp"
+	FetcherGalleryDL  = "gallery-dl"
+	FetcherStreamlink = "streamlink"
+)
+
+// Sidecar files
+const (
+	SidecarNFO         = "nfo"
+	SidecarChapters    = "chapters"
+	SidecarDescription = "description"
+)
+
+// SidecarKinds are the sidecar files which can be written next to downloaded videos.
+var SidecarKinds = []string{SidecarNFO, SidecarChapters, SidecarDescription}
+
+// Login methods
+const (
+	AuthMethodForm    = "form"
+	AuthMethodBrowser = "browser"
+)
+
+// Fragment and connection limits
+const (
+	MaxConcurrentFragments = 64
+	MaxAriaConnections     = 16
+)
+
+// Self-test
+const (
+	// SelfTestURL is yt-dlp's own ten second test video.
+	SelfTestURL     = "https://www.youtube.com/watch?v=BaW_jenozKc"
+	SelfTestChannel = "tubarr-selftest"
 )
@@ -2,5 +2,13 @@ package errconsts
 
 // Programs
 const (
-	YTDLPFailure = "yt-dlp command failed, ensure your yt-dlp install at $PATH is healthy and running with the correct Python version: %w"
+	GalleryDLFailure  = "gallery-dl command failed, ensure your gallery-dl install at $PATH is healthy: %w"
+	StreamlinkFailure = "streamlink command failed, ensure your streamlink install at $PATH is healthy: %w"
+	YTDLPFailure      = "yt-dlp command failed, ensure your yt-dlp install at $PATH is healthy and running with the correct Python version: %w"
+)
+
+// Confirmation tokens
+const (
+	ConfirmRenewCLI = "run the command without --confirm to get a new one"
+	ConfirmRenewAPI = "repeat the request without the confirm query parameter to get a new one"
 )
@@ -11,6 +11,16 @@ const (
 const (
 	ChannelCheckNew string = "CheckChannelsForNew"
 	CheckChannels   string = "checkChannels"
+	RunScheduler    string = "runScheduler"
+	RunServer       string = "runServer"
+)
+
+// Self-test
+const (
+	RunSelfTest    string = "runSelfTest"
+	SelfTestURL    string = "selfTestURL"
+	SelfTestKeep   string = "selfTestKeep"
+	SelfTestNotify string = "selfTestNotify"
 )
 
 // Download operations
@@ -13,12 +13,14 @@ const (
 	AuthUsername string = "auth-username"
 	AuthPassword string = "auth-password"
 	AuthURL      string = "auth-url"
+	Owner        string = "owner"
 )
 
 // Files and directories
 const (
 	VideoDir       string = "video-directory"
 	JSONDir        string = "json-directory"
+	StagingDir     string = "staging-directory"
 	MetarrPreset   string = "metarr-preset"
 	OutputFiletype string = "ext"
 )
@@ -38,14 +40,24 @@ const (
 	MoveOnComplete        string = "move-on-complete"
 	URLFile               string = "url-file"
 	URLAdd                string = "add-url"
+	AuthCookies           string = "auth-cookies"
+	AuthCookiesFile       string = "auth-cookies-file"
 	URLs                  string = "urls"
+	DeleteFiles           string = "delete-files"
 	Benchmarking          string = "benchmark"
+	AutoTuneHosts         string = "auto-tune-hosts"
+	ReadOnly              string = "read-only"
 )
 
 // Settings
 const (
 	FilterOpsInput string = "filter-ops"
 	CrawlFreq      string = "crawl-freq"
+	CrawlCron      string = "crawl-cron"
+	QuietHours     string = "quiet-hours"
+	CrawlJitter    string = "crawl-jitter"
+	URLAllow       string = "url-allow"
+	URLBlock       string = "url-block"
 )
 
 // Database operations
@@ -69,5 +81,77 @@ const (
 	NoFileOverwrite       string = "metarr-no-file-overwrite"
 	MetarrConcurrency     string = "metarr-concurrency"
 	MetarrOutputDir       string = "metarr-output-dir"
+	SkipMetarr            string = "skip-metarr"
+	MountMarker           string = "mount-marker"
+	YTDLPExtraArgs        string = "ytdlp-extra-args"
+	YTDLPPluginDir        string = "ytdlp-plugin-dir"
+	ConcurrentFragments   string = "concurrent-fragments"
+	ExternalDLConnections string = "external-downloader-connections"
+	PlaylistMatch         string = "playlist-match"
+	ExtractorDiagnostics  string = "extractor-diagnostics"
+	BlackoutDates         string = "blackout-dates"
+	ExternalIDs           string = "external-ids"
+	RetryMaxAttempts      string = "retry-max-attempts"
+	MaxDownloadsPerCrawl  string = "max-downloads-per-crawl"
+	MinDuration           string = "min-duration"
+	MaxDuration           string = "max-duration"
+	MinViews              string = "min-views"
+	MaxResolution         string = "max-resolution"
+	PreferredCodec        string = "preferred-codec"
+	AudioOnly             string = "audio-only"
+	Sidecars              string = "sidecars"
+	NFO                   string = "nfo"
+	NFOEpisodeTemplate    string = "nfo-episode-template"
+	NFOShowTemplate       string = "nfo-show-template"
+	NFOFieldMap           string = "nfo-field-map"
+	NFOFieldMapFile       string = "nfo-field-map-file"
+	OutputTemplate        string = "output-template"
+	PostProcessors        string = "post-processors"
+	PostProcessorDir      string = "post-processor-dir"
+	PostProcessorTimeout  string = "post-processor-timeout"
+	Transcribe            string = "transcribe"
+	TranscribeModel       string = "transcribe-model"
+	TranscribeLanguage    string = "transcribe-language"
+	WhisperPath           string = "whisper-path"
+	MaxRate               string = "max-rate"
+	Fetcher               string = "fetcher"
+	FetcherRules          string = "fetcher-rule"
+	SponsorBlockRemove    string = "sponsorblock-remove"
+	SponsorBlockMark      string = "sponsorblock-mark"
+	PostsURL              string = "posts-url"
+	MaxTotalSize          string = "max-total-size"
+	QuotaPrune            string = "quota-prune"
+	KeepLast              string = "keep-last"
+	KeepDays              string = "keep-days"
+	RetentionNotify       string = "retention-notify"
+	Proxy                 string = "proxy"
+	LiveURL               string = "live-url"
+	ChannelTag            string = "tag"
+	Preset                string = "preset"
+	LiveCheckFreq         string = "live-check-freq"
+	RecordSkips           string = "record-skips"
+	SkipRetentionDays     string = "skip-retention-days"
+	EventRetentionDays    string = "event-retention-days"
+	DurationTolerance     string = "duration-tolerance"
+	HTTPAddr              string = "http-addr"
+	IdleExit              string = "idle-exit"
+	APIToken              string = "api-token"
+	RemoteAddr            string = "remote-addr"    // Set when commands go to a running instance's HTTP API
+	QueueCommands         string = "queue-commands" // Set when commands are queued for a running scheduler
+	ShutdownGrace         string = "shutdown-grace"
+	DigestWebhook         string = "digest-webhook"
+	DigestEmail           string = "digest-email"
+	AppriseBin            string = "apprise-bin"
+	AppriseURLs           string = "apprise-url"
+	CrawlConcurrency      string = "crawl-concurrency"
+	CrawlHostConcurrency  string = "crawl-host-concurrency"
+	HostRateLimit         string = "host-rate-limit"
+	FallbackProxy         string = "fallback-proxy"
+	FallbackCookieSource  string = "fallback-cookie-source"
+	BotBlockBackoff       string = "bot-block-backoff"
+	AuthMethod            string = "auth-method"
+	ChromePath            string = "chrome-path"
+	SecretCommand         string = "secret-command"  // Flag adding a command 'cmd:name' secret references may run
+	SecretCommands        string = "secret-commands" // Config file map of the commands 'cmd:name' secret references may run
 	MetarrExt             string = "metarr-ext"
 )
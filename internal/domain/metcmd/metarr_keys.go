@@ -29,4 +29,5 @@ const (
 	VideoDirectory = "--video-directory"
 	JSONFile       = "--json-file"
 	VideoFile      = "--video-file"
+	ResultsFile    = "--results-file"
 )
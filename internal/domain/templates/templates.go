@@ -6,12 +6,28 @@ const (
 	ChannelName   = "channel_name"
 	ChannelURL    = "channel_url"
 	ChannelID     = "channel_id"
+	Channel       = "channel" // Alias of ChannelName
+)
+
+const (
+	TVDBID = "tvdb_id"
+	TMDBID = "tmdb_id"
+	IMDBID = "imdb_id"
 )
 
 const (
 	VideoID    = "video_id"
 	VideoURL   = "video_url"
 	VideoTitle = "video_title"
+	Playlist   = "playlist"
+)
+
+const (
+	UploadYear  = "upload_year"
+	UploadMonth = "upload_month"
+	UploadDay   = "upload_day"
+	Season      = "season"  // Upload year
+	Episode     = "episode" // Upload month and day, then the index among the season's videos that day
 )
 
 const (
@@ -0,0 +1,94 @@
+package downloads
+
+import (
+	"context"
+	"sync"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+// bandwidth shares the global rate ceiling between concurrent video downloads.
+//
+// yt-dlp can't be throttled once running, so each download is given a fixed --limit-rate when it
+// starts. Shares are reserved from the ceiling and returned when the download ends, so the total
+// never exceeds it; a download waits if no share is free.
+type bandwidth struct {
+	mu        sync.Mutex
+	allocated int64
+	released  chan struct{} // Closed and replaced whenever a share is returned
+}
+
+var sharedBandwidth = &bandwidth{released: make(chan struct{})}
+
+// acquire reserves a rate for one download, returning 0 if no limit applies.
+//
+// The global ceiling is split evenly between the download slots which may run at once (channel
+// concurrency times per-channel concurrency), and the channel's own cap applies on top.
+func (b *bandwidth) acquire(ctx context.Context, ceiling, channelRate int64, slots int) (rate int64, release func(), err error) {
+	if ceiling <= 0 {
+		return channelRate, func() {}, nil
+	}
+
+	share := ceiling / int64(max(slots, 1))
+	if channelRate > 0 && channelRate < share {
+		share = channelRate
+	}
+
+	for {
+		b.mu.Lock()
+		if b.allocated+share <= ceiling {
+			b.allocated += share
+			b.mu.Unlock()
+
+			var once sync.Once
+			return share, func() { once.Do(func() { b.release(share) }) }, nil
+		}
+		wait := b.released
+		b.mu.Unlock()
+
+		logging.D(1, "Waiting for download bandwidth (%s of %s in use)", parsing.FormatRate(b.inUse()), parsing.FormatRate(ceiling))
+		select {
+		case <-ctx.Done():
+			return 0, nil, ctx.Err()
+		case <-wait:
+		}
+	}
+}
+
+// release returns a download's share to the pool and wakes waiting downloads.
+func (b *bandwidth) release(share int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.allocated -= share
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+// inUse returns the currently reserved rate.
+func (b *bandwidth) inUse() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.allocated
+}
+
+// downloadRate reserves the rate for a video download from the global and channel caps.
+//
+// Invalid rates are logged and ignored, since they are validated when set.
+func (d *Download) downloadRate() (rate int64, release func(), err error) {
+	ceiling, err := parsing.ParseRate(cfg.GetString(keys.MaxRate))
+	if err != nil {
+		logging.E(0, "Ignoring global max rate: %v", err)
+		ceiling = 0
+	}
+	channelRate, err := parsing.ParseRate(d.Video.Settings.MaxRate)
+	if err != nil {
+		logging.E(0, "Ignoring channel max rate: %v", err)
+		channelRate = 0
+	}
+
+	slots := max(cfg.GetInt(keys.Concurrency), 1) * max(d.Video.Settings.Concurrency, 1)
+	return sharedBandwidth.acquire(d.Context, ceiling, channelRate, slots)
+}
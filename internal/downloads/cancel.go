@@ -0,0 +1,75 @@
+package downloads
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const userCancelPoll = 5 * time.Second
+
+var (
+	liveMu sync.Mutex
+	live   = make(map[int64]context.CancelCauseFunc) // Running downloads by video ID
+)
+
+// CancelLive stops a running download of the video in this instance, recording the reason.
+//
+// Returns false if the video isn't being downloaded.
+func CancelLive(videoID int64, reason consts.CancelReason) bool {
+	liveMu.Lock()
+	cancel, ok := live[videoID]
+	liveMu.Unlock()
+	if ok {
+		cancel(&CancelError{Reason: reason})
+	}
+	return ok
+}
+
+// trackLive registers a running download so it can be cancelled, returning a function to unregister it.
+func trackLive(videoID int64, cancel context.CancelCauseFunc) func() {
+	if videoID == 0 {
+		return func() {}
+	}
+	liveMu.Lock()
+	live[videoID] = cancel
+	liveMu.Unlock()
+	return func() {
+		liveMu.Lock()
+		delete(live, videoID)
+		liveMu.Unlock()
+	}
+}
+
+// watchUserCancel stops the download if the video is cancelled in the database after it started,
+// such as by 'video cancel' in another instance.
+func (d *Download) watchUserCancel(started time.Time, cancel context.CancelCauseFunc) {
+	if d.Video.ID == 0 || d.DLTracker == nil || d.DLTracker.dlStore == nil {
+		return
+	}
+	ticker := time.NewTicker(userCancelPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.Context.Done():
+			return
+		case <-ticker.C:
+		}
+
+		status := &models.Video{ID: d.Video.ID}
+		if err := d.DLTracker.dlStore.GetDownloadStatus(status); err != nil {
+			logging.D(1, "Could not check %q for cancellation: %v", d.Video.URL, err)
+			continue
+		}
+		if status.DownloadStatus.CancelReason == consts.CancelUser && status.DownloadStatus.CancelledAt.After(started) {
+			logging.I("Stopping download of %q, cancelled by the user", d.Video.URL)
+			cancel(&CancelError{Reason: consts.CancelUser})
+			return
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package downloads
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -51,10 +53,53 @@ func (d *Download) waitForFile(filepath string, timeout time.Duration) error {
 	return fmt.Errorf("file not ready or empty after %v: %s", timeout, filepath)
 }
 
-// cancelDownload cancels the download, typically due to user input.
+// cancelDownload cancels the download, typically due to user input or program shutdown.
 func (d *Download) cancelDownload() error {
-	d.Video.DownloadStatus.Status = consts.DLStatusFailed
+	reason := cancelReason(d.Context)
+
+	d.Video.DownloadStatus.Status = consts.DLStatusCancelled
 	d.Video.DownloadStatus.Error = d.Context.Err()
+	d.Video.DownloadStatus.CancelReason = reason
+	d.Video.DownloadStatus.CancelledAt = time.Now()
 	d.DLTracker.sendUpdate(d.Video)
-	return fmt.Errorf("user canceled download for %s: %w", d.Video.URL, d.Context.Err())
+	return fmt.Errorf("download for %s canceled (reason: %s): %w", d.Video.URL, reason, d.Context.Err())
+}
+
+// CancelError can be passed as a context cancellation cause to record why a download stopped.
+type CancelError struct {
+	Reason consts.CancelReason
+}
+
+// Error implements the error interface.
+func (e *CancelError) Error() string {
+	return "download canceled: " + string(e.Reason)
+}
+
+// failReason classifies a failed download attempt.
+func failReason(err error) consts.FailReason {
+	var truncErr *TruncatedError
+	if errors.As(err, &truncErr) {
+		return consts.FailTruncated
+	}
+
+	var fetchErr *FetchError
+	if errors.As(err, &fetchErr) {
+		return classifyErrLine(fetchErr.Line)
+	}
+	return ""
+}
+
+// cancelReason determines the cancellation source from the context.
+//
+// Contexts without a specific cause are treated as program shutdown (e.g. SIGINT/SIGTERM).
+func cancelReason(ctx context.Context) consts.CancelReason {
+	var cancelErr *CancelError
+	if errors.As(context.Cause(ctx), &cancelErr) {
+		return cancelErr.Reason
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return consts.CancelTimeout
+	}
+	return consts.CancelShutdown
 }
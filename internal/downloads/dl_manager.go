@@ -5,11 +5,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"time"
 
 	"tubarr/internal/domain/consts"
 	"tubarr/internal/models"
+	"tubarr/internal/utils/hostlimit"
 	"tubarr/internal/utils/logging"
 )
 
@@ -41,6 +43,15 @@ func (d *Download) Execute() error {
 		return errors.New("video model is nil")
 	}
 
+	// Cancellable on its own, recording why it stopped
+	ctx, cancel := context.WithCancelCause(d.Context)
+	defer cancel(nil)
+	d.Context, d.cancel = ctx, cancel
+	defer trackLive(d.Video.ID, cancel)()
+	if d.Type == TypeVideo {
+		go d.watchUserCancel(time.Now(), cancel)
+	}
+
 	var lastErr error
 	for attempt := 1; attempt <= d.Options.MaxRetries; attempt++ {
 		logging.I("Starting %s download attempt %d/%d for URL: %s",
@@ -51,13 +62,30 @@ func (d *Download) Execute() error {
 			return d.cancelDownload()
 		default:
 			if err := d.executeAttempt(); err != nil {
+				// Killed by shutdown, so left resumable rather than failed
+				if d.Context.Err() != nil {
+					return d.cancelDownload()
+				}
+				// Left out by yt-dlp on purpose, so neither failed nor worth retrying
+				var dateErr *DateRangeError
+				if errors.As(err, &dateErr) {
+					return err
+				}
 				lastErr = err
 				logging.E(0, "Download attempt %d failed: %v", attempt, err)
 
 				d.Video.DownloadStatus.Status = consts.DLStatusFailed
 				d.Video.DownloadStatus.Error = err
+				d.Video.DownloadStatus.FailReason = failReason(err)
 				d.DLTracker.sendUpdate(d.Video)
 
+				// Remove truncated files so the backend doesn't treat the next attempt as already downloaded
+				if d.Video.DownloadStatus.FailReason == consts.FailTruncated {
+					if err := os.Remove(d.Video.VideoPath); err != nil && !os.IsNotExist(err) {
+						logging.E(0, "Failed to remove truncated file %q: %v", d.Video.VideoPath, err)
+					}
+				}
+
 				if attempt < d.Options.MaxRetries {
 					select {
 					case <-d.Context.Done():
@@ -72,6 +100,7 @@ func (d *Download) Execute() error {
 				d.Video.UpdatedAt = time.Now()
 				d.Video.DownloadStatus.Status = consts.DLStatusCompleted
 				d.Video.DownloadStatus.Pct = 100.0
+				d.Video.DownloadStatus.FailReason = ""
 
 				d.DLTracker.sendUpdate(d.Video)
 				return nil
@@ -84,12 +113,27 @@ func (d *Download) Execute() error {
 
 // executeAttempt performs a single download attempt.
 func (d *Download) executeAttempt() error {
-	var cmd *exec.Cmd
+	var (
+		cmd *exec.Cmd
+		f   fetcher
+	)
+
+	// Spaced out per host across every channel and manual downloads
+	if err := hostlimit.Wait(d.Context, d.Video.URL); err != nil {
+		return err
+	}
+
 	switch d.Type {
 	case TypeJSON:
 		cmd = d.buildJSONCommand()
 	case TypeVideo:
-		cmd = d.buildVideoCommand()
+		rate, release, err := d.downloadRate()
+		if err != nil {
+			return err
+		}
+		defer release()
+		f = newFetcher(d)
+		cmd = f.command(d, rate)
 	default:
 		return fmt.Errorf("unsupported download type: %s", d.Type)
 	}
@@ -105,5 +149,5 @@ func (d *Download) executeAttempt() error {
 	d.DLTracker.sendUpdate(d.Video)
 
 	// Execute the video download
-	return d.executeVideoDownload(cmd)
+	return d.executeVideoDownload(cmd, f)
 }
@@ -20,6 +20,7 @@ const (
 type Options struct {
 	MaxRetries    int
 	RetryInterval time.Duration
+	MaxBytes      int64 // Room left in the channel's max total size, 0 for no limit
 }
 
 // DefaultOptions provides sensible defaults.
@@ -36,4 +37,7 @@ type Download struct {
 	DLTracker *DownloadTracker
 	Options   Options
 	Context   context.Context
+
+	errLine string                  // Last error line from the download backend, only read after the output scanner finishes
+	cancel  context.CancelCauseFunc // Cancels this download alone, set while it runs
 }
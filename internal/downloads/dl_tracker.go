@@ -44,15 +44,23 @@ func (t *DownloadTracker) Stop() {
 	close(t.done)
 }
 
-// sendUpdate constructs the update and sends it into the processing channel.
+// sendUpdate constructs the update, publishes it to progress subscribers, and sends it into the processing channel.
 func (t *DownloadTracker) sendUpdate(v *models.Video) {
-	t.updates <- models.StatusUpdate{
-		VideoID:  v.ID,
-		VideoURL: v.URL,
-		Status:   v.DownloadStatus.Status,
-		Percent:  v.DownloadStatus.Pct,
-		Error:    v.DownloadStatus.Error,
+	update := models.StatusUpdate{
+		VideoID:      v.ID,
+		ChannelID:    v.ChannelID,
+		VideoURL:     v.URL,
+		Status:       v.DownloadStatus.Status,
+		Percent:      v.DownloadStatus.Pct,
+		Error:        v.DownloadStatus.Error,
+		CancelReason: v.DownloadStatus.CancelReason,
+		FailReason:   v.DownloadStatus.FailReason,
+		Partial:      v.DownloadStatus.Partial,
+		Speed:        v.DownloadStatus.Speed,
+		ETA:          v.DownloadStatus.ETA,
 	}
+	publishProgress(update)
+	t.updates <- update
 }
 
 // processUpdates processes download status updates.
@@ -65,14 +73,23 @@ func (t *DownloadTracker) processUpdates(ctx context.Context) {
 	for {
 		select {
 		case <-t.done:
-			if lastUpdate.VideoID != 0 {
-				t.flushUpdates(ctx, []models.StatusUpdate{lastUpdate})
+			// Drain any updates still queued (e.g. cancellations during shutdown)
+			for drained := false; !drained; {
+				select {
+				case update := <-t.updates:
+					newUpdate = update
+				default:
+					drained = true
+				}
+			}
+			if newUpdate.VideoID != 0 && newUpdate.Differs(lastUpdate) {
+				t.flushUpdates(ctx, []models.StatusUpdate{newUpdate})
 			}
 			return
 		case update := <-t.updates:
 			newUpdate = update
 		case <-ticker.C:
-			if newUpdate != lastUpdate {
+			if newUpdate.Differs(lastUpdate) {
 				lastUpdate = newUpdate
 
 				logging.I("Status update for video with URL %q:\nStatus: %s\nPercentage: %.1f\nError: %v",
@@ -90,8 +107,8 @@ func (t *DownloadTracker) flushUpdates(ctx context.Context, updates []models.Sta
 		return
 	}
 
-	// Add context with timeout
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	// Add context with timeout (detached from cancellation so final statuses are still written on shutdown)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
 	defer cancel()
 
 	// Retry logic for transient failures
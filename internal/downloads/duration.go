@@ -0,0 +1,95 @@
+package downloads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	ffprobeTimeout   = 30 * time.Second
+	durationTolerPct = 0.02 // Allowed shortfall as a fraction of the metadata duration
+)
+
+// TruncatedError is returned when the downloaded file is shorter than the metadata duration.
+type TruncatedError struct {
+	Path     string
+	Expected float64
+	Actual   float64
+}
+
+// Error implements the error interface.
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("video %q appears truncated: duration %.1fs, metadata reports %.1fs", e.Path, e.Actual, e.Expected)
+}
+
+// verifyVideoDuration compares the file duration from ffprobe against the metadata duration.
+//
+// The check is skipped if ffprobe is not installed, the metadata has no duration, or the tolerance is negative.
+func (d *Download) verifyVideoDuration() error {
+	toleranceSecs := cfg.GetFloat64(keys.DurationTolerance)
+	if toleranceSecs < 0 {
+		return nil
+	}
+
+	expected, ok := d.Video.MetadataMap["duration"].(float64)
+	if !ok || expected <= 0 {
+		logging.D(2, "No metadata duration for %q, skipping duration check", d.Video.URL)
+		return nil
+	}
+
+	if _, err := exec.LookPath("ffprobe"); err != nil {
+		logging.D(1, "ffprobe not found in $PATH, skipping duration check for %q", d.Video.VideoPath)
+		return nil
+	}
+
+	actual, err := probeDuration(d.Context, d.Video.VideoPath)
+	if err != nil {
+		logging.E(0, "Could not check duration of %q: %v", d.Video.VideoPath, err)
+		return nil
+	}
+
+	// Segments cut by SponsorBlock shorten the file on purpose
+	if removed := removedDuration(d.Video); removed > 0 {
+		logging.D(1, "Expecting %q to be %.1fs shorter after SponsorBlock removals", d.Video.VideoPath, removed)
+		expected -= removed
+	}
+
+	allowed := math.Max(toleranceSecs, expected*durationTolerPct)
+	if expected-actual > allowed {
+		return &TruncatedError{Path: d.Video.VideoPath, Expected: expected, Actual: actual}
+	}
+
+	logging.D(1, "Duration check passed for %q (file %.1fs, metadata %.1fs)", d.Video.VideoPath, actual, expected)
+	return nil
+}
+
+// probeDuration returns the container duration of a media file in seconds.
+func probeDuration(ctx context.Context, path string) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, ffprobeTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path).Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	s := strings.TrimSpace(string(out))
+	if s == "" || s == "N/A" {
+		return 0, errors.New("ffprobe reported no duration")
+	}
+	return strconv.ParseFloat(s, 64)
+}
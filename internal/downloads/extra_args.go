@@ -0,0 +1,141 @@
+package downloads
+
+import (
+	"strings"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/shellwords"
+)
+
+// repeatableFlags are yt-dlp flags which may be entered more than once, each occurrence adding a value.
+var repeatableFlags = map[string]struct{}{
+	"--add-header":               {},
+	"--postprocessor-args":       {},
+	"--ppa":                      {},
+	"--downloader-args":          {},
+	"--external-downloader-args": {},
+	"--extractor-args":           {},
+	"--use-postprocessor":        {},
+	"--parse-metadata":           {},
+	"--replace-in-metadata":      {},
+	"--match-filters":            {},
+	"--exec":                     {},
+	"--print":                    {},
+	"--print-to-file":            {},
+	"--output":                   {},
+	"-o":                         {},
+	"--paths":                    {},
+	"-P":                         {},
+	"--compat-options":           {},
+}
+
+// argGroup is a yt-dlp flag followed by any values.
+type argGroup struct {
+	flag string
+	args []string
+}
+
+// appendExtraArgs layers the global and channel extra yt-dlp arguments onto the built arguments.
+//
+// Channel arguments take precedence over global ones for the same flag, except repeatable flags
+// which keep the values of both. Flags already set by Tubarr are not overridden.
+func appendExtraArgs(args []string, channelArgs string) []string {
+	merged := mergeExtraArgs(cfg.GetString(keys.YTDLPExtraArgs), channelArgs)
+	if len(merged) == 0 {
+		return args
+	}
+
+	managed := make(map[string]struct{}, len(args))
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			managed[flagName(a)] = struct{}{}
+		}
+	}
+
+	for _, g := range merged {
+		if _, exists := managed[g.flag]; exists {
+			logging.E(0, "Ignoring extra yt-dlp argument %q, this flag is managed by Tubarr settings", g.flag)
+			continue
+		}
+		args = append(args, g.args...)
+	}
+	return args
+}
+
+// mergeExtraArgs combines global and channel argument strings, with channel flags replacing global ones.
+//
+// Repeatable flags such as --add-header are kept from both, global first.
+func mergeExtraArgs(global, channel string) []argGroup {
+	globalGroups := parseArgGroups(global, "global")
+	channelGroups := parseArgGroups(channel, "channel")
+
+	overridden := make(map[string]struct{}, len(channelGroups))
+	for _, g := range channelGroups {
+		if _, ok := repeatableFlags[g.flag]; !ok {
+			overridden[g.flag] = struct{}{}
+		}
+	}
+
+	merged := make([]argGroup, 0, len(globalGroups)+len(channelGroups))
+	for _, g := range globalGroups {
+		if _, ok := overridden[g.flag]; ok {
+			logging.D(1, "Channel extra yt-dlp argument %q overrides the global default", g.flag)
+			continue
+		}
+		merged = append(merged, g)
+	}
+	return append(merged, channelGroups...)
+}
+
+// parseArgGroups splits an argument string into flag groups, keeping the last of any duplicate flags.
+//
+// The string is split like a shell would, so quoted values may hold spaces. Every occurrence of a repeatable flag is kept.
+func parseArgGroups(s, layer string) []argGroup {
+	fields, err := shellwords.Split(s)
+	if err != nil {
+		logging.E(0, "Ignoring %s extra yt-dlp arguments %q: %v", layer, s, err)
+		return nil
+	}
+	groups := make([]argGroup, 0, len(fields))
+	index := make(map[string]int, len(fields))
+
+	for _, f := range fields {
+		// Quoted values such as "-ss 0" may start with a dash, but flags never hold spaces
+		if !strings.HasPrefix(f, "-") || strings.ContainsAny(f, " \t") {
+			if len(groups) == 0 {
+				logging.E(0, "Ignoring %s extra yt-dlp value %q with no preceding flag", layer, f)
+				continue
+			}
+			groups[len(groups)-1].args = append(groups[len(groups)-1].args, f)
+			continue
+		}
+
+		name := flagName(f)
+		if _, ok := repeatableFlags[name]; ok {
+			groups = append(groups, argGroup{flag: name, args: []string{f}})
+			continue
+		}
+		if i, dup := index[name]; dup {
+			logging.E(0, "Duplicate %s extra yt-dlp flag %q, using the last value", layer, name)
+			groups = append(groups[:i], groups[i+1:]...)
+			for k, v := range index {
+				if v > i {
+					index[k] = v - 1
+				}
+			}
+		}
+		index[name] = len(groups)
+		groups = append(groups, argGroup{flag: name, args: []string{f}})
+	}
+	return groups
+}
+
+// flagName returns the flag without any inline "=value".
+func flagName(arg string) string {
+	if i := strings.IndexRune(arg, '='); i >= 0 {
+		return arg[:i]
+	}
+	return arg
+}
@@ -0,0 +1,213 @@
+package downloads
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"tubarr/internal/domain/cmdvideo"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/errconsts"
+	"tubarr/internal/downloads/downloaders"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// fetcher is a backend program which downloads a video's media files.
+type fetcher interface {
+	// name returns the backend's program name.
+	name() string
+	// command builds the download command, limited to rate bytes per second if non-zero.
+	command(d *Download, rate int64) *exec.Cmd
+	// scanLine parses a line of command output, returning progress (0 if none), any completed file path,
+	// and whether the download is finished.
+	scanLine(line string) (pct float64, path string, done bool)
+	// isErrLine reports whether an output line is an error message.
+	isErrLine(line string) bool
+	// checkDuration reports whether the downloaded file should match the metadata duration.
+	checkDuration() bool
+}
+
+// newFetcher returns the download backend for the video's URL, yt-dlp by default.
+func newFetcher(d *Download) fetcher {
+	switch d.Video.Settings.FetcherFor(d.Video.URL) {
+	case consts.FetcherGalleryDL:
+		return &galleryDLFetcher{}
+	case consts.FetcherStreamlink:
+		return &streamlinkFetcher{}
+	default:
+		return &ytdlpFetcher{video: d.Video, downloader: d.DLTracker.downloader}
+	}
+}
+
+// FetchError is a failed download backend run, holding the last error line the backend printed.
+type FetchError struct {
+	Fetcher string
+	Line    string
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *FetchError) Error() string {
+	format := errconsts.YTDLPFailure
+	switch e.Fetcher {
+	case consts.FetcherGalleryDL:
+		format = errconsts.GalleryDLFailure
+	case consts.FetcherStreamlink:
+		format = errconsts.StreamlinkFailure
+	}
+	if e.Line != "" {
+		return fmt.Errorf(format+"\n%s", e.Err, e.Line).Error()
+	}
+	return fmt.Errorf(format, e.Err).Error()
+}
+
+// Unwrap returns the underlying command error.
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// failLinePatterns classify backend error lines, checked in order against the lowercased line.
+//
+// Status codes are only matched as HTTP errors, so numbers in IDs, titles, or URLs aren't mistaken for them.
+var failLinePatterns = []struct {
+	reason   consts.FailReason
+	contains []string
+}{
+	{consts.FailRateLimited, append(httpStatusSigns(429), "too many requests", "rate limit")},
+	{consts.FailAuth, append(append(httpStatusSigns(401), httpStatusSigns(403)...), "sign in", "log in", "login", "private", "members-only", "authentication")},
+	{consts.FailUnavailable, append(httpStatusSigns(404), "not found", "unavailable", "removed", "deleted")},
+}
+
+// httpStatusSigns returns the ways yt-dlp, gallery-dl, and streamlink report an HTTP status code, lowercased.
+func httpStatusSigns(code int) []string {
+	c := strconv.Itoa(code)
+	return []string{"http error " + c, "status " + c, "status code " + c, c + " client error", "'" + c + " "}
+}
+
+// classifyErrLine returns the failure reason matching a backend error line, if any.
+func classifyErrLine(line string) consts.FailReason {
+	line = strings.ToLower(line)
+	for _, p := range failLinePatterns {
+		for _, c := range p.contains {
+			if strings.Contains(line, c) {
+				return p.reason
+			}
+		}
+	}
+	return ""
+}
+
+const (
+	ytdlpDestPrefix = "[download] Destination: "
+)
+
+// ytdlpFragRx matches the fragment progress yt-dlp prints for fragmented downloads, e.g. "(frag 12/27)".
+var ytdlpFragRx = regexp.MustCompile(`\(frag (\d+)/(\d+)\)`)
+
+// ytdlpProgressRx matches yt-dlp's own progress lines, e.g. "[download]  12.3% of ~10.00MiB at 1.20MiB/s ETA 00:05".
+var ytdlpProgressRx = regexp.MustCompile(`^\[download\]\s+(\d+(?:\.\d+)?)%(?:.*?\bat\s+(\S+))?(?:.*?\bETA\s+(\S+))?`)
+
+// ytdlpFetcher downloads videos with yt-dlp.
+type ytdlpFetcher struct {
+	video                      *models.Video
+	downloader                 string
+	totalFrags, completedFrags int
+}
+
+// name returns the backend's program name.
+func (f *ytdlpFetcher) name() string {
+	return consts.FetcherYTDLP
+}
+
+// command builds the yt-dlp video download command.
+func (f *ytdlpFetcher) command(d *Download, rate int64) *exec.Cmd {
+	return d.buildVideoCommand(rate)
+}
+
+// scanLine parses download progress, SponsorBlock segments, and the moved file path printed by yt-dlp.
+func (f *ytdlpFetcher) scanLine(line string) (pct float64, path string, done bool) {
+	if strings.HasPrefix(line, cmdvideo.SponsorBlockPrefix) {
+		recordSponsorSegments(f.video, line)
+		return 0, "", false
+	}
+
+	switch f.downloader {
+
+	// Aria2c
+	case consts.DownloaderAria:
+		var err error
+		f.totalFrags, f.completedFrags, pct, err = downloaders.Aria2OutputParser(line, f.video.URL, f.totalFrags, f.completedFrags)
+		if err != nil {
+			logging.E(0, "Could not parse Aria2 output line %q: %v", line, err)
+		}
+		if f.totalFrags > 0 {
+			f.video.DownloadStatus.Partial.FragsDone = f.completedFrags
+			f.video.DownloadStatus.Partial.FragsTotal = f.totalFrags
+		}
+	default:
+		pct = f.scanProgress(line)
+	}
+	f.scanPartial(line)
+
+	// Check for completed file path
+	if strings.HasPrefix(line, "/") {
+		ext := filepath.Ext(line)
+		for _, validExt := range consts.AllVidExtensions {
+			if ext == validExt {
+				return pct, line, true
+			}
+		}
+	}
+	return pct, "", false
+}
+
+// scanProgress parses yt-dlp's own progress lines, recording the speed and time remaining.
+//
+// Progress is held below 100, since separately downloaded formats are merged afterwards.
+func (f *ytdlpFetcher) scanProgress(line string) float64 {
+	m := ytdlpProgressRx.FindStringSubmatch(line)
+	if m == nil {
+		return 0
+	}
+	pct, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0
+	}
+	f.video.DownloadStatus.Speed = knownProgress(m[2])
+	f.video.DownloadStatus.ETA = knownProgress(m[3])
+	return min(pct, 99.9)
+}
+
+// knownProgress returns the progress value, blank if yt-dlp reported it as unknown.
+func knownProgress(v string) string {
+	if strings.HasPrefix(v, "Unknown") {
+		return ""
+	}
+	return v
+}
+
+// scanPartial records the file yt-dlp is writing and its fragment progress, so an interrupted download can be resumed.
+func (f *ytdlpFetcher) scanPartial(line string) {
+	if path, ok := strings.CutPrefix(line, ytdlpDestPrefix); ok {
+		f.video.DownloadStatus.Partial = models.PartialDownload{Path: strings.TrimSpace(path)}
+		return
+	}
+	if m := ytdlpFragRx.FindStringSubmatch(line); m != nil {
+		f.video.DownloadStatus.Partial.FragsDone, _ = strconv.Atoi(m[1])
+		f.video.DownloadStatus.Partial.FragsTotal, _ = strconv.Atoi(m[2])
+	}
+}
+
+// isErrLine reports whether the line is a yt-dlp "ERROR:" line.
+func (f *ytdlpFetcher) isErrLine(line string) bool {
+	return strings.HasPrefix(line, ytdlpErrPrefix)
+}
+
+// checkDuration reports true, yt-dlp downloads are videos.
+func (f *ytdlpFetcher) checkDuration() bool {
+	return true
+}
@@ -0,0 +1,85 @@
+package downloads
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"tubarr/internal/domain/cmdvideo"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/proxy"
+)
+
+const (
+	galleryDLErrTag = "][error]"
+)
+
+// galleryDLFetcher downloads image and gallery posts with gallery-dl.
+//
+// A post may hold several files, the last one written is kept as the video path.
+type galleryDLFetcher struct{}
+
+// name returns the backend's program name.
+func (f *galleryDLFetcher) name() string {
+	return consts.FetcherGalleryDL
+}
+
+// command builds the gallery-dl download command.
+//
+// yt-dlp specific settings such as extra arguments and external downloaders are not applied.
+func (f *galleryDLFetcher) command(d *Download, rate int64) *exec.Cmd {
+	args := make([]string, 0, 16)
+	args = append(args, cmdvideo.GalleryDLDest, d.Video.VideoDir)
+
+	if d.Video.CookiePath == "" {
+		if d.Video.Settings.CookieSource != "" {
+			args = append(args, cmdvideo.CookieSource, d.Video.Settings.CookieSource)
+		}
+	} else {
+		args = append(args, cmdvideo.CookiePath, d.Video.CookiePath)
+	}
+
+	if d.Video.Settings.MaxFilesize != "" {
+		args = append(args, cmdvideo.GalleryDLMaxFilesize, d.Video.Settings.MaxFilesize)
+	}
+
+	if p := proxy.Pick(d.Video.Settings.Proxies); p != "" {
+		args = append(args, cmdvideo.GalleryDLProxy, p)
+	}
+
+	if rate > 0 {
+		args = append(args, cmdvideo.LimitRate, strconv.FormatInt(rate, 10))
+		logging.D(1, "Limiting download of %q to %s", d.Video.URL, parsing.FormatRate(rate))
+	}
+
+	if d.Video.Settings.Retries != 0 {
+		args = append(args, cmdvideo.Retries, strconv.Itoa(d.Video.Settings.Retries))
+	}
+
+	args = append(args, cmdvideo.GalleryDLSleep, cmdvideo.SleepRequestsNum)
+	args = append(args, d.Video.URL)
+
+	cmd := exec.CommandContext(d.Context, cmdvideo.GalleryDL, args...)
+	logging.D(1, "Built gallery-dl download command for URL %q:\n%v", d.Video.URL, cmd.String())
+	return cmd
+}
+
+// scanLine records each file path gallery-dl prints, skipped files are prefixed with "# ".
+func (f *galleryDLFetcher) scanLine(line string) (pct float64, path string, done bool) {
+	if strings.HasPrefix(line, "/") {
+		return 0, line, false
+	}
+	return 0, "", false
+}
+
+// isErrLine reports whether the line is a gallery-dl "[category][error]" line.
+func (f *galleryDLFetcher) isErrLine(line string) bool {
+	return strings.Contains(line, galleryDLErrTag)
+}
+
+// checkDuration reports false, gallery posts are often images with no duration.
+func (f *galleryDLFetcher) checkDuration() bool {
+	return false
+}
@@ -9,6 +9,8 @@ import (
 
 	"tubarr/internal/domain/cmdjson"
 	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/proxy"
+	"tubarr/internal/utils/ytdlp"
 )
 
 // buildJSONCommand builds and returns the argument for downloading metadata files for the given URL.
@@ -33,6 +35,10 @@ func (d *Download) buildJSONCommand() *exec.Cmd {
 		args = append(args, cmdjson.MaxFilesize, d.Video.Settings.MaxFilesize)
 	}
 
+	if p := proxy.Pick(d.Video.Settings.Proxies); p != "" {
+		args = append(args, cmdjson.Proxy, p)
+	}
+
 	if d.Video.Settings.ExternalDownloader != "" {
 		args = append(args, cmdjson.ExternalDLer, d.Video.Settings.ExternalDownloader)
 
@@ -45,15 +51,30 @@ func (d *Download) buildJSONCommand() *exec.Cmd {
 		args = append(args, cmdjson.Retries, strconv.Itoa(d.Video.Settings.Retries))
 	}
 
-	args = append(args, cmdjson.RestrictFilenames, cmdjson.Output, cmdjson.FilenameSyntax,
-		d.Video.URL)
+	args = append(args, cmdjson.RestrictFilenames, cmdjson.Output, cmdjson.FilenameSyntax)
+	args = appendExtraArgs(args, d.Video.Settings.YTDLPExtraArgs)
+	args = append(args, d.Video.URL)
 
-	cmd := exec.CommandContext(d.Context, cmdjson.YTDLP, args...)
+	cmd := ytdlp.Command(d.Context, args...)
 	logging.D(1, "Built metadata download command for URL %q:\n%v", d.Video.URL, cmd.String())
 
 	return cmd
 }
 
+// ytdlpDateRangeMsg is printed by yt-dlp in place of the metadata for videos outside --dateafter or --datebefore.
+const ytdlpDateRangeMsg = "upload date is not in range"
+
+// DateRangeError is returned when yt-dlp leaves a video out for its upload date, from date window arguments.
+type DateRangeError struct {
+	URL    string
+	Detail string // yt-dlp's message, e.g. "20230101 upload date is not in range 20240101 - 99991231"
+}
+
+// Error implements the error interface.
+func (e *DateRangeError) Error() string {
+	return fmt.Sprintf("yt-dlp skipped %s: %s", e.URL, e.Detail)
+}
+
 // executeJSONDownload executes a JSON download command.
 func (d *Download) executeJSONDownload(cmd *exec.Cmd) error {
 	if cmd == nil {
@@ -65,6 +86,14 @@ func (d *Download) executeJSONDownload(cmd *exec.Cmd) error {
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
+
+	// Checked before the exit status, which is an error with --break-on-reject
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if strings.Contains(line, ytdlpDateRangeMsg) {
+			return &DateRangeError{URL: d.Video.URL, Detail: strings.TrimSpace(strings.TrimPrefix(line, "[download]"))}
+		}
+	}
+
 	if err != nil {
 		return fmt.Errorf("yt-dlp error for %s: %w\nStderr: %s", d.Video.URL, err, stderr.String())
 	}
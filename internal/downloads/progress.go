@@ -0,0 +1,51 @@
+package downloads
+
+import (
+	"sync"
+
+	"tubarr/internal/models"
+)
+
+// progressSubBuffer is how many updates a slow subscriber may fall behind before updates are dropped for it.
+const progressSubBuffer = 64
+
+// progressHub fans download status updates out to live subscribers, such as the HTTP progress stream.
+type progressHub struct {
+	mu   sync.Mutex
+	subs map[chan models.StatusUpdate]struct{}
+}
+
+var progress = progressHub{subs: make(map[chan models.StatusUpdate]struct{})}
+
+// SubscribeProgress returns a channel receiving every download status update, and a function ending the subscription.
+//
+// Updates are dropped rather than blocking downloads when the subscriber falls behind.
+func SubscribeProgress() (updates <-chan models.StatusUpdate, unsubscribe func()) {
+	ch := make(chan models.StatusUpdate, progressSubBuffer)
+
+	progress.mu.Lock()
+	progress.subs[ch] = struct{}{}
+	progress.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			progress.mu.Lock()
+			delete(progress.subs, ch)
+			progress.mu.Unlock()
+		})
+	}
+}
+
+// publishProgress sends the update to all subscribers without waiting on any of them.
+func publishProgress(u models.StatusUpdate) {
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+
+	for ch := range progress.subs {
+		select {
+		case ch <- u:
+		default:
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package downloads
+
+import (
+	"encoding/json"
+	"strings"
+
+	"tubarr/internal/domain/cmdvideo"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	sponsorBlockMetaKey = "sponsorblock_removed"
+)
+
+// sponsorSegment is an entry of yt-dlp's "sponsorblock_chapters" field.
+type sponsorSegment struct {
+	Category string  `json:"category"`
+	Start    float64 `json:"start_time"`
+	End      float64 `json:"end_time"`
+}
+
+// recordSponsorSegments stores the SponsorBlock segments removed from a video in its metadata.
+//
+// yt-dlp lists the segments of every requested category, so marked-only segments are dropped.
+func recordSponsorSegments(v *models.Video, line string) {
+	var segments []sponsorSegment
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, cmdvideo.SponsorBlockPrefix)), &segments); err != nil {
+		logging.D(1, "No SponsorBlock segments parsed for %q: %v", v.URL, err)
+		return
+	}
+
+	removed := make([]sponsorSegment, 0, len(segments))
+	for _, seg := range segments {
+		if seg.End > seg.Start && sponsorBlockRemoves(v.Settings.SponsorBlockRemove, seg.Category) {
+			removed = append(removed, seg)
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	if v.MetadataMap == nil {
+		v.MetadataMap = make(map[string]any)
+	}
+	v.MetadataMap[sponsorBlockMetaKey] = removed
+	logging.I("Removed %d SponsorBlock segment(s) from %q", len(removed), v.URL)
+}
+
+// sponsorBlockRemoves reports whether a category is selected by a yt-dlp SponsorBlock category list.
+//
+// Later entries take precedence, e.g. "all,-filler" removes everything but filler.
+func sponsorBlockRemoves(categories, category string) bool {
+	selected := false
+	for _, c := range strings.Split(categories, ",") {
+		c = strings.TrimSpace(c)
+		exclude := strings.HasPrefix(c, "-")
+		c = strings.TrimPrefix(c, "-")
+
+		switch {
+		case c == category, c == "all", c == "default" && category != "filler":
+			selected = !exclude
+		}
+	}
+	return selected
+}
+
+// removedDuration returns the total length in seconds of the SponsorBlock segments removed from a video.
+func removedDuration(v *models.Video) float64 {
+	segments, ok := v.MetadataMap[sponsorBlockMetaKey].([]sponsorSegment)
+	if !ok {
+		return 0
+	}
+	var total float64
+	for _, seg := range segments {
+		total += seg.End - seg.Start
+	}
+	return total
+}
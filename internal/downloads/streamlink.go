@@ -0,0 +1,98 @@
+package downloads
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"tubarr/internal/domain/cmdvideo"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/proxy"
+)
+
+const (
+	streamlinkExt       = ".ts"
+	streamlinkErrPrefix = "error:"
+	streamlinkErrTag    = "][error]"
+)
+
+// streamlinkFetcher records livestreams and VODs with streamlink, in the best available quality.
+//
+// streamlink has no filename templates matching yt-dlp's, so the output path is built from the video title.
+type streamlinkFetcher struct {
+	path string
+}
+
+// name returns the backend's program name.
+func (f *streamlinkFetcher) name() string {
+	return consts.FetcherStreamlink
+}
+
+// command builds the streamlink download command.
+//
+// yt-dlp specific settings such as extra arguments, cookies, and rate limits are not applied.
+func (f *streamlinkFetcher) command(d *Download, rate int64) *exec.Cmd {
+	f.path = filepath.Join(d.Video.VideoDir, streamlinkFilename(d.Video.Title, d.Video.ID))
+
+	args := make([]string, 0, 12)
+	args = append(args, cmdvideo.StreamlinkOutput, f.path, cmdvideo.StreamlinkForce)
+
+	if p := proxy.Pick(d.Video.Settings.Proxies); p != "" {
+		args = append(args, cmdvideo.StreamlinkHTTPProxy, p)
+	}
+
+	if d.Video.Settings.Retries != 0 {
+		args = append(args, cmdvideo.StreamlinkRetryOpen, strconv.Itoa(d.Video.Settings.Retries))
+	}
+
+	if rate > 0 {
+		logging.D(1, "streamlink has no rate limit, ignoring the rate limit for %q", d.Video.URL)
+	}
+
+	args = append(args, d.Video.URL, cmdvideo.StreamlinkQualityBest)
+
+	cmd := exec.CommandContext(d.Context, cmdvideo.Streamlink, args...)
+	logging.D(1, "Built streamlink download command for URL %q:\n%v", d.Video.URL, cmd.String())
+	return cmd
+}
+
+// scanLine returns the output path once streamlink reports writing to it.
+//
+// The file is complete when streamlink exits, so scanning continues until then.
+func (f *streamlinkFetcher) scanLine(line string) (pct float64, path string, done bool) {
+	if strings.Contains(line, cmdvideo.StreamlinkWritingOutput) {
+		return 0, f.path, false
+	}
+	return 0, "", false
+}
+
+// isErrLine reports whether the line is a streamlink "error:" or "[cli][error]" line.
+func (f *streamlinkFetcher) isErrLine(line string) bool {
+	return strings.HasPrefix(line, streamlinkErrPrefix) || strings.Contains(line, streamlinkErrTag)
+}
+
+// checkDuration reports false, recorded livestreams have no fixed duration to compare.
+func (f *streamlinkFetcher) checkDuration() bool {
+	return false
+}
+
+// streamlinkFilename returns a filename from the video title, restricted to the characters yt-dlp's
+// --restrict-filenames keeps.
+func streamlinkFilename(title string, id int64) string {
+	var b strings.Builder
+	for _, r := range title {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	name := strings.Trim(b.String(), "_.")
+	if name == "" {
+		name = "stream_" + strconv.FormatInt(id, 10)
+	}
+	return name + streamlinkExt
+}
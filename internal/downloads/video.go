@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -13,26 +14,43 @@ import (
 
 	"tubarr/internal/domain/cmdvideo"
 	"tubarr/internal/domain/consts"
-	"tubarr/internal/domain/errconsts"
-	"tubarr/internal/downloads/downloaders"
 	"tubarr/internal/models"
+	"tubarr/internal/parsing"
 	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/proxy"
+	"tubarr/internal/utils/ytdlp"
 )
 
 const (
 	ariaBase = len(consts.DownloaderAria) + len(": ") + len(cmdvideo.AriaLog)
 )
 
-// buildVideoCommand builds the command to download a video using yt-dlp.
-func (d *Download) buildVideoCommand() *exec.Cmd {
+// buildVideoCommand builds the command to download a video using yt-dlp, limited to the given rate if non-zero.
+func (d *Download) buildVideoCommand(rate int64) *exec.Cmd {
 	args := make([]string, 0, 32)
 
 	args = append(args,
 		cmdvideo.RestrictFilenames,
 		cmdvideo.Output, filepath.Join(d.Video.VideoDir, cmdvideo.FilenameSyntax))
 
+	// Printed before the file path, which ends output scanning
+	if d.Video.Settings.SponsorBlockRemove != "" {
+		args = append(args, cmdvideo.SponsorBlockRemove, d.Video.Settings.SponsorBlockRemove)
+		args = append(args, cmdvideo.Print, cmdvideo.SponsorBlockPrint)
+	}
+	if d.Video.Settings.SponsorBlockMark != "" {
+		args = append(args, cmdvideo.SponsorBlockMark, d.Video.Settings.SponsorBlockMark)
+	}
+
 	args = append(args, cmdvideo.Print, cmdvideo.AfterMove)
 
+	// Progress is quieted by --print, and printed on one line per update so it can be scanned
+	args = append(args, cmdvideo.Progress, cmdvideo.Newline)
+
+	if d.Video.Live {
+		args = append(args, cmdvideo.LiveFromStart)
+	}
+
 	if d.Video.CookiePath == "" {
 		if d.Video.Settings.CookieSource != "" {
 			args = append(args, cmdvideo.CookieSource, d.Video.Settings.CookieSource)
@@ -41,28 +59,47 @@ func (d *Download) buildVideoCommand() *exec.Cmd {
 		args = append(args, cmdvideo.CookiePath, d.Video.CookiePath)
 	}
 
+	if format := parsing.YTDLPFormat(d.Video.Settings.MaxResolution, d.Video.Settings.PreferredCodec, d.Video.Settings.AudioOnly); format != "" {
+		args = append(args, cmdvideo.Format, format)
+	}
+
 	if d.Video.Settings.MaxFilesize != "" {
 		args = append(args, cmdvideo.MaxFilesize, d.Video.Settings.MaxFilesize)
 	}
 
+	if p := proxy.Pick(d.Video.Settings.Proxies); p != "" {
+		args = append(args, cmdvideo.Proxy, p)
+	}
+
+	if rate > 0 {
+		args = append(args, cmdvideo.LimitRate, strconv.FormatInt(rate, 10))
+		logging.D(1, "Limiting download of %q to %s", d.Video.URL, parsing.FormatRate(rate))
+	}
+
+	if d.Video.Settings.ConcurrentFragments > 0 {
+		args = append(args, cmdvideo.ConcurrentFrags, strconv.Itoa(d.Video.Settings.ConcurrentFragments))
+	}
+
 	if d.Video.Settings.ExternalDownloader != "" {
 		args = append(args, cmdvideo.ExternalDLer, d.Video.Settings.ExternalDownloader)
-		if d.Video.Settings.ExternalDownloaderArgs != "" {
+
+		extArgs := withConnectionArgs(d.Video.Settings.ExternalDownloader, d.Video.Settings.ExternalDownloaderArgs, d.Video.Settings.ExternalDLConnections)
+		if extArgs != "" {
 
 			switch d.Video.Settings.ExternalDownloader {
 			case consts.DownloaderAria:
 				var b strings.Builder
 
-				b.Grow(ariaBase + len(d.Video.Settings.ExternalDownloaderArgs))
+				b.Grow(ariaBase + len(extArgs))
 				b.WriteString(consts.DownloaderAria)
 				b.WriteRune(':')
-				b.WriteString(d.Video.Settings.ExternalDownloaderArgs) // "aria2c:-x 16 -s 16 --console-log-level=info"
+				b.WriteString(extArgs) // "aria2c:-x 16 -s 16 --console-log-level=info"
 				b.WriteRune(' ')
 				b.WriteString(cmdvideo.AriaLog)
 
 				args = append(args, cmdvideo.ExternalDLArgs, b.String())
 			default:
-				args = append(args, cmdvideo.ExternalDLArgs, d.Video.Settings.ExternalDownloaderArgs)
+				args = append(args, cmdvideo.ExternalDLArgs, extArgs)
 			}
 		}
 	}
@@ -71,16 +108,61 @@ func (d *Download) buildVideoCommand() *exec.Cmd {
 		args = append(args, cmdvideo.Retries, strconv.Itoa(d.Video.Settings.Retries))
 	}
 
-	args = append(args, cmdvideo.SleepRequests, cmdvideo.SleepRequestsNum, d.Video.URL)
+	args = append(args, cmdvideo.SleepRequests, cmdvideo.SleepRequestsNum)
+	args = appendExtraArgs(args, d.Video.Settings.YTDLPExtraArgs)
+	args = append(args, d.Video.URL)
 
-	cmd := exec.CommandContext(d.Context, cmdvideo.YTDLP, args...)
+	cmd := ytdlp.Command(d.Context, args...)
 	logging.D(1, "Built video download command for URL %q:\n%v", d.Video.URL, cmd.String())
 
 	return cmd
 }
 
-// executeVideoDownload executes a video download command.
-func (d *Download) executeVideoDownload(cmd *exec.Cmd) error {
+// withConnectionArgs prepends the external downloader's connection count flags, unless already set in the user's args.
+func withConnectionArgs(downloader, extArgs string, connections int) string {
+	if connections <= 0 {
+		return extArgs
+	}
+
+	var flags []string
+	switch downloader {
+	case consts.DownloaderAria:
+		flags = []string{"-x", "-s", "--max-connection-per-server", "--split"}
+	case consts.DownloaderAxel:
+		flags = []string{"-n", "--num-connections"}
+	default:
+		return extArgs
+	}
+
+	for _, f := range strings.Fields(extArgs) {
+		for _, flag := range flags {
+			if f == flag || strings.HasPrefix(f, flag+"=") {
+				logging.D(1, "External downloader args already set connections with %q, ignoring connection setting", f)
+				return extArgs
+			}
+		}
+	}
+
+	n := strconv.Itoa(connections)
+	var connArgs string
+	if downloader == consts.DownloaderAria {
+		connArgs = "-x " + n + " -s " + n
+	} else {
+		connArgs = "-n " + n
+	}
+
+	if extArgs == "" {
+		return connArgs
+	}
+	return connArgs + " " + extArgs
+}
+
+const (
+	ytdlpErrPrefix = "ERROR:"
+)
+
+// executeVideoDownload executes a video download command, reading its output with the given backend.
+func (d *Download) executeVideoDownload(cmd *exec.Cmd, f fetcher) error {
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -93,16 +175,17 @@ func (d *Download) executeVideoDownload(cmd *exec.Cmd) error {
 	}
 	filenameChan := make(chan string, 1)
 
-	go d.scanVideoCmdOutput(io.MultiReader(stdout, stderr), filenameChan)
+	go d.scanVideoCmdOutput(io.MultiReader(stdout, stderr), f, filenameChan)
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf(errconsts.YTDLPFailure, err)
+		return &FetchError{Fetcher: f.name(), Err: err}
 	}
 
+	// The scanner reads to the end of the output before Wait closes the pipes
+	filename := <-filenameChan
 	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf(errconsts.YTDLPFailure, err)
+		return &FetchError{Fetcher: f.name(), Line: d.errLine, Err: err}
 	}
 
-	filename := <-filenameChan
 	if filename == "" {
 		return errors.New("no output filename captured")
 	}
@@ -116,18 +199,23 @@ func (d *Download) executeVideoDownload(cmd *exec.Cmd) error {
 		return err
 	}
 
+	if f.checkDuration() {
+		if err := d.verifyVideoDuration(); err != nil {
+			return err
+		}
+	}
+
 	logging.S(0, "Download successful: %s", d.Video.VideoPath)
 	return nil
 }
 
-// scanVideoCmdOutput scans the yt-dlp video download output for relevant information.
-func (d *Download) scanVideoCmdOutput(r io.Reader, filenameChan chan<- string) {
+// scanVideoCmdOutput scans the video download output for progress, errors, and the completed file path.
+func (d *Download) scanVideoCmdOutput(r io.Reader, f fetcher, filenameChan chan<- string) {
 	scanner := bufio.NewScanner(r)
 	var (
-		totalFrags,
-		completedFrags int
 		pct        float64
-		err        error
+		filename   string
+		done       bool
 		lastUpdate = models.StatusUpdate{
 			VideoID:  d.Video.ID,
 			VideoURL: d.Video.URL,
@@ -142,29 +230,38 @@ func (d *Download) scanVideoCmdOutput(r io.Reader, filenameChan chan<- string) {
 
 	for scanner.Scan() {
 		line := scanner.Text()
-		switch d.DLTracker.downloader {
 
-		// Aria2c
-		case consts.DownloaderAria:
-			totalFrags, completedFrags, pct, err = downloaders.Aria2OutputParser(line, d.Video.URL, totalFrags, completedFrags)
-			if err != nil {
-				logging.E(0, "Could not parse Aria2 output line %q: %v", line, err)
-			}
+		// Keep the latest error line for failure diagnosis
+		if f.isErrLine(line) {
+			d.errLine = line
+		}
+
+		// Keep reading once finished, so the backend never blocks on a full pipe
+		if done {
+			continue
 		}
 
-		// Send updates
-		if pct > 0.0 {
+		var path string
+		pct, path, done = f.scanLine(line)
+
+		// Send updates, including partial file changes so interrupted downloads can be resumed
+		if pct > 0.0 || d.Video.DownloadStatus.Partial != lastUpdate.Partial {
+			d.checkMaxBytes()
+			if pct == 0.0 {
+				pct = lastUpdate.Percent
+			}
 			newUpdate := models.StatusUpdate{
 				VideoID:  d.Video.ID,
 				VideoURL: d.Video.URL,
 				Status:   d.Video.DownloadStatus.Status,
 				Percent:  pct,
 				Error:    d.Video.DownloadStatus.Error,
+				Partial:  d.Video.DownloadStatus.Partial,
 			}
 			if pct == 100.0 {
 				newUpdate.Status = consts.DLStatusCompleted
 			}
-			if newUpdate != lastUpdate {
+			if newUpdate.Differs(lastUpdate) {
 				d.Video.DownloadStatus.Status = newUpdate.Status
 				d.Video.DownloadStatus.Pct = newUpdate.Percent
 				d.DLTracker.sendUpdate(d.Video)
@@ -172,20 +269,31 @@ func (d *Download) scanVideoCmdOutput(r io.Reader, filenameChan chan<- string) {
 			}
 		}
 
-		// Check for completed file path
-		if strings.HasPrefix(line, "/") {
-			ext := filepath.Ext(line)
-			for _, validExt := range consts.AllVidExtensions {
-				if ext == validExt {
-					filenameChan <- line
-					return
-				}
-			}
+		if path != "" {
+			filename = path
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
 		logging.E(0, "Scanner error: %v", err)
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			logging.E(0, "Failed to drain download output: %v", err)
+		}
 	}
+	filenameChan <- filename
 	close(filenameChan)
 }
+
+// checkMaxBytes cancels the download once its partial file outgrows the room left in the channel's max total size.
+func (d *Download) checkMaxBytes() {
+	path := d.Video.DownloadStatus.Partial.Path
+	if d.Options.MaxBytes <= 0 || path == "" || d.cancel == nil || d.Context.Err() != nil {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() <= d.Options.MaxBytes {
+		return
+	}
+	logging.W("Stopping download of %q, its %d bytes exceed the %d bytes left in the channel's max total size", d.Video.URL, info.Size(), d.Options.MaxBytes)
+	d.cancel(&CancelError{Reason: consts.CancelQuota})
+}
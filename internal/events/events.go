@@ -0,0 +1,118 @@
+// Package events publishes program events, such as finished downloads, to subscribed handlers.
+//
+// Handlers run asynchronously, so publishing never waits on them. Wait blocks until running handlers finish.
+package events
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Event names.
+const (
+	VideoDownloaded = "video.downloaded"
+	VideoFailed     = "video.failed"
+	ChannelBlocked  = "channel.blocked"
+	CrawlFinished   = "crawl.finished"
+)
+
+// Names returns every event name.
+func Names() []string {
+	return []string{VideoDownloaded, VideoFailed, ChannelBlocked, CrawlFinished}
+}
+
+// Event is a published event. Fields not relevant to the event are left blank.
+type Event struct {
+	Name      string
+	Time      time.Time
+	ChannelID int64
+	Channel   string
+	URL       string // Video URL, or the channel URL for channel events
+	Title     string
+	Path      string // Downloaded video file
+	JSONPath  string // Downloaded metadata file
+	Error     string
+	NewVideos int // For crawl.finished
+	Failures  int // For crawl.finished
+	Filtered  int // For crawl.finished
+}
+
+// Fields returns the event fields available to templates.
+func Fields() []string {
+	return []string{".Name", ".Time", ".ChannelID", ".Channel", ".URL", ".Title", ".Path", ".JSONPath", ".Error", ".NewVideos", ".Failures", ".Filtered"}
+}
+
+// Handler is run for each published event it is subscribed to.
+type Handler func(Event)
+
+var bus = struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	running  sync.WaitGroup
+}{handlers: make(map[string][]Handler)}
+
+// Valid reports whether name is a known event.
+func Valid(name string) bool {
+	return slices.Contains(Names(), name)
+}
+
+// Subscribe runs the handler for each published event with the given name.
+func Subscribe(name string, h Handler) {
+	bus.mu.Lock()
+	bus.handlers[name] = append(bus.handlers[name], h)
+	bus.mu.Unlock()
+}
+
+// Publish runs the event's handlers in the background, setting its time if unset.
+func Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	bus.mu.RLock()
+	handlers := bus.handlers[e.Name]
+	bus.mu.RUnlock()
+
+	for _, h := range handlers {
+		bus.running.Add(1)
+		go func() {
+			defer bus.running.Done()
+			h(e)
+		}()
+	}
+}
+
+// Wait blocks until all running handlers have finished.
+func Wait() {
+	bus.running.Wait()
+}
+
+// Render executes an argument template with the event's fields.
+func Render(tmpl string, e Event) (string, error) {
+	t, err := parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, e); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// CheckTemplate checks an argument template parses and only uses event fields.
+func CheckTemplate(tmpl string) error {
+	if _, err := Render(tmpl, Event{}); err != nil {
+		return fmt.Errorf("invalid hook argument %q: %w", tmpl, err)
+	}
+	return nil
+}
+
+// parse parses an argument template.
+func parse(tmpl string) (*template.Template, error) {
+	return template.New("hook").Option("missingkey=error").Parse(tmpl)
+}
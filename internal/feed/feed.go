@@ -0,0 +1,122 @@
+// Package feed writes RSS feeds of a channel's downloaded videos.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+)
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	GUID        string       `xml:"guid"`
+	PubDate     string       `xml:"pubDate,omitempty"`
+	Description string       `xml:"description,omitempty"`
+	Enclosure   rssEnclosure `xml:"enclosure"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// Write writes the RSS feed for the channel's downloaded videos, newest first, returning the number of items.
+//
+// Enclosures link to the URL returned by link for each video. A limit of 0 includes every video.
+func Write(w io.Writer, vs interfaces.VideoStore, c *models.Channel, limit int, link func(*models.Video) string) (int, error) {
+	var videos []*models.Video
+	if err := vs.StreamChannelVideos(c.ID, func(v *models.Video) error {
+		if v.VideoPath != "" && v.DownloadStatus.Status == consts.DLStatusCompleted {
+			videos = append(videos, v)
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	sort.SliceStable(videos, func(i, j int) bool {
+		return pubTime(videos[i]).After(pubTime(videos[j]))
+	})
+	if limit > 0 && len(videos) > limit {
+		videos = videos[:limit]
+	}
+
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       c.Name,
+			Link:        c.URL,
+			Description: fmt.Sprintf("Videos downloaded by Tubarr from %s", c.URL),
+			Items:       make([]rssItem, 0, len(videos)),
+		},
+	}
+
+	for _, v := range videos {
+		item := rssItem{
+			Title:       v.Title,
+			Link:        v.URL,
+			GUID:        v.URL,
+			Description: v.Description,
+			Enclosure: rssEnclosure{
+				URL:  link(v),
+				Type: mime.TypeByExtension(filepath.Ext(v.VideoPath)),
+			},
+		}
+		if item.Title == "" {
+			item.Title = filepath.Base(v.VideoPath)
+		}
+		if t := pubTime(v); !t.IsZero() {
+			item.PubDate = t.Format(time.RFC1123Z)
+		}
+		if info, err := os.Stat(v.VideoPath); err == nil {
+			item.Enclosure.Length = info.Size()
+		}
+		if item.Enclosure.Type == "" {
+			item.Enclosure.Type = "application/octet-stream"
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return 0, err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return 0, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return len(videos), err
+}
+
+// pubTime returns the upload date, or when Tubarr recorded the video if the upload date is unknown.
+func pubTime(v *models.Video) time.Time {
+	if !v.UploadDate.IsZero() {
+		return v.UploadDate
+	}
+	return v.CreatedAt
+}
@@ -4,14 +4,25 @@ package interfaces
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	"tubarr/internal/domain/consts"
 	"tubarr/internal/models"
 )
 
 // Store allows access to the main store repo methods.
 type Store interface {
+	GetDB() *sql.DB
 	ChannelStore() ChannelStore
+	ConfirmStore() ConfirmStore
 	DownloadStore() DownloadStore
+	HostStore() HostStore
+	PostStore() PostStore
+	RetryStore() RetryStore
+	SkipStore() SkipStore
+	StatsStore() StatsStore
+	StorageStore() StorageStore
+	UserStore() UserStore
 	VideoStore() VideoStore
 }
 
@@ -19,38 +30,187 @@ type Store interface {
 type ChannelStore interface {
 	AddAuth(channelID int64, username, password, loginURL string) error
 	AddChannel(c *models.Channel) (int64, error)
+	AddChannelTags(channelID int64, tags []string) error
+	AddGlobalIgnore(g *models.GlobalIgnore) (added bool, err error)
+	AddHook(h *models.Hook) error
 	AddNotifyURL(id int64, notifyName, notifyURL string) error
-	AddURLToIgnore(channelID int64, ignoreURL string) error
+	AddPreset(p *models.Preset, replace bool) error
+	AddURLToIgnore(channelID int64, ignoreURL string) (caught bool, err error)
+	AddWebhook(h *models.Webhook) error
+	CheckLiveURL(key, val string, s Store, ctx context.Context) error
 	CrawlChannel(key, val string, s Store, ctx context.Context) error
 	CrawlChannelIgnore(key, val string, s Store, ctx context.Context) error
 	DeleteChannel(key, val string) error
+	DeleteGlobalIgnores(ids []int64) error
+	DeleteHooks(names []string) error
 	DeleteVideoURLs(channelID int64, urls []string) error
 	DeleteNotifyURLs(channelID int64, urls, names []string) error
+	DeletePreset(name string) (bool, error)
+	DeleteWebhooks(channelID int64, names []string) error
+	ExportDownloadArchive(key, val, path string, appendTo bool, s Store) error
 	FetchAllChannels() (channels []*models.Channel, err error, hasRows bool)
 	FetchChannel(id int64) (c *models.Channel, err error, hasRows bool)
+	FetchChannelActivity(channelID int64, limit int) ([]*models.ActivityEvent, error)
+	FetchPresets() ([]*models.Preset, error)
+	FetchTagCounts() (map[string]int, error)
+	FetchTaggedChannels(tag string) ([]*models.Channel, error)
 	GetAuth(channelID int64) (username, password, loginURL string, err error)
+	GetChannelTags(channelID int64) ([]string, error)
 	GetDB() *sql.DB
+	GetDefaults() (*models.Defaults, error)
+	GetGlobalIgnores() ([]*models.GlobalIgnore, error)
+	GetHooks(event string) ([]*models.Hook, error)
 	GetID(key, val string) (int64, error)
+	GetNotifications(id int64) ([]*models.Notification, error)
 	GetNotifyURLs(id int64) ([]string, error)
+	GetPreset(name string) (*models.Preset, error)
+	GetSMTPConfig() (*models.SMTPConfig, error)
+	GetStoredSettings(channelID int64) (*models.ChannelSettings, *models.MetarrArgs, error)
+	GetWebhooks(channelID int64) ([]*models.Webhook, error)
+	ImportDownloadArchive(key, val, path string, offline bool, s Store, ctx context.Context) error
+	ImportExistingVideos(key, val, dir string, offline, dryRun bool, s Store, ctx context.Context) error
+	LoadAllVideoURLs(c *models.Channel) (urls []string, err error)
 	LoadGrabbedURLs(c *models.Channel) (urls []string, err error)
+	LoadIgnoredURLs(channelID int64) (urls []string, err error)
+	QueueCommand(key, val string, action consts.CommandAction, urls []string) error
+	RecordChannelEvent(channelID int64, kind consts.ActivityKind, detail string) error
+	RepeatChannelEvent(channelID int64, kind consts.ActivityKind, detail string) error
+	PruneChannelEvents(before time.Time) (int64, error)
+	RedownloadVideos(key, val string, urls []string, deleteFiles bool, s Store, ctx context.Context) error
+	RemoveChannelTags(channelID int64, tags []string) (int64, error)
+	ReprocessChannelMetarr(key, val string, urls []string, s Store, ctx context.Context) error
+	ResetDefaults() error
+	TakePendingCommands() ([]*models.PendingCommand, error)
 	UpdateChannelEntry(chanKey, chanVal, updateKey, updateVal string) error
 	UpdateChannelMetarrArgsJSON(key, val string, updateFn func(*models.MetarrArgs) error) (int64, error)
 	UpdateChannelSettingsJSON(key, val string, updateFn func(*models.ChannelSettings) error) (int64, error)
 	UpdateChannelRow(key, val, col, newVal string) error
+	UpdateDefaultMetarrArgsJSON(updateFn func(*models.MetarrArgs) error) error
+	UpdateDefaultSettingsJSON(updateFn func(*models.ChannelSettings) error) error
 	UpdateLastScan(channelID int64) error
+	UpdateSMTPConfig(updateFn func(*models.SMTPConfig) error) error
+	VerifyChannelComplete(key, val string, enqueue bool, s Store, ctx context.Context) error
+}
+
+// ConfirmStore allows access to destructive operation confirmation methods.
+type ConfirmStore interface {
+	ConsumeConfirmation(token, operation string) (summary string, err error)
+	GetDB() *sql.DB
+	IssueConfirmation(operation, summary string, ttl time.Duration) (token string, err error)
 }
 
 type DownloadStore interface {
+	CancelDownload(videoID int64, reason consts.CancelReason) error
+	ClearPartialDownload(videoID int64) error
+	FetchInterruptedDownloads() ([]*models.InterruptedDownload, error)
 	GetDB() *sql.DB
+	GetDownloadStatus(v *models.Video) error
+	RequeueDownload(videoID int64) error
 	SetDownloadStatus(v *models.Video) error
 	UpdateDownloadStatuses(ctx context.Context, updates []models.StatusUpdate) error
 }
 
+// HostStore allows access to per-hostname statistics and pause methods.
+type HostStore interface {
+	ActivePause(hostname string) (*models.Pause, error)
+	ClearPause(scope string) (bool, error)
+	FetchAllHostStats() ([]*models.HostStats, error)
+	FetchPauses() ([]*models.Pause, error)
+	GetDB() *sql.DB
+	GetHostStats(hostname string) (*models.HostStats, error)
+	RecordResult(hostname string, success, botBlock bool) error
+	SetHostTuning(hostname string, concurrency int, delay time.Duration, blocksSeen int) error
+	SetPause(scope string, until time.Time, reason string) error
+}
+
+// StatsStore allows access to the daily download statistics rollup and crawl reports.
+type StatsStore interface {
+	FetchCrawlReports(limit int) ([]*models.CrawlReport, error)
+	FetchDailyStats(channelID int64, since time.Time) ([]*models.DownloadStats, error)
+	FetchStatsTotals(channelID int64, since time.Time) ([]*models.DownloadStats, error)
+	GetDB() *sql.DB
+	MarkDigestSent(reportID int64) error
+	RecordCrawlReport(r *models.CrawlReport) (int64, error)
+	RecordDownload(channelID int64, at time.Time, success bool, bytes int64, dur time.Duration) error
+}
+
+// UserStore allows access to web users, their login sessions, and the channels they own.
+type UserStore interface {
+	AddUser(username, password, role string) (int64, error)
+	Authenticate(username, password string) (*models.User, error)
+	CanAccessChannel(u *models.User, channelID int64) (bool, error)
+	CanAccessVideo(u *models.User, videoID int64) (bool, error)
+	CreateSession(userID int64, ttl time.Duration) (string, error)
+	DeleteSession(token string) error
+	DeleteUser(username string) error
+	FetchUsers() ([]*models.User, error)
+	FetchVisibleChannels(u *models.User) ([]*models.OwnedChannel, error)
+	GetDB() *sql.DB
+	HasUsers() (bool, error)
+	SessionUser(token string) (*models.User, error)
+	SetChannelOwner(channelID int64, username string) error
+}
+
+// RetryStore allows access to the failed download retry queue.
+type RetryStore interface {
+	ClearRetry(channelID int64, url string) error
+	FetchChannelRetries(channelID int64) ([]*models.RetryEntry, error)
+	FetchDueRetries(at time.Time) ([]*models.RetryEntry, error)
+	GetDB() *sql.DB
+	NextRetryAt(after time.Time) (time.Time, error)
+	QueueRetry(channelID int64, url, lastErr string, maxAttempts int, base time.Duration) (*models.RetryEntry, error)
+}
+
+// PostStore allows access to archived channel post methods.
+type PostStore interface {
+	AddPost(p *models.Post) (added bool, err error)
+	FetchPosts(channelID int64, limit int) ([]*models.Post, error)
+	GetDB() *sql.DB
+	HasPost(channelID int64, postID string) (bool, error)
+}
+
+// SkipStore allows access to skipped video repo methods.
+type SkipStore interface {
+	FetchSkipped(channelID int64) ([]*models.SkippedVideo, error)
+	GetDB() *sql.DB
+	PruneSkipped(before time.Time) (int64, error)
+	RecordSkip(sv *models.SkippedVideo) error
+	RecordSkips(svs []*models.SkippedVideo) error
+}
+
+// StorageStore allows access to cached disk usage repo methods.
+type StorageStore interface {
+	FetchAllChannelStorage() ([]*models.ChannelStorage, error)
+	GetDB() *sql.DB
+	RefreshChannelStorage(channelID int64) (*models.ChannelStorage, error)
+	RewritePaths(from, to string, dryRun, allowMissing bool) (*models.PathRewrite, error)
+}
+
 // VideoStore allows access to video repo methods.
 type VideoStore interface {
 	AddVideo(v *models.Video) (int64, error)
 	AddVideos(videos []*models.Video, c *models.Channel) ([]*models.Video, []error)
+	BulkVideoAction(chanID int64, action consts.BulkAction, ids []int64, urls []string) ([]models.BulkResult, error)
 	GetDB() *sql.DB
 	DeleteVideo(key, val string, chanID int64) error
+	EditVideoMetadata(id int64, e *models.VideoMetadataEdit) (*models.Video, error)
+	FetchChannelTimings(chanID int64) ([]*models.VideoTiming, error)
+	FetchExpiredDownloads(chanID int64, keepLast int, before time.Time) ([]*models.Video, error)
+	FetchOldestDownloads(chanID int64, limit int) ([]*models.Video, error)
+	FetchVideoTiming(videoID int64) (*models.VideoTiming, error)
+	FetchVideosWithPaths() ([]*models.Video, error)
+	GetTranscriptPath(id int64) (string, error)
+	GetVideoID(chanID int64, url string) (int64, error)
+	GetVideoIDs(chanID int64, urls []string) (map[string]int64, error)
+	GetVideoPaths(id int64) (videoPath, jsonPath string, err error)
+	GetVideoURL(id int64) (chanID int64, url string, err error)
+	GetYTDLPVersion(id int64) (string, error)
+	ImportVideos(chanID int64, videos []*models.Video) ([]*models.Video, error)
+	RecordStage(videoID int64, stage consts.PipelineStage, at time.Time) error
+	SearchVideos(q string, chanID int64, limit int) ([]*models.Video, error)
+	SetTranscriptPath(id int64, path string) error
+	SetYTDLPVersion(id int64, version string) error
+	StreamChannelVideos(chanID int64, fn func(v *models.Video) error) error
 	UpdateVideo(v *models.Video) error
+	UpdateVideoPaths(id int64, videoPath, jsonPath string) error
 }
@@ -3,6 +3,7 @@ package metarr
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 
 	"tubarr/internal/cfg"
@@ -37,7 +38,7 @@ const (
 )
 
 // makeMetarrCommand combines arguments from both Viper config and model settings.
-func makeMetarrCommand(v *models.Video) []string {
+func makeMetarrCommand(v *models.Video, resultsPath string) []string {
 
 	fields := []metCmdMapping{
 
@@ -113,6 +114,9 @@ func makeMetarrCommand(v *models.Video) []string {
 
 	argMap[metcmd.VideoFile] = v.VideoPath
 	argMap[metcmd.JSONFile] = v.JSONPath
+	if resultsPath != "" {
+		argMap[metcmd.ResultsFile] = resultsPath
+	}
 
 	// Final args
 	args := make([]string, 0, singlesLen+sliceLen)
@@ -164,24 +168,26 @@ func processField(f metCmdMapping, argMap map[string]string, argSlicesMap map[st
 			argMap[f.cmdKey] = cfg.GetString(f.viperKey)
 		}
 	case strSlice:
-		if len(f.metarrValue.strSlice) > 0 {
-			argSlicesMap[f.cmdKey] = f.metarrValue.strSlice
-		} else if f.viperKey != "" && cfg.IsSet(f.viperKey) {
-			argSlicesMap[f.cmdKey] = cfg.GetStringSlice(f.viperKey)
+		vals := f.metarrValue.strSlice
+		if len(vals) == 0 && f.viperKey != "" && cfg.IsSet(f.viperKey) {
+			vals = cfg.GetStringSlice(f.viperKey)
 		}
 
-		// Set Meta Overwrite flag if meta-ops arguments exist
-		if f.cmdKey == metcmd.MetaOps {
-			elemCount := len(f.metarrValue.strSlice)
-
-			if cfg.IsSet(f.viperKey) {
-				elemCount += len(cfg.GetStringSlice(f.viperKey))
-			}
+		// Regex operations are applied by Tubarr, not passed to Metarr
+		switch f.cmdKey {
+		case metcmd.MetaOps:
+			vals = slices.DeleteFunc(slices.Clone(vals), isMetaRegex)
+		case metcmd.FilenameReplaceSfx:
+			vals = slices.DeleteFunc(slices.Clone(vals), isFilenameRegex)
+		}
+		if len(vals) > 0 {
+			argSlicesMap[f.cmdKey] = vals
+		}
 
-			if elemCount > 0 {
-				logging.I("User set meta ops, will set meta overwrite key...")
-				return true
-			}
+		// Set Meta Overwrite flag if meta-ops arguments exist
+		if f.cmdKey == metcmd.MetaOps && len(vals) > 0 {
+			logging.I("User set meta ops, will set meta overwrite key...")
+			return true
 		}
 	}
 	return false
@@ -213,7 +219,7 @@ func parseOutputDir(v *models.Video) string {
 
 // calcNumElements returns the required map sizes.
 func calcNumElements(fields []metCmdMapping) (singles, slices int) {
-	singleElements := 2 // Start at 2 for VideoFile and JSONFile
+	singleElements := 3 // Start at 3 for VideoFile, JSONFile, and ResultsFile
 	sliceElements := 0
 	for _, f := range fields {
 		switch f.valType {
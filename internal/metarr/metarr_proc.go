@@ -2,17 +2,51 @@ package metarr
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"tubarr/internal/domain/metcmd"
 	"tubarr/internal/models"
 	"tubarr/internal/utils/logging"
 )
 
-// InitMetarr begins processing with Metarr
+const helpTimeout = 10 * time.Second
+
+var (
+	resultsFileOnce      sync.Once
+	resultsFileSupported bool
+)
+
+// metarrResults is the manifest Metarr writes to the results file after processing a video.
+//
+// Holds the final locations of the files, after any renames or moves.
+type metarrResults struct {
+	VideoFile string `json:"video_file"`
+	JSONFile  string `json:"json_file"`
+}
+
+// InitMetarr begins processing with Metarr.
+//
+// Regex meta operations are applied to the JSON file first, and regex renames after Metarr finishes.
+// If the installed Metarr supports a results manifest, the video's paths are updated to the final file locations.
 func InitMetarr(v *models.Video, ctx context.Context) error {
-	args := makeMetarrCommand(v)
+	if err := applyMetaRegex(v); err != nil {
+		return fmt.Errorf("failed to apply regex meta operations: %w", err)
+	}
+
+	var resultsPath string
+	if supportsResultsFile(ctx) {
+		resultsPath = filepath.Join(os.TempDir(), fmt.Sprintf("tubarr-metarr-%d-%d.json", v.ID, time.Now().UnixNano()))
+	}
+
+	args := makeMetarrCommand(v, resultsPath)
 	if len(args) == 0 {
 		logging.I("No Metarr arguments built, returning...")
 		return nil
@@ -23,10 +57,78 @@ func InitMetarr(v *models.Video, ctx context.Context) error {
 	if err := runMetarr(cmd); err != nil {
 		return err
 	}
+
+	if resultsPath != "" {
+		if err := applyMetarrResults(v, resultsPath); err != nil {
+			logging.E(0, "Failed to apply Metarr results for %q: %v", v.URL, err)
+		}
+	}
+	if err := applyFilenameRegex(v); err != nil {
+		logging.E(0, "Failed to apply regex renames for %q: %v", v.URL, err)
+	}
 	logging.S(1, "Finished Metarr command for %q", v.VideoPath)
 	return nil
 }
 
+// supportsResultsFile reports whether the installed Metarr accepts the results file flag.
+//
+// Older Metarr versions exit on unknown flags, so they keep running without one and the video's paths stay as they were.
+// The check runs once per program run.
+func supportsResultsFile(ctx context.Context) bool {
+	resultsFileOnce.Do(func() {
+		hctx, cancel := context.WithTimeout(ctx, helpTimeout)
+		defer cancel()
+
+		out, err := exec.CommandContext(hctx, "metarr", "--help").CombinedOutput()
+		resultsFileSupported = strings.Contains(string(out), metcmd.ResultsFile)
+		if !resultsFileSupported {
+			logging.D(1, "Metarr does not list %q in its help (err: %v), not requesting a results manifest", metcmd.ResultsFile, err)
+		}
+	})
+	return resultsFileSupported
+}
+
+// applyMetarrResults reads the Metarr results manifest and updates the video's file paths.
+func applyMetarrResults(v *models.Video, resultsPath string) error {
+	data, err := os.ReadFile(resultsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logging.D(1, "No Metarr results manifest written for %q, keeping current paths", v.URL)
+			return nil
+		}
+		return err
+	}
+	defer func() {
+		if err := os.Remove(resultsPath); err != nil {
+			logging.E(0, "Failed to remove Metarr results manifest %q: %v", resultsPath, err)
+		}
+	}()
+
+	var res metarrResults
+	if err := json.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("invalid Metarr results manifest %q: %w", resultsPath, err)
+	}
+
+	if res.VideoFile != "" && res.VideoFile != v.VideoPath {
+		if _, err := os.Stat(res.VideoFile); err != nil {
+			return fmt.Errorf("metarr reported video file %q which could not be found: %w", res.VideoFile, err)
+		}
+		logging.I("Metarr moved video %q → %q", v.VideoPath, res.VideoFile)
+		v.VideoPath = res.VideoFile
+		v.VideoDir = filepath.Dir(res.VideoFile)
+	}
+
+	if res.JSONFile != "" && res.JSONFile != v.JSONPath {
+		if _, err := os.Stat(res.JSONFile); err != nil {
+			return fmt.Errorf("metarr reported JSON file %q which could not be found: %w", res.JSONFile, err)
+		}
+		logging.I("Metarr moved JSON %q → %q", v.JSONPath, res.JSONFile)
+		v.JSONPath = res.JSONFile
+		v.JSONDir = filepath.Dir(res.JSONFile)
+	}
+	return nil
+}
+
 // RunMetarr runs a Metarr command with a built argument list
 func runMetarr(cmd *exec.Cmd) error {
 	var err error
@@ -0,0 +1,159 @@
+package metarr
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+// isMetaRegex reports whether a meta operation is a regex operation, applied by Tubarr rather than Metarr.
+func isMetaRegex(op string) bool {
+	_, ok, _ := parsing.ParseMetaRegex(op)
+	return ok
+}
+
+// isFilenameRegex reports whether a filename operation is a regex rename, applied by Tubarr rather than Metarr.
+func isFilenameRegex(op string) bool {
+	_, ok, _ := parsing.ParseFilenameOp(op)
+	return ok
+}
+
+// regexOps returns the parsed regex operations of the video's meta or filename operations.
+//
+// The video's own operations take precedence over the config file's, as when building the Metarr command.
+func regexOps(ops []string, viperKey string, parse func(string) (parsing.RegexReplace, bool, error)) []parsing.RegexReplace {
+	if len(ops) == 0 && cfg.IsSet(viperKey) {
+		ops = cfg.GetStringSlice(viperKey)
+	}
+
+	var parsed []parsing.RegexReplace
+	for _, op := range ops {
+		r, ok, err := parse(op)
+		if err != nil {
+			logging.E(0, "Skipping invalid operation %q: %v", op, err)
+			continue
+		}
+		if ok {
+			parsed = append(parsed, r)
+		}
+	}
+	return parsed
+}
+
+// applyMetaRegex runs the regex meta operations over the fields of the video's JSON file.
+func applyMetaRegex(v *models.Video) error {
+	ops := regexOps(v.MetarrArgs.MetaOps, keys.MetaOps, parsing.ParseMetaRegex)
+	if len(ops) == 0 || v.JSONPath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(v.JSONPath)
+	if err != nil {
+		return err
+	}
+	var meta map[string]any
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return fmt.Errorf("invalid JSON in %q: %w", v.JSONPath, err)
+	}
+
+	changed := false
+	for _, op := range ops {
+		val, ok := meta[op.Field].(string)
+		if !ok {
+			logging.D(1, "Field %q in %q is missing or not a string, skipping regex operation", op.Field, v.JSONPath)
+			continue
+		}
+		if replaced := op.Apply(val); replaced != val {
+			logging.I("Regex meta operation set field %q: %q → %q", op.Field, val, replaced)
+			meta[op.Field] = replaced
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	out, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(v.JSONPath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(v.JSONPath, out, info.Mode().Perm())
+}
+
+// applyFilenameRegex runs the regex renames over the video's filename, without its extension.
+//
+// A JSON file named after the video is renamed to match, any other JSON filename gets the renames itself.
+func applyFilenameRegex(v *models.Video) error {
+	ops := regexOps(v.MetarrArgs.FilenameReplaceSfx, keys.FilenameReplaceSuffix, parsing.ParseFilenameOp)
+	if len(ops) == 0 || v.VideoPath == "" {
+		return nil
+	}
+
+	videoExt := filepath.Ext(v.VideoPath)
+	oldBase := strings.TrimSuffix(filepath.Base(v.VideoPath), videoExt)
+	newBase := oldBase
+	for _, op := range ops {
+		newBase = op.Apply(newBase)
+	}
+	if newBase == oldBase {
+		return nil
+	}
+	if newBase == "" {
+		return fmt.Errorf("regex renames leave %q without a filename", v.VideoPath)
+	}
+
+	newVideo := filepath.Join(filepath.Dir(v.VideoPath), newBase+videoExt)
+	if err := renameNoClobber(v.VideoPath, newVideo); err != nil {
+		return err
+	}
+	logging.I("Regex rename moved video %q → %q", v.VideoPath, newVideo)
+	v.VideoPath = newVideo
+
+	if v.JSONPath == "" {
+		return nil
+	}
+	jsonName := filepath.Base(v.JSONPath)
+	if rest, ok := strings.CutPrefix(jsonName, oldBase); ok {
+		jsonName = newBase + rest
+	} else {
+		jsonExt := filepath.Ext(jsonName)
+		base := strings.TrimSuffix(jsonName, jsonExt)
+		for _, op := range ops {
+			base = op.Apply(base)
+		}
+		jsonName = base + jsonExt
+	}
+
+	newJSON := filepath.Join(filepath.Dir(v.JSONPath), jsonName)
+	if newJSON == v.JSONPath {
+		return nil
+	}
+	if err := renameNoClobber(v.JSONPath, newJSON); err != nil {
+		return err
+	}
+	logging.I("Regex rename moved JSON %q → %q", v.JSONPath, newJSON)
+	v.JSONPath = newJSON
+	return nil
+}
+
+// renameNoClobber renames a file, failing if the target already exists.
+func renameNoClobber(from, to string) error {
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("cannot rename %q to %q, the target already exists", from, to)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return os.Rename(from, to)
+}
@@ -0,0 +1,106 @@
+package metarr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// MoveWithoutMetarr moves the raw downloaded files straight to the output directory, bypassing Metarr.
+//
+// If no output directory is configured the files are left in place.
+func MoveWithoutMetarr(v *models.Video) error {
+	outDir := parseOutputDir(v)
+	if outDir == "" {
+		logging.I("Skipping Metarr for %q, no output directory set so files are left in place", v.URL)
+		return nil
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory %q: %w", outDir, err)
+	}
+
+	if v.VideoPath != "" {
+		dst, err := MoveFile(v.VideoPath, outDir)
+		if err != nil {
+			return err
+		}
+		v.VideoPath = dst
+		v.VideoDir = outDir
+	}
+
+	if v.JSONPath != "" {
+		dst, err := MoveFile(v.JSONPath, outDir)
+		if err != nil {
+			return err
+		}
+		v.JSONPath = dst
+		v.JSONDir = outDir
+	}
+
+	logging.S(1, "Moved files for %q to %q without Metarr", v.URL, outDir)
+	return nil
+}
+
+// MoveFile moves a file into the destination directory, copying across filesystems if needed.
+//
+// The file only appears in the destination once complete, copies are written to a temporary file and renamed.
+func MoveFile(src, dstDir string) (string, error) {
+	dst := filepath.Join(dstDir, filepath.Base(src))
+	if dst == src {
+		return dst, nil
+	}
+
+	err := os.Rename(src, dst)
+	if err == nil {
+		return dst, nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return "", fmt.Errorf("failed to move %q to %q: %w", src, dst, err)
+	}
+
+	if err := copyFile(src, dst); err != nil {
+		return "", err
+	}
+	if err := os.Remove(src); err != nil {
+		return "", fmt.Errorf("copied %q but failed to remove source: %w", src, err)
+	}
+	return dst, nil
+}
+
+// copyFile copies the contents of src into a new file at dst, through a temporary file in the same directory.
+func copyFile(src, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("failed to copy %q to %q: %w", src, dst, os.ErrExist)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %q to %q: %w", src, dst, err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(out.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(out.Name(), dst)
+}
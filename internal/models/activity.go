@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
+
+// ActivityEvent is a single entry in a channel's activity feed.
+type ActivityEvent struct {
+	Time    time.Time
+	Kind    consts.ActivityKind
+	VideoID int64
+	URL     string
+	Detail  string
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
+
+// PendingCommand is a channel command queued by another instance, run by the scheduler.
+type PendingCommand struct {
+	ID        int64
+	ChannelID int64                `db:"channel_id"`
+	Action    consts.CommandAction `db:"action"`
+	URLs      []string             `db:"urls"` // Videos to limit the action to, all if empty
+	CreatedAt time.Time            `db:"created_at"`
+}
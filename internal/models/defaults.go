@@ -0,0 +1,31 @@
+package models
+
+import (
+	"reflect"
+	"time"
+)
+
+// Defaults is the settings profile inherited by channels for the settings they leave unset.
+type Defaults struct {
+	Settings   ChannelSettings `json:"settings" db:"settings"`
+	MetarrArgs MetarrArgs      `json:"metarr" db:"metarr"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Inherit fills the channel's unset settings and Metarr arguments from the defaults.
+//
+// A setting is unset if it holds its zero value (e.g. empty, 0, or false), so a default
+// of true can't be overridden to false by a channel.
+func (d *Defaults) Inherit(c *Channel) {
+	inheritZero(reflect.ValueOf(&c.Settings).Elem(), reflect.ValueOf(&d.Settings).Elem())
+	inheritZero(reflect.ValueOf(&c.MetarrArgs).Elem(), reflect.ValueOf(&d.MetarrArgs).Elem())
+}
+
+// inheritZero sets each zero field of the dst struct to the src struct's value.
+func inheritZero(dst, src reflect.Value) {
+	for i := range dst.NumField() {
+		if f := dst.Field(i); f.CanSet() && f.IsZero() {
+			f.Set(src.Field(i))
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Hook is an external script run whenever an event is published.
+type Hook struct {
+	ID      int64
+	Name    string        `db:"name"`
+	Event   string        `db:"event"`
+	Path    string        `db:"path"`
+	Args    []string      `db:"args"` // Go templates, rendered with the event's fields
+	Timeout time.Duration `db:"timeout_secs"`
+}
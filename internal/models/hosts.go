@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
+
+// HostStats holds recorded download results and the tuned effective values for a hostname.
+type HostStats struct {
+	Hostname    string        `db:"hostname"`
+	Successes   int           `db:"successes"`
+	Failures    int           `db:"failures"`
+	BotBlocks   int           `db:"bot_blocks"`
+	BlockStreak int           `db:"block_streak"`   // Bot blocks since the last success
+	NewBlocks   int           `db:"new_bot_blocks"` // Bot blocks since the host was last tuned
+	Concurrency int           `db:"concurrency"`
+	Delay       time.Duration `db:"delay_seconds"`
+	CreatedAt   time.Time     `db:"created_at"`
+	UpdatedAt   time.Time     `db:"updated_at"`
+}
+
+// Pause holds back new downloads for every host, for a hostname and its subdomains, for a channel group, or for a channel URL.
+type Pause struct {
+	Scope     string    `db:"scope"`
+	Until     time.Time `db:"until"` // Zero if paused until resumed
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Covers reports whether the pause applies to the hostname.
+func (p *Pause) Covers(hostname string) bool {
+	hostname = strings.ToLower(hostname)
+	return p.Scope == consts.PauseAllScope || hostname == p.Scope || strings.HasSuffix(hostname, "."+p.Scope)
+}
+
+// Describe returns what the pause covers, until when, and why.
+func (p *Pause) Describe() string {
+	scope := "all hosts"
+	switch {
+	case strings.HasPrefix(p.Scope, consts.PauseGroupPrefix):
+		scope = "group " + strings.TrimPrefix(p.Scope, consts.PauseGroupPrefix)
+	case strings.HasPrefix(p.Scope, consts.PauseURLPrefix):
+		scope = "URL " + strings.TrimPrefix(p.Scope, consts.PauseURLPrefix)
+	case p.Scope != consts.PauseAllScope:
+		scope = p.Scope
+	}
+	until := "until resumed"
+	if !p.Until.IsZero() {
+		until = "until " + p.Until.Local().Format(time.DateTime)
+	}
+	if p.Reason != "" {
+		return scope + " " + until + " (" + p.Reason + ")"
+	}
+	return scope + " " + until
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
+
+// GlobalIgnore is an ignore rule applied to the videos of every channel.
+type GlobalIgnore struct {
+	ID        int64
+	Kind      consts.IgnoreKind `db:"kind"`
+	Value     string            `db:"value"` // URL, or a regex for patterns and titles
+	Note      string            `db:"note"`
+	CreatedAt time.Time         `db:"created_at"`
+}
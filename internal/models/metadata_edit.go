@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// VideoMetadataEdit holds manual corrections to a video's metadata.
+//
+// Nil fields are left unchanged.
+type VideoMetadataEdit struct {
+	Title       *string
+	Description *string
+	UploadDate  *time.Time
+}
+
+// Fields returns the names of the fields set in the edit.
+func (e *VideoMetadataEdit) Fields() []string {
+	var fields []string
+	if e.Title != nil {
+		fields = append(fields, "title")
+	}
+	if e.Description != nil {
+		fields = append(fields, "description")
+	}
+	if e.UploadDate != nil {
+		fields = append(fields, "upload_date")
+	}
+	return fields
+}
+
+// MetadataFields returns the edited fields keyed and formatted as in yt-dlp metadata.
+func (e *VideoMetadataEdit) MetadataFields() map[string]any {
+	m := make(map[string]any, 3)
+	if e.Title != nil {
+		m["title"] = *e.Title
+	}
+	if e.Description != nil {
+		m["description"] = *e.Description
+	}
+	if e.UploadDate != nil {
+		m["upload_date"] = e.UploadDate.Format("20060102")
+	}
+	return m
+}
@@ -0,0 +1,7 @@
+package models
+
+// Notification is a named notification URL called after a channel's downloads.
+type Notification struct {
+	Name string `json:"name" db:"name"`
+	URL  string `json:"url" db:"notify_url"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Post is a community, text, or image post archived from a channel's posts feed.
+type Post struct {
+	ID        int64     `db:"id"`
+	ChannelID int64     `db:"channel_id"`
+	PostID    string    `db:"post_id"`
+	URL       string    `db:"url"`
+	Text      string    `db:"text"`
+	Files     []string  `db:"files"`
+	Dir       string    `db:"directory"`
+	PostedAt  time.Time `db:"posted_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
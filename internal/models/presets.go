@@ -0,0 +1,21 @@
+package models
+
+import (
+	"reflect"
+	"time"
+)
+
+// Preset is a named set of settings and Metarr arguments applied to channels when they are added.
+type Preset struct {
+	Name       string          `json:"name" db:"name"`
+	Settings   ChannelSettings `json:"settings" db:"settings"`
+	MetarrArgs MetarrArgs      `json:"metarr" db:"metarr"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Apply fills the channel's unset settings and Metarr arguments from the preset.
+func (p *Preset) Apply(c *Channel) {
+	inheritZero(reflect.ValueOf(&c.Settings).Elem(), reflect.ValueOf(&p.Settings).Elem())
+	inheritZero(reflect.ValueOf(&c.MetarrArgs).Elem(), reflect.ValueOf(&p.MetarrArgs).Elem())
+}
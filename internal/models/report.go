@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// CrawlReport summarizes one pass of crawling due channels.
+type CrawlReport struct {
+	ID         int64
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Channels   []ChannelReport
+	DigestSent bool
+}
+
+// ChannelReport holds the results of crawling one channel.
+type ChannelReport struct {
+	ChannelID   int64  `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	NewVideos   int    `json:"new_videos"`
+	Failures    int    `json:"failures"`
+	Filtered    int    `json:"filtered"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Totals returns the results summed over every channel in the report.
+func (r *CrawlReport) Totals() ChannelReport {
+	var t ChannelReport
+	for _, c := range r.Channels {
+		t.NewVideos += c.NewVideos
+		t.Failures += c.Failures
+		t.Filtered += c.Filtered
+	}
+	return t
+}
+
+// FailedChannels returns how many channels failed to crawl.
+func (r *CrawlReport) FailedChannels() int {
+	n := 0
+	for _, c := range r.Channels {
+		if c.Error != "" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
+
+// RetryEntry is a failed download waiting to be retried.
+//
+// NextAttempt is zero once the channel's maximum attempts are used up.
+type RetryEntry struct {
+	ChannelID   int64             `db:"channel_id"`
+	URL         string            `db:"url"`
+	Attempts    int               `db:"attempts"`
+	NextAttempt time.Time         `db:"next_attempt_at"`
+	LastError   string            `db:"last_error"`
+	FailReason  consts.FailReason // From the video's download, blank if unclassified
+	UpdatedAt   time.Time         `db:"updated_at"`
+}
+
+// Exhausted reports whether the entry has no retries left.
+func (r *RetryEntry) Exhausted() bool {
+	return r.NextAttempt.IsZero()
+}
@@ -1,16 +1,91 @@
 package models
 
+import (
+	"regexp"
+	"strings"
+)
+
 // ChannelSettings are the primary settings for a channel, affecting videos belonging to it.
 type ChannelSettings struct {
-	CookieSource           string      `json:"cookie_source"`
-	CrawlFreq              int         `json:"crawl_freq"`
-	Filters                []DLFilters `json:"filters"`
-	Retries                int         `json:"download_retries"`
-	ExternalDownloader     string      `json:"external_downloader"`
-	ExternalDownloaderArgs string      `json:"external_downloader_args"`
-	Concurrency            int         `json:"max_concurrency"`
-	MaxFilesize            string      `json:"max_filesize"`
-	AutoDownload           bool        `json:"auto_download"`
+	CookieSource           string            `json:"cookie_source"`
+	CrawlFreq              int               `json:"crawl_freq"`
+	Filters                []DLFilters       `json:"filters"`
+	Retries                int               `json:"download_retries"`
+	ExternalDownloader     string            `json:"external_downloader"`
+	ExternalDownloaderArgs string            `json:"external_downloader_args"`
+	Concurrency            int               `json:"max_concurrency"`
+	MaxFilesize            string            `json:"max_filesize"`
+	AutoDownload           bool              `json:"auto_download"`
+	URLAllow               []string          `json:"url_allow"`
+	URLBlock               []string          `json:"url_block"`
+	SkipMetarr             bool              `json:"skip_metarr"`
+	YTDLPExtraArgs         string            `json:"ytdlp_extra_args"`
+	ConcurrentFragments    int               `json:"concurrent_fragments"`
+	ExternalDLConnections  int               `json:"external_downloader_connections"`
+	PlaylistMatch          string            `json:"playlist_match"`
+	ExtractorDiagnostics   bool              `json:"extractor_diagnostics"`
+	BlackoutDates          []string          `json:"blackout_dates"`
+	CrawlCron              string            `json:"crawl_cron"`
+	QuietHours             string            `json:"quiet_hours"`
+	CrawlJitter            int               `json:"crawl_jitter"`
+	ExternalIDs            map[string]string `json:"external_ids"`
+	RetryMaxAttempts       int               `json:"retry_max_attempts"`
+	MaxDownloadsPerCrawl   int               `json:"max_downloads_per_crawl"`
+	MaxRate                string            `json:"max_rate"`
+	Fetcher                string            `json:"fetcher"`
+	FetcherRules           []string          `json:"fetcher_rules"`
+	SponsorBlockRemove     string            `json:"sponsorblock_remove"`
+	SponsorBlockMark       string            `json:"sponsorblock_mark"`
+	PostsURL               string            `json:"posts_url"`
+	MaxTotalSize           string            `json:"max_total_size"`
+	QuotaPrune             bool              `json:"quota_prune"`
+	KeepLast               int               `json:"keep_last"`
+	KeepDays               int               `json:"keep_days"`
+	RetentionNotify        bool              `json:"retention_notify"`
+	Proxies                []string          `json:"proxies"`
+	LiveURL                string            `json:"live_url"`
+	LiveCheckFreq          int               `json:"live_check_freq"`
+	StagingDir             string            `json:"staging_directory"`
+	FallbackProxy          string            `json:"fallback_proxy"`
+	FallbackCookieSource   string            `json:"fallback_cookie_source"`
+	AuthMethod             string            `json:"auth_method"`
+	MinDuration            string            `json:"min_duration"`
+	MaxDuration            string            `json:"max_duration"`
+	MinViews               int               `json:"min_views"`
+	MaxResolution          int               `json:"max_resolution"`
+	PreferredCodec         string            `json:"preferred_codec"`
+	AudioOnly              bool              `json:"audio_only"`
+	Sidecars               []string          `json:"sidecars"`
+	NFO                    bool              `json:"nfo"`
+	NFOEpisodeTemplate     string            `json:"nfo_episode_template"`
+	NFOShowTemplate        string            `json:"nfo_show_template"`
+	NFOFieldMap            map[string]string `json:"nfo_field_map"`
+	OutputTemplate         string            `json:"output_template"`
+	PostProcessors         []string          `json:"post_processors"`
+	Transcribe             bool              `json:"transcribe"`
+	TranscribeModel        string            `json:"transcribe_model"`
+	TranscribeLanguage     string            `json:"transcribe_language"`
+}
+
+// FetcherFor returns the download backend for a video URL.
+//
+// Fetcher rules are "pattern:fetcher" pairs checked in order, the first pattern matching the URL wins.
+// Without a match the channel's fetcher is used, an empty string meaning yt-dlp.
+func (s *ChannelSettings) FetcherFor(videoURL string) string {
+	for _, rule := range s.FetcherRules {
+		i := strings.LastIndex(rule, ":")
+		if i < 1 {
+			continue
+		}
+		rx, err := regexp.Compile(rule[:i])
+		if err != nil {
+			continue
+		}
+		if rx.MatchString(videoURL) {
+			return rule[i+1:]
+		}
+	}
+	return s.Fetcher
 }
 
 // DLFilters are used to filter in or out videos from download by metafields.
@@ -49,5 +49,9 @@ type Video struct {
 	DownloadStatus DLStatus        `json:"download_status" db:"download_status"`
 	CreatedAt      time.Time       `db:"created_at"`
 	UpdatedAt      time.Time       `db:"updated_at"`
+	YTDLPVersion   string          `db:"ytdlp_version"`
 	CookiePath     string
+	Playlist       string `db:"-"`
+	Live           bool   `db:"-"`
+	Filtered       bool   `db:"-"` // Rejected by the channel's filters this crawl
 }
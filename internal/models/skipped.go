@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
+
+// SkippedVideo is a video candidate which was not downloaded, along with why.
+type SkippedVideo struct {
+	ID        int64             `db:"id"`
+	ChannelID int64             `db:"channel_id"`
+	URL       string            `db:"url"`
+	Reason    consts.SkipReason `db:"reason"`
+	Detail    string            `db:"detail"`
+	CreatedAt time.Time         `db:"created_at"`
+}
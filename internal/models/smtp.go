@@ -0,0 +1,13 @@
+package models
+
+// SMTPConfig holds the global mail server settings for email notifications.
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"` // May be a secret reference such as "env:SMTP_PASSWORD"
+	From     string `json:"from"`
+	TLS      string `json:"tls"`     // "starttls", "tls", or "none"
+	Subject  string `json:"subject"` // Template, blank for the default
+	Body     string `json:"body"`    // Template, blank for the default
+}
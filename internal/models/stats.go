@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
+
+// DownloadStats holds download totals for a channel, for one day or summed over a period.
+type DownloadStats struct {
+	ChannelID   int64
+	ChannelName string
+	Day         string // YYYY-MM-DD, blank for totals over a period
+	Downloads   int
+	Failures    int
+	Bytes       int64
+	Duration    time.Duration             // Total time spent on successful downloads
+	FailReasons map[consts.FailReason]int // Downloads still failed, by the reason of their last failure
+}
+
+// AvgDuration returns the average time taken by a successful download.
+func (s *DownloadStats) AvgDuration() time.Duration {
+	if s.Downloads == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Downloads)
+}
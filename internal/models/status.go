@@ -1,12 +1,43 @@
 package models
 
-import "tubarr/internal/domain/consts"
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
 
 // DLStatus holds the data related to download progress etc.
 type DLStatus struct {
-	Status consts.DownloadStatus `json:"status"`
-	Pct    float64               `json:"percentage"`
-	Error  error                 `json:"error"`
+	Status       consts.DownloadStatus `json:"status"`
+	Pct          float64               `json:"percentage"`
+	Error        error                 `json:"error"`
+	CancelReason consts.CancelReason   `json:"cancel_reason"`
+	CancelledAt  time.Time             `json:"cancelled_at"`
+	FailReason   consts.FailReason     `json:"fail_reason"`
+	Partial      PartialDownload       `json:"partial"`
+	Speed        string                `json:"speed"` // Latest download speed reported by the backend, not stored
+	ETA          string                `json:"eta"`   // Latest time remaining reported by the backend, not stored
+}
+
+// PartialDownload is the file an in-progress download is writing, and its fragment progress if known.
+type PartialDownload struct {
+	Path       string `json:"path"`
+	FragsDone  int    `json:"fragments_done"`
+	FragsTotal int    `json:"fragments_total"`
+}
+
+// InterruptedDownload is an unfinished download with partial files left on disk.
+type InterruptedDownload struct {
+	VideoID      int64
+	ChannelID    int64
+	URL          string
+	Status       consts.DownloadStatus
+	CancelReason consts.CancelReason
+	Partial      PartialDownload
 }
 
 var DLStatusDefault = DLStatus{
@@ -17,9 +48,57 @@ var DLStatusDefault = DLStatus{
 
 // StatusUpdate models updates to the download status of a video.
 type StatusUpdate struct {
-	VideoID  int64
-	VideoURL string
-	Status   consts.DownloadStatus
-	Percent  float64
-	Error    error
+	VideoID      int64
+	ChannelID    int64
+	VideoURL     string
+	Status       consts.DownloadStatus
+	Percent      float64
+	Error        error
+	CancelReason consts.CancelReason
+	FailReason   consts.FailReason
+	Partial      PartialDownload
+	Speed        string
+	ETA          string
+}
+
+// Differs reports whether the update carries a different status, progress, or error message than o.
+//
+// Errors are compared by message, since error values may not be comparable.
+func (u StatusUpdate) Differs(o StatusUpdate) bool {
+	return u.VideoID != o.VideoID ||
+		u.Status != o.Status ||
+		u.Percent != o.Percent ||
+		u.CancelReason != o.CancelReason ||
+		u.FailReason != o.FailReason ||
+		u.Partial != o.Partial ||
+		errMessage(u.Error) != errMessage(o.Error)
+}
+
+// errMessage returns the error's message, blank if nil.
+func errMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// BulkResult holds the outcome of a bulk action for a single video.
+type BulkResult struct {
+	VideoID int64
+	URL     string
+	Err     error
+}
+
+// BulkOperation identifies a bulk action on an exact set of videos for confirmation, regardless of argument order.
+func BulkOperation(chanID int64, action string, ids []int64, urls []string) string {
+	idStrs := make([]string, len(ids))
+	for i, id := range ids {
+		idStrs[i] = strconv.FormatInt(id, 10)
+	}
+	sort.Strings(idStrs)
+
+	sortedURLs := append([]string(nil), urls...)
+	sort.Strings(sortedURLs)
+
+	return fmt.Sprintf("video-bulk:%d:%s:%s:%s", chanID, action, strings.Join(idStrs, ","), strings.Join(sortedURLs, ","))
 }
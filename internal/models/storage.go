@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ChannelStorage holds the cached disk usage of a channel's downloaded files.
+type ChannelStorage struct {
+	ChannelID  int64     `db:"channel_id"`
+	VideoBytes int64     `db:"video_bytes"`
+	JSONBytes  int64     `db:"json_bytes"`
+	Files      int       `db:"file_count"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// PathRewrite summarizes a library root move across stored channel and video paths.
+type PathRewrite struct {
+	Channels int
+	Videos   int
+	Posts    int
+	Missing  []string // Rewritten file paths which don't exist on disk
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
+
+// VideoTiming holds when a video reached each pipeline stage in its latest run.
+type VideoTiming struct {
+	VideoID int64
+	Stages  map[consts.PipelineStage]time.Time
+}
+
+// Between returns the time taken from one stage to another, if both were reached.
+func (t *VideoTiming) Between(from, to consts.PipelineStage) (time.Duration, bool) {
+	start, okStart := t.Stages[from]
+	end, okEnd := t.Stages[to]
+	if !okStart || !okEnd || end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
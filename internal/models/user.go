@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"tubarr/internal/domain/consts"
+)
+
+// User is an account for the HTTP endpoints.
+type User struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsAdmin reports whether the user may see every channel and make destructive changes.
+func (u *User) IsAdmin() bool {
+	return u != nil && u.Role == consts.RoleAdmin
+}
+
+// SeesAll reports whether the user may see every channel, as admins and requests made before any users exist can.
+func (u *User) SeesAll() bool {
+	return u != nil && (u.Role == consts.RoleAdmin || u.Role == consts.RoleOpen)
+}
+
+// OwnedChannel is a channel as listed to a user.
+type OwnedChannel struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Owner string `json:"owner,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// Webhook is a generic HTTP notification sent for each newly downloaded video.
+type Webhook struct {
+	ID        int64
+	ChannelID int64             `db:"channel_id"`
+	Name      string            `db:"name"`
+	URL       string            `db:"url"`
+	Method    string            `db:"method"`
+	Headers   map[string]string `db:"headers"`
+	Payload   string            `db:"payload"`
+}
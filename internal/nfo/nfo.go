@@ -0,0 +1,296 @@
+// Package nfo writes Kodi and Jellyfin compatible NFO files for channels and their videos.
+package nfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ShowFile is the name of the channel's NFO file, in the root of its video directory.
+const ShowFile = "tvshow.nfo"
+
+// Metadata holds the yt-dlp metadata fields used in NFO and sidecar files.
+type Metadata struct {
+	ID           string   `json:"id"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	UploadDate   string   `json:"upload_date"`
+	Uploader     string   `json:"uploader"`
+	Channel      string   `json:"channel"`
+	Duration     float64  `json:"duration"`
+	Tags         []string `json:"tags"`
+	Categories   []string `json:"categories"`
+	ExtractorKey string   `json:"extractor_key"`
+	WebpageURL   string   `json:"webpage_url"`
+	Chapters     []struct {
+		StartTime float64 `json:"start_time"`
+		Title     string  `json:"title"`
+	} `json:"chapters"`
+
+	fields map[string]any // Every field, for fields mapped by name
+}
+
+// Episode is the data available to episode and movie NFO templates.
+type Episode struct {
+	Title     string
+	Plot      string
+	Aired     string // yyyy-mm-dd
+	Year      int
+	Studio    string
+	ShowTitle string
+	Runtime   int // Minutes
+	Genres    []string
+	Tags      []string
+	Source    string // Site the ID belongs to, e.g. "youtube"
+	ID        string
+	URL       string
+}
+
+// Episode fields which metadata fields can be mapped to.
+const (
+	FieldTitle  = "title"
+	FieldPlot   = "plot"
+	FieldStudio = "studio"
+	FieldAired  = "aired"
+	FieldGenres = "genres"
+	FieldTags   = "tags"
+)
+
+// MappableFields are the episode fields which metadata fields can be mapped to.
+var MappableFields = []string{FieldTitle, FieldPlot, FieldStudio, FieldAired, FieldGenres, FieldTags}
+
+// Show is the data available to show NFO templates.
+type Show struct {
+	Title     string
+	Plot      string
+	Studio    string
+	URL       string
+	Tags      []string
+	UniqueIDs []UniqueID
+}
+
+// UniqueID is a show's ID on a metadata provider, such as TVDB.
+type UniqueID struct {
+	Type    string // Provider, e.g. "tvdb"
+	ID      string
+	Default bool
+}
+
+// idProviders are the metadata providers in the order their IDs are written, the first is the default.
+var idProviders = []string{"tvdb", "tmdb", "imdb"}
+
+// ShowIDs returns a channel's external IDs in provider order, with the first marked as the default.
+func ShowIDs(ids map[string]string) []UniqueID {
+	var out []UniqueID
+	for _, p := range idProviders {
+		if id := ids[p]; id != "" {
+			out = append(out, UniqueID{Type: p, ID: id, Default: len(out) == 0})
+		}
+	}
+	return out
+}
+
+// funcs are the functions available to NFO templates.
+var funcs = template.FuncMap{
+	"xml": func(s string) string {
+		var b strings.Builder
+		if err := xml.EscapeText(&b, []byte(s)); err != nil {
+			return ""
+		}
+		return b.String()
+	},
+}
+
+var (
+	episodeTmpl = template.Must(template.New("episode").Funcs(funcs).Parse(defaultEpisode))
+	movieTmpl   = template.Must(template.New("movie").Funcs(funcs).Parse(defaultMovie))
+	showTmpl    = template.Must(template.New("show").Funcs(funcs).Parse(defaultShow))
+)
+
+// ReadMetadata reads the NFO fields from a yt-dlp metadata JSON file.
+func ReadMetadata(jsonPath string) (*Metadata, error) {
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+	var m Metadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid metadata JSON %q: %w", jsonPath, err)
+	}
+	if err := json.Unmarshal(data, &m.fields); err != nil {
+		return nil, fmt.Errorf("invalid metadata JSON %q: %w", jsonPath, err)
+	}
+	return &m, nil
+}
+
+// Episode returns the metadata as episode template data of the show.
+func (m *Metadata) Episode(showTitle string) *Episode {
+	e := &Episode{
+		Title:     m.Title,
+		Plot:      m.Description,
+		Studio:    m.Channel,
+		ShowTitle: showTitle,
+		Runtime:   int(m.Duration+30) / 60,
+		Genres:    m.Categories,
+		Tags:      m.Tags,
+		Source:    strings.ToLower(m.ExtractorKey),
+		ID:        m.ID,
+		URL:       m.WebpageURL,
+	}
+	if e.Studio == "" {
+		e.Studio = m.Uploader
+	}
+	if e.Source == "" {
+		e.Source = "tubarr"
+	}
+	if t, err := time.Parse("20060102", m.UploadDate); err == nil {
+		e.Aired = t.Format("2006-01-02")
+		e.Year = t.Year()
+	}
+	return e
+}
+
+// MapFields fills episode fields from the metadata fields mapped to them, keyed by lowercase episode field name.
+//
+// Metadata fields are read as Metarr left them, so fields its meta ops set or clean can be used, e.g. plot from
+// a cleaned description. Mapped fields which are missing or empty keep their default. It returns the missing
+// metadata fields.
+func (e *Episode) MapFields(m *Metadata, fieldMap map[string]string) (missing []string) {
+	for field, metaField := range fieldMap {
+		v, ok := m.fields[metaField]
+		if !ok || v == nil {
+			missing = append(missing, metaField)
+			continue
+		}
+
+		switch field {
+		case FieldTitle:
+			e.Title = stringValue(e.Title, v)
+		case FieldPlot:
+			e.Plot = stringValue(e.Plot, v)
+		case FieldStudio:
+			e.Studio = stringValue(e.Studio, v)
+		case FieldAired:
+			if t, err := time.Parse("20060102", strings.ReplaceAll(stringValue("", v), "-", "")); err == nil {
+				e.Aired = t.Format("2006-01-02")
+				e.Year = t.Year()
+			}
+		case FieldGenres:
+			if l := stringList(v); len(l) > 0 {
+				e.Genres = l
+			}
+		case FieldTags:
+			if l := stringList(v); len(l) > 0 {
+				e.Tags = l
+			}
+		}
+	}
+	return missing
+}
+
+// stringValue returns the metadata value as a string, or def if it is empty.
+func stringValue(def string, v any) string {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case float64, bool:
+		s = fmt.Sprint(v)
+	}
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
+
+// stringList returns a metadata list of strings, or a comma separated string, as a list.
+func stringList(v any) []string {
+	var out []string
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, s)
+			}
+		}
+	case string:
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// CheckTemplate parses a template override file, reporting any syntax errors.
+func CheckTemplate(path string) error {
+	if path == "" {
+		return nil
+	}
+	_, err := loadTemplate(path, nil)
+	return err
+}
+
+// WriteEpisode writes an episodedetails NFO to path, using the template file at tmplPath if set.
+func WriteEpisode(path, tmplPath string, e *Episode) error {
+	return write(path, tmplPath, episodeTmpl, e)
+}
+
+// WriteMovie writes a movie NFO to path.
+func WriteMovie(path string, e *Episode) error {
+	return write(path, "", movieTmpl, e)
+}
+
+// WriteShow writes the show NFO into dir, using the template file at tmplPath if set.
+//
+// An existing show NFO is kept, so it can be edited by hand. It returns whether the file was written.
+func WriteShow(dir, tmplPath string, s *Show) (bool, error) {
+	path := filepath.Join(dir, ShowFile)
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, err
+	}
+	return true, write(path, tmplPath, showTmpl, s)
+}
+
+// write executes the template override, or the default template, with data into the file at path.
+func write(path, tmplPath string, def *template.Template, data any) error {
+	tmpl, err := loadTemplate(tmplPath, def)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return fmt.Errorf("failed to fill NFO template for %q: %w", path, err)
+	}
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
+
+// loadTemplate parses the template file at path, or returns def if path is blank.
+func loadTemplate(path string, def *template.Template) (*template.Template, error) {
+	if path == "" {
+		return def, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read NFO template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(funcs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NFO template %q: %w", path, err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,83 @@
+package nfo
+
+// Default NFO templates, overridden per channel by template files using the same fields.
+const (
+	defaultEpisode = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<episodedetails>
+  <title>{{xml .Title}}</title>
+  <showtitle>{{xml .ShowTitle}}</showtitle>
+{{- if .Plot}}
+  <plot>{{xml .Plot}}</plot>
+{{- end}}
+{{- if .Aired}}
+  <aired>{{.Aired}}</aired>
+  <premiered>{{.Aired}}</premiered>
+  <year>{{.Year}}</year>
+{{- end}}
+{{- if .Studio}}
+  <studio>{{xml .Studio}}</studio>
+{{- end}}
+{{- if .Runtime}}
+  <runtime>{{.Runtime}}</runtime>
+{{- end}}
+{{- range .Genres}}
+  <genre>{{xml .}}</genre>
+{{- end}}
+{{- range .Tags}}
+  <tag>{{xml .}}</tag>
+{{- end}}
+{{- if .ID}}
+  <uniqueid type="{{xml .Source}}" default="true">{{xml .ID}}</uniqueid>
+{{- end}}
+</episodedetails>
+`
+
+	defaultMovie = `<?xml version="1.0" encoding="UTF-8"?>
+<movie>
+  <title>{{xml .Title}}</title>
+{{- if .Plot}}
+  <plot>{{xml .Plot}}</plot>
+{{- end}}
+{{- if .Aired}}
+  <premiered>{{.Aired}}</premiered>
+  <year>{{.Year}}</year>
+{{- end}}
+{{- if .Studio}}
+  <studio>{{xml .Studio}}</studio>
+{{- end}}
+{{- if .Runtime}}
+  <runtime>{{.Runtime}}</runtime>
+{{- end}}
+{{- range .Genres}}
+  <genre>{{xml .}}</genre>
+{{- end}}
+{{- range .Tags}}
+  <tag>{{xml .}}</tag>
+{{- end}}
+{{- if .ID}}
+  <uniqueid type="{{xml .Source}}" default="true">{{xml .ID}}</uniqueid>
+{{- end}}
+</movie>
+`
+
+	defaultShow = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<tvshow>
+  <title>{{xml .Title}}</title>
+{{- if .Plot}}
+  <plot>{{xml .Plot}}</plot>
+{{- end}}
+{{- if .Studio}}
+  <studio>{{xml .Studio}}</studio>
+{{- end}}
+{{- range .Tags}}
+  <tag>{{xml .}}</tag>
+{{- end}}
+{{- if .URL}}
+  <website>{{xml .URL}}</website>
+{{- end}}
+{{- range .UniqueIDs}}
+  <uniqueid type="{{xml .Type}}"{{if .Default}} default="true"{{end}}>{{xml .ID}}</uniqueid>
+{{- end}}
+</tvshow>
+`
+)
@@ -0,0 +1,58 @@
+package parsing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	blackoutDateLayout = "2006-01-02"
+	blackoutRangeSep   = ".."
+)
+
+// ParseBlackout parses a blackout date ("2025-12-24") or inclusive date range ("2025-12-20..2026-01-02").
+//
+// Dates are in local time. The returned end is the start of the day after the last blackout day.
+func ParseBlackout(s string) (start, end time.Time, err error) {
+	first, last, isRange := strings.Cut(strings.TrimSpace(s), blackoutRangeSep)
+	if !isRange {
+		last = first
+	}
+
+	if start, err = time.ParseInLocation(blackoutDateLayout, strings.TrimSpace(first), time.Local); err != nil {
+		return start, end, fmt.Errorf("invalid blackout date %q, use YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD", s)
+	}
+	if end, err = time.ParseInLocation(blackoutDateLayout, strings.TrimSpace(last), time.Local); err != nil {
+		return start, end, fmt.Errorf("invalid blackout date %q, use YYYY-MM-DD or YYYY-MM-DD..YYYY-MM-DD", s)
+	}
+	if end.Before(start) {
+		return start, end, fmt.Errorf("blackout range %q ends before it starts", s)
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// ActiveBlackout returns the blackout range covering the given time, and when crawling resumes.
+//
+// Overlapping or adjacent ranges are followed, so the resume time is the end of the whole blackout.
+func ActiveBlackout(blackouts []string, at time.Time) (active string, resume time.Time, ok bool) {
+	resume = at
+	for {
+		extended := false
+		for _, b := range blackouts {
+			start, end, err := ParseBlackout(b)
+			if err != nil {
+				continue
+			}
+			if !resume.Before(start) && resume.Before(end) {
+				if !ok {
+					active = b
+				}
+				resume, ok, extended = end, true, true
+			}
+		}
+		if !extended {
+			return active, resume, ok
+		}
+	}
+}
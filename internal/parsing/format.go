@@ -0,0 +1,81 @@
+package parsing
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CodecAny clears a channel's preferred video codec.
+const CodecAny = "any"
+
+// videoCodecPatterns match the vcodec names yt-dlp reports for each preferred codec.
+var videoCodecPatterns = map[string]string{
+	"h264": "^(avc|h264)",
+	"h265": "^(hev|hvc|h265)",
+	"vp9":  "^vp0?9",
+	"av1":  "^av0?1",
+}
+
+// resolutionAliases are common names for video heights.
+var resolutionAliases = map[string]int{
+	"sd":  480,
+	"hd":  720,
+	"fhd": 1080,
+	"2k":  1440,
+	"4k":  2160,
+	"8k":  4320,
+}
+
+// VideoCodecs returns the valid preferred video codecs.
+func VideoCodecs() []string {
+	codecs := make([]string, 0, len(videoCodecPatterns)+1)
+	for c := range videoCodecPatterns {
+		codecs = append(codecs, c)
+	}
+	slices.Sort(codecs)
+	return append(codecs, CodecAny)
+}
+
+// ParseResolution parses a maximum video height such as "1080", "720p", or "4k".
+//
+// "0" is no limit, returned as 0.
+func ParseResolution(s string) (int, error) {
+	raw := s
+	s = strings.ToLower(strings.TrimSpace(s))
+	if h, ok := resolutionAliases[s]; ok {
+		return h, nil
+	}
+	h, err := strconv.Atoi(strings.TrimSuffix(s, "p"))
+	if err != nil || h < 0 {
+		return 0, fmt.Errorf("invalid resolution %q, expected a height such as '1080', '720p', or '4k'", raw)
+	}
+	return h, nil
+}
+
+// YTDLPFormat builds a yt-dlp format selector from a channel's format settings, or "" to leave yt-dlp's default.
+//
+// The preferred codec falls back to any codec within the height limit if unavailable, and formats with
+// an unknown height or codec are allowed rather than failing the download.
+func YTDLPFormat(maxHeight int, codec string, audioOnly bool) string {
+	if audioOnly {
+		return "ba/b"
+	}
+
+	var limit string
+	if maxHeight > 0 {
+		limit = fmt.Sprintf("[height<=?%d]", maxHeight)
+	}
+	pattern, hasCodec := videoCodecPatterns[codec]
+	if limit == "" && !hasCodec {
+		return ""
+	}
+
+	fallback := "bv*" + limit + "+ba/b" + limit
+	if !hasCodec {
+		return fallback
+	}
+	c := fmt.Sprintf("[vcodec~='%s']", pattern)
+	return "bv*" + limit + c + "+ba/b" + limit + c + "/" + fallback
+}
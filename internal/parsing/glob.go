@@ -0,0 +1,70 @@
+package parsing
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// CompileGlob compiles a case-insensitive title glob.
+//
+// '*' matches any run of characters, including '/', '?' matches one character, and '[...]' matches a character class,
+// negated by a leading '!' or '^'. A backslash matches the next character literally.
+func CompileGlob(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString(`(?is)^`)
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			b.WriteString(`.*`)
+
+		case '?':
+			b.WriteString(`.`)
+
+		case '\\':
+			i++
+			if i == len(runes) {
+				return nil, errors.New("glob ends with an unescaped backslash")
+			}
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+
+		case '[':
+			end := i + 1
+			if end < len(runes) && (runes[end] == '!' || runes[end] == '^') {
+				end++
+			}
+			if end < len(runes) && runes[end] == ']' {
+				end++ // A leading ']' is part of the class
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("glob has an unclosed character class")
+			}
+
+			b.WriteByte('[')
+			class := runes[i+1 : end]
+			if len(class) > 0 && (class[0] == '!' || class[0] == '^') {
+				b.WriteByte('^')
+				class = class[1:]
+			}
+			for _, c := range class {
+				if c == '\\' || c == '[' || c == ']' {
+					b.WriteByte('\\')
+				}
+				b.WriteRune(c)
+			}
+			b.WriteByte(']')
+			i = end
+
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteByte('$')
+
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,101 @@
+package parsing
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"tubarr/internal/domain/templates"
+)
+
+// outputTags are the tags available in output path templates.
+var outputTags = map[string]bool{
+	templates.ChannelDomain: true,
+	templates.ChannelName:   true,
+	templates.Channel:       true,
+	templates.ChannelURL:    true,
+	templates.ChannelID:     true,
+	templates.TVDBID:        true,
+	templates.TMDBID:        true,
+	templates.IMDBID:        true,
+	templates.VideoID:       true,
+	templates.VideoURL:      true,
+	templates.VideoTitle:    true,
+	templates.Playlist:      true,
+	templates.UploadYear:    true,
+	templates.UploadMonth:   true,
+	templates.UploadDay:     true,
+	templates.Season:        true,
+	templates.Episode:       true,
+}
+
+// pathReplacer replaces characters which are unsafe in file names on common filesystems.
+var pathReplacer = strings.NewReplacer(
+	"/", "_", "\\", "_", ":", " -", "*", "_", "?", "",
+	"\"", "'", "<", "_", ">", "_", "|", "_",
+)
+
+// ValidateOutputTemplate checks an output path template is relative, stays inside the video directory,
+// and only uses tags available once a video is downloaded.
+func ValidateOutputTemplate(tmpl string) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return errors.New("output template is empty")
+	}
+	if filepath.IsAbs(tmpl) {
+		return fmt.Errorf("output template %q must be relative to the video directory", tmpl)
+	}
+	if strings.HasSuffix(tmpl, "/") || strings.HasSuffix(tmpl, string(filepath.Separator)) {
+		return fmt.Errorf("output template %q must end in a file name", tmpl)
+	}
+	for _, elem := range strings.Split(filepath.ToSlash(tmpl), "/") {
+		if elem == ".." {
+			return fmt.Errorf("output template %q must not leave the video directory", tmpl)
+		}
+	}
+
+	if strings.Count(tmpl, open) != strings.Count(tmpl, close) {
+		return fmt.Errorf("output template %q has mismatched template delimiters", tmpl)
+	}
+	remaining := tmpl
+	for {
+		startIdx := strings.Index(remaining, open)
+		if startIdx == -1 {
+			return nil
+		}
+		endIdx := strings.Index(remaining[startIdx:], close)
+		if endIdx == -1 {
+			return fmt.Errorf("output template %q is missing a closing delimiter", tmpl)
+		}
+		tag := strings.ToLower(strings.TrimSpace(remaining[startIdx+len(open) : startIdx+endIdx]))
+		if !outputTags[tag] {
+			return fmt.Errorf("tag %q is not available in output templates", tag)
+		}
+		remaining = remaining[startIdx+endIdx+len(close):]
+	}
+}
+
+// ParseOutputTemplate returns the output path template filled in for the video, as a path relative to the video directory
+// without a file extension.
+//
+// Tag values have unsafe file name characters replaced. Index numbers the episode among the season's videos uploaded the same day,
+// starting at 1.
+func (dp *Directory) ParseOutputTemplate(tmpl string, index int) (string, error) {
+	dp.sanitize, dp.index = true, index
+	defer func() { dp.sanitize, dp.index = false, 0 }()
+
+	parsed, err := dp.parseTemplate(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("output template parsing error: %w", err)
+	}
+	return filepath.Clean(parsed), nil
+}
+
+// sanitizePathElem makes a tag value safe to use inside a single file or directory name.
+func sanitizePathElem(s string) string {
+	s = strings.Trim(pathReplacer.Replace(s), " .")
+	if s == "" {
+		return "_"
+	}
+	return s
+}
@@ -0,0 +1,325 @@
+package parsing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+)
+
+// FilterTypes are the valid download filter types.
+var FilterTypes = []string{consts.FilterContains, consts.FilterOmit, consts.FilterRegex, consts.FilterOmitRegex}
+
+// MetaOpTypes are the valid Metarr meta operations.
+var MetaOpTypes = []string{"append", "copy-to", "paste-from", "prefix", "trim-prefix", "trim-suffix", "replace", "set", "date-tag", consts.OpRegex}
+
+// DateTagLocations are the valid date tag placements.
+var DateTagLocations = []string{"prefix", "suffix"}
+
+// DateTagFormats are the valid date tag formats, where 'Y' is yyyy and 'y' is yy.
+var DateTagFormats = []string{"Ymd", "ymd", "Ydm", "ydm", "dmY", "dmy", "mdY", "mdy", "md", "dm"}
+
+// CommonFields are well known metadata field names, used to suggest corrections for misspelled fields.
+var CommonFields = []string{
+	"title", "fulltitle", "description", "synopsis", "summary", "uploader", "uploader_id", "channel", "channel_id",
+	"creator", "artist", "album", "genre", "tags", "categories", "upload_date", "release_date", "date", "timestamp",
+	"duration", "view_count", "like_count", "comment_count", "webpage_url", "id", "playlist", "playlist_title",
+	"series", "season", "episode", "is_live", "was_live", "age_limit", "availability", "language", "location",
+}
+
+// OpError is a filter or meta operation syntax error, pointing at the token which failed.
+type OpError struct {
+	Input   string
+	Pos     int // Byte offset of the failing token in Input
+	Token   string
+	Msg     string
+	Suggest string
+}
+
+// Error implements the error interface, printing the input with a marker under the failing token.
+func (e *OpError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "column %d: %s", e.Pos+1, e.Msg)
+	if e.Suggest != "" {
+		fmt.Fprintf(&b, ", did you mean %q?", e.Suggest)
+	}
+	n := len(e.Token)
+	if n == 0 {
+		n = 1
+	}
+	fmt.Fprintf(&b, "\n  %s\n  %s%s", e.Input, strings.Repeat(" ", e.Pos), strings.Repeat("^", n))
+	return b.String()
+}
+
+// RegexReplace replaces matches of Pattern in a metadata field or filename with Replacement.
+//
+// Replacement may refer to capture groups as '$1' or '${1}'.
+type RegexReplace struct {
+	Field       string
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// Apply returns s with all matches of the pattern replaced.
+func (r RegexReplace) Apply(s string) string {
+	return r.Pattern.ReplaceAllString(s, r.Replacement)
+}
+
+// opPart is a colon separated token of an operation and its offset in the input.
+type opPart struct {
+	s   string
+	pos int
+}
+
+// splitOp splits an operation into at most n colon separated parts, the last keeps any further colons.
+func splitOp(s string, n int) []opPart {
+	parts := make([]opPart, 0, n)
+	pos := 0
+	for _, p := range strings.SplitN(s, ":", n) {
+		parts = append(parts, opPart{s: p, pos: pos})
+		pos += len(p) + 1
+	}
+	return parts
+}
+
+// ParseFilter parses a download filter in the form 'field:type' or 'field:type:value'.
+//
+// The value may contain colons.
+func ParseFilter(s string) (models.DLFilters, error) {
+	parts := splitOp(s, 3)
+	if len(parts) < 2 {
+		return models.DLFilters{}, &OpError{Input: s, Pos: len(s), Msg: "missing filter type, expected 'field:type[:value]' (e.g. 'title:omit:frogs')"}
+	}
+	if parts[0].s == "" {
+		return models.DLFilters{}, &OpError{Input: s, Pos: 0, Msg: "missing filter field name"}
+	}
+	if !contains(FilterTypes, parts[1].s) {
+		return models.DLFilters{}, &OpError{
+			Input:   s,
+			Pos:     parts[1].pos,
+			Token:   parts[1].s,
+			Msg:     fmt.Sprintf("invalid filter type %q, expected %s", parts[1].s, strings.Join(FilterTypes, " or ")),
+			Suggest: closest(parts[1].s, FilterTypes),
+		}
+	}
+
+	f := models.DLFilters{Field: parts[0].s, Type: parts[1].s}
+	isRegex := f.Type == consts.FilterRegex || f.Type == consts.FilterOmitRegex
+	if len(parts) == 3 {
+		if parts[2].s == "" {
+			return models.DLFilters{}, &OpError{Input: s, Pos: parts[2].pos, Msg: "empty filter value, leave out the trailing ':' to match on the field existing"}
+		}
+		f.Value = parts[2].s
+	} else if isRegex {
+		return models.DLFilters{}, &OpError{Input: s, Pos: len(s), Msg: fmt.Sprintf("missing pattern for filter type %q (e.g. 'title:regex:Episode \\d+')", f.Type)}
+	}
+
+	if isRegex {
+		if _, err := regexp.Compile(f.Value); err != nil {
+			return models.DLFilters{}, &OpError{Input: s, Pos: parts[2].pos, Token: parts[2].s, Msg: fmt.Sprintf("invalid regular expression: %v", err)}
+		}
+	}
+	return f, nil
+}
+
+// ParseFilters parses several download filters, stopping at the first error.
+func ParseFilters(filters []string) ([]models.DLFilters, error) {
+	parsed := make([]models.DLFilters, 0, len(filters))
+	for _, s := range filters {
+		f, err := ParseFilter(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, f)
+	}
+	return parsed, nil
+}
+
+// ParseMetaOp checks a meta operation in the form 'field:operation:value', or 'field:date-tag:location:format'.
+//
+// It returns a key identifying the operation for duplicate detection, date tags differing only in format share a key.
+func ParseMetaOp(s string) (key string, err error) {
+	parts := splitOp(s, 3)
+	if parts[0].s == "" {
+		return "", &OpError{Input: s, Pos: 0, Msg: "missing meta operation field name"}
+	}
+	if len(parts) < 2 {
+		return "", &OpError{Input: s, Pos: len(s), Msg: "missing meta operation, expected 'field:operation:value' (e.g. 'title:prefix:Draft-')"}
+	}
+	if !contains(MetaOpTypes, parts[1].s) {
+		return "", &OpError{
+			Input:   s,
+			Pos:     parts[1].pos,
+			Token:   parts[1].s,
+			Msg:     fmt.Sprintf("invalid meta operation %q", parts[1].s),
+			Suggest: closest(parts[1].s, MetaOpTypes),
+		}
+	}
+	if len(parts) < 3 {
+		return "", &OpError{Input: s, Pos: len(s), Msg: fmt.Sprintf("missing value for meta operation %q", parts[1].s)}
+	}
+
+	if parts[1].s == consts.OpRegex {
+		r, err := parseRegexReplace(s, parts[0].s, parts[2])
+		if err != nil {
+			return "", err
+		}
+		return parts[0].s + ":" + consts.OpRegex + ":" + r.Pattern.String(), nil
+	}
+	if parts[1].s != "date-tag" {
+		return s, nil
+	}
+
+	// Date tags hold a location and format, e.g. 'title:date-tag:prefix:ymd'
+	loc, format, ok := strings.Cut(parts[2].s, ":")
+	if !contains(DateTagLocations, loc) {
+		return "", &OpError{
+			Input:   s,
+			Pos:     parts[2].pos,
+			Token:   loc,
+			Msg:     fmt.Sprintf("invalid date tag location %q, expected %s", loc, strings.Join(DateTagLocations, " or ")),
+			Suggest: closest(loc, DateTagLocations),
+		}
+	}
+	formatPos := parts[2].pos + len(loc) + 1
+	if !ok {
+		return "", &OpError{Input: s, Pos: len(s), Msg: "missing date tag format, expected 'field:date-tag:location:format' (e.g. 'title:date-tag:prefix:ymd')"}
+	}
+	if !contains(DateTagFormats, format) {
+		return "", &OpError{
+			Input:   s,
+			Pos:     formatPos,
+			Token:   format,
+			Msg:     fmt.Sprintf("invalid date tag format %q, expected one of %s", format, strings.Join(DateTagFormats, ", ")),
+			Suggest: closest(format, DateTagFormats),
+		}
+	}
+	return parts[0].s + ":" + parts[1].s + ":" + loc, nil
+}
+
+// ParseMetaRegex parses a meta operation in the form 'field:regex:pattern:replacement'.
+//
+// It returns false if the operation is valid but not a regex operation.
+func ParseMetaRegex(s string) (RegexReplace, bool, error) {
+	parts := splitOp(s, 3)
+	if len(parts) < 2 || parts[1].s != consts.OpRegex {
+		return RegexReplace{}, false, nil
+	}
+	if _, err := ParseMetaOp(s); err != nil {
+		return RegexReplace{}, false, err
+	}
+	r, err := parseRegexReplace(s, parts[0].s, parts[2])
+	if err != nil {
+		return RegexReplace{}, false, err
+	}
+	return r, true, nil
+}
+
+// ParseFilenameOp checks a filename operation, either a suffix replacement 'suffix:replacement'
+// or a regex rename 'regex:pattern:replacement' applied to the filename without its extension.
+//
+// It returns the regex rename, and true if the operation is one.
+func ParseFilenameOp(s string) (RegexReplace, bool, error) {
+	parts := splitOp(s, 2)
+	if len(parts) < 2 {
+		return RegexReplace{}, false, &OpError{Input: s, Pos: len(s), Msg: "missing replacement, expected 'suffix:replacement' or 'regex:pattern:replacement'"}
+	}
+	if parts[0].s != consts.OpRegex {
+		return RegexReplace{}, false, nil
+	}
+	r, err := parseRegexReplace(s, "", parts[1])
+	if err != nil {
+		return RegexReplace{}, false, err
+	}
+	return r, true, nil
+}
+
+// parseRegexReplace parses 'pattern:replacement' from an operation part.
+//
+// The pattern ends at the first colon not escaped as '\:', the replacement may hold colons and be empty.
+func parseRegexReplace(input, field string, p opPart) (RegexReplace, error) {
+	end := -1
+	for i := 0; i < len(p.s); i++ {
+		if p.s[i] == '\\' {
+			i++ // Skip the escaped character
+			continue
+		}
+		if p.s[i] == ':' {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return RegexReplace{}, &OpError{Input: input, Pos: len(input), Msg: "missing regex replacement, expected 'pattern:replacement' (e.g. 'Episode (\\d+):Ep. $1')"}
+	}
+
+	pattern := p.s[:end]
+	if pattern == "" {
+		return RegexReplace{}, &OpError{Input: input, Pos: p.pos, Msg: "empty regular expression"}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return RegexReplace{}, &OpError{Input: input, Pos: p.pos, Token: pattern, Msg: fmt.Sprintf("invalid regular expression: %v", err)}
+	}
+	return RegexReplace{Field: field, Pattern: re, Replacement: p.s[end+1:]}, nil
+}
+
+// SuggestField returns a well known field name close to an unknown field, or "" if the field is known or nothing is close.
+func SuggestField(field string) string {
+	if contains(CommonFields, field) {
+		return ""
+	}
+	return closest(field, CommonFields)
+}
+
+// contains reports whether s is in list.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// closest returns the candidate within a small edit distance of s, or "" if none are close.
+func closest(s string, candidates []string) string {
+	if s == "" {
+		return ""
+	}
+	maxDist := 3
+	if len(s) <= 3 {
+		maxDist = 2
+	}
+
+	best, bestDist := "", maxDist
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(s), strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -25,6 +25,10 @@ const (
 type Directory struct {
 	C *models.Channel
 	V *models.Video
+
+	// Set when parsing output path templates
+	sanitize bool
+	index    int
 }
 
 func NewDirectoryParser(c *models.Channel, v *models.Video) (parseDir *Directory) {
@@ -110,6 +114,9 @@ func (dp *Directory) parseTemplate(dir string) (string, error) {
 		if err != nil {
 			return "", err
 		}
+		if dp.sanitize {
+			replacement = sanitizePathElem(replacement)
+		}
 		b.WriteString(replacement)
 
 		// String after template close
@@ -146,7 +153,7 @@ func (dp *Directory) replace(tag string) (string, error) {
 		}
 		return "", errors.New("templating: channel ID is 0")
 
-	case templates.ChannelName:
+	case templates.ChannelName, templates.Channel:
 		if c.Name != "" {
 			return c.Name, nil
 		}
@@ -158,6 +165,13 @@ func (dp *Directory) replace(tag string) (string, error) {
 		}
 		return "", errors.New("templating: URL empty")
 
+	case templates.TVDBID, templates.TMDBID, templates.IMDBID:
+		provider := strings.TrimSuffix(strings.ToLower(tag), "_id")
+		if id := c.Settings.ExternalIDs[provider]; id != "" {
+			return id, nil
+		}
+		return "", fmt.Errorf("templating: channel has no %s ID set", provider)
+
 	case templates.VideoID:
 		if v.ID != 0 {
 			return strconv.Itoa(int(v.ID)), nil
@@ -176,6 +190,39 @@ func (dp *Directory) replace(tag string) (string, error) {
 		}
 		return "", errors.New("templating: video URL is empty")
 
+	case templates.Playlist:
+		if v.Playlist != "" {
+			return sanitizePathElem(v.Playlist), nil
+		}
+		return "", errors.New("templating: video has no matched playlist")
+
+	case templates.UploadYear, templates.Season:
+		if v.UploadDate.IsZero() {
+			return "", errors.New("templating: video upload date unknown")
+		}
+		return strconv.Itoa(v.UploadDate.Year()), nil
+
+	case templates.UploadMonth:
+		if v.UploadDate.IsZero() {
+			return "", errors.New("templating: video upload date unknown")
+		}
+		return v.UploadDate.Format("01"), nil
+
+	case templates.UploadDay:
+		if v.UploadDate.IsZero() {
+			return "", errors.New("templating: video upload date unknown")
+		}
+		return v.UploadDate.Format("02"), nil
+
+	case templates.Episode:
+		if dp.index == 0 {
+			return "", errors.New("templating: episode numbers are only available in output path templates")
+		}
+		if v.UploadDate.IsZero() {
+			return "", errors.New("templating: video upload date unknown")
+		}
+		return fmt.Sprintf("%s%02d", v.UploadDate.Format("0102"), dp.index), nil
+
 		// Metarr cases:
 	case templates.MetAuthor, templates.MetDay, templates.MetDirector,
 		templates.MetDomain, templates.MetMonth, templates.MetYear:
@@ -189,3 +236,14 @@ func (dp *Directory) replace(tag string) (string, error) {
 		return "", fmt.Errorf("tag %q detected as invalid", tag)
 	}
 }
+
+// StaticPrefix returns the portion of a directory before any template tags.
+func StaticPrefix(dir string) string {
+	if i := strings.Index(dir, open); i >= 0 {
+		dir = dir[:i]
+		if j := strings.LastIndex(dir, string(filepath.Separator)); j >= 0 {
+			dir = dir[:j+1]
+		}
+	}
+	return dir
+}
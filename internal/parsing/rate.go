@@ -0,0 +1,81 @@
+package parsing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rateUnits are binary multipliers, matching how yt-dlp reads --limit-rate.
+var rateUnits = map[byte]float64{
+	'K': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+}
+
+// ParseRate parses a download rate such as "4M" or "500K" into bytes per second.
+//
+// An empty string is no limit, returned as 0.
+func ParseRate(s string) (int64, error) {
+	raw := s
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/S"), "B")
+	if strings.HasSuffix(s, "I") { // e.g. "MiB"
+		s = s[:len(s)-1]
+	}
+
+	mult := 1.0
+	if n := len(s); n > 0 {
+		if m, ok := rateUnits[s[n-1]]; ok {
+			mult = m
+			s = s[:n-1]
+		}
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f <= 0 {
+		return 0, fmt.Errorf("invalid rate %q, expected a positive value such as '4M' or '500K'", raw)
+	}
+	return int64(f * mult), nil
+}
+
+// FormatRate returns a readable form of a bytes per second rate.
+func FormatRate(bps int64) string {
+	switch {
+	case bps >= 1<<30:
+		return fmt.Sprintf("%.1fGiB/s", float64(bps)/(1<<30))
+	case bps >= 1<<20:
+		return fmt.Sprintf("%.1fMiB/s", float64(bps)/(1<<20))
+	case bps >= 1<<10:
+		return fmt.Sprintf("%.1fKiB/s", float64(bps)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB/s", bps)
+	}
+}
+
+// AllHosts is the host rate limit key applying to every host without its own limit.
+const AllHosts = "*"
+
+// ParseHostRates parses request rate limits such as "youtube.com=6" into requests per minute keyed by hostname.
+//
+// A bare number such as "10" limits every host without its own entry, and is keyed by AllHosts.
+func ParseHostRates(entries []string) (map[string]int, error) {
+	rates := make(map[string]int, len(entries))
+	for _, e := range entries {
+		host, n, found := strings.Cut(strings.TrimSpace(e), "=")
+		if !found {
+			host, n = AllHosts, host
+		}
+		host = strings.ToLower(strings.TrimSpace(host))
+
+		rpm, err := strconv.Atoi(strings.TrimSpace(n))
+		if err != nil || rpm < 1 || host == "" || strings.ContainsAny(host, "/:") {
+			return nil, fmt.Errorf("invalid host rate limit %q, expected requests per minute such as '10' or 'youtube.com=6'", e)
+		}
+		rates[host] = rpm
+	}
+	return rates, nil
+}
@@ -0,0 +1,60 @@
+package parsing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits are binary multipliers for disk sizes.
+var sizeUnits = map[byte]float64{
+	'K': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+	'T': 1 << 40,
+}
+
+// ParseSize parses a disk size such as "50G" or "500M" into bytes.
+//
+// An empty string is no limit, returned as 0.
+func ParseSize(s string) (int64, error) {
+	raw := s
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+	s = strings.TrimSuffix(s, "B")
+	if strings.HasSuffix(s, "I") { // e.g. "GiB"
+		s = s[:len(s)-1]
+	}
+
+	mult := 1.0
+	if n := len(s); n > 0 {
+		if m, ok := sizeUnits[s[n-1]]; ok {
+			mult = m
+			s = s[:n-1]
+		}
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f <= 0 {
+		return 0, fmt.Errorf("invalid size %q, expected a positive value such as '50G' or '500M'", raw)
+	}
+	return int64(f * mult), nil
+}
+
+// FormatSize returns a readable form of a size in bytes.
+func FormatSize(b int64) string {
+	switch {
+	case b >= 1<<40:
+		return fmt.Sprintf("%.1fTiB", float64(b)/(1<<40))
+	case b >= 1<<30:
+		return fmt.Sprintf("%.1fGiB", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.1fMiB", float64(b)/(1<<20))
+	case b >= 1<<10:
+		return fmt.Sprintf("%.1fKiB", float64(b)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", b)
+	}
+}
@@ -0,0 +1,219 @@
+// Package postprocess runs custom processing steps on downloaded videos, between the download and Metarr.
+//
+// Steps are built in PostProcessors registered with Register, or plugin executables speaking a JSON
+// protocol: Tubarr writes a Job to the plugin's stdin and reads a Result from its stdout. Paths the
+// plugin leaves blank in its result are unchanged. A non-zero exit status or a result error fails the video.
+package postprocess
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tubarr/internal/domain/setup"
+	"tubarr/internal/utils/logging"
+)
+
+// ProtocolVersion is sent to plugins in each job, and bumped on incompatible changes.
+const ProtocolVersion = 1
+
+// DefaultTimeout limits each plugin run when no timeout is set.
+const DefaultTimeout = time.Hour
+
+const (
+	pluginsDir      = "plugins"
+	maxResultSize   = 1 << 20
+	pluginWaitDelay = 5 * time.Second
+)
+
+// Job is the downloaded video passed to each step. Steps may change the paths if they move or replace files.
+type Job struct {
+	Version   int    `json:"version"`
+	VideoPath string `json:"video_path"`
+	JSONPath  string `json:"json_path"`
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	ChannelID int64  `json:"channel_id"`
+	Channel   string `json:"channel"`
+}
+
+// Result is read from a plugin's stdout.
+type Result struct {
+	VideoPath string `json:"video_path,omitempty"`
+	JSONPath  string `json:"json_path,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PostProcessor is a processing step run on each downloaded video.
+type PostProcessor interface {
+	Name() string
+	Process(ctx context.Context, job *Job) error
+}
+
+var registry = struct {
+	mu     sync.RWMutex
+	byName map[string]PostProcessor
+}{byName: make(map[string]PostProcessor)}
+
+// Register adds a built in post-processor, chosen with its name in channel settings.
+//
+// Built in post-processors take precedence over plugins with the same name.
+func Register(p PostProcessor) {
+	registry.mu.Lock()
+	registry.byName[p.Name()] = p
+	registry.mu.Unlock()
+}
+
+// Dir returns the plugin directory, defaulting to "plugins" in the Tubarr config directory.
+func Dir(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	return filepath.Join(setup.CfgDir, pluginsDir)
+}
+
+// ValidName checks a post-processor name is a bare name or an absolute path.
+func ValidName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("post-processor name is blank")
+	case filepath.IsAbs(name):
+		return nil
+	case strings.ContainsRune(name, filepath.Separator) || strings.HasPrefix(name, "."):
+		return fmt.Errorf("invalid post-processor %q, expected a plugin name or an absolute path", name)
+	}
+	return nil
+}
+
+// Load returns the named post-processor: a built in one, the plugin at an absolute path,
+// or the plugin with that name in the plugin directory.
+func Load(dir, name string, timeout time.Duration) (PostProcessor, error) {
+	if err := ValidName(name); err != nil {
+		return nil, err
+	}
+
+	registry.mu.RLock()
+	p, ok := registry.byName[name]
+	registry.mu.RUnlock()
+	if ok {
+		return p, nil
+	}
+
+	path := name
+	if !filepath.IsAbs(name) {
+		path = filepath.Join(dir, name)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("post-processor %q not found: %w", name, err)
+	}
+	if info.IsDir() || info.Mode()&0o111 == 0 {
+		return nil, fmt.Errorf("post-processor plugin %q is not an executable file", path)
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &plugin{name: name, path: path, timeout: timeout}, nil
+}
+
+// Run loads and runs the named post-processors on the job in order, stopping at the first failure.
+func Run(ctx context.Context, dir string, names []string, timeout time.Duration, job *Job) error {
+	job.Version = ProtocolVersion
+	for _, name := range names {
+		p, err := Load(dir, name, timeout)
+		if err != nil {
+			return err
+		}
+
+		started := time.Now()
+		if err := p.Process(ctx, job); err != nil {
+			return fmt.Errorf("post-processor %q failed: %w", name, err)
+		}
+		for _, path := range []string{job.VideoPath, job.JSONPath} {
+			if path == "" {
+				continue
+			}
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("post-processor %q left a missing file: %w", name, err)
+			}
+		}
+		logging.D(1, "Post-processor %q finished for %q in %v", name, job.URL, time.Since(started).Round(time.Millisecond))
+	}
+	return nil
+}
+
+// plugin is a post-processor run as a subprocess.
+type plugin struct {
+	name    string
+	path    string
+	timeout time.Duration
+}
+
+// Name returns the name the plugin was loaded by.
+func (p *plugin) Name() string {
+	return p.name
+}
+
+// Process runs the plugin with the job on stdin, applying the paths in its result.
+//
+// The plugin's stderr is logged at debug level.
+func (p *plugin) Process(ctx context.Context, job *Job) error {
+	in, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = pluginWaitDelay
+	cmd.Env = append(os.Environ(), "TUBARR_PROTOCOL_VERSION="+strconv.Itoa(ProtocolVersion))
+
+	runErr := cmd.Run()
+	for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+		if line != "" {
+			logging.D(1, "[%s] %s", p.name, line)
+		}
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %v", p.timeout)
+	}
+
+	var res Result
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 {
+		if len(out) > maxResultSize {
+			return fmt.Errorf("result is larger than %d bytes", maxResultSize)
+		}
+		if err := json.Unmarshal(out, &res); err != nil {
+			return fmt.Errorf("invalid result JSON: %w", err)
+		}
+	}
+	switch {
+	case res.Error != "":
+		return errors.New(res.Error)
+	case runErr != nil:
+		return runErr
+	}
+
+	if res.VideoPath != "" {
+		job.VideoPath = res.VideoPath
+	}
+	if res.JSONPath != "" {
+		job.JSONPath = res.JSONPath
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/nfo"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	archiveYouTube   = "youtube"
+	youTubeWatchURL  = "https://www.youtube.com/watch?v="
+	maxArchiveShown  = 20
+	archiveLineLimit = 64 * 1024
+)
+
+// ImportArchive marks the videos listed in a yt-dlp download archive file as finished in the channel.
+//
+// Each "extractor id" line is matched to the channel's recorded videos and, unless offline is set, its remote
+// videos by ID. Unmatched YouTube IDs are added by their watch URL, other unmatched entries are skipped.
+func ImportArchive(s interfaces.Store, c *models.Channel, path string, offline bool, ctx context.Context) error {
+	entries, err := readArchive(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		logging.I("No entries in download archive %q", path)
+		return nil
+	}
+
+	known, err := importCandidates(s, c, offline, ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]string, len(known))
+	for _, v := range known {
+		byID[videoIDFromURL(v.URL)] = v.URL
+	}
+
+	var (
+		urls       = make([]string, 0, len(entries))
+		unresolved []string
+	)
+	for _, e := range entries {
+		switch u := byID[e[1]]; {
+		case u != "":
+			urls = append(urls, u)
+		case e[0] == archiveYouTube:
+			urls = append(urls, youTubeWatchURL+url.QueryEscape(e[1]))
+		default:
+			unresolved = append(unresolved, e[0]+" "+e[1])
+		}
+	}
+
+	if len(unresolved) > 0 {
+		logging.W("Skipping %d archive entries not matching a video of channel %q", len(unresolved), c.Name)
+		for i, e := range unresolved {
+			if i == maxArchiveShown {
+				logging.P("...and %d more", len(unresolved)-maxArchiveShown)
+				break
+			}
+			logging.P("%s", e)
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	results, err := s.VideoStore().BulkVideoAction(c.ID, consts.BulkIgnore, nil, urls)
+	if err != nil {
+		for _, r := range results {
+			if r.Err != nil {
+				logging.E(0, "Failed to import %q: %v", r.URL, r.Err)
+			}
+		}
+		return err
+	}
+	logging.S(0, "Marked %d videos from download archive %q as finished in channel %q", len(results), path, c.Name)
+	return nil
+}
+
+// ExportArchive writes the channel's finished and ignored videos to a yt-dlp download archive file.
+//
+// If appendTo is set, entries are added to an existing file, skipping those already in it. Videos whose
+// extractor can't be told from their URL or metadata are skipped.
+func ExportArchive(s interfaces.Store, c *models.Channel, path string, appendTo bool) error {
+	grabbed, err := s.ChannelStore().LoadGrabbedURLs(c)
+	if err != nil {
+		return err
+	}
+
+	jsonPaths := make(map[string]string, len(grabbed))
+	if err := s.VideoStore().StreamChannelVideos(c.ID, func(v *models.Video) error {
+		if v.JSONPath != "" {
+			jsonPaths[v.URL] = v.JSONPath
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	existing := make(map[string]struct{})
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendTo {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		entries, err := readArchive(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		for _, e := range entries {
+			existing[e[0]+" "+e[1]] = struct{}{}
+		}
+	}
+
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+
+	written, skipped := 0, 0
+	for _, u := range grabbed {
+		extractor, id := archiveEntry(u, jsonPaths[u])
+		if extractor == "" {
+			logging.D(1, "No extractor known for %q, leaving it out of the archive", u)
+			skipped++
+			continue
+		}
+		line := extractor + " " + id
+		if _, ok := existing[line]; ok {
+			continue
+		}
+		existing[line] = struct{}{}
+		if _, err := w.WriteString(line + "\n"); err != nil {
+			f.Close()
+			return err
+		}
+		written++
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if skipped > 0 {
+		logging.W("Left %d videos with no known extractor out of the archive", skipped)
+	}
+	logging.S(0, "Wrote %d entries for channel %q to download archive %q", written, c.Name, path)
+	return nil
+}
+
+// archiveEntry returns the yt-dlp archive extractor and ID of a video, from its URL for YouTube or else its metadata.
+func archiveEntry(videoURL, jsonPath string) (extractor, id string) {
+	if u, err := url.Parse(videoURL); err == nil {
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		if host == "youtu.be" || host == "youtube.com" || strings.HasSuffix(host, ".youtube.com") {
+			if id = videoIDFromURL(videoURL); id != "" {
+				return archiveYouTube, id
+			}
+		}
+	}
+	if jsonPath == "" {
+		return "", ""
+	}
+	m, err := nfo.ReadMetadata(jsonPath)
+	if err != nil || m.ExtractorKey == "" || m.ID == "" {
+		return "", ""
+	}
+	return strings.ToLower(m.ExtractorKey), m.ID
+}
+
+// readArchive reads the extractor and ID of each entry in a download archive file.
+func readArchive(path string) ([][2]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries [][2]string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 4096), archiveLineLimit)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		extractor, id, ok := strings.Cut(line, " ")
+		if !ok || strings.TrimSpace(id) == "" {
+			return nil, fmt.Errorf("invalid download archive line %d in %q: %q, expected 'extractor id'", n, path, line)
+		}
+		entries = append(entries, [2]string{strings.ToLower(extractor), strings.TrimSpace(id)})
+	}
+	return entries, sc.Err()
+}
@@ -0,0 +1,171 @@
+package process
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	tuneMinSamples   = 5
+	tuneHighFailRate = 0.5
+	tuneLowFailRate  = 0.1
+	tuneBaseDelay    = 5 * time.Second
+	tuneMaxDelay     = 5 * time.Minute
+)
+
+// botBlockSignatures are lowercase output fragments indicating the host is rate-limiting or bot-blocking.
+var botBlockSignatures = []string{
+	"http error 429",
+	"status 429",
+	"status code 429",
+	"too many requests",
+	"not a bot",
+	"captcha",
+	"rate-limit",
+	"rate limit",
+}
+
+// tuneHost computes the effective concurrency and delay for a host from its recorded results.
+//
+// Concurrency is halved and the delay doubled while error rates are high or bot blocks were seen since the last tuning,
+// then both are gradually restored towards the configured values when things are healthy.
+func tuneHost(hs interfaces.HostStore, hostname string, baseConc int) (conc int, delay time.Duration) {
+	conc = baseConc
+	h, err := hs.GetHostStats(hostname)
+	if err != nil {
+		logging.E(0, "Could not get stats for host %q, using configured concurrency: %v", hostname, err)
+		return baseConc, 0
+	}
+
+	if h.Concurrency > 0 && h.Concurrency < baseConc {
+		conc = h.Concurrency
+	}
+	delay = h.Delay
+
+	total := h.Successes + h.Failures
+	if total < tuneMinSamples && h.NewBlocks == 0 {
+		return conc, delay
+	}
+
+	failRate := float64(h.Failures) / float64(max(total, 1))
+
+	switch {
+	case h.NewBlocks > 0 || failRate > tuneHighFailRate:
+		conc = max(conc/2, 1)
+		delay = min(max(delay*2, tuneBaseDelay), tuneMaxDelay)
+		logging.W("Host %q failure rate %.0f%% (new bot blocks: %d), lowering concurrency to %d with %v delay",
+			hostname, failRate*100, h.NewBlocks, conc, delay)
+
+	case failRate < tuneLowFailRate:
+		conc = min(conc+1, baseConc)
+		if delay /= 2; delay < time.Second {
+			delay = 0
+		}
+		logging.D(1, "Host %q healthy (failure rate %.0f%%), restoring concurrency to %d with %v delay",
+			hostname, failRate*100, conc, delay)
+	}
+
+	if err := hs.SetHostTuning(hostname, conc, delay, h.NewBlocks); err != nil {
+		logging.E(0, "Failed to save tuning for host %q: %v", hostname, err)
+	}
+	return conc, delay
+}
+
+// recordHostResult stores the outcome of a video job for its host.
+//
+// Bot blocks are also added to the channel's event history, and escalated.
+func recordHostResult(hs interfaces.HostStore, cs interfaces.ChannelStore, v *models.Video, jobErr error) {
+	hostname := videoHostname(v)
+	if hostname == "" {
+		return
+	}
+
+	botBlock := isBotBlock(jobErr)
+	if err := hs.RecordResult(hostname, jobErr == nil, botBlock); err != nil {
+		logging.E(0, "Failed to record result for host %q: %v", hostname, err)
+	}
+
+	if botBlock {
+		if err := cs.RecordChannelEvent(v.ChannelID, consts.ActivityBotBlock, hostname+": "+v.URL); err != nil {
+			logging.E(0, "Failed to record bot block for channel %d: %v", v.ChannelID, err)
+		}
+		escalateBotBlock(hs, cs, v, hostname)
+	}
+}
+
+// videoHostname returns the hostname of a video URL.
+func videoHostname(v *models.Video) string {
+	u, err := url.Parse(v.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// isBotBlock checks whether an error looks like a rate-limit or bot-detection response.
+func isBotBlock(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, sig := range botBlockSignatures {
+		if strings.Contains(msg, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostOutput is a host's recorded results and effective tuning as served over HTTP.
+type hostOutput struct {
+	Hostname     string    `json:"hostname"`
+	Successes    int       `json:"successes"`
+	Failures     int       `json:"failures"`
+	FailureRate  float64   `json:"failure_rate"`
+	BotBlocks    int       `json:"bot_blocks"`
+	BlockStreak  int       `json:"block_streak"`
+	NewBlocks    int       `json:"new_bot_blocks"`
+	Concurrency  int       `json:"concurrency,omitempty"`
+	DelaySeconds float64   `json:"delay_seconds"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// hostsHandler serves the current auto-tuned concurrency and delay per hostname, like 'tubarr host stats'.
+//
+// Concurrency is left out while a host runs at the configured value.
+func hostsHandler(hs interfaces.HostStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats, err := hs.FetchAllHostStats()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		out := make([]hostOutput, 0, len(stats))
+		for _, h := range stats {
+			ho := hostOutput{
+				Hostname:     h.Hostname,
+				Successes:    h.Successes,
+				Failures:     h.Failures,
+				BotBlocks:    h.BotBlocks,
+				BlockStreak:  h.BlockStreak,
+				NewBlocks:    h.NewBlocks,
+				Concurrency:  h.Concurrency,
+				DelaySeconds: h.Delay.Seconds(),
+				UpdatedAt:    h.UpdatedAt,
+			}
+			if total := h.Successes + h.Failures; total > 0 {
+				ho.FailureRate = float64(h.Failures) / float64(total)
+			}
+			out = append(out, ho)
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
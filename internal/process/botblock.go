@@ -0,0 +1,112 @@
+package process
+
+import (
+	"fmt"
+	"time"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/gotify"
+	"tubarr/internal/utils/logging"
+)
+
+// botBlockMaxBackoff caps how long a host is paused for after repeated bot blocks.
+const botBlockMaxBackoff = 24 * time.Hour
+
+// escalateBotBlock backs off from a host which bot blocked one of the channel's videos, and warns the channel's webhooks.
+//
+// Each block in a row doubles the host's pause, starting from the configured backoff. If the channel has a
+// fallback proxy or cookie source, the first block switches to those instead of pausing.
+func escalateBotBlock(hs interfaces.HostStore, cs interfaces.ChannelStore, v *models.Video, hostname string) {
+	h, err := hs.GetHostStats(hostname)
+	if err != nil {
+		logging.E(0, "Failed to get block streak for host %q: %v", hostname, err)
+		return
+	}
+
+	c := v.Channel
+	if c == nil {
+		if c, err, _ = cs.FetchChannel(v.ChannelID); err != nil || c == nil {
+			logging.E(0, "Failed to load channel with ID %d for bot block warning: %v", v.ChannelID, err)
+			return
+		}
+	}
+
+	steps := h.BlockStreak
+	if hasFallbacks(v.Settings) {
+		steps--
+	}
+
+	msg := fmt.Sprintf("Channel %q was bot blocked by %s (%d in a row)", c.Name, hostname, h.BlockStreak)
+	base := cfg.GetDuration(keys.BotBlockBackoff)
+	switch {
+	case base > 0 && steps > 0:
+		backoff := botBlockMaxBackoff
+		if steps < 16 {
+			backoff = min(base<<(steps-1), botBlockMaxBackoff)
+		}
+		until := time.Now().Add(backoff)
+
+		// Longer pauses, such as ones set by hand, are left alone
+		if p, err := hs.ActivePause(hostname); err == nil && p != nil && (p.Until.IsZero() || !p.Until.Before(until)) {
+			msg += ", downloads are already paused for " + p.Describe()
+			break
+		}
+		if err := hs.SetPause(hostname, until, fmt.Sprintf("bot blocked %d times in a row", h.BlockStreak)); err != nil {
+			logging.E(0, "Failed to pause downloads for host %q: %v", hostname, err)
+			break
+		}
+		msg += fmt.Sprintf(", pausing downloads from the host for %s", backoff)
+	case h.BlockStreak == 1 && hasFallbacks(v.Settings):
+		msg += ", switching to its fallback proxy or cookie source"
+	}
+	logging.W("%s", msg)
+	notifyGotifyEvent(cs, c, gotify.EventBlocked, msg)
+	publishBlocked(c, msg)
+
+	hooks, err := cs.GetWebhooks(c.ID)
+	if err != nil {
+		logging.E(0, "Failed to load webhooks for channel %q: %v", c.Name, err)
+		return
+	}
+	for _, err := range sendWarningWebhooks(c, hooks, msg) {
+		logging.E(0, "%v", err)
+	}
+}
+
+// applyFallbacks switches the video to the channel's fallback proxy and cookie source while its host is bot blocking.
+//
+// Fallbacks stay in use until a download from the host succeeds.
+func applyFallbacks(hs interfaces.HostStore, v *models.Video) {
+	if !hasFallbacks(v.Settings) {
+		return
+	}
+	hostname := videoHostname(v)
+	if hostname == "" {
+		return
+	}
+	h, err := hs.GetHostStats(hostname)
+	if err != nil {
+		logging.E(0, "Failed to get block streak for host %q: %v", hostname, err)
+		return
+	}
+	if h.BlockStreak == 0 {
+		return
+	}
+
+	if v.Settings.FallbackProxy != "" {
+		v.Settings.Proxies = []string{v.Settings.FallbackProxy}
+	}
+	if v.Settings.FallbackCookieSource != "" {
+		v.Settings.CookieSource = v.Settings.FallbackCookieSource
+		v.CookiePath = ""
+	}
+	logging.I("Host %q bot blocked the last %d downloads, using fallbacks for %q", hostname, h.BlockStreak, v.URL)
+}
+
+// hasFallbacks reports whether the settings have a fallback proxy or cookie source.
+func hasFallbacks(s models.ChannelSettings) bool {
+	return s.FallbackProxy != "" || s.FallbackCookieSource != ""
+}
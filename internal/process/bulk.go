@@ -0,0 +1,86 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const maxBulkInput = 1 << 20
+
+// bulkResult is the outcome of a bulk action on one video, as returned by the HTTP API.
+type bulkResult struct {
+	VideoID int64  `json:"video_id"`
+	URL     string `json:"url"`
+	Error   string `json:"error,omitempty"`
+}
+
+// bulkVideosHandler applies a bulk action to videos of the channel in the request path, like 'video bulk'.
+//
+// The body is JSON with the action and the video IDs and/or URLs. Actions deleting files are only for admins,
+// and must be confirmed by sending the same request again with the returned token.
+func bulkVideosHandler(s interfaces.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid channel ID", http.StatusBadRequest)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxBulkInput)
+		var req struct {
+			Action string   `json:"action"`
+			IDs    []int64  `json:"video_ids"`
+			URLs   []string `json:"video_urls"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(req.IDs) == 0 && len(req.URLs) == 0 {
+			http.Error(w, "must enter at least one video URL or ID", http.StatusBadRequest)
+			return
+		}
+
+		action := consts.BulkAction(req.Action)
+		deletesFiles := action == consts.BulkDeleteFilesKeep || action == consts.BulkRedownload
+		if deletesFiles && !requestUser(r).IsAdmin() {
+			http.Error(w, "only admins can delete files", http.StatusForbidden)
+			return
+		}
+		if deletesFiles {
+			summary := fmt.Sprintf("delete the downloaded files of %d videos in channel with ID %d, keeping their records", len(req.IDs)+len(req.URLs), id)
+			if !confirmRequest(s.ConfirmStore(), w, r, models.BulkOperation(id, req.Action, req.IDs, req.URLs), summary) {
+				return
+			}
+		}
+
+		results, err := s.VideoStore().BulkVideoAction(id, action, req.IDs, req.URLs)
+		out := make([]bulkResult, 0, len(results))
+		for _, res := range results {
+			br := bulkResult{VideoID: res.VideoID, URL: res.URL}
+			if res.Err != nil {
+				br.Error = res.Err.Error()
+			}
+			out = append(out, br)
+		}
+		if err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"error": err.Error(), "results": out})
+			return
+		}
+
+		if deletesFiles {
+			if _, err := s.StorageStore().RefreshChannelStorage(id); err != nil {
+				logging.E(0, "Failed to refresh storage usage for channel with ID %d: %v", id, err)
+			}
+		}
+		logging.S(0, "Applied %s to %d videos in channel with ID %d for HTTP user %q", action, len(results), id, requestUser(r).Username)
+		writeJSON(w, http.StatusOK, map[string]any{"results": out})
+	}
+}
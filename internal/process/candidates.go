@@ -0,0 +1,167 @@
+package process
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const candidateBatchSize = 256
+
+// skippedOutput is a skipped video as served over HTTP.
+type skippedOutput struct {
+	URL       string    `json:"url"`
+	Reason    string    `json:"reason"`
+	Detail    string    `json:"detail,omitempty"`
+	SkippedAt time.Time `json:"skipped_at"`
+}
+
+// skippedHandler serves the videos skipped in the channel's crawls and why, like 'video skipped'.
+func skippedHandler(ss interfaces.SkipStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		skipped, err := ss.FetchSkipped(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out := make([]skippedOutput, 0, len(skipped))
+		for _, sv := range skipped {
+			out = append(out, skippedOutput{URL: sv.URL, Reason: string(sv.Reason), Detail: sv.Detail, SkippedAt: sv.CreatedAt})
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// skipBatch holds the skip records of a crawl's videos, so they are written a batch at a time.
+type skipBatch struct {
+	mu    sync.Mutex
+	skips []*models.SkippedVideo
+}
+
+// add holds a skip record, writing the batch once it is full. Without a batch the record is written straight away.
+func (b *skipBatch) add(ss interfaces.SkipStore, sv *models.SkippedVideo) {
+	if !cfg.GetBool(keys.RecordSkips) {
+		return
+	}
+	if b == nil {
+		recordSkips(ss, []*models.SkippedVideo{sv})
+		return
+	}
+
+	b.mu.Lock()
+	b.skips = append(b.skips, sv)
+	var full []*models.SkippedVideo
+	if len(b.skips) >= candidateBatchSize {
+		full, b.skips = b.skips, nil
+	}
+	b.mu.Unlock()
+
+	recordSkips(ss, full)
+}
+
+// flush writes any held skip records.
+func (b *skipBatch) flush(ss interfaces.SkipStore) {
+	b.mu.Lock()
+	held := b.skips
+	b.skips = nil
+	b.mu.Unlock()
+
+	recordSkips(ss, held)
+}
+
+// recordSkips writes skip records in one transaction.
+func recordSkips(ss interfaces.SkipStore, svs []*models.SkippedVideo) {
+	if len(svs) == 0 {
+		return
+	}
+	if err := ss.RecordSkips(svs); err != nil {
+		logging.E(0, "Failed to record %d skipped videos: %v", len(svs), err)
+	}
+}
+
+// queueCandidates passes videos through the lookup and filter stages a batch at a time, then on to the workers.
+//
+// Each stage runs in its own goroutine, so workers start on the first batch while later ones are still looked up.
+// Videos dropped before the workers are reported to results like finished jobs. Closes jobs once every video is sent.
+func queueCandidates(s interfaces.Store, c *models.Channel, videos []*models.Video, skips *skipBatch, jobs chan<- *models.Video, results chan<- error) {
+	var (
+		batches  = make(chan []*models.Video, 1)
+		lookedUp = make(chan []*models.Video, 1)
+	)
+
+	// Enumerate
+	go func() {
+		defer close(batches)
+		for start := 0; start < len(videos); start += candidateBatchSize {
+			batches <- videos[start:min(start+candidateBatchSize, len(videos))]
+		}
+	}()
+
+	// Look up stored videos, one query per batch
+	go func() {
+		defer close(lookedUp)
+		for batch := range batches {
+			lookupVideoIDs(s.VideoStore(), c, batch)
+			lookedUp <- batch
+		}
+	}()
+
+	// Filter and enqueue
+	go func() {
+		defer close(jobs)
+		cs, ss := s.ChannelStore(), s.SkipStore()
+
+		for batch := range lookedUp {
+			for _, v := range batch {
+				switch {
+				case v == nil:
+					logging.E(0, "Video in queue for channel %q is nil", c.Name)
+					results <- nil
+				case ignoredAt(cs, c.ID, v, "metadata download"):
+					publishVideo(c, v, nil)
+					results <- nil
+				case globallyIgnored(cs, ss, skips, v, false):
+					v.Filtered = true
+					publishVideo(c, v, nil)
+					results <- nil
+				default:
+					jobs <- v
+				}
+			}
+		}
+	}()
+}
+
+// lookupVideoIDs sets the IDs of videos in the batch already stored for the channel.
+//
+// Videos left without an ID are looked up again when added, so a failed lookup only costs the batching.
+func lookupVideoIDs(vs interfaces.VideoStore, c *models.Channel, batch []*models.Video) {
+	urls := make([]string, 0, len(batch))
+	for _, v := range batch {
+		if v != nil && v.ID == 0 {
+			urls = append(urls, v.URL)
+		}
+	}
+	if len(urls) == 0 {
+		return
+	}
+
+	ids, err := vs.GetVideoIDs(c.ID, urls)
+	if err != nil {
+		logging.E(0, "Failed to look up %d videos for channel %q: %v", len(urls), c.Name, err)
+		return
+	}
+	for _, v := range batch {
+		if v != nil && v.ID == 0 {
+			v.ID = ids[v.URL]
+		}
+	}
+}
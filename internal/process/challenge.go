@@ -0,0 +1,180 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/browser"
+	"tubarr/internal/utils/gotify"
+	"tubarr/internal/utils/logging"
+)
+
+// maxCookieInput caps the size of cookies pasted into the challenge page.
+const maxCookieInput = 1 << 20
+
+// pendingChallenge is a channel whose crawls are stuck on a login challenge.
+type pendingChallenge struct {
+	ChannelID int64
+	Name      string
+	URL       string
+	Reason    string
+	Since     time.Time
+}
+
+var challenges = struct {
+	mu     sync.Mutex
+	byChan map[int64]pendingChallenge
+}{byChan: make(map[int64]pendingChallenge)}
+
+// noteChallenge records a channel as waiting on the user if the crawl error is a login challenge.
+//
+// The challenge is logged to the activity feed and sent to the channel's webhooks.
+func noteChallenge(cs interfaces.ChannelStore, c *models.Channel, err error) {
+	var ch *browser.ChallengeError
+	if !errors.As(err, &ch) {
+		return
+	}
+
+	challenges.mu.Lock()
+	challenges.byChan[c.ID] = pendingChallenge{
+		ChannelID: c.ID,
+		Name:      c.Name,
+		URL:       ch.URL,
+		Reason:    ch.Reason,
+		Since:     time.Now(),
+	}
+	challenges.mu.Unlock()
+
+	msg := fmt.Sprintf("Channel %q hit a login challenge at %s (%s), paste its cookies to resume crawling", c.Name, ch.URL, ch.Reason)
+	logging.W("%s", msg)
+
+	if err := cs.RecordChannelEvent(c.ID, consts.ActivityChallenge, ch.Reason+": "+ch.URL); err != nil {
+		logging.E(0, "Failed to record challenge for channel %q: %v", c.Name, err)
+	}
+	notifyGotifyEvent(cs, c, gotify.EventBlocked, msg)
+	publishBlocked(c, msg)
+	hooks, err := cs.GetWebhooks(c.ID)
+	if err != nil {
+		logging.E(0, "Failed to load webhooks for channel %q: %v", c.Name, err)
+		return
+	}
+	for _, err := range sendWarningWebhooks(c, hooks, msg) {
+		logging.E(0, "%v", err)
+	}
+}
+
+// clearChallenge removes a channel's pending challenge.
+func clearChallenge(id int64) {
+	challenges.mu.Lock()
+	delete(challenges.byChan, id)
+	challenges.mu.Unlock()
+}
+
+// pendingChallenges returns the channels waiting on a login challenge, oldest first.
+func pendingChallenges() []pendingChallenge {
+	challenges.mu.Lock()
+	defer challenges.mu.Unlock()
+
+	list := make([]pendingChallenge, 0, len(challenges.byChan))
+	for _, p := range challenges.byChan {
+		list = append(list, p)
+	}
+	slices.SortFunc(list, func(a, b pendingChallenge) int {
+		return a.Since.Compare(b.Since)
+	})
+	return list
+}
+
+var challengePage = template.Must(template.New("challenges").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Tubarr login challenges</title></head>
+<body>
+<h1>Login challenges</h1>
+{{if not .}}<p>No channels are waiting on a login challenge.</p>{{end}}
+{{range .}}
+<section>
+<h2>{{.Name}}</h2>
+<p>{{.Reason}} at <a href="{{.URL}}" target="_blank" rel="noopener">{{.URL}}</a> since {{.Since.Format "2006-01-02 15:04:05"}}.</p>
+<p>Log in to the site in your browser, then paste its cookies (a Netscape cookies file or a Cookie header) below.</p>
+<form method="post" action="/challenges/{{.ChannelID}}">
+<textarea name="cookies" rows="8" cols="100" required></textarea><br>
+<button type="submit">Save cookies and resume crawl</button>
+</form>
+</section>
+{{end}}
+</body>
+</html>
+`))
+
+// challengesHandler lists the user's channels waiting on a login challenge, with a form to paste cookies for each.
+func challengesHandler(us interfaces.UserStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var visible []pendingChallenge
+		for _, p := range pendingChallenges() {
+			ok, err := us.CanAccessChannel(requestUser(r), p.ChannelID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if ok {
+				visible = append(visible, p)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := challengePage.Execute(w, visible); err != nil {
+			logging.E(0, "Failed to render challenge page: %v", err)
+		}
+	}
+}
+
+// resolveChallengeHandler saves the cookies pasted for a channel and crawls it again in the background.
+func resolveChallengeHandler(s interfaces.Store, ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid channel ID", http.StatusBadRequest)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxCookieInput)
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		c, err, hasRows := s.ChannelStore().FetchChannel(id)
+		switch {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		case !hasRows:
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		if _, err := browser.SetAuthCookies(c, r.PostFormValue("cookies")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		clearChallenge(c.ID)
+
+		logging.I("Resuming crawl for channel %q with pasted cookies", c.Name)
+		go func() {
+			if err := ChannelCrawl(s, c, ctx); err != nil {
+				logging.E(0, "Crawl for channel %q failed after pasting cookies: %v", c.Name, err)
+			}
+		}()
+		http.Redirect(w, r, "/challenges", http.StatusSeeOther)
+	}
+}
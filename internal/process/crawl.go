@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,8 +19,16 @@ import (
 	"tubarr/internal/domain/keys"
 	"tubarr/internal/interfaces"
 	"tubarr/internal/models"
+	"tubarr/internal/schedule"
+	"tubarr/internal/utils/apprise"
 	"tubarr/internal/utils/browser"
+	"tubarr/internal/utils/chat"
+	"tubarr/internal/utils/email"
+	"tubarr/internal/utils/gotify"
 	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/plex"
+	"tubarr/internal/utils/proxy"
+	"tubarr/internal/utils/ytdlp"
 )
 
 var (
@@ -26,10 +36,15 @@ var (
 	lanClient       *http.Client
 	initClientsOnce sync.Once
 	browserInstance *browser.Browser
+
+	// Channels being crawled, so crawls requested over HTTP don't overlap scheduled ones
+	crawlingMu sync.Mutex
+	crawling   = make(map[int64]struct{})
 )
 
 const (
 	applicationJSON = "application/json"
+	clientTimeout   = 10 * time.Second
 )
 
 func init() {
@@ -40,10 +55,10 @@ func init() {
 func initClients() {
 	initClientsOnce.Do(func() {
 		regClient = &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: clientTimeout,
 		}
 		lanClient = &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: clientTimeout,
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{
 					InsecureSkipVerify: true, // Skip SSL verification for self-hosted servers
@@ -54,14 +69,34 @@ func initClients() {
 	})
 }
 
+// httpClient returns the client for a request to the host, routing public hosts through the channel's proxies.
+//
+// Hosts on private networks, such as self-hosted media servers, are never proxied.
+func httpClient(c *models.Channel, host string) *http.Client {
+	if isPrivateNetwork(host) {
+		return lanClient
+	}
+	if p := proxy.Pick(c.Settings.Proxies); p != "" {
+		return proxy.Client(p, clientTimeout)
+	}
+	return regClient
+}
+
 // CrawlIgnoreNew gets the channel's currently displayed videos and ignores them on subsequent crawls.
 //
 // Essentially it marks the URLs it finds as though they have already been downloaded.
 func CrawlIgnoreNew(s interfaces.Store, c *models.Channel, ctx context.Context) error {
-	videos, err := browserInstance.GetNewReleases(s.ChannelStore(), c, ctx)
+	if err := ytdlp.CheckPlugins(ctx); err != nil {
+		return err
+	}
+
+	videos, err := browserInstance.GetNewReleases(s, c, ctx)
 	if err != nil {
+		noteChallenge(s.ChannelStore(), c, err)
 		return err
 	}
+	clearChallenge(c.ID)
+	collectPosts(s, c, ctx)
 
 	if len(videos) > 0 {
 		for _, v := range videos {
@@ -86,78 +121,192 @@ func CrawlIgnoreNew(s interfaces.Store, c *models.Channel, ctx context.Context)
 	return nil
 }
 
+// VerifyComplete lists remote videos for the channel which have never been recorded by Tubarr.
+//
+// If enqueue is set, the missing videos are downloaded.
+func VerifyComplete(s interfaces.Store, c *models.Channel, enqueue bool, ctx context.Context) error {
+	if err := ytdlp.CheckPlugins(ctx); err != nil {
+		return err
+	}
+
+	videos, err := browserInstance.GetUnseenReleases(s, c, ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(videos) == 0 {
+		logging.S(0, "Archive for channel %q is complete, no unseen remote videos", c.Name)
+		return nil
+	}
+
+	fmt.Printf("\n%sUnseen videos for channel %q:%s\n", consts.ColorYellow, c.Name, consts.ColorReset)
+	for _, v := range videos {
+		fmt.Println(v.URL)
+	}
+	fmt.Println()
+
+	if !enqueue {
+		logging.I("Found %d unseen videos in channel %q, run with --enqueue to download them", len(videos), c.Name)
+		return nil
+	}
+
+	if err := checkChannelMounts(c); err != nil {
+		return err
+	}
+
+	success, errArray := InitProcess(s, c, videos, ctx)
+	refreshStorage(s, c)
+	if !success && len(errArray) > 0 {
+		return fmt.Errorf("encountered %d errors during processing: %v", len(errArray), errArray)
+	}
+	for _, err := range errArray {
+		logging.E(0, "Error downloading unseen video in channel %q: %v", c.Name, err)
+	}
+	return nil
+}
+
 // CheckChannels checks channels and whether they are due for a crawl.
 func CheckChannels(s interfaces.Store, ctx context.Context) error {
+	_, err := crawlDueChannels(s, ctx)
+	return err
+}
+
+// crawlDueChannels crawls each channel which is due, returning when the next channel is due.
+func crawlDueChannels(s interfaces.Store, ctx context.Context) (nextDue time.Time, err error) {
 	cs := s.ChannelStore()
 	chans, err, hasRows := cs.FetchAllChannels()
 	if !hasRows {
 		logging.I("No channels in database")
 	} else if err != nil {
-		return err
-	}
-
-	var (
-		wg sync.WaitGroup
-	)
-
-	conc := cfg.GetInt(keys.Concurrency)
-	if conc < 1 {
-		conc = 1
+		return nextDue, err
 	}
 
-	sem := make(chan struct{}, conc)
-	errChan := make(chan error, len(chans))
+	nextDue = retryDue(s, chans, ctx)
 
+	due := make([]*models.Channel, 0, len(chans))
 	for i := range chans {
-
-		timeSinceLastScan := time.Since(chans[i].LastScan)
-		crawlFreqDuration := time.Duration(chans[i].Settings.CrawlFreq) * time.Minute
+		now := time.Now()
+		timeSinceLastScan := now.Sub(chans[i].LastScan)
 
 		fmt.Println()
-		logging.I("Time since last check for channel %q: %s\nCrawl frequency: %d minutes",
-			chans[i].Name,
-			timeSinceLastScan.Round(time.Second),
-			chans[i].Settings.CrawlFreq)
-
-		if timeSinceLastScan < crawlFreqDuration {
-			remainingTime := crawlFreqDuration - timeSinceLastScan
-			logging.P("Next check in: %s", remainingTime.Round(time.Second))
+		if chans[i].Settings.CrawlCron != "" {
+			logging.I("Time since last check for channel %q: %s\nCrawl schedule: %s",
+				chans[i].Name,
+				timeSinceLastScan.Round(time.Second),
+				chans[i].Settings.CrawlCron)
+		} else {
+			logging.I("Time since last check for channel %q: %s\nCrawl frequency: %d minutes",
+				chans[i].Name,
+				timeSinceLastScan.Round(time.Second),
+				chans[i].Settings.CrawlFreq)
+		}
+
+		// Paused URLs are left out of the pass, rather than crawled only to be skipped
+		if p, paused := urlPaused(s.HostStore(), chans[i].URL); paused {
+			logging.P("Crawls paused for %s", p.Describe())
 			fmt.Println()
+			if !p.Until.IsZero() && (nextDue.IsZero() || p.Until.Before(nextDue)) {
+				nextDue = p.Until
+			}
 			continue
 		}
 
-		wg.Add(1)
-		go func(c *models.Channel) {
-			defer wg.Done()
-
-			sem <- struct{}{}
-			defer func() {
-				<-sem
-			}()
+		next := schedule.Next(chans[i], now)
+		if next.At.IsZero() {
+			logging.P("Crawl schedule %q never matches, skipping channel", chans[i].Settings.CrawlCron)
+			fmt.Println()
+			continue
+		}
 
-			if err := ChannelCrawl(s, c, ctx); err != nil {
-				errChan <- err
+		if next.At.After(now) {
+			if next.Reason != "" {
+				logging.P("Next check in: %s (held by %s)", next.At.Sub(now).Round(time.Second), next.Reason)
+			} else {
+				logging.P("Next check in: %s", next.At.Sub(now).Round(time.Second))
 			}
-		}(chans[i])
-	}
+			fmt.Println()
+			if nextDue.IsZero() || next.At.Before(nextDue) {
+				nextDue = next.At
+			}
+			continue
+		}
 
-	wg.Wait()
-	close(errChan)
+		due = append(due, chans[i])
+	}
 
-	var errors []error
-	for err := range errChan {
-		errors = append(errors, err)
+	started := time.Now()
+	errs, reports := crawlChannels(s, due, ctx)
+	if len(reports) > 0 {
+		reportCrawl(s, &models.CrawlReport{StartedAt: started, FinishedAt: time.Now(), Channels: reports})
+	}
+	if len(errs) > 0 {
+		return nextDue, fmt.Errorf("encountered %d errors during processing: %v", len(errs), errs)
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("encountered %d errors during processing: %v", len(errors), errors)
+	return nextDue, nil
+}
+
+// recordCrawlEvent adds a crawl run to the channel's event history.
+//
+// Crawls finding nothing are collapsed into one entry while they repeat, so they don't bury the rest of the history.
+func recordCrawlEvent(cs interfaces.ChannelStore, c *models.Channel, detail string, noop bool) {
+	record := cs.RecordChannelEvent
+	if noop {
+		record = cs.RepeatChannelEvent
+	}
+	if err := record(c.ID, consts.ActivityCrawl, detail); err != nil {
+		logging.E(0, "Failed to record crawl for channel %q: %v", c.Name, err)
 	}
+}
 
-	return nil
+// activityOutput is a channel activity event as served over HTTP.
+type activityOutput struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	VideoID int64     `json:"video_id,omitempty"`
+	URL     string    `json:"url,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+}
+
+// activityHandler serves the channel's merged activity history, newest first, like 'channel activity'.
+//
+// The newest 50 events are included, or as many as the limit query parameter asks for (0 for all).
+func activityHandler(cs interfaces.ChannelStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		limit := 50
+		if q := r.URL.Query().Get("limit"); q != "" {
+			n, err := strconv.Atoi(q)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+
+		events, err := cs.FetchChannelActivity(id, limit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out := make([]activityOutput, 0, len(events))
+		for _, e := range events {
+			out = append(out, activityOutput{Time: e.Time, Kind: string(e.Kind), VideoID: e.VideoID, URL: e.URL, Detail: e.Detail})
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
 }
 
 // ChannelCrawl crawls a channel for new URLs.
 func ChannelCrawl(s interfaces.Store, c *models.Channel, ctx context.Context) error {
+	var report models.ChannelReport
+	err := crawlChannel(s, c, &report, ctx)
+	finishCrawl(s.ChannelStore(), c, &report, err)
+	return err
+}
+
+// crawlChannel crawls a channel for new URLs, filling in the report's counts if it is not nil.
+func crawlChannel(s interfaces.Store, c *models.Channel, report *models.ChannelReport, ctx context.Context) error {
 	const (
 		errMsg = "encountered %d errors during processing: %v"
 	)
@@ -172,12 +321,42 @@ func ChannelCrawl(s interfaces.Store, c *models.Channel, ctx context.Context) er
 		return errors.New("output directories are blank")
 	}
 
+	if !claimCrawl(c.ID) {
+		return fmt.Errorf("channel %q is already being crawled", c.Name)
+	}
+	defer releaseCrawl(c.ID)
+
+	if p, paused := downloadsPaused(s.HostStore(), c.URL); paused {
+		logging.I("Skipping crawl for channel %q, downloads are paused for %s", c.Name, p.Describe())
+		return nil
+	}
+	if p, paused := groupPaused(s.ChannelStore(), s.HostStore(), c); paused {
+		logging.I("Skipping crawl for channel %q, downloads are paused for %s", c.Name, p.Describe())
+		return nil
+	}
+
+	if err := checkChannelMounts(c); err != nil {
+		return err
+	}
+	defer applyRetention(s, c)
+
+	if err := ytdlp.CheckPlugins(ctx); err != nil {
+		return err
+	}
+
+	if cfg.GetBool(keys.RecordSkips) {
+		pruneSkipped(s.SkipStore())
+	}
+
 	cs := s.ChannelStore()
+	pruneEvents(cs)
 
-	videos, err := browserInstance.GetNewReleases(cs, c, ctx)
+	videos, err := browserInstance.GetNewReleases(s, c, ctx)
 	if err != nil {
+		noteChallenge(cs, c, err)
 		return err
 	}
+	clearChallenge(c.ID)
 
 	var (
 		success  bool
@@ -186,12 +365,32 @@ func ChannelCrawl(s interfaces.Store, c *models.Channel, ctx context.Context) er
 
 	if len(videos) == 0 {
 		logging.I("No new releases for channel %q", c.URL)
+		recordCrawlEvent(cs, c, "no new videos", true)
+
+		// Still a completed crawl, so the channel isn't due again until its next scheduled check
+		if err := cs.UpdateLastScan(c.ID); err != nil {
+			return fmt.Errorf("failed to update last scan time: %w", err)
+		}
 		return nil
 	} else {
+		var deferred int
+		videos, deferred = capDownloads(c, videos)
+
 		success, errArray = InitProcess(s, c, videos, ctx)
 		if errArray != nil {
 			logging.AddToErrorArray(err)
 		}
+		refreshStorage(s, c)
+		if report != nil {
+			countResults(report, videos, len(errArray))
+		}
+
+		summary := fmt.Sprintf("%d new videos, %d errors", len(videos), len(errArray))
+		if deferred > 0 {
+			summary += fmt.Sprintf(", %d deferred to later crawls", deferred)
+			logging.I("Backlog for channel %q: %d videos remaining after this crawl", c.Name, deferred)
+		}
+		recordCrawlEvent(cs, c, summary, false)
 
 		if err := cs.UpdateLastScan(c.ID); err != nil {
 			return fmt.Errorf("failed to update last scan time: %w", err)
@@ -202,7 +401,21 @@ func ChannelCrawl(s interfaces.Store, c *models.Channel, ctx context.Context) er
 		}
 	}
 
-	// Some successful downloads, notify URLs
+	// Some successful downloads, notify URLs and webhooks
+	if err := notifyChannel(s, c, videos); err != nil {
+		return err
+	}
+
+	if len(errArray) > 0 {
+		return fmt.Errorf(errMsg, len(errArray), errArray)
+	}
+
+	return nil
+}
+
+// notifyChannel sends the channel's notification URLs and webhooks after downloads.
+func notifyChannel(s interfaces.Store, c *models.Channel, videos []*models.Video) error {
+	cs := s.ChannelStore()
 	notifyURLs, err := cs.GetNotifyURLs(c.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -211,8 +424,43 @@ func ChannelCrawl(s interfaces.Store, c *models.Channel, ctx context.Context) er
 		logging.D(1, "No notification URL for channel with name %q and ID: %d", c.Name, c.ID)
 	}
 
-	if len(notifyURLs) > 0 {
-		if errs := notify(c, notifyURLs); len(errs) != 0 {
+	webhooks, err := cs.GetWebhooks(c.ID)
+	if err != nil {
+		logging.E(0, "Failed to load webhooks for channel %q: %v", c.Name, err)
+	}
+
+	// Email recipients and chat targets are stored as prefixed URLs alongside the HTTP notification URLs
+	recipients := email.Recipients(notifyURLs)
+	appriseURLs := append(apprise.URLs(notifyURLs), cfg.GetStringSlice(keys.AppriseURLs)...)
+	var (
+		chats   []string
+		gotifys []*gotify.Target
+	)
+	notifyURLs = slices.DeleteFunc(notifyURLs, func(u string) bool {
+		if _, _, ok := chat.Parse(u); ok {
+			chats = append(chats, u)
+			return true
+		}
+		if t, ok := gotify.Parse(u); ok {
+			gotifys = append(gotifys, t)
+			return true
+		}
+		return strings.HasPrefix(u, email.MailtoScheme) || strings.HasPrefix(u, apprise.Scheme)
+	})
+
+	if len(notifyURLs) > 0 || len(webhooks) > 0 || len(recipients) > 0 || len(chats) > 0 || len(appriseURLs) > 0 || len(gotifys) > 0 {
+		var errs []error
+		if len(notifyURLs) > 0 {
+			errs = notify(c, notifyURLs)
+		}
+		errs = append(errs, sendWebhooks(c, webhooks, videos)...)
+		if err := sendEmails(cs, c, recipients, videos); err != nil {
+			errs = append(errs, err)
+		}
+		errs = append(errs, sendChats(c, chats, videos)...)
+		errs = append(errs, sendApprise(c, appriseURLs, videos)...)
+		errs = append(errs, sendGotifyVideos(c, gotifys, videos)...)
+		if len(errs) != 0 {
 			var b strings.Builder
 			totalLength := 0
 			for _, err := range errs {
@@ -229,15 +477,142 @@ func ChannelCrawl(s interfaces.Store, c *models.Channel, ctx context.Context) er
 			}
 			return fmt.Errorf("errors sending notifications for channel with ID %d:\n%s", c.ID, b.String())
 		}
+		markNotified(s.VideoStore(), videos)
 	}
+	return nil
+}
 
-	if len(errArray) > 0 {
-		return fmt.Errorf(errMsg, len(errArray), errArray)
+// sendEmails mails the channel's downloaded videos to the recipients.
+func sendEmails(cs interfaces.ChannelStore, c *models.Channel, recipients []string, videos []*models.Video) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	done := slices.DeleteFunc(slices.Clone(videos), func(v *models.Video) bool {
+		return v.DownloadStatus.Status != consts.DLStatusCompleted
+	})
+	if len(done) == 0 {
+		return nil
 	}
 
+	smtpCfg, err := cs.GetSMTPConfig()
+	if err != nil {
+		return err
+	}
+	if err := email.Send(smtpCfg, recipients, email.FromVideos(c, done)); err != nil {
+		return fmt.Errorf("failed to send email for channel %q: %w", c.Name, err)
+	}
+	logging.S(1, "Emailed %d videos in channel %q to %v", len(done), c.Name, recipients)
 	return nil
 }
 
+// sendChats posts a message for each of the channel's downloaded videos to the chat targets.
+func sendChats(c *models.Channel, chats []string, videos []*models.Video) []error {
+	if len(chats) == 0 {
+		return nil
+	}
+	initClients()
+
+	var errs []error
+	for _, notifyURL := range chats {
+		provider, target, _ := chat.Parse(notifyURL)
+		parsed, err := url.Parse(target)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s notification URL: %w", provider, err))
+			continue
+		}
+		client := httpClient(c, parsed.Host)
+
+		sent := 0
+		for _, v := range videos {
+			if v.DownloadStatus.Status != consts.DLStatusCompleted {
+				continue
+			}
+			if err := chat.Send(client, provider, target, chat.FromVideo(c, v)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to send %s message for %q: %w", provider, v.URL, err))
+				continue
+			}
+			sent++
+		}
+		if sent > 0 {
+			logging.S(1, "Sent %d %s messages for channel %q", sent, provider, c.Name)
+		}
+	}
+	return errs
+}
+
+// sendApprise notifies the Apprise URLs of each of the channel's downloaded videos.
+func sendApprise(c *models.Channel, appriseURLs []string, videos []*models.Video) []error {
+	if len(appriseURLs) == 0 {
+		return nil
+	}
+	initClients()
+	clientFor := func(host string) *http.Client {
+		return httpClient(c, host)
+	}
+
+	var errs []error
+	for _, v := range videos {
+		if v.DownloadStatus.Status != consts.DLStatusCompleted {
+			continue
+		}
+		if err := apprise.Send(clientFor, cfg.GetString(keys.AppriseBin), appriseURLs, chat.FromVideo(c, v)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send Apprise notifications for %q: %w", v.URL, err))
+		}
+	}
+	return errs
+}
+
+// capDownloads limits the videos processed in one crawl to the channel's per-crawl maximum.
+//
+// Videos past the cap aren't stored, so they are found again as new on later crawls.
+func capDownloads(c *models.Channel, videos []*models.Video) (capped []*models.Video, deferred int) {
+	limit := c.Settings.MaxDownloadsPerCrawl
+	if limit < 1 || len(videos) <= limit {
+		return videos, 0
+	}
+	logging.I("Channel %q has %d new videos, downloading %d this crawl (max downloads per crawl)", c.Name, len(videos), limit)
+	return videos[:limit], len(videos) - limit
+}
+
+// refreshStorage recalculates the channel's cached disk usage after downloads.
+func refreshStorage(s interfaces.Store, c *models.Channel) {
+	if _, err := s.StorageStore().RefreshChannelStorage(c.ID); err != nil {
+		logging.E(0, "Failed to refresh storage usage for channel %q: %v", c.Name, err)
+	}
+}
+
+// pruneSkipped removes skipped video records older than the retention period.
+func pruneSkipped(ss interfaces.SkipStore) {
+	days := cfg.GetInt(keys.SkipRetentionDays)
+	if days < 1 {
+		return
+	}
+	n, err := ss.PruneSkipped(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		logging.E(0, "Failed to prune skipped videos: %v", err)
+		return
+	}
+	if n > 0 {
+		logging.D(1, "Pruned %d skipped video records older than %d days", n, days)
+	}
+}
+
+// pruneEvents removes channel events older than the retention period.
+func pruneEvents(cs interfaces.ChannelStore) {
+	days := cfg.GetInt(keys.EventRetentionDays)
+	if days < 1 {
+		return
+	}
+	n, err := cs.PruneChannelEvents(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		logging.E(0, "Failed to prune channel events: %v", err)
+		return
+	}
+	if n > 0 {
+		logging.D(1, "Pruned %d channel events older than %d days", n, days)
+	}
+}
+
 // notify pings notification services as required.
 func notify(c *models.Channel, notifyURLs []string) []error {
 
@@ -245,8 +620,16 @@ func notify(c *models.Channel, notifyURLs []string) []error {
 	initClients()
 
 	// Inner function
-	notifyFunc := func(client *http.Client, notifyURL string) error {
-		resp, err := client.Post(notifyURL, applicationJSON, nil)
+	notifyFunc := func(client *http.Client, notifyURL string, get bool) error {
+		var (
+			resp *http.Response
+			err  error
+		)
+		if get {
+			resp, err = client.Get(notifyURL)
+		} else {
+			resp, err = client.Post(notifyURL, applicationJSON, nil)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to send notification to URL %q for channel %q (ID: %d): %w",
 				notifyURL, c.Name, c.ID, err)
@@ -273,12 +656,9 @@ func notify(c *models.Channel, notifyURLs []string) []error {
 			continue
 		}
 
-		client := regClient
-		if isPrivateNetwork(parsed.Host) {
-			client = lanClient
-		}
+		client := httpClient(c, parsed.Host)
 
-		if err := notifyFunc(client, notifyURL); err != nil {
+		if err := notifyFunc(client, notifyURL, plex.IsRefreshURL(parsed)); err != nil {
 			errs = append(errs, fmt.Errorf("failed to notify URL %q: %w", notifyURL, err))
 			continue
 		}
@@ -292,3 +672,120 @@ func notify(c *models.Channel, notifyURLs []string) []error {
 
 	return errs
 }
+
+// claimCrawl marks a channel as being crawled, returning false if it already is.
+func claimCrawl(id int64) bool {
+	crawlingMu.Lock()
+	defer crawlingMu.Unlock()
+	if _, ok := crawling[id]; ok {
+		return false
+	}
+	crawling[id] = struct{}{}
+	return true
+}
+
+// releaseCrawl marks a channel's crawl as finished.
+func releaseCrawl(id int64) {
+	crawlingMu.Lock()
+	delete(crawling, id)
+	crawlingMu.Unlock()
+}
+
+// crawlHandler starts a crawl of the channel in the request path, for commands sent by another instance.
+func crawlHandler(s interfaces.Store, ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid channel ID", http.StatusBadRequest)
+			return
+		}
+
+		c, err, hasRows := s.ChannelStore().FetchChannel(id)
+		switch {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		case !hasRows:
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		crawlingMu.Lock()
+		_, running := crawling[id]
+		crawlingMu.Unlock()
+		if running {
+			http.Error(w, fmt.Sprintf("channel %q is already being crawled", c.Name), http.StatusConflict)
+			return
+		}
+
+		logging.I("Crawling channel %q for HTTP user %q", c.Name, requestUser(r).Username)
+		goBackground(func() {
+			if err := ChannelCrawl(s, c, ctx); err != nil {
+				logging.E(0, "Crawl of channel %q failed: %v", c.Name, err)
+			}
+		})
+		writeJSON(w, http.StatusAccepted, map[string]any{"channel_id": id})
+	}
+}
+
+// crawlURLHandler starts a crawl of one of the channel's URLs, its crawl URL or its live URL, named by the urlID path value.
+//
+// Crawling the crawl URL crawls the channel, and crawling the live URL checks it for a stream now instead of at its
+// next live check. Paused URLs are refused.
+func crawlURLHandler(s interfaces.Store, ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid channel ID", http.StatusBadRequest)
+			return
+		}
+
+		c, err, hasRows := s.ChannelStore().FetchChannel(id)
+		switch {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		case !hasRows:
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		urlID := r.PathValue("urlID")
+		var chanURL string
+		switch urlID {
+		case consts.ChannelURLCrawl:
+			chanURL = c.URL
+		case consts.ChannelURLLive:
+			chanURL = c.Settings.LiveURL
+		}
+		if chanURL == "" {
+			http.Error(w, fmt.Sprintf("channel %q has no URL %q, expected %s or %s", c.Name, urlID, consts.ChannelURLCrawl, consts.ChannelURLLive), http.StatusNotFound)
+			return
+		}
+		if p, paused := urlPaused(s.HostStore(), chanURL); paused {
+			http.Error(w, fmt.Sprintf("URL %q of channel %q is paused for %s", chanURL, c.Name, p.Describe()), http.StatusConflict)
+			return
+		}
+
+		if urlID == consts.ChannelURLCrawl {
+			crawlHandler(s, ctx)(w, r)
+			return
+		}
+
+		liveBusyMu.Lock()
+		busy := liveBusy[id]
+		liveBusyMu.Unlock()
+		if busy {
+			http.Error(w, fmt.Sprintf("live URL of channel %q is already being checked or captured", c.Name), http.StatusConflict)
+			return
+		}
+
+		logging.I("Checking live URL of channel %q for HTTP user %q", c.Name, requestUser(r).Username)
+		goBackground(func() {
+			if err := CheckLive(s, c, ctx); err != nil {
+				logging.E(0, "Live check of channel %q failed: %v", c.Name, err)
+			}
+		})
+		writeJSON(w, http.StatusAccepted, map[string]any{"channel_id": id, "url_id": urlID, "url": chanURL})
+	}
+}
@@ -0,0 +1,151 @@
+package process
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"sync"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// crawlPool hands channel crawls to a fixed set of workers, holding back channels whose host is at its crawl limit.
+type crawlPool struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []*models.Channel
+	limits  map[string]int // Crawls at once per host, 0 for no limit
+	active  map[string]int
+}
+
+// crawlChannels crawls the channels in parallel, returning the errors of channels which failed
+// and a report of each channel's results.
+//
+// At most --crawl-concurrency channels (defaulting to the concurrency limit) are crawled at once, and at most
+// --crawl-host-concurrency on the same host. With host auto-tuning on, hosts tuned down are limited further.
+func crawlChannels(s interfaces.Store, chans []*models.Channel, ctx context.Context) ([]error, []models.ChannelReport) {
+	if len(chans) == 0 {
+		return nil, nil
+	}
+
+	workers := cfg.GetInt(keys.CrawlConcurrency)
+	if workers < 1 {
+		workers = cfg.GetInt(keys.Concurrency)
+	}
+	workers = min(max(workers, 1), len(chans))
+
+	p := &crawlPool{
+		pending: slices.Clone(chans),
+		limits:  hostCrawlLimits(s.HostStore(), chans),
+		active:  make(map[string]int),
+	}
+	p.cond = sync.NewCond(&p.mu)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errs    []error
+		reports []models.ChannelReport
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				c, host, ok := p.next()
+				if !ok {
+					return
+				}
+				report := models.ChannelReport{ChannelID: c.ID, ChannelName: c.Name}
+				err := crawlChannel(s, c, &report, ctx)
+				p.done(host)
+				finishCrawl(s.ChannelStore(), c, &report, err)
+
+				mu.Lock()
+				if err != nil {
+					report.Error = err.Error()
+					errs = append(errs, err)
+				}
+				reports = append(reports, report)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errs, reports
+}
+
+// next takes the first pending channel whose host has room, waiting for a running crawl to finish if none do.
+//
+// Returns false once no channels are left.
+func (p *crawlPool) next() (c *models.Channel, host string, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.pending) > 0 {
+		if draining() {
+			logging.I("Shutting down, leaving %d due channels for the next run", len(p.pending))
+			p.pending = nil
+			return nil, "", false
+		}
+		for i, c := range p.pending {
+			host := channelHost(c)
+			if limit := p.limits[host]; limit > 0 && p.active[host] >= limit {
+				continue
+			}
+			p.pending = slices.Delete(p.pending, i, i+1)
+			p.active[host]++
+			return c, host, true
+		}
+		p.cond.Wait()
+	}
+	return nil, "", false
+}
+
+// done frees the host's slot once a crawl finishes.
+func (p *crawlPool) done(host string) {
+	p.mu.Lock()
+	p.active[host]--
+	p.mu.Unlock()
+	p.cond.Broadcast()
+}
+
+// hostCrawlLimits returns how many channels on each host may be crawled at once.
+func hostCrawlLimits(hs interfaces.HostStore, chans []*models.Channel) map[string]int {
+	base := max(cfg.GetInt(keys.CrawlHostConcurrency), 0)
+	tune := cfg.GetBool(keys.AutoTuneHosts)
+
+	limits := make(map[string]int)
+	for _, c := range chans {
+		host := channelHost(c)
+		if _, ok := limits[host]; ok {
+			continue
+		}
+		limit := base
+		if tune && host != "" {
+			if h, err := hs.GetHostStats(host); err != nil {
+				logging.E(0, "Could not get stats for host %q, crawling without its tuned limit: %v", host, err)
+			} else if h.Concurrency > 0 && (limit == 0 || h.Concurrency < limit) {
+				limit = h.Concurrency
+			}
+		}
+		if limit > 0 {
+			logging.D(1, "Crawling at most %d channels at once on host %q", limit, host)
+		}
+		limits[host] = limit
+	}
+	return limits
+}
+
+// channelHost returns the hostname of a channel URL.
+func channelHost(c *models.Channel) string {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,110 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/ytdlpbin"
+)
+
+const (
+	diagMinFailures   = 3
+	diagFailRate      = 0.5
+	diagSignatureRate = 0.5
+	diagSignatureLen  = 120
+)
+
+var (
+	// ytdlpErrRx matches yt-dlp error lines such as "ERROR: [youtube] abc123: Sign in to confirm..."
+	ytdlpErrRx = regexp.MustCompile(`ERROR: \[([^\]]+)\](?: [^:\s]+:)? ?([^\n]*)`)
+	digitsRx   = regexp.MustCompile(`\d+`)
+
+	ytdlpVersion     string
+	ytdlpVersionOnce sync.Once
+)
+
+// extractorFailure is a group of failures sharing an extractor and error message.
+type extractorFailure struct {
+	extractor string
+	message   string
+	count     int
+}
+
+// diagnoseFailures checks a processing run for a failure spike likely caused by a broken yt-dlp extractor.
+//
+// Only runs for channels with extractor diagnostics enabled.
+func diagnoseFailures(cs interfaces.ChannelStore, c *models.Channel, attempted int, errs []error, ctx context.Context) {
+	if !c.Settings.ExtractorDiagnostics || ctx.Err() != nil {
+		return
+	}
+	if len(errs) < diagMinFailures || float64(len(errs)) < float64(attempted)*diagFailRate {
+		return
+	}
+
+	groups := make(map[string]*extractorFailure)
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		m := ytdlpErrRx.FindStringSubmatch(err.Error())
+		if m == nil {
+			continue
+		}
+
+		message := digitsRx.ReplaceAllString(strings.TrimSpace(m[2]), "N")
+		if len(message) > diagSignatureLen {
+			message = message[:diagSignatureLen]
+		}
+
+		key := m[1] + "|" + message
+		if g, ok := groups[key]; ok {
+			g.count++
+		} else {
+			groups[key] = &extractorFailure{extractor: m[1], message: message, count: 1}
+		}
+	}
+	if len(groups) == 0 {
+		return
+	}
+
+	sorted := make([]*extractorFailure, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, g)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].count > sorted[j].count })
+
+	top := sorted[0]
+	if float64(top.count) < float64(len(errs))*diagSignatureRate {
+		return
+	}
+
+	detail := fmt.Sprintf("yt-dlp %s, extractor %q failed %d/%d downloads: %s",
+		getYTDLPVersion(ctx), top.extractor, top.count, attempted, top.message)
+
+	logging.E(0, "%sLikely yt-dlp issue for channel %q, updating yt-dlp is recommended.\n%s", consts.RedError, c.Name, detail)
+
+	if err := cs.RecordChannelEvent(c.ID, consts.ActivityExtractorFailure, detail); err != nil {
+		logging.E(0, "Failed to record extractor failure for channel %q: %v", c.Name, err)
+	}
+}
+
+// getYTDLPVersion returns the installed yt-dlp version, looked up once per run.
+func getYTDLPVersion(ctx context.Context) string {
+	ytdlpVersionOnce.Do(func() {
+		v, err := ytdlpbin.Version(ctx)
+		if err != nil {
+			logging.E(0, "%v", err)
+			v = "unknown"
+		}
+		ytdlpVersion = v
+	})
+	return ytdlpVersion
+}
@@ -0,0 +1,93 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/browser"
+	"tubarr/internal/utils/chat"
+	"tubarr/internal/utils/gotify"
+	"tubarr/internal/utils/logging"
+)
+
+// sendGotifyVideos pushes each of the channel's downloaded videos to the Gotify targets which want new videos.
+func sendGotifyVideos(c *models.Channel, targets []*gotify.Target, videos []*models.Video) []error {
+	if len(targets) == 0 {
+		return nil
+	}
+	initClients()
+
+	var errs []error
+	for _, t := range targets {
+		if !t.Wants(gotify.EventVideo) {
+			continue
+		}
+		sent := 0
+		for _, v := range videos {
+			if v.DownloadStatus.Status != consts.DLStatusCompleted {
+				continue
+			}
+			m := chat.FromVideo(c, v)
+			msg := gotify.Message{Title: "New video from " + c.Name, Text: m.Title, ClickURL: m.URL, ImageURL: m.Thumbnail}
+			if err := pushGotify(c, t, gotify.EventVideo, msg); err != nil {
+				errs = append(errs, fmt.Errorf("failed to push %q to Gotify: %w", v.URL, err))
+				continue
+			}
+			sent++
+		}
+		if sent > 0 {
+			logging.S(1, "Pushed %d videos in channel %q to Gotify server %q", sent, c.Name, t.Server)
+		}
+	}
+	return errs
+}
+
+// notifyGotifyEvent pushes a warning about the channel to its Gotify targets which want the event.
+//
+// Failures are logged.
+func notifyGotifyEvent(cs interfaces.ChannelStore, c *models.Channel, event, text string) {
+	notifyURLs, err := cs.GetNotifyURLs(c.ID)
+	if err != nil {
+		return
+	}
+	initClients()
+
+	title := fmt.Sprintf("Tubarr: channel %q blocked", c.Name)
+	if event == gotify.EventFailure {
+		title = fmt.Sprintf("Tubarr: crawl failed for channel %q", c.Name)
+	}
+	for _, u := range notifyURLs {
+		t, ok := gotify.Parse(u)
+		if !ok || !t.Wants(event) {
+			continue
+		}
+		if err := pushGotify(c, t, event, gotify.Message{Title: title, Text: text, ClickURL: c.URL}); err != nil {
+			logging.E(0, "Failed to push %s for channel %q to Gotify: %v", event, c.Name, err)
+		}
+	}
+}
+
+// notifyCrawlFailure pushes a failed crawl to the channel's Gotify targets.
+//
+// Cancelled crawls and login challenges, which push their own block event, are skipped.
+func notifyCrawlFailure(cs interfaces.ChannelStore, c *models.Channel, err error) {
+	var ch *browser.ChallengeError
+	if err == nil || errors.Is(err, context.Canceled) || errors.As(err, &ch) {
+		return
+	}
+	notifyGotifyEvent(cs, c, gotify.EventFailure, err.Error())
+}
+
+// pushGotify sends a message to the target with the client for its host.
+func pushGotify(c *models.Channel, t *gotify.Target, event string, m gotify.Message) error {
+	parsed, err := url.Parse(t.Server)
+	if err != nil {
+		return fmt.Errorf("invalid Gotify server %q: %w", t.Server, err)
+	}
+	return gotify.Send(httpClient(c, parsed.Host), t, event, m)
+}
@@ -0,0 +1,147 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"tubarr/internal/interfaces"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/ytdlpbin"
+)
+
+const (
+	healthOK       = "ok"
+	healthFail     = "fail"
+	healthTimeout  = 10 * time.Second
+	healthFileGlob = ".tubarr-health-*"
+)
+
+// healthCheck is the result of one health check.
+type healthCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// healthReport is the JSON body returned by the health endpoints.
+type healthReport struct {
+	Status string        `json:"status"`
+	Checks []healthCheck `json:"checks"`
+}
+
+// healthzHandler checks the database is reachable.
+func healthzHandler(s interfaces.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, checkDatabase(s, r.Context()))
+	}
+}
+
+// readyzHandler also checks yt-dlp is available and each channel's video directory is writable.
+func readyzHandler(s interfaces.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		checks := []healthCheck{checkDatabase(s, r.Context()), checkYTDLP(), checkDraining()}
+		writeHealth(w, append(checks, checkVideoDirs(s)...)...)
+	}
+}
+
+// writeHealth writes the checks as JSON, with status 503 if any failed.
+func writeHealth(w http.ResponseWriter, checks ...healthCheck) {
+	report := healthReport{Status: healthOK, Checks: checks}
+	for _, c := range checks {
+		if !c.OK {
+			report.Status = healthFail
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != healthOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logging.E(0, "Failed to write health report: %v", err)
+	}
+}
+
+// checkDatabase checks the database answers a query.
+func checkDatabase(s interfaces.Store, ctx context.Context) healthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	var one int
+	if err := s.GetDB().QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return healthCheck{Name: "database", Error: err.Error()}
+	}
+	return healthCheck{Name: "database", OK: true}
+}
+
+// checkYTDLP checks the yt-dlp binary can be found.
+func checkYTDLP() healthCheck {
+	path, err := exec.LookPath(ytdlpbin.Path())
+	if err != nil {
+		return healthCheck{Name: "yt-dlp", Error: err.Error()}
+	}
+	return healthCheck{Name: "yt-dlp", OK: true, Detail: path}
+}
+
+// checkDraining fails once a graceful shutdown begins, so no new work is sent to this instance.
+func checkDraining() healthCheck {
+	if draining() {
+		return healthCheck{Name: "shutdown", Error: "shutting down, finishing work in progress"}
+	}
+	return healthCheck{Name: "shutdown", OK: true, Detail: "running"}
+}
+
+// checkVideoDirs checks each distinct channel video directory is available and writable.
+//
+// Templated directories are checked at their static prefix.
+func checkVideoDirs(s interfaces.Store) []healthCheck {
+	chans, err, hasRows := s.ChannelStore().FetchAllChannels()
+	if err != nil {
+		return []healthCheck{{Name: "video directories", Error: err.Error()}}
+	}
+	if !hasRows {
+		return nil
+	}
+
+	var (
+		checks []healthCheck
+		seen   = make(map[string]bool)
+	)
+	for _, c := range chans {
+		dir := filepath.Clean(parsing.StaticPrefix(c.VideoDir))
+		if dir == "." || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		check := healthCheck{Name: "video directory", Detail: dir}
+		if err := checkDirWritable(dir); err != nil {
+			check.Error = err.Error()
+		} else {
+			check.OK = true
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkDirWritable checks the directory is mounted and a file can be created in it.
+func checkDirWritable(dir string) error {
+	if err := checkDirAvailable(dir); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, healthFileGlob)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -17,10 +18,12 @@ import (
 )
 
 // parseAndStoreJSON checks if the JSON is valid and if it passes filter checks.
-func parseAndStoreJSON(v *models.Video) (valid bool, err error) {
+//
+// If a filter or limit rejects the video, the skip reason and the rejecting filter or limit are returned.
+func parseAndStoreJSON(v *models.Video) (valid bool, reason consts.SkipReason, hit string, err error) {
 	f, err := os.Open(v.JSONPath)
 	if err != nil {
-		return false, err
+		return false, "", "", err
 	}
 	defer func() {
 		if err := f.Close(); err != nil {
@@ -33,7 +36,7 @@ func parseAndStoreJSON(v *models.Video) (valid bool, err error) {
 	m := make(map[string]any)
 	decoder := json.NewDecoder(f)
 	if err := decoder.Decode(&m); err != nil {
-		return false, fmt.Errorf("failed to decode JSON: %w", err)
+		return false, "", "", fmt.Errorf("failed to decode JSON: %w", err)
 	}
 
 	if len(m) > 0 {
@@ -64,25 +67,34 @@ func parseAndStoreJSON(v *models.Video) (valid bool, err error) {
 		}
 
 	} else {
-		return false, nil
+		return false, "", "", nil
 	}
 
-	if valid, err = filterRequests(v); err != nil {
-		return false, err
+	if valid, hit, err = filterRequests(v); err != nil {
+		return false, "", "", err
 	} else if !valid {
-		return false, nil
+		return false, consts.SkipFilter, hit, nil
+	}
+
+	if reason, hit = metadataLimitHit(v); hit != "" {
+		if err := removeUnwantedJSON(v.JSONPath); err != nil {
+			logging.E(0, "Failed to remove unwanted JSON at %q: %v", v.JSONPath, err)
+		}
+		return false, reason, hit, nil
 	}
 
 	logging.D(1, "Successfully validated and stored metadata for video: %s (Title: %s)", v.URL, v.Title)
-	return true, nil
+	return true, "", "", nil
 }
 
 // filterRequests uses user input filters to check if the video should be downloaded.
-func filterRequests(v *models.Video) (valid bool, err error) {
+//
+// If the video is filtered out, the rejecting filter is returned as 'field:type:value'.
+func filterRequests(v *models.Video) (valid bool, filterHit string, err error) {
 	// Check if filters are set and validate if so
 	if len(v.Settings.Filters) == 0 {
 		logging.D(2, "No filters to check for %q", v.URL)
-		return true, nil
+		return true, "", nil
 	}
 
 	// Apply filters if any match metadata content
@@ -98,7 +110,7 @@ func filterRequests(v *models.Video) (valid bool, err error) {
 					if err := removeUnwantedJSON(v.JSONPath); err != nil {
 						logging.E(0, "Failed to remove unwanted JSON at %s: %v", v.JSONPath, err.Error())
 					}
-					return false, nil
+					return false, filterString(filter), nil
 
 				case consts.FilterOmit:
 					logging.D(2, "Passed check: Field %q does not exist", filter.Field)
@@ -113,7 +125,7 @@ func filterRequests(v *models.Video) (valid bool, err error) {
 					if err := removeUnwantedJSON(v.JSONPath); err != nil {
 						logging.E(0, "Failed to remove unwanted JSON at %q: %v", v.JSONPath, err)
 					}
-					return false, nil
+					return false, filterString(filter), nil
 
 				case consts.FilterContains:
 					logging.D(2, "Passed check: Field %q exists", filter.Field)
@@ -143,7 +155,7 @@ func filterRequests(v *models.Video) (valid bool, err error) {
 					if err := removeUnwantedJSON(v.JSONPath); err != nil {
 						logging.E(0, "Failed to remove unwanted JSON at %q: %v", v.JSONPath, err)
 					}
-					return false, nil
+					return false, filterString(filter), nil
 				}
 
 			case consts.FilterContains:
@@ -153,7 +165,22 @@ func filterRequests(v *models.Video) (valid bool, err error) {
 					if err := removeUnwantedJSON(v.JSONPath); err != nil {
 						logging.E(0, "Failed to remove unwanted JSON at %q: %v", v.JSONPath, err)
 					}
-					return false, nil
+					return false, filterString(filter), nil
+				}
+
+			case consts.FilterRegex, consts.FilterOmitRegex:
+				re, err := regexp.Compile(filter.Value)
+				if err != nil {
+					logging.E(0, "Invalid regular expression in filter %q, skipping: %v", filterString(filter), err)
+					continue
+				}
+				if re.MatchString(strVal) == (filter.Type == consts.FilterOmitRegex) {
+
+					logging.D(1, "Filtering out video %q by pattern %q in field %q", v.URL, filter.Value, filter.Field)
+					if err := removeUnwantedJSON(v.JSONPath); err != nil {
+						logging.E(0, "Failed to remove unwanted JSON at %q: %v", v.JSONPath, err)
+					}
+					return false, filterString(filter), nil
 				}
 
 			default:
@@ -163,7 +190,50 @@ func filterRequests(v *models.Video) (valid bool, err error) {
 		}
 	}
 	logging.D(1, "Video %q passed filter checks", v.URL)
-	return true, nil
+	return true, "", nil
+}
+
+// metadataLimitHit checks the video's duration and view count against the channel's limits.
+//
+// It returns the skip reason and the limit the video falls outside of, or "" if it passes. Videos whose
+// metadata lacks the field, such as upcoming livestreams without a duration, are not filtered.
+func metadataLimitHit(v *models.Video) (reason consts.SkipReason, limit string) {
+	s := v.Settings
+	if s.MinDuration != "" || s.MaxDuration != "" {
+		if secs, ok := v.MetadataMap["duration"].(float64); ok {
+			dur := time.Duration(secs * float64(time.Second))
+			if lo, err := time.ParseDuration(s.MinDuration); err == nil && lo > 0 && dur < lo {
+				logging.I("Filtering: Video %q is %v long, shorter than the minimum %v", v.URL, dur, lo)
+				return consts.SkipDuration, "min-duration:" + s.MinDuration
+			}
+			if hi, err := time.ParseDuration(s.MaxDuration); err == nil && hi > 0 && dur > hi {
+				logging.I("Filtering: Video %q is %v long, longer than the maximum %v", v.URL, dur, hi)
+				return consts.SkipDuration, "max-duration:" + s.MaxDuration
+			}
+		} else {
+			logging.D(2, "No duration in metadata for %q, skipping duration limits", v.URL)
+		}
+	}
+
+	if s.MinViews > 0 {
+		if views, ok := v.MetadataMap["view_count"].(float64); ok {
+			if int64(views) < int64(s.MinViews) {
+				logging.I("Filtering: Video %q has %d views, fewer than the minimum %d", v.URL, int64(views), s.MinViews)
+				return consts.SkipViews, "min-views:" + strconv.Itoa(s.MinViews)
+			}
+		} else {
+			logging.D(2, "No view count in metadata for %q, skipping view limit", v.URL)
+		}
+	}
+	return "", ""
+}
+
+// filterString returns the filter in its input format.
+func filterString(f models.DLFilters) string {
+	if f.Value == "" {
+		return f.Field + ":" + f.Type
+	}
+	return f.Field + ":" + f.Type + ":" + f.Value
 }
 
 // removeUnwantedJSON removes filtered out JSON files.
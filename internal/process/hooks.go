@@ -0,0 +1,164 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/events"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	defaultHookTimeout = time.Minute // For hooks saved without a timeout
+	hookWaitDelay      = 5 * time.Second
+)
+
+// InitHooks runs the saved hook scripts for each published event.
+//
+// Hooks are loaded when the event is published, so changes apply without restarting the scheduler.
+// Running hooks are killed when the context is cancelled.
+func InitHooks(s interfaces.Store, ctx context.Context) {
+	cs := s.ChannelStore()
+	for _, name := range events.Names() {
+		events.Subscribe(name, func(e events.Event) {
+			hooks, err := cs.GetHooks(e.Name)
+			if err != nil {
+				logging.E(0, "Failed to load hooks for event %s: %v", e.Name, err)
+				return
+			}
+			for _, h := range hooks {
+				runHook(h, e, ctx)
+			}
+		})
+	}
+}
+
+// runHook runs a hook script for the event, logging its output.
+//
+// Arguments are rendered as templates and passed directly rather than through a shell. The event's
+// fields are also set as TUBARR_ environment variables.
+func runHook(h *models.Hook, e events.Event, ctx context.Context) {
+	args := make([]string, 0, len(h.Args))
+	for _, a := range h.Args {
+		rendered, err := events.Render(a, e)
+		if err != nil {
+			logging.E(0, "Failed to render arguments for hook %q: %v", h.Name, err)
+			return
+		}
+		args = append(args, rendered)
+	}
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.Path, args...)
+	cmd.Env = append(os.Environ(), hookEnv(e)...)
+	cmd.WaitDelay = hookWaitDelay // Children of a killed script may hold its output open
+
+	started := time.Now()
+	out, err := cmd.CombinedOutput()
+	lines := outputLines(out)
+	for _, line := range lines {
+		logging.D(1, "[hook %s] %s", h.Name, line)
+	}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		logging.E(0, "Hook %q for %s timed out after %v", h.Name, e.Name, timeout)
+	case err != nil:
+		if len(lines) > 0 {
+			err = fmt.Errorf("%w: %s", err, lines[len(lines)-1])
+		}
+		logging.E(0, "Hook %q for %s failed: %v", h.Name, e.Name, err)
+	default:
+		logging.S(1, "Ran hook %q for %s in %v", h.Name, e.Name, time.Since(started).Round(time.Millisecond))
+	}
+}
+
+// hookEnv returns the event's fields as environment variables.
+func hookEnv(e events.Event) []string {
+	return []string{
+		"TUBARR_EVENT=" + e.Name,
+		"TUBARR_TIME=" + e.Time.Format(time.RFC3339),
+		"TUBARR_CHANNEL_ID=" + strconv.FormatInt(e.ChannelID, 10),
+		"TUBARR_CHANNEL=" + e.Channel,
+		"TUBARR_URL=" + e.URL,
+		"TUBARR_TITLE=" + e.Title,
+		"TUBARR_PATH=" + e.Path,
+		"TUBARR_JSON_PATH=" + e.JSONPath,
+		"TUBARR_ERROR=" + e.Error,
+		"TUBARR_NEW_VIDEOS=" + strconv.Itoa(e.NewVideos),
+		"TUBARR_FAILURES=" + strconv.Itoa(e.Failures),
+		"TUBARR_FILTERED=" + strconv.Itoa(e.Filtered),
+	}
+}
+
+// outputLines returns the non-blank lines of a hook's output.
+func outputLines(out []byte) []string {
+	var lines []string
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		if l := strings.TrimSpace(sc.Text()); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// publishVideo publishes a video's result from a download job.
+//
+// Videos skipped without an error, such as filtered or paused ones, publish nothing.
+func publishVideo(c *models.Channel, v *models.Video, err error) {
+	e := events.Event{ChannelID: c.ID, Channel: c.Name, URL: v.URL, Title: v.Title, Path: v.VideoPath, JSONPath: v.JSONPath}
+	switch {
+	case err != nil:
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		e.Name, e.Error = events.VideoFailed, err.Error()
+	case v.DownloadStatus.Status == consts.DLStatusCompleted:
+		e.Name = events.VideoDownloaded
+	default:
+		return
+	}
+	events.Publish(e)
+}
+
+// publishBlocked publishes a channel hitting a bot block or login challenge.
+func publishBlocked(c *models.Channel, msg string) {
+	events.Publish(events.Event{Name: events.ChannelBlocked, ChannelID: c.ID, Channel: c.Name, URL: c.URL, Error: msg})
+}
+
+// finishCrawl notifies of a finished channel crawl and publishes its counts.
+func finishCrawl(cs interfaces.ChannelStore, c *models.Channel, report *models.ChannelReport, err error) {
+	notifyCrawlFailure(cs, c, err)
+
+	e := events.Event{
+		Name:      events.CrawlFinished,
+		ChannelID: c.ID,
+		Channel:   c.Name,
+		URL:       c.URL,
+		NewVideos: report.NewVideos,
+		Failures:  report.Failures,
+		Filtered:  report.Filtered,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	events.Publish(e)
+}
@@ -0,0 +1,180 @@
+package process
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	ignoreRefreshInterval = 2 * time.Second
+)
+
+// ignoreSet is a channel's ignore list, shared by every crawl of that channel in this process.
+type ignoreSet struct {
+	mu        sync.Mutex
+	urls      map[string]struct{}
+	refreshed time.Time
+}
+
+var (
+	muIgnoreSets sync.Mutex
+	ignoreSets   = make(map[int64]*ignoreSet)
+)
+
+// ignoreRule is a compiled global ignore pattern.
+type ignoreRule struct {
+	value string
+	rx    *regexp.Regexp
+}
+
+// globalIgnores is the ignore list applied to every channel, shared by every crawl in this process.
+var globalIgnores = struct {
+	mu        sync.Mutex
+	urls      map[string]struct{}
+	patterns  []ignoreRule
+	titles    []ignoreRule
+	refreshed time.Time
+}{urls: make(map[string]struct{})}
+
+// getIgnoreSet returns the shared ignore set for a channel, creating it if needed.
+func getIgnoreSet(channelID int64) *ignoreSet {
+	muIgnoreSets.Lock()
+	defer muIgnoreSets.Unlock()
+
+	is, ok := ignoreSets[channelID]
+	if !ok {
+		is = &ignoreSet{urls: make(map[string]struct{})}
+		ignoreSets[channelID] = is
+	}
+	return is
+}
+
+// MarkIgnored adds a URL to the channel's ignore set, so crawls running in this process skip it immediately.
+func MarkIgnored(channelID int64, url string) {
+	is := getIgnoreSet(channelID)
+	is.mu.Lock()
+	is.urls[url] = struct{}{}
+	is.mu.Unlock()
+}
+
+// UnmarkIgnored removes a URL from the channel's ignore set, so crawls running in this process pick it up again.
+func UnmarkIgnored(channelID int64, url string) {
+	is := getIgnoreSet(channelID)
+	is.mu.Lock()
+	delete(is.urls, url)
+	is.mu.Unlock()
+}
+
+// ignoredAt checks a video against its channel's ignore list at a pipeline checkpoint.
+//
+// The list is reloaded from the database when stale, picking up URLs ignored or unignored by other Tubarr processes.
+func ignoredAt(cs interfaces.ChannelStore, channelID int64, v *models.Video, checkpoint string) bool {
+	is := getIgnoreSet(channelID)
+
+	is.mu.Lock()
+	defer is.mu.Unlock()
+
+	if time.Since(is.refreshed) >= ignoreRefreshInterval {
+		urls, err := cs.LoadIgnoredURLs(channelID)
+		if err != nil {
+			logging.E(0, "Failed to refresh ignore list for channel with ID %d: %v", channelID, err)
+		} else {
+			// Replaced rather than merged, so URLs unignored by other Tubarr processes are crawled again
+			is.urls = make(map[string]struct{}, len(urls))
+			for _, u := range urls {
+				is.urls[u] = struct{}{}
+			}
+			is.refreshed = time.Now()
+		}
+	}
+
+	if _, ok := is.urls[v.URL]; ok {
+		logging.I("Skipping %q, it was ignored before %s", v.URL, checkpoint)
+		return true
+	}
+	return false
+}
+
+// ResetGlobalIgnores has the next check reload the global ignore list, so changes apply immediately.
+func ResetGlobalIgnores() {
+	globalIgnores.mu.Lock()
+	globalIgnores.refreshed = time.Time{}
+	globalIgnores.mu.Unlock()
+}
+
+// globallyIgnored checks a video against the global ignore list, recording it as skipped if it matches.
+//
+// Title patterns are only checked once the title is known from the video's metadata.
+func globallyIgnored(cs interfaces.ChannelStore, ss interfaces.SkipStore, skips *skipBatch, v *models.Video, checkTitle bool) bool {
+	kind, rule, ok := matchGlobalIgnore(cs, v, checkTitle)
+	if !ok {
+		return false
+	}
+	logging.I("Skipping %q, it matches global ignore %s %q", v.URL, kind, rule)
+
+	skips.add(ss, &models.SkippedVideo{
+		ChannelID: v.ChannelID,
+		URL:       v.URL,
+		Reason:    consts.SkipIgnored,
+		Detail:    string(kind) + ": " + rule,
+	})
+	return true
+}
+
+// matchGlobalIgnore returns the global ignore rule matching a video, reloading the list when stale.
+func matchGlobalIgnore(cs interfaces.ChannelStore, v *models.Video, checkTitle bool) (kind consts.IgnoreKind, rule string, ok bool) {
+	g := &globalIgnores
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if time.Since(g.refreshed) >= ignoreRefreshInterval {
+		ignores, err := cs.GetGlobalIgnores()
+		if err != nil {
+			logging.E(0, "Failed to refresh global ignore list: %v", err)
+		} else {
+			g.urls = make(map[string]struct{}, len(ignores))
+			g.patterns, g.titles = nil, nil
+			for _, ig := range ignores {
+				if ig.Kind == consts.IgnoreURL {
+					g.urls[ig.Value] = struct{}{}
+					continue
+				}
+				rx, err := regexp.Compile(ig.Value)
+				if err != nil {
+					logging.E(0, "Invalid global ignore %s %q: %v", ig.Kind, ig.Value, err)
+					continue
+				}
+				switch ig.Kind {
+				case consts.IgnorePattern:
+					g.patterns = append(g.patterns, ignoreRule{value: ig.Value, rx: rx})
+				case consts.IgnoreTitle:
+					g.titles = append(g.titles, ignoreRule{value: ig.Value, rx: rx})
+				}
+			}
+			g.refreshed = time.Now()
+		}
+	}
+
+	if _, ok := g.urls[v.URL]; ok {
+		return consts.IgnoreURL, v.URL, true
+	}
+	for _, r := range g.patterns {
+		if r.rx.MatchString(v.URL) {
+			return consts.IgnorePattern, r.value, true
+		}
+	}
+	if checkTitle && v.Title != "" {
+		for _, r := range g.titles {
+			if r.rx.MatchString(v.Title) {
+				return consts.IgnoreTitle, r.value, true
+			}
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,311 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/nfo"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/ytdlp"
+)
+
+const (
+	minImportIDLen    = 6  // Shorter filename tokens are too likely to match by chance
+	minImportTitleLen = 10 // Shorter titles are too likely to match by chance
+)
+
+// importAudioExts are audio files kept by audio only channels, imported alongside video files.
+var importAudioExts = []string{".m4a", ".mp3", ".opus", ".ogg", ".flac", ".wav", ".aac"}
+
+// importMatch is an existing file matched to a channel video.
+type importMatch struct {
+	video *models.Video
+	how   string
+}
+
+// ImportExisting scans a directory of previously downloaded files and records those matching the channel's
+// videos as finished, so they aren't downloaded again.
+//
+// Files are matched by the URL in their yt-dlp metadata JSON, then by a video ID in the filename, then by
+// title. IDs and titles are matched against the channel's recorded videos and, unless offline is set, its
+// remote videos. Ambiguous matches are skipped.
+func ImportExisting(s interfaces.Store, c *models.Channel, dir string, offline, dryRun bool, ctx context.Context) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%q is not a directory", dir)
+	}
+
+	files, jsons, err := scanImportDir(dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		logging.I("No video files found in %q", dir)
+		return nil
+	}
+	logging.I("Found %d video files in %q", len(files), dir)
+
+	known, err := importCandidates(s, c, offline, ctx)
+	if err != nil {
+		return err
+	}
+	byID, byTitle := indexCandidates(known)
+
+	var (
+		matches   []importMatch
+		unmatched []string
+		claimed   = make(map[string]string, len(files))
+	)
+	for _, f := range files {
+		base := strings.TrimSuffix(f, filepath.Ext(f))
+		jsonPath := jsons[base]
+
+		u, title, how := "", "", ""
+		if jsonPath != "" {
+			if m, err := nfo.ReadMetadata(jsonPath); err != nil {
+				logging.D(1, "Skipping metadata %q: %v", jsonPath, err)
+			} else {
+				title = m.Title
+				if u = byID[m.ID]; u == "" {
+					u = m.WebpageURL
+				}
+				how = "metadata"
+			}
+		}
+		if u == "" {
+			u, how = matchByID(filepath.Base(base), byID), "video ID"
+		}
+		if u == "" {
+			u, how = matchByTitle(filepath.Base(base), byTitle), "title"
+		}
+		if u == "" {
+			unmatched = append(unmatched, f)
+			continue
+		}
+		if prev, ok := claimed[u]; ok {
+			logging.W("Both %q and %q match %q, keeping the first", prev, f, u)
+			continue
+		}
+		claimed[u] = f
+
+		v := &models.Video{
+			ChannelID: c.ID,
+			URL:       u,
+			Title:     title,
+			VideoDir:  filepath.Dir(f),
+			VideoPath: f,
+			JSONDir:   filepath.Dir(f),
+			JSONPath:  jsonPath,
+		}
+		if jsonPath != "" {
+			v.JSONDir = filepath.Dir(jsonPath)
+		}
+		matches = append(matches, importMatch{video: v, how: how})
+	}
+
+	fmt.Printf("\n%sMatched %d of %d files in %q:%s\n", consts.ColorGreen, len(matches), len(files), dir, consts.ColorReset)
+	for _, m := range matches {
+		fmt.Printf("%s -> %s (by %s)\n", m.video.VideoPath, m.video.URL, m.how)
+	}
+	if len(unmatched) > 0 {
+		fmt.Printf("\n%sUnmatched files:%s\n", consts.ColorYellow, consts.ColorReset)
+		for _, f := range unmatched {
+			fmt.Println(f)
+		}
+	}
+	fmt.Println()
+
+	if dryRun || len(matches) == 0 {
+		if dryRun {
+			logging.I("Dry run, no videos were marked as downloaded")
+		}
+		return nil
+	}
+
+	videos := make([]*models.Video, 0, len(matches))
+	for _, m := range matches {
+		videos = append(videos, m.video)
+	}
+	imported, err := s.VideoStore().ImportVideos(c.ID, videos)
+	if err != nil {
+		return err
+	}
+	refreshStorage(s, c)
+	logging.S(0, "Marked %d existing videos as downloaded in channel %q (%d already downloaded)", len(imported), c.Name, len(videos)-len(imported))
+	return nil
+}
+
+// scanImportDir returns the video files under dir, and the metadata JSON files keyed by path without extension.
+func scanImportDir(dir string) (files []string, jsons map[string]string, err error) {
+	jsons = make(map[string]string)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			logging.W("Skipping %q: %v", path, err)
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		name := strings.ToLower(d.Name())
+		switch {
+		case strings.HasSuffix(name, ".info.json"):
+			jsons[path[:len(path)-len(".info.json")]] = path
+		case strings.HasSuffix(name, ".json"):
+			base := strings.TrimSuffix(path, filepath.Ext(path))
+			if _, ok := jsons[base]; !ok {
+				jsons[base] = path
+			}
+		default:
+			ext := filepath.Ext(name)
+			if slices.Contains(consts.AllVidExtensions[:], ext) || slices.Contains(importAudioExts, ext) {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	return files, jsons, err
+}
+
+// importCandidates returns the channel's recorded videos, and its remote videos unless offline is set.
+func importCandidates(s interfaces.Store, c *models.Channel, offline bool, ctx context.Context) ([]*models.Video, error) {
+	var known []*models.Video
+	if err := s.VideoStore().StreamChannelVideos(c.ID, func(v *models.Video) error {
+		known = append(known, v)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if offline {
+		return known, nil
+	}
+
+	if err := ytdlp.CheckPlugins(ctx); err != nil {
+		return nil, err
+	}
+	remote, err := browserInstance.GetUnseenReleases(s, c, ctx)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+		logging.W("Failed to list remote videos of channel %q, matching recorded videos only: %v", c.Name, err)
+		return known, nil
+	}
+	return append(known, remote...), nil
+}
+
+// indexCandidates maps the video IDs in candidate URLs, and their normalized titles, to the URLs.
+//
+// IDs and titles shared by different URLs map to an empty string, so they are never matched.
+func indexCandidates(videos []*models.Video) (byID, byTitle map[string]string) {
+	byID = make(map[string]string, len(videos))
+	byTitle = make(map[string]string, len(videos))
+	add := func(m map[string]string, key, u string) {
+		if prev, ok := m[key]; ok && prev != u {
+			m[key] = ""
+			return
+		}
+		m[key] = u
+	}
+	for _, v := range videos {
+		if id := videoIDFromURL(v.URL); len(id) >= minImportIDLen {
+			add(byID, id, v.URL)
+		}
+		if t := normalizeTitle(v.Title); len(t) >= minImportTitleLen {
+			add(byTitle, t, v.URL)
+		}
+	}
+	return byID, byTitle
+}
+
+// videoIDFromURL returns the video ID in a URL: its "v" query parameter, or else its last path segment.
+func videoIDFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	if v := u.Query().Get("v"); v != "" {
+		return v
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	return segments[len(segments)-1]
+}
+
+// matchByID returns the single candidate URL whose video ID appears as a token of the filename, such as
+// yt-dlp's default "Title [id]".
+func matchByID(name string, byID map[string]string) string {
+	tokens := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r == '-' || r == '_' || r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)))
+	})
+
+	var found string
+	for _, t := range tokens {
+		u, ok := byID[t]
+		if !ok || len(t) < minImportIDLen {
+			continue
+		}
+		if u == "" || (found != "" && found != u) {
+			return ""
+		}
+		found = u
+	}
+	return found
+}
+
+// matchByTitle returns the single candidate URL whose title ends the normalized filename, allowing date prefixes.
+func matchByTitle(name string, byTitle map[string]string) string {
+	norm := normalizeTitle(name)
+	if u, ok := byTitle[norm]; ok {
+		return u
+	}
+
+	var found string
+	for t, u := range byTitle {
+		if !strings.HasSuffix(norm, t) {
+			continue
+		}
+		if u == "" || (found != "" && found != u) {
+			return ""
+		}
+		found = u
+	}
+	return found
+}
+
+// normalizeTitle lowercases a title or filename, dropping bracketed parts and anything but letters and digits.
+func normalizeTitle(s string) string {
+	var (
+		b     strings.Builder
+		depth int
+	)
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r == '[' || r == '(':
+			depth++
+		case r == ']' || r == ')':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0 && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -5,9 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"os/exec"
 	"sync"
+	"time"
 
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/keys"
 	"tubarr/internal/downloads"
 	"tubarr/internal/interfaces"
 	"tubarr/internal/metarr"
@@ -39,29 +44,34 @@ func InitProcess(s interfaces.Store, c *models.Channel, videos []*models.Video,
 		conc = 1
 	}
 
+	var delay time.Duration
+	if cfg.GetBool(keys.AutoTuneHosts) {
+		if u, err := url.Parse(c.URL); err == nil {
+			conc, delay = tuneHost(s.HostStore(), u.Hostname(), conc)
+		}
+	}
+
 	logging.I("Starting meta/video processing for %d videos", len(videos))
 
 	dlTracker := downloads.NewDownloadTracker(s.DownloadStore(), c.Settings.ExternalDownloader)
 	dlTracker.Start(ctx)
 	defer dlTracker.Stop()
 
+	quota := newDiskQuota(s.StorageStore(), c)
+
 	jobs := make(chan *models.Video, len(videos))
 	results := make(chan error, len(videos))
+	skips := &skipBatch{}
+	defer skips.flush(s.SkipStore())
 
 	// Start workers
+	queuedAt := time.Now()
 	for w := 1; w <= conc; w++ {
-		go videoJob(w, jobs, results, s.VideoStore(), c, dlTracker, ctx)
+		go videoJob(w, jobs, results, s, c, dlTracker, quota, skips, delay, queuedAt, ctx)
 	}
 
-	// Send jobs
-	for _, video := range videos {
-		if video == nil {
-			logging.E(0, "Video in queue for channel %q is nil", c.Name)
-			continue
-		}
-		jobs <- video
-	}
-	close(jobs)
+	// Send jobs through the lookup and filter stages
+	queueCandidates(s, c, videos, skips, jobs, results)
 
 	for i := 0; i < len(videos); i++ {
 		if err := <-results; err != nil {
@@ -72,17 +82,68 @@ func InitProcess(s interfaces.Store, c *models.Channel, videos []*models.Video,
 			success = true
 		}
 	}
+	reportQuota(s.ChannelStore(), c, quota)
 
 	if len(errs) > 0 {
+		diagnoseFailures(s.ChannelStore(), c, len(videos), errs, ctx)
 		return success, errs
 	}
 	return success, nil
 }
 
 // videoJob starts a worker's process for a video.
-func videoJob(id int, videos <-chan *models.Video, results chan<- error, vs interfaces.VideoStore, c *models.Channel, dlTracker *downloads.DownloadTracker, ctx context.Context) {
+func videoJob(id int, videos <-chan *models.Video, results chan<- error, s interfaces.Store, c *models.Channel, dlTracker *downloads.DownloadTracker, quota *diskQuota, skips *skipBatch, delay time.Duration, queuedAt time.Time, ctx context.Context) {
+	var (
+		vs = s.VideoStore()
+		hs = s.HostStore()
+		cs = s.ChannelStore()
+		rs = s.RetryStore()
+		ts = s.StatsStore()
+	)
+	done := func(v *models.Video, err error) {
+		publishVideo(c, v, err)
+		results <- err
+	}
+
 	for v := range videos {
 		var err error
+		timer := newStageTimer(vs, v, queuedAt)
+
+		// Not yet stored, so the video is found again by the next crawl after a restart or the pause
+		if draining() {
+			logging.I("Not starting %q, Tubarr is shutting down", v.URL)
+			done(v, nil)
+			continue
+		}
+		p, paused := downloadsPaused(hs, v.URL)
+		if !paused {
+			p, paused = urlPaused(hs, c.URL)
+		}
+		if !paused {
+			p, paused = groupPaused(cs, hs, c)
+		}
+		if paused {
+			logging.I("Not starting %q, downloads are paused for %s", v.URL, p.Describe())
+			done(v, nil)
+			continue
+		}
+
+		applyFallbacks(hs, v)
+
+		// Also left unstored, so the video is downloaded by a later crawl if there is room
+		if !quota.allow(vs, c) {
+			logging.I("Not starting %q, channel %q is at its max total size of %s", v.URL, c.Name, c.Settings.MaxTotalSize)
+			done(v, nil)
+			continue
+		}
+
+		if delay > 0 {
+			logging.D(1, "Worker %d waiting %v before next download (host auto-tuning)", id, delay)
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+		}
 
 		// Initialize directory parser
 		dirParser := parsing.NewDirectoryParser(c, v)
@@ -104,11 +165,31 @@ func videoJob(id int, videos <-chan *models.Video, results chan<- error, vs inte
 			}
 		}
 
-		if err := processJSON(ctx, v, vs, dlTracker); err != nil {
-			results <- fmt.Errorf("JSON processing error for video (ID: %d, URL: %s): %w", v.ID, v.URL, err)
+		// Downloads and Metarr run in the staging directory, files are moved into the video directory on completion
+		finalDir := v.VideoDir
+		stageDir, err := stageVideo(dirParser, c, v)
+		if err != nil {
+			done(v, fmt.Errorf("failed to stage video (URL: %s): %w", v.URL, err))
 			continue
 		}
 
+		timer.mark(consts.StageDownloadStart)
+		started := time.Now()
+		if err := processJSON(ctx, v, vs, cs, s.SkipStore(), skips, dlTracker); err != nil {
+			if errors.Is(err, errFiltered) {
+				v.Filtered = true
+				done(v, nil)
+				continue
+			}
+			recordHostResult(hs, cs, v, err)
+			recordDownloadStats(ts, v, started, err)
+			queueRetry(rs, c, v, err, ctx)
+			done(v, fmt.Errorf("JSON processing error for video (ID: %d, URL: %s): %w", v.ID, v.URL, err))
+			continue
+		}
+		timer.flush()
+		quota.add(v.JSONPath)
+
 		if logging.Level > 1 {
 			fmt.Println()
 			logging.I("Worker %d processing: %q", id, v.URL)
@@ -116,18 +197,77 @@ func videoJob(id int, videos <-chan *models.Video, results chan<- error, vs inte
 			logging.P("Uploaded=%s", v.UploadDate)
 		}
 
-		if err := processVideo(ctx, v, vs, dlTracker); err != nil {
-			results <- fmt.Errorf("video processing error for video (ID: %d, URL: %s): %w", v.ID, v.URL, err)
+		if ignoredAt(cs, c.ID, v, "video download") {
+			done(v, nil)
+			continue
+		}
+
+		if err := processVideo(ctx, v, vs, dlTracker, quota.remaining()); err != nil {
+			recordHostResult(hs, cs, v, err)
+			recordDownloadStats(ts, v, started, err)
+			queueRetry(rs, c, v, err, ctx)
+			done(v, fmt.Errorf("video processing error for video (ID: %d, URL: %s): %w", v.ID, v.URL, err))
+			continue
+		}
+		recordHostResult(hs, cs, v, nil)
+		recordDownloadStats(ts, v, started, nil)
+		clearRetry(rs, c, v)
+		quota.add(v.VideoPath)
+		timer.mark(consts.StageDownloadEnd)
+
+		if err := runPostProcessors(c, v, ctx); err != nil {
+			done(v, fmt.Errorf("post-processing error for video (ID: %d, URL: %s): %w", v.ID, v.URL, err))
+			continue
+		}
+
+		if v.Settings.SkipMetarr {
+			if err := metarr.MoveWithoutMetarr(v); err != nil {
+				done(v, fmt.Errorf("failed to move files for video (ID: %d, URL: %s): %w", v.ID, v.URL, err))
+				continue
+			}
+			err := finishVideo(vs, c, v, stageDir, finalDir, ctx)
+			if err == nil {
+				timer.mark(consts.StageMoved)
+			}
+			done(v, err)
 			continue
 		}
 
 		if _, err := exec.LookPath("metarr"); err != nil {
 			logging.I("Skipping Metarr process... 'metarr' not available: %v", err)
+			done(v, finishVideo(vs, c, v, stageDir, finalDir, ctx))
 			continue
 		}
+		timer.mark(consts.StageMetarrStart)
 		if err := metarr.InitMetarr(v, ctx); err != nil {
-			results <- fmt.Errorf("error initializing Metarr: %w", err)
+			done(v, fmt.Errorf("error initializing Metarr: %w", err))
+			continue
+		}
+		timer.mark(consts.StageMetarrEnd)
+
+		err = finishVideo(vs, c, v, stageDir, finalDir, ctx)
+		if err == nil {
+			timer.mark(consts.StageMoved)
 		}
-		results <- nil // nil = success
+		done(v, err) // nil = success
 	}
 }
+
+// finishVideo moves a processed video's files out of staging and into the output template, stores the final
+// paths in case Metarr or the template renamed them, then writes the sidecars, NFO, and transcript.
+func finishVideo(vs interfaces.VideoStore, c *models.Channel, v *models.Video, stageDir, finalDir string, ctx context.Context) error {
+	if err := unstage(v, stageDir, finalDir); err != nil {
+		return fmt.Errorf("failed to move staged files for video (ID: %d, URL: %s): %w", v.ID, v.URL, err)
+	}
+	if err := organizeOutput("", c, v); err != nil {
+		return fmt.Errorf("failed to organize files for video (ID: %d, URL: %s): %w", v.ID, v.URL, err)
+	}
+	if err := vs.UpdateVideo(v); err != nil {
+		return fmt.Errorf("failed to update video paths: %w", err)
+	}
+
+	writeSidecars(v)
+	writeNFO(c, v)
+	transcribeVideo(vs, v, ctx)
+	return nil
+}
@@ -0,0 +1,254 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"tubarr/internal/domain/cmdvideo"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/proxy"
+	"tubarr/internal/utils/ytdlp"
+)
+
+const (
+	defaultLiveCheckFreq = 2 * time.Minute
+	liveWatchTick        = 30 * time.Second
+	liveStatusLive       = "is_live"
+)
+
+var (
+	// Channels whose live URL is being checked or captured, by the live watcher or on request
+	liveBusyMu sync.Mutex
+	liveBusy   = make(map[int64]bool)
+)
+
+// liveWatcher polls channels with a live URL and captures their streams from the start as they go live.
+type liveWatcher struct {
+	s         interfaces.Store
+	mu        sync.Mutex
+	lastCheck map[int64]time.Time
+	manual    bool // Checking on request, so outcomes are logged at info level
+}
+
+// watchLive runs the live watcher until the context is cancelled.
+//
+// Channels are re-read on every tick, so live URLs added while running are picked up.
+// Each channel is checked or captured by one goroutine at a time.
+func watchLive(s interfaces.Store, ctx context.Context) {
+	w := &liveWatcher{
+		s:         s,
+		lastCheck: make(map[int64]time.Time),
+	}
+
+	ticker := time.NewTicker(liveWatchTick)
+	defer ticker.Stop()
+
+	for {
+		w.checkChannels(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkChannels starts a check for each channel with a live URL which is due and not already being checked or captured.
+func (w *liveWatcher) checkChannels(ctx context.Context) {
+	if draining() {
+		return
+	}
+	chans, err, hasRows := w.s.ChannelStore().FetchAllChannels()
+	if !hasRows || err != nil {
+		if err != nil {
+			logging.E(0, "Live watcher could not load channels: %v", err)
+		}
+		return
+	}
+
+	now := time.Now()
+	for _, c := range chans {
+		if c.Settings.LiveURL == "" {
+			continue
+		}
+
+		freq := defaultLiveCheckFreq
+		if c.Settings.LiveCheckFreq > 0 {
+			freq = time.Duration(c.Settings.LiveCheckFreq) * time.Minute
+		}
+
+		w.mu.Lock()
+		due := now.Sub(w.lastCheck[c.ID]) >= freq && claimLiveCheck(c.ID)
+		if due {
+			w.lastCheck[c.ID] = now
+		}
+		w.mu.Unlock()
+
+		if due {
+			go func(c *models.Channel) {
+				defer releaseLiveCheck(c.ID)
+				w.check(c, ctx)
+			}(c)
+		}
+	}
+}
+
+// CheckLive checks the channel's live URL now, capturing its stream if it is live, regardless of its live check frequency.
+//
+// Pauses on the live URL still apply.
+func CheckLive(s interfaces.Store, c *models.Channel, ctx context.Context) error {
+	if c.Settings.LiveURL == "" {
+		return fmt.Errorf("channel %q has no live URL", c.Name)
+	}
+	if !claimLiveCheck(c.ID) {
+		return fmt.Errorf("live URL of channel %q is already being checked or captured", c.Name)
+	}
+	defer releaseLiveCheck(c.ID)
+
+	(&liveWatcher{s: s, manual: true}).check(c, ctx)
+	return nil
+}
+
+// claimLiveCheck marks a channel's live URL as being checked, returning false if it already is.
+func claimLiveCheck(id int64) bool {
+	liveBusyMu.Lock()
+	defer liveBusyMu.Unlock()
+	if liveBusy[id] {
+		return false
+	}
+	liveBusy[id] = true
+	return true
+}
+
+// releaseLiveCheck marks a channel's live check or capture as finished.
+func releaseLiveCheck(id int64) {
+	liveBusyMu.Lock()
+	delete(liveBusy, id)
+	liveBusyMu.Unlock()
+}
+
+// check captures the channel's stream if it is live and hasn't been captured before.
+func (w *liveWatcher) check(c *models.Channel, ctx context.Context) {
+	p, paused := downloadsPaused(w.s.HostStore(), c.Settings.LiveURL)
+	if !paused {
+		p, paused = groupPaused(w.s.ChannelStore(), w.s.HostStore(), c)
+	}
+	if paused {
+		w.skipped(1, "Skipping live check for channel %q, downloads are paused for %s", c.Name, p.Describe())
+		return
+	}
+
+	streamURL, live, err := liveStream(c, ctx)
+	if err != nil {
+		w.skipped(1, "Channel %q is not live: %v", c.Name, err)
+		return
+	}
+	if !live {
+		w.skipped(2, "Channel %q is not live", c.Name)
+		return
+	}
+
+	if _, err := w.s.VideoStore().GetVideoID(c.ID, streamURL); err == nil {
+		w.skipped(2, "Live stream %q for channel %q was already captured", streamURL, c.Name)
+		return
+	}
+
+	if err := checkChannelMounts(c); err != nil {
+		logging.E(0, "Not capturing live stream for channel %q: %v", c.Name, err)
+		return
+	}
+
+	w.capture(c, streamURL, ctx)
+}
+
+// skipped logs why no stream was captured, at info level for checks made on request.
+func (w *liveWatcher) skipped(level int, format string, args ...any) {
+	if w.manual {
+		logging.I(format, args...)
+		return
+	}
+	logging.D(level, format, args...)
+}
+
+// capture records the live stream from the start through the normal video pipeline.
+func (w *liveWatcher) capture(c *models.Channel, streamURL string, ctx context.Context) {
+	cs := w.s.ChannelStore()
+	logging.I("Channel %q is live, capturing %s", c.Name, streamURL)
+	recordLiveEvent(cs, c, "live stream started, capturing "+streamURL)
+
+	v := &models.Video{
+		ChannelID:  c.ID,
+		URL:        streamURL,
+		VideoDir:   c.VideoDir,
+		JSONDir:    c.JSONDir,
+		Channel:    c,
+		Settings:   c.Settings,
+		MetarrArgs: c.MetarrArgs,
+		CookiePath: c.CookiePath,
+		Live:       true,
+	}
+
+	videos := []*models.Video{v}
+	if success, errs := InitProcess(w.s, c, videos, ctx); !success {
+		logging.E(0, "Failed to capture live stream %q for channel %q: %v", streamURL, c.Name, errs)
+		recordLiveEvent(cs, c, fmt.Sprintf("capture of %s failed: %v", streamURL, errs))
+		return
+	}
+
+	logging.S(0, "Captured live stream %q for channel %q", streamURL, c.Name)
+	recordLiveEvent(cs, c, "captured "+streamURL)
+	refreshStorage(w.s, c)
+
+	if err := notifyChannel(w.s, c, videos); err != nil {
+		logging.E(0, "%v", err)
+	}
+}
+
+// liveStream asks yt-dlp whether the channel's live URL is showing a livestream, returning the stream's URL.
+//
+// yt-dlp exits with an error for pages with no current stream, which is returned as the error.
+func liveStream(c *models.Channel, ctx context.Context) (streamURL string, live bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	cmd := liveStatusCommand(c, ctx)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", false, fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", false, err
+	}
+
+	status, streamURL, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+	return streamURL, status == liveStatusLive && streamURL != "", nil
+}
+
+// liveStatusCommand builds the yt-dlp command printing the live status and URL of the channel's live page.
+func liveStatusCommand(c *models.Channel, ctx context.Context) *exec.Cmd {
+	args := []string{consts.YtDLPPrint, consts.YtDLPLiveStatus, consts.YtDLPPlaylistOne, consts.YtDLPNoWarnings}
+	if c.CookiePath != "" {
+		args = append(args, cmdvideo.CookiePath, c.CookiePath)
+	} else if c.Settings.CookieSource != "" {
+		args = append(args, cmdvideo.CookieSource, c.Settings.CookieSource)
+	}
+	if p := proxy.Pick(c.Settings.Proxies); p != "" {
+		args = append(args, cmdvideo.Proxy, p)
+	}
+	return ytdlp.Command(ctx, append(args, c.Settings.LiveURL)...)
+}
+
+// recordLiveEvent adds a live watcher event to the channel's event history.
+func recordLiveEvent(cs interfaces.ChannelStore, c *models.Channel, detail string) {
+	if err := cs.RecordChannelEvent(c.ID, consts.ActivityLive, detail); err != nil {
+		logging.E(0, "Failed to record live event for channel %q: %v", c.Name, err)
+	}
+}
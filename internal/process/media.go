@@ -0,0 +1,145 @@
+package process
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"tubarr/internal/feed"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// thumbnailExts are the image extensions yt-dlp writes thumbnails with, in order of preference.
+var thumbnailExts = []string{".jpg", ".jpeg", ".webp", ".png"}
+
+const defaultFeedLimit = 100
+
+// streamHandler serves a downloaded video file, with range requests for seeking.
+func streamHandler(vs interfaces.VideoStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		videoPath, _, ok := videoPaths(vs, w, r)
+		if !ok {
+			return
+		}
+		if videoPath == "" {
+			http.Error(w, "video has not been downloaded", http.StatusNotFound)
+			return
+		}
+		serveFile(w, r, videoPath)
+	}
+}
+
+// feedHandler serves an RSS feed of the channel's downloaded videos, with enclosures streamed from this server.
+//
+// The newest 100 videos are included, or as many as the limit query parameter asks for (0 for all).
+func feedHandler(s interfaces.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		limit := defaultFeedLimit
+		if q := r.URL.Query().Get("limit"); q != "" {
+			n, err := strconv.Atoi(q)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+
+		c, err, hasRows := s.ChannelStore().FetchChannel(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		} else if !hasRows {
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		scheme := "http"
+		if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+			scheme = "https"
+		}
+		base := scheme + "://" + r.Host + "/api/videos/"
+
+		var b bytes.Buffer
+		if _, err := feed.Write(&b, s.VideoStore(), c, limit, func(v *models.Video) string {
+			return base + strconv.FormatInt(v.ID, 10) + "/stream"
+		}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		if _, err := w.Write(b.Bytes()); err != nil {
+			logging.E(0, "Failed to write feed for channel %q: %v", c.Name, err)
+		}
+	}
+}
+
+// thumbnailHandler serves the thumbnail image saved alongside a video or its JSON file.
+func thumbnailHandler(vs interfaces.VideoStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		videoPath, jsonPath, ok := videoPaths(vs, w, r)
+		if !ok {
+			return
+		}
+		thumb := findThumbnail(videoPath, jsonPath)
+		if thumb == "" {
+			http.Error(w, "no thumbnail found for video", http.StatusNotFound)
+			return
+		}
+		serveFile(w, r, thumb)
+	}
+}
+
+// videoPaths looks up the stored paths of the video in the request path, writing an error response if it can't.
+func videoPaths(vs interfaces.VideoStore, w http.ResponseWriter, r *http.Request) (videoPath, jsonPath string, ok bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid video ID", http.StatusBadRequest)
+		return "", "", false
+	}
+	videoPath, jsonPath, err = vs.GetVideoPaths(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return "", "", false
+	}
+	return videoPath, jsonPath, true
+}
+
+// serveFile serves a file, letting http.ServeContent handle range and conditional requests.
+func serveFile(w http.ResponseWriter, r *http.Request, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		http.Error(w, "file is not available", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.Error(w, "file is not available", http.StatusNotFound)
+		return
+	}
+	logging.D(2, "Serving %q to %s", path, r.RemoteAddr)
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), f)
+}
+
+// findThumbnail returns an image file sharing the video or JSON file's name, or an empty string if there is none.
+func findThumbnail(paths ...string) string {
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		stem := strings.TrimSuffix(p, filepath.Ext(p))
+		for _, ext := range thumbnailExts {
+			if info, err := os.Stat(stem + ext); err == nil && !info.IsDir() {
+				return stem + ext
+			}
+		}
+	}
+	return ""
+}
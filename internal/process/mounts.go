@@ -0,0 +1,131 @@
+package process
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	fstabPath  = "/etc/fstab"
+	mountsPath = "/proc/mounts"
+)
+
+// checkChannelMounts verifies the channel's configured directories are on available mounts.
+func checkChannelMounts(c *models.Channel) error {
+	dirs := []string{c.VideoDir, c.JSONDir, c.MetarrArgs.OutputDir}
+	if cfg.IsSet(keys.MoveOnComplete) {
+		dirs = append(dirs, cfg.GetString(keys.MoveOnComplete))
+	}
+
+	for _, d := range dirs {
+		if d == "" {
+			continue
+		}
+		if err := checkDirAvailable(parsing.StaticPrefix(d)); err != nil {
+			return fmt.Errorf("channel %q paused until mount returns: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// checkDirAvailable checks a directory is not under an unmounted fstab entry, and has the mount marker if one is configured.
+func checkDirAvailable(dir string) error {
+	if dir == "" || !filepath.IsAbs(dir) {
+		return nil
+	}
+	dir = filepath.Clean(dir)
+
+	if mountPoint := unmountedFstabEntry(dir); mountPoint != "" {
+		return fmt.Errorf("directory %q is under %q which is listed in %s but not mounted", dir, mountPoint, fstabPath)
+	}
+
+	if marker := cfg.GetString(keys.MountMarker); marker != "" {
+		for p := dir; ; p = filepath.Dir(p) {
+			if _, err := os.Stat(filepath.Join(p, marker)); err == nil {
+				return nil
+			}
+			if p == filepath.Dir(p) {
+				break
+			}
+		}
+		return fmt.Errorf("mount marker %q not found at or above directory %q", marker, dir)
+	}
+	return nil
+}
+
+// unmountedFstabEntry returns the fstab mountpoint containing dir if it is not currently mounted.
+func unmountedFstabEntry(dir string) string {
+	mounted, err := readMountPoints(mountsPath)
+	if err != nil {
+		logging.D(2, "Skipping mount comparison, could not read %s: %v", mountsPath, err)
+		return ""
+	}
+	configured, err := readMountPoints(fstabPath)
+	if err != nil {
+		logging.D(2, "Skipping mount comparison, could not read %s: %v", fstabPath, err)
+		return ""
+	}
+
+	var best string
+	for mp := range configured {
+		if mp == "/" || !pathWithin(dir, mp) {
+			continue
+		}
+		if len(mp) > len(best) {
+			best = mp
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+	if _, ok := mounted[best]; ok {
+		return ""
+	}
+	return best
+}
+
+// readMountPoints reads the mountpoint column from an fstab formatted file.
+func readMountPoints(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	points := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[2] == "swap" || !strings.HasPrefix(fields[1], "/") {
+			continue
+		}
+		points[filepath.Clean(strings.ReplaceAll(fields[1], `\040`, " "))] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, errors.New("no mountpoints found")
+	}
+	return points, nil
+}
+
+// pathWithin returns true if path is the same as, or nested under, root.
+func pathWithin(path, root string) bool {
+	return path == root || strings.HasPrefix(path, root+string(filepath.Separator))
+}
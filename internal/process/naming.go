@@ -0,0 +1,97 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+// maxEpisodeIndex is the most videos numbered for one upload day.
+const maxEpisodeIndex = 99
+
+// organizeOutput moves the downloaded video to the path given by the channel's output template, under root,
+// or under the video's current directory if root is blank.
+//
+// The metadata JSON moves with it if it sits next to the video. Same-day uploads are numbered by
+// the first free episode index.
+func organizeOutput(root string, c *models.Channel, v *models.Video) error {
+	tmpl := v.Settings.OutputTemplate
+	if tmpl == "" || v.VideoPath == "" {
+		return nil
+	}
+
+	if root == "" {
+		root = filepath.Dir(v.VideoPath)
+	}
+
+	dirParser := parsing.NewDirectoryParser(c, v)
+	ext := filepath.Ext(v.VideoPath)
+
+	var dest, prev string
+	for index := 1; ; index++ {
+		rel, err := dirParser.ParseOutputTemplate(tmpl, index)
+		if err != nil {
+			return err
+		}
+		dest = filepath.Join(root, rel)
+		if dest+ext == v.VideoPath {
+			return nil
+		}
+		if !outputTaken(dest, v) {
+			break
+		}
+		if rel == prev || index == maxEpisodeIndex {
+			return fmt.Errorf("output path %q is already taken", dest+ext)
+		}
+		prev = rel
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	oldDir := filepath.Dir(v.VideoPath)
+	if err := os.Rename(v.VideoPath, dest+ext); err != nil {
+		return fmt.Errorf("failed to move video to output path: %w", err)
+	}
+	logging.I("Moved %q to %q", v.VideoPath, dest+ext)
+	v.VideoPath, v.VideoDir = dest+ext, filepath.Dir(dest)
+
+	if v.JSONPath != "" && filepath.Dir(v.JSONPath) == oldDir {
+		jsonDest := dest + jsonSuffix(v.JSONPath)
+		if err := os.Rename(v.JSONPath, jsonDest); err != nil {
+			return fmt.Errorf("failed to move metadata JSON to output path: %w", err)
+		}
+		v.JSONPath, v.JSONDir = jsonDest, v.VideoDir
+	}
+	return nil
+}
+
+// outputTaken returns whether any file other than the video's own uses the output base path.
+func outputTaken(base string, v *models.Video) bool {
+	entries, err := os.ReadDir(filepath.Dir(base))
+	if err != nil {
+		return false
+	}
+	prefix := filepath.Base(base) + "."
+	for _, e := range entries {
+		path := filepath.Join(filepath.Dir(base), e.Name())
+		if strings.HasPrefix(e.Name(), prefix) && path != v.VideoPath && path != v.JSONPath {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonSuffix returns the extension of a metadata JSON file, keeping yt-dlp's ".info.json".
+func jsonSuffix(path string) string {
+	if strings.HasSuffix(strings.ToLower(path), ".info.json") {
+		return path[len(path)-len(".info.json"):]
+	}
+	return filepath.Ext(path)
+}
@@ -0,0 +1,84 @@
+package process
+
+import (
+	"path/filepath"
+	"strings"
+
+	"tubarr/internal/domain/templates"
+	"tubarr/internal/models"
+	"tubarr/internal/nfo"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+// writeNFO writes the episode NFO next to the downloaded video, and the channel's show NFO if it has none yet.
+//
+// Failures are logged rather than failing the download.
+func writeNFO(c *models.Channel, v *models.Video) {
+	if !v.Settings.NFO || v.VideoPath == "" {
+		return
+	}
+	if v.JSONPath == "" {
+		logging.W("No metadata JSON for %q, skipping NFO files", v.URL)
+		return
+	}
+	m, err := nfo.ReadMetadata(v.JSONPath)
+	if err != nil {
+		logging.E(0, "Failed to read metadata for NFO of %q: %v", v.URL, err)
+		return
+	}
+
+	dir := showDir(c, v)
+	show := &nfo.Show{Title: c.Name, Studio: m.Channel, URL: c.URL, UniqueIDs: nfo.ShowIDs(c.Settings.ExternalIDs)}
+	if show.Studio == "" {
+		show.Studio = m.Uploader
+	}
+	if written, err := nfo.WriteShow(dir, v.Settings.NFOShowTemplate, show); err != nil {
+		logging.E(0, "Failed to write %s for channel %q: %v", nfo.ShowFile, c.Name, err)
+	} else if written {
+		logging.I("Wrote %s for channel %q in %q", nfo.ShowFile, c.Name, dir)
+	}
+
+	e := m.Episode(c.Name)
+	if missing := e.MapFields(m, v.Settings.NFOFieldMap); len(missing) > 0 {
+		logging.D(1, "Metadata of %q has no %v fields mapped to its NFO, keeping their defaults", v.URL, missing)
+	}
+
+	path := strings.TrimSuffix(v.VideoPath, filepath.Ext(v.VideoPath)) + ".nfo"
+	if err := nfo.WriteEpisode(path, v.Settings.NFOEpisodeTemplate, e); err != nil {
+		logging.E(0, "Failed to write NFO for %q: %v", v.URL, err)
+		return
+	}
+	logging.D(1, "Wrote NFO %q", path)
+}
+
+// showDir returns the show directory of the channel, where its show NFO goes.
+//
+// With an output template this is the directory above the season directory. Otherwise it is the root of the
+// video directory, which for templated directories is the part before the first template if the video is inside it,
+// falling back to the video's own directory.
+func showDir(c *models.Channel, v *models.Video) string {
+	videoDir := filepath.Dir(v.VideoPath)
+
+	if tmpl := v.Settings.OutputTemplate; tmpl != "" {
+		elems := strings.Split(filepath.ToSlash(tmpl), "/")
+		for i, elem := range elems[:len(elems)-1] {
+			if strings.Contains(strings.ToLower(elem), templates.Season) {
+				dir := videoDir
+				for range len(elems) - 1 - i {
+					dir = filepath.Dir(dir)
+				}
+				return dir
+			}
+		}
+	}
+
+	root := filepath.Clean(parsing.StaticPrefix(c.VideoDir))
+	if c.VideoDir == "" {
+		return videoDir
+	}
+	if rel, err := filepath.Rel(root, videoDir); err != nil || strings.HasPrefix(rel, "..") {
+		return videoDir
+	}
+	return root
+}
@@ -0,0 +1,71 @@
+package process
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// downloadsPaused returns the global, host, or URL pause which holds back downloads from the URL, if any.
+//
+// Pauses are read from the database each time, so pauses set by another Tubarr process take effect immediately.
+func downloadsPaused(hs interfaces.HostStore, rawURL string) (*models.Pause, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, false
+	}
+
+	p, err := hs.ActivePause(u.Hostname())
+	if err != nil {
+		logging.E(0, "Failed to check download pauses for %q: %v", rawURL, err)
+		return nil, false
+	}
+	if p != nil {
+		return p, true
+	}
+	return urlPaused(hs, rawURL)
+}
+
+// urlPaused returns the pause set on the channel URL with 'channel url pause', if any.
+func urlPaused(hs interfaces.HostStore, rawURL string) (*models.Pause, bool) {
+	pauses, err := hs.FetchPauses()
+	if err != nil {
+		logging.E(0, "Failed to check URL pauses for %q: %v", rawURL, err)
+		return nil, false
+	}
+	for _, p := range pauses {
+		if p.Scope == consts.PauseURLPrefix+rawURL {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// groupPaused returns the pause holding back one of the channel's groups, if any.
+func groupPaused(cs interfaces.ChannelStore, hs interfaces.HostStore, c *models.Channel) (*models.Pause, bool) {
+	tags, err := cs.GetChannelTags(c.ID)
+	if err != nil {
+		logging.E(0, "Failed to check group pauses for channel %q: %v", c.Name, err)
+		return nil, false
+	}
+	if len(tags) == 0 {
+		return nil, false
+	}
+
+	pauses, err := hs.FetchPauses()
+	if err != nil {
+		logging.E(0, "Failed to check group pauses for channel %q: %v", c.Name, err)
+		return nil, false
+	}
+	for _, p := range pauses {
+		if tag, ok := strings.CutPrefix(p.Scope, consts.PauseGroupPrefix); ok && slices.Contains(tags, tag) {
+			return p, true
+		}
+	}
+	return nil, false
+}
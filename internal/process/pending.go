@@ -0,0 +1,64 @@
+package process
+
+import (
+	"context"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const pendingCommandTick = 10 * time.Second
+
+// drainPendingCommands runs the commands queued by other instances until shutdown, leaving later commands queued.
+func drainPendingCommands(s interfaces.Store, ctx context.Context) {
+	ticker := time.NewTicker(pendingCommandTick)
+	defer ticker.Stop()
+
+	for !draining() {
+		cmds, err := s.ChannelStore().TakePendingCommands()
+		if err != nil {
+			logging.E(0, "Failed to load pending commands: %v", err)
+		}
+		for _, cmd := range cmds {
+			runPendingCommand(s, cmd, ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-drainCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runPendingCommand starts a queued command in the background.
+func runPendingCommand(s interfaces.Store, cmd *models.PendingCommand, ctx context.Context) {
+	c, err, hasRows := s.ChannelStore().FetchChannel(cmd.ChannelID)
+	if err != nil || !hasRows {
+		logging.E(0, "Dropping queued %s of channel with ID %d, could not load channel: %v", cmd.Action, cmd.ChannelID, err)
+		return
+	}
+
+	logging.I("Running %s of channel %q queued at %s", cmd.Action, c.Name, cmd.CreatedAt.Format("2006-01-02 15:04:05"))
+	switch cmd.Action {
+	case consts.CommandCrawl:
+		goBackground(func() {
+			if err := ChannelCrawl(s, c, ctx); err != nil {
+				logging.E(0, "Crawl of channel %q failed: %v", c.Name, err)
+			}
+		})
+	case consts.CommandReprocess:
+		goBackground(func() {
+			if err := ReprocessMetarr(s, c, cmd.URLs, ctx); err != nil {
+				logging.E(0, "Metarr reprocessing for channel %q failed: %v", c.Name, err)
+			}
+		})
+	default:
+		logging.E(0, "Dropping queued command with unknown action %q for channel %q", cmd.Action, c.Name)
+	}
+}
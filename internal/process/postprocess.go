@@ -0,0 +1,32 @@
+package process
+
+import (
+	"context"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/models"
+	"tubarr/internal/postprocess"
+)
+
+// runPostProcessors runs the channel's post-processors in order on a downloaded video, updating its paths.
+func runPostProcessors(c *models.Channel, v *models.Video, ctx context.Context) error {
+	if len(v.Settings.PostProcessors) == 0 {
+		return nil
+	}
+
+	job := &postprocess.Job{
+		VideoPath: v.VideoPath,
+		JSONPath:  v.JSONPath,
+		URL:       v.URL,
+		Title:     v.Title,
+		ChannelID: c.ID,
+		Channel:   c.Name,
+	}
+	dir := postprocess.Dir(cfg.GetString(keys.PostProcessorDir))
+	if err := postprocess.Run(ctx, dir, v.Settings.PostProcessors, cfg.GetDuration(keys.PostProcessorTimeout), job); err != nil {
+		return err
+	}
+	v.VideoPath, v.JSONPath = job.VideoPath, job.JSONPath
+	return nil
+}
@@ -0,0 +1,224 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"tubarr/internal/domain/cmdvideo"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/proxy"
+)
+
+const (
+	postsDirName    = "posts"
+	postFilename    = "{filename}.{extension}"
+	postDateLayout  = "2006-01-02 15:04:05"
+	galleryDLMsgDir = 2
+	galleryDLMsgURL = 3
+)
+
+// Post metadata keys differ between gallery-dl extractors, the first present key is used.
+var (
+	postIDKeys   = []string{"post_id", "tweet_id", "shortcode", "id"}
+	postTextKeys = []string{"content", "text", "description", "caption", "title"}
+	postURLKeys  = []string{"post_url", "permalink", "link"}
+)
+
+// collectPosts archives new posts from the channel's posts feed into a "posts" folder in the video directory.
+//
+// Post text is written to "<post ID>.txt" next to the post's files, failures are logged and don't fail the crawl.
+func collectPosts(s interfaces.Store, c *models.Channel, ctx context.Context) {
+	if c.Settings.PostsURL == "" {
+		return
+	}
+
+	n, err := archivePosts(s.PostStore(), c, ctx)
+	if err != nil {
+		logging.E(0, "Failed to collect posts for channel %q: %v", c.Name, err)
+		return
+	}
+	if n > 0 {
+		logging.S(0, "Archived %d new posts for channel %q", n, c.Name)
+	}
+}
+
+// archivePosts lists the posts feed, downloads files for the posts not yet archived, and records them.
+func archivePosts(ps interfaces.PostStore, c *models.Channel, ctx context.Context) (added int, err error) {
+	posts, err := listPosts(c, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	dir := filepath.Join(c.VideoDir, postsDirName)
+	var (
+		newPosts []*models.Post
+		hasFiles bool
+	)
+	for _, p := range posts {
+		exists, err := ps.HasPost(c.ID, p.PostID)
+		if err != nil {
+			return 0, err
+		}
+		if exists {
+			continue
+		}
+		p.ChannelID, p.Dir = c.ID, dir
+		newPosts = append(newPosts, p)
+		hasFiles = hasFiles || len(p.Files) > 0
+	}
+	if len(newPosts) == 0 {
+		logging.D(1, "No new posts for channel %q", c.Name)
+		return 0, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create posts directory %q: %w", dir, err)
+	}
+	if hasFiles {
+		if out, err := postsCommand(c, ctx, cmdvideo.GalleryDLDest, dir, cmdvideo.GalleryDLFilename, postFilename).CombinedOutput(); err != nil {
+			logging.E(0, "gallery-dl failed downloading some post files for channel %q: %v\n%s", c.Name, err, out)
+		}
+	}
+
+	for _, p := range newPosts {
+		var files []string
+		for _, f := range p.Files {
+			path := filepath.Join(dir, f)
+			if _, err := os.Stat(path); err != nil {
+				logging.W("File %q of post %q was not downloaded", f, p.PostID)
+				continue
+			}
+			files = append(files, path)
+		}
+		p.Files = files
+
+		if p.Text != "" {
+			textPath := filepath.Join(dir, filepath.Base(p.PostID)+".txt")
+			if err := os.WriteFile(textPath, []byte(p.Text+"\n"), 0o644); err != nil {
+				return added, fmt.Errorf("failed to write text of post %q: %w", p.PostID, err)
+			}
+		}
+
+		ok, err := ps.AddPost(p)
+		if err != nil {
+			return added, err
+		}
+		if ok {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// listPosts reads the posts feed with gallery-dl's JSON output, grouping the listed files by post.
+func listPosts(c *models.Channel, ctx context.Context) ([]*models.Post, error) {
+	out, err := postsCommand(c, ctx, cmdvideo.GalleryDLDumpJSON).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts at %q: %w", c.Settings.PostsURL, err)
+	}
+
+	// Each message is [type, kwdict] for a post, or [type, url, kwdict] for a file
+	var messages [][]json.RawMessage
+	if err := json.Unmarshal(out, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse gallery-dl output for %q: %w", c.Settings.PostsURL, err)
+	}
+
+	var (
+		posts []*models.Post
+		byID  = make(map[string]*models.Post)
+	)
+	for _, msg := range messages {
+		if len(msg) < 2 {
+			continue
+		}
+		var msgType int
+		if err := json.Unmarshal(msg[0], &msgType); err != nil {
+			continue
+		}
+
+		var kwdict map[string]any
+		switch msgType {
+		case galleryDLMsgDir:
+			err = json.Unmarshal(msg[1], &kwdict)
+		case galleryDLMsgURL:
+			if len(msg) < 3 {
+				continue
+			}
+			err = json.Unmarshal(msg[2], &kwdict)
+		default:
+			logging.D(2, "Skipping gallery-dl message type %d in posts feed %q", msgType, c.Settings.PostsURL)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		id := firstString(kwdict, postIDKeys)
+		if id == "" {
+			continue
+		}
+		p, ok := byID[id]
+		if !ok {
+			p = &models.Post{
+				PostID: id,
+				URL:    firstString(kwdict, postURLKeys),
+				Text:   firstString(kwdict, postTextKeys),
+			}
+			if date, ok := kwdict["date"].(string); ok {
+				if t, err := time.Parse(postDateLayout, date); err == nil {
+					p.PostedAt = t
+				}
+			}
+			byID[id] = p
+			posts = append(posts, p)
+		}
+
+		if msgType == galleryDLMsgURL {
+			name, _ := kwdict["filename"].(string)
+			ext, _ := kwdict["extension"].(string)
+			if name != "" && ext != "" {
+				p.Files = append(p.Files, name+"."+ext)
+			}
+		}
+	}
+	return posts, nil
+}
+
+// postsCommand builds a gallery-dl command for the channel's posts feed with the channel's cookies.
+func postsCommand(c *models.Channel, ctx context.Context, args ...string) *exec.Cmd {
+	if c.CookiePath != "" {
+		args = append(args, cmdvideo.CookiePath, c.CookiePath)
+	} else if c.Settings.CookieSource != "" {
+		args = append(args, cmdvideo.CookieSource, c.Settings.CookieSource)
+	}
+	if p := proxy.Pick(c.Settings.Proxies); p != "" {
+		args = append(args, cmdvideo.GalleryDLProxy, p)
+	}
+	args = append(args, cmdvideo.GalleryDLSleep, cmdvideo.SleepRequestsNum, c.Settings.PostsURL)
+
+	cmd := exec.CommandContext(ctx, cmdvideo.GalleryDL, args...)
+	logging.D(1, "Built gallery-dl posts command:\n%v", cmd.String())
+	return cmd
+}
+
+// firstString returns the first non-empty value of keys in the metadata, numbers are formatted as integers.
+func firstString(kwdict map[string]any, keys []string) string {
+	for _, k := range keys {
+		switch v := kwdict[k].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return fmt.Sprintf("%.0f", v)
+		}
+	}
+	return ""
+}
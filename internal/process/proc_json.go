@@ -2,17 +2,22 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"tubarr/internal/domain/consts"
 	"tubarr/internal/downloads"
 	"tubarr/internal/interfaces"
 	"tubarr/internal/models"
 	"tubarr/internal/utils/logging"
 )
 
+// errFiltered is returned when a video is rejected by the channel's filters.
+var errFiltered = errors.New("video filtered out")
+
 // processJSON downloads and processes JSON for a video.
-func processJSON(ctx context.Context, v *models.Video, vs interfaces.VideoStore, dlTracker *downloads.DownloadTracker) error {
+func processJSON(ctx context.Context, v *models.Video, vs interfaces.VideoStore, cs interfaces.ChannelStore, ss interfaces.SkipStore, skips *skipBatch, dlTracker *downloads.DownloadTracker) error {
 	if v == nil {
 		logging.I("Null video entered")
 		return nil
@@ -29,12 +34,36 @@ func processJSON(ctx context.Context, v *models.Video, vs interfaces.VideoStore,
 	}
 
 	if err := dl.Execute(); err != nil {
+		// Left out by a date window in the channel's yt-dlp arguments
+		var dateErr *downloads.DateRangeError
+		if errors.As(err, &dateErr) {
+			logging.I("Skipping %q, %s", v.URL, dateErr.Detail)
+			skips.add(ss, &models.SkippedVideo{
+				ChannelID: v.ChannelID,
+				URL:       v.URL,
+				Reason:    consts.SkipDateWindow,
+				Detail:    dateErr.Detail,
+			})
+			return errFiltered
+		}
 		return err
 	}
 
-	_, err = parseAndStoreJSON(v)
+	valid, reason, hit, err := parseAndStoreJSON(v)
 	if err != nil {
 		logging.E(0, "JSON parsing/storage failed for %q: %v", v.URL, err)
+	} else if !valid && hit != "" {
+		skips.add(ss, &models.SkippedVideo{
+			ChannelID: v.ChannelID,
+			URL:       v.URL,
+			Reason:    reason,
+			Detail:    hit,
+		})
+		return errFiltered
+	}
+
+	if globallyIgnored(cs, ss, skips, v, true) {
+		return errFiltered
 	}
 
 	if v.ID, err = vs.AddVideo(v); err != nil {
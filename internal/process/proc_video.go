@@ -3,16 +3,19 @@ package process
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 
+	"tubarr/internal/domain/consts"
 	"tubarr/internal/downloads"
 	"tubarr/internal/interfaces"
 	"tubarr/internal/models"
 	"tubarr/internal/utils/logging"
 )
 
-// processVideo processes video downloads.
-func processVideo(ctx context.Context, v *models.Video, vs interfaces.VideoStore, dlTracker *downloads.DownloadTracker) error {
+// processVideo processes video downloads, stopping any larger than maxBytes (0 for no limit).
+func processVideo(ctx context.Context, v *models.Video, vs interfaces.VideoStore, dlTracker *downloads.DownloadTracker, maxBytes int64) error {
 	if v == nil {
 		logging.I("Null video entered")
 		return nil
@@ -23,11 +26,19 @@ func processVideo(ctx context.Context, v *models.Video, vs interfaces.VideoStore
 	dl, err := downloads.NewDownload(downloads.TypeVideo, ctx, v, dlTracker, &downloads.Options{
 		MaxRetries:    3,
 		RetryInterval: 5 * time.Second,
+		MaxBytes:      maxBytes,
 	})
 	if err != nil {
 		return err
 	}
 
+	// Recorded before downloading, so failed extractions show the version too
+	if f := v.Settings.FetcherFor(v.URL); f == "" || f == consts.FetcherYTDLP {
+		if err := vs.SetYTDLPVersion(v.ID, getYTDLPVersion(ctx)); err != nil {
+			logging.E(0, "%v", err)
+		}
+	}
+
 	if err := dl.Execute(); err != nil {
 		return err
 	}
@@ -39,3 +50,21 @@ func processVideo(ctx context.Context, v *models.Video, vs interfaces.VideoStore
 	logging.D(1, "Successfully processed and marked as downloaded: %s", v.URL)
 	return nil
 }
+
+// cancelVideoHandler marks the video in the request path as cancelled by the user, stopping its download if running.
+func cancelVideoHandler(ds interfaces.DownloadStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid video ID", http.StatusBadRequest)
+			return
+		}
+		if err := ds.CancelDownload(id, consts.CancelUser); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		stopped := downloads.CancelLive(id, consts.CancelUser)
+		logging.I("Video with ID %d cancelled by HTTP user %q", id, requestUser(r).Username)
+		writeJSON(w, http.StatusOK, map[string]any{"video_id": id, "stopped": stopped})
+	}
+}
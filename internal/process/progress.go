@@ -0,0 +1,125 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tubarr/internal/downloads"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+)
+
+// progressKeepAlive is how often an idle progress stream sends a comment, so proxies don't close it.
+const progressKeepAlive = 30 * time.Second
+
+// progressOutput is a download status update as streamed over HTTP.
+type progressOutput struct {
+	VideoID      int64   `json:"video_id"`
+	ChannelID    int64   `json:"channel_id"`
+	URL          string  `json:"url"`
+	Status       string  `json:"status"`
+	Percent      float64 `json:"percent"`
+	Speed        string  `json:"speed,omitempty"`
+	ETA          string  `json:"eta,omitempty"`
+	Error        string  `json:"error,omitempty"`
+	CancelReason string  `json:"cancel_reason,omitempty"`
+	FailReason   string  `json:"fail_reason,omitempty"`
+	FragsDone    int     `json:"fragments_done,omitempty"`
+	FragsTotal   int     `json:"fragments_total,omitempty"`
+}
+
+// toProgressOutput converts a download status update for the progress stream.
+func toProgressOutput(u models.StatusUpdate) progressOutput {
+	out := progressOutput{
+		VideoID:      u.VideoID,
+		ChannelID:    u.ChannelID,
+		URL:          u.VideoURL,
+		Status:       string(u.Status),
+		Percent:      u.Percent,
+		Speed:        u.Speed,
+		ETA:          u.ETA,
+		CancelReason: string(u.CancelReason),
+		FailReason:   string(u.FailReason),
+		FragsDone:    u.Partial.FragsDone,
+		FragsTotal:   u.Partial.FragsTotal,
+	}
+	if u.Error != nil {
+		out.Error = u.Error.Error()
+	}
+	return out
+}
+
+// progressHandler streams download progress and status changes as server-sent "progress" events.
+//
+// Only downloads in channels the user can see are sent, and only one channel's if channel_id is set.
+// The stream ends when the client disconnects or the server shuts down.
+func progressHandler(us interfaces.UserStore, ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var chanID int64
+		if v := r.URL.Query().Get("channel_id"); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid channel ID", http.StatusBadRequest)
+				return
+			}
+			chanID = id
+		}
+
+		visible, err := visibleChannels(us, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if chanID != 0 && visible != nil && !visible[chanID] {
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported by this connection", http.StatusInternalServerError)
+			return
+		}
+
+		updates, unsubscribe := downloads.SubscribeProgress()
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		keepAlive := time.NewTicker(progressKeepAlive)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ctx.Done():
+				return
+			case <-keepAlive.C:
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case u := <-updates:
+				if (chanID != 0 && u.ChannelID != chanID) || (visible != nil && !visible[u.ChannelID]) {
+					continue
+				}
+				data, err := json.Marshal(toProgressOutput(u))
+				if err != nil {
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "event: progress\ndata: %s\n\n", data); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	quotaPruneBatch = 20
+)
+
+// diskQuota tracks a channel's disk usage against its max total size during a processing run.
+//
+// A nil quota allows every download.
+type diskQuota struct {
+	mu       sync.Mutex
+	limit    int64
+	used     int64
+	prune    bool
+	skipped  int
+	pruned   int
+	freed    int64
+	pruneErr error
+}
+
+// newDiskQuota returns the channel's quota, starting from the usage of the files recorded in the videos table.
+func newDiskQuota(ss interfaces.StorageStore, c *models.Channel) *diskQuota {
+	if c.Settings.MaxTotalSize == "" {
+		return nil
+	}
+	limit, err := parsing.ParseSize(c.Settings.MaxTotalSize)
+	if err != nil {
+		logging.E(0, "Ignoring max total size for channel %q: %v", c.Name, err)
+		return nil
+	}
+
+	usage, err := ss.RefreshChannelStorage(c.ID)
+	if err != nil {
+		logging.E(0, "Ignoring max total size for channel %q, could not get disk usage: %v", c.Name, err)
+		return nil
+	}
+	return &diskQuota{
+		limit: limit,
+		used:  usage.VideoBytes + usage.JSONBytes,
+		prune: c.Settings.QuotaPrune,
+	}
+}
+
+// allow reports whether another video may be downloaded, pruning the oldest downloads first if enabled.
+func (q *diskQuota) allow(vs interfaces.VideoStore, c *models.Channel) bool {
+	if q == nil {
+		return true
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.used < q.limit {
+		return true
+	}
+	if q.prune && q.pruneErr == nil {
+		if q.pruneErr = q.pruneOldest(vs, c); q.pruneErr != nil {
+			logging.E(0, "Failed to prune videos for channel %q: %v", c.Name, q.pruneErr)
+		}
+		if q.used < q.limit {
+			return true
+		}
+	}
+	q.skipped++
+	return false
+}
+
+// add counts a newly downloaded file towards the channel's usage.
+func (q *diskQuota) add(path string) {
+	if q == nil || path == "" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	q.mu.Lock()
+	q.used += info.Size()
+	q.mu.Unlock()
+}
+
+// remaining returns the room left under the limit, 0 for no limit.
+func (q *diskQuota) remaining() int64 {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return max(q.limit-q.used, 1)
+}
+
+// pruneOldest deletes the channel's oldest downloaded files until usage is under the limit.
+//
+// The video records are kept with their paths cleared, so pruned videos aren't downloaded again.
+func (q *diskQuota) pruneOldest(vs interfaces.VideoStore, c *models.Channel) error {
+	for q.used >= q.limit {
+		videos, err := vs.FetchOldestDownloads(c.ID, quotaPruneBatch)
+		if err != nil {
+			return err
+		}
+		if len(videos) == 0 {
+			return errors.New("no downloaded videos left to prune")
+		}
+
+		for _, v := range videos {
+			freed, err := removeDownload(vs, v)
+			if err != nil {
+				return err
+			}
+
+			logging.I("Pruned %q (%s) from channel %q to stay under its max total size", v.Title, v.URL, c.Name)
+			q.used -= freed
+			q.freed += freed
+			q.pruned++
+			if q.used < q.limit {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// warning returns a summary of videos skipped or pruned for the quota this run, or an empty string if none were.
+func (q *diskQuota) warning(c *models.Channel) string {
+	if q == nil {
+		return ""
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var msg string
+	if q.pruned > 0 {
+		msg = fmt.Sprintf("Channel %q pruned %d oldest videos (%s) to stay under its max total size of %s.",
+			c.Name, q.pruned, parsing.FormatSize(q.freed), c.Settings.MaxTotalSize)
+	}
+	if q.skipped > 0 {
+		if msg != "" {
+			msg += " "
+		}
+		msg += fmt.Sprintf("Channel %q skipped %d downloads, it is using %s of its max total size of %s.",
+			c.Name, q.skipped, parsing.FormatSize(q.used), c.Settings.MaxTotalSize)
+	}
+	return msg
+}
+
+// reportQuota logs, records, and sends the channel's webhooks a warning if the quota affected this run.
+func reportQuota(cs interfaces.ChannelStore, c *models.Channel, q *diskQuota) {
+	msg := q.warning(c)
+	if msg == "" {
+		return
+	}
+	logging.W("%s", msg)
+
+	if err := cs.RecordChannelEvent(c.ID, consts.ActivityQuota, msg); err != nil {
+		logging.E(0, "Failed to record quota warning for channel %q: %v", c.Name, err)
+	}
+
+	hooks, err := cs.GetWebhooks(c.ID)
+	if err != nil {
+		logging.E(0, "Failed to load webhooks for channel %q: %v", c.Name, err)
+		return
+	}
+	for _, err := range sendWarningWebhooks(c, hooks, msg) {
+		logging.E(0, "%v", err)
+	}
+}
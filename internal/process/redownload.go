@@ -0,0 +1,118 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/ytdlp"
+)
+
+// Redownload requeues videos of the channel and runs them through the download and Metarr pipeline again.
+//
+// If deleteFiles is set the existing files are deleted first, otherwise yt-dlp may find and keep them.
+// No videos are requeued unless all of them are found in the channel.
+func Redownload(s interfaces.Store, c *models.Channel, urls []string, deleteFiles bool, ctx context.Context) error {
+	if len(urls) == 0 {
+		return errors.New("no video URLs entered to redownload")
+	}
+	if err := checkChannelMounts(c); err != nil {
+		return err
+	}
+	if err := ytdlp.CheckPlugins(ctx); err != nil {
+		return err
+	}
+
+	action := consts.BulkRequeue
+	if deleteFiles {
+		action = consts.BulkRedownload
+	}
+	results, err := s.VideoStore().BulkVideoAction(c.ID, action, nil, urls)
+	if err != nil {
+		for _, r := range results {
+			if r.Err != nil {
+				logging.E(0, "Cannot redownload %q: %v", r.URL, r.Err)
+			}
+		}
+		return err
+	}
+
+	// Copy the channel, since processing parses templated directories in place
+	rc := *c
+	videos := make([]*models.Video, 0, len(results))
+	for _, r := range results {
+		videos = append(videos, &models.Video{
+			ChannelID:  rc.ID,
+			URL:        r.URL,
+			VideoDir:   rc.VideoDir,
+			JSONDir:    rc.JSONDir,
+			Channel:    &rc,
+			Settings:   rc.Settings,
+			MetarrArgs: rc.MetarrArgs,
+			CookiePath: rc.CookiePath,
+		})
+	}
+
+	logging.I("Redownloading %d videos for channel %q", len(videos), c.Name)
+	success, errArray := InitProcess(s, &rc, videos, ctx)
+	refreshStorage(s, &rc)
+	if !success && len(errArray) > 0 {
+		return fmt.Errorf("encountered %d errors during processing: %v", len(errArray), errArray)
+	}
+	for _, err := range errArray {
+		logging.E(0, "Error redownloading video in channel %q: %v", c.Name, err)
+	}
+	return notifyChannel(s, &rc, videos)
+}
+
+// redownloadHandler downloads the video in the request path again in the background.
+//
+// Only admins may set delete_files, since it deletes the existing files first.
+func redownloadHandler(s interfaces.Store, ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid video ID", http.StatusBadRequest)
+			return
+		}
+		var deleteFiles bool
+		if v := r.URL.Query().Get("delete_files"); v != "" {
+			if deleteFiles, err = strconv.ParseBool(v); err != nil {
+				http.Error(w, "invalid delete_files value", http.StatusBadRequest)
+				return
+			}
+		}
+		if deleteFiles && !requestUser(r).IsAdmin() {
+			http.Error(w, "only admins can delete files", http.StatusForbidden)
+			return
+		}
+
+		chanID, videoURL, err := s.VideoStore().GetVideoURL(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		c, err, hasRows := s.ChannelStore().FetchChannel(chanID)
+		switch {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		case !hasRows:
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		go func() {
+			if err := Redownload(s, c, []string{videoURL}, deleteFiles, ctx); err != nil {
+				logging.E(0, "Redownload of %q failed: %v", videoURL, err)
+			}
+		}()
+		writeJSON(w, http.StatusAccepted, map[string]any{"video_id": id, "url": videoURL})
+	}
+}
@@ -0,0 +1,101 @@
+package process
+
+import (
+	"net/url"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/email"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/webhook"
+)
+
+// countResults adds a crawl's downloaded and filtered videos, and its failures, to the channel's report.
+func countResults(report *models.ChannelReport, videos []*models.Video, failures int) {
+	for _, v := range videos {
+		switch {
+		case v.Filtered:
+			report.Filtered++
+		case v.DownloadStatus.Status == consts.DLStatusCompleted:
+			report.NewVideos++
+		}
+	}
+	report.Failures += failures
+}
+
+// reportCrawl stores the summary of a crawl pass and sends it to the digest webhook and email recipients, if set.
+//
+// Passes with no new videos, failures, filtered videos, or failed channels are stored but not sent.
+func reportCrawl(s interfaces.Store, r *models.CrawlReport) {
+	ts := s.StatsStore()
+	t := r.Totals()
+	failed := r.FailedChannels()
+	logging.I("Crawl pass finished for %d channels (%d failed): %d new videos, %d failures, %d filtered", len(r.Channels), failed, t.NewVideos, t.Failures, t.Filtered)
+
+	id, err := ts.RecordCrawlReport(r)
+	if err != nil {
+		logging.E(0, "%v", err)
+	}
+
+	if t.NewVideos == 0 && t.Failures == 0 && t.Filtered == 0 && failed == 0 {
+		return
+	}
+
+	sent := sendDigestWebhook(r)
+	if to := cfg.GetStringSlice(keys.DigestEmail); len(to) > 0 {
+		if err := sendDigestEmail(s.ChannelStore(), to, r); err != nil {
+			logging.E(0, "Failed to email crawl digest: %v", err)
+		} else {
+			logging.S(1, "Emailed crawl digest to %v", to)
+			sent = true
+		}
+	}
+
+	if sent && id != 0 {
+		if err := ts.MarkDigestSent(id); err != nil {
+			logging.E(0, "%v", err)
+		}
+	}
+}
+
+// sendDigestWebhook posts the crawl report to the digest webhook, returning whether it was sent.
+func sendDigestWebhook(r *models.CrawlReport) bool {
+	hookURL := cfg.GetString(keys.DigestWebhook)
+	if hookURL == "" {
+		return false
+	}
+	parsed, err := url.Parse(hookURL)
+	if err != nil {
+		logging.E(0, "Invalid digest webhook URL %q: %v", hookURL, err)
+		return false
+	}
+
+	body, err := webhook.Digest(r)
+	if err != nil {
+		logging.E(0, "Failed to build crawl digest: %v", err)
+		return false
+	}
+	initClients()
+	client := regClient
+	if isPrivateNetwork(parsed.Host) {
+		client = lanClient
+	}
+	if err := webhook.Send(client, &models.Webhook{Name: "digest", URL: hookURL}, body); err != nil {
+		logging.E(0, "Failed to send crawl digest: %v", err)
+		return false
+	}
+	logging.S(1, "Sent crawl digest to %q", parsed.Host)
+	return true
+}
+
+// sendDigestEmail mails the crawl report to the recipients with the global SMTP settings.
+func sendDigestEmail(cs interfaces.ChannelStore, to []string, r *models.CrawlReport) error {
+	smtpCfg, err := cs.GetSMTPConfig()
+	if err != nil {
+		return err
+	}
+	return email.Send(smtpCfg, to, email.FromReport(r))
+}
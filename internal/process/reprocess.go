@@ -0,0 +1,157 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"tubarr/internal/interfaces"
+	"tubarr/internal/metarr"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+// ReprocessMetarr runs the channel's downloaded videos through Metarr again with its current Metarr arguments.
+//
+// Videos are not downloaded again. If urls is set only those videos are processed. Videos missing their
+// video or JSON file are skipped, and a failed video doesn't stop the rest.
+func ReprocessMetarr(s interfaces.Store, c *models.Channel, urls []string, ctx context.Context) error {
+	if _, err := exec.LookPath("metarr"); err != nil {
+		return fmt.Errorf("'metarr' not available: %w", err)
+	}
+	if err := checkChannelMounts(c); err != nil {
+		return err
+	}
+
+	vs := s.VideoStore()
+
+	// Collected first, so paths can be updated without holding the query open
+	var videos []*models.Video
+	if err := vs.StreamChannelVideos(c.ID, func(v *models.Video) error {
+		if v.VideoPath == "" || (len(urls) > 0 && !slices.Contains(urls, v.URL)) {
+			return nil
+		}
+		videos = append(videos, v)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(videos) == 0 {
+		logging.I("No downloaded videos to reprocess for channel %q", c.Name)
+		return nil
+	}
+
+	// Copy the channel, since processing parses templated directories in place
+	rc := *c
+
+	logging.I("Reprocessing %d videos for channel %q with Metarr", len(videos), c.Name)
+	var done, skipped, failed int
+	for _, v := range videos {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if missing := missingFile(v.VideoPath, v.JSONPath); missing != "" {
+			logging.W("Skipping %q, its file %q is missing", v.URL, missing)
+			skipped++
+			continue
+		}
+
+		v.Channel = &rc
+		v.Settings = rc.Settings
+		v.MetarrArgs = rc.MetarrArgs
+		v.VideoDir, v.JSONDir = filepath.Dir(v.VideoPath), filepath.Dir(v.JSONPath)
+
+		// Already organized videos are organized again from the video directory root
+		var (
+			root string
+			err  error
+		)
+		if v.Settings.OutputTemplate != "" && rc.MetarrArgs.OutputDir == "" {
+			if root, err = parsing.NewDirectoryParser(c, v).ParseDirectory(c.VideoDir); err != nil {
+				logging.E(0, "Failed to parse video directory for %q: %v", v.URL, err)
+				failed++
+				continue
+			}
+		}
+
+		if err := metarr.InitMetarr(v, ctx); err != nil {
+			logging.E(0, "Failed to reprocess %q with Metarr: %v", v.URL, err)
+			failed++
+			continue
+		}
+		if err := organizeOutput(root, &rc, v); err != nil {
+			logging.E(0, "Failed to organize files of %q: %v", v.URL, err)
+			failed++
+			continue
+		}
+		if err := vs.UpdateVideoPaths(v.ID, v.VideoPath, v.JSONPath); err != nil {
+			logging.E(0, "Failed to update paths of %q after Metarr: %v", v.URL, err)
+			failed++
+			continue
+		}
+		writeSidecars(v)
+		writeNFO(&rc, v)
+		done++
+	}
+	refreshStorage(s, &rc)
+
+	logging.S(0, "Reprocessed %d videos for channel %q (%d skipped, %d failed)", done, c.Name, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("metarr failed for %d of %d videos", failed, len(videos))
+	}
+	return nil
+}
+
+// missingFile returns the first of the paths which is empty or doesn't exist, or an empty string if all exist.
+func missingFile(paths ...string) string {
+	for _, p := range paths {
+		if p == "" {
+			return "(none recorded)"
+		}
+		if _, err := os.Stat(p); err != nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// reprocessHandler runs the downloaded videos of the channel in the request path through Metarr again in the background.
+//
+// Repeated url query parameters limit it to those videos.
+func reprocessHandler(s interfaces.Store, ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid channel ID", http.StatusBadRequest)
+			return
+		}
+		if _, err := exec.LookPath("metarr"); err != nil {
+			http.Error(w, "'metarr' not available", http.StatusServiceUnavailable)
+			return
+		}
+
+		c, err, hasRows := s.ChannelStore().FetchChannel(id)
+		switch {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		case !hasRows:
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		urls := r.URL.Query()["url"]
+		goBackground(func() {
+			if err := ReprocessMetarr(s, c, urls, ctx); err != nil {
+				logging.E(0, "Metarr reprocessing for channel %q failed: %v", c.Name, err)
+			}
+		})
+		writeJSON(w, http.StatusAccepted, map[string]any{"channel_id": id})
+	}
+}
@@ -0,0 +1,126 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// ResumeDownloads restarts downloads left unfinished by a previous run which was killed or shut down.
+//
+// yt-dlp continues from the partial files still on disk, or starts over if they are gone. Failed downloads
+// are left to the retry queue, and the partial files of downloads cancelled since are removed.
+func ResumeDownloads(s interfaces.Store, ctx context.Context) error {
+	ds := s.DownloadStore()
+	dls, err := ds.FetchInterruptedDownloads()
+	if err != nil {
+		return err
+	}
+
+	resume := make(map[int64][]*models.InterruptedDownload)
+	for _, d := range dls {
+		switch {
+		case d.Status == consts.DLStatusFailed:
+			continue
+		case d.Status == consts.DLStatusCancelled && d.CancelReason != consts.CancelShutdown:
+			removePartialFiles(d.Partial.Path)
+			if err := ds.ClearPartialDownload(d.VideoID); err != nil {
+				logging.E(0, "%v", err)
+			}
+		default:
+			resume[d.ChannelID] = append(resume[d.ChannelID], d)
+		}
+	}
+
+	var errs []error
+	for chanID, chanDLs := range resume {
+		c, err, hasRows := s.ChannelStore().FetchChannel(chanID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !hasRows {
+			continue
+		}
+
+		videos := make([]*models.Video, 0, len(chanDLs))
+		for _, d := range chanDLs {
+			if partialExists(d.Partial.Path) {
+				logging.I("Resuming download of %q from %s", d.URL, describePartial(d.Partial))
+			} else {
+				logging.I("Partial files for %q are gone, downloading again from the start", d.URL)
+			}
+			videos = append(videos, &models.Video{
+				ChannelID:  c.ID,
+				URL:        d.URL,
+				VideoDir:   c.VideoDir,
+				JSONDir:    c.JSONDir,
+				Channel:    c,
+				Settings:   c.Settings,
+				MetarrArgs: c.MetarrArgs,
+				CookiePath: c.CookiePath,
+			})
+		}
+
+		logging.I("Resuming %d interrupted downloads for channel %q", len(videos), c.Name)
+		success, procErrs := InitProcess(s, c, videos, ctx)
+		errs = append(errs, procErrs...)
+		if success {
+			refreshStorage(s, c)
+			if err := notifyChannel(s, c, videos); err != nil {
+				logging.E(0, "Failed to notify after resuming downloads for channel %q: %v", c.Name, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// partialExists reports whether any of a download's partial files are on disk.
+func partialExists(path string) bool {
+	for _, p := range []string{path + ".part", path} {
+		if _, err := os.Stat(p); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// describePartial returns the partial file and fragment progress of a download for logging.
+func describePartial(p models.PartialDownload) string {
+	if p.FragsTotal > 0 {
+		return fmt.Sprintf("%q (fragment %d/%d)", p.Path, p.FragsDone, p.FragsTotal)
+	}
+	return fmt.Sprintf("%q", p.Path)
+}
+
+// removePartialFiles removes a download's partial file, its fragments, and yt-dlp's resume state.
+func removePartialFiles(path string) {
+	dir, base := filepath.Split(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logging.E(0, "Failed to read directory of partial download %q: %v", path, err)
+		}
+		return
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if name != base && name != base+".ytdl" && !strings.HasPrefix(name, base+".part") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			logging.E(0, "Failed to remove partial download file %q: %v", name, err)
+			continue
+		}
+		logging.D(1, "Removed partial download file %q", name)
+	}
+}
@@ -0,0 +1,98 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+// applyRetention deletes the channel's downloads past its keep last and keep days policies.
+//
+// The video records are kept with their paths cleared, so deleted videos aren't downloaded again.
+func applyRetention(s interfaces.Store, c *models.Channel) {
+	keepLast, keepDays := c.Settings.KeepLast, c.Settings.KeepDays
+	if keepLast < 1 && keepDays < 1 {
+		return
+	}
+
+	var before time.Time
+	if keepDays > 0 {
+		before = time.Now().AddDate(0, 0, -keepDays)
+	}
+
+	vs := s.VideoStore()
+	videos, err := vs.FetchExpiredDownloads(c.ID, keepLast, before)
+	if err != nil {
+		logging.E(0, "Failed to check retention for channel %q: %v", c.Name, err)
+		return
+	}
+	if len(videos) == 0 {
+		return
+	}
+
+	var (
+		removed int
+		freed   int64
+	)
+	for _, v := range videos {
+		n, err := removeDownload(vs, v)
+		if err != nil {
+			logging.E(0, "Failed to delete %q from channel %q: %v", v.URL, c.Name, err)
+			continue
+		}
+		logging.D(1, "Deleted %q (%s) from channel %q, past its retention policy", v.Title, v.URL, c.Name)
+		removed++
+		freed += n
+	}
+	if removed == 0 {
+		return
+	}
+
+	logging.I("Deleted %d videos (%s) from channel %q past its retention policy", removed, parsing.FormatSize(freed), c.Name)
+	refreshStorage(s, c)
+
+	if !c.Settings.RetentionNotify {
+		return
+	}
+	notifyURLs, err := s.ChannelStore().GetNotifyURLs(c.ID)
+	if err != nil || len(notifyURLs) == 0 {
+		return
+	}
+	for _, err := range notify(c, notifyURLs) {
+		logging.E(0, "Failed to notify after retention for channel %q: %v", c.Name, err)
+	}
+}
+
+// removeDownload deletes a video's files and clears its stored paths, returning the bytes freed.
+func removeDownload(vs interfaces.VideoStore, v *models.Video) (freed int64, err error) {
+	transcript, err := vs.GetTranscriptPath(v.ID)
+	if err != nil {
+		return 0, err
+	}
+	for _, path := range append([]string{v.VideoPath, v.JSONPath, transcript}, SidecarPaths(v.VideoPath)...) {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			freed += info.Size()
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("failed to remove %q: %w", path, err)
+		}
+	}
+	if err := vs.UpdateVideoPaths(v.ID, "", ""); err != nil {
+		return 0, err
+	}
+	if transcript != "" {
+		if err := vs.SetTranscriptPath(v.ID, ""); err != nil {
+			return 0, err
+		}
+	}
+	return freed, nil
+}
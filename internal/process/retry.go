@@ -0,0 +1,129 @@
+package process
+
+import (
+	"context"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/schedule"
+	"tubarr/internal/utils/logging"
+)
+
+// retryBaseBackoff is the delay before a failed download's first retry, doubling after each further failure.
+const retryBaseBackoff = 15 * time.Minute
+
+// queueRetry adds a failed download to the retry queue, if the channel has retries enabled.
+//
+// Cancelled downloads aren't retried.
+func queueRetry(rs interfaces.RetryStore, c *models.Channel, v *models.Video, dlErr error, ctx context.Context) {
+	if c.Settings.RetryMaxAttempts < 1 || ctx.Err() != nil || v.URL == "" || v.DownloadStatus.Status == consts.DLStatusCancelled {
+		return
+	}
+
+	entry, err := rs.QueueRetry(c.ID, v.URL, dlErr.Error(), c.Settings.RetryMaxAttempts, retryBaseBackoff)
+	if err != nil {
+		logging.E(0, "Failed to queue retry for %q: %v", v.URL, err)
+		return
+	}
+
+	reason := v.DownloadStatus.FailReason
+	if reason == "" {
+		reason = consts.FailUnknown
+	}
+
+	if entry.Exhausted() {
+		logging.E(0, "Giving up on %q after %d failed attempts (reason: %s)", v.URL, entry.Attempts, reason)
+		return
+	}
+	logging.I("Queued retry %d/%d for %q in %s (reason: %s)",
+		entry.Attempts, c.Settings.RetryMaxAttempts-1, v.URL, time.Until(entry.NextAttempt).Round(time.Second), reason)
+}
+
+// clearRetry removes a successfully downloaded video from the retry queue.
+func clearRetry(rs interfaces.RetryStore, c *models.Channel, v *models.Video) {
+	if err := rs.ClearRetry(c.ID, v.URL); err != nil {
+		logging.E(0, "Failed to clear retry entry for %q: %v", v.URL, err)
+	}
+}
+
+// retryDue reprocesses queued downloads which are due, returning when the next retry is due.
+//
+// Retries are held back by the channel's quiet hours, blackout dates, and download pauses, like crawls.
+func retryDue(s interfaces.Store, chans []*models.Channel, ctx context.Context) (next time.Time) {
+	rs := s.RetryStore()
+	now := time.Now()
+
+	entries, err := rs.FetchDueRetries(now)
+	if err != nil {
+		logging.E(0, "Failed to load due retries: %v", err)
+		return time.Time{}
+	}
+
+	due := make(map[int64][]*models.RetryEntry, len(entries))
+	for _, e := range entries {
+		due[e.ChannelID] = append(due[e.ChannelID], e)
+	}
+
+	for _, c := range chans {
+		if len(due[c.ID]) == 0 || c.Settings.RetryMaxAttempts < 1 {
+			continue
+		}
+		if held := schedule.Unheld(c, now); held.At.After(now) {
+			logging.I("Holding %d retries for channel %q until %s (%s)", len(due[c.ID]), c.Name, held.At.Format(time.DateTime), held.Reason)
+			if next.IsZero() || held.At.Before(next) {
+				next = held.At
+			}
+			continue
+		}
+		p, paused := downloadsPaused(s.HostStore(), c.URL)
+		if !paused {
+			p, paused = groupPaused(s.ChannelStore(), s.HostStore(), c)
+		}
+		if paused {
+			logging.I("Holding %d retries for channel %q, downloads are paused for %s", len(due[c.ID]), c.Name, p.Describe())
+			if !p.Until.IsZero() && (next.IsZero() || p.Until.Before(next)) {
+				next = p.Until
+			}
+			continue
+		}
+
+		// Copy the channel, since processing parses templated directories in place
+		rc := *c
+		videos := make([]*models.Video, 0, len(due[c.ID]))
+		for _, e := range due[c.ID] {
+			videos = append(videos, &models.Video{
+				ChannelID:  rc.ID,
+				URL:        e.URL,
+				VideoDir:   rc.VideoDir,
+				JSONDir:    rc.JSONDir,
+				Channel:    &rc,
+				Settings:   rc.Settings,
+				MetarrArgs: rc.MetarrArgs,
+				CookiePath: rc.CookiePath,
+			})
+		}
+
+		logging.I("Retrying %d failed downloads for channel %q", len(videos), c.Name)
+		success, errs := InitProcess(s, &rc, videos, ctx)
+		if len(errs) > 0 {
+			logging.E(0, "%d retries failed for channel %q", len(errs), c.Name)
+		}
+		if success {
+			refreshStorage(s, &rc)
+			if err := notifyChannel(s, &rc, videos); err != nil {
+				logging.E(0, "Failed to notify after retries for channel %q: %v", c.Name, err)
+			}
+		}
+	}
+
+	pending, err := rs.NextRetryAt(now)
+	if err != nil {
+		logging.E(0, "Failed to check next retry time: %v", err)
+	}
+	if !pending.IsZero() && (next.IsZero() || pending.Before(next)) {
+		next = pending
+	}
+	return next
+}
@@ -0,0 +1,63 @@
+package process
+
+import (
+	"context"
+	"time"
+
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	minSchedulerWait = 5 * time.Second
+	maxSchedulerWait = 5 * time.Minute
+)
+
+// RunScheduler crawls channels as they become due until the context is cancelled.
+//
+// Channels are re-read on every pass, so schedule changes made while running are picked up
+// within a few minutes. Channels which fail are retried on the next pass. Channels with a
+// live URL are watched alongside, capturing their streams as they start. Health checks and
+// downloaded videos are served over HTTP if an address is configured, and commands queued by
+// other instances are run as they arrive.
+func RunScheduler(s interfaces.Store, ctx context.Context) error {
+	logging.I("Scheduler started, crawling channels as they become due")
+	go watchLive(s, ctx)
+	go drainPendingCommands(s, ctx)
+	if addr := HTTPAddr(); addr != "" {
+		go func() {
+			if err := serveHTTP(s, addr, ctx); err != nil {
+				logging.E(0, "%v", err)
+			}
+		}()
+	}
+
+	for {
+		nextDue, err := crawlDueChannels(s, ctx)
+		if err != nil {
+			logging.E(0, "Scheduled crawl pass encountered errors: %v", err)
+		}
+
+		wait := maxSchedulerWait
+		if !nextDue.IsZero() {
+			if untilDue := time.Until(nextDue); untilDue < wait {
+				wait = max(untilDue, minSchedulerWait)
+			}
+		}
+		logging.I("Scheduler sleeping for %s", wait.Round(time.Second))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logging.I("Scheduler stopped")
+			return nil
+		case <-drainCh:
+			timer.Stop()
+			logging.I("Scheduler stopped, waiting for background crawls to finish")
+			waitBackground(ctx)
+			return nil
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/downloads"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/metarr"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/webhook"
+	"tubarr/internal/utils/ytdlp"
+	"tubarr/internal/utils/ytdlpbin"
+)
+
+// errStageSkipped marks a self-test stage which did not apply, e.g. Metarr when it isn't installed.
+var errStageSkipped = errors.New("skipped")
+
+// selfTestStage is the result of one step of the self-test.
+type selfTestStage struct {
+	name    string
+	detail  string
+	err     error
+	elapsed time.Duration
+}
+
+// selfTest holds the state passed between self-test stages.
+type selfTest struct {
+	s        interfaces.Store
+	c        *models.Channel
+	v        *models.Video
+	dir      string
+	useMetar bool
+	stages   []selfTestStage
+}
+
+// SelfTest runs one video through the download pipeline with a temporary channel, reporting each stage.
+//
+// Stages stop at the first failure, since each depends on the one before.
+func SelfTest(s interfaces.Store, ctx context.Context) error {
+	mediaURL := cfg.GetString(keys.SelfTestURL)
+	if mediaURL == "" {
+		mediaURL = consts.SelfTestURL
+	}
+
+	dir, err := os.MkdirTemp("", consts.SelfTestChannel+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create self-test directory: %w", err)
+	}
+	if cfg.GetBool(keys.SelfTestKeep) {
+		defer logging.I("Self-test files kept in %q", dir)
+	} else {
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				logging.E(0, "Failed to remove self-test directory %q: %v", dir, err)
+			}
+		}()
+	}
+
+	t := &selfTest{s: s, dir: dir}
+	defer t.removeChannel()
+
+	steps := []struct {
+		name string
+		fn   func(ctx context.Context) (string, error)
+	}{
+		{"tools", t.checkTools},
+		{"channel", func(context.Context) (string, error) { return t.addChannel(mediaURL) }},
+		{"metadata", t.fetchMetadata},
+		{"download", t.download},
+		{"metarr", t.runMetarr},
+		{"move", t.checkMoved},
+		{"notify", t.renderNotifications},
+	}
+
+	var failed string
+	for _, step := range steps {
+		start := time.Now()
+		detail, err := step.fn(ctx)
+		t.stages = append(t.stages, selfTestStage{name: step.name, detail: detail, err: err, elapsed: time.Since(start)})
+		if err != nil && !errors.Is(err, errStageSkipped) {
+			failed = step.name
+			break
+		}
+	}
+
+	t.report(len(steps))
+	if failed != "" {
+		return fmt.Errorf("stage %q failed", failed)
+	}
+	return nil
+}
+
+// checkTools checks yt-dlp runs, and whether Metarr is installed.
+func (t *selfTest) checkTools(ctx context.Context) (string, error) {
+	if _, err := exec.LookPath(ytdlpbin.Path()); err != nil {
+		return "", fmt.Errorf("yt-dlp not found: %w", err)
+	}
+	out, err := ytdlp.Command(ctx, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("yt-dlp failed to run: %w", err)
+	}
+	detail := "yt-dlp " + strings.TrimSpace(string(out))
+
+	if _, err := exec.LookPath("metarr"); err == nil {
+		t.useMetar = true
+		return detail + ", metarr found", nil
+	}
+	return detail + ", metarr not found", nil
+}
+
+// addChannel adds the temporary channel the test video is downloaded for, replacing any left by an earlier run.
+func (t *selfTest) addChannel(mediaURL string) (string, error) {
+	cs := t.s.ChannelStore()
+	t.removeChannel()
+
+	c := &models.Channel{
+		Name:     consts.SelfTestChannel,
+		URL:      "https://" + consts.SelfTestChannel + ".invalid/",
+		VideoDir: filepath.Join(t.dir, "videos"),
+		JSONDir:  filepath.Join(t.dir, "json"),
+		Settings: models.ChannelSettings{
+			CrawlFreq:  30,
+			SkipMetarr: !t.useMetar,
+		},
+		MetarrArgs: models.MetarrArgs{
+			OutputDir: filepath.Join(t.dir, "output"),
+		},
+	}
+	for _, d := range []string{c.VideoDir, c.JSONDir} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			return "", fmt.Errorf("failed to create %q: %w", d, err)
+		}
+	}
+
+	id, err := cs.AddChannel(c)
+	if err != nil {
+		return "", err
+	}
+	c.ID = id
+	t.c = c
+
+	t.v = &models.Video{
+		ChannelID:  c.ID,
+		URL:        mediaURL,
+		VideoDir:   c.VideoDir,
+		JSONDir:    c.JSONDir,
+		Channel:    c,
+		Settings:   c.Settings,
+		MetarrArgs: c.MetarrArgs,
+	}
+	return fmt.Sprintf("channel %q (ID %d) in %s", c.Name, c.ID, t.dir), nil
+}
+
+// fetchMetadata downloads the video's metadata and stores the video, as a crawl does.
+func (t *selfTest) fetchMetadata(ctx context.Context) (string, error) {
+	tracker := downloads.NewDownloadTracker(t.s.DownloadStore(), "")
+	tracker.Start(ctx)
+	defer tracker.Stop()
+
+	if err := processJSON(ctx, t.v, t.s.VideoStore(), t.s.ChannelStore(), t.s.SkipStore(), nil, tracker); err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(t.v.JSONPath); err != nil {
+		return "", fmt.Errorf("metadata file missing: %w", err)
+	}
+	return fmt.Sprintf("%q, %s", t.v.Title, t.v.JSONPath), nil
+}
+
+// download downloads the video file.
+func (t *selfTest) download(ctx context.Context) (string, error) {
+	tracker := downloads.NewDownloadTracker(t.s.DownloadStore(), "")
+	tracker.Start(ctx)
+	defer tracker.Stop()
+
+	if err := processVideo(ctx, t.v, t.s.VideoStore(), tracker, 0); err != nil {
+		return "", err
+	}
+	info, err := os.Stat(t.v.VideoPath)
+	if err != nil {
+		return "", fmt.Errorf("video file missing: %w", err)
+	}
+	return fmt.Sprintf("%d bytes, %s", info.Size(), t.v.VideoPath), nil
+}
+
+// runMetarr hands the download to Metarr, if installed.
+func (t *selfTest) runMetarr(ctx context.Context) (string, error) {
+	if !t.useMetar {
+		return "metarr not installed", errStageSkipped
+	}
+	if err := metarr.InitMetarr(t.v, ctx); err != nil {
+		return "", err
+	}
+	return t.v.VideoPath, nil
+}
+
+// checkMoved moves the files without Metarr if it didn't run, then checks they are in the output directory.
+func (t *selfTest) checkMoved(context.Context) (string, error) {
+	if !t.useMetar {
+		if err := metarr.MoveWithoutMetarr(t.v); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := os.Stat(t.v.VideoPath); err != nil {
+		return "", fmt.Errorf("video file missing after move: %w", err)
+	}
+	if !strings.HasPrefix(t.v.VideoPath, t.c.MetarrArgs.OutputDir) {
+		return "", fmt.Errorf("video %q is not in the output directory %q", t.v.VideoPath, t.c.MetarrArgs.OutputDir)
+	}
+	return t.v.VideoPath, nil
+}
+
+// renderNotifications renders webhook payloads for the test video without sending anything.
+//
+// The default payload is always rendered, plus the notification channel's webhooks if one is set.
+func (t *selfTest) renderNotifications(context.Context) (string, error) {
+	payload := webhook.FromVideo(t.c, t.v)
+	if _, err := webhook.Render("", payload); err != nil {
+		return "", fmt.Errorf("default webhook payload: %w", err)
+	}
+
+	name := cfg.GetString(keys.SelfTestNotify)
+	if name == "" {
+		return "default webhook payload rendered, nothing sent", nil
+	}
+
+	cs := t.s.ChannelStore()
+	id, err := cs.GetID(consts.QChanName, name)
+	if err != nil {
+		return "", fmt.Errorf("notification channel %q: %w", name, err)
+	}
+	notifyURLs, err := cs.GetNotifyURLs(id)
+	if err != nil {
+		return "", err
+	}
+	hooks, err := cs.GetWebhooks(id)
+	if err != nil {
+		return "", err
+	}
+
+	for _, h := range hooks {
+		body, err := webhook.Render(h.Payload, payload)
+		if err != nil {
+			return "", fmt.Errorf("webhook %q: %w", h.Name, err)
+		}
+		logging.I("Would send webhook %q: %s %s\n%s", h.Name, strings.ToUpper(h.Method), h.URL, body)
+	}
+	for _, u := range notifyURLs {
+		logging.I("Would call notification URL: %s", u)
+	}
+	return fmt.Sprintf("%d notification URL(s) and %d webhook(s) for channel %q rendered, nothing sent", len(notifyURLs), len(hooks), name), nil
+}
+
+// removeChannel deletes the temporary channel and its stored video, if present.
+func (t *selfTest) removeChannel() {
+	cs := t.s.ChannelStore()
+	if _, err := cs.GetID(consts.QChanName, consts.SelfTestChannel); err != nil {
+		return
+	}
+	if err := cs.DeleteChannel(consts.QChanName, consts.SelfTestChannel); err != nil {
+		logging.E(0, "Failed to remove self-test channel: %v", err)
+	}
+}
+
+// report prints the result of each stage run, and any stages not reached.
+func (t *selfTest) report(total int) {
+	fmt.Printf("\n%sSelf-test results%s\n", consts.ColorGreen, consts.ColorReset)
+	for _, st := range t.stages {
+		var status string
+		switch {
+		case st.err == nil:
+			status = consts.ColorGreen + "PASS" + consts.ColorReset
+		case errors.Is(st.err, errStageSkipped):
+			status = consts.ColorYellow + "SKIP" + consts.ColorReset
+		default:
+			status = consts.ColorRed + "FAIL" + consts.ColorReset
+			st.detail = st.err.Error()
+		}
+		fmt.Printf("  %-9s %s  %-7s %s\n", st.name, status, st.elapsed.Round(time.Millisecond), st.detail)
+	}
+	if n := total - len(t.stages); n > 0 {
+		fmt.Printf("  %d later stage(s) not run\n", n)
+	}
+	fmt.Println()
+}
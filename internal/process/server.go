@@ -0,0 +1,145 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/socket"
+)
+
+const serverShutdown = 5 * time.Second
+
+// Serve serves the HTTP API until the context is cancelled, without crawling channels.
+//
+// With an idle exit set, it also stops once no requests or background work have been seen for that long.
+func Serve(s interfaces.Store, ctx context.Context) error {
+	addr := HTTPAddr()
+	if addr == "" {
+		return errors.New("no address to serve on, set --http-addr or start Tubarr by systemd socket activation")
+	}
+	if cfg.GetBool(keys.ReadOnly) {
+		logging.I("Read-only mode, requests which modify the database are refused")
+	}
+	if idle := cfg.GetDuration(keys.IdleExit); idle > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		go exitWhenIdle(idle, cancel, ctx)
+	}
+	return serveHTTP(s, addr, ctx)
+}
+
+// HTTPAddr returns the address the HTTP API is served on, blank if it isn't served.
+//
+// This is --http-addr if set, or else the address of the socket passed by systemd socket activation.
+func HTTPAddr() string {
+	if addr := cfg.GetString(keys.HTTPAddr); addr != "" {
+		return addr
+	}
+	ln, err := socket.Inherited()
+	if err != nil || ln == nil {
+		return ""
+	}
+	return ln.Addr().String()
+}
+
+// listen returns the socket passed by systemd socket activation if there is one, or else listens on addr.
+func listen(addr string) (net.Listener, error) {
+	ln, err := socket.Inherited()
+	if err != nil {
+		return nil, fmt.Errorf("failed to use socket passed by systemd: %w", err)
+	}
+	if ln != nil {
+		return ln, nil
+	}
+	return net.Listen("tcp", addr)
+}
+
+// serveHTTP serves the health checks, downloaded videos, and login challenge page until the context is cancelled.
+//
+// Everything but the health checks and login needs a logged-in user once users are added. Requests which
+// start work are refused once a graceful shutdown begins, and requests which modify the database are
+// refused in read-only mode.
+func serveHTTP(s interfaces.Store, addr string, ctx context.Context) error {
+	readOnly := cfg.GetBool(keys.ReadOnly)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", healthzHandler(s))
+	mux.HandleFunc("GET /readyz", readyzHandler(s))
+	us := s.UserStore()
+	if readOnly {
+		us = newMemorySessions(us)
+	}
+	mux.HandleFunc("POST /api/login", loginHandler(us))
+	mux.HandleFunc("POST /api/logout", logoutHandler(us))
+	mux.HandleFunc("GET /api/channels", requireUser(us, channelsHandler(us)))
+	mux.HandleFunc("POST /api/channels/{id}/crawl", requireChannelAccess(us, crawlHandler(s, ctx)))
+	mux.HandleFunc("POST /api/channels/{id}/urls/{urlID}/crawl", requireChannelAccess(us, crawlURLHandler(s, ctx)))
+	mux.HandleFunc("POST /api/channels/{id}/reprocess", requireChannelAccess(us, reprocessHandler(s, ctx)))
+	mux.HandleFunc("POST /api/channels/{id}/videos/bulk", requireChannelAccess(us, bulkVideosHandler(s)))
+	mux.HandleFunc("POST /api/channels/{id}/files/{kind}", requireChannelAccess(us, uploadFileHandler(s.ChannelStore())))
+	mux.HandleFunc("GET /api/channels/{id}/activity", requireChannelAccess(us, activityHandler(s.ChannelStore())))
+	mux.HandleFunc("GET /api/channels/{id}/skipped", requireChannelAccess(us, skippedHandler(s.SkipStore())))
+	mux.HandleFunc("GET /api/channels/{id}/feed.xml", requireChannelAccess(us, feedHandler(s)))
+	mux.HandleFunc("DELETE /api/channels/{id}", requireAdmin(us, deleteChannelHandler(s.ChannelStore(), s.ConfirmStore())))
+	mux.HandleFunc("GET /api/videos", requireUser(us, searchVideosHandler(s)))
+	mux.HandleFunc("GET /api/videos/{id}/stream", requireVideoAccess(us, streamHandler(s.VideoStore())))
+	mux.HandleFunc("GET /api/videos/{id}/thumbnail", requireVideoAccess(us, thumbnailHandler(s.VideoStore())))
+	mux.HandleFunc("POST /api/videos/{id}/redownload", requireVideoAccess(us, redownloadHandler(s, ctx)))
+	mux.HandleFunc("POST /api/videos/{id}/cancel", requireVideoAccess(us, cancelVideoHandler(s.DownloadStore())))
+	mux.HandleFunc("PATCH /api/videos/{id}/metadata", requireVideoAccess(us, editMetadataHandler(s.VideoStore(), s.ChannelStore())))
+	mux.HandleFunc("DELETE /api/videos/{id}", requireAdmin(us, deleteVideoHandler(s.VideoStore(), s.ConfirmStore())))
+	mux.HandleFunc("GET /api/stats", requireUser(us, statsHandler(s)))
+	mux.HandleFunc("GET /api/storage", requireUser(us, storageHandler(s)))
+	mux.HandleFunc("GET /api/hosts", requireUser(us, hostsHandler(s.HostStore())))
+	mux.HandleFunc("GET /api/downloads/events", requireUser(us, progressHandler(us, ctx)))
+	mux.HandleFunc("GET /challenges", requireUser(us, challengesHandler(us)))
+	mux.HandleFunc("POST /challenges/{id}", requireChannelAccess(us, resolveChallengeHandler(s, ctx)))
+
+	handler := trackRequests(refuseWhileDraining(mux))
+	if readOnly {
+		handler = refuseWrites(handler)
+	}
+
+	ln, err := listen(addr)
+	if err != nil {
+		return fmt.Errorf("HTTP endpoints failed to start: %w", err)
+	}
+
+	srv := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: healthTimeout}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdown)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	logging.I("Serving HTTP endpoints on %s", ln.Addr())
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP endpoints stopped: %w", err)
+	}
+	return nil
+}
+
+// refuseWrites rejects requests which modify the database, besides logging in and out.
+func refuseWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if r.URL.Path != "/api/login" && r.URL.Path != "/api/logout" {
+				http.Error(w, "this Tubarr instance is read-only", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,119 @@
+package process
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tubarr/internal/utils/logging"
+)
+
+// idleCheckEvery caps how often the idle exit checks for activity.
+const idleCheckEvery = time.Minute
+
+var (
+	drainOnce sync.Once
+	drainCh   = make(chan struct{})
+
+	// Crawls and reprocessing started in the background, by HTTP requests or queued commands
+	backgroundWork sync.WaitGroup
+	backgroundJobs atomic.Int64
+
+	// HTTP requests in progress, and when the last one finished (Unix nanoseconds)
+	activeRequests atomic.Int64
+	lastRequest    atomic.Int64
+)
+
+// BeginDrain starts a graceful shutdown: work in progress continues, but no new crawls, downloads, or
+// HTTP requests for work are started. Channels left uncrawled stay due, and queued commands stay queued.
+func BeginDrain() {
+	drainOnce.Do(func() {
+		close(drainCh)
+	})
+}
+
+// draining reports whether a graceful shutdown has begun.
+func draining() bool {
+	select {
+	case <-drainCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// goBackground runs fn in the background, waited on by a graceful shutdown.
+func goBackground(fn func()) {
+	backgroundWork.Add(1)
+	backgroundJobs.Add(1)
+	go func() {
+		defer backgroundWork.Done()
+		defer backgroundJobs.Add(-1)
+		fn()
+	}()
+}
+
+// waitBackground waits for background work to finish, or for the context to be cancelled.
+func waitBackground(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		backgroundWork.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// refuseWhileDraining answers requests which start work with 503 once a graceful shutdown has begun.
+func refuseWhileDraining(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if draining() && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			logging.D(1, "Refusing %s %s, Tubarr is shutting down", r.Method, r.URL.Path)
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, "Tubarr is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// trackRequests records HTTP requests in progress and when the last one finished, for the idle exit.
+func trackRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		activeRequests.Add(1)
+		defer func() {
+			lastRequest.Store(time.Now().UnixNano())
+			activeRequests.Add(-1)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// exitWhenIdle calls cancel once there have been no HTTP requests or background work for the idle duration.
+func exitWhenIdle(idle time.Duration, cancel context.CancelFunc, ctx context.Context) {
+	lastRequest.Store(time.Now().UnixNano())
+	ticker := time.NewTicker(min(idle/4+time.Second, idleCheckEvery))
+	defer ticker.Stop()
+
+	logging.I("Exiting after %s without requests or background work", idle)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if activeRequests.Load() > 0 || backgroundJobs.Load() > 0 {
+				continue
+			}
+			if time.Since(time.Unix(0, lastRequest.Load())) >= idle {
+				logging.I("No requests or background work for %s, exiting", idle)
+				cancel()
+				return
+			}
+		}
+	}
+}
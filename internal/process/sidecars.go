@@ -0,0 +1,112 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/nfo"
+	"tubarr/internal/utils/logging"
+)
+
+// sidecarExts are the extensions of each sidecar file, replacing the video's extension.
+var sidecarExts = map[string]string{
+	consts.SidecarNFO:         ".nfo",
+	consts.SidecarChapters:    ".chapters.txt",
+	consts.SidecarDescription: ".description",
+}
+
+// SidecarPaths returns the paths of every kind of sidecar file for a video file, whether written or not.
+func SidecarPaths(videoPath string) []string {
+	if videoPath == "" {
+		return nil
+	}
+	base := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	paths := make([]string, 0, len(sidecarExts))
+	for _, kind := range consts.SidecarKinds {
+		paths = append(paths, base+sidecarExts[kind])
+	}
+	return paths
+}
+
+// writeSidecars writes the channel's chosen sidecar files next to the downloaded video, from its metadata JSON.
+//
+// Failures are logged rather than failing the download.
+func writeSidecars(v *models.Video) {
+	if len(v.Settings.Sidecars) == 0 || v.VideoPath == "" {
+		return
+	}
+	if v.JSONPath == "" {
+		logging.W("No metadata JSON for %q, skipping sidecar files", v.URL)
+		return
+	}
+
+	m, err := nfo.ReadMetadata(v.JSONPath)
+	if err != nil {
+		logging.E(0, "Failed to read metadata for sidecar files of %q: %v", v.URL, err)
+		return
+	}
+
+	base := strings.TrimSuffix(v.VideoPath, filepath.Ext(v.VideoPath))
+	for _, kind := range v.Settings.Sidecars {
+		ext, ok := sidecarExts[kind]
+		if !ok {
+			logging.E(0, "Unknown sidecar file type %q, skipping", kind)
+			continue
+		}
+
+		path := base + ext
+		var content []byte
+		switch kind {
+		case consts.SidecarNFO:
+			if v.Settings.NFO {
+				logging.D(1, "Episode NFOs enabled for %q, skipping the movie NFO sidecar", v.URL)
+				continue
+			}
+			e := m.Episode("")
+			e.MapFields(m, v.Settings.NFOFieldMap)
+			if err := nfo.WriteMovie(path, e); err != nil {
+				logging.E(0, "Failed to write NFO for %q: %v", v.URL, err)
+			} else {
+				logging.D(1, "Wrote sidecar file %q", path)
+			}
+			continue
+		case consts.SidecarChapters:
+			if len(m.Chapters) == 0 {
+				logging.D(1, "No chapters in metadata for %q", v.URL)
+				continue
+			}
+			content = buildChapters(m)
+		case consts.SidecarDescription:
+			if m.Description == "" {
+				logging.D(1, "No description in metadata for %q", v.URL)
+				continue
+			}
+			content = []byte(m.Description + "\n")
+		}
+
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			logging.E(0, "Failed to write sidecar file %q: %v", path, err)
+			continue
+		}
+		logging.D(1, "Wrote sidecar file %q", path)
+	}
+}
+
+// buildChapters returns the video's chapters in the OGM chapter format read by mkvmerge and media servers.
+func buildChapters(m *nfo.Metadata) []byte {
+	var b strings.Builder
+	for i, ch := range m.Chapters {
+		start := time.Duration(ch.StartTime * float64(time.Second))
+		h, rem := start/time.Hour, start%time.Hour
+		mins, rem := rem/time.Minute, rem%time.Minute
+		sec, ms := rem/time.Second, (rem%time.Second)/time.Millisecond
+		fmt.Fprintf(&b, "CHAPTER%02d=%02d:%02d:%02d.%03d\n", i+1, h, mins, sec, ms)
+		fmt.Fprintf(&b, "CHAPTER%02dNAME=%s\n", i+1, ch.Title)
+	}
+	return []byte(b.String())
+}
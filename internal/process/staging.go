@@ -0,0 +1,80 @@
+package process
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tubarr/internal/metarr"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+// stageVideo points the video at its own directory under the channel's staging directory, returning the directory.
+//
+// The directory is named from the video URL, so a retried download resumes from the partial files of the last attempt.
+// Returns an empty string if the channel has no staging directory.
+func stageVideo(dirParser *parsing.Directory, c *models.Channel, v *models.Video) (string, error) {
+	if c.Settings.StagingDir == "" {
+		return "", nil
+	}
+
+	root, err := dirParser.ParseDirectory(c.Settings.StagingDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse staging directory %q: %w", c.Settings.StagingDir, err)
+	}
+
+	sum := sha256.Sum256([]byte(v.URL))
+	stageDir := filepath.Join(root, hex.EncodeToString(sum[:8]))
+	if err := os.MkdirAll(stageDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory %q: %w", stageDir, err)
+	}
+
+	v.VideoDir = stageDir
+	return stageDir, nil
+}
+
+// unstage moves the finished files in the staging directory into the final directory, then removes the staging directory.
+//
+// Partial download files are left behind.
+func unstage(v *models.Video, stageDir, finalDir string) error {
+	if stageDir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(stageDir)
+	if err != nil {
+		return fmt.Errorf("failed to read staging directory %q: %w", stageDir, err)
+	}
+	if err := os.MkdirAll(finalDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create video directory %q: %w", finalDir, err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".part") || strings.HasSuffix(e.Name(), ".ytdl") {
+			continue
+		}
+
+		src := filepath.Join(stageDir, e.Name())
+		dst, err := metarr.MoveFile(src, finalDir)
+		if err != nil {
+			return err
+		}
+		if src == v.VideoPath {
+			v.VideoPath = dst
+		}
+	}
+	if v.VideoDir == stageDir {
+		v.VideoDir = finalDir
+	}
+
+	if err := os.Remove(stageDir); err != nil {
+		logging.D(1, "Staging directory %q not removed: %v", stageDir, err)
+	}
+	logging.D(1, "Moved staged files for %q to %q", v.URL, finalDir)
+	return nil
+}
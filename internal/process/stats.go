@@ -0,0 +1,112 @@
+package process
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// recordDownloadStats adds a video job's result to its channel's daily download statistics.
+//
+// Successful downloads count the size of the downloaded video and the time since its metadata download started.
+func recordDownloadStats(ts interfaces.StatsStore, v *models.Video, started time.Time, jobErr error) {
+	var bytes int64
+	if jobErr == nil && v.VideoPath != "" {
+		if info, err := os.Stat(v.VideoPath); err == nil {
+			bytes = info.Size()
+		}
+	}
+
+	now := time.Now()
+	if err := ts.RecordDownload(v.ChannelID, now, jobErr == nil, bytes, now.Sub(started)); err != nil {
+		logging.E(0, "%v", err)
+	}
+}
+
+// statsOutput is a channel's download statistics as served over HTTP.
+type statsOutput struct {
+	ChannelID   int64                     `json:"channel_id"`
+	ChannelName string                    `json:"channel_name"`
+	Day         string                    `json:"day,omitempty"`
+	Downloads   int                       `json:"downloads"`
+	Failures    int                       `json:"failures"`
+	Bytes       int64                     `json:"bytes"`
+	AvgSeconds  float64                   `json:"average_seconds"`
+	FailReasons map[consts.FailReason]int `json:"fail_reasons,omitempty"`
+}
+
+// statsHandler serves download statistics for the channels the user can see, like 'tubarr stats'.
+//
+// The days (default 30), daily, and channel_id query parameters match the command's flags.
+func statsHandler(s interfaces.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		days := 30
+		if v := q.Get("days"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				http.Error(w, "days must be at least 1", http.StatusBadRequest)
+				return
+			}
+			days = n
+		}
+		daily := q.Get("daily") == "true" || q.Get("daily") == "1"
+
+		var chanID int64
+		if v := q.Get("channel_id"); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid channel ID", http.StatusBadRequest)
+				return
+			}
+			chanID = id
+		}
+
+		visible, err := visibleChannels(s.UserStore(), r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if chanID != 0 && visible != nil && !visible[chanID] {
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		ts := s.StatsStore()
+		since := time.Now().AddDate(0, 0, -(days - 1))
+		var stats []*models.DownloadStats
+		if daily {
+			stats, err = ts.FetchDailyStats(chanID, since)
+		} else {
+			stats, err = ts.FetchStatsTotals(chanID, since)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		out := make([]statsOutput, 0, len(stats))
+		for _, st := range stats {
+			if visible != nil && !visible[st.ChannelID] {
+				continue
+			}
+			out = append(out, statsOutput{
+				ChannelID:   st.ChannelID,
+				ChannelName: st.ChannelName,
+				Day:         st.Day,
+				Downloads:   st.Downloads,
+				Failures:    st.Failures,
+				Bytes:       st.Bytes,
+				AvgSeconds:  st.AvgDuration().Seconds(),
+				FailReasons: st.FailReasons,
+			})
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
@@ -0,0 +1,92 @@
+package process
+
+import (
+	"net/http"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"tubarr/internal/interfaces"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/disk"
+)
+
+// storageOutput is cached disk usage as served over HTTP.
+type storageOutput struct {
+	Roots    []rootStorage    `json:"roots"`
+	Channels []channelStorage `json:"channels"`
+}
+
+// rootStorage is the disk usage under a root video directory, with its filesystem's free space.
+type rootStorage struct {
+	Path       string `json:"path"`
+	UsedBytes  int64  `json:"used_bytes"`
+	FreeBytes  uint64 `json:"free_bytes,omitempty"`
+	TotalBytes uint64 `json:"total_bytes,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// channelStorage is a channel's cached disk usage, without sizes if not yet calculated.
+type channelStorage struct {
+	ChannelID  int64      `json:"channel_id"`
+	Name       string     `json:"name"`
+	VideoBytes int64      `json:"video_bytes"`
+	JSONBytes  int64      `json:"json_bytes"`
+	Files      int        `json:"files"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// storageHandler serves cached disk usage per root directory and per channel the user can see, like 'tubarr storage'.
+//
+// Usage is read from the cache kept up to date after downloads and deletes, nothing is walked on request.
+func storageHandler(s interfaces.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		visible, err := visibleChannels(s.UserStore(), r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		chans, err, _ := s.ChannelStore().FetchAllChannels()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		usages, err := s.StorageStore().FetchAllChannelStorage()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		out := storageOutput{Roots: []rootStorage{}, Channels: []channelStorage{}}
+		roots := make(map[string]int64)
+		for _, c := range chans {
+			if visible != nil && !visible[c.ID] {
+				continue
+			}
+			cs := channelStorage{ChannelID: c.ID, Name: c.Name}
+			for _, u := range usages {
+				if u.ChannelID == c.ID {
+					updated := u.UpdatedAt
+					cs.VideoBytes, cs.JSONBytes, cs.Files, cs.UpdatedAt = u.VideoBytes, u.JSONBytes, u.Files, &updated
+					break
+				}
+			}
+			out.Channels = append(out.Channels, cs)
+
+			root := filepath.Clean(parsing.StaticPrefix(c.VideoDir))
+			roots[root] += cs.VideoBytes + cs.JSONBytes
+		}
+
+		for root, used := range roots {
+			rs := rootStorage{Path: root, UsedBytes: used}
+			if space, err := disk.FreeSpace(root); err != nil {
+				rs.Error = err.Error()
+			} else {
+				rs.FreeBytes, rs.TotalBytes = space.Free, space.Total
+			}
+			out.Roots = append(out.Roots, rs)
+		}
+		sort.Slice(out.Roots, func(i, j int) bool { return out.Roots[i].Path < out.Roots[j].Path })
+		writeJSON(w, http.StatusOK, out)
+	}
+}
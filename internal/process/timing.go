@@ -0,0 +1,69 @@
+package process
+
+import (
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// stageMark is a pipeline stage reached at a point in time.
+type stageMark struct {
+	stage consts.PipelineStage
+	at    time.Time
+}
+
+// stageTimer records a video's pipeline stages.
+//
+// Stages reached before the video has a database ID are held until it gets one.
+type stageTimer struct {
+	vs      interfaces.VideoStore
+	v       *models.Video
+	pending []stageMark
+}
+
+// newStageTimer returns a stage timer for a video queued at the given time.
+func newStageTimer(vs interfaces.VideoStore, v *models.Video, queuedAt time.Time) *stageTimer {
+	t := &stageTimer{vs: vs, v: v}
+	t.markAt(consts.StageQueued, queuedAt)
+	return t
+}
+
+// mark records the video reaching a stage now.
+func (t *stageTimer) mark(stage consts.PipelineStage) {
+	t.markAt(stage, time.Now())
+}
+
+// markAt records the video reaching a stage at the given time.
+func (t *stageTimer) markAt(stage consts.PipelineStage, at time.Time) {
+	t.pending = append(t.pending, stageMark{stage: stage, at: at})
+	t.flush()
+}
+
+// flush writes held stages once the video has a database ID.
+func (t *stageTimer) flush() {
+	if t.v.ID == 0 {
+		return
+	}
+	for _, m := range t.pending {
+		if err := t.vs.RecordStage(t.v.ID, m.stage, m.at); err != nil {
+			logging.E(0, "Failed to record %s stage for video %q: %v", m.stage, t.v.URL, err)
+		}
+	}
+	t.pending = t.pending[:0]
+}
+
+// markNotified records the notified stage for the videos downloaded in a crawl.
+func markNotified(vs interfaces.VideoStore, videos []*models.Video) {
+	now := time.Now()
+	for _, v := range videos {
+		if v.ID == 0 || v.DownloadStatus.Status != consts.DLStatusCompleted {
+			continue
+		}
+		if err := vs.RecordStage(v.ID, consts.StageNotified, now); err != nil {
+			logging.E(0, "Failed to record %s stage for video %q: %v", consts.StageNotified, v.URL, err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/whisper"
+)
+
+// transcribeVideo generates subtitles for a downloaded video with whisper.cpp, if the channel enables it.
+//
+// Videos with stored subtitles which still exist are skipped. Failures are logged rather than failing the download.
+func transcribeVideo(vs interfaces.VideoStore, v *models.Video, ctx context.Context) {
+	if !v.Settings.Transcribe || v.VideoPath == "" {
+		return
+	}
+	if v.Settings.TranscribeModel == "" {
+		logging.W("No whisper.cpp model set for %q, skipping transcription (set --%s)", v.URL, keys.TranscribeModel)
+		return
+	}
+
+	existing, err := vs.GetTranscriptPath(v.ID)
+	if err != nil {
+		logging.E(0, "Failed to check transcript of %q: %v", v.URL, err)
+		return
+	}
+	if existing != "" {
+		if _, err := os.Stat(existing); err == nil {
+			logging.D(1, "Video %q already transcribed to %q, skipping", v.URL, existing)
+			return
+		}
+	}
+
+	logging.I("Transcribing %q with whisper.cpp...", v.VideoPath)
+	srt, err := whisper.Transcribe(ctx, cfg.GetString(keys.WhisperPath), v.Settings.TranscribeModel, v.Settings.TranscribeLanguage, v.VideoPath)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			logging.E(0, "Failed to transcribe %q: %v", v.URL, err)
+		}
+		return
+	}
+	if err := vs.SetTranscriptPath(v.ID, srt); err != nil {
+		logging.E(0, "Failed to store transcript of %q: %v", v.URL, err)
+		return
+	}
+	logging.S(0, "Wrote subtitles %q", srt)
+}
@@ -0,0 +1,222 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cfgvalidate "tubarr/internal/cfg/validation"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/setup"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/browser"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	maxUploadBytes = 1 << 20
+	uploadsDir     = "uploads"
+)
+
+// Upload kinds, the file types which can be uploaded for a channel.
+const (
+	uploadCookies = "cookies"
+	uploadFilters = "filters"
+	uploadMetaOps = "meta-ops"
+)
+
+// uploadFileHandler accepts a cookies, filters, or meta ops file for the channel, sent as the multipart "file" field.
+//
+// The file is validated before anything is replaced, so an invalid upload leaves the channel's current file and settings
+// in place. Cookies are saved to the channel's cookie file. Filters and meta ops, one per line with '#' comments, replace
+// the channel's settings and are kept under the config directory.
+func uploadFileHandler(cs interfaces.ChannelStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid channel ID", http.StatusBadRequest)
+			return
+		}
+		kind := r.PathValue("kind")
+		switch kind {
+		case uploadCookies, uploadFilters, uploadMetaOps:
+		default:
+			http.Error(w, fmt.Sprintf("unknown upload %q, expected %s, %s, or %s", kind, uploadCookies, uploadFilters, uploadMetaOps), http.StatusNotFound)
+			return
+		}
+
+		data, err := readUpload(w, r)
+		if err != nil {
+			status := http.StatusBadRequest
+			if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		c, err, hasRows := cs.FetchChannel(id)
+		switch {
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		case !hasRows:
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		var count int
+		switch kind {
+		case uploadCookies:
+			if count, err = browser.SetAuthCookies(c, string(data)); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+		case uploadFilters:
+			lines, err := uploadLines(data)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			filters := make([]models.DLFilters, 0, len(lines))
+			for _, l := range lines {
+				f, err := parsing.ParseFilter(l.op)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("line %d: %v", l.n, err), http.StatusBadRequest)
+					return
+				}
+				filters = append(filters, f)
+			}
+			if _, err := cs.UpdateChannelSettingsJSON(consts.QChanID, strconv.FormatInt(id, 10), func(s *models.ChannelSettings) error {
+				s.Filters = filters
+				return nil
+			}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			count = len(filters)
+
+		case uploadMetaOps:
+			lines, err := uploadLines(data)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ops := make([]string, 0, len(lines))
+			for _, l := range lines {
+				if _, err := parsing.ParseMetaOp(l.op); err != nil {
+					http.Error(w, fmt.Sprintf("line %d: %v", l.n, err), http.StatusBadRequest)
+					return
+				}
+				ops = append(ops, l.op)
+			}
+			if ops, err = cfgvalidate.ValidateMetaOps(ops); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if _, err := cs.UpdateChannelMetarrArgsJSON(consts.QChanID, strconv.FormatInt(id, 10), func(m *models.MetarrArgs) error {
+				m.MetaOps = ops
+				return nil
+			}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			count = len(ops)
+		}
+
+		if kind != uploadCookies {
+			if err := saveUpload(uploadPath(id, kind), data); err != nil {
+				logging.E(0, "Failed to keep uploaded %s file for channel %q: %v", kind, c.Name, err)
+			}
+		}
+
+		logging.I("HTTP user %q uploaded %d %s for channel %q", requestUser(r).Username, count, kind, c.Name)
+		writeJSON(w, http.StatusOK, map[string]any{"channel_id": id, "kind": kind, "count": count})
+	}
+}
+
+// readUpload reads the request's multipart "file" field, refusing files over maxUploadBytes.
+func readUpload(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	if err := r.ParseMultipartForm(maxUploadBytes); err != nil {
+		return nil, err
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	f, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("missing upload, send the file as the multipart \"file\" field: %w", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.New("uploaded file is empty")
+	}
+	return data, nil
+}
+
+// uploadLine is an operation from an uploaded file, with its line number.
+type uploadLine struct {
+	n  int
+	op string
+}
+
+// uploadLines returns the operations in an uploaded file, one per line, skipping blank lines and '#' comments.
+func uploadLines(data []byte) ([]uploadLine, error) {
+	var lines []uploadLine
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, uploadLine{n: n, op: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("uploaded file holds no operations")
+	}
+	return lines, nil
+}
+
+// uploadPath returns where the channel's uploaded file of the given kind is kept.
+func uploadPath(chanID int64, kind string) string {
+	return filepath.Join(setup.CfgDir, uploadsDir, strconv.FormatInt(chanID, 10), kind+".txt")
+}
+
+// saveUpload writes the file beside the previous upload and renames it over, so the previous upload is never truncated.
+func saveUpload(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,412 @@
+package process
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/errconsts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	sessionCookie = "tubarr_session"
+	sessionTTL    = 7 * 24 * time.Hour
+	maxLoginInput = 1 << 12
+	confirmTTL    = 5 * time.Minute
+)
+
+// openUser is who requests from this machine act as before any users are added, so existing local setups
+// keep working. Other clients are refused until a user is added.
+var openUser = &models.User{Username: "", Role: consts.RoleOpen}
+
+type userCtxKey struct{}
+
+// requestUser returns the user a request was authenticated as.
+func requestUser(r *http.Request) *models.User {
+	u, _ := r.Context().Value(userCtxKey{}).(*models.User)
+	return u
+}
+
+// requireUser only lets logged-in users through, by session cookie or bearer token.
+//
+// Until the first user is added only loopback clients are let through, seeing every channel but unable
+// to delete. A reverse proxy on the same machine counts as loopback, so add a user before exposing one.
+func requireUser(us interfaces.UserStore, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hasUsers, err := us.HasUsers()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		u := openUser
+		if !hasUsers && !loopbackClient(r) {
+			http.Error(w, "add a user with 'tubarr user add' to use the API from other machines", http.StatusForbidden)
+			return
+		}
+		if hasUsers {
+			if u, err = us.SessionUser(sessionToken(r)); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if u == nil {
+				http.Error(w, "login required", http.StatusUnauthorized)
+				return
+			}
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), userCtxKey{}, u)))
+	}
+}
+
+// requireAdmin only lets logged-in admins through, refusing everyone until an admin is added.
+func requireAdmin(us interfaces.UserStore, next http.HandlerFunc) http.HandlerFunc {
+	return requireUser(us, func(w http.ResponseWriter, r *http.Request) {
+		if requestUser(r).Role == consts.RoleOpen {
+			http.Error(w, "add an admin with 'tubarr user add --role admin' and log in to do this", http.StatusForbidden)
+			return
+		}
+		if !requestUser(r).IsAdmin() {
+			http.Error(w, "only admins can do this", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	})
+}
+
+// requireChannelAccess only lets through users who can see the channel in the request path.
+func requireChannelAccess(us interfaces.UserStore, next http.HandlerFunc) http.HandlerFunc {
+	return requireUser(us, func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid channel ID", http.StatusBadRequest)
+			return
+		}
+		if ok, err := us.CanAccessChannel(requestUser(r), id); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		} else if !ok {
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+		next(w, r)
+	})
+}
+
+// requireVideoAccess only lets through users who can see the channel of the video in the request path.
+func requireVideoAccess(us interfaces.UserStore, next http.HandlerFunc) http.HandlerFunc {
+	return requireUser(us, func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid video ID", http.StatusBadRequest)
+			return
+		}
+		if ok, err := us.CanAccessVideo(requestUser(r), id); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		} else if !ok {
+			http.Error(w, "video not found", http.StatusNotFound)
+			return
+		}
+		next(w, r)
+	})
+}
+
+// loopbackClient reports whether the request came from this machine.
+func loopbackClient(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// sessionToken returns the session token from the request's cookie or Authorization header.
+func sessionToken(r *http.Request) string {
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(auth)
+	}
+	if ck, err := r.Cookie(sessionCookie); err == nil {
+		return ck.Value
+	}
+	return ""
+}
+
+// loginHandler checks a username and password, sent as a form or JSON, and starts a session.
+//
+// The token is set as a cookie and also returned, for clients using bearer tokens.
+func loginHandler(us interfaces.UserStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxLoginInput)
+
+		var creds struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			if err := r.ParseForm(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			creds.Username, creds.Password = r.PostFormValue("username"), r.PostFormValue("password")
+		}
+
+		u, err := us.Authenticate(creds.Username, creds.Password)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if u == nil {
+			logging.W("Failed HTTP login for username %q from %s", creds.Username, r.RemoteAddr)
+			http.Error(w, "invalid username or password", http.StatusUnauthorized)
+			return
+		}
+
+		token, err := us.CreateSession(u.ID, sessionTTL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCookie,
+			Value:    token,
+			Path:     "/",
+			MaxAge:   int(sessionTTL.Seconds()),
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+		writeJSON(w, http.StatusOK, map[string]any{"token": token, "user": u})
+	}
+}
+
+// logoutHandler ends the request's session.
+func logoutHandler(us interfaces.UserStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token := sessionToken(r); token != "" {
+			if err := us.DeleteSession(token); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "", Path: "/", MaxAge: -1, HttpOnly: true})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// channelsHandler lists the channels the user can see.
+func channelsHandler(us interfaces.UserStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		chans, err := us.FetchVisibleChannels(requestUser(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if chans == nil {
+			chans = []*models.OwnedChannel{}
+		}
+		writeJSON(w, http.StatusOK, chans)
+	}
+}
+
+// visibleChannels returns the IDs of the channels the request's user can see, or nil if they see every channel.
+func visibleChannels(us interfaces.UserStore, r *http.Request) (map[int64]bool, error) {
+	u := requestUser(r)
+	if u.SeesAll() {
+		return nil, nil
+	}
+	chans, err := us.FetchVisibleChannels(u)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[int64]bool, len(chans))
+	for _, c := range chans {
+		ids[c.ID] = true
+	}
+	return ids, nil
+}
+
+// confirmRequest makes destructive requests take two calls, like their CLI commands.
+//
+// Without a "confirm" query parameter, it responds with a token for the operation and returns false.
+// The caller sends the same request again with "?confirm=<token>" within a few minutes to proceed.
+func confirmRequest(fs interfaces.ConfirmStore, w http.ResponseWriter, r *http.Request, operation, summary string) bool {
+	if token := r.URL.Query().Get("confirm"); token != "" {
+		if _, err := fs.ConsumeConfirmation(token, operation); err != nil {
+			// The store words renewal for the CLI's --confirm flag
+			http.Error(w, strings.Replace(err.Error(), errconsts.ConfirmRenewCLI, errconsts.ConfirmRenewAPI, 1), http.StatusConflict)
+			return false
+		}
+		return true
+	}
+
+	token, err := fs.IssueConfirmation(operation, summary, confirmTTL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	writeJSON(w, http.StatusPreconditionRequired, map[string]any{
+		"summary":    "This will " + summary,
+		"confirm":    token,
+		"expires_in": int(confirmTTL.Seconds()),
+	})
+	return false
+}
+
+// deleteChannelHandler deletes the channel in the request path, once confirmed.
+func deleteChannelHandler(cs interfaces.ChannelStore, fs interfaces.ConfirmStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid channel ID", http.StatusBadRequest)
+			return
+		}
+		urls, err := cs.LoadAllVideoURLs(&models.Channel{ID: id})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		summary := fmt.Sprintf("delete channel with ID %d and its %d video records", id, len(urls))
+		if !confirmRequest(fs, w, r, fmt.Sprintf("api-channel-delete:%d", id), summary) {
+			return
+		}
+		if err := cs.DeleteChannel(consts.QChanID, strconv.FormatInt(id, 10)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		clearChallenge(id)
+
+		logging.S(0, "Deleted channel with ID %d for HTTP user %q", id, requestUser(r).Username)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// deleteVideoHandler removes the downloaded files of the video in the request path, once confirmed.
+func deleteVideoHandler(vs interfaces.VideoStore, fs interfaces.ConfirmStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid video ID", http.StatusBadRequest)
+			return
+		}
+		videoPath, jsonPath, err := vs.GetVideoPaths(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		summary := fmt.Sprintf("delete the downloaded files of video with ID %d", id)
+		if !confirmRequest(fs, w, r, fmt.Sprintf("api-video-delete:%d", id), summary) {
+			return
+		}
+		freed, err := removeDownload(vs, &models.Video{ID: id, VideoPath: videoPath, JSONPath: jsonPath})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		logging.S(0, "Deleted video with ID %d (%d bytes) for HTTP user %q", id, freed, requestUser(r).Username)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.E(0, "Failed to write HTTP response: %v", err)
+	}
+}
+
+// memorySessions keeps login sessions in memory, for read-only instances which can't write them to the database.
+//
+// Sessions end when the instance exits.
+type memorySessions struct {
+	interfaces.UserStore
+
+	mu       sync.Mutex
+	sessions map[string]memorySession
+}
+
+type memorySession struct {
+	user    *models.User
+	expires time.Time
+}
+
+// newMemorySessions returns the user store with login sessions kept in memory.
+func newMemorySessions(us interfaces.UserStore) *memorySessions {
+	return &memorySessions{UserStore: us, sessions: make(map[string]memorySession)}
+}
+
+// CreateSession starts an in-memory login session for the user, returning its token.
+func (ms *memorySessions) CreateSession(userID int64, ttl time.Duration) (string, error) {
+	users, err := ms.FetchUsers()
+	if err != nil {
+		return "", err
+	}
+	var user *models.User
+	for _, u := range users {
+		if u.ID == userID {
+			user = u
+			break
+		}
+	}
+	if user == nil {
+		return "", fmt.Errorf("no user with ID %d", userID)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	now := time.Now()
+	for t, sess := range ms.sessions {
+		if now.After(sess.expires) {
+			delete(ms.sessions, t)
+		}
+	}
+	ms.sessions[token] = memorySession{user: user, expires: now.Add(ttl)}
+	return token, nil
+}
+
+// SessionUser returns the user logged in with the session token, or nil if the session is unknown or expired.
+func (ms *memorySessions) SessionUser(token string) (*models.User, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	sess, ok := ms.sessions[token]
+	if !ok || time.Now().After(sess.expires) {
+		return nil, nil
+	}
+	return sess.user, nil
+}
+
+// DeleteSession ends an in-memory login session.
+func (ms *memorySessions) DeleteSession(token string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.sessions, token)
+	return nil
+}
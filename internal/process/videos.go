@@ -0,0 +1,181 @@
+package process
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/interfaces"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/jsonutils"
+	"tubarr/internal/utils/logging"
+)
+
+const maxMetadataEdit = 1 << 20
+
+// videoOutput is a video as served over HTTP.
+type videoOutput struct {
+	ID        int64  `json:"id"`
+	ChannelID int64  `json:"channel_id"`
+	Channel   string `json:"channel"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	Path      string `json:"path,omitempty"`
+	Status    string `json:"status"`
+}
+
+// toVideoOutput converts a video for an HTTP response.
+func toVideoOutput(v *models.Video) videoOutput {
+	return videoOutput{
+		ID:        v.ID,
+		ChannelID: v.ChannelID,
+		Channel:   v.Channel.Name,
+		Title:     v.Title,
+		URL:       v.URL,
+		Path:      v.VideoPath,
+		Status:    string(v.DownloadStatus.Status),
+	}
+}
+
+// searchVideosHandler searches the videos of the channels the user can see, like 'video search'.
+//
+// The q query parameter is required. Results are newest first, limited to 50 unless the limit parameter
+// is set (0 for all), and to one channel if channel_id is set.
+func searchVideosHandler(s interfaces.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		q := strings.TrimSpace(query.Get("q"))
+		if q == "" {
+			http.Error(w, "missing search query, set q", http.StatusBadRequest)
+			return
+		}
+		limit := 50
+		if v := query.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		var chanID int64
+		if v := query.Get("channel_id"); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid channel ID", http.StatusBadRequest)
+				return
+			}
+			chanID = id
+		}
+
+		visible, err := visibleChannels(s.UserStore(), r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if chanID != 0 && visible != nil && !visible[chanID] {
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+
+		// Users who can't see every channel search each of theirs, so the limit counts only their videos
+		chanIDs := []int64{chanID}
+		if chanID == 0 && visible != nil {
+			chanIDs = chanIDs[:0]
+			for id := range visible {
+				chanIDs = append(chanIDs, id)
+			}
+		}
+
+		var videos []*models.Video
+		for _, id := range chanIDs {
+			found, err := s.VideoStore().SearchVideos(q, id, limit)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			videos = append(videos, found...)
+		}
+		if len(chanIDs) > 1 {
+			sort.SliceStable(videos, func(i, j int) bool {
+				if !videos[i].UploadDate.Equal(videos[j].UploadDate) {
+					return videos[i].UploadDate.After(videos[j].UploadDate)
+				}
+				return videos[i].ID > videos[j].ID
+			})
+		}
+		if limit > 0 && len(videos) > limit {
+			videos = videos[:limit]
+		}
+
+		out := make([]videoOutput, 0, len(videos))
+		for _, v := range videos {
+			out = append(out, toVideoOutput(v))
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// editMetadataHandler corrects the title, description, or upload date of the video in the request path,
+// like 'video edit-metadata'.
+//
+// The body is a JSON object with any of "title", "description", and "upload_date" (YYYY-MM-DD), and
+// "rewrite_json" to also update the video's JSON file. The edit is recorded in the channel activity feed.
+func editMetadataHandler(vs interfaces.VideoStore, cs interfaces.ChannelStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		r.Body = http.MaxBytesReader(w, r.Body, maxMetadataEdit)
+
+		var body struct {
+			Title       *string `json:"title"`
+			Description *string `json:"description"`
+			UploadDate  *string `json:"upload_date"`
+			RewriteJSON bool    `json:"rewrite_json"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		e := models.VideoMetadataEdit{Title: body.Title, Description: body.Description}
+		if body.UploadDate != nil {
+			t, err := time.Parse("2006-01-02", *body.UploadDate)
+			if err != nil {
+				http.Error(w, "invalid upload date, must be in the format YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			e.UploadDate = &t
+		}
+		fields := e.Fields()
+		if len(fields) == 0 {
+			http.Error(w, "must enter at least one of title, description, or upload_date", http.StatusBadRequest)
+			return
+		}
+
+		v, err := vs.EditVideoMetadata(id, &e)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := cs.RecordChannelEvent(v.ChannelID, consts.ActivityMetadataEdit, v.URL+": "+strings.Join(fields, ", ")); err != nil {
+			logging.E(0, "Failed to record metadata edit for video with URL %q: %v", v.URL, err)
+		}
+
+		if body.RewriteJSON {
+			if v.JSONPath == "" {
+				http.Error(w, "metadata updated in database, but the video has no JSON file to rewrite", http.StatusConflict)
+				return
+			}
+			if err := jsonutils.RewriteSidecar(v.JSONPath, e.MetadataFields()); err != nil {
+				http.Error(w, "metadata updated in database, but JSON rewrite failed: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		logging.S(0, "Updated %s for video with URL %q over HTTP", strings.Join(fields, ", "), v.URL)
+		writeJSON(w, http.StatusOK, map[string]any{"id": v.ID, "updated": fields})
+	}
+}
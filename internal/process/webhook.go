@@ -0,0 +1,83 @@
+package process
+
+import (
+	"fmt"
+	"net/url"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/webhook"
+)
+
+// sendWebhooks sends each webhook once for every video which finished downloading.
+func sendWebhooks(c *models.Channel, hooks []*models.Webhook, videos []*models.Video) []error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	initClients()
+
+	var errs []error
+	for _, h := range hooks {
+		parsed, err := url.Parse(h.URL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid webhook URL %q: %w", h.URL, err))
+			continue
+		}
+
+		client := httpClient(c, parsed.Host)
+
+		sent := 0
+		for _, v := range videos {
+			if v.DownloadStatus.Status != consts.DLStatusCompleted {
+				continue
+			}
+
+			body, err := webhook.Render(h.Payload, webhook.FromVideo(c, v))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("webhook %q for video %q: %w", h.Name, v.URL, err))
+				continue
+			}
+			if err := webhook.Send(client, h, body); err != nil {
+				errs = append(errs, fmt.Errorf("failed to send webhook %q for video %q: %w", h.Name, v.URL, err))
+				continue
+			}
+			sent++
+		}
+		if sent > 0 {
+			logging.S(1, "Sent webhook %q for %d videos in channel %q", h.Name, sent, c.Name)
+		}
+	}
+	return errs
+}
+
+// sendWarningWebhooks sends each webhook a warning about the channel, with the message as the payload's title.
+func sendWarningWebhooks(c *models.Channel, hooks []*models.Webhook, warning string) []error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	initClients()
+
+	var errs []error
+	for _, h := range hooks {
+		parsed, err := url.Parse(h.URL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid webhook URL %q: %w", h.URL, err))
+			continue
+		}
+
+		client := httpClient(c, parsed.Host)
+
+		body, err := webhook.Render(h.Payload, webhook.FromWarning(c, warning))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhook %q for warning: %w", h.Name, err))
+			continue
+		}
+		if err := webhook.Send(client, h, body); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send warning webhook %q: %w", h.Name, err))
+			continue
+		}
+		logging.S(1, "Sent warning webhook %q for channel %q", h.Name, c.Name)
+	}
+	return errs
+}
@@ -0,0 +1,147 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"tubarr/internal/models"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+// NextCrawl is when a channel is next due for a crawl, and why it was pushed back if it was.
+type NextCrawl struct {
+	At     time.Time
+	Reason string
+}
+
+// QuietHours is a daily window, which may wrap past midnight, during which crawls don't start.
+type QuietHours struct {
+	start, end int // Minutes after midnight
+	raw        string
+}
+
+// ParseQuietHours parses a daily window such as "22:00-07:00".
+func ParseQuietHours(s string) (*QuietHours, error) {
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid quiet hours %q, use HH:MM-HH:MM", s)
+	}
+
+	start, err := parseClock(startStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+	end, err := parseClock(endStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid quiet hours %q, start and end are the same", s)
+	}
+	return &QuietHours{start: start, end: end, raw: strings.TrimSpace(s)}, nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	hStr, mStr, ok := strings.Cut(strings.TrimSpace(s), ":")
+	h, hErr := strconv.Atoi(hStr)
+	m, mErr := strconv.Atoi(mStr)
+	if !ok || hErr != nil || mErr != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q, use HH:MM", s)
+	}
+	return h*60 + m, nil
+}
+
+// contains reports whether the time falls in the quiet window.
+func (q *QuietHours) contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if q.start < q.end {
+		return m >= q.start && m < q.end
+	}
+	return m >= q.start || m < q.end
+}
+
+// endAfter returns when the quiet window containing the time ends.
+func (q *QuietHours) endAfter(t time.Time) time.Time {
+	end := time.Date(t.Year(), t.Month(), t.Day(), q.end/60, q.end%60, 0, 0, t.Location())
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// Next returns when the channel is next due for a crawl, no earlier than now.
+//
+// The channel's cron expression is used if set, otherwise its crawl frequency. Jitter is added
+// to the scheduled time, and the result is pushed past any quiet hours or blackout dates.
+func Next(c *models.Channel, now time.Time) NextCrawl {
+	next := NextCrawl{At: scheduledAfter(c)}
+	if next.At.IsZero() {
+		return next
+	}
+	next.At = next.At.Add(jitter(c.ID, next.At, c.Settings.CrawlJitter))
+	if next.At.Before(now) {
+		next.At = now
+	}
+	return Unheld(c, next.At)
+}
+
+// Unheld returns the first time at or after the given time which falls outside the channel's
+// quiet hours and blackout dates, with the reason it was pushed back if it was.
+func Unheld(c *models.Channel, at time.Time) NextCrawl {
+	next := NextCrawl{At: at}
+
+	var quiet *QuietHours
+	if c.Settings.QuietHours != "" {
+		var err error
+		if quiet, err = ParseQuietHours(c.Settings.QuietHours); err != nil {
+			logging.E(0, "Ignoring quiet hours for channel %q: %v", c.Name, err)
+		}
+	}
+
+	// Quiet hours and blackouts may push each other back, e.g. a blackout ending at midnight inside quiet hours
+	for {
+		moved := false
+		if quiet != nil && quiet.contains(next.At) {
+			next.At = quiet.endAfter(next.At)
+			next.Reason = "quiet hours " + quiet.raw
+			moved = true
+		}
+		if blackout, resume, ok := parsing.ActiveBlackout(c.Settings.BlackoutDates, next.At); ok {
+			next.At = resume
+			next.Reason = "blackout " + blackout
+			moved = true
+		}
+		if !moved {
+			return next
+		}
+	}
+}
+
+// scheduledAfter returns the channel's next scheduled time after its last scan, before jitter and quiet periods.
+func scheduledAfter(c *models.Channel) time.Time {
+	if c.Settings.CrawlCron != "" {
+		cron, err := ParseCron(c.Settings.CrawlCron)
+		if err == nil {
+			return cron.Next(c.LastScan)
+		}
+		logging.E(0, "Invalid crawl schedule for channel %q, falling back to crawl frequency: %v", c.Name, err)
+	}
+	return c.LastScan.Add(time.Duration(c.Settings.CrawlFreq) * time.Minute)
+}
+
+// jitter returns a stable offset of up to the given minutes for a channel's scheduled time.
+//
+// The offset is derived from the channel and slot, so repeated checks agree on when the channel is due.
+func jitter(channelID int64, slot time.Time, minutes int) time.Duration {
+	if minutes <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%d", channelID, slot.Unix())
+	return time.Duration(h.Sum64() % uint64(time.Duration(minutes)*time.Minute))
+}
@@ -0,0 +1,170 @@
+// Package apprise sends notifications to Apprise service URLs, such as "tgram://token/chat" or "slack://...".
+//
+// Discord, Telegram, and ntfy URLs are sent directly. Other services need the apprise command line tool.
+package apprise
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"tubarr/internal/utils/chat"
+)
+
+// Scheme marks channel notification URLs which are Apprise URLs, such as "apprise:tgram://token/chat".
+const Scheme = "apprise:"
+
+// DefaultBin is the apprise executable, searched for in PATH.
+const DefaultBin = "apprise"
+
+const runTimeout = 2 * time.Minute
+
+// URLs returns the Apprise URLs of prefixed notification URLs.
+func URLs(notifyURLs []string) []string {
+	var out []string
+	for _, u := range notifyURLs {
+		if a, ok := strings.CutPrefix(u, Scheme); ok {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Validate checks an Apprise URL, using the apprise tool for services which aren't sent directly.
+//
+// If the apprise tool isn't installed only the URL form is checked.
+func Validate(bin, appriseURL string) error {
+	scheme, _, ok := strings.Cut(appriseURL, "://")
+	if !ok || scheme == "" || strings.ContainsAny(scheme, "/?#:@ ") || strings.ContainsAny(appriseURL, " \n") {
+		return fmt.Errorf("invalid Apprise URL %q, expected a service URL such as 'tgram://bottoken/chatid'", appriseURL)
+	}
+	if _, _, ok := native(appriseURL); ok {
+		return nil
+	}
+
+	path, err := exec.LookPath(binOrDefault(bin))
+	if err != nil {
+		return fmt.Errorf("%q notifications need the apprise tool, which was not found: %w", scheme, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, path, "--dry-run", "--body", "test", appriseURL).CombinedOutput(); err != nil {
+		return fmt.Errorf("apprise rejected URL %q: %s", appriseURL, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Send notifies each Apprise URL, sending Discord, Telegram, and ntfy directly and the rest through the apprise tool.
+//
+// The client function returns the HTTP client for a host, for direct sends.
+func Send(client func(host string) *http.Client, bin string, appriseURLs []string, m chat.Message) error {
+	var (
+		errs []error
+		rest []string
+	)
+	for _, a := range appriseURLs {
+		provider, target, ok := native(a)
+		if !ok {
+			rest = append(rest, a)
+			continue
+		}
+		u, err := url.Parse(target)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s target: %w", provider, err))
+			continue
+		}
+		if err := chat.Send(client(u.Host), provider, target, m); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider, err))
+		}
+	}
+
+	if len(rest) > 0 {
+		if err := run(bin, rest, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run sends the message to the URLs with the apprise tool.
+//
+// URLs are passed as arguments rather than through the shell.
+func run(bin string, appriseURLs []string, m chat.Message) error {
+	path, err := exec.LookPath(binOrDefault(bin))
+	if err != nil {
+		return fmt.Errorf("apprise tool not found: %w", err)
+	}
+
+	args := []string{"--title", "New video from " + m.Channel, "--body", m.Title + "\n" + m.URL}
+	if m.Thumbnail != "" {
+		args = append(args, "--attach", m.Thumbnail)
+	}
+	args = append(args, appriseURLs...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("apprise failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// native converts Apprise URLs for services Tubarr sends directly into a chat provider and target.
+//
+// URLs with extra options are left to the apprise tool. The URLs are split by hand, as Telegram
+// tokens hold a colon which URL parsing reads as a port.
+func native(appriseURL string) (provider, target string, ok bool) {
+	scheme, rest, _ := strings.Cut(appriseURL, "://")
+	if rest == "" || strings.ContainsAny(rest, "?#@") {
+		return "", "", false
+	}
+	parts := strings.Split(strings.TrimSuffix(rest, "/"), "/")
+	for _, p := range parts {
+		if p == "" {
+			return "", "", false
+		}
+	}
+
+	switch scheme {
+	case "discord":
+		// discord://webhook_id/webhook_token
+		if len(parts) == 2 {
+			return chat.Discord, "https://discord.com/api/webhooks/" + parts[0] + "/" + parts[1], true
+		}
+	case "tgram":
+		// tgram://bot_token/chat_id, with a single chat
+		if len(parts) == 2 {
+			return chat.Telegram, chat.TelegramTarget(parts[0], parts[1]), true
+		}
+	case "ntfy", "ntfys":
+		// ntfy://topic on ntfy.sh, or ntfy://host/topic
+		switch len(parts) {
+		case 1:
+			return chat.Ntfy, chat.DefaultNtfy + "/" + parts[0], true
+		case 2:
+			proto := "http"
+			if scheme == "ntfys" {
+				proto = "https"
+			}
+			return chat.Ntfy, proto + "://" + parts[0] + "/" + parts[1], true
+		}
+	}
+	return "", "", false
+}
+
+// binOrDefault returns bin, or the default apprise executable if bin is blank.
+func binOrDefault(bin string) string {
+	if bin == "" {
+		return DefaultBin
+	}
+	return bin
+}
@@ -1,13 +1,19 @@
 package browser
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	"tubarr/internal/auth"
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/domain/keys"
 	"tubarr/internal/models"
 	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/proxy"
 
 	"golang.org/x/net/publicsuffix"
 
@@ -15,9 +21,20 @@ import (
 )
 
 // channelAuth authenticates a user for a given channel, if login credentials are present.
-func channelAuth(channelURL, cookiesFilePath string, c *models.Channel) ([]*http.Cookie, error) {
+func channelAuth(channelURL, cookiesFilePath string, c *models.Channel, ctx context.Context) ([]*http.Cookie, error) {
+	authMu.Lock()
+	defer authMu.Unlock()
+
 	if customAuthCookies[channelURL] == nil { // If the user is not already authenticated
-		cookies, err := login(cookiesFilePath, c)
+		var (
+			cookies []*http.Cookie
+			err     error
+		)
+		if c.Settings.AuthMethod == consts.AuthMethodBrowser {
+			cookies, err = browserLogin(cookiesFilePath, c, ctx)
+		} else {
+			cookies, err = login(cookiesFilePath, c)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -26,7 +43,44 @@ func channelAuth(channelURL, cookiesFilePath string, c *models.Channel) ([]*http
 	return customAuthCookies[channelURL], nil
 }
 
+// browserLogin logs the user in through a headless browser, reusing the session saved by the last login while it lasts.
+//
+// The session is saved encrypted, and written to the cookies file for yt-dlp.
+func browserLogin(cookiesFilePath string, c *models.Channel, ctx context.Context) ([]*http.Cookie, error) {
+	cookies, err := auth.LoadSession(c)
+	if err != nil {
+		logging.E(0, "Failed to load saved session for channel %q, logging in again: %v", c.Name, err)
+	}
+
+	if len(cookies) > 0 {
+		logging.I("Reusing saved login session for channel %q", c.Name)
+	} else {
+		sess, err := auth.BrowserLogin(c, cfg.GetString(keys.ChromePath), proxy.Pick(c.Settings.Proxies), ctx)
+		if err != nil {
+			return nil, err
+		}
+		if reason := challengeMarker(sess.HTML); reason != "" {
+			return nil, &ChallengeError{URL: sess.URL, Reason: reason}
+		}
+		if sess.FormShown && len(sess.Cookies) == 0 {
+			return nil, &ChallengeError{URL: sess.URL, Reason: "login form was shown again"}
+		}
+
+		cookies = sess.Cookies
+		if err := auth.SaveSession(c, cookies); err != nil {
+			logging.E(0, "Failed to save login session for channel %q: %v", c.Name, err)
+		}
+	}
+
+	if err := saveCookiesToFile(cookies, cookiesFilePath, c); err != nil {
+		return nil, err
+	}
+	return cookies, nil
+}
+
 // login logs the user in and returns the authentication cookie.
+//
+// Returns a *ChallengeError if the site shows a captcha or login wall instead.
 func login(cookiesFilePath string, c *models.Channel) ([]*http.Cookie, error) {
 	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
 	if err != nil {
@@ -34,6 +88,9 @@ func login(cookiesFilePath string, c *models.Channel) ([]*http.Cookie, error) {
 	}
 
 	client := &http.Client{Jar: jar}
+	if pf := proxy.Func(proxy.Pick(c.Settings.Proxies)); pf != nil {
+		client.Transport = &http.Transport{Proxy: pf}
+	}
 
 	logging.I("Logging in to %q with username %q", c.LoginURL, c.Username)
 
@@ -52,6 +109,9 @@ func login(cookiesFilePath string, c *models.Channel) ([]*http.Cookie, error) {
 	if err != nil {
 		return nil, err
 	}
+	if reason := detectChallenge(resp, body); reason != "" {
+		return nil, &ChallengeError{URL: c.LoginURL, Status: resp.StatusCode, Reason: reason}
+	}
 
 	// Parse the login page to find any hidden token fields
 	token := parseToken(string(body))
@@ -79,6 +139,13 @@ func login(cookiesFilePath string, c *models.Channel) ([]*http.Cookie, error) {
 	}
 	defer resp.Body.Close()
 
+	if body, err = io.ReadAll(resp.Body); err != nil {
+		return nil, err
+	}
+	if reason := detectChallenge(resp, body); reason != "" {
+		return nil, &ChallengeError{URL: c.LoginURL, Status: resp.StatusCode, Reason: reason}
+	}
+
 	// Log the cookies for debugging
 	if logging.Level > 1 {
 		for _, cookie := range resp.Cookies() {
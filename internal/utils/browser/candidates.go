@@ -0,0 +1,128 @@
+package browser
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"tubarr/internal/domain/consts"
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	candidateChunkSize = 256
+)
+
+// candidateFilter holds the compiled state used to evaluate candidate URLs.
+type candidateFilter struct {
+	existing map[string]struct{}
+	allow    []string
+	allowRx  []*regexp.Regexp
+	blockRx  []*regexp.Regexp
+}
+
+// evaluateCandidates drops already recorded URLs, then applies the URL allow and block patterns.
+//
+// Existing URLs are normalized into a set once, and candidates are evaluated in parallel chunks.
+// Returned URLs keep the input order.
+func evaluateCandidates(candidates, existingURLs, allow, block []string) (newURLs []string, skipped []*models.SkippedVideo, err error) {
+	f := candidateFilter{
+		existing: make(map[string]struct{}, len(existingURLs)),
+		allow:    allow,
+	}
+	if f.allowRx, err = compilePatterns(allow, "allow"); err != nil {
+		return nil, nil, err
+	}
+	if f.blockRx, err = compilePatterns(block, "block"); err != nil {
+		return nil, nil, err
+	}
+	for _, u := range existingURLs {
+		f.existing[normalizeURL(u)] = struct{}{}
+	}
+
+	var (
+		n        = len(candidates)
+		outcomes = make([]*models.SkippedVideo, n)
+		keep     = make([]bool, n)
+		next     atomic.Int64
+		wg       sync.WaitGroup
+	)
+
+	workers := min(runtime.NumCPU(), (n+candidateChunkSize-1)/candidateChunkSize)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				start := int(next.Add(candidateChunkSize)) - candidateChunkSize
+				if start >= n {
+					return
+				}
+				for i := start; i < min(start+candidateChunkSize, n); i++ {
+					keep[i], outcomes[i] = f.evaluate(candidates[i])
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Dedup is done in order afterwards, so the first occurrence of a URL wins
+	seen := make(map[string]struct{}, n)
+	newURLs = make([]string, 0, n)
+	for i, u := range candidates {
+		switch {
+		case outcomes[i] != nil:
+			skipped = append(skipped, outcomes[i])
+		case keep[i]:
+			norm := normalizeURL(u)
+			if _, dup := seen[norm]; dup {
+				continue
+			}
+			seen[norm] = struct{}{}
+			newURLs = append(newURLs, u)
+		}
+	}
+
+	if len(skipped) > 0 {
+		logging.I("Skipped %d URLs due to URL allow/block patterns", len(skipped))
+	}
+	return newURLs, skipped, nil
+}
+
+// evaluate checks a single candidate, returning whether to keep it or why it was skipped.
+//
+// Already recorded URLs are neither kept nor reported as skipped.
+func (f *candidateFilter) evaluate(u string) (keep bool, skipped *models.SkippedVideo) {
+	if u == "" {
+		return false, nil
+	}
+	if _, exists := f.existing[normalizeURL(u)]; exists {
+		return false, nil
+	}
+	if len(f.allowRx) > 0 && matchingPattern(u, f.allowRx) == "" {
+		logging.D(1, "URL %q does not match any allow pattern, skipping", u)
+		return false, &models.SkippedVideo{URL: u, Reason: consts.SkipURLAllow, Detail: strings.Join(f.allow, ", ")}
+	}
+	if p := matchingPattern(u, f.blockRx); p != "" {
+		logging.D(1, "URL %q matches a block pattern, skipping", u)
+		return false, &models.SkippedVideo{URL: u, Reason: consts.SkipURLBlock, Detail: p}
+	}
+	return true, nil
+}
+
+// compilePatterns compiles URL allow or block patterns.
+func compilePatterns(patterns []string, kind string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		rx, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %s pattern %q: %w", kind, p, err)
+		}
+		compiled = append(compiled, rx)
+	}
+	return compiled, nil
+}
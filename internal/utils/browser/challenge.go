@@ -0,0 +1,154 @@
+package browser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// ChallengeError is returned when a site answers a login with a captcha or login wall a person has to get past.
+type ChallengeError struct {
+	URL    string
+	Status int
+	Reason string
+}
+
+func (e *ChallengeError) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("login challenge at %s: %s", e.URL, e.Reason)
+	}
+	return fmt.Sprintf("login challenge at %s (HTTP %d): %s", e.URL, e.Status, e.Reason)
+}
+
+// challengeMarkers are page contents which identify captchas and bot checks.
+var challengeMarkers = []string{
+	"g-recaptcha",
+	"h-captcha",
+	"cf-chl-",
+	"challenge-platform",
+	"captcha",
+	"verify you are human",
+	"are you a robot",
+}
+
+var authMu sync.Mutex
+
+// detectChallenge returns why a login response is a challenge, or an empty string if it isn't one.
+//
+// The login form coming back with no cookies set is treated as a login wall.
+func detectChallenge(resp *http.Response, body []byte) string {
+	if reason := challengeMarker(string(body)); reason != "" {
+		return reason
+	}
+	page := strings.ToLower(string(body))
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "login was refused"
+	case http.StatusTooManyRequests:
+		return "rate limited"
+	}
+
+	if resp.Request != nil && resp.Request.Method == http.MethodPost &&
+		len(resp.Cookies()) == 0 && strings.Contains(page, `type="password"`) {
+		return "login form was shown again"
+	}
+	return ""
+}
+
+// challengeMarker returns which captcha or bot check a page shows, or an empty string if it shows none.
+func challengeMarker(page string) string {
+	page = strings.ToLower(page)
+	for _, m := range challengeMarkers {
+		if strings.Contains(page, m) {
+			return fmt.Sprintf("page contains %q", m)
+		}
+	}
+	return ""
+}
+
+// SetAuthCookies uses cookies pasted by the user for the channel's crawls in place of logging in.
+//
+// The input is either a Netscape cookies file or a Cookie header ("name=value; name2=value2").
+// The cookies are saved to the channel's cookie file, which yt-dlp also uses. Returns the number of cookies set.
+func SetAuthCookies(c *models.Channel, raw string) (int, error) {
+	if c.BaseDomain == "" {
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return 0, err
+		}
+		c.BaseDomain = u.Hostname()
+	}
+
+	cookies, err := parseCookieInput(raw)
+	if err != nil {
+		return 0, err
+	}
+	if err := saveCookiesToFile(cookies, authCookiesPath(c), c); err != nil {
+		return 0, err
+	}
+
+	authMu.Lock()
+	customAuthCookies[c.BaseDomain] = cookies
+	authMu.Unlock()
+
+	logging.I("Set %d cookies for channel %q", len(cookies), c.Name)
+	return len(cookies), nil
+}
+
+// parseCookieInput parses cookies from a Netscape cookies file or a Cookie header.
+func parseCookieInput(raw string) ([]*http.Cookie, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("no cookies entered")
+	}
+
+	var cookies []*http.Cookie
+	if strings.Contains(raw, "\t") {
+		sc := bufio.NewScanner(strings.NewReader(raw))
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || (strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "#HttpOnly_")) {
+				continue
+			}
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+
+			f := strings.Split(line, "\t")
+			if len(f) != 7 {
+				return nil, fmt.Errorf("invalid cookies file line %q, expected 7 tab separated fields", line)
+			}
+			ck := &http.Cookie{Domain: f[0], Path: f[2], Secure: f[3] == "TRUE", Name: f[5], Value: f[6]}
+			if exp, err := strconv.ParseInt(f[4], 10, 64); err == nil && exp > 0 {
+				ck.Expires = time.Unix(exp, 0)
+			}
+			cookies = append(cookies, ck)
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		header := strings.TrimSpace(strings.TrimPrefix(raw, "Cookie:"))
+		parsed, err := http.ParseCookie(header)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie header: %w", err)
+		}
+		for _, ck := range parsed {
+			ck.Path = "/"
+		}
+		cookies = parsed
+	}
+
+	if len(cookies) == 0 {
+		return nil, errors.New("no cookies entered")
+	}
+	return cookies, nil
+}
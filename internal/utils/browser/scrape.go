@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	"tubarr/internal/cfg"
@@ -20,6 +21,8 @@ import (
 	"tubarr/internal/models"
 	"tubarr/internal/parsing"
 	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/proxy"
+	"tubarr/internal/utils/ytdlp"
 
 	"github.com/gocolly/colly"
 )
@@ -36,7 +39,8 @@ type urlPattern struct {
 
 type ytDlpOutput struct {
 	Entries []struct {
-		URL string `json:"url"`
+		URL   string `json:"url"`
+		Title string `json:"title"`
 	} `json:"entries"`
 }
 
@@ -71,23 +75,37 @@ func NewBrowser() *Browser {
 }
 
 // GetNewReleases checks a channel URL for URLs which have not yet been recorded as downloaded.
-func (b *Browser) GetNewReleases(cs interfaces.ChannelStore, c *models.Channel, ctx context.Context) ([]*models.Video, error) {
+func (b *Browser) GetNewReleases(s interfaces.Store, c *models.Channel, ctx context.Context) ([]*models.Video, error) {
 	if c.URL == "" {
 		return nil, fmt.Errorf("channel url is blank (channel ID: %d)", c.ID)
 	}
 
-	existingURLs, err := cs.LoadGrabbedURLs(c)
+	existingURLs, err := s.ChannelStore().LoadGrabbedURLs(c)
 	if err != nil {
 		return nil, err
 	}
+	return b.getReleases(s, c, existingURLs, ctx)
+}
 
-	existingMap := make(map[string]struct{}, len(existingURLs))
-	for _, url := range existingURLs {
-		existingMap[url] = struct{}{}
+// GetUnseenReleases checks a channel URL for URLs which have no video entry of any status in the database.
+func (b *Browser) GetUnseenReleases(s interfaces.Store, c *models.Channel, ctx context.Context) ([]*models.Video, error) {
+	if c.URL == "" {
+		return nil, fmt.Errorf("channel url is blank (channel ID: %d)", c.ID)
 	}
 
-	if len(existingMap) > 0 {
-		logging.I("Found %d existing downloaded video URLs:", len(existingMap))
+	knownURLs, err := s.ChannelStore().LoadAllVideoURLs(c)
+	if err != nil {
+		return nil, err
+	}
+	return b.getReleases(s, c, knownURLs, ctx)
+}
+
+// getReleases scrapes the channel URL and returns video requests for URLs not in existingURLs.
+func (b *Browser) getReleases(s interfaces.Store, c *models.Channel, existingURLs []string, ctx context.Context) ([]*models.Video, error) {
+	var err error
+
+	if len(existingURLs) > 0 {
+		logging.I("Found %d existing video URLs:", len(existingURLs))
 	}
 
 	var (
@@ -116,41 +134,35 @@ func (b *Browser) GetNewReleases(cs interfaces.ChannelStore, c *models.Channel,
 		logging.D(1, "Saved channel domain %q\nChannel domain with protocol %q", c.BaseDomain, c.BaseDomainWithProto)
 	}
 
-	if customAuthCookies[c.BaseDomain] == nil {
-		const (
-			tubarrDir = ".tubarr/"
-			txtExt    = ".txt"
-		)
-
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			logging.E(0, "Failed to get user home directory, reverting to '/': %v", err)
-			homeDir = "/"
-		}
-
-		sb.Reset()
-		sb.Grow(len(homeDir) + 1 + len(tubarrDir) + len(c.Name) + len(txtExt))
-		sb.WriteString(homeDir)
-		if !strings.HasSuffix(c.VideoDir, "/") {
-			sb.WriteRune('/')
+	if cfg.IsSet(keys.AuthCookies) {
+		if _, err := SetAuthCookies(c, cfg.GetString(keys.AuthCookies)); err != nil {
+			return nil, err
 		}
-		sb.WriteString(tubarrDir)
+	}
 
-		noSpaceChanName := strings.ReplaceAll(c.Name, " ", "-")
-		sb.WriteString(noSpaceChanName)
-		sb.WriteString(txtExt)
+	authMu.Lock()
+	cookies = customAuthCookies[c.BaseDomain]
+	authMu.Unlock()
 
+	if cookies == nil {
 		if (c.Username != "" || c.Password != "") && c.LoginURL != "" {
-			cookies, err = channelAuth(c.BaseDomain, sb.String(), c)
+			// Password references are only resolved when logging in
+			if c.Username, c.Password, c.LoginURL, err = s.ChannelStore().GetAuth(c.ID); err != nil {
+				return nil, err
+			}
+			cookies, err = channelAuth(c.BaseDomain, authCookiesPath(c), c, ctx)
 			if err != nil {
 				return nil, err
 			}
-			customAuthCookies[c.BaseDomain] = cookies
 			logging.D(2, "Set %d cookies for domain %q: %v", len(cookies), c.BaseDomain, cookies)
 		}
 	} else {
-		cookies = customAuthCookies[c.BaseDomain]
 		logging.D(2, "Retrieved %d cookies for domain %q: %v", len(cookies), c.BaseDomain, cookies)
+		if path := authCookiesPath(c); c.CookiePath == "" {
+			if _, err := os.Stat(path); err == nil {
+				c.CookiePath = path
+			}
+		}
 	}
 
 	if cookies == nil {
@@ -168,27 +180,42 @@ func (b *Browser) GetNewReleases(cs interfaces.ChannelStore, c *models.Channel,
 		}
 	}
 
-	newURLs, err := b.newEpisodeURLs(c.URL, existingURLs, fileURLs, cookies, ctx)
+	playlists := make(map[string]string)
+	candidates, err := b.newEpisodeURLs(c.URL, c.Settings.PlaylistMatch, proxy.Pick(c.Settings.Proxies), fileURLs, cookies, playlists, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newURLs, skipped, err := evaluateCandidates(candidates, existingURLs, c.Settings.URLAllow, c.Settings.URLBlock)
 	if err != nil {
 		return nil, err
 	}
+	if len(newURLs) == 0 {
+		logging.I("No new videos at %s", c.URL)
+	}
+
+	if len(skipped) > 0 && cfg.GetBool(keys.RecordSkips) {
+		for _, sv := range skipped {
+			sv.ChannelID = c.ID
+		}
+		if err := s.SkipStore().RecordSkips(skipped); err != nil {
+			logging.E(0, "Failed to record skipped videos: %v", err)
+		}
+	}
 
 	newRequests := make([]*models.Video, 0, len(newURLs))
 	for _, newURL := range newURLs {
-		if newURL != "" {
-			if _, exists := existingMap[newURL]; !exists {
-				newRequests = append(newRequests, &models.Video{
-					ChannelID:  c.ID,
-					URL:        newURL,
-					VideoDir:   c.VideoDir,
-					JSONDir:    c.JSONDir,
-					Channel:    c,
-					Settings:   c.Settings,
-					MetarrArgs: c.MetarrArgs,
-					CookiePath: c.CookiePath,
-				})
-			}
-		}
+		newRequests = append(newRequests, &models.Video{
+			ChannelID:  c.ID,
+			URL:        newURL,
+			VideoDir:   c.VideoDir,
+			JSONDir:    c.JSONDir,
+			Channel:    c,
+			Settings:   c.Settings,
+			MetarrArgs: c.MetarrArgs,
+			CookiePath: c.CookiePath,
+			Playlist:   playlists[newURL],
+		})
 	}
 
 	if len(newRequests) > 0 {
@@ -204,10 +231,47 @@ func (b *Browser) GetNewReleases(cs interfaces.ChannelStore, c *models.Channel,
 	return newRequests, nil
 }
 
-// newEpisodeURLs checks for new episode URLs that are not yet in grabbed-urls.txt
-func (b *Browser) newEpisodeURLs(targetURL string, existingURLs, fileURLs []string, cookies []*http.Cookie, ctx context.Context) ([]string, error) {
+// authCookiesPath returns the path of the cookies file saved for a channel's login.
+func authCookiesPath(c *models.Channel) string {
+	const (
+		tubarrDir = ".tubarr/"
+		txtExt    = ".txt"
+	)
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		logging.E(0, "Failed to get user home directory, reverting to '/': %v", err)
+		homeDir = "/"
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(homeDir) + 1 + len(tubarrDir) + len(c.Name) + len(txtExt))
+	sb.WriteString(homeDir)
+	if !strings.HasSuffix(c.VideoDir, "/") {
+		sb.WriteRune('/')
+	}
+	sb.WriteString(tubarrDir)
+
+	noSpaceChanName := strings.ReplaceAll(c.Name, " ", "-")
+	sb.WriteString(noSpaceChanName)
+	sb.WriteString(txtExt)
+	return sb.String()
+}
+
+// newEpisodeURLs collects the unique candidate episode URLs from the page, URL file, and added URLs.
+//
+// If playlistMatch is set, the target is treated as a playlists page and each video's playlist title is added to playlists.
+// The page and yt-dlp requests go through proxyURL if it is set.
+func (b *Browser) newEpisodeURLs(targetURL, playlistMatch, proxyURL string, fileURLs []string, cookies []*http.Cookie, playlists map[string]string, ctx context.Context) ([]string, error) {
 	uniqueEpisodeURLs := make(map[string]struct{})
 
+	// Reset on every crawl, as the collector is shared by all channels
+	if pf := proxy.Func(proxyURL); pf != nil {
+		b.collector.SetProxyFunc(pf)
+	} else {
+		b.collector.SetProxyFunc(http.ProxyFromEnvironment)
+	}
+
 	// Set cookies
 	for _, cookie := range cookies {
 		if err := b.collector.SetCookies(targetURL, []*http.Cookie{cookie}); err != nil {
@@ -236,14 +300,19 @@ func (b *Browser) newEpisodeURLs(targetURL string, existingURLs, fileURLs []stri
 		})
 	}
 
-	if customDom {
+	if playlistMatch != "" && !cfg.IsSet(keys.URLFile) {
+		var err error
+		if uniqueEpisodeURLs, err = ytDlpPlaylistFetch(targetURL, playlistMatch, proxyURL, uniqueEpisodeURLs, playlists, ctx); err != nil {
+			return nil, err
+		}
+	} else if customDom {
 		if err := b.collector.Visit(targetURL); err != nil {
 			return nil, fmt.Errorf("error visiting webpage (%s): %w", targetURL, err)
 		}
 		b.collector.Wait()
 	} else {
 		var err error
-		if uniqueEpisodeURLs, err = ytDlpURLFetch(targetURL, uniqueEpisodeURLs, ctx); err != nil {
+		if uniqueEpisodeURLs, err = ytDlpURLFetch(targetURL, proxyURL, uniqueEpisodeURLs, ctx); err != nil {
 			return nil, err
 		}
 	}
@@ -260,33 +329,17 @@ func (b *Browser) newEpisodeURLs(targetURL string, existingURLs, fileURLs []stri
 		episodeURLs = append(episodeURLs, urls...)
 	}
 
-	// Filter out existing URLs
-	newURLs := ignoreDownloadedURLs(episodeURLs, existingURLs)
-
-	if len(newURLs) == 0 {
-		logging.I("No new videos at %s", targetURL)
-		return nil, nil
-	}
-	return newURLs, nil
+	return episodeURLs, nil
 }
 
-// ignoreDownloadedURLs filters out already downloaded URLs.
-func ignoreDownloadedURLs(inputURLs, existingURLs []string) []string {
-	var newURLs = make([]string, 0, len(inputURLs))
-	for _, url := range inputURLs {
-		normalizedURL := normalizeURL(url)
-		exists := false
-		for _, existingURL := range existingURLs {
-			if normalizeURL(existingURL) == normalizedURL {
-				exists = true
-				break
-			}
-		}
-		if !exists {
-			newURLs = append(newURLs, url)
+// matchingPattern returns the first pattern matching the input, or an empty string if none match.
+func matchingPattern(s string, patterns []*regexp.Regexp) string {
+	for _, rx := range patterns {
+		if rx.MatchString(s) {
+			return rx.String()
 		}
 	}
-	return newURLs
+	return ""
 }
 
 // normalizeURL standardizes URLs for comparison by removing protocol and any trailing slashes.
@@ -299,12 +352,12 @@ func normalizeURL(inputURL string) string {
 }
 
 // ytDlpURLFetch fetches URLs using yt-dlp.
-func ytDlpURLFetch(chanURL string, uniqueEpisodeURLs map[string]struct{}, ctx context.Context) (map[string]struct{}, error) {
+func ytDlpURLFetch(chanURL, proxyURL string, uniqueEpisodeURLs map[string]struct{}, ctx context.Context) (map[string]struct{}, error) {
 	if uniqueEpisodeURLs == nil {
 		uniqueEpisodeURLs = make(map[string]struct{})
 	}
 
-	cmd := exec.CommandContext(ctx, cmdvideo.YTDLP, consts.YtDLPFlatPlaylist, consts.YtDLPOutputJSON, chanURL)
+	cmd := flatPlaylistCommand(chanURL, proxyURL, ctx)
 
 	j, err := cmd.Output()
 	if err != nil {
@@ -322,3 +375,60 @@ func ytDlpURLFetch(chanURL string, uniqueEpisodeURLs map[string]struct{}, ctx co
 
 	return uniqueEpisodeURLs, nil
 }
+
+// flatPlaylistCommand builds a yt-dlp command listing the entries at the URL without downloading them.
+func flatPlaylistCommand(targetURL, proxyURL string, ctx context.Context) *exec.Cmd {
+	args := []string{consts.YtDLPFlatPlaylist, consts.YtDLPOutputJSON}
+	if proxyURL != "" {
+		args = append(args, cmdvideo.Proxy, proxyURL)
+	}
+	return ytdlp.Command(ctx, append(args, targetURL)...)
+}
+
+// ytDlpPlaylistFetch enumerates the playlists on a page and fetches URLs from those with titles matching the pattern.
+//
+// Matching is case-insensitive, and '*' matches across '/'. The matched playlist title is recorded for each video URL.
+func ytDlpPlaylistFetch(pageURL, pattern, proxyURL string, uniqueEpisodeURLs map[string]struct{}, playlists map[string]string, ctx context.Context) (map[string]struct{}, error) {
+	cmd := flatPlaylistCommand(pageURL, proxyURL, ctx)
+
+	j, err := cmd.Output()
+	if err != nil {
+		return uniqueEpisodeURLs, fmt.Errorf(errconsts.YTDLPFailure, err)
+	}
+
+	var result ytDlpOutput
+	if err := json.Unmarshal(j, &result); err != nil {
+		return uniqueEpisodeURLs, err
+	}
+
+	match, err := parsing.CompileGlob(pattern)
+	if err != nil {
+		return uniqueEpisodeURLs, fmt.Errorf("invalid playlist match pattern %q: %w", pattern, err)
+	}
+
+	var matched int
+	for _, entry := range result.Entries {
+		if !match.MatchString(entry.Title) {
+			logging.D(1, "Ignoring playlist %q, title does not match %q", entry.Title, pattern)
+			continue
+		}
+		matched++
+
+		videoURLs, err := ytDlpURLFetch(entry.URL, proxyURL, nil, ctx)
+		if err != nil {
+			return uniqueEpisodeURLs, fmt.Errorf("failed to fetch playlist %q: %w", entry.Title, err)
+		}
+		for u := range videoURLs {
+			uniqueEpisodeURLs[u] = struct{}{}
+			if _, exists := playlists[u]; !exists {
+				playlists[u] = entry.Title
+			}
+		}
+		logging.I("Matched playlist %q with %d videos", entry.Title, len(videoURLs))
+	}
+
+	if matched == 0 {
+		logging.I("No playlists at %s matched %q", pageURL, pattern)
+	}
+	return uniqueEpisodeURLs, nil
+}
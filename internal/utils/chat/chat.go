@@ -0,0 +1,256 @@
+// Package chat sends new video messages to Discord webhooks, Telegram bots, and ntfy topics.
+//
+// Chat targets are stored as notification URLs with the provider as a prefix, such as
+// "discord:https://discord.com/api/webhooks/...".
+package chat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// Providers.
+const (
+	Discord  = "discord"
+	Telegram = "telegram"
+	Ntfy     = "ntfy"
+)
+
+// Providers returns the supported chat providers.
+func Providers() []string {
+	return []string{Discord, Telegram, Ntfy}
+}
+
+// DefaultNtfy is the public ntfy server, used when no server is given.
+const DefaultNtfy = "https://ntfy.sh"
+
+const (
+	telegramAPI   = "https://api.telegram.org"
+	checkTimeout  = 10 * time.Second
+	discordPrefix = "/api/webhooks/"
+)
+
+var checkClient = &http.Client{Timeout: checkTimeout}
+
+// Message is a new video announcement.
+type Message struct {
+	Title     string
+	Channel   string
+	URL       string
+	Thumbnail string // Image URL, blank if unknown
+}
+
+// FromVideo builds the message for a downloaded video, using the thumbnail URL from its metadata if present.
+func FromVideo(c *models.Channel, v *models.Video) Message {
+	m := Message{Title: v.Title, Channel: c.Name, URL: v.URL}
+	if thumb, ok := v.MetadataMap["thumbnail"].(string); ok {
+		m.Thumbnail = thumb
+	}
+	if m.Title == "" {
+		m.Title = v.URL
+	}
+	return m
+}
+
+// Parse splits a stored notification URL into its chat provider and target URL.
+//
+// Returns false if the notification URL is not a chat target.
+func Parse(notifyURL string) (provider, target string, ok bool) {
+	provider, target, ok = strings.Cut(notifyURL, ":")
+	if !ok {
+		return "", "", false
+	}
+	switch provider {
+	case Discord, Telegram, Ntfy:
+		return provider, target, true
+	}
+	return "", "", false
+}
+
+// DiscordURL checks a Discord webhook URL and returns it as a notification URL.
+//
+// The webhook is fetched to confirm it exists.
+func DiscordURL(webhookURL string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" || !strings.HasPrefix(u.Path, discordPrefix) ||
+		(u.Hostname() != "discord.com" && u.Hostname() != "discordapp.com" && !strings.HasSuffix(u.Hostname(), ".discord.com")) {
+		return "", fmt.Errorf("invalid Discord webhook URL %q, expected https://discord.com/api/webhooks/<id>/<token>", webhookURL)
+	}
+	if err := check(webhookURL, "Discord webhook"); err != nil {
+		return "", err
+	}
+	return Discord + ":" + webhookURL, nil
+}
+
+// TelegramURL checks a Telegram bot token and returns the bot and chat as a notification URL.
+//
+// The bot is looked up to confirm the token is valid.
+func TelegramURL(botToken, chatID string) (string, error) {
+	if botToken == "" || chatID == "" {
+		return "", errors.New("telegram bot token and chat ID are required")
+	}
+	if strings.ContainsAny(botToken, "/?#") {
+		return "", errors.New("invalid Telegram bot token")
+	}
+	if err := check(telegramAPI+"/bot"+botToken+"/getMe", "Telegram bot"); err != nil {
+		return "", err
+	}
+	return Telegram + ":" + TelegramTarget(botToken, chatID), nil
+}
+
+// TelegramTarget returns the send target for a Telegram bot and chat.
+func TelegramTarget(botToken, chatID string) string {
+	return telegramAPI + "/bot" + botToken + "?" + url.Values{"chat_id": {chatID}}.Encode()
+}
+
+// NtfyURL checks an ntfy topic and returns it as a notification URL, on ntfy.sh if server is blank.
+func NtfyURL(server, topic string) (string, error) {
+	if server == "" {
+		server = DefaultNtfy
+	}
+	u, err := url.Parse(strings.TrimRight(server, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid ntfy server %q", server)
+	}
+	if topic == "" || strings.ContainsAny(topic, "/?#") {
+		return "", fmt.Errorf("invalid ntfy topic %q", topic)
+	}
+	return Ntfy + ":" + u.String() + "/" + topic, nil
+}
+
+// Send delivers the message to a chat target.
+func Send(client *http.Client, provider, target string, m Message) error {
+	switch provider {
+	case Discord:
+		return sendDiscord(client, target, m)
+	case Telegram:
+		return sendTelegram(client, target, m)
+	case Ntfy:
+		return sendNtfy(client, target, m)
+	default:
+		return fmt.Errorf("unsupported chat provider %q", provider)
+	}
+}
+
+// sendDiscord posts the message as an embed with the thumbnail as its image.
+func sendDiscord(client *http.Client, target string, m Message) error {
+	embed := map[string]any{
+		"title":       m.Title,
+		"url":         m.URL,
+		"description": "New video from " + m.Channel,
+		"author":      map[string]string{"name": m.Channel},
+	}
+	if m.Thumbnail != "" {
+		embed["image"] = map[string]string{"url": m.Thumbnail}
+	}
+	body, err := json.Marshal(map[string]any{"embeds": []any{embed}})
+	if err != nil {
+		return err
+	}
+	return post(client, target, "application/json", body, nil)
+}
+
+// sendTelegram sends the message as a photo with a caption, or as text if there is no thumbnail.
+func sendTelegram(client *http.Client, target string, m Message) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid Telegram target: %w", err)
+	}
+	chatID := u.Query().Get("chat_id")
+	u.RawQuery = ""
+
+	text := fmt.Sprintf("<b>%s</b>\n%s\n%s", html.EscapeString(m.Title), html.EscapeString(m.Channel), html.EscapeString(m.URL))
+	payload := map[string]any{"chat_id": chatID, "parse_mode": "HTML"}
+	method := "/sendMessage"
+	if m.Thumbnail != "" {
+		method = "/sendPhoto"
+		payload["photo"], payload["caption"] = m.Thumbnail, text
+	} else {
+		payload["text"] = text
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return post(client, u.String()+method, "application/json", body, nil)
+}
+
+// sendNtfy publishes the message with the video as its click action and the thumbnail attached.
+func sendNtfy(client *http.Client, target string, m Message) error {
+	headers := map[string]string{
+		"Title": "New video from " + m.Channel,
+		"Click": m.URL,
+		"Tags":  "tv",
+	}
+	if m.Thumbnail != "" {
+		headers["Attach"] = m.Thumbnail
+	}
+	return post(client, target, "text/plain; charset=utf-8", []byte(m.Title), headers)
+}
+
+// post sends a request body to the URL, failing on error statuses.
+func post(client *http.Client, target, contentType string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		// Header values must not contain line breaks
+		req.Header.Set(k, strings.Join(strings.Fields(v), " "))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.E(0, "Failed to close HTTP response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("chat service returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// check fetches the URL, failing unless it returns a success status.
+func check(target, what string) error {
+	resp, err := checkClient.Get(target)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", what, redact(err))
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.E(0, "Failed to close HTTP response body: %v", err)
+		}
+	}()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%s check failed with status %d, check the token or URL", what, resp.StatusCode)
+	}
+	return nil
+}
+
+// redact removes the request URL from HTTP client errors, as it holds the token.
+func redact(err error) error {
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return uerr.Err
+	}
+	return err
+}
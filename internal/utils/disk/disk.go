@@ -0,0 +1,24 @@
+// Package disk reports filesystem capacity and formats file sizes.
+package disk
+
+import "fmt"
+
+// Space holds the capacity of the filesystem containing a path.
+type Space struct {
+	Total uint64
+	Free  uint64
+}
+
+// FormatBytes returns a human readable size.
+func FormatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package disk
+
+import "errors"
+
+// FreeSpace is not supported on this platform.
+func FreeSpace(path string) (Space, error) {
+	return Space{}, errors.New("free space reporting is not supported on this platform")
+}
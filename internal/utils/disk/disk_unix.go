@@ -0,0 +1,18 @@
+//go:build unix
+
+package disk
+
+import "syscall"
+
+// FreeSpace returns the total and available bytes of the filesystem containing path.
+func FreeSpace(path string) (Space, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Space{}, err
+	}
+	bsize := uint64(st.Bsize)
+	return Space{
+		Total: uint64(st.Blocks) * bsize,
+		Free:  uint64(st.Bavail) * bsize,
+	}, nil
+}
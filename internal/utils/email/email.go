@@ -0,0 +1,270 @@
+// Package email renders and sends email notifications over SMTP.
+package email
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"tubarr/internal/auth"
+	"tubarr/internal/models"
+)
+
+// TLS modes for the SMTP connection.
+const (
+	TLSStartTLS = "starttls" // Upgrade a plain connection, usually port 587
+	TLSImplicit = "tls"      // TLS from the start, usually port 465
+	TLSNone     = "none"     // Unencrypted, only for local relays
+)
+
+// MailtoScheme marks channel notification URLs which are email recipients, such as "mailto:me@example.com".
+const MailtoScheme = "mailto:"
+
+// Default templates, overridden by the SMTP settings.
+const (
+	DefaultSubject = "{{.Title}}"
+	DefaultBody    = "{{.Text}}\n"
+)
+
+const dialTimeout = 30 * time.Second
+
+// Data holds the fields available to subject and body templates.
+type Data struct {
+	Title   string
+	Text    string
+	Channel string              // Blank for digests
+	Videos  []Video             // Blank for digests
+	Report  *models.CrawlReport // Set for digests
+}
+
+// Video is a downloaded video in an email notification.
+type Video struct {
+	Title string
+	URL   string
+	Path  string
+}
+
+// FromVideos builds the template data for a channel's downloaded videos.
+func FromVideos(c *models.Channel, videos []*models.Video) Data {
+	d := Data{Channel: c.Name}
+	var b strings.Builder
+	for _, v := range videos {
+		d.Videos = append(d.Videos, Video{Title: v.Title, URL: v.URL, Path: v.VideoPath})
+		fmt.Fprintf(&b, "%s\n  %s\n", v.Title, v.URL)
+	}
+	d.Title = fmt.Sprintf("Tubarr: %d new videos from %s", len(videos), c.Name)
+	d.Text = strings.TrimSuffix(b.String(), "\n")
+	return d
+}
+
+// FromReport builds the template data for a crawl pass digest.
+func FromReport(r *models.CrawlReport) Data {
+	t := r.Totals()
+	d := Data{
+		Title:  fmt.Sprintf("Tubarr crawl: %d new videos, %d failures, %d filtered", t.NewVideos, t.Failures, t.Filtered),
+		Report: r,
+	}
+	if failed := r.FailedChannels(); failed > 0 {
+		d.Title += fmt.Sprintf(", %d channels failed", failed)
+	}
+
+	var b strings.Builder
+	for _, c := range r.Channels {
+		fmt.Fprintf(&b, "%s: %d new, %d failed, %d filtered\n", c.ChannelName, c.NewVideos, c.Failures, c.Filtered)
+		if c.Error != "" {
+			fmt.Fprintf(&b, "  Error: %s\n", c.Error)
+		}
+	}
+	d.Text = strings.TrimSuffix(b.String(), "\n")
+	return d
+}
+
+// Recipients returns the addresses of mailto notification URLs.
+func Recipients(notifyURLs []string) []string {
+	var to []string
+	for _, u := range notifyURLs {
+		if addr, ok := strings.CutPrefix(u, MailtoScheme); ok {
+			to = append(to, addr)
+		}
+	}
+	return to
+}
+
+// Validate checks the SMTP settings are complete and their templates parse.
+func Validate(c *models.SMTPConfig) error {
+	switch {
+	case c.Host == "":
+		return errors.New("SMTP host is not set")
+	case c.Port < 1 || c.Port > 65535:
+		return fmt.Errorf("invalid SMTP port %d", c.Port)
+	case c.From == "":
+		return errors.New("SMTP sender address is not set")
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", c.From, err)
+	}
+	switch c.TLS {
+	case "", TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return fmt.Errorf("invalid TLS mode %q, expected %s, %s, or %s", c.TLS, TLSStartTLS, TLSImplicit, TLSNone)
+	}
+	if _, err := parse(c.Subject, DefaultSubject); err != nil {
+		return fmt.Errorf("invalid subject template: %w", err)
+	}
+	if _, err := parse(c.Body, DefaultBody); err != nil {
+		return fmt.Errorf("invalid body template: %w", err)
+	}
+	return nil
+}
+
+// ValidateAddresses checks each address is a valid email address.
+func ValidateAddresses(addrs []string) error {
+	for _, a := range addrs {
+		if _, err := mail.ParseAddress(a); err != nil {
+			return fmt.Errorf("invalid email address %q: %w", a, err)
+		}
+	}
+	return nil
+}
+
+// Send renders the subject and body templates with the data and mails them to the recipients.
+func Send(c *models.SMTPConfig, to []string, d Data) error {
+	if len(to) == 0 {
+		return nil
+	}
+	if err := Validate(c); err != nil {
+		return err
+	}
+
+	subject, err := render(c.Subject, DefaultSubject, d)
+	if err != nil {
+		return fmt.Errorf("failed to render subject: %w", err)
+	}
+	body, err := render(c.Body, DefaultBody, d)
+	if err != nil {
+		return fmt.Errorf("failed to render body: %w", err)
+	}
+
+	client, err := dial(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.Username != "" {
+		password, err := auth.ResolveSecret(c.Password)
+		if err != nil {
+			return err
+		}
+		if err := client.Auth(smtp.PlainAuth("", c.Username, password, c.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	// The envelope takes bare addresses, without display names
+	from, err := mail.ParseAddress(c.From)
+	if err != nil {
+		return err
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected sender %q: %w", c.From, err)
+	}
+	for _, addr := range to {
+		rcpt, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("invalid email address %q: %w", addr, err)
+		}
+		if err := client.Rcpt(rcpt.Address); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %q: %w", addr, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message(c.From, to, subject, body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected message: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial connects to the SMTP server, with TLS as configured.
+func dial(c *models.SMTPConfig) (*smtp.Client, error) {
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	tlsConfig := &tls.Config{ServerName: c.Host}
+
+	if c.TLS == TLSImplicit {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", addr, tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to SMTP server %q: %w", addr, err)
+		}
+		return smtp.NewClient(conn, c.Host)
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server %q: %w", addr, err)
+	}
+	client, err := smtp.NewClient(conn, c.Host)
+	if err != nil {
+		return nil, err
+	}
+	if c.TLS != TLSNone {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to start TLS with SMTP server %q: %w", addr, err)
+		}
+	}
+	return client, nil
+}
+
+// message builds a plain text email.
+func message(from string, to []string, subject, body string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", encodeHeader(subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes()
+}
+
+// encodeHeader strips line breaks from a header value, encoding it if it has non-ASCII characters.
+func encodeHeader(s string) string {
+	return mime.BEncoding.Encode("UTF-8", strings.Join(strings.Fields(s), " "))
+}
+
+// parse parses a template, falling back to def if it is empty.
+func parse(tmpl, def string) (*template.Template, error) {
+	if tmpl == "" {
+		tmpl = def
+	}
+	return template.New("email").Option("missingkey=error").Parse(tmpl)
+}
+
+// render executes a template with the data.
+func render(tmpl, def string, d Data) (string, error) {
+	t, err := parse(tmpl, def)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, d); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,203 @@
+// Package gotify sends push notifications to Gotify servers.
+//
+// Targets are stored as notification URLs with the "gotify:" prefix, holding the server's message
+// endpoint, the application token, and which events to push at what priority.
+package gotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"tubarr/internal/utils/logging"
+)
+
+// Scheme marks channel notification URLs which are Gotify targets.
+const Scheme = "gotify:"
+
+// Events which can be pushed.
+const (
+	EventVideo   = "new-video"       // A video was downloaded
+	EventFailure = "crawl-failure"   // A channel crawl failed
+	EventBlocked = "channel-blocked" // A channel hit a bot block or login challenge
+)
+
+// Events returns every event, in display order.
+func Events() []string {
+	return []string{EventVideo, EventFailure, EventBlocked}
+}
+
+// Default priorities, on Gotify's 0-10 scale.
+const (
+	DefaultPriority      = 5
+	DefaultErrorPriority = 8
+)
+
+const (
+	messagePath   = "/message"
+	versionPath   = "/version"
+	tokenParam    = "token"
+	eventsParam   = "events"
+	priorityParam = "priority"
+	errorParam    = "error-priority"
+)
+
+var checkClient = &http.Client{Timeout: 10 * time.Second}
+
+// Target is a Gotify server and application to push to.
+type Target struct {
+	Server        string
+	Token         string
+	Events        []string
+	Priority      int // For new videos
+	ErrorPriority int // For failures and blocks
+}
+
+// Message is a push notification.
+type Message struct {
+	Title    string
+	Text     string
+	ClickURL string // Opened when the notification is clicked, blank for none
+	ImageURL string // Shown in the notification, blank for none
+}
+
+// URL returns the target as a notification URL.
+func (t *Target) URL() string {
+	q := url.Values{
+		tokenParam:    {t.Token},
+		eventsParam:   {strings.Join(t.Events, ",")},
+		priorityParam: {strconv.Itoa(t.Priority)},
+		errorParam:    {strconv.Itoa(t.ErrorPriority)},
+	}
+	return Scheme + strings.TrimRight(t.Server, "/") + messagePath + "?" + q.Encode()
+}
+
+// Wants reports whether the target pushes the event.
+func (t *Target) Wants(event string) bool {
+	return slices.Contains(t.Events, event)
+}
+
+// Parse reads a Gotify target from a notification URL, returning false if it is not one.
+func Parse(notifyURL string) (*Target, bool) {
+	raw, ok := strings.CutPrefix(notifyURL, Scheme)
+	if !ok {
+		return nil, false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, false
+	}
+	q := u.Query()
+	t := &Target{
+		Token:         q.Get(tokenParam),
+		Priority:      DefaultPriority,
+		ErrorPriority: DefaultErrorPriority,
+	}
+	if events := q.Get(eventsParam); events != "" {
+		t.Events = strings.Split(events, ",")
+	}
+	if p, err := strconv.Atoi(q.Get(priorityParam)); err == nil {
+		t.Priority = p
+	}
+	if p, err := strconv.Atoi(q.Get(errorParam)); err == nil {
+		t.ErrorPriority = p
+	}
+	u.RawQuery = ""
+	t.Server = strings.TrimSuffix(u.String(), messagePath)
+	return t, true
+}
+
+// Validate checks the target's settings, and that the server is reachable.
+func Validate(t *Target) error {
+	u, err := url.Parse(t.Server)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid Gotify server %q", t.Server)
+	}
+	if t.Token == "" {
+		return fmt.Errorf("an application token is required for Gotify server %q", t.Server)
+	}
+	if len(t.Events) == 0 {
+		return fmt.Errorf("no events chosen for Gotify, expected any of %v", Events())
+	}
+	for _, e := range t.Events {
+		if !slices.Contains(Events(), e) {
+			return fmt.Errorf("invalid Gotify event %q, expected any of %v", e, Events())
+		}
+	}
+	for _, p := range []int{t.Priority, t.ErrorPriority} {
+		if p < 0 || p > 10 {
+			return fmt.Errorf("invalid Gotify priority %d, expected 0 to 10", p)
+		}
+	}
+
+	// Application tokens can only post messages, so only the server is checked
+	resp, err := checkClient.Get(strings.TrimRight(t.Server, "/") + versionPath)
+	if err != nil {
+		return fmt.Errorf("failed to reach Gotify server %q: %w", t.Server, err)
+	}
+	defer closeBody(resp)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gotify server %q returned status %d", t.Server, resp.StatusCode)
+	}
+	return nil
+}
+
+// Send pushes the message for the event, at the target's priority for that event.
+func Send(client *http.Client, t *Target, event string, m Message) error {
+	priority := t.Priority
+	if event != EventVideo {
+		priority = t.ErrorPriority
+	}
+
+	payload := map[string]any{
+		"title":    m.Title,
+		"message":  m.Text,
+		"priority": priority,
+	}
+	notification := map[string]any{}
+	if m.ClickURL != "" {
+		notification["click"] = map[string]string{"url": m.ClickURL}
+	}
+	if m.ImageURL != "" {
+		notification["bigImageUrl"] = m.ImageURL
+	}
+	if len(notification) > 0 {
+		payload["extras"] = map[string]any{"client::notification": notification}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(t.Server, "/")+messagePath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid Gotify server %q: %w", t.Server, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", t.Token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Gotify server %q: %w", t.Server, err)
+	}
+	defer closeBody(resp)
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("gotify server %q returned status %d: %s", t.Server, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// closeBody closes a response body, logging failures.
+func closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		logging.E(0, "Failed to close HTTP response body: %v", err)
+	}
+}
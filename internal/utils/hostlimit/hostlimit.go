@@ -0,0 +1,87 @@
+// Package hostlimit spaces out requests to each host, shared by every channel's metadata fetches and downloads.
+package hostlimit
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/parsing"
+	"tubarr/internal/utils/logging"
+)
+
+var (
+	mu   sync.Mutex
+	next = make(map[string]time.Time) // Earliest start of each limited host's next request
+)
+
+// Wait blocks until a request to the URL's host may start under its requests per minute limit.
+//
+// A limit set for a domain is shared by its subdomains. Returns the context's error if cancelled while waiting.
+func Wait(ctx context.Context, rawURL string) error {
+	key, rpm := limitFor(rawURL)
+	if rpm <= 0 {
+		return nil
+	}
+	interval := time.Minute / time.Duration(rpm)
+
+	mu.Lock()
+	now := time.Now()
+	at := next[key]
+	if at.Before(now) {
+		at = now
+	}
+	next[key] = at.Add(interval)
+	mu.Unlock()
+
+	wait := at.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	logging.D(1, "Waiting %v before request to %q (limit %d per minute)", wait.Round(time.Millisecond), key, rpm)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// limitFor returns the key a URL's requests are spaced under and its requests per minute, 0 if unlimited.
+//
+// The most specific configured domain covering the host wins, then the limit for all hosts.
+func limitFor(rawURL string) (key string, rpm int) {
+	entries := cfg.GetStringSlice(keys.HostRateLimit)
+	if len(entries) == 0 {
+		return "", 0
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return "", 0
+	}
+	rates, err := parsing.ParseHostRates(entries)
+	if err != nil {
+		logging.E(0, "Ignoring host rate limits: %v", err)
+		return "", 0
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for h := host; h != ""; {
+		if rpm, ok := rates[h]; ok {
+			return h, rpm
+		}
+		_, parent, found := strings.Cut(h, ".")
+		if !found {
+			break
+		}
+		h = parent
+	}
+	return host, rates[parsing.AllHosts]
+}
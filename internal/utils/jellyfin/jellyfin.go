@@ -0,0 +1,122 @@
+// Package jellyfin triggers library refreshes on Jellyfin and Emby servers.
+//
+// Emby and Jellyfin share an API, differing only in Emby's "/emby" path prefix.
+package jellyfin
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"tubarr/internal/utils/logging"
+)
+
+// Server types.
+const (
+	TypeJellyfin = "jellyfin"
+	TypeEmby     = "emby"
+)
+
+const (
+	tokenHeader = "X-Emby-Token"
+	keyParam    = "api_key"
+	embyPrefix  = "/emby"
+	infoPath    = "/System/Info"
+	refreshPath = "/Library/Refresh"
+)
+
+var (
+	client = &http.Client{Timeout: 10 * time.Second}
+
+	// insecureClient is for servers with self-signed certificates, only used when asked for
+	insecureClient = &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+)
+
+// displayName returns the server type as shown to users.
+func displayName(kind string) string {
+	if kind == TypeEmby {
+		return "Emby"
+	}
+	return "Jellyfin"
+}
+
+// baseURL returns the server API root for the given server type.
+func baseURL(kind, server string) (string, error) {
+	base := strings.TrimRight(server, "/")
+	switch kind {
+	case TypeJellyfin:
+		return base, nil
+	case TypeEmby:
+		return base + embyPrefix, nil
+	default:
+		return "", fmt.Errorf("unsupported server type %q, expected %q or %q", kind, TypeJellyfin, TypeEmby)
+	}
+}
+
+// CheckServer verifies the server is reachable and accepts the API key, returning the server's name.
+//
+// The server's certificate is verified unless insecure is set.
+func CheckServer(kind, server, apiKey string, insecure bool) (string, error) {
+	base, err := baseURL(kind, server)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, base+infoPath, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s server %q: %w", displayName(kind), server, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(tokenHeader, apiKey)
+
+	c := client
+	if insecure {
+		c = insecureClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach %s server %q: %w", displayName(kind), server, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.E(0, "Failed to close HTTP response body: %v", err)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return "", fmt.Errorf("%s server %q rejected the API key", displayName(kind), server)
+	case resp.StatusCode >= 400:
+		return "", fmt.Errorf("%s server %q returned status %d", displayName(kind), server, resp.StatusCode)
+	}
+
+	var info struct {
+		ServerName string `json:"ServerName"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", fmt.Errorf("failed to decode %s server info: %w", displayName(kind), err)
+	}
+	return info.ServerName, nil
+}
+
+// RefreshURL builds the URL which triggers a refresh of all libraries.
+//
+// The refresh endpoint expects a POST, which is how non-Plex notification URLs are sent.
+func RefreshURL(kind, server, apiKey string) (string, error) {
+	base, err := baseURL(kind, server)
+	if err != nil {
+		return "", err
+	}
+	return base + refreshPath + "?" + keyParam + "=" + url.QueryEscape(apiKey), nil
+}
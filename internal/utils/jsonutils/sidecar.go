@@ -0,0 +1,52 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RewriteSidecar sets the given fields in a JSON sidecar file, leaving other fields intact.
+func RewriteSidecar(path string, fields map[string]any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read JSON file %q: %w", path, err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("invalid JSON in file %q: %w", path, err)
+	}
+	if m == nil {
+		m = make(map[string]any, len(fields))
+	}
+	for k, v := range fields {
+		m[k] = v
+	}
+
+	if data, err = json.Marshal(m); err != nil {
+		return fmt.Errorf("failed to marshal JSON for file %q: %w", path, err)
+	}
+
+	// Write beside the original and rename, so a failed write never truncates the sidecar
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tubarr-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %q: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write JSON file %q: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write JSON file %q: %w", tmp.Name(), err)
+	}
+	if info, err := os.Stat(path); err == nil {
+		if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to set permissions on %q: %w", tmp.Name(), err)
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -84,6 +84,11 @@ func SetupLogging(targetDir string) error {
 	return nil
 }
 
+// SetConsole sets where console messages are written, such as stderr to keep stdout clean for scripts.
+func SetConsole(f *os.File) {
+	console = f
+}
+
 // writeToConsole writes messages to console without using zerolog (zerolog parses JSON, inefficient).
 func writeToConsole(msg string) {
 	timestamp := time.Now().Format(timeFormat)
@@ -0,0 +1,99 @@
+// Package plex queries Plex Media Server for library details.
+package plex
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	tokenParam    = "X-Plex-Token"
+	sectionsPath  = "/library/sections"
+	refreshSuffix = "/refresh"
+)
+
+// Section is a Plex library section.
+type Section struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+	Type  string `json:"type"`
+}
+
+var (
+	client = &http.Client{Timeout: 10 * time.Second}
+
+	// insecureClient is for servers with self-signed certificates, only used when asked for
+	insecureClient = &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+)
+
+// FetchSections returns the library sections on a Plex server.
+//
+// The server's certificate is verified unless insecure is set.
+func FetchSections(server, token string, insecure bool) ([]Section, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(server, "/")+sectionsPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Plex server %q: %w", server, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(tokenParam, token)
+
+	c := client
+	if insecure {
+		c = insecureClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Plex server %q: %w", server, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.E(0, "Failed to close HTTP response body: %v", err)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, fmt.Errorf("plex server %q rejected the token", server)
+	case resp.StatusCode >= 400:
+		return nil, fmt.Errorf("plex server %q returned status %d", server, resp.StatusCode)
+	}
+
+	var body struct {
+		MediaContainer struct {
+			Directory []Section `json:"Directory"`
+		} `json:"MediaContainer"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode Plex library sections: %w", err)
+	}
+	return body.MediaContainer.Directory, nil
+}
+
+// RefreshURL builds the URL which triggers a scan of a library section.
+func RefreshURL(server, token, sectionKey string) string {
+	return strings.TrimRight(server, "/") + sectionsPath + "/" + url.PathEscape(sectionKey) + refreshSuffix +
+		"?" + tokenParam + "=" + url.QueryEscape(token)
+}
+
+// IsRefreshURL reports whether a URL is a Plex library section refresh URL.
+//
+// Plex expects these as GET requests rather than the POST used for other notifications.
+func IsRefreshURL(u *url.URL) bool {
+	return strings.HasPrefix(u.Path, sectionsPath+"/") &&
+		strings.HasSuffix(u.Path, refreshSuffix) &&
+		u.Query().Get(tokenParam) != ""
+}
@@ -0,0 +1,74 @@
+// Package proxy picks proxies for yt-dlp, gallery-dl, and Tubarr's own HTTP requests.
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/keys"
+)
+
+var (
+	mu      sync.Mutex
+	next    = make(map[string]int)
+	clients = make(map[string]*http.Client)
+)
+
+// Pick returns the next proxy from the channel's list, or the global list if the channel has none.
+//
+// Lists are rotated round-robin, each call moving to the next entry. An empty string means no proxy.
+func Pick(channelProxies []string) string {
+	list := channelProxies
+	if len(list) == 0 {
+		list = cfg.GetStringSlice(keys.Proxy)
+	}
+	switch len(list) {
+	case 0:
+		return ""
+	case 1:
+		return list[0]
+	}
+
+	key := strings.Join(list, ",")
+	mu.Lock()
+	defer mu.Unlock()
+	i := next[key] % len(list)
+	next[key] = i + 1
+	return list[i]
+}
+
+// Func returns a proxy function for an HTTP transport, or nil for no proxy.
+func Func(proxyURL string) func(*http.Request) (*url.URL, error) {
+	if proxyURL == "" {
+		return nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return func(*http.Request) (*url.URL, error) {
+			return nil, fmt.Errorf("invalid proxy %q: %w", proxyURL, err)
+		}
+	}
+	return http.ProxyURL(u)
+}
+
+// Client returns an HTTP client routed through the proxy, shared by every caller using the same proxy.
+func Client(proxyURL string, timeout time.Duration) *http.Client {
+	mu.Lock()
+	defer mu.Unlock()
+
+	key := proxyURL + "|" + timeout.String()
+	if c, ok := clients[key]; ok {
+		return c
+	}
+	c := &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{Proxy: Func(proxyURL)},
+	}
+	clients[key] = c
+	return c
+}
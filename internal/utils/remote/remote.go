@@ -0,0 +1,74 @@
+// Package remote sends commands to the HTTP API of a running Tubarr instance.
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"tubarr/internal/utils/logging"
+)
+
+// TokenEnv is the environment variable holding a session token, used when the flag isn't set.
+const TokenEnv = "TUBARR_API_TOKEN"
+
+const maxErrorBody = 1 << 10
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// BaseURL returns the URL to reach a listen address on, using the loopback address for unspecified hosts.
+func BaseURL(addr string) string {
+	if strings.Contains(addr, "://") {
+		return strings.TrimRight(addr, "/")
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// Call sends a request to the API at addr, decoding the JSON response into out if it isn't nil.
+func Call(addr, token, method, path string, out any) error {
+	u := BaseURL(addr) + path
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return fmt.Errorf("invalid API request %q: %w", u, err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach running Tubarr instance at %q: %w", addr, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.E(0, "Failed to close HTTP response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		msg := strings.TrimSpace(string(body))
+		if resp.StatusCode == http.StatusUnauthorized {
+			msg += fmt.Sprintf(" (set a session token from /api/login with --api-token or %s)", TokenEnv)
+		}
+		return fmt.Errorf("running Tubarr instance returned %s: %s", resp.Status, msg)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response from running Tubarr instance: %w", err)
+	}
+	return nil
+}
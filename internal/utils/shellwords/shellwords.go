@@ -0,0 +1,72 @@
+// Package shellwords splits command lines into arguments the way a POSIX shell does, without running one.
+package shellwords
+
+import (
+	"errors"
+	"strings"
+)
+
+// Split splits a command line into arguments, honoring single and double quotes and backslash escapes.
+//
+// Nothing is expanded: variables, globs, and command substitutions are kept as written.
+func Split(line string) ([]string, error) {
+	var (
+		args    []string
+		b       strings.Builder
+		quote   rune
+		escaped bool
+		inToken bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			// Inside double quotes a backslash only escapes characters the shell treats specially there
+			if quote == '"' && !strings.ContainsRune("\"\\$`\n", r) {
+				b.WriteRune('\\')
+			}
+			if r != '\n' {
+				b.WriteRune(r)
+				inToken = true
+			}
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+				continue
+			}
+			b.WriteRune(r)
+		case r == '\\':
+			escaped = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+				continue
+			}
+			b.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inToken {
+				args = append(args, b.String())
+				b.Reset()
+				inToken = false
+			}
+		default:
+			b.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inToken {
+		args = append(args, b.String())
+	}
+	return args, nil
+}
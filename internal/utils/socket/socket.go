@@ -0,0 +1,57 @@
+// Package socket inherits listening sockets passed by systemd socket activation.
+package socket
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	"tubarr/internal/utils/logging"
+)
+
+// listenFDsStart is the first file descriptor systemd passes sockets on.
+const listenFDsStart = 3
+
+var (
+	inheritOnce sync.Once
+	inherited   net.Listener
+	inheritErr  error
+)
+
+// Activated reports whether systemd passed this process a listening socket.
+func Activated() bool {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return false
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	return err == nil && n > 0
+}
+
+// Inherited returns the listening socket passed by systemd, or nil if none was passed.
+//
+// Only the first socket is used. Later calls return the same listener, and the activation
+// variables are cleared so programs Tubarr runs don't try to inherit it too.
+func Inherited() (net.Listener, error) {
+	inheritOnce.Do(func() {
+		if !Activated() {
+			return
+		}
+		if n, _ := strconv.Atoi(os.Getenv("LISTEN_FDS")); n > 1 {
+			logging.W("systemd passed %d sockets, only the first is used", n)
+		}
+		for _, v := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+			if err := os.Unsetenv(v); err != nil {
+				logging.E(0, "Failed to clear %s: %v", v, err)
+			}
+		}
+
+		f := os.NewFile(listenFDsStart, "systemd socket")
+		inherited, inheritErr = net.FileListener(f)
+		if err := f.Close(); err != nil {
+			logging.E(0, "Failed to close inherited socket descriptor: %v", err)
+		}
+	})
+	return inherited, inheritErr
+}
@@ -0,0 +1,174 @@
+// Package webhook renders and sends generic webhook notifications.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"tubarr/internal/models"
+	"tubarr/internal/utils/logging"
+)
+
+// DefaultPayload is used when a webhook has no payload template of its own.
+const DefaultPayload = `{"title": {{json .Title}}, "channel": {{json .Channel}}, "url": {{json .URL}}, "path": {{json .Path}}}`
+
+// Payload holds the fields available to webhook payload templates.
+type Payload struct {
+	Title      string
+	Channel    string
+	ChannelURL string
+	URL        string
+	Path       string
+	UploadDate time.Time
+	Warning    string
+}
+
+var methods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// FromVideo builds the template payload for a downloaded video.
+func FromVideo(c *models.Channel, v *models.Video) Payload {
+	return Payload{
+		Title:      v.Title,
+		Channel:    c.Name,
+		ChannelURL: c.URL,
+		URL:        v.URL,
+		Path:       v.VideoPath,
+		UploadDate: v.UploadDate,
+	}
+}
+
+// FromWarning builds the template payload for a channel warning, with the message as the title.
+func FromWarning(c *models.Channel, msg string) Payload {
+	return Payload{
+		Title:      msg,
+		Channel:    c.Name,
+		ChannelURL: c.URL,
+		URL:        c.URL,
+		Warning:    msg,
+	}
+}
+
+// Validate checks the method is supported and that the payload template renders to valid JSON.
+func Validate(method, payload string) error {
+	if !methods[strings.ToUpper(method)] {
+		return fmt.Errorf("unsupported webhook method %q", method)
+	}
+	_, err := Render(payload, Payload{
+		Title:      "Example title",
+		Channel:    "Example channel",
+		ChannelURL: "https://example.com/channel",
+		URL:        "https://example.com/video",
+		Path:       "/videos/example.mp4",
+		UploadDate: time.Now(),
+	})
+	return err
+}
+
+// Render executes the payload template, falling back to DefaultPayload if it is empty.
+func Render(payload string, p Payload) ([]byte, error) {
+	if payload == "" {
+		payload = DefaultPayload
+	}
+	tmpl, err := template.New("payload").Funcs(funcs).Option("missingkey=error").Parse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook payload template: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, p); err != nil {
+		return nil, fmt.Errorf("failed to render webhook payload: %w", err)
+	}
+	if !json.Valid(b.Bytes()) {
+		return nil, errors.New("webhook payload template did not render valid JSON")
+	}
+	return b.Bytes(), nil
+}
+
+// Send delivers a rendered payload to the webhook. GET requests are sent without a body.
+func Send(client *http.Client, h *models.Webhook, body []byte) error {
+	method := strings.ToUpper(h.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var reader io.Reader
+	if method != http.MethodGet {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, h.URL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.E(0, "Failed to close HTTP response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Digest builds the JSON body summarizing a crawl pass, with a title and text for chat services and the full report.
+func Digest(r *models.CrawlReport) ([]byte, error) {
+	t := r.Totals()
+	title := fmt.Sprintf("Tubarr crawl: %d new videos, %d failures, %d filtered", t.NewVideos, t.Failures, t.Filtered)
+	if failed := r.FailedChannels(); failed > 0 {
+		title += fmt.Sprintf(", %d channels failed", failed)
+	}
+
+	var b strings.Builder
+	for _, c := range r.Channels {
+		fmt.Fprintf(&b, "%s: %d new, %d failed, %d filtered", c.ChannelName, c.NewVideos, c.Failures, c.Filtered)
+		if c.Error != "" {
+			fmt.Fprintf(&b, " (%s)", c.Error)
+		}
+		b.WriteByte('\n')
+	}
+
+	return json.Marshal(map[string]any{
+		"title":       title,
+		"text":        strings.TrimSuffix(b.String(), "\n"),
+		"started_at":  r.StartedAt,
+		"finished_at": r.FinishedAt,
+		"new_videos":  t.NewVideos,
+		"failures":    t.Failures,
+		"filtered":    t.Filtered,
+		"failed":      r.FailedChannels(),
+		"channels":    r.Channels,
+	})
+}
@@ -0,0 +1,132 @@
+// Package whisper generates subtitles for downloaded videos with whisper.cpp.
+//
+// The video's audio is converted by ffmpeg to the 16kHz mono WAV whisper.cpp reads, then transcribed
+// to an SRT file next to the video.
+package whisper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"tubarr/internal/utils/logging"
+)
+
+// DefaultBin is the whisper.cpp command line tool, searched for in $PATH.
+const DefaultBin = "whisper-cli"
+
+// AutoLanguage has whisper.cpp detect the spoken language.
+const AutoLanguage = "auto"
+
+const (
+	ffmpegBin   = "ffmpeg"
+	srtExt      = ".srt"
+	waitDelay   = 5 * time.Second
+	maxErrLines = 3
+)
+
+// languageRx matches whisper.cpp language codes such as "en" or "yue".
+var languageRx = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// ValidateLanguage checks a language is blank, "auto", or a language code.
+func ValidateLanguage(lang string) error {
+	if lang == "" || lang == AutoLanguage || languageRx.MatchString(lang) {
+		return nil
+	}
+	return fmt.Errorf("invalid transcription language %q, expected %q or a code such as \"en\"", lang, AutoLanguage)
+}
+
+// ValidateModel checks a whisper.cpp model file exists.
+func ValidateModel(model string) error {
+	info, err := os.Stat(model)
+	if err != nil {
+		return fmt.Errorf("whisper model %q not found: %w", model, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("whisper model %q is a directory, expected a ggml model file", model)
+	}
+	return nil
+}
+
+// SubtitlePath returns where the subtitles for a video are written.
+//
+// A set language is added before the extension, as media servers expect.
+func SubtitlePath(videoPath, lang string) string {
+	base := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	if lang != "" && lang != AutoLanguage {
+		base += "." + lang
+	}
+	return base + srtExt
+}
+
+// Transcribe writes SRT subtitles for the video with the whisper.cpp tool and model, returning their path.
+func Transcribe(ctx context.Context, bin, model, lang, videoPath string) (string, error) {
+	if bin == "" {
+		bin = DefaultBin
+	}
+	whisperPath, err := exec.LookPath(bin)
+	if err != nil {
+		return "", fmt.Errorf("whisper.cpp tool %q not found: %w", bin, err)
+	}
+	if _, err := exec.LookPath(ffmpegBin); err != nil {
+		return "", fmt.Errorf("ffmpeg is needed to extract audio for whisper.cpp: %w", err)
+	}
+	if err := ValidateModel(model); err != nil {
+		return "", err
+	}
+	if lang == "" {
+		lang = AutoLanguage
+	}
+
+	tmp, err := os.MkdirTemp("", "tubarr-whisper-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := os.RemoveAll(tmp); err != nil {
+			logging.E(0, "Failed to remove temporary directory %q: %v", tmp, err)
+		}
+	}()
+
+	wav := filepath.Join(tmp, "audio.wav")
+	if err := run(ctx, ffmpegBin, "-nostdin", "-v", "error", "-i", videoPath, "-vn", "-ac", "1", "-ar", "16000", "-c:a", "pcm_s16le", wav); err != nil {
+		return "", fmt.Errorf("failed to extract audio from %q: %w", videoPath, err)
+	}
+
+	srt := SubtitlePath(videoPath, lang)
+	started := time.Now()
+	if err := run(ctx, whisperPath, "-m", model, "-l", lang, "-f", wav, "-osrt", "-of", strings.TrimSuffix(srt, srtExt), "-np"); err != nil {
+		return "", fmt.Errorf("whisper.cpp failed for %q: %w", videoPath, err)
+	}
+	if _, err := os.Stat(srt); err != nil {
+		return "", fmt.Errorf("whisper.cpp wrote no subtitles for %q: %w", videoPath, err)
+	}
+	logging.D(1, "Transcribed %q in %v", videoPath, time.Since(started).Round(time.Second))
+	return srt, nil
+}
+
+// run runs a command, adding the end of its output to any error.
+func run(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = waitDelay
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) > maxErrLines {
+		lines = lines[len(lines)-maxErrLines:]
+	}
+	if msg := strings.TrimSpace(strings.Join(lines, " ")); msg != "" {
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+	return err
+}
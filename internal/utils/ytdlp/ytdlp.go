@@ -0,0 +1,100 @@
+// Package ytdlp builds yt-dlp subprocesses with program-wide options such as plugin directories.
+package ytdlp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"tubarr/internal/cfg"
+	"tubarr/internal/domain/cmdvideo"
+	"tubarr/internal/domain/keys"
+	"tubarr/internal/utils/logging"
+	"tubarr/internal/utils/ytdlpbin"
+)
+
+const (
+	pluginPackage    = "yt_dlp_plugins"
+	extractorPlugins = "[debug] Extractor Plugins:"
+)
+
+// pluginRecheck is how long a failed plugin check is reused before checking again.
+const pluginRecheck = time.Minute
+
+var pluginCheck struct {
+	mu      sync.Mutex
+	ok      bool
+	err     error
+	checked time.Time
+}
+
+// Command returns a yt-dlp command with the program-wide arguments placed before args.
+func Command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, ytdlpbin.Path(), append(globalArgs(), args...)...)
+}
+
+// globalArgs returns the yt-dlp arguments applied to every invocation.
+func globalArgs() []string {
+	if dir := cfg.GetString(keys.YTDLPPluginDir); dir != "" {
+		return []string{cmdvideo.PluginDirs, dir}
+	}
+	return nil
+}
+
+// CheckPlugins verifies that extractor plugins load from the configured plugin directory.
+//
+// A passing check is kept for the program run. A failure is returned for a minute then checked again, so
+// plugins fixed while the scheduler runs are picked up. Checks cut short by cancellation aren't kept.
+func CheckPlugins(ctx context.Context) error {
+	dir := cfg.GetString(keys.YTDLPPluginDir)
+	if dir == "" {
+		return nil
+	}
+
+	pluginCheck.mu.Lock()
+	defer pluginCheck.mu.Unlock()
+	if pluginCheck.ok {
+		return nil
+	}
+	if pluginCheck.err != nil && time.Since(pluginCheck.checked) < pluginRecheck {
+		return pluginCheck.err
+	}
+
+	err := checkPlugins(dir, ctx)
+	if ctx.Err() == nil {
+		pluginCheck.ok, pluginCheck.err, pluginCheck.checked = err == nil, err, time.Now()
+	}
+	return err
+}
+
+// checkPlugins checks the plugin directory layout, then asks yt-dlp which extractor plugins it loaded.
+func checkPlugins(dir string, ctx context.Context) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("yt-dlp plugin directory %q: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("yt-dlp plugin directory %q is not a directory", dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, pluginPackage)); err != nil {
+		return fmt.Errorf("yt-dlp plugin directory %q has no %q package", dir, pluginPackage)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// yt-dlp exits with an error as no URL is passed, the verbose header is printed first
+	out, _ := Command(ctx, "--verbose", "--ignore-config").CombinedOutput()
+	for _, line := range strings.Split(string(out), "\n") {
+		if plugins, ok := strings.CutPrefix(strings.TrimSpace(line), extractorPlugins); ok {
+			logging.I("Loaded yt-dlp extractor plugins from %q:%s", dir, plugins)
+			return nil
+		}
+	}
+	return fmt.Errorf("yt-dlp loaded no extractor plugins from %q, check the plugins import cleanly", dir)
+}
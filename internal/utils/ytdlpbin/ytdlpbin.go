@@ -0,0 +1,262 @@
+// Package ytdlpbin manages the yt-dlp binary, including release downloads into the Tubarr config directory.
+//
+// A yt-dlp binary in the config directory takes precedence over the one in $PATH.
+package ytdlpbin
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"tubarr/internal/domain/cmdvideo"
+	"tubarr/internal/domain/setup"
+	"tubarr/internal/utils/logging"
+)
+
+const (
+	releasesURL = "https://github.com/yt-dlp/yt-dlp/releases"
+	binDir      = "bin"
+	pinFile     = "yt-dlp.pin"
+	sumsFile    = "SHA2-256SUMS"
+	maxSumsSize = 1 << 20
+	Latest      = "latest"
+)
+
+var (
+	// versionRx matches yt-dlp release tags such as "2025.01.15" or "2025.01.15.1".
+	versionRx = regexp.MustCompile(`^\d{4}\.\d{2}\.\d{2}(\.\d+)?$`)
+
+	downloadClient = &http.Client{Timeout: 5 * time.Minute}
+)
+
+// ManagedPath returns where a downloaded yt-dlp release is kept.
+func ManagedPath() string {
+	name := cmdvideo.YTDLP
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(setup.CfgDir, binDir, name)
+}
+
+// Managed reports whether a downloaded yt-dlp release is installed in the config directory.
+func Managed() bool {
+	if setup.CfgDir == "" {
+		return false
+	}
+	info, err := os.Stat(ManagedPath())
+	return err == nil && !info.IsDir()
+}
+
+// Path returns the yt-dlp binary to run, the managed release if installed, otherwise yt-dlp from $PATH.
+func Path() string {
+	if Managed() {
+		return ManagedPath()
+	}
+	return cmdvideo.YTDLP
+}
+
+// Version runs the yt-dlp binary to get its version.
+func Version(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, Path(), "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get yt-dlp version from %q: %w", Path(), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Pinned returns the release the managed binary is pinned to, or an empty string if not pinned.
+func Pinned() string {
+	b, err := os.ReadFile(filepath.Join(setup.CfgDir, binDir, pinFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// ValidateVersion checks a release is "latest" or a yt-dlp release tag such as "2025.01.15".
+func ValidateVersion(version string) error {
+	if version == Latest || versionRx.MatchString(version) {
+		return nil
+	}
+	return fmt.Errorf("invalid yt-dlp version %q, expected %q or a release such as '2025.01.15'", version, Latest)
+}
+
+// Install downloads a yt-dlp release into the config directory, replacing any managed binary.
+//
+// The download is checked against the release's SHA2-256SUMS and not installed if it doesn't match.
+// If pin is set the release is kept by Update until unpinned.
+func Install(ctx context.Context, version string, pin bool) error {
+	if err := ValidateVersion(version); err != nil {
+		return err
+	}
+	if setup.CfgDir == "" {
+		return errors.New("config directory is not set")
+	}
+
+	dir := filepath.Join(setup.CfgDir, binDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %q: %w", dir, err)
+	}
+
+	base := releasesURL + "/latest/download/"
+	if version != Latest {
+		base = releasesURL + "/download/" + version + "/"
+	}
+	sum, err := fetchChecksum(ctx, base+sumsFile, assetName())
+	if err != nil {
+		return err
+	}
+
+	u := base + assetName()
+	logging.I("Downloading yt-dlp %s from %s", version, u)
+
+	if err := download(ctx, u, ManagedPath(), sum); err != nil {
+		return err
+	}
+
+	pinPath := filepath.Join(dir, pinFile)
+	if pin {
+		if err := os.WriteFile(pinPath, []byte(version+"\n"), 0o644); err != nil {
+			return fmt.Errorf("failed to pin yt-dlp version: %w", err)
+		}
+	} else if err := os.Remove(pinPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to clear yt-dlp pin: %w", err)
+	}
+	return nil
+}
+
+// Update updates yt-dlp to the latest release.
+//
+// The managed binary is replaced by a fresh download, or yt-dlp in $PATH updates itself with -U.
+// Pinned releases are left alone.
+func Update(ctx context.Context) error {
+	if v := Pinned(); v != "" {
+		return fmt.Errorf("yt-dlp is pinned to %s, unpin it or pin a newer release", v)
+	}
+	if Managed() {
+		return Install(ctx, Latest, false)
+	}
+
+	out, err := exec.CommandContext(ctx, cmdvideo.YTDLP, "-U").CombinedOutput()
+	fmt.Print(string(out))
+	if err != nil {
+		return fmt.Errorf("yt-dlp self-update failed (package manager installs must be updated with the package manager): %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the managed binary and its pin, returning to yt-dlp from $PATH.
+func Remove() error {
+	for _, p := range []string{ManagedPath(), filepath.Join(setup.CfgDir, binDir, pinFile)} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// assetName returns the standalone yt-dlp release asset for this platform.
+func assetName() string {
+	switch runtime.GOOS {
+	case "linux":
+		if runtime.GOARCH == "arm64" {
+			return "yt-dlp_linux_aarch64"
+		}
+		return "yt-dlp_linux"
+	case "darwin":
+		return "yt-dlp_macos"
+	case "windows":
+		return "yt-dlp.exe"
+	}
+	return cmdvideo.YTDLP // Python zipapp, needs python3
+}
+
+// fetchChecksum gets the SHA-256 hash of the asset from a release's checksum file.
+func fetchChecksum(ctx context.Context, u, asset string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := downloadClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download yt-dlp checksums: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download yt-dlp checksums from %s: %s", u, resp.Status)
+	}
+
+	// Lines are "<hex hash>  <asset name>"
+	sc := bufio.NewScanner(io.LimitReader(resp.Body, maxSumsSize))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != asset {
+			continue
+		}
+		if _, err := hex.DecodeString(fields[0]); err != nil || len(fields[0]) != sha256.Size*2 {
+			return "", fmt.Errorf("invalid checksum %q for %q in %s", fields[0], asset, u)
+		}
+		return strings.ToLower(fields[0]), nil
+	}
+	if err := sc.Err(); err != nil {
+		return "", fmt.Errorf("failed to read yt-dlp checksums from %s: %w", u, err)
+	}
+	return "", fmt.Errorf("no checksum for %q in %s", asset, u)
+}
+
+// download fetches the URL into dest through a temporary file, so a failed download leaves any existing binary in place.
+//
+// The file is only moved into place if its SHA-256 hash matches sum.
+func download(ctx context.Context, u, dest, sum string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := downloadClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download yt-dlp: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download yt-dlp from %s: %s", u, resp.Status)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".yt-dlp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write yt-dlp download: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != sum {
+		return fmt.Errorf("yt-dlp download from %s failed checksum verification (expected %s, got %s), not installing", u, sum, got)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}